@@ -3,6 +3,7 @@ import CheckCircleIcon from 'mdi-react/CheckCircleIcon'
 import ErrorIcon from 'mdi-react/ErrorIcon'
 import React from 'react'
 
+import { LoadingSpinner } from '@sourcegraph/react-loading-spinner'
 import { pluralize } from '@sourcegraph/shared/src/util/strings'
 
 import { Collapsible } from './Collapsible'
@@ -16,6 +17,7 @@ interface ExecutionLogEntryProps extends React.PropsWithChildren<{}> {
         exitCode: number
         out: string
         durationMilliseconds: number
+        running: boolean
     }
     now?: () => Date
 }
@@ -26,7 +28,9 @@ export const ExecutionLogEntry: React.FunctionComponent<ExecutionLogEntryProps>
             <LogOutput text={logEntry.command.join(' ')} className="mb-3" />
 
             <div>
-                {logEntry.exitCode === 0 ? (
+                {logEntry.running ? (
+                    <LoadingSpinner className="icon-inline" />
+                ) : logEntry.exitCode === 0 ? (
                     <CheckCircleIcon className="text-success" />
                 ) : (
                     <ErrorIcon className="text-danger" />
@@ -35,7 +39,14 @@ export const ExecutionLogEntry: React.FunctionComponent<ExecutionLogEntryProps>
                 <span className="ml-2">
                     <span className="text-muted">Started</span>{' '}
                     <Timestamp date={logEntry.startTime} now={now} noAbout={true} />
-                    <span className="text-muted">, ran for</span> {formatMilliseconds(logEntry.durationMilliseconds)}
+                    {logEntry.running ? (
+                        <span className="text-muted">, still running</span>
+                    ) : (
+                        <>
+                            <span className="text-muted">, ran for</span>{' '}
+                            {formatMilliseconds(logEntry.durationMilliseconds)}
+                        </>
+                    )}
                 </span>
             </div>
 
@@ -44,7 +55,12 @@ export const ExecutionLogEntry: React.FunctionComponent<ExecutionLogEntryProps>
 
         <div className="p-2">
             {logEntry.out ? (
-                <Collapsible title="Log output" titleAtStart={true} buttonClassName="p-2">
+                <Collapsible
+                    title="Log output"
+                    titleAtStart={true}
+                    buttonClassName="p-2"
+                    defaultExpanded={logEntry.running}
+                >
                     <LogOutput text={logEntry.out} />
                 </Collapsible>
             ) : (
@@ -65,8 +81,8 @@ const LogOutput: React.FunctionComponent<LogOutputProps> = React.memo(({ text, c
     <pre className={classNames('bg-code rounded p-3 mb-0', className)}>
         {
             // Use index as key because log lines may not be unique. This is OK
-            // here because this list will not be updated during this component's
-            // lifetime (note: it's also memoized).
+            // here because lines are only ever appended to the output of a
+            // running command (note: it's also memoized).
             /* eslint-disable react/no-array-index-key */
             text.split('\n').map((line, index) => (
                 <code key={index} className={classNames('d-block', line.startsWith('stderr:') ? 'text-danger' : '')}>
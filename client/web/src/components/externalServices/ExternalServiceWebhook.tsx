@@ -48,6 +48,10 @@ export const ExternalServiceWebhook: React.FunctionComponent<Props> = ({ externa
         case ExternalServiceKind.GITLAB:
             description = commonDescription('gitlab')
             break
+
+        case ExternalServiceKind.AZUREDEVOPS:
+            description = commonDescription('azuredevops')
+            break
     }
 
     return (
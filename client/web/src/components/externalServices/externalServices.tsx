@@ -11,8 +11,11 @@ import React from 'react'
 import { PhabricatorIcon } from '@sourcegraph/shared/src/components/icons'
 
 import awsCodeCommitSchemaJSON from '../../../../../schema/aws_codecommit.schema.json'
+import azureDevOpsSchemaJSON from '../../../../../schema/azuredevops.schema.json'
 import bitbucketCloudSchemaJSON from '../../../../../schema/bitbucket_cloud.schema.json'
 import bitbucketServerSchemaJSON from '../../../../../schema/bitbucket_server.schema.json'
+import gerritSchemaJSON from '../../../../../schema/gerrit.schema.json'
+import giteaSchemaJSON from '../../../../../schema/gitea.schema.json'
 import githubSchemaJSON from '../../../../../schema/github.schema.json'
 import gitlabSchemaJSON from '../../../../../schema/gitlab.schema.json'
 import gitoliteSchemaJSON from '../../../../../schema/gitolite.schema.json'
@@ -1013,6 +1016,255 @@ const GITOLITE: AddExternalServiceOptions = {
         },
     ],
 }
+const GITEA: AddExternalServiceOptions = {
+    kind: ExternalServiceKind.GITEA,
+    title: 'Gitea',
+    icon: GitIcon,
+    jsonSchema: giteaSchemaJSON,
+    defaultDisplayName: 'Gitea',
+    defaultConfig: `{
+  "url": "https://gitea.example.com",
+  "token": "<access token>",
+  "orgs": []
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, set <Field>url</Field> to the URL of your Gitea instance.
+                </li>
+                <li>
+                    Create a Gitea access token from the <b>Applications</b> tab of your Gitea user settings and set it
+                    to be the value of the <Field>token</Field> field.
+                </li>
+                <li>
+                    Set the <Field>orgs</Field> or <Field>repos</Field> fields to select the repositories to mirror. If
+                    neither is set, all repositories accessible to the token's user are mirrored.
+                </li>
+            </ol>
+            <p>
+                See{' '}
+                <a
+                    rel="noopener noreferrer"
+                    target="_blank"
+                    href="https://docs.sourcegraph.com/admin/external_service/gitea#configuration"
+                >
+                    the docs for more advanced options
+                </a>
+                , or try one of the buttons below.
+            </p>
+        </div>
+    ),
+    editorActions: [
+        {
+            id: 'setAccessToken',
+            label: 'Set access token',
+            run: (config: string) => {
+                const value = '<access token>'
+                const edits = setProperty(config, ['token'], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'addOrgRepositories',
+            label: 'Add repositories belonging to organization',
+            run: (config: string) => {
+                const value = '<organization>'
+                const edits = setProperty(config, ['orgs', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'addRepository',
+            label: 'Add a single repository',
+            run: (config: string) => {
+                const value = '<owner>/<repository>'
+                const edits = setProperty(config, ['repos', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'excludeRepo',
+            label: 'Exclude a repository',
+            run: (config: string) => {
+                const value = { name: '<owner>/<repository>' }
+                const edits = setProperty(config, ['exclude', -1], value, defaultFormattingOptions)
+                return { edits, selectText: '<owner>/<repository>' }
+            },
+        },
+    ],
+}
+const GERRIT: AddExternalServiceOptions = {
+    kind: ExternalServiceKind.GERRIT,
+    title: 'Gerrit',
+    icon: GitIcon,
+    jsonSchema: gerritSchemaJSON,
+    defaultDisplayName: 'Gerrit',
+    defaultConfig: `{
+  "url": "https://gerrit.example.com",
+  "username": "<username>",
+  "password": "<HTTP password>"
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, set <Field>url</Field> to the URL of your Gerrit instance.
+                </li>
+                <li>
+                    Generate an HTTP password from the <b>HTTP Credentials</b> page of your Gerrit user settings. Set{' '}
+                    <Field>username</Field> and <Field>password</Field> to your username and the generated password.
+                </li>
+                <li>
+                    Optionally, set the <Field>projects</Field> field to the list of projects to mirror. If it is not
+                    set, all projects visible to the user are mirrored.
+                </li>
+            </ol>
+            <p>
+                See{' '}
+                <a
+                    rel="noopener noreferrer"
+                    target="_blank"
+                    href="https://docs.sourcegraph.com/admin/external_service/gerrit#configuration"
+                >
+                    the docs for more advanced options
+                </a>
+                , or try one of the buttons below.
+            </p>
+        </div>
+    ),
+    editorActions: [
+        {
+            id: 'setUsername',
+            label: 'Set username',
+            run: (config: string) => {
+                const value = '<username>'
+                const edits = setProperty(config, ['username'], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'addProject',
+            label: 'Add a single project',
+            run: (config: string) => {
+                const value = '<project>'
+                const edits = setProperty(config, ['projects', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'excludeProject',
+            label: 'Exclude a project',
+            run: (config: string) => {
+                const value = { name: '<project>' }
+                const edits = setProperty(config, ['exclude', -1], value, defaultFormattingOptions)
+                return { edits, selectText: '<project>' }
+            },
+        },
+    ],
+}
+const AZURE_DEVOPS: AddExternalServiceOptions = {
+    kind: ExternalServiceKind.AZUREDEVOPS,
+    title: 'Azure DevOps',
+    icon: GitIcon,
+    jsonSchema: azureDevOpsSchemaJSON,
+    defaultDisplayName: 'Azure DevOps',
+    defaultConfig: `{
+  "url": "https://dev.azure.com",
+  "token": "<personal access token>",
+  "orgs": []
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, set <Field>url</Field> to <code>https://dev.azure.com</code> for Azure
+                    DevOps Services, or to the URL of your Azure DevOps Server.
+                </li>
+                <li>
+                    Create a personal access token with the <b>Code (Read)</b> scope and set it to be the value of the{' '}
+                    <Field>token</Field> field. The token also needs the <b>Identity (Read)</b> and{' '}
+                    <b>Security (Manage)</b> scopes if you enforce permissions.
+                </li>
+                <li>
+                    Set the <Field>orgs</Field> or <Field>projects</Field> fields to select the repositories to mirror.
+                </li>
+            </ol>
+            <p>
+                See{' '}
+                <a
+                    rel="noopener noreferrer"
+                    target="_blank"
+                    href="https://docs.sourcegraph.com/admin/external_service/azuredevops#configuration"
+                >
+                    the docs for more advanced options
+                </a>
+                , or try one of the buttons below.
+            </p>
+        </div>
+    ),
+    editorActions: [
+        {
+            id: 'setAccessToken',
+            label: 'Set access token',
+            run: (config: string) => {
+                const value = '<personal access token>'
+                const edits = setProperty(config, ['token'], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'addOrgRepositories',
+            label: 'Add repositories belonging to organization',
+            run: (config: string) => {
+                const value = '<organization>'
+                const edits = setProperty(config, ['orgs', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'addProjectRepositories',
+            label: 'Add repositories belonging to project',
+            run: (config: string) => {
+                const value = '<organization>/<project>'
+                const edits = setProperty(config, ['projects', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'excludeRepo',
+            label: 'Exclude a repository',
+            run: (config: string) => {
+                const value = { name: '<organization>/<project>/<repository>' }
+                const edits = setProperty(config, ['exclude', -1], value, defaultFormattingOptions)
+                return { edits, selectText: '<organization>/<project>/<repository>' }
+            },
+        },
+        {
+            id: 'enforcePermissions',
+            label: 'Enforce permissions',
+            run: (config: string) => {
+                const value = {
+                    identityProvider: {
+                        authProviderID: '<configID of the OpenID Connect or SAML auth provider>',
+                        authProviderType: 'openidconnect',
+                    },
+                }
+                const edits = setProperty(config, ['authorization'], value, defaultFormattingOptions)
+                return { edits, selectText: '<configID of the OpenID Connect or SAML auth provider>' }
+            },
+        },
+        {
+            id: 'addWebhook',
+            label: 'Add webhook',
+            run: (config: string) => {
+                const value = { secret: '<any secret string>' }
+                const edits = setProperty(config, ['webhooks', -1], value, defaultFormattingOptions)
+                return { edits, selectText: '<any secret string>' }
+            },
+        },
+    ],
+}
 const PHABRICATOR_SERVICE: AddExternalServiceOptions = {
     kind: ExternalServiceKind.PHABRICATOR,
     title: 'Phabricator connection',
@@ -1217,6 +1469,9 @@ export const codeHostExternalServices: Record<string, AddExternalServiceOptions>
     bitbucket: BITBUCKET_CLOUD,
     bitbucketserver: BITBUCKET_SERVER,
     aws_codecommit: AWS_CODE_COMMIT,
+    azuredevops: AZURE_DEVOPS,
+    gitea: GITEA,
+    gerrit: GERRIT,
     srcservegit: SRC_SERVE_GIT,
     gitolite: GITOLITE,
     git: GENERIC_GIT,
@@ -1239,6 +1494,9 @@ export const defaultExternalServices: Record<ExternalServiceKind, AddExternalSer
     [ExternalServiceKind.BITBUCKETSERVER]: BITBUCKET_SERVER,
     [ExternalServiceKind.GITLAB]: GITLAB_DOTCOM,
     [ExternalServiceKind.GITOLITE]: GITOLITE,
+    [ExternalServiceKind.GITEA]: GITEA,
+    [ExternalServiceKind.GERRIT]: GERRIT,
+    [ExternalServiceKind.AZUREDEVOPS]: AZURE_DEVOPS,
     [ExternalServiceKind.PHABRICATOR]: PHABRICATOR_SERVICE,
     [ExternalServiceKind.OTHER]: GENERIC_GIT,
     [ExternalServiceKind.AWSCODECOMMIT]: AWS_CODE_COMMIT,
@@ -2,6 +2,7 @@ import { parseISO } from 'date-fns'
 import { formatDistance } from 'date-fns/esm'
 import { isArray, isEqual } from 'lodash'
 import AlertCircleIcon from 'mdi-react/AlertCircleIcon'
+import CancelIcon from 'mdi-react/CancelIcon'
 import CheckCircleIcon from 'mdi-react/CheckCircleIcon'
 import CheckIcon from 'mdi-react/CheckIcon'
 import ErrorIcon from 'mdi-react/ErrorIcon'
@@ -134,6 +135,8 @@ const ExecutionTimeline: React.FunctionComponent<ExecutionTimelineProps> = ({ ex
 
             execution.state === BatchSpecExecutionState.COMPLETED
                 ? { icon: <CheckIcon />, text: 'Finished', date: execution.finishedAt, className: 'bg-success' }
+                : execution.state === BatchSpecExecutionState.CANCELED
+                ? { icon: <CancelIcon />, text: 'Canceled', date: execution.finishedAt, className: 'bg-secondary' }
                 : { icon: <ErrorIcon />, text: 'Failed', date: execution.finishedAt, className: 'bg-danger' },
         ],
         [execution, now]
@@ -176,14 +179,24 @@ const teardownStage = (execution: BatchSpecExecutionFields, now?: () => Date): T
               ...genericStage(execution.steps.teardown),
           }
 
-const genericStage = <E extends { startTime: string; exitCode: number }>(
+const genericStage = <E extends { startTime: string; exitCode: number; running: boolean }>(
     value: E | E[]
 ): Pick<TimelineStage, 'icon' | 'date' | 'className' | 'expanded'> => {
-    const success = isArray(value) ? value.every(logEntry => logEntry.exitCode === 0) : value.exitCode === 0
+    const logEntries = isArray(value) ? value : [value]
+    if (logEntries.some(logEntry => logEntry.running)) {
+        return {
+            icon: <ProgressClockIcon />,
+            date: logEntries[0].startTime,
+            className: 'bg-info',
+            expanded: true,
+        }
+    }
+
+    const success = logEntries.every(logEntry => logEntry.exitCode === 0)
 
     return {
         icon: success ? <CheckIcon /> : <ErrorIcon />,
-        date: isArray(value) ? value[0].startTime : value.startTime,
+        date: logEntries[0].startTime,
         className: success ? 'bg-success' : 'bg-danger',
         expanded: !success,
     }
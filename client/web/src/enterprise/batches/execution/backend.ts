@@ -53,6 +53,7 @@ const batchSpecExecutionFieldsFragment = gql`
         startTime
         exitCode
         durationMilliseconds
+        running
         out
     }
 `
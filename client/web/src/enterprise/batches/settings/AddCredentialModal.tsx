@@ -56,9 +56,19 @@ const helpTexts: Record<ExternalServiceKind, JSX.Element> = {
             with <code>write</code> permissions on the project and repository level.
         </>
     ),
+    [ExternalServiceKind.AZUREDEVOPS]: (
+        <>
+            <a href={HELP_TEXT_LINK_URL} rel="noreferrer noopener" target="_blank">
+                Create a new personal access token
+            </a>{' '}
+            with the <code>Code (Read &amp; write)</code> scope.
+        </>
+    ),
 
     // These are just for type completeness and serve as placeholders for a bright future.
     [ExternalServiceKind.BITBUCKETCLOUD]: <span>Unsupported</span>,
+    [ExternalServiceKind.GERRIT]: <span>Unsupported</span>,
+    [ExternalServiceKind.GITEA]: <span>Unsupported</span>,
     [ExternalServiceKind.GITOLITE]: <span>Unsupported</span>,
     [ExternalServiceKind.JVMPACKAGES]: <span>Unsupported</span>,
     [ExternalServiceKind.PERFORCE]: <span>Unsupported</span>,
@@ -185,6 +185,7 @@ const steps: LsifIndexStepsFields = {
             startTime: '2020-06-15T12:25:30+00:00',
             exitCode: 0,
             durationMilliseconds: 50123,
+            running: false,
         },
         {
             key: '',
@@ -205,6 +206,7 @@ const steps: LsifIndexStepsFields = {
             startTime: '2020-06-15T12:25:30+00:00',
             exitCode: 0,
             durationMilliseconds: 50123,
+            running: false,
         },
         {
             key: '',
@@ -225,6 +227,7 @@ const steps: LsifIndexStepsFields = {
             startTime: '2020-06-15T12:25:30+00:00',
             exitCode: 0,
             durationMilliseconds: 50123,
+            running: false,
         },
     ],
     preIndex: [{ root: '/web', image: 'node:alpine', commands: ['yarn'], logEntry: null }],
@@ -265,6 +268,7 @@ const steps: LsifIndexStepsFields = {
             startTime: '2020-06-15T12:25:30+00:00',
             exitCode: 0,
             durationMilliseconds: 50123,
+            running: false,
         },
     },
     upload: null,
@@ -131,7 +131,7 @@ export const CodeIntelIndexPage: FunctionComponent<CodeIntelIndexPageProps> = ({
     )
 }
 
-const terminalStates = new Set([LSIFIndexState.COMPLETED, LSIFIndexState.ERRORED])
+const terminalStates = new Set([LSIFIndexState.COMPLETED, LSIFIndexState.ERRORED, LSIFIndexState.CANCELED])
 
 function shouldReload(index: LsifIndexFields | ErrorLike | null | undefined): boolean {
     return !isErrorLike(index) && !(index && terminalStates.has(index.state))
@@ -118,14 +118,24 @@ const indexTeardownStage = (index: LsifIndexFields, now?: () => Date): TimelineS
               ...genericStage(index.steps.teardown),
           }
 
-const genericStage = <E extends { startTime: string; exitCode: number }>(
+const genericStage = <E extends { startTime: string; exitCode: number; running: boolean }>(
     value: E | E[]
 ): Pick<TimelineStage, 'icon' | 'date' | 'className' | 'expanded'> => {
-    const success = isArray(value) ? value.every(logEntry => logEntry.exitCode === 0) : value.exitCode === 0
+    const logEntries = isArray(value) ? value : [value]
+    if (logEntries.some(logEntry => logEntry.running)) {
+        return {
+            icon: <ProgressClockIcon />,
+            date: logEntries[0].startTime,
+            className: 'bg-info',
+            expanded: true,
+        }
+    }
+
+    const success = logEntries.every(logEntry => logEntry.exitCode === 0)
 
     return {
         icon: success ? <CheckIcon /> : <ErrorIcon />,
-        date: isArray(value) ? value[0].startTime : value.startTime,
+        date: logEntries[0].startTime,
         className: success ? 'bg-success' : 'bg-danger',
         expanded: !success,
     }
@@ -125,4 +125,5 @@ const executionLog = {
     exitCode: 0,
     out: 'foo\nbar\baz\n',
     durationMilliseconds: 123456,
+    running: false,
 }
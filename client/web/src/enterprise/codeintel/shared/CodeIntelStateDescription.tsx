@@ -38,6 +38,8 @@ export const CodeIntelStateDescription: FunctionComponent<CodeIntelStateDescript
         <span className={className}>
             {upperFirst(typeName)} failed to complete: <ErrorMessage error={failure} />
         </span>
+    ) : state === LSIFIndexState.CANCELED ? (
+        <span className={className}>{upperFirst(typeName)} was canceled.</span>
     ) : (
         <></>
     )
@@ -1,4 +1,5 @@
 import classNames from 'classnames'
+import CancelIcon from 'mdi-react/CancelIcon'
 import CheckCircleIcon from 'mdi-react/CheckCircleIcon'
 import ErrorIcon from 'mdi-react/ErrorIcon'
 import FileUploadIcon from 'mdi-react/FileUploadIcon'
@@ -27,6 +28,8 @@ export const CodeIntelStateIcon: FunctionComponent<CodeIntelStateIconProps> = ({
         <CheckCircleIcon className={classNames('text-success', className)} />
     ) : state === LSIFUploadState.ERRORED || state === LSIFIndexState.ERRORED ? (
         <ErrorIcon className={classNames('text-danger', className)} />
+    ) : state === LSIFIndexState.CANCELED ? (
+        <CancelIcon className={classNames('text-muted', className)} />
     ) : (
         <></>
     )
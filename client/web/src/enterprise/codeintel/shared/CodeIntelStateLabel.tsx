@@ -26,6 +26,8 @@ export const CodeIntelStateLabel: FunctionComponent<CodeIntelStateLabelProps> =
         <span className={classNames(labelClassNames, className)}>Completed</span>
     ) : state === LSIFUploadState.ERRORED || state === LSIFIndexState.ERRORED ? (
         <span className={classNames(labelClassNames, className)}>Failed</span>
+    ) : state === LSIFIndexState.CANCELED ? (
+        <span className={classNames(labelClassNames, className)}>Canceled</span>
     ) : (
         <></>
     )
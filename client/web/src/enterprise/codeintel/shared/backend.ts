@@ -109,5 +109,6 @@ export const lsifIndexFieldsFragment = gql`
         exitCode
         out
         durationMilliseconds
+        running
     }
 `
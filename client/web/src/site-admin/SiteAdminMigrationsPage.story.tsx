@@ -104,6 +104,9 @@ const migrations = [
         lastUpdated: '2020-12-20T14:00+00:00',
         nonDestructive: true,
         applyReverse: false,
+        paused: false,
+        batchSize: null,
+        batchIntervalMilliseconds: null,
         errors: [],
     },
     {
@@ -118,6 +121,9 @@ const migrations = [
         lastUpdated: '2021-03-05T11:59:45+00:00',
         nonDestructive: false,
         applyReverse: false,
+        paused: true,
+        batchSize: 100,
+        batchIntervalMilliseconds: 5000,
         errors: [
             { message: 'uh-oh 4', created: '2021-03-05T11:59:45+00:00' },
             { message: 'uh-oh 3', created: '2021-01-25T16:00+00:00' },
@@ -137,6 +143,9 @@ const migrations = [
         lastUpdated: '2021-01-26T12:00+00:00',
         nonDestructive: false,
         applyReverse: true,
+        paused: false,
+        batchSize: null,
+        batchIntervalMilliseconds: null,
         errors: [],
     },
     {
@@ -151,6 +160,9 @@ const migrations = [
         lastUpdated: '2021-02-25T12:00+00:00',
         nonDestructive: false,
         applyReverse: true,
+        paused: false,
+        batchSize: null,
+        batchIntervalMilliseconds: null,
         errors: [],
     },
     {
@@ -165,6 +177,9 @@ const migrations = [
         lastUpdated: '',
         nonDestructive: false,
         applyReverse: false,
+        paused: false,
+        batchSize: null,
+        batchIntervalMilliseconds: null,
         errors: [],
     },
 ]
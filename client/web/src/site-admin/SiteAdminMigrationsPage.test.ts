@@ -68,5 +68,8 @@ const zeroFields: OutOfBandMigrationFields = {
     lastUpdated: null,
     nonDestructive: false,
     applyReverse: false,
+    paused: false,
+    batchSize: null,
+    batchIntervalMilliseconds: null,
     errors: [],
 }
@@ -2,7 +2,7 @@ import ArrowLeftBoldIcon from 'mdi-react/ArrowLeftBoldIcon'
 import ArrowRightBoldIcon from 'mdi-react/ArrowRightBoldIcon'
 import ErrorIcon from 'mdi-react/ErrorIcon'
 import WarningIcon from 'mdi-react/WarningIcon'
-import React, { useCallback, useMemo } from 'react'
+import React, { useCallback, useMemo, useState } from 'react'
 import { RouteComponentProps } from 'react-router'
 import { Observable, of, timer } from 'rxjs'
 import { catchError, concatMap, delay, map, repeatWhen, takeWhile } from 'rxjs/operators'
@@ -23,6 +23,9 @@ import { OutOfBandMigrationFields } from '../graphql-operations'
 import {
     fetchAllOutOfBandMigrations as defaultFetchAllMigrations,
     fetchSiteUpdateCheck as defaultFetchSiteUpdateCheck,
+    pauseOutOfBandMigration,
+    resumeOutOfBandMigration,
+    rollbackOutOfBandMigration,
 } from './backend'
 
 export interface SiteAdminMigrationsPageProps extends RouteComponentProps<{}>, TelemetryProps {
@@ -297,12 +300,27 @@ const MigrationNode: React.FunctionComponent<MigrationNodeProps> = ({ node, now
                     )}
                     .
                 </p>
+
+                {(node.batchSize !== null || node.batchIntervalMilliseconds !== null) && (
+                    <p className="m-0">
+                        <span className="text-muted">Rate limited to</span>{' '}
+                        {node.batchSize !== null ? `${node.batchSize} records` : 'default-sized batches'}{' '}
+                        <span className="text-muted">every</span>{' '}
+                        {node.batchIntervalMilliseconds !== null
+                            ? `${node.batchIntervalMilliseconds}ms`
+                            : 'default interval'}
+                        .
+                    </p>
+                )}
+
+                <MigrationControls node={node} />
             </div>
         </div>
 
         <span className="d-none d-md-inline site-admin-migration-node__progress">
             <div className="m-0 text-nowrap d-flex flex-column align-items-center justify-content-center">
                 <div>
+                    {node.paused && <span className="badge badge-warning mr-1">Paused</span>}
                     {node.applyReverse ? (
                         <ArrowLeftBoldIcon className="icon-inline mr-1 text-danger" />
                     ) : (
@@ -368,6 +386,68 @@ const MigrationNode: React.FunctionComponent<MigrationNodeProps> = ({ node, now
     </React.Fragment>
 )
 
+interface MigrationControlsProps {
+    node: OutOfBandMigrationFields
+}
+
+const MigrationControls: React.FunctionComponent<MigrationControlsProps> = ({ node }) => {
+    const [isLoading, setIsLoading] = useState(false)
+    const [error, setError] = useState<ErrorLike>()
+
+    const run = useCallback(
+        async (action: (id: string) => Observable<void>): Promise<void> => {
+            setError(undefined)
+            setIsLoading(true)
+            try {
+                await action(node.id).toPromise()
+            } catch (error) {
+                setError(asError(error))
+            } finally {
+                setIsLoading(false)
+            }
+        },
+        [node.id]
+    )
+
+    const onTogglePaused = useCallback(
+        (): Promise<void> => run(node.paused ? resumeOutOfBandMigration : pauseOutOfBandMigration),
+        [node.paused, run]
+    )
+
+    const onRollback = useCallback(async (): Promise<void> => {
+        if (
+            !window.confirm(
+                'Roll back this migration? It will run in reverse until no migrated data remains. This may take a long time.'
+            )
+        ) {
+            return
+        }
+        await run(rollbackOutOfBandMigration)
+    }, [run])
+
+    return (
+        <div className="mt-2">
+            <button
+                type="button"
+                className="btn btn-sm btn-secondary mr-2"
+                onClick={onTogglePaused}
+                disabled={isLoading || (!node.paused && isComplete(node))}
+            >
+                {node.paused ? 'Resume' : 'Pause'}
+            </button>
+            <button
+                type="button"
+                className="btn btn-sm btn-outline-danger"
+                onClick={onRollback}
+                disabled={isLoading || node.progress === 0 || (node.applyReverse && !node.paused)}
+            >
+                Roll back
+            </button>
+            {error && <ErrorAlert className="mt-2" error={error} />}
+        </div>
+    )
+}
+
 type PartialVersion = SemVer | null
 
 /** Parse the given version safely. */
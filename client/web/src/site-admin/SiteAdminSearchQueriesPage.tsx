@@ -0,0 +1,144 @@
+import React, { useEffect } from 'react'
+import { RouteComponentProps } from 'react-router'
+import { Link } from 'react-router-dom'
+import { Observable } from 'rxjs'
+import { map } from 'rxjs/operators'
+
+import { dataOrThrowErrors, gql } from '@sourcegraph/shared/src/graphql/graphql'
+import { TelemetryProps } from '@sourcegraph/shared/src/telemetry/telemetryService'
+import { buildSearchURLQuery } from '@sourcegraph/shared/src/util/url'
+import { PageHeader } from '@sourcegraph/wildcard'
+
+import { requestGraphQL } from '../backend/graphql'
+import {
+    FilteredConnection,
+    FilteredConnectionFilter,
+    FilteredConnectionQueryArguments,
+} from '../components/FilteredConnection'
+import { PageTitle } from '../components/PageTitle'
+import { Timestamp } from '../components/time/Timestamp'
+import {
+    SearchQueryStatisticFields,
+    SearchQueryStatisticsOrderBy,
+    SiteAdminSearchQueryStatisticsResult,
+    SiteAdminSearchQueryStatisticsVariables,
+} from '../graphql-operations'
+
+interface Props extends Pick<RouteComponentProps<{}>, 'history' | 'location'>, TelemetryProps {}
+
+const filters: FilteredConnectionFilter[] = [
+    {
+        id: 'order',
+        label: 'Order',
+        type: 'select',
+        values: [
+            {
+                label: 'Slowest',
+                value: 'slowest',
+                tooltip: 'Show queries with the highest average duration first',
+                args: { orderBy: SearchQueryStatisticsOrderBy.SLOWEST },
+            },
+            {
+                label: 'Most failing',
+                value: 'most-failing',
+                tooltip: 'Show queries that timed out or failed most often first',
+                args: { orderBy: SearchQueryStatisticsOrderBy.MOST_FAILING },
+            },
+        ],
+    },
+]
+
+/**
+ * Displays the slowest and most failing searches run on the site.
+ */
+export const SiteAdminSearchQueriesPage: React.FunctionComponent<Props> = ({ history, location, telemetryService }) => {
+    useEffect(() => {
+        telemetryService.logViewEvent('SiteAdminSearchQueries')
+    }, [telemetryService])
+
+    return (
+        <div className="site-admin-search-queries-page">
+            <PageTitle title="Search queries - Admin" />
+            <PageHeader
+                headingElement="h2"
+                path={[{ text: 'Search queries' }]}
+                description="Searches run by users in the last 93 days, aggregated per query. Searches of users who turned off the search.queryHistory setting are not included."
+                className="mb-3"
+            />
+            <FilteredConnection<SearchQueryStatisticFields>
+                className="list-group list-group-flush mt-3"
+                noun="query"
+                pluralNoun="queries"
+                queryConnection={querySearchQueryStatistics}
+                nodeComponent={SearchQueryStatisticNode}
+                filters={filters}
+                hideSearch={true}
+                noSummaryIfAllNodesVisible={true}
+                history={history}
+                location={location}
+            />
+        </div>
+    )
+}
+
+const SearchQueryStatisticNode: React.FunctionComponent<{ node: SearchQueryStatisticFields }> = ({ node }) => (
+    <li className="list-group-item d-block">
+        <Link to={`/search?${buildSearchURLQuery(node.query, node.patternType, false)}`}>
+            <code>{node.query}</code>
+        </Link>
+        <br />
+        <small className="text-muted">
+            Run {node.count} {node.count === 1 ? 'time' : 'times'}, last <Timestamp date={node.lastRunAt} />{' '}
+            &mdash; {node.averageDurationMilliseconds}ms on average, {node.maxDurationMilliseconds}ms at most &mdash;{' '}
+            {node.timeoutCount} timed out, {node.errorCount} failed
+        </small>
+        {node.latestAlert && (
+            <>
+                <br />
+                <small>
+                    Latest alert: <strong>{node.latestAlert.title}</strong> ({node.latestAlert.type})
+                </small>
+            </>
+        )}
+    </li>
+)
+
+function querySearchQueryStatistics(
+    args: FilteredConnectionQueryArguments & { orderBy?: SearchQueryStatisticsOrderBy }
+): Observable<SiteAdminSearchQueryStatisticsResult['site']['searchQueryStatistics']> {
+    return requestGraphQL<SiteAdminSearchQueryStatisticsResult, SiteAdminSearchQueryStatisticsVariables>(
+        gql`
+            query SiteAdminSearchQueryStatistics($first: Int, $orderBy: SearchQueryStatisticsOrderBy) {
+                site {
+                    searchQueryStatistics(first: $first, orderBy: $orderBy) {
+                        nodes {
+                            ...SearchQueryStatisticFields
+                        }
+                        totalCount
+                        pageInfo {
+                            hasNextPage
+                        }
+                    }
+                }
+            }
+            fragment SearchQueryStatisticFields on SearchQueryStatistic {
+                query
+                patternType
+                count
+                averageDurationMilliseconds
+                maxDurationMilliseconds
+                timeoutCount
+                errorCount
+                latestAlert {
+                    type
+                    title
+                }
+                lastRunAt
+            }
+        `,
+        { first: args.first ?? null, orderBy: args.orderBy ?? null }
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(data => data.site.searchQueryStatistics)
+    )
+}
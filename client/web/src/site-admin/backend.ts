@@ -52,6 +52,12 @@ import {
     OutOfBandMigrationFields,
     OutOfBandMigrationsResult,
     OutOfBandMigrationsVariables,
+    PauseOutOfBandMigrationResult,
+    PauseOutOfBandMigrationVariables,
+    ResumeOutOfBandMigrationResult,
+    ResumeOutOfBandMigrationVariables,
+    RollbackOutOfBandMigrationResult,
+    RollbackOutOfBandMigrationVariables,
 } from '../graphql-operations'
 
 /**
@@ -858,6 +864,9 @@ export function fetchAllOutOfBandMigrations(): Observable<OutOfBandMigrationFiel
                 lastUpdated
                 nonDestructive
                 applyReverse
+                paused
+                batchSize
+                batchIntervalMilliseconds
                 errors {
                     message
                     created
@@ -869,3 +878,51 @@ export function fetchAllOutOfBandMigrations(): Observable<OutOfBandMigrationFiel
         map(data => data.outOfBandMigrations)
     )
 }
+
+/**
+ * Pauses an out-of-band migration so that it makes no progress until resumed.
+ */
+export function pauseOutOfBandMigration(id: Scalars['ID']): Observable<void> {
+    return requestGraphQL<PauseOutOfBandMigrationResult, PauseOutOfBandMigrationVariables>(
+        gql`
+            mutation PauseOutOfBandMigration($id: ID!) {
+                pauseOutOfBandMigration(id: $id) {
+                    alwaysNil
+                }
+            }
+        `,
+        { id }
+    ).pipe(map(dataOrThrowErrors), mapTo(undefined))
+}
+
+/**
+ * Resumes a paused out-of-band migration.
+ */
+export function resumeOutOfBandMigration(id: Scalars['ID']): Observable<void> {
+    return requestGraphQL<ResumeOutOfBandMigrationResult, ResumeOutOfBandMigrationVariables>(
+        gql`
+            mutation ResumeOutOfBandMigration($id: ID!) {
+                resumeOutOfBandMigration(id: $id) {
+                    alwaysNil
+                }
+            }
+        `,
+        { id }
+    ).pipe(map(dataOrThrowErrors), mapTo(undefined))
+}
+
+/**
+ * Rolls back an out-of-band migration by running it in reverse until no migrated data remains.
+ */
+export function rollbackOutOfBandMigration(id: Scalars['ID']): Observable<void> {
+    return requestGraphQL<RollbackOutOfBandMigrationResult, RollbackOutOfBandMigrationVariables>(
+        gql`
+            mutation RollbackOutOfBandMigration($id: ID!) {
+                rollbackOutOfBandMigration(id: $id) {
+                    alwaysNil
+                }
+            }
+        `,
+        { id }
+    ).pipe(map(dataOrThrowErrors), mapTo(undefined))
+}
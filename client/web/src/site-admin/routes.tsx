@@ -109,6 +109,11 @@ export const siteAdminAreaRoutes: readonly SiteAdminAreaRoute[] = [
         exact: true,
         render: lazyComponent(() => import('./SiteAdminReportBugPage'), 'SiteAdminReportBugPage'),
     },
+    {
+        path: '/search-queries',
+        exact: true,
+        render: lazyComponent(() => import('./SiteAdminSearchQueriesPage'), 'SiteAdminSearchQueriesPage'),
+    },
     {
         path: '/surveys',
         exact: true,
@@ -22,6 +22,10 @@ export const overviewGroup: SiteAdminSideBarGroup = {
             label: 'Usage stats',
             to: '/site-admin/usage-statistics',
         },
+        {
+            label: 'Search queries',
+            to: '/site-admin/search-queries',
+        },
         {
             label: 'Feedback survey',
             to: '/site-admin/surveys',
@@ -87,6 +87,14 @@ export const userSettingsAreaRoutes: readonly UserSettingsAreaRoute[] = [
         render: lazyComponent(() => import('./accessTokens/UserSettingsTokensArea'), 'UserSettingsTokensArea'),
         condition: () => window.context.accessTokensAllow !== 'none',
     },
+    {
+        path: '/search-history',
+        exact: true,
+        render: lazyComponent(
+            () => import('./searchHistory/UserSettingsSearchHistoryPage'),
+            'UserSettingsSearchHistoryPage'
+        ),
+    },
     // future GA Cloud routes
     {
         path: '/security',
@@ -0,0 +1,228 @@
+import React, { useCallback, useEffect, useMemo, useState } from 'react'
+import { RouteComponentProps } from 'react-router'
+import { Link } from 'react-router-dom'
+import { Observable, Subject } from 'rxjs'
+import { map, mapTo } from 'rxjs/operators'
+
+import { dataOrThrowErrors, gql } from '@sourcegraph/shared/src/graphql/graphql'
+import { TelemetryProps } from '@sourcegraph/shared/src/telemetry/telemetryService'
+import { asError, isErrorLike } from '@sourcegraph/shared/src/util/errors'
+import { buildSearchURLQuery } from '@sourcegraph/shared/src/util/url'
+import { Container, PageHeader } from '@sourcegraph/wildcard'
+
+import { requestGraphQL } from '../../../backend/graphql'
+import { ErrorAlert } from '../../../components/alerts'
+import { FilteredConnection, FilteredConnectionQueryArguments } from '../../../components/FilteredConnection'
+import { PageTitle } from '../../../components/PageTitle'
+import { Timestamp } from '../../../components/time/Timestamp'
+import {
+    DeleteSearchQueryHistoryResult,
+    DeleteSearchQueryHistoryVariables,
+    Scalars,
+    SearchQueryHistoryConnectionFields,
+    SearchQueryHistoryEntryFields,
+    SearchQueryHistoryResult,
+    SearchQueryHistoryVariables,
+} from '../../../graphql-operations'
+import { UserSettingsAreaRouteContext } from '../UserSettingsArea'
+
+interface Props
+    extends Pick<UserSettingsAreaRouteContext, 'user'>,
+        Pick<RouteComponentProps<{}>, 'history' | 'location'>,
+        TelemetryProps {}
+
+/**
+ * Displays the searches run by a user, so they can run them again.
+ */
+export const UserSettingsSearchHistoryPage: React.FunctionComponent<Props> = ({
+    telemetryService,
+    history,
+    location,
+    user,
+}) => {
+    useEffect(() => {
+        telemetryService.logViewEvent('UserSettingsSearchHistory')
+    }, [telemetryService])
+
+    const historyUpdates = useMemo(() => new Subject<void>(), [])
+    const onDidDelete = useCallback(() => historyUpdates.next(), [historyUpdates])
+
+    const [isClearing, setIsClearing] = useState<boolean | Error>(false)
+    const onClearHistory = useCallback(async () => {
+        if (!window.confirm('Delete your whole search history?')) {
+            return
+        }
+        setIsClearing(true)
+        try {
+            await deleteSearchQueryHistory(user.id, null)
+            setIsClearing(false)
+            onDidDelete()
+        } catch (error) {
+            setIsClearing(asError(error))
+        }
+    }, [user.id, onDidDelete])
+
+    const queryUserSearchQueryHistory = useCallback(
+        (args: FilteredConnectionQueryArguments) =>
+            querySearchQueryHistory({ user: user.id, first: args.first ?? null, query: args.query ?? null }),
+        [user.id]
+    )
+
+    return (
+        <div className="user-settings-search-history-page">
+            <PageTitle title="Search history" />
+            <PageHeader
+                headingElement="h2"
+                path={[{ text: 'Search history' }]}
+                description={
+                    <>
+                        Searches you ran in the last 93 days. Turn off the <code>search.queryHistory</code> setting
+                        to stop recording your searches.
+                    </>
+                }
+                actions={
+                    <button
+                        type="button"
+                        className="btn btn-danger"
+                        onClick={onClearHistory}
+                        disabled={isClearing === true}
+                    >
+                        Clear history
+                    </button>
+                }
+                className="mb-3"
+            />
+            {isErrorLike(isClearing) && <ErrorAlert className="mb-3" error={isClearing} />}
+            <Container>
+                <FilteredConnection<SearchQueryHistoryEntryFields, Omit<SearchQueryHistoryNodeProps, 'node'>>
+                    listClassName="list-group list-group-flush"
+                    noun="search"
+                    pluralNoun="searches"
+                    queryConnection={queryUserSearchQueryHistory}
+                    nodeComponent={SearchQueryHistoryNode}
+                    nodeComponentProps={{ userID: user.id, afterDelete: onDidDelete }}
+                    updates={historyUpdates}
+                    noSummaryIfAllNodesVisible={true}
+                    history={history}
+                    location={location}
+                    emptyElement={<p className="text-muted text-center w-100 mb-0">You haven't run any searches.</p>}
+                />
+            </Container>
+        </div>
+    )
+}
+
+interface SearchQueryHistoryNodeProps {
+    node: SearchQueryHistoryEntryFields
+    userID: Scalars['ID']
+    afterDelete: () => void
+}
+
+const SearchQueryHistoryNode: React.FunctionComponent<SearchQueryHistoryNodeProps> = ({
+    node,
+    userID,
+    afterDelete,
+}) => {
+    const [isDeleting, setIsDeleting] = useState<boolean | Error>(false)
+    const onDelete = useCallback(async () => {
+        setIsDeleting(true)
+        try {
+            await deleteSearchQueryHistory(userID, [node.id])
+            setIsDeleting(false)
+            afterDelete()
+        } catch (error) {
+            setIsDeleting(asError(error))
+        }
+    }, [userID, node.id, afterDelete])
+
+    return (
+        <li className="list-group-item d-block">
+            <div className="d-flex w-100 justify-content-between align-items-center">
+                <div className="mr-2 text-truncate">
+                    <Link to={`/search?${buildSearchURLQuery(node.query, node.patternType, false)}`}>
+                        <code>{node.query}</code>
+                    </Link>
+                    <br />
+                    <small className="text-muted">
+                        <Timestamp date={node.createdAt} /> &mdash; {node.resultCount}{' '}
+                        {node.resultCount === 1 ? 'result' : 'results'} in {node.durationMilliseconds}ms
+                        {node.status !== 'success' && <> &mdash; {node.alertTitle ?? node.status}</>}
+                    </small>
+                </div>
+                <button
+                    type="button"
+                    className="btn btn-sm btn-secondary"
+                    onClick={onDelete}
+                    disabled={isDeleting === true}
+                >
+                    Delete
+                </button>
+            </div>
+            {isErrorLike(isDeleting) && <ErrorAlert className="mt-2" error={isDeleting} />}
+        </li>
+    )
+}
+
+const querySearchQueryHistory = (
+    variables: SearchQueryHistoryVariables
+): Observable<SearchQueryHistoryConnectionFields> =>
+    requestGraphQL<SearchQueryHistoryResult, SearchQueryHistoryVariables>(
+        gql`
+            query SearchQueryHistory($user: ID!, $first: Int, $query: String) {
+                node(id: $user) {
+                    __typename
+                    ... on User {
+                        searchQueryHistory(first: $first, query: $query) {
+                            ...SearchQueryHistoryConnectionFields
+                        }
+                    }
+                }
+            }
+            fragment SearchQueryHistoryConnectionFields on SearchQueryHistoryConnection {
+                nodes {
+                    ...SearchQueryHistoryEntryFields
+                }
+                totalCount
+                pageInfo {
+                    hasNextPage
+                }
+            }
+            fragment SearchQueryHistoryEntryFields on SearchQueryHistoryEntry {
+                id
+                query
+                patternType
+                status
+                alertTitle
+                resultCount
+                durationMilliseconds
+                createdAt
+            }
+        `,
+        variables
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(data => {
+            if (!data.node) {
+                throw new Error('User not found')
+            }
+            if (data.node.__typename !== 'User') {
+                throw new Error(`Node is a ${data.node.__typename}, not a User`)
+            }
+            return data.node.searchQueryHistory
+        })
+    )
+
+function deleteSearchQueryHistory(user: Scalars['ID'], entries: Scalars['ID'][] | null): Promise<void> {
+    return requestGraphQL<DeleteSearchQueryHistoryResult, DeleteSearchQueryHistoryVariables>(
+        gql`
+            mutation DeleteSearchQueryHistory($user: ID!, $entries: [ID!]) {
+                deleteSearchQueryHistory(user: $user, entries: $entries) {
+                    alwaysNil
+                }
+            }
+        `,
+        { user, entries }
+    )
+        .pipe(map(dataOrThrowErrors), mapTo(undefined))
+        .toPromise()
+}
@@ -29,6 +29,11 @@ export const userSettingsSideBarItems: UserSettingsSidebarItems = [
         to: '/tokens',
         condition: () => window.context.accessTokensAllow !== 'none',
     },
+    {
+        label: 'Search history',
+        to: '/search-history',
+        exact: true,
+    },
     //  future GA Cloud nav items
     {
         label: 'Account security',
@@ -123,6 +123,7 @@ func AllowAnonymousRequest(req *http.Request) bool {
 		"/.api/github-webhooks",
 		"/.api/gitlab-webhooks",
 		"/.api/bitbucket-server-webhooks",
+		"/.api/azuredevops-webhooks",
 	} {
 		if strings.HasPrefix(req.URL.Path, prefix) {
 			return true
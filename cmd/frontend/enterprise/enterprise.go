@@ -15,6 +15,7 @@ type Services struct {
 	GitHubWebhook             webhooks.Registerer
 	GitLabWebhook             http.Handler
 	BitbucketServerWebhook    http.Handler
+	AzureDevOpsWebhook        http.Handler
 	NewCodeIntelUploadHandler NewCodeIntelUploadHandler
 	NewExecutorProxyHandler   NewExecutorProxyHandler
 	AuthzResolver             graphqlbackend.AuthzResolver
@@ -41,6 +42,7 @@ func DefaultServices() Services {
 		GitHubWebhook:             registerFunc(func(webhook *webhooks.GitHubWebhook) {}),
 		GitLabWebhook:             makeNotFoundHandler("gitlab webhook"),
 		BitbucketServerWebhook:    makeNotFoundHandler("bitbucket server webhook"),
+		AzureDevOpsWebhook:        makeNotFoundHandler("azure devops webhook"),
 		NewCodeIntelUploadHandler: func(_ bool) http.Handler { return makeNotFoundHandler("code intel upload") },
 		NewExecutorProxyHandler:   func() http.Handler { return makeNotFoundHandler("executor proxy") },
 	}
@@ -0,0 +1,128 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+)
+
+// accessTokenScopesByField maps the top-level Query and Mutation fields that may be requested
+// with an access token that only has fine-grained scopes to the scope they require. Such access
+// tokens may not request any other top-level field.
+//
+// 🚨 SECURITY: Resolvers of these fields are responsible for enforcing the repository patterns
+// of the scopes.
+var accessTokenScopesByField = map[string]string{
+	// Introspection.
+	"__schema":   "",
+	"__type":     "",
+	"__typename": "",
+
+	// Search.
+	"search": authz.ScopeSearchRead,
+
+	// Code.
+	"repository":         authz.ScopeCodeRead,
+	"repositoryRedirect": authz.ScopeCodeRead,
+	"repositories":       authz.ScopeCodeRead,
+
+	// Batch changes.
+	"batchChange":              authz.ScopeBatchesWrite,
+	"batchChanges":             authz.ScopeBatchesWrite,
+	"createChangesetSpec":      authz.ScopeBatchesWrite,
+	"createBatchSpec":          authz.ScopeBatchesWrite,
+	"createBatchChange":        authz.ScopeBatchesWrite,
+	"applyBatchChange":         authz.ScopeBatchesWrite,
+	"closeBatchChange":         authz.ScopeBatchesWrite,
+	"moveBatchChange":          authz.ScopeBatchesWrite,
+	"deleteBatchChange":        authz.ScopeBatchesWrite,
+	"syncChangeset":            authz.ScopeBatchesWrite,
+	"reenqueueChangeset":       authz.ScopeBatchesWrite,
+	"detachChangesets":         authz.ScopeBatchesWrite,
+	"createChangesetComments":  authz.ScopeBatchesWrite,
+	"reenqueueChangesets":      authz.ScopeBatchesWrite,
+	"mergeChangesets":          authz.ScopeBatchesWrite,
+	"closeChangesets":          authz.ScopeBatchesWrite,
+	"publishChangesets":        authz.ScopeBatchesWrite,
+	"createBatchSpecExecution": authz.ScopeBatchesWrite,
+	"cancelBatchSpecExecution": authz.ScopeBatchesWrite,
+
+	// Also requires the search:read scope, which is checked when the search is run.
+	"createBatchSpecFromStructuralReplace": authz.ScopeBatchesWrite,
+}
+
+// CheckAccessTokenScopes returns an error if the actor in ctx was authenticated with an access
+// token that only has fine-grained scopes, and the scopes don't permit all top-level fields of
+// all operations in the GraphQL query.
+func CheckAccessTokenScopes(ctx context.Context, query string) error {
+	if actor.FromContext(ctx).Scopes == nil {
+		return nil
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return errors.Wrap(err, "parsing query")
+	}
+
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, def := range doc.Definitions {
+		if frag, ok := def.(*ast.FragmentDefinition); ok {
+			fragments[frag.Name.Value] = frag
+		}
+	}
+
+	seenFragments := map[string]struct{}{}
+	var checkSelections func(*ast.SelectionSet) error
+	checkSelections = func(set *ast.SelectionSet) error {
+		if set == nil {
+			return nil
+		}
+		for _, selection := range set.Selections {
+			switch s := selection.(type) {
+			case *ast.Field:
+				scope, ok := accessTokenScopesByField[s.Name.Value]
+				if !ok {
+					return errors.Errorf("access token scopes do not permit the field %q", s.Name.Value)
+				}
+				if scope == "" {
+					continue
+				}
+				if err := authz.CheckScope(ctx, scope); err != nil {
+					return err
+				}
+
+			case *ast.InlineFragment:
+				if err := checkSelections(s.SelectionSet); err != nil {
+					return err
+				}
+
+			case *ast.FragmentSpread:
+				name := s.Name.Value
+				if _, seen := seenFragments[name]; seen {
+					continue
+				}
+				seenFragments[name] = struct{}{}
+				if frag, ok := fragments[name]; ok {
+					if err := checkSelections(frag.SelectionSet); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	}
+
+	for _, def := range doc.Definitions {
+		if op, ok := def.(*ast.OperationDefinition); ok {
+			if err := checkSelections(op.SelectionSet); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
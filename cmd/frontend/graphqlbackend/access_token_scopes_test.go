@@ -0,0 +1,70 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+)
+
+// 🚨 SECURITY: This tests that access tokens with fine-grained scopes can only request the
+// GraphQL fields their scopes permit.
+func TestCheckAccessTokenScopes(t *testing.T) {
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1, Scopes: []string{authz.ScopeSearchRead, `code:read@^github\.com/`}})
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		query   string
+		wantErr bool
+	}{
+		{
+			name:  "unrestricted actor",
+			ctx:   actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+			query: `mutation { deleteUser(user: "x") { alwaysNil } }`,
+		},
+		{
+			name:  "permitted fields",
+			ctx:   ctx,
+			query: `query { __typename search(query: "x") { results { matchCount } } repository(name: "github.com/a/b") { name } }`,
+		},
+		{
+			name:  "permitted fields in fragments",
+			ctx:   ctx,
+			query: `query { ...A ... on Query { repositories(first: 1) { nodes { name } } } } fragment A on Query { ...A search(query: "x") { __typename } }`,
+		},
+		{
+			name:    "field without scope",
+			ctx:     ctx,
+			query:   `query { search(query: "x") { __typename } currentUser { username } }`,
+			wantErr: true,
+		},
+		{
+			name:    "field without scope in fragment",
+			ctx:     ctx,
+			query:   `query { ...A } fragment A on Query { node(id: "x") { id } }`,
+			wantErr: true,
+		},
+		{
+			name:    "field of missing scope",
+			ctx:     ctx,
+			query:   `mutation { createBatchSpec(batchSpec: "", changesetSpecs: []) { id } }`,
+			wantErr: true,
+		},
+		{
+			name:    "any operation",
+			ctx:     ctx,
+			query:   `query A { search(query: "x") { __typename } } mutation B { createAccessToken(user: "x", scopes: [], note: "") { token } }`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckAccessTokenScopes(tt.ctx, tt.query)
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Errorf("got error %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
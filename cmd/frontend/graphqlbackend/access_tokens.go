@@ -2,8 +2,10 @@ package graphqlbackend
 
 import (
 	"context"
+	"encoding/json"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/graph-gophers/graphql-go"
@@ -53,22 +55,27 @@ func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAcce
 	}
 
 	// Validate scopes.
-	var hasUserAllScope bool
+	var hasUserAllScope, hasSudoScope, hasFineGrainedScope bool
 	seenScope := map[string]struct{}{}
 	sort.Strings(args.Scopes)
 	for _, scope := range args.Scopes {
-		switch scope {
-		case authz.ScopeUserAll:
+		if err := authz.ValidateScope(scope); err != nil {
+			return nil, err
+		}
+
+		switch {
+		case scope == authz.ScopeUserAll:
 			hasUserAllScope = true
-		case authz.ScopeSiteAdminSudo:
+		case scope == authz.ScopeSiteAdminSudo:
 			// 🚨 SECURITY: Only site admins may create a token with the "site-admin:sudo" scope.
 			if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
 				return nil, err
 			} else if envvar.SourcegraphDotComMode() {
 				return nil, errors.New("creation of access tokens with sudo scope is disabled")
 			}
-		default:
-			return nil, errors.Errorf("unknown access token scope %q (valid scopes: %q)", scope, authz.AllScopes)
+			hasSudoScope = true
+		case authz.IsFineGrainedScope(scope):
+			hasFineGrainedScope = true
 		}
 
 		if _, seen := seenScope[scope]; seen {
@@ -76,11 +83,21 @@ func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAcce
 		}
 		seenScope[scope] = struct{}{}
 	}
-	if !hasUserAllScope {
-		return nil, errors.Errorf("all access tokens must have scope %q", authz.ScopeUserAll)
+	switch {
+	case hasUserAllScope && hasFineGrainedScope:
+		return nil, errors.Errorf("access tokens with scope %q may not have other scopes than %q", authz.ScopeUserAll, authz.ScopeSiteAdminSudo)
+	case hasSudoScope && !hasUserAllScope:
+		return nil, errors.Errorf("access tokens with scope %q must also have scope %q", authz.ScopeSiteAdminSudo, authz.ScopeUserAll)
+	case !hasUserAllScope && !hasFineGrainedScope:
+		return nil, errors.Errorf("access tokens must have scope %q or at least one of the scopes %q", authz.ScopeUserAll, []string{authz.ScopeSearchRead, authz.ScopeCodeRead, authz.ScopeCodeIntelUpload, authz.ScopeBatchesWrite})
 	}
 
 	id, token, err := database.AccessTokens(r.db).Create(ctx, userID, args.Scopes, args.Note, actor.FromContext(ctx).UID)
+	if err != nil {
+		return nil, err
+	}
+
+	logAccessTokenCreated(ctx, r.db, userID, args.Scopes)
 
 	if conf.CanSendEmail() {
 		if err := backend.UserEmails.SendUserEmailOnFieldUpdate(ctx, userID, "created an access token"); err != nil {
@@ -88,7 +105,30 @@ func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAcce
 		}
 	}
 
-	return &createAccessTokenResult{id: marshalAccessTokenID(id), token: token}, err
+	return &createAccessTokenResult{id: marshalAccessTokenID(id), token: token}, nil
+}
+
+// logAccessTokenCreated records the creation of an access token and its scopes as a security
+// event.
+func logAccessTokenCreated(ctx context.Context, db dbutil.DB, subjectUserID int32, scopes []string) {
+	args, err := json.Marshal(struct {
+		SubjectUserID int32    `json:"subject_user_id"`
+		Scopes        []string `json:"scopes"`
+	}{
+		SubjectUserID: subjectUserID,
+		Scopes:        scopes,
+	})
+	if err != nil {
+		log15.Error("logAccessTokenCreated: failed to marshal JSON", "error", err)
+	}
+
+	database.SecurityEventLogs(db).LogEvent(ctx, &database.SecurityEvent{
+		Name:      database.SecurityEventNameAccessTokenCreated,
+		UserID:    uint32(actor.FromContext(ctx).UID),
+		Argument:  args,
+		Source:    "BACKEND",
+		Timestamp: time.Now(),
+	})
 }
 
 type createAccessTokenResult struct {
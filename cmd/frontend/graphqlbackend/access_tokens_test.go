@@ -88,6 +88,54 @@ func TestMutation_CreateAccessToken(t *testing.T) {
 		}
 	})
 
+	t.Run("authenticated as user, using fine-grained scopes", func(t *testing.T) {
+		resetMocks()
+		mockAccessTokensCreate(t, 1, []string{`code:read@^github\.com/a/`, "search:read"})
+		database.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+			return &types.User{ID: 1, SiteAdmin: false}, nil
+		}
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		result, err := (&schemaResolver{db: db}).CreateAccessToken(ctx, &createAccessTokenInput{
+			User:   uid1GQLID,
+			Scopes: []string{authz.ScopeSearchRead, `code:read@^github\.com/a/`},
+			Note:   "n",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := graphql.ID("QWNjZXNzVG9rZW46MQ=="); result.ID() != want {
+			t.Errorf("got ID %q, want %q", result.ID(), want)
+		}
+	})
+
+	for name, scopes := range map[string][]string{
+		"unknown scope":                       {"code:write"},
+		"invalid repository pattern":          {"code:read@("},
+		"repository pattern on user:all":      {"user:all@^github\\.com/"},
+		"user:all and fine-grained scope":     {authz.ScopeUserAll, authz.ScopeCodeRead},
+		"sudo without user:all":               {authz.ScopeSiteAdminSudo, authz.ScopeCodeRead},
+		"duplicate fine-grained scope":        {authz.ScopeCodeRead, authz.ScopeCodeRead},
+		"repository pattern without pattern":  {"code:read@"},
+		"repository pattern with a delimiter": {"code:read@a@b"},
+	} {
+		t.Run("authenticated as site admin, using invalid scopes: "+name, func(t *testing.T) {
+			resetMocks()
+			database.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+				return &types.User{ID: 1, SiteAdmin: true}, nil
+			}
+
+			ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+			result, err := (&schemaResolver{db: db}).CreateAccessToken(ctx, &createAccessTokenInput{User: uid1GQLID, Scopes: scopes, Note: "n"})
+			if err == nil {
+				t.Error("err == nil")
+			}
+			if result != nil {
+				t.Errorf("got result %v, want nil", result)
+			}
+		})
+	}
+
 	t.Run("authenticated as user, using site-admin-only scopes", func(t *testing.T) {
 		resetMocks()
 		database.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
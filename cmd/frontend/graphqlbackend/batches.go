@@ -159,6 +159,19 @@ type CreateBatchSpecArgs struct {
 	ChangesetSpecs []graphql.ID
 }
 
+type CreateBatchSpecFromStructuralReplaceArgs struct {
+	Namespace graphql.ID
+	Query     string
+
+	Name        string
+	Description *string
+
+	Title         string
+	Body          *string
+	Branch        string
+	CommitMessage *string
+}
+
 type ChangesetSpecsConnectionArgs struct {
 	First int32
 	After *string
@@ -240,6 +253,10 @@ type CreateBatchSpecExecutionArgs struct {
 	Spec string
 }
 
+type CancelBatchSpecExecutionArgs struct {
+	BatchSpecExecution graphql.ID
+}
+
 type CloseChangesetsArgs struct {
 	BulkOperationBaseArgs
 }
@@ -265,6 +282,7 @@ type BatchChangesResolver interface {
 	// New:
 	CreateBatchChange(ctx context.Context, args *CreateBatchChangeArgs) (BatchChangeResolver, error)
 	CreateBatchSpec(ctx context.Context, args *CreateBatchSpecArgs) (BatchSpecResolver, error)
+	CreateBatchSpecFromStructuralReplace(ctx context.Context, args *CreateBatchSpecFromStructuralReplaceArgs) (BatchSpecResolver, error)
 	ApplyBatchChange(ctx context.Context, args *ApplyBatchChangeArgs) (BatchChangeResolver, error)
 	CloseBatchChange(ctx context.Context, args *CloseBatchChangeArgs) (BatchChangeResolver, error)
 	MoveBatchChange(ctx context.Context, args *MoveBatchChangeArgs) (BatchChangeResolver, error)
@@ -280,6 +298,7 @@ type BatchChangesResolver interface {
 	ReenqueueChangesets(ctx context.Context, args *ReenqueueChangesetsArgs) (BulkOperationResolver, error)
 	MergeChangesets(ctx context.Context, args *MergeChangesetsArgs) (BulkOperationResolver, error)
 	CreateBatchSpecExecution(ctx context.Context, args *CreateBatchSpecExecutionArgs) (BatchSpecExecutionResolver, error)
+	CancelBatchSpecExecution(ctx context.Context, args *CancelBatchSpecExecutionArgs) (BatchSpecExecutionResolver, error)
 	CloseChangesets(ctx context.Context, args *CloseChangesetsArgs) (BulkOperationResolver, error)
 	PublishChangesets(ctx context.Context, args *PublishChangesetsArgs) (BulkOperationResolver, error)
 
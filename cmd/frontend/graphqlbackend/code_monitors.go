@@ -48,6 +48,7 @@ type MonitorTrigger interface {
 type MonitorQueryResolver interface {
 	ID() graphql.ID
 	Query() string
+	Kind() string
 	Events(ctx context.Context, args *ListEventsArgs) (MonitorTriggerEventConnectionResolver, error)
 }
 
@@ -135,6 +136,7 @@ type CreateCodeMonitorArgs struct {
 
 type CreateTriggerArgs struct {
 	Query string
+	Kind  string
 }
 
 type CreateActionArgs struct {
@@ -2,6 +2,8 @@ package graphqlbackend
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
@@ -13,37 +15,73 @@ type ExecutionLogEntryResolver interface {
 	Command() []string
 	StartTime() DateTime
 	ExitCode() int32
-	Out(ctx context.Context) (string, error)
+	Out(ctx context.Context, args *ExecutionLogEntryOutArgs) (string, error)
 	DurationMilliseconds() int32
+	Running() bool
 }
 
-func NewExecutionLogEntryResolver(db dbutil.DB, entry workerutil.ExecutionLogEntry) *executionLogEntryResolver {
+type ExecutionLogEntryOutArgs struct {
+	AfterLine int32
+}
+
+// ExecutionLogEntryRecord is the state of the record an execution log entry belongs to.
+// Entries without an exit code are only running while the record is being processed.
+type ExecutionLogEntryRecord struct {
+	State      string
+	FinishedAt *time.Time
+}
+
+func NewExecutionLogEntryResolver(db dbutil.DB, entry workerutil.ExecutionLogEntry, record ExecutionLogEntryRecord) *executionLogEntryResolver {
 	return &executionLogEntryResolver{
-		db:    db,
-		entry: entry,
+		db:     db,
+		entry:  entry,
+		record: record,
 	}
 }
 
 type executionLogEntryResolver struct {
-	db    dbutil.DB
-	entry workerutil.ExecutionLogEntry
+	db     dbutil.DB
+	entry  workerutil.ExecutionLogEntry
+	record ExecutionLogEntryRecord
 }
 
 var _ ExecutionLogEntryResolver = &executionLogEntryResolver{}
 
 func (r *executionLogEntryResolver) Key() string       { return r.entry.Key }
 func (r *executionLogEntryResolver) Command() []string { return r.entry.Command }
-func (r *executionLogEntryResolver) ExitCode() int32   { return int32(r.entry.ExitCode) }
+
+func (r *executionLogEntryResolver) ExitCode() int32 {
+	if r.entry.ExitCode == nil {
+		return 0
+	}
+	return int32(*r.entry.ExitCode)
+}
 
 func (r *executionLogEntryResolver) StartTime() DateTime {
 	return DateTime{Time: r.entry.StartTime}
 }
 
 func (r *executionLogEntryResolver) DurationMilliseconds() int32 {
-	return int32(r.entry.DurationMs)
+	if r.entry.DurationMs != nil {
+		return int32(*r.entry.DurationMs)
+	}
+	if r.Running() {
+		return int32(time.Since(r.entry.StartTime) / time.Millisecond)
+	}
+
+	// The command never finished, because the record was canceled, or the executor
+	// died and the record was reset. We can only tell how long it ran for at most.
+	if r.record.FinishedAt == nil || r.record.FinishedAt.Before(r.entry.StartTime) {
+		return 0
+	}
+	return int32(r.record.FinishedAt.Sub(r.entry.StartTime) / time.Millisecond)
 }
 
-func (r *executionLogEntryResolver) Out(ctx context.Context) (string, error) {
+func (r *executionLogEntryResolver) Running() bool {
+	return r.entry.ExitCode == nil && r.record.State == "processing"
+}
+
+func (r *executionLogEntryResolver) Out(ctx context.Context, args *ExecutionLogEntryOutArgs) (string, error) {
 	// 🚨 SECURITY: Only site admins can view executor log contents.
 	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
 		if err != backend.ErrMustBeSiteAdmin {
@@ -53,5 +91,18 @@ func (r *executionLogEntryResolver) Out(ctx context.Context) (string, error) {
 		return "", nil
 	}
 
-	return r.entry.Out, nil
+	return skipLines(r.entry.Out, int(args.AfterLine)), nil
+}
+
+// skipLines returns the given output without its first n lines.
+func skipLines(out string, n int) string {
+	for ; n > 0 && out != ""; n-- {
+		i := strings.IndexByte(out, '\n')
+		if i < 0 {
+			return ""
+		}
+		out = out[i+1:]
+	}
+
+	return out
 }
@@ -0,0 +1,84 @@
+package graphqlbackend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+)
+
+func TestExecutionLogEntryRunning(t *testing.T) {
+	startTime := time.Now().Add(-time.Minute)
+	finishedAt := startTime.Add(5 * time.Second)
+	exitCode, durationMs := 1, 1500
+
+	for _, tc := range []struct {
+		name         string
+		entry        workerutil.ExecutionLogEntry
+		record       ExecutionLogEntryRecord
+		wantRunning  bool
+		wantDuration int32
+	}{
+		{
+			name:         "finished",
+			entry:        workerutil.ExecutionLogEntry{StartTime: startTime, ExitCode: &exitCode, DurationMs: &durationMs},
+			record:       ExecutionLogEntryRecord{State: "processing"},
+			wantRunning:  false,
+			wantDuration: 1500,
+		},
+		{
+			name:         "canceled",
+			entry:        workerutil.ExecutionLogEntry{StartTime: startTime},
+			record:       ExecutionLogEntryRecord{State: "canceled", FinishedAt: &finishedAt},
+			wantRunning:  false,
+			wantDuration: 5000,
+		},
+		{
+			name:         "reset",
+			entry:        workerutil.ExecutionLogEntry{StartTime: startTime},
+			record:       ExecutionLogEntryRecord{State: "queued"},
+			wantRunning:  false,
+			wantDuration: 0,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewExecutionLogEntryResolver(nil, tc.entry, tc.record)
+			if have := r.Running(); have != tc.wantRunning {
+				t.Errorf("unexpected running. want=%v have=%v", tc.wantRunning, have)
+			}
+			if have := r.DurationMilliseconds(); have != tc.wantDuration {
+				t.Errorf("unexpected duration. want=%d have=%d", tc.wantDuration, have)
+			}
+		})
+	}
+
+	r := NewExecutionLogEntryResolver(nil, workerutil.ExecutionLogEntry{StartTime: startTime}, ExecutionLogEntryRecord{State: "processing"})
+	if !r.Running() {
+		t.Errorf("expected entry of processing record to be running")
+	}
+	if have := r.DurationMilliseconds(); have < 60000 {
+		t.Errorf("unexpected duration of running entry. want>=%d have=%d", 60000, have)
+	}
+}
+
+func TestSkipLines(t *testing.T) {
+	out := "stdout: a\nstderr: b\nstdout: c\n"
+
+	for _, tc := range []struct {
+		n    int
+		want string
+	}{
+		{n: 0, want: out},
+		{n: 1, want: "stderr: b\nstdout: c\n"},
+		{n: 3, want: ""},
+		{n: 10, want: ""},
+	} {
+		if have := skipLines(out, tc.n); have != tc.want {
+			t.Errorf("skipLines(%d): want=%q have=%q", tc.n, tc.want, have)
+		}
+	}
+
+	if have, want := skipLines("stdout: a\nstdout: partial", 1), "stdout: partial"; have != want {
+		t.Errorf("unexpected output for partial line: want=%q have=%q", want, have)
+	}
+}
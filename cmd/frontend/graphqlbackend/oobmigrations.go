@@ -2,6 +2,9 @@ package graphqlbackend
 
 import (
 	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
 
 	"github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
@@ -72,6 +75,79 @@ func (r *schemaResolver) SetMigrationDirection(ctx context.Context, args *struct
 	return nil, nil
 }
 
+// PauseOutOfBandMigration stops an out-of-band migration from making progress in either direction.
+func (r *schemaResolver) PauseOutOfBandMigration(ctx context.Context, args *struct{ ID graphql.ID }) (*EmptyResponse, error) {
+	return r.updateOutOfBandMigration(ctx, args.ID, func(store *oobmigration.Store, id int) error {
+		return store.UpdatePaused(ctx, id, true)
+	})
+}
+
+// ResumeOutOfBandMigration allows a paused out-of-band migration to make progress again.
+func (r *schemaResolver) ResumeOutOfBandMigration(ctx context.Context, args *struct{ ID graphql.ID }) (*EmptyResponse, error) {
+	return r.updateOutOfBandMigration(ctx, args.ID, func(store *oobmigration.Store, id int) error {
+		return store.UpdatePaused(ctx, id, false)
+	})
+}
+
+// RollbackOutOfBandMigration runs an out-of-band migration in reverse until no migrated data remains.
+func (r *schemaResolver) RollbackOutOfBandMigration(ctx context.Context, args *struct{ ID graphql.ID }) (*EmptyResponse, error) {
+	return r.updateOutOfBandMigration(ctx, args.ID, func(store *oobmigration.Store, id int) error {
+		return store.Rollback(ctx, id)
+	})
+}
+
+// SetOutOfBandMigrationRateLimit overrides the batch size and the interval between batches of an
+// out-of-band migration. Null values restore the defaults of the migration.
+func (r *schemaResolver) SetOutOfBandMigrationRateLimit(ctx context.Context, args *struct {
+	ID                        graphql.ID
+	BatchSize                 *int32
+	BatchIntervalMilliseconds *int32
+}) (*EmptyResponse, error) {
+	var batchSize *int
+	if args.BatchSize != nil {
+		if *args.BatchSize <= 0 {
+			return nil, errors.New("batchSize must be positive")
+		}
+
+		v := int(*args.BatchSize)
+		batchSize = &v
+	}
+
+	var batchInterval *time.Duration
+	if args.BatchIntervalMilliseconds != nil {
+		if *args.BatchIntervalMilliseconds <= 0 {
+			return nil, errors.New("batchIntervalMilliseconds must be positive")
+		}
+
+		v := time.Duration(*args.BatchIntervalMilliseconds) * time.Millisecond
+		batchInterval = &v
+	}
+
+	return r.updateOutOfBandMigration(ctx, args.ID, func(store *oobmigration.Store, id int) error {
+		return store.UpdateRateLimit(ctx, id, batchSize, batchInterval)
+	})
+}
+
+// updateOutOfBandMigration invokes the given function with the identifier of the out-of-band
+// migration referenced by the given GraphQL id after ensuring the current user is a site admin.
+func (r *schemaResolver) updateOutOfBandMigration(ctx context.Context, id graphql.ID, update func(store *oobmigration.Store, id int) error) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins may modify out-of-band migrations
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	migrationID, err := UnmarshalOutOfBandMigrationID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := update(oobmigration.NewStoreWithDB(r.db), int(migrationID)); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
 // MarshalOutOfBandMigrationID converts an internal out of band migration id into a GraphQL id.
 func MarshalOutOfBandMigrationID(id int32) graphql.ID {
 	return relay.MarshalID("OutOfBandMigration", id)
@@ -109,6 +185,25 @@ func (r *outOfBandMigrationResolver) Created() DateTime      { return DateTime{r
 func (r *outOfBandMigrationResolver) LastUpdated() *DateTime { return DateTimeOrNil(r.m.LastUpdated) }
 func (r *outOfBandMigrationResolver) NonDestructive() bool   { return r.m.NonDestructive }
 func (r *outOfBandMigrationResolver) ApplyReverse() bool     { return r.m.ApplyReverse }
+func (r *outOfBandMigrationResolver) Paused() bool           { return r.m.Paused }
+
+func (r *outOfBandMigrationResolver) BatchSize() *int32 {
+	if r.m.BatchSize == nil {
+		return nil
+	}
+
+	v := int32(*r.m.BatchSize)
+	return &v
+}
+
+func (r *outOfBandMigrationResolver) BatchIntervalMilliseconds() *int32 {
+	if r.m.BatchInterval == nil {
+		return nil
+	}
+
+	v := int32(*r.m.BatchInterval / time.Millisecond)
+	return &v
+}
 
 func (r *outOfBandMigrationResolver) Errors() []*outOfBandMigrationErrorResolver {
 	resolvers := make([]*outOfBandMigrationErrorResolver, 0, len(r.m.Errors))
@@ -90,10 +90,15 @@ func (r *schemaResolver) ParseSearchQuery(ctx context.Context, args *struct {
 		return nil, err
 	}
 
+	expandedQuery, _, err := query.ExpandMacros(args.Query, settings.SearchMacros)
+	if err != nil {
+		return nil, err
+	}
+
 	globbing := getBoolPtr(settings.SearchGlobbing, false)
 
 	plan, err := query.Pipeline(
-		query.Init(args.Query, searchType),
+		query.Init(expandedQuery, searchType),
 		query.With(globbing, query.Globbing),
 	)
 	if err != nil {
@@ -11,6 +11,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/search"
@@ -179,6 +180,18 @@ func (r *repositoryConnectionResolver) compute(ctx context.Context) ([]*types.Re
 				repos = keepRepos
 			}
 
+			// 🚨 SECURITY: Access tokens whose code:read scope is restricted to some
+			// repositories can't list other repositories.
+			if _, ok := authz.RepoPattern(ctx, authz.ScopeCodeRead); ok {
+				keepRepos := repos[:0]
+				for _, repo := range repos {
+					if authz.CheckRepoScope(ctx, authz.ScopeCodeRead, repo.Name) == nil {
+						keepRepos = append(keepRepos, repo)
+					}
+				}
+				repos = keepRepos
+			}
+
 			r.repos = append(r.repos, repos...)
 
 			if opt2.LimitOffset == nil {
@@ -239,6 +252,10 @@ func (r *repositoryConnectionResolver) TotalCount(ctx context.Context, args *Tot
 		// Don't support counting if filtering by index status.
 		return nil, nil
 	}
+	if _, ok := authz.RepoPattern(ctx, authz.ScopeCodeRead); ok {
+		// Don't support counting if filtering by access token scopes.
+		return nil, nil
+	}
 
 	// Counting repositories is slow on Sourcegraph.com. Don't wait very long for an exact count.
 	if !args.Precise && envvar.SourcegraphDotComMode() {
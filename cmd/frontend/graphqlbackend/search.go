@@ -10,6 +10,7 @@ import (
 	otlog "github.com/opentracing/opentracing-go/log"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/endpoint"
@@ -66,6 +67,12 @@ func NewSearchImplementer(ctx context.Context, db dbutil.DB, args *SearchArgs) (
 		tr.Finish()
 	}()
 
+	// 🚨 SECURITY: Access tokens with fine-grained scopes may only search if they have the
+	// search:read scope.
+	if err := authz.CheckScope(ctx, authz.ScopeSearchRead); err != nil {
+		return nil, err
+	}
+
 	settings := args.Settings
 	if settings == nil {
 		var err error
@@ -75,11 +82,16 @@ func NewSearchImplementer(ctx context.Context, db dbutil.DB, args *SearchArgs) (
 		}
 	}
 
+	expandedQuery, _, err := query.ExpandMacros(args.Query, settings.SearchMacros)
+	if err != nil {
+		return alertForQuery(args.Query, err).wrapSearchImplementer(db), nil
+	}
+
 	searchType, err := detectSearchType(args.Version, args.PatternType)
 	if err != nil {
 		return nil, err
 	}
-	searchType = overrideSearchType(args.Query, searchType)
+	searchType = overrideSearchType(expandedQuery, searchType)
 
 	if searchType == query.SearchTypeStructural && !conf.StructuralSearchEnabled() {
 		return nil, errors.New("Structural search is disabled in the site configuration.")
@@ -89,7 +101,7 @@ func NewSearchImplementer(ctx context.Context, db dbutil.DB, args *SearchArgs) (
 	globbing := getBoolPtr(settings.SearchGlobbing, false)
 	tr.LogFields(otlog.Bool("globbing", globbing))
 	plan, err = query.Pipeline(
-		query.Init(args.Query, searchType),
+		query.Init(expandedQuery, searchType),
 		query.With(globbing, query.Globbing),
 	)
 	if err != nil {
@@ -97,6 +109,33 @@ func NewSearchImplementer(ctx context.Context, db dbutil.DB, args *SearchArgs) (
 	}
 	tr.LazyPrintf("parsing done")
 
+	// 🚨 SECURITY: debug:yes reveals the addresses of the search backends, so only site
+	// admins may use it.
+	if planIsDebug(plan) {
+		if err := backend.CheckCurrentUserIsSiteAdmin(ctx, db); err != nil {
+			return nil, errors.New("debug:yes is only available to site admins")
+		}
+	}
+
+	// 🚨 SECURITY: If the search:read scope of the access token is restricted to some
+	// repositories, every query of the plan must only search those repositories.
+	if pattern, ok := authz.RepoPattern(ctx, authz.ScopeSearchRead); ok {
+		plan = query.MapPlan(plan, func(basic query.Basic) query.Basic {
+			return basic.AddRepoFilter(pattern)
+		})
+	}
+
+	// Aggregations count every result, so unless the query limits the number of
+	// results with count:, the search is exhaustive.
+	if plan.ToParseTree().Aggregation() != "" {
+		plan = query.MapPlan(plan, func(basic query.Basic) query.Basic {
+			if basic.GetCount() != "" {
+				return basic
+			}
+			return basic.AddCount(query.CountAllLimit)
+		})
+	}
+
 	defaultLimit := defaultMaxSearchResults
 	if args.Stream != nil {
 		defaultLimit = defaultMaxSearchResultsStreaming
@@ -112,6 +151,7 @@ func NewSearchImplementer(ctx context.Context, db dbutil.DB, args *SearchArgs) (
 			Plan:           plan,
 			Query:          plan.ToParseTree(),
 			OriginalQuery:  args.Query,
+			ExpandedQuery:  expandedQuery,
 			VersionContext: args.VersionContext,
 			UserSettings:   settings,
 			PatternType:    searchType,
@@ -162,6 +202,16 @@ func detectSearchType(version string, patternType *string) (query.SearchType, er
 	return searchType, nil
 }
 
+// planIsDebug returns true if a query of the plan has debug:yes.
+func planIsDebug(plan query.Plan) bool {
+	for _, basic := range plan {
+		if basic.IsDebug() {
+			return true
+		}
+	}
+	return false
+}
+
 func overrideSearchType(input string, searchType query.SearchType) query.SearchType {
 	q, err := query.Parse(input, query.SearchTypeLiteral)
 	q = query.LowercaseFieldNames(q)
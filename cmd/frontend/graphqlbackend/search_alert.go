@@ -26,6 +26,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/search/run"
 	"github.com/sourcegraph/sourcegraph/internal/search/searchcontexts"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
 
@@ -73,6 +74,14 @@ func alertForQuery(queryString string, err error) *searchAlert {
 			description:    `I'm having trouble understanding that query. Your query contains "and" or "or" operators that make me think they apply to filters like "repo:" or "file:". We only support "and" or "or" operators on search patterns for file contents currently. You can help me by putting parentheses around the search pattern.`,
 		}
 	}
+	var macroErr *query.MacroError
+	if errors.As(err, &macroErr) {
+		return &searchAlert{
+			prometheusType: "invalid_search_macro",
+			title:          "Invalid search macro",
+			description:    fmt.Sprintf("%s (at position %d of the query). Search macros are defined in the `search.macros` setting.", capFirst(macroErr.Msg), macroErr.Range.Start.Column),
+		}
+	}
 	return &searchAlert{
 		prometheusType: "generic_invalid_query",
 		title:          "Unable To Process Query",
@@ -238,6 +247,15 @@ func (r *searchResolver) alertForNoResolvedRepos(ctx context.Context, q query.Q)
 		}
 	}
 
+	if proposedQueries := r.similarRepoQueries(ctx, q, repoFilters); len(proposedQueries) > 0 {
+		return &searchAlert{
+			prometheusType:  "no_resolved_repos__did_you_mean",
+			title:           "No repositories found",
+			description:     "No repositories matched your `repo:` filter, but there are repositories with similar names.",
+			proposedQueries: proposedQueries,
+		}
+	}
+
 	return &searchAlert{
 		prometheusType: "no_resolved_repos__generic",
 		title:          "No repositories found",
@@ -391,6 +409,25 @@ func alertForStructuralSearchNotSet(queryString string) *searchAlert {
 	}
 }
 
+// hasUnindexedFileFilters returns true if the query plan filters on the size or
+// line count of files, which indexed search does not support.
+func hasUnindexedFileFilters(plan query.Plan) bool {
+	for _, b := range plan {
+		if b.FileFilters().NeedContent() {
+			return true
+		}
+	}
+	return false
+}
+
+func alertForUnindexedFileFilters() *searchAlert {
+	return &searchAlert{
+		prometheusType: "file_filters_not_indexed",
+		title:          "size: and lines: are not supported for indexed search",
+		description:    fmt.Sprintf("Queries with size: or lines: filters search the repositories without the index, which is slower and searches at most %d repositories at a time. Use the \"repo:\" filter to narrow down which repositories to search.", search.MaxUnindexedRepoRevSearchesPerQuery),
+	}
+}
+
 type missingRepoRevsError struct {
 	Missing []*search.RepositoryRevisions
 }
@@ -433,6 +470,47 @@ func alertForMissingRepoRevs(missingRepoRevs []*search.RepositoryRevisions) *sea
 	}
 }
 
+type reposWithoutCommitAtTimeError struct {
+	Repos  []types.RepoName
+	AtTime *query.RevAtTimePredicate
+}
+
+func (*reposWithoutCommitAtTimeError) Error() string {
+	return "repos without commit at time"
+}
+
+func alertForReposWithoutCommitAtTime(repos []types.RepoName, atTime *query.RevAtTimePredicate) *searchAlert {
+	branch := "the default branch"
+	if atTime.Branch != "" {
+		branch = "branch " + atTime.Branch
+	}
+	date := atTime.Time.Format(time.RFC3339)
+
+	var description string
+	if len(repos) == 1 {
+		description = fmt.Sprintf("The repository %s could not be searched because %s has no commit on or before %s.", repos[0].Name, branch, date)
+	} else {
+		sampleSize := 10
+		if sampleSize > len(repos) {
+			sampleSize = len(repos)
+		}
+		b := strings.Builder{}
+		_, _ = fmt.Fprintf(&b, "%d repositories could not be searched because %s has no commit on or before %s:", len(repos), branch, date)
+		for _, repo := range repos[:sampleSize] {
+			_, _ = fmt.Fprintf(&b, "\n* %s", repo.Name)
+		}
+		if sampleSize < len(repos) {
+			b.WriteString("\n* ...")
+		}
+		description = b.String()
+	}
+	return &searchAlert{
+		prometheusType: "repos_without_commit_at_time",
+		title:          "Some repositories could not be searched",
+		description:    description,
+	}
+}
+
 // pathParentsByFrequency returns the most common path parents of the given paths.
 // For example, given paths [a/b a/c x/y], it would return [a x] because "a"
 // is a parent to 2 paths and "x" is a parent to 1 path.
@@ -504,11 +582,15 @@ func alertForError(err error) *searchAlert {
 		rErr  *run.RepoLimitError
 		tErr  *run.TimeLimitError
 		mErr  *missingRepoRevsError
+		cErr  *reposWithoutCommitAtTimeError
 	)
 
 	if errors.As(err, &mErr) {
 		alert = alertForMissingRepoRevs(mErr.Missing)
 		alert.priority = 6
+	} else if errors.As(err, &cErr) {
+		alert = alertForReposWithoutCommitAtTime(cErr.Repos, cErr.AtTime)
+		alert.priority = 6
 	} else if strings.Contains(err.Error(), "Worker_oomed") || strings.Contains(err.Error(), "Worker_exited_abnormally") {
 		alert = &searchAlert{
 			prometheusType: "structural_search_needs_more_memory",
@@ -675,6 +757,10 @@ func (o *alertObserver) Done(stats *streaming.Stats) (*searchAlert, error) {
 		o.update(alertForStructuralSearchNotSet(o.Inputs.OriginalQuery))
 	}
 
+	if hasUnindexedFileFilters(o.Inputs.Plan) {
+		o.update(alertForUnindexedFileFilters())
+	}
+
 	if o.hasResults && o.err != nil {
 		log15.Error("Errors during search", "error", o.err)
 		return o.alert, nil
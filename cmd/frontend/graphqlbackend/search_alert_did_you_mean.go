@@ -0,0 +1,371 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"go.uber.org/atomic"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+)
+
+const (
+	// didYouMeanTimeout is the time we spend at most looking for the
+	// symbols a search without results may have been looking for.
+	didYouMeanTimeout = 2 * time.Second
+
+	// maxDidYouMeanIdentifiers is the number of identifiers of a search
+	// pattern we look for similar symbols of.
+	maxDidYouMeanIdentifiers = 3
+
+	// Shorter identifiers are similar to too many symbols for the suggestions
+	// to be useful, and longer ones make for expensive regular expressions.
+	minDidYouMeanIdentifierLength = 4
+	maxDidYouMeanIdentifierLength = 64
+
+	// didYouMeanSymbolCount is the number of symbols similar to an identifier
+	// we look at.
+	didYouMeanSymbolCount = 200
+
+	// maxDidYouMeanQueries is the number of queries we propose at most.
+	maxDidYouMeanQueries = 5
+)
+
+var identifierRegexp = lazyregexp.New(`[A-Za-z_][A-Za-z0-9_]*`)
+
+// didYouMeanSuggestion is the replacement of an identifier of a search
+// pattern by the name of a similar symbol.
+type didYouMeanSuggestion struct {
+	identifier  string
+	replacement string
+
+	// distance is the edit distance of replacement to identifier, ignoring
+	// case. It is 0 if they only differ in case.
+	distance int
+
+	// count is the number of symbols named replacement we found. Common
+	// names are better suggestions.
+	count int
+}
+
+// withNoResultsAlert adds an alert to the results of a search which found
+// nothing and raised no alert. The alert proposes queries in which the
+// identifiers of the search pattern are replaced by the names of similar
+// symbols in the searched repositories.
+//
+// Looking for similar symbols runs more searches, so it is only done for
+// streaming searches, which are run by users of the web app rather than by
+// API clients, code monitors or insights.
+func (r *searchResolver) withNoResultsAlert(ctx context.Context, sr *SearchResults) *SearchResults {
+	if sr == nil {
+		sr = &SearchResults{}
+	}
+	if len(sr.Matches) > 0 || sr.Alert != nil || ctx.Err() != nil {
+		return sr
+	}
+	// Symbols are no suggestions for commits, diffs or repositories.
+	if searchesNonFileTypes(r.Query) {
+		return sr
+	}
+	// The search may have found something in the repositories it didn't
+	// search.
+	if sr.Stats.IsLimitHit || sr.Stats.Status.Any(search.RepoStatusTimedout|search.RepoStatusCloning|search.RepoStatusMissing) {
+		return sr
+	}
+	sr.Alert = r.alertForNoResults(ctx)
+	return sr
+}
+
+// searchesNonFileTypes returns true if q has a type:commit, type:diff or
+// type:repo filter.
+func searchesNonFileTypes(q query.Q) bool {
+	found := false
+	query.VisitField(q, query.FieldType, func(value string, negated bool, _ query.Annotation) {
+		switch strings.ToLower(value) {
+		case "commit", "diff", "repo":
+			found = found || !negated
+		}
+	})
+	return found
+}
+
+// alertForNoResults returns an alert which proposes queries replacing the
+// identifiers of the search pattern by the names of similar symbols, or nil
+// if there are no similar symbols.
+func (r *searchResolver) alertForNoResults(ctx context.Context) *searchAlert {
+	if r.PatternType == query.SearchTypeStructural || len(r.Plan) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, didYouMeanTimeout)
+	defer cancel()
+
+	var suggestions []didYouMeanSuggestion
+	for _, identifier := range patternIdentifiers(r.Query) {
+		s, err := r.symbolSuggestions(ctx, identifier)
+		if err != nil {
+			if ctx.Err() == nil {
+				log15.Warn("failed to find symbols similar to search pattern", "identifier", identifier, "error", err)
+			}
+			break
+		}
+		suggestions = append(suggestions, s...)
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		si, sj := suggestions[i], suggestions[j]
+		if si.distance != sj.distance {
+			return si.distance < sj.distance
+		}
+		if si.count != sj.count {
+			return si.count > sj.count
+		}
+		return si.replacement < sj.replacement
+	})
+
+	var proposedQueries []*searchQueryDescription
+	for _, s := range suggestions {
+		if len(proposedQueries) == maxDidYouMeanQueries {
+			break
+		}
+		proposedQueries = append(proposedQueries, &searchQueryDescription{
+			description: fmt.Sprintf("did you mean %s?", s.replacement),
+			query:       query.ReplaceWord(r.Query, s.identifier, s.replacement),
+			patternType: r.PatternType,
+		})
+	}
+	if len(proposedQueries) == 0 {
+		return nil
+	}
+
+	return &searchAlert{
+		prometheusType:  "no_results__did_you_mean",
+		title:           "No results",
+		description:     "No results matched your query, but there are symbols with similar names in the repositories you searched.",
+		proposedQueries: proposedQueries,
+	}
+}
+
+// symbolSuggestions searches the repositories of the search for symbols
+// whose names are at most one edit away from identifier, ignoring case.
+func (r *searchResolver) symbolSuggestions(ctx context.Context, identifier string) ([]didYouMeanSuggestion, error) {
+	plan, err := query.SymbolSearchPlan(r.Plan[0], fuzzyIdentifierRegexp(identifier), didYouMeanSymbolCount)
+	if err != nil {
+		return nil, err
+	}
+
+	// Like for predicates, disable streaming so we can use the results
+	// rather than sending them back to the caller.
+	orig := r.stream
+	r.stream = nil
+	defer func() { r.stream = orig }()
+
+	sr, err := r.resultsRecursive(ctx, plan)
+	if err != nil || sr == nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, m := range sr.Matches {
+		fm, ok := m.(*result.FileMatch)
+		if !ok {
+			continue
+		}
+		for _, sym := range fm.Symbols {
+			counts[sym.Symbol.Name]++
+		}
+	}
+
+	caseSensitive := r.Query.IsCaseSensitive()
+	var suggestions []didYouMeanSuggestion
+	for name, count := range counts {
+		if name == identifier {
+			continue
+		}
+		distance := editDistance(strings.ToLower(identifier), strings.ToLower(name))
+		// Without case:yes, the search already matched the symbols whose
+		// names only differ in case.
+		if distance > 1 || (distance == 0 && !caseSensitive) {
+			continue
+		}
+		suggestions = append(suggestions, didYouMeanSuggestion{
+			identifier:  identifier,
+			replacement: name,
+			distance:    distance,
+			count:       count,
+		})
+	}
+	return suggestions, nil
+}
+
+// patternIdentifiers returns the distinct identifiers in the non-negated
+// search patterns of q. Regular expression patterns are only considered if
+// they are an identifier.
+func patternIdentifiers(q query.Q) []string {
+	seen := map[string]struct{}{}
+	var identifiers []string
+	query.VisitPattern(q, func(value string, negated bool, annotation query.Annotation) {
+		if negated {
+			return
+		}
+		if annotation.Labels.IsSet(query.Regexp) && identifierRegexp.FindString(value) != value {
+			return
+		}
+		for _, identifier := range identifierRegexp.FindAllString(value, -1) {
+			if len(identifier) < minDidYouMeanIdentifierLength || len(identifier) > maxDidYouMeanIdentifierLength {
+				continue
+			}
+			if _, ok := seen[identifier]; ok {
+				continue
+			}
+			seen[identifier] = struct{}{}
+			identifiers = append(identifiers, identifier)
+		}
+	})
+	if len(identifiers) > maxDidYouMeanIdentifiers {
+		identifiers = identifiers[:maxDidYouMeanIdentifiers]
+	}
+	return identifiers
+}
+
+// fuzzyIdentifierRegexp returns a regular expression which matches the
+// identifiers at most one edit away from identifier. An edit inserts,
+// deletes or substitutes a character or transposes two adjacent characters.
+// identifier must only consist of characters matched by identifierRegexp,
+// which have no special meaning in regular expressions.
+func fuzzyIdentifierRegexp(identifier string) string {
+	variants := []string{identifier, identifier + "."}
+	for i := 0; i < len(identifier); i++ {
+		prefix, rest := identifier[:i], identifier[i+1:]
+		variants = append(variants,
+			prefix+rest,               // deletion
+			prefix+"."+rest,           // substitution
+			prefix+"."+identifier[i:], // insertion
+		)
+		if i+1 < len(identifier) {
+			variants = append(variants, prefix+identifier[i+1:i+2]+identifier[i:i+1]+identifier[i+2:]) // transposition
+		}
+	}
+	return "^(?:" + strings.Join(variants, "|") + ")$"
+}
+
+// editDistance returns the number of insertions, deletions, substitutions
+// and transpositions of adjacent characters it takes to turn a into b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	// d[i][j] is the distance of ra[:i] and rb[:j].
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = d[i-1][j-1] + cost
+			if d[i-1][j]+1 < d[i][j] {
+				d[i][j] = d[i-1][j] + 1
+			}
+			if d[i][j-1]+1 < d[i][j] {
+				d[i][j] = d[i][j-1] + 1
+			}
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] && d[i-2][j-2]+1 < d[i][j] {
+				d[i][j] = d[i-2][j-2] + 1
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+// similarRepoQueries returns queries which replace the repo: filters of q by
+// the names of repositories with similar names. It is used when no repository
+// matches the filters.
+func (r *searchResolver) similarRepoQueries(ctx context.Context, q query.Q, repoFilters []string) []*searchQueryDescription {
+	var proposedQueries []*searchQueryDescription
+	for _, filter := range repoFilters {
+		pattern, rev := filter, ""
+		if i := strings.Index(filter, "@"); i >= 0 {
+			pattern, rev = filter[:i], filter[i:]
+		}
+		name, ok := literalRepoPattern(pattern)
+		if !ok || len(name) < minDidYouMeanIdentifierLength {
+			continue
+		}
+
+		repos, err := database.Repos(r.db).ListRepoNames(ctx, database.ReposListOptions{
+			SimilarTo:   name,
+			LimitOffset: &database.LimitOffset{Limit: maxDidYouMeanQueries},
+		})
+		if err != nil {
+			log15.Warn("failed to find repositories with similar names", "name", name, "error", err)
+			continue
+		}
+		for _, repo := range repos {
+			proposedQueries = append(proposedQueries, &searchQueryDescription{
+				description: fmt.Sprintf("did you mean %s?", repo.Name),
+				query:       query.ReplaceFieldValue(q, query.FieldRepo, filter, "^"+regexp.QuoteMeta(string(repo.Name))+"$"+rev),
+				patternType: r.PatternType,
+			})
+		}
+	}
+	if len(proposedQueries) > maxDidYouMeanQueries {
+		proposedQueries = proposedQueries[:maxDidYouMeanQueries]
+	}
+	return proposedQueries
+}
+
+// literalRepoPattern returns the repository name which the repo: pattern
+// spells out, if it is a literal string apart from anchors and unescaped dots,
+// which are common in repository names like github.com/foo/bar.
+func literalRepoPattern(pattern string) (string, bool) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	subs := []*syntax.Regexp{re}
+	if re.Op == syntax.OpConcat {
+		subs = re.Sub
+	}
+
+	var name strings.Builder
+	for _, sub := range subs {
+		switch sub.Op {
+		case syntax.OpLiteral:
+			name.WriteString(string(sub.Rune))
+		case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+			name.WriteByte('.')
+		case syntax.OpBeginText, syntax.OpEndText, syntax.OpBeginLine, syntax.OpEndLine:
+		default:
+			return "", false
+		}
+	}
+	return name.String(), name.Len() > 0
+}
+
+// streamWithResultsSeen returns a stream which sends the events it receives
+// to parent and sets seen once an event has results.
+func streamWithResultsSeen(parent streaming.Sender, seen *atomic.Bool) streaming.Sender {
+	return streaming.StreamFunc(func(e streaming.SearchEvent) {
+		if len(e.Results) > 0 {
+			seen.Store(true)
+		}
+		parent.Send(e)
+	})
+}
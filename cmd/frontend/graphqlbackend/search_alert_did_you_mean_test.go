@@ -0,0 +1,215 @@
+package graphqlbackend
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	searchrepos "github.com/sourcegraph/sourcegraph/internal/search/repos"
+	"github.com/sourcegraph/sourcegraph/internal/search/run"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestFuzzyIdentifierRegexp(t *testing.T) {
+	re := regexp.MustCompile(fuzzyIdentifierRegexp("NewReader"))
+	for _, name := range []string{
+		"NewReader",
+		"NewReaders", // insertion at the end
+		"NewXReader", // insertion
+		"NewReade",   // deletion
+		"ewReader",   // deletion at the start
+		"NewReadEr",  // substitution
+		"NewRaeder",  // transposition
+		"eNwReader",  // transposition at the start
+		"NewReadre",  // transposition at the end
+	} {
+		if !re.MatchString(name) {
+			t.Errorf("%s: want match", name)
+		}
+	}
+	for _, name := range []string{
+		"NewRdr",
+		"Reader",
+		"NewReaderSize",
+		"XNewReaderX",
+		"NewRaedre",
+	} {
+		if re.MatchString(name) {
+			t.Errorf("%s: want no match", name)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"reader", "reader", 0},
+		{"reader", "", 6},
+		{"reader", "readers", 1},
+		{"reader", "rader", 1},
+		{"reader", "raeder", 1},
+		{"kitten", "sitting", 3},
+		{"ca", "abc", 3},
+	}
+	for _, c := range cases {
+		if got := editDistance(c.a, c.b); got != c.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestPatternIdentifiers(t *testing.T) {
+	cases := []struct {
+		query       string
+		patternType query.SearchType
+		want        []string
+	}{
+		{"NewRaeder", query.SearchTypeLiteral, []string{"NewRaeder"}},
+		{"io.NewRaeder(r)", query.SearchTypeLiteral, []string{"NewRaeder"}},
+		{"NewRaeder or bufio.Scaner", query.SearchTypeLiteral, []string{"NewRaeder", "bufio", "Scaner"}},
+		{"NewRaeder not Close", query.SearchTypeLiteral, []string{"NewRaeder"}},
+		{"repo:foo NewRaeder NewRaeder", query.SearchTypeLiteral, []string{"NewRaeder"}},
+		{"one two three four five", query.SearchTypeLiteral, []string{"three", "four", "five"}},
+		{"NewRaeder", query.SearchTypeRegex, []string{"NewRaeder"}},
+		{"New.*Raeder", query.SearchTypeRegex, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			q, err := query.ParseSearchType(c.query, c.patternType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(c.want, patternIdentifiers(q)); diff != "" {
+				t.Errorf("unexpected identifiers (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLiteralRepoPattern(t *testing.T) {
+	cases := []struct {
+		pattern string
+		want    string
+		wantOK  bool
+	}{
+		{"sourcegraf", "sourcegraf", true},
+		{"^github.com/sourcegraf/sourcegraph$", "github.com/sourcegraf/sourcegraph", true},
+		{`github\.com/sourcegraf`, "github.com/sourcegraf", true},
+		{"sourcegraph|zoekt", "", false},
+		{"source.*graph", "", false},
+		{"(", "", false},
+	}
+	for _, c := range cases {
+		got, ok := literalRepoPattern(c.pattern)
+		if got != c.want || ok != c.wantOK {
+			t.Errorf("literalRepoPattern(%q) = %q, %t, want %q, %t", c.pattern, got, ok, c.want, c.wantOK)
+		}
+	}
+}
+
+func TestAlertForNoResolvedRepos_similarRepos(t *testing.T) {
+	mockResolveRepositories = func() (searchrepos.Resolved, error) {
+		return searchrepos.Resolved{}, nil
+	}
+	database.Mocks.ExternalServices.Count = func(context.Context, database.ExternalServicesListOptions) (int, error) {
+		return 1, nil
+	}
+	database.Mocks.Repos.ListRepoNames = func(_ context.Context, opt database.ReposListOptions) ([]types.RepoName, error) {
+		if opt.SimilarTo != "github.com/sourcegraf/zoekt" {
+			t.Errorf("got SimilarTo %q, want github.com/sourcegraf/zoekt", opt.SimilarTo)
+		}
+		return []types.RepoName{{Name: "github.com/sourcegraph/zoekt"}}, nil
+	}
+	defer func() {
+		mockResolveRepositories = nil
+		database.Mocks = database.MockStores{}
+	}()
+
+	searchQuery := "repo:^github.com/sourcegraf/zoekt$@main fork:no archived:no foo"
+	q, err := query.ParseRegexp(searchQuery)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sr := searchResolver{
+		db: new(dbtesting.MockDB),
+		SearchInputs: &run.SearchInputs{
+			OriginalQuery: searchQuery,
+			Query:         q,
+			UserSettings:  &schema.Settings{},
+			PatternType:   query.SearchTypeRegex,
+		},
+	}
+
+	alert := sr.alertForNoResolvedRepos(context.Background(), q)
+	want := &searchAlert{
+		prometheusType: "no_resolved_repos__did_you_mean",
+		title:          "No repositories found",
+		description:    "No repositories matched your `repo:` filter, but there are repositories with similar names.",
+		proposedQueries: []*searchQueryDescription{{
+			description: "did you mean github.com/sourcegraph/zoekt?",
+			query:       `repo:^github\.com/sourcegraph/zoekt$@main fork:no archived:no foo`,
+			patternType: query.SearchTypeRegex,
+		}},
+	}
+	if diff := cmp.Diff(want, alert, cmp.AllowUnexported(searchAlert{}, searchQueryDescription{})); diff != "" {
+		t.Errorf("unexpected alert (-want +got):\n%s", diff)
+	}
+}
+
+func TestWithNoResultsAlert_skipped(t *testing.T) {
+	sr := searchResolver{SearchInputs: &run.SearchInputs{}}
+	cases := []struct {
+		name    string
+		results *SearchResults
+	}{
+		{"alert", &SearchResults{Alert: &searchAlert{title: "alert"}}},
+		{"limit hit", &SearchResults{Stats: streaming.Stats{IsLimitHit: true}}},
+		{"timed out", &SearchResults{Stats: streaming.Stats{Status: timedOutStatus()}}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			alert := c.results.Alert
+			if got := sr.withNoResultsAlert(context.Background(), c.results); got.Alert != alert {
+				t.Errorf("got alert %v, want %v", got.Alert, alert)
+			}
+		})
+	}
+}
+
+func TestSearchesNonFileTypes(t *testing.T) {
+	for in, want := range map[string]bool{
+		"NewRaeder":                 false,
+		"type:symbol NewRaeder":     false,
+		"type:file NewRaeder":       false,
+		"type:commit NewRaeder":     true,
+		"type:diff NewRaeder":       true,
+		"type:repo NewRaeder":       true,
+		"repo:foo type:diff Raeder": true,
+	} {
+		q, err := query.ParseLiteral(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := searchesNonFileTypes(q); got != want {
+			t.Errorf("searchesNonFileTypes(%q) = %t, want %t", in, got, want)
+		}
+	}
+}
+
+func timedOutStatus() search.RepoStatusMap {
+	var m search.RepoStatusMap
+	m.Update(1, search.RepoStatusTimedout)
+	return m
+}
@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/google/go-cmp/cmp"
@@ -190,6 +191,48 @@ func TestAlertForDiffCommitSearchLimits(t *testing.T) {
 	}
 }
 
+func TestAlertForReposWithoutCommitAtTime(t *testing.T) {
+	date := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name                 string
+		err                  error
+		wantAlertDescription string
+	}{
+		{
+			name: "one_repo",
+			err: &reposWithoutCommitAtTimeError{
+				Repos:  []types.RepoName{{Name: "github.com/a/a"}},
+				AtTime: &query.RevAtTimePredicate{Time: date},
+			},
+			wantAlertDescription: "The repository github.com/a/a could not be searched because the default branch has no commit on or before 2021-01-01T00:00:00Z.",
+		},
+		{
+			name: "many_repos_with_branch",
+			err: multierror.Append(&multierror.Error{}, &reposWithoutCommitAtTimeError{
+				Repos:  []types.RepoName{{Name: "github.com/a/a"}, {Name: "github.com/b/b"}},
+				AtTime: &query.RevAtTimePredicate{Time: date, Branch: "dev"},
+			}),
+			wantAlertDescription: "2 repositories could not be searched because branch dev has no commit on or before 2021-01-01T00:00:00Z:\n* github.com/a/a\n* github.com/b/b",
+		},
+	}
+
+	for _, test := range cases {
+		alert := alertForError(test.err)
+		if diff := cmp.Diff(test.wantAlertDescription, alert.description); diff != "" {
+			t.Fatalf("test %s, mismatched alert (-want, +got):\n%s", test.name, diff)
+		}
+	}
+}
+
+func TestAlertForQueryMacroError(t *testing.T) {
+	_, _, err := query.ExpandMacros("foo $bar", map[string]string{"baz": "repo:baz"})
+	alert := alertForQuery("foo $bar", err)
+	want := "Search macro $bar is not defined (at position 4 of the query). Search macros are defined in the `search.macros` setting."
+	if diff := cmp.Diff(want, alert.description); diff != "" {
+		t.Fatalf("mismatched alert (-want, +got):\n%s", diff)
+	}
+}
+
 func TestErrorToAlertStructuralSearch(t *testing.T) {
 	cases := []struct {
 		name           string
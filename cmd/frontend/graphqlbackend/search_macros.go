@@ -0,0 +1,46 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+)
+
+func (r *schemaResolver) ExpandSearchMacros(ctx context.Context, args *struct{ Query string }) (*searchMacroExpansionResolver, error) {
+	settings, err := decodedViewerFinalSettings(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+
+	expandedQuery, used, err := query.ExpandMacros(args.Query, settings.SearchMacros)
+	if err != nil {
+		return nil, err
+	}
+
+	macros := make([]*searchMacroResolver, 0, len(used))
+	for _, name := range used {
+		macros = append(macros, &searchMacroResolver{name: name, definition: settings.SearchMacros[name]})
+	}
+	return &searchMacroExpansionResolver{query: args.Query, expandedQuery: expandedQuery, macros: macros}, nil
+}
+
+type searchMacroExpansionResolver struct {
+	query         string
+	expandedQuery string
+	macros        []*searchMacroResolver
+}
+
+func (r *searchMacroExpansionResolver) Query() string { return r.query }
+
+func (r *searchMacroExpansionResolver) ExpandedQuery() string { return r.expandedQuery }
+
+func (r *searchMacroExpansionResolver) Macros() []*searchMacroResolver { return r.macros }
+
+type searchMacroResolver struct {
+	name       string
+	definition string
+}
+
+func (r *searchMacroResolver) Name() string { return r.name }
+
+func (r *searchMacroResolver) Definition() string { return r.definition }
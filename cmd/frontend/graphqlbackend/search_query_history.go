@@ -0,0 +1,297 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sync"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+func (r *UserResolver) SearchQueryHistory(ctx context.Context, args *struct {
+	graphqlutil.ConnectionArgs
+	Query *string
+}) (*searchQueryHistoryConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins and the user can list a user's search query history.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, r.db, r.user.ID); err != nil {
+		return nil, err
+	}
+
+	opt := database.SearchQueryHistoryListOptions{UserID: r.user.ID}
+	if args.Query != nil {
+		opt.Query = *args.Query
+	}
+	args.ConnectionArgs.Set(&opt.LimitOffset)
+	return &searchQueryHistoryConnectionResolver{db: r.db, opt: opt}, nil
+}
+
+// searchQueryHistoryConnectionResolver resolves a list of search query
+// history entries.
+//
+// 🚨 SECURITY: When instantiating a searchQueryHistoryConnectionResolver
+// value, the caller MUST check permissions.
+type searchQueryHistoryConnectionResolver struct {
+	db  dbutil.DB
+	opt database.SearchQueryHistoryListOptions
+
+	// cache results because they are used by multiple fields
+	once    sync.Once
+	entries []*database.SearchQueryHistoryEntry
+	err     error
+}
+
+func (r *searchQueryHistoryConnectionResolver) compute(ctx context.Context) ([]*database.SearchQueryHistoryEntry, error) {
+	r.once.Do(func() {
+		opt2 := r.opt
+		if opt2.LimitOffset != nil {
+			tmp := *opt2.LimitOffset
+			opt2.LimitOffset = &tmp
+			opt2.Limit++ // so we can detect if there is a next page
+		}
+
+		r.entries, r.err = database.SearchQueryHistory(r.db).List(ctx, opt2)
+	})
+	return r.entries, r.err
+}
+
+func (r *searchQueryHistoryConnectionResolver) Nodes(ctx context.Context) ([]*searchQueryHistoryEntryResolver, error) {
+	entries, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.opt.LimitOffset != nil && len(entries) > r.opt.LimitOffset.Limit {
+		entries = entries[:r.opt.LimitOffset.Limit]
+	}
+
+	l := make([]*searchQueryHistoryEntryResolver, 0, len(entries))
+	for _, entry := range entries {
+		l = append(l, &searchQueryHistoryEntryResolver{entry: entry})
+	}
+	return l, nil
+}
+
+func (r *searchQueryHistoryConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := database.SearchQueryHistory(r.db).Count(ctx, r.opt)
+	return int32(count), err
+}
+
+func (r *searchQueryHistoryConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	entries, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graphqlutil.HasNextPage(r.opt.LimitOffset != nil && len(entries) > r.opt.Limit), nil
+}
+
+type searchQueryHistoryEntryResolver struct {
+	entry *database.SearchQueryHistoryEntry
+}
+
+func marshalSearchQueryHistoryEntryID(id int64) graphql.ID {
+	return relay.MarshalID("SearchQueryHistoryEntry", id)
+}
+
+func unmarshalSearchQueryHistoryEntryID(id graphql.ID) (entryID int64, err error) {
+	err = relay.UnmarshalSpec(id, &entryID)
+	return
+}
+
+func (r *searchQueryHistoryEntryResolver) ID() graphql.ID {
+	return marshalSearchQueryHistoryEntryID(r.entry.ID)
+}
+
+func (r *searchQueryHistoryEntryResolver) Query() string { return r.entry.Query }
+
+func (r *searchQueryHistoryEntryResolver) PatternType() string {
+	return searchPatternTypeFromHistory(r.entry.PatternType)
+}
+
+func (r *searchQueryHistoryEntryResolver) Status() string { return r.entry.Status }
+
+func (r *searchQueryHistoryEntryResolver) AlertTitle() *string {
+	if r.entry.AlertTitle == "" {
+		return nil
+	}
+	return &r.entry.AlertTitle
+}
+
+func (r *searchQueryHistoryEntryResolver) ResultCount() int32 { return r.entry.ResultCount }
+
+func (r *searchQueryHistoryEntryResolver) DurationMilliseconds() int32 { return r.entry.DurationMs }
+
+func (r *searchQueryHistoryEntryResolver) CreatedAt() DateTime {
+	return DateTime{Time: r.entry.CreatedAt}
+}
+
+// searchPatternTypeFromHistory converts the pattern type recorded in the
+// search query history to its SearchPatternType GraphQL enum value.
+func searchPatternTypeFromHistory(patternType string) string {
+	if patternType == "regex" {
+		return "regexp"
+	}
+	return patternType
+}
+
+type deleteSearchQueryHistoryArgs struct {
+	User    graphql.ID
+	Entries *[]graphql.ID
+}
+
+func (r *schemaResolver) DeleteSearchQueryHistory(ctx context.Context, args *deleteSearchQueryHistoryArgs) (*EmptyResponse, error) {
+	userID, err := UnmarshalUserID(args.User)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Only site admins and the user can delete a user's search query history.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, r.db, userID); err != nil {
+		return nil, err
+	}
+
+	store := database.SearchQueryHistory(r.db)
+	if args.Entries == nil {
+		if err := store.DeleteAll(ctx, userID); err != nil {
+			return nil, err
+		}
+		return &EmptyResponse{}, nil
+	}
+
+	ids := make([]int64, 0, len(*args.Entries))
+	for _, id := range *args.Entries {
+		entryID, err := unmarshalSearchQueryHistoryEntryID(id)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, entryID)
+	}
+	if err := store.Delete(ctx, userID, ids); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+func (r *siteResolver) SearchQueryStatistics(ctx context.Context, args *struct {
+	graphqlutil.ConnectionArgs
+	OrderBy string
+	Since   *DateTime
+}) (*searchQueryStatisticConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins can see the searches run by all users.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	opt := database.SearchQueryStatisticsOptions{
+		OrderBy: database.SearchQueryStatisticsOrder(args.OrderBy),
+	}
+	if args.Since != nil {
+		opt.Since = args.Since.Time
+	}
+	args.ConnectionArgs.Set(&opt.LimitOffset)
+	return &searchQueryStatisticConnectionResolver{db: r.db, opt: opt}, nil
+}
+
+// searchQueryStatisticConnectionResolver resolves a list of search query
+// statistics.
+//
+// 🚨 SECURITY: When instantiating a searchQueryStatisticConnectionResolver
+// value, the caller MUST check permissions.
+type searchQueryStatisticConnectionResolver struct {
+	db  dbutil.DB
+	opt database.SearchQueryStatisticsOptions
+
+	// cache results because they are used by multiple fields
+	once  sync.Once
+	stats []*database.SearchQueryStatistic
+	err   error
+}
+
+func (r *searchQueryStatisticConnectionResolver) compute(ctx context.Context) ([]*database.SearchQueryStatistic, error) {
+	r.once.Do(func() {
+		opt2 := r.opt
+		if opt2.LimitOffset != nil {
+			tmp := *opt2.LimitOffset
+			opt2.LimitOffset = &tmp
+			opt2.Limit++ // so we can detect if there is a next page
+		}
+
+		r.stats, r.err = database.SearchQueryHistory(r.db).Statistics(ctx, opt2)
+	})
+	return r.stats, r.err
+}
+
+func (r *searchQueryStatisticConnectionResolver) Nodes(ctx context.Context) ([]*searchQueryStatisticResolver, error) {
+	stats, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.opt.LimitOffset != nil && len(stats) > r.opt.LimitOffset.Limit {
+		stats = stats[:r.opt.LimitOffset.Limit]
+	}
+
+	l := make([]*searchQueryStatisticResolver, 0, len(stats))
+	for _, st := range stats {
+		l = append(l, &searchQueryStatisticResolver{stat: st})
+	}
+	return l, nil
+}
+
+func (r *searchQueryStatisticConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := database.SearchQueryHistory(r.db).CountStatistics(ctx, r.opt)
+	return int32(count), err
+}
+
+func (r *searchQueryStatisticConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	stats, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graphqlutil.HasNextPage(r.opt.LimitOffset != nil && len(stats) > r.opt.Limit), nil
+}
+
+type searchQueryStatisticResolver struct {
+	stat *database.SearchQueryStatistic
+}
+
+func (r *searchQueryStatisticResolver) Query() string { return r.stat.Query }
+
+func (r *searchQueryStatisticResolver) PatternType() string {
+	return searchPatternTypeFromHistory(r.stat.PatternType)
+}
+
+func (r *searchQueryStatisticResolver) Count() int32 { return r.stat.Count }
+
+func (r *searchQueryStatisticResolver) AverageDurationMilliseconds() int32 {
+	return r.stat.AverageDurationMs
+}
+
+func (r *searchQueryStatisticResolver) MaxDurationMilliseconds() int32 { return r.stat.MaxDurationMs }
+
+func (r *searchQueryStatisticResolver) TimeoutCount() int32 { return r.stat.TimeoutCount }
+
+func (r *searchQueryStatisticResolver) ErrorCount() int32 { return r.stat.ErrorCount }
+
+func (r *searchQueryStatisticResolver) AlertCount() int32 { return r.stat.AlertCount }
+
+func (r *searchQueryStatisticResolver) LatestAlert() *searchQueryStatisticAlertResolver {
+	if r.stat.LatestAlertTitle == "" {
+		return nil
+	}
+	return &searchQueryStatisticAlertResolver{typ: r.stat.LatestAlertType, title: r.stat.LatestAlertTitle}
+}
+
+func (r *searchQueryStatisticResolver) LastRunAt() DateTime {
+	return DateTime{Time: r.stat.LastRunAt}
+}
+
+type searchQueryStatisticAlertResolver struct {
+	typ   string
+	title string
+}
+
+func (r *searchQueryStatisticAlertResolver) Type() string  { return r.typ }
+func (r *searchQueryStatisticAlertResolver) Title() string { return r.title }
@@ -21,6 +21,7 @@ import (
 	otlog "github.com/opentracing/opentracing-go/log"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/atomic"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
 	searchlogs "github.com/sourcegraph/sourcegraph/cmd/frontend/internal/search/logs"
@@ -110,6 +111,10 @@ type SearchResultsResolver struct {
 	// cache for user settings. Ideally this should be set just once in the code path
 	// by an upstream resolver
 	UserSettings *schema.Settings
+
+	// q is the query that produced the results. It determines the
+	// aggregations.
+	q query.Q
 }
 
 type SearchResults struct {
@@ -228,6 +233,37 @@ func (sr *SearchResultsResolver) DynamicFilters(ctx context.Context) []*searchFi
 	return resolvers
 }
 
+func (sr *SearchResultsResolver) Aggregations() *[]*searchAggregationGroupResolver {
+	aggregation := streaming.NewSearchAggregation(sr.q)
+	if aggregation == nil {
+		return nil
+	}
+
+	aggregation.Update(streaming.SearchEvent{
+		Results: sr.Matches,
+		Stats:   sr.Stats,
+	})
+
+	groups := aggregation.Compute()
+	resolvers := make([]*searchAggregationGroupResolver, 0, len(groups))
+	for _, g := range groups {
+		resolvers = append(resolvers, &searchAggregationGroupResolver{group: g})
+	}
+	return &resolvers
+}
+
+type searchAggregationGroupResolver struct {
+	group streaming.Aggregation
+}
+
+func (g *searchAggregationGroupResolver) Label() string {
+	return g.group.Label
+}
+
+func (g *searchAggregationGroupResolver) Count() int32 {
+	return int32(g.group.Count)
+}
+
 type searchFilterResolver struct {
 	filter streaming.Filter
 }
@@ -524,6 +560,7 @@ func (r *searchResolver) toRepoOptions(q query.Q, opts resolveRepositoriesOpts)
 		OnlyPrivate:        visibility == query.Private,
 		OnlyPublic:         visibility == query.Public,
 		CommitAfter:        commitAfter,
+		RevAtTime:          q.RevAtTime(),
 		Query:              q,
 		Ranked:             true,
 		Limit:              opts.limit,
@@ -539,6 +576,14 @@ func withMode(args search.TextParameters, st query.SearchType, versionContext *s
 		if versionContext != nil && *versionContext != "" {
 			return false
 		}
+		if args.Query.RevAtTime() != nil {
+			// Indexed search only contains the latest revision.
+			return false
+		}
+		if args.PatternInfo.FileFilters.NeedContent() {
+			// Indexed search doesn't support size: and lines:.
+			return false
+		}
 		querySearchContextSpec, _ := args.Query.StringValue(query.FieldContext)
 		if !searchcontexts.IsGlobalSearchContextSpec(querySearchContextSpec) {
 			return false
@@ -934,6 +979,9 @@ func (r *searchResolver) resultsStreaming(ctx context.Context) (*SearchResultsRe
 		endpoint := r.stream
 		r.stream = nil // Disables streaming: backends may not use the endpoint.
 		srr, err := r.resultsBatch(ctx)
+		if err == nil {
+			srr.SearchResults = r.withNoResultsAlert(ctx, srr.SearchResults)
+		}
 		if srr != nil {
 			endpoint.Send(streaming.SearchEvent{
 				Results: srr.Matches,
@@ -942,12 +990,19 @@ func (r *searchResolver) resultsStreaming(ctx context.Context) (*SearchResultsRe
 		}
 		return srr, err
 	}
+	// Results are sent on the stream rather than returned, so record whether
+	// there were any after select: applied.
+	var seen atomic.Bool
+	r.stream = streamWithResultsSeen(r.stream, &seen)
 	if sp, _ := r.Plan.ToParseTree().StringValue(query.FieldSelect); sp != "" {
 		// Ensure downstream events sent on the stream are processed by `select:`.
 		selectPath, _ := filter.SelectPathFromString(sp) // Invariant: error already checked
 		r.stream = streaming.WithSelect(r.stream, selectPath)
 	}
 	sr, err := r.resultsRecursive(ctx, r.Plan)
+	if err == nil && !seen.Load() {
+		sr = r.withNoResultsAlert(ctx, sr)
+	}
 	srr := r.resultsToResolver(sr)
 	return srr, err
 }
@@ -961,6 +1016,7 @@ func (r *searchResolver) resultsToResolver(results *SearchResults) *SearchResult
 		limit:         r.MaxResults(),
 		db:            r.db,
 		UserSettings:  r.UserSettings,
+		q:             r.Query,
 	}
 }
 
@@ -1033,7 +1089,12 @@ func (r *searchResolver) resultsRecursive(ctx context.Context, plan query.Plan)
 			return r.resultsRecursive(ctx, predicatePlan)
 		}
 
-		newResult, err := r.evaluate(ctx, q)
+		var newResult *SearchResults
+		if q.IsDebug() {
+			newResult, err = r.evaluateDebug(ctx, q)
+		} else {
+			newResult, err = r.evaluate(ctx, q)
+		}
 		if err != nil {
 			// Fail if any subexpression fails.
 			return nil, err
@@ -1055,6 +1116,25 @@ func (r *searchResolver) resultsRecursive(ctx context.Context, plan query.Plan)
 	return sr, err
 }
 
+// evaluateDebug evaluates a query with debug:yes. It records q in the debug
+// information of the file matches, so that matches of a query containing or,
+// which is evaluated as several queries, tell which of them found them.
+func (r *searchResolver) evaluateDebug(ctx context.Context, q query.Basic) (*SearchResults, error) {
+	debugQuery := q.String()
+	if r.stream == nil {
+		sr, err := r.evaluate(ctx, q)
+		if sr != nil {
+			result.WithDebugQuery(sr.Matches, debugQuery)
+		}
+		return sr, err
+	}
+
+	orig := r.stream
+	r.stream = streaming.WithDebugQuery(orig, debugQuery)
+	defer func() { r.stream = orig }()
+	return r.evaluate(ctx, q)
+}
+
 // searchResultsToRepoNodes converts a set of search results into repository nodes
 // such that they can be used to replace a repository predicate
 func searchResultsToRepoNodes(matches []result.Match) ([]query.Node, error) {
@@ -1146,6 +1226,12 @@ func substitutePredicates(q query.Basic, evaluate func(query.Predicate) (*Search
 			return orig
 		}
 
+		if field == query.FieldRev {
+			// rev: predicates are resolved for each repository when
+			// resolving repositories, rather than expanded here.
+			return orig
+		}
+
 		if topErr != nil {
 			return orig
 		}
@@ -1464,24 +1550,37 @@ func (r *searchResolver) doResults(ctx context.Context, args *search.TextParamet
 	}
 
 	tr.LazyPrintf("searching %d repos, %d missing", len(resolved.RepoRevs), len(resolved.MissingRepoRevs))
-	if len(resolved.RepoRevs) == 0 {
+	if len(resolved.RepoRevs) == 0 && len(resolved.Unsearched) == 0 {
+		if len(resolved.ReposWithoutCommitAtTime) > 0 {
+			return alertForReposWithoutCommitAtTime(resolved.ReposWithoutCommitAtTime, args.RepoOptions.RevAtTime).wrapResults(), nil
+		}
 		return r.alertForNoResolvedRepos(ctx, args.Query).wrapResults(), nil
 	}
 
 	if len(resolved.MissingRepoRevs) > 0 {
 		agg.Error(&missingRepoRevsError{Missing: resolved.MissingRepoRevs})
 	}
+	if len(resolved.ReposWithoutCommitAtTime) > 0 {
+		agg.Error(&reposWithoutCommitAtTimeError{Repos: resolved.ReposWithoutCommitAtTime, AtTime: args.RepoOptions.RevAtTime})
+	}
 
 	// Send down our first bit of progress.
 	{
-		repos := make(map[api.RepoID]types.RepoName, len(resolved.RepoRevs))
+		repos := make(map[api.RepoID]types.RepoName, len(resolved.RepoRevs)+len(resolved.Unsearched))
 		for _, repoRev := range resolved.RepoRevs {
 			repos[repoRev.Repo.ID] = repoRev.Repo
 		}
 
+		var status search.RepoStatusMap
+		for repo, repoStatus := range resolved.Unsearched {
+			repos[repo.ID] = repo
+			status.Update(repo.ID, repoStatus)
+		}
+
 		agg.Send(streaming.SearchEvent{
 			Stats: streaming.Stats{
 				Repos:            repos,
+				Status:           status,
 				ExcludedForks:    resolved.ExcludedRepos.Forks,
 				ExcludedArchived: resolved.ExcludedRepos.Archived,
 			},
@@ -1681,11 +1780,12 @@ func (r *searchResolver) sortResults(results []result.Match) {
 // getExactFilePatterns returns the set of file patterns without glob syntax.
 func (r *searchResolver) getExactFilePatterns() map[string]struct{} {
 	m := map[string]struct{}{}
+	parsedQuery := r.ParsedQuery()
 	query.VisitField(
 		r.Query,
 		query.FieldFile,
 		func(value string, negated bool, annotation query.Annotation) {
-			originalValue := r.OriginalQuery[annotation.Range.Start.Column+len(query.FieldFile)+1 : annotation.Range.End.Column]
+			originalValue := parsedQuery[annotation.Range.Start.Column+len(query.FieldFile)+1 : annotation.Range.End.Column]
 			if !negated && query.ContainsNoGlobSyntax(originalValue) {
 				m[originalValue] = struct{}{}
 			}
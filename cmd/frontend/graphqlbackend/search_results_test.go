@@ -473,6 +473,45 @@ func TestSearchResolver_DynamicFilters(t *testing.T) {
 	}
 }
 
+func TestSearchResultsResolver_Aggregations(t *testing.T) {
+	repoMatch := func(name string) *result.RepoMatch {
+		return &result.RepoMatch{Name: api.RepoName(name)}
+	}
+	results := &SearchResults{
+		Matches: []result.Match{repoMatch("a"), repoMatch("b"), repoMatch("b")},
+	}
+
+	t.Run("no aggregate parameter", func(t *testing.T) {
+		q, err := query.ParseLiteral("foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		sr := &SearchResultsResolver{SearchResults: results, q: q}
+		if got := sr.Aggregations(); got != nil {
+			t.Fatalf("got %v, want nil", *got)
+		}
+	})
+
+	t.Run("aggregate:repo", func(t *testing.T) {
+		q, err := query.ParseLiteral("foo aggregate:repo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		sr := &SearchResultsResolver{SearchResults: results, q: q}
+
+		// Resolving the field twice must not count results twice.
+		for i := 0; i < 2; i++ {
+			var got []string
+			for _, g := range *sr.Aggregations() {
+				got = append(got, fmt.Sprintf("%s %d", g.Label(), g.Count()))
+			}
+			if diff := cmp.Diff([]string{"b 2", "a 1"}, got); diff != "" {
+				t.Fatalf("unexpected aggregations (-want +got):\n%s", diff)
+			}
+		}
+	})
+}
+
 func TestLonger(t *testing.T) {
 	N := 2
 	noise := time.Nanosecond
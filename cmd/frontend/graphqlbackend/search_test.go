@@ -267,6 +267,29 @@ func testStringResult(result SearchSuggestionResolver) string {
 	return name
 }
 
+func TestNewSearchImplementer_debug(t *testing.T) {
+	conf.Mock(&conf.Unified{})
+	defer conf.Mock(nil)
+	defer func() { database.Mocks = database.MockStores{} }()
+
+	db := new(dbtesting.MockDB)
+	args := &SearchArgs{Query: "debug:yes foo", Version: "V2", Settings: &schema.Settings{}}
+
+	database.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{ID: 1}, nil
+	}
+	if _, err := NewSearchImplementer(context.Background(), db, args); err == nil {
+		t.Fatal("expected error for non-admin user")
+	}
+
+	database.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{ID: 1, SiteAdmin: true}, nil
+	}
+	if _, err := NewSearchImplementer(context.Background(), db, args); err != nil {
+		t.Fatalf("unexpected error for site admin: %s", err)
+	}
+}
+
 func TestDetectSearchType(t *testing.T) {
 	typeRegexp := "regexp"
 	typeLiteral := "literal"
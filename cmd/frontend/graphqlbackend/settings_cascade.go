@@ -141,6 +141,7 @@ var deeplyMergedSettingsFields = map[string]int{
 	"search.scopes":           1,
 	"search.savedQueries":     1,
 	"search.repositoryGroups": 1,
+	"search.macros":           1,
 	"insights.dashboards":     1,
 	"insights.allrepos":       1,
 	"quicklinks":              1,
@@ -162,21 +162,31 @@ func (r *schemaResolver) UpdateSiteConfiguration(ctx context.Context, args *stru
 	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
 		return false, err
 	}
+	return writeSiteConfiguration(ctx, args.Input)
+}
+
+// writeSiteConfiguration validates the given site configuration and saves it as the
+// current site configuration. It returns whether or not a restart is required for the
+// update to be applied.
+//
+// 🚨 SECURITY: This function does NOT verify the user is an admin. The caller is
+// responsible for ensuring this.
+func writeSiteConfiguration(ctx context.Context, input string) (bool, error) {
 	if !canUpdateSiteConfiguration() {
 		return false, errors.New("updating site configuration not allowed when using SITE_CONFIG_FILE")
 	}
-	if strings.TrimSpace(args.Input) == "" {
+	if strings.TrimSpace(input) == "" {
 		return false, errors.Errorf("blank site configuration is invalid (you can clear the site configuration by entering an empty JSON object: {})")
 	}
 
-	if problems, err := conf.ValidateSite(args.Input); err != nil {
+	if problems, err := conf.ValidateSite(input); err != nil {
 		return false, errors.Errorf("failed to validate site configuration: %w", err)
 	} else if len(problems) > 0 {
 		return false, errors.Errorf("site configuration is invalid: %s", strings.Join(problems, ","))
 	}
 
 	prev := globals.ConfigurationServerFrontendOnly.Raw()
-	prev.Site = args.Input
+	prev.Site = input
 	// TODO(slimsag): future: actually pass lastID through to prevent race conditions
 	if err := globals.ConfigurationServerFrontendOnly.Write(ctx, prev); err != nil {
 		return false, err
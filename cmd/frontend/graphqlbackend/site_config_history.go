@@ -0,0 +1,180 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/confdb"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+func marshalSiteConfigurationChangeID(id int32) graphql.ID {
+	return relay.MarshalID("SiteConfigurationChange", id)
+}
+
+func unmarshalSiteConfigurationChangeID(id graphql.ID) (changeID int32, err error) {
+	err = relay.UnmarshalSpec(id, &changeID)
+	return
+}
+
+type siteConfigurationHistoryArgs struct {
+	graphqlutil.ConnectionArgs
+	After *string
+}
+
+func (r *siteConfigurationResolver) History(ctx context.Context, args *siteConfigurationHistoryArgs) (*siteConfigurationChangeConnectionResolver, error) {
+	// 🚨 SECURITY: The site configuration contains secret tokens and credentials,
+	// so only admins may view its history.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	opt := confdb.SiteListOptions{}
+	if args.First != nil {
+		// Fetch one revision more than requested: it tells us whether there is a
+		// next page and serves as the base of the diff of the last revision.
+		opt.Limit = int(*args.First) + 1
+	}
+	if args.After != nil {
+		beforeID, err := unmarshalSiteConfigurationChangeID(graphql.ID(*args.After))
+		if err != nil {
+			return nil, err
+		}
+		opt.BeforeID = beforeID
+	}
+
+	return &siteConfigurationChangeConnectionResolver{db: r.db, opt: opt}, nil
+}
+
+type siteConfigurationChangeConnectionResolver struct {
+	db  dbutil.DB
+	opt confdb.SiteListOptions
+
+	// cache results because they are used by multiple fields
+	once      sync.Once
+	revisions []*confdb.SiteConfig
+	err       error
+}
+
+func (r *siteConfigurationChangeConnectionResolver) compute(ctx context.Context) ([]*confdb.SiteConfig, error) {
+	r.once.Do(func() {
+		r.revisions, r.err = confdb.SiteListRevisions(ctx, r.opt)
+	})
+	return r.revisions, r.err
+}
+
+// hasNextPage returns true if more revisions exist than were requested.
+func (r *siteConfigurationChangeConnectionResolver) hasNextPage(revisions []*confdb.SiteConfig) bool {
+	return r.opt.Limit > 0 && len(revisions) == r.opt.Limit
+}
+
+func (r *siteConfigurationChangeConnectionResolver) Nodes(ctx context.Context) ([]*siteConfigurationChangeResolver, error) {
+	revisions, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(revisions)
+	if r.hasNextPage(revisions) {
+		n--
+	}
+
+	resolvers := make([]*siteConfigurationChangeResolver, 0, n)
+	for i := 0; i < n; i++ {
+		// Revisions are ordered newest first, so the previous revision is the next one in the list.
+		var previous *confdb.SiteConfig
+		if i+1 < len(revisions) {
+			previous = revisions[i+1]
+		}
+		resolvers = append(resolvers, &siteConfigurationChangeResolver{db: r.db, revision: revisions[i], previous: previous})
+	}
+	return resolvers, nil
+}
+
+func (r *siteConfigurationChangeConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := confdb.SiteCountRevisions(ctx)
+	return int32(count), err
+}
+
+func (r *siteConfigurationChangeConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	revisions, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !r.hasNextPage(revisions) {
+		return graphqlutil.HasNextPage(false), nil
+	}
+
+	endCursorID := revisions[len(revisions)-2].ID
+	return graphqlutil.NextPageCursor(string(marshalSiteConfigurationChangeID(endCursorID))), nil
+}
+
+// siteConfigurationChangeResolver implements the GraphQL type SiteConfigurationChange.
+type siteConfigurationChangeResolver struct {
+	db       dbutil.DB
+	revision *confdb.SiteConfig
+	previous *confdb.SiteConfig // nil if this is the first revision
+}
+
+func (r *siteConfigurationChangeResolver) ID() graphql.ID {
+	return marshalSiteConfigurationChangeID(r.revision.ID)
+}
+
+func (r *siteConfigurationChangeResolver) Author(ctx context.Context) (*UserResolver, error) {
+	if r.revision.AuthorUserID == nil {
+		return nil, nil
+	}
+
+	user, err := UserByIDInt32(ctx, r.db, *r.revision.AuthorUserID)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return user, err
+}
+
+func (r *siteConfigurationChangeResolver) CreatedAt() DateTime {
+	return DateTime{Time: r.revision.CreatedAt}
+}
+
+func (r *siteConfigurationChangeResolver) Diff() (string, error) {
+	var previousContents string
+	if r.previous != nil {
+		previousContents = r.previous.Contents
+	}
+
+	// 🚨 SECURITY: The diff must never include secrets from either revision.
+	return conf.RedactedDiff(previousContents, r.revision.Contents)
+}
+
+func (r *schemaResolver) RevertSiteConfiguration(ctx context.Context, args *struct{ ID graphql.ID }) (bool, error) {
+	// 🚨 SECURITY: The site configuration contains secret tokens and credentials,
+	// so only admins may modify it.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return false, err
+	}
+
+	changeID, err := unmarshalSiteConfigurationChangeID(args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	revision, err := confdb.SiteGetByID(ctx, changeID)
+	if err != nil {
+		return false, err
+	}
+	if revision == nil {
+		return false, errors.Errorf("site configuration change not found: %d", changeID)
+	}
+
+	// The schema may have changed since the revision was saved, so the contents are
+	// validated again before they become the current site configuration.
+	return writeSiteConfiguration(ctx, revision.Contents)
+}
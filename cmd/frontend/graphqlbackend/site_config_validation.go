@@ -0,0 +1,51 @@
+package graphqlbackend
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+)
+
+func (r *schemaResolver) ValidateSiteConfiguration(ctx context.Context, args *struct{ Input string }) ([]*siteConfigurationProblemResolver, error) {
+	// 🚨 SECURITY: Problem descriptions may reveal parts of the current site configuration,
+	// so only admins may validate it.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(args.Input) == "" {
+		return nil, errors.New("blank site configuration is invalid (you can clear the site configuration by entering an empty JSON object: {})")
+	}
+
+	raw := conf.Raw()
+	raw.Site = args.Input
+	problems, err := conf.Validate(raw)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to validate site configuration")
+	}
+
+	resolvers := make([]*siteConfigurationProblemResolver, 0, len(problems))
+	for _, p := range problems.Site() {
+		resolvers = append(resolvers, &siteConfigurationProblemResolver{problem: p})
+	}
+	return resolvers, nil
+}
+
+// siteConfigurationProblemResolver implements the GraphQL type SiteConfigurationProblem.
+type siteConfigurationProblemResolver struct {
+	problem *conf.Problem
+}
+
+func (r *siteConfigurationProblemResolver) Message() string {
+	return r.problem.String()
+}
+
+func (r *siteConfigurationProblemResolver) Location() *string {
+	if location := r.problem.Location(); location != "" {
+		return &location
+	}
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/app/router"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/app/ui"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/session"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 )
@@ -88,5 +89,20 @@ func NewHandler(db dbutil.DB) http.Handler {
 		m.Handle(p, rickRoll)
 	}
 
-	return m
+	return accessTokenScopesMiddleware(m)
+}
+
+// accessTokenScopesMiddleware rejects requests from actors that were authenticated with an
+// access token that only has fine-grained scopes, unless they fetch raw file contents.
+//
+// 🚨 SECURITY: serveRaw checks that the code:read scope of the access token permits the
+// requested repository.
+func accessTokenScopesMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if actor.FromContext(r.Context()).Scopes != nil && !ui.IsRawRoute(r) {
+			http.Error(w, "The scopes of the access token do not permit this request.", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
 }
@@ -21,6 +21,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/vfsutil"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
@@ -84,6 +85,13 @@ func serveRaw(w http.ResponseWriter, r *http.Request) (err error) {
 		break
 	}
 
+	// 🚨 SECURITY: Access tokens whose code:read scope is restricted to some repositories
+	// can't fetch the contents of other repositories.
+	if err := authz.CheckRepoScope(r.Context(), authz.ScopeCodeRead, common.Repo.Name); err != nil {
+		serveError(w, r, err, http.StatusForbidden)
+		return nil
+	}
+
 	requestedPath := mux.Vars(r)["Path"]
 	if !strings.HasPrefix(requestedPath, "/") {
 		requestedPath = "/" + requestedPath
@@ -114,6 +114,15 @@ func Router() *mux.Router {
 	return uirouter.Router
 }
 
+// IsRawRoute reports whether r is a request for raw file contents.
+func IsRawRoute(r *http.Request) bool {
+	if uirouter.Router == nil {
+		return false
+	}
+	var match mux.RouteMatch
+	return uirouter.Router.Match(r, &match) && match.Route != nil && match.Route.GetName() == routeRaw
+}
+
 // InitRouter create the router that serves pages for our web app
 // and assigns it to uirouter.Router.
 // The router can be accessed by calling Router().
@@ -38,3 +38,18 @@ func DeleteOldSecurityEventLogsInPostgres(ctx context.Context, db dbutil.DB) {
 		time.Sleep(time.Hour)
 	}
 }
+
+func DeleteOldSearchQueryHistoryInPostgres(ctx context.Context, db dbutil.DB) {
+	for {
+		// We keep the same three months of search query history as we keep
+		// of event logs.
+		_, err := db.ExecContext(
+			ctx,
+			`DELETE FROM search_query_history WHERE created_at < now() - interval '93' day`,
+		)
+		if err != nil {
+			log15.Error("deleting expired rows from search_query_history table", "error", err)
+		}
+		time.Sleep(time.Hour)
+	}
+}
@@ -31,6 +31,9 @@ func ReposourceCloneURLToRepoName(ctx context.Context, db dbutil.DB, cloneURL st
 			extsvc.KindGitHub,
 			extsvc.KindGitLab,
 			extsvc.KindBitbucketServer,
+			extsvc.KindGitea,
+			extsvc.KindGerrit,
+			extsvc.KindAzureDevOps,
 			extsvc.KindAWSCodeCommit,
 			extsvc.KindGitolite,
 			extsvc.KindPhabricator,
@@ -68,6 +71,15 @@ func ReposourceCloneURLToRepoName(ctx context.Context, db dbutil.DB, cloneURL st
 			case *schema.BitbucketServerConnection:
 				rs = reposource.BitbucketServer{BitbucketServerConnection: c}
 				host = c.Url
+			case *schema.GiteaConnection:
+				rs = reposource.Gitea{GiteaConnection: c}
+				host = c.Url
+			case *schema.GerritConnection:
+				rs = reposource.Gerrit{GerritConnection: c}
+				host = c.Url
+			case *schema.AzureDevOpsConnection:
+				rs = reposource.AzureDevOps{AzureDevOpsConnection: c}
+				host = c.Url
 			case *schema.AWSCodeCommitConnection:
 				rs = reposource.AWS{AWSCodeCommitConnection: c}
 				// AWS type does not have URL
@@ -10,16 +10,18 @@ import (
 	"github.com/keegancsmith/sqlf"
 	"github.com/sourcegraph/jsonx"
 
+	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/conf/confdefaults"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbconn"
 )
 
 // SiteConfig contains the contents of a site config along with associated metadata.
 type SiteConfig struct {
-	ID        int32     // the unique ID of this config
-	Contents  string    // the raw JSON content (with comments and trailing commas allowed)
-	CreatedAt time.Time // the date when this config was created
-	UpdatedAt time.Time // the date when this config was updated
+	ID           int32     // the unique ID of this config
+	Contents     string    // the raw JSON content (with comments and trailing commas allowed)
+	CreatedAt    time.Time // the date when this config was created
+	UpdatedAt    time.Time // the date when this config was updated
+	AuthorUserID *int32    // the user who saved this config, or nil if it was written by the system
 }
 
 // ErrNewerEdit is returned by SiteCreateIfUpToDate when a newer edit has already been applied and
@@ -30,7 +32,8 @@ var ErrNewerEdit = errors.New("someone else has already applied a newer edit")
 // supplied "lastID" is equal to the one that was most recently saved to the database.
 //
 // The site config that was most recently saved to the database is returned.
-// An error is returned if "contents" is invalid JSON.
+// An error is returned if "contents" is invalid JSON. The authenticated user
+// of the context, if any, is recorded as the author of the new config.
 //
 // 🚨 SECURITY: This method does NOT verify the user is an admin. The caller is
 // responsible for ensuring this or that the response never makes it to a user.
@@ -72,6 +75,66 @@ func SiteGetLatest(ctx context.Context) (latest *SiteConfig, err error) {
 	return getLatest(ctx, tx)
 }
 
+// SiteListOptions specifies the options for listing site config revisions.
+type SiteListOptions struct {
+	// Limit is the maximum number of revisions to return. Zero means no limit.
+	Limit int
+
+	// BeforeID, if non-zero, only returns revisions older than the revision with this ID.
+	BeforeID int32
+}
+
+// SiteListRevisions returns the site configs saved to the database, newest first.
+//
+// 🚨 SECURITY: This method does NOT verify the user is an admin. The caller is
+// responsible for ensuring this or that the response never makes it to a user.
+func SiteListRevisions(ctx context.Context, opt SiteListOptions) ([]*SiteConfig, error) {
+	conds := []*sqlf.Query{sqlf.Sprintf("s.type = %s", "site")}
+	if opt.BeforeID != 0 {
+		conds = append(conds, sqlf.Sprintf("s.id < %s", opt.BeforeID))
+	}
+
+	limit := sqlf.Sprintf("")
+	if opt.Limit > 0 {
+		limit = sqlf.Sprintf("LIMIT %s", opt.Limit)
+	}
+
+	q := sqlf.Sprintf(
+		"SELECT "+siteConfigColumns+" FROM critical_and_site_config s WHERE %s ORDER BY s.id DESC %s",
+		sqlf.Join(conds, "AND"),
+		limit,
+	)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	return parseQueryRows(ctx, rows)
+}
+
+// SiteCountRevisions returns the number of site configs saved to the database.
+func SiteCountRevisions(ctx context.Context) (count int, err error) {
+	err = dbconn.Global.QueryRowContext(ctx, "SELECT COUNT(*) FROM critical_and_site_config WHERE type = $1", "site").Scan(&count)
+	return count, err
+}
+
+// SiteGetByID returns the site config with the given ID. This returns nil, nil if
+// there is no site config with that ID.
+//
+// 🚨 SECURITY: This method does NOT verify the user is an admin. The caller is
+// responsible for ensuring this or that the response never makes it to a user.
+func SiteGetByID(ctx context.Context, id int32) (*SiteConfig, error) {
+	q := sqlf.Sprintf("SELECT "+siteConfigColumns+" FROM critical_and_site_config s WHERE s.type = %s AND s.id = %s", "site", id)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := parseQueryRows(ctx, rows)
+	if err != nil || len(versions) == 0 {
+		return nil, err
+	}
+	return versions[0], nil
+}
+
 func newTransaction(ctx context.Context) (tx queryable, done func(), err error) {
 	rtx, err := dbconn.Global.BeginTx(ctx, nil)
 	if err != nil {
@@ -115,6 +178,10 @@ func createIfUpToDate(ctx context.Context, tx queryable, lastID *int32, contents
 	}
 
 	new := SiteConfig{Contents: contents}
+	if a := actor.FromContext(ctx); a.IsAuthenticated() {
+		uid := a.UID
+		new.AuthorUserID = &uid
+	}
 
 	latest, err = getLatest(ctx, tx)
 	if err != nil {
@@ -126,8 +193,8 @@ func createIfUpToDate(ctx context.Context, tx queryable, lastID *int32, contents
 
 	err = tx.QueryRowContext(
 		ctx,
-		"INSERT INTO critical_and_site_config(type, contents) VALUES($1, $2) RETURNING id, created_at, updated_at",
-		"site", new.Contents,
+		"INSERT INTO critical_and_site_config(type, contents, author_user_id) VALUES($1, $2, $3) RETURNING id, created_at, updated_at",
+		"site", new.Contents, new.AuthorUserID,
 	).Scan(&new.ID, &new.CreatedAt, &new.UpdatedAt)
 	if err != nil {
 		return nil, err
@@ -136,7 +203,7 @@ func createIfUpToDate(ctx context.Context, tx queryable, lastID *int32, contents
 }
 
 func getLatest(ctx context.Context, tx queryable) (*SiteConfig, error) {
-	q := sqlf.Sprintf("SELECT s.id, s.contents, s.created_at, s.updated_at FROM critical_and_site_config s WHERE type=%s ORDER BY id DESC LIMIT 1", "site")
+	q := sqlf.Sprintf("SELECT "+siteConfigColumns+" FROM critical_and_site_config s WHERE type=%s ORDER BY id DESC LIMIT 1", "site")
 	rows, err := tx.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
 	if err != nil {
 		return nil, err
@@ -152,12 +219,14 @@ func getLatest(ctx context.Context, tx queryable) (*SiteConfig, error) {
 	return versions[0], nil
 }
 
+const siteConfigColumns = "s.id, s.contents, s.created_at, s.updated_at, s.author_user_id"
+
 func parseQueryRows(ctx context.Context, rows *sql.Rows) ([]*SiteConfig, error) {
 	versions := []*SiteConfig{}
 	defer rows.Close()
 	for rows.Next() {
 		f := SiteConfig{}
-		err := rows.Scan(&f.ID, &f.Contents, &f.CreatedAt, &f.UpdatedAt)
+		err := rows.Scan(&f.ID, &f.Contents, &f.CreatedAt, &f.UpdatedAt, &f.AuthorUserID)
 		if err != nil {
 			return nil, err
 		}
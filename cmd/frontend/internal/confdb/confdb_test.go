@@ -5,6 +5,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
 )
 
@@ -198,3 +200,91 @@ func TestSiteCreateIfUpToDate(t *testing.T) {
 		})
 	}
 }
+
+func TestSiteListRevisions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	var userID int32
+	if err := dbconn.Global.QueryRowContext(ctx, "INSERT INTO users (username) VALUES ('alice') RETURNING id").Scan(&userID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Creates the default config with ID 1
+	if _, err := SiteGetLatest(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	lastID := int32(1)
+	byUser, err := SiteCreateIfUpToDate(actor.WithActor(ctx, actor.FromUser(userID)), &lastID, `{"a": 1}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bySystem, err := SiteCreateIfUpToDate(ctx, &byUser.ID, `{"a": 2}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if byUser.AuthorUserID == nil || *byUser.AuthorUserID != userID {
+		t.Errorf("unexpected author. want=%d have=%v", userID, byUser.AuthorUserID)
+	}
+	if bySystem.AuthorUserID != nil {
+		t.Errorf("unexpected author. want=nil have=%d", *bySystem.AuthorUserID)
+	}
+
+	for _, testCase := range []struct {
+		opt     SiteListOptions
+		wantIDs []int32
+	}{
+		{opt: SiteListOptions{}, wantIDs: []int32{3, 2, 1}},
+		{opt: SiteListOptions{Limit: 2}, wantIDs: []int32{3, 2}},
+		{opt: SiteListOptions{BeforeID: 3}, wantIDs: []int32{2, 1}},
+		{opt: SiteListOptions{Limit: 1, BeforeID: 2}, wantIDs: []int32{1}},
+	} {
+		revisions, err := SiteListRevisions(ctx, testCase.opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids := make([]int32, 0, len(revisions))
+		for _, revision := range revisions {
+			ids = append(ids, revision.ID)
+		}
+		if len(ids) != len(testCase.wantIDs) {
+			t.Fatalf("unexpected revisions for %+v. want=%v have=%v", testCase.opt, testCase.wantIDs, ids)
+		}
+		for i := range ids {
+			if ids[i] != testCase.wantIDs[i] {
+				t.Fatalf("unexpected revisions for %+v. want=%v have=%v", testCase.opt, testCase.wantIDs, ids)
+			}
+		}
+	}
+
+	count, err := SiteCountRevisions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("unexpected count. want=%d have=%d", 3, count)
+	}
+
+	revision, err := SiteGetByID(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revision == nil || revision.Contents != `{"a": 1}` {
+		t.Errorf("unexpected revision: %+v", revision)
+	}
+
+	missing, err := SiteGetByID(ctx, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if missing != nil {
+		t.Errorf("expected no revision, got: %+v", missing)
+	}
+}
@@ -28,6 +28,7 @@ func newTest() *httptestutil.Client {
 		enterpriseServices.GitHubWebhook,
 		enterpriseServices.GitLabWebhook,
 		enterpriseServices.BitbucketServerWebhook,
+		enterpriseServices.AzureDevOpsWebhook,
 		enterpriseServices.NewCodeIntelUploadHandler,
 		rateLimiter,
 	))
@@ -63,13 +63,16 @@ func AccessTokenAuthMiddleware(db dbutil.DB, next http.Handler) http.Handler {
 			//
 			// 🚨 SECURITY: It's important we check for the correct scopes to know what this token
 			// is allowed to do.
-			var requiredScope string
+			var (
+				subjectUserID int32
+				scopes        []string
+				err           error
+			)
 			if sudoUser == "" {
-				requiredScope = authz.ScopeUserAll
+				subjectUserID, scopes, err = lookupAccessTokenScopes(r, db, token)
 			} else {
-				requiredScope = authz.ScopeSiteAdminSudo
+				subjectUserID, err = database.AccessTokens(db).Lookup(r.Context(), token, authz.ScopeSiteAdminSudo)
 			}
-			subjectUserID, err := database.AccessTokens(db).Lookup(r.Context(), token, requiredScope)
 			if err != nil {
 				log15.Error("Invalid access token.", "token", token, "err", err)
 				http.Error(w, "Invalid access token.", http.StatusUnauthorized)
@@ -107,9 +110,32 @@ func AccessTokenAuthMiddleware(db dbutil.DB, next http.Handler) http.Handler {
 				log15.Debug("HTTP request used sudo token.", "requestURI", r.URL.RequestURI(), "tokenSubjectUserID", subjectUserID, "actorUserID", actorUserID, "actorUsername", user.Username)
 			}
 
-			r = r.WithContext(actor.WithActor(r.Context(), &actor.Actor{UID: actorUserID}))
+			r = r.WithContext(actor.WithActor(r.Context(), &actor.Actor{UID: actorUserID, Scopes: scopes}))
 		}
 
 		next.ServeHTTP(w, r)
 	})
 }
+
+// lookupAccessTokenScopes looks up an access token that is used without sudo. It returns nil
+// scopes if the token has the "user:all" scope, and the token's fine-grained scopes otherwise.
+// Tokens that have neither may only be used with sudo.
+func lookupAccessTokenScopes(r *http.Request, db dbutil.DB, token string) (subjectUserID int32, scopes []string, err error) {
+	subjectUserID, tokenScopes, err := database.AccessTokens(db).LookupScopes(r.Context(), token)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	for _, scope := range tokenScopes {
+		if scope == authz.ScopeUserAll {
+			return subjectUserID, nil, nil
+		}
+		if authz.IsFineGrainedScope(scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		return 0, nil, database.ErrAccessTokenNotFound
+	}
+	return subjectUserID, scopes, nil
+}
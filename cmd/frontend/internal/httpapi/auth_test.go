@@ -23,6 +23,9 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		actor := actor.FromContext(r.Context())
 		if actor.IsAuthenticated() {
 			fmt.Fprintf(w, "user %v", actor.UID)
+			if actor.Scopes != nil {
+				fmt.Fprintf(w, " with scopes %q", actor.Scopes)
+			}
 		} else {
 			fmt.Fprint(w, "no user")
 		}
@@ -71,9 +74,9 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		req, _ := http.NewRequest("GET", "/", nil)
 		req.Header.Set("Authorization", "token badbad")
 		var calledAccessTokensLookup bool
-		database.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+		database.Mocks.AccessTokens.LookupScopes = func(tokenHexEncoded string) (subjectUserID int32, scopes []string, err error) {
 			calledAccessTokensLookup = true
-			return 0, errors.New("x")
+			return 0, nil, errors.New("x")
 		}
 		defer func() { database.Mocks = database.MockStores{} }()
 		checkHTTPResponse(t, req, http.StatusUnauthorized, "Invalid access token.\n")
@@ -87,15 +90,12 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 			req, _ := http.NewRequest("GET", "/", nil)
 			req.Header.Set("Authorization", headerValue)
 			var calledAccessTokensLookup bool
-			database.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+			database.Mocks.AccessTokens.LookupScopes = func(tokenHexEncoded string) (subjectUserID int32, scopes []string, err error) {
 				calledAccessTokensLookup = true
 				if want := "abcdef"; tokenHexEncoded != want {
 					t.Errorf("got %q, want %q", tokenHexEncoded, want)
 				}
-				if want := authz.ScopeUserAll; requiredScope != want {
-					t.Errorf("got %q, want %q", requiredScope, want)
-				}
-				return 123, nil
+				return 123, []string{authz.ScopeUserAll}, nil
 			}
 			defer func() { database.Mocks = database.MockStores{} }()
 			checkHTTPResponse(t, req, http.StatusOK, "user 123")
@@ -111,15 +111,12 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		req.Header.Set("Authorization", "token abcdef")
 		req = req.WithContext(actor.WithActor(context.Background(), &actor.Actor{UID: 456}))
 		var calledAccessTokensLookup bool
-		database.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+		database.Mocks.AccessTokens.LookupScopes = func(tokenHexEncoded string) (subjectUserID int32, scopes []string, err error) {
 			calledAccessTokensLookup = true
 			if want := "abcdef"; tokenHexEncoded != want {
 				t.Errorf("got %q, want %q", tokenHexEncoded, want)
 			}
-			if want := authz.ScopeUserAll; requiredScope != want {
-				t.Errorf("got %q, want %q", requiredScope, want)
-			}
-			return 123, nil
+			return 123, []string{authz.ScopeUserAll}, nil
 		}
 		defer func() { database.Mocks = database.MockStores{} }()
 		checkHTTPResponse(t, req, http.StatusOK, "user 123")
@@ -145,15 +142,12 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 			}
 			req = req.WithContext(actor.WithActor(context.Background(), &actor.Actor{UID: 456}))
 			var calledAccessTokensLookup bool
-			database.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+			database.Mocks.AccessTokens.LookupScopes = func(tokenHexEncoded string) (subjectUserID int32, scopes []string, err error) {
 				calledAccessTokensLookup = true
 				if want := "abcdef"; tokenHexEncoded != want {
 					t.Errorf("got %q, want %q", tokenHexEncoded, want)
 				}
-				if want := authz.ScopeUserAll; requiredScope != want {
-					t.Errorf("got %q, want %q", requiredScope, want)
-				}
-				return 123, nil
+				return 123, []string{authz.ScopeUserAll}, nil
 			}
 			defer func() { database.Mocks = database.MockStores{} }()
 			checkHTTPResponse(t, req, http.StatusOK, "user 123")
@@ -163,6 +157,27 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		})
 	}
 
+	t.Run("valid non-sudo token with fine-grained scopes", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "token abcdef")
+		database.Mocks.AccessTokens.LookupScopes = func(tokenHexEncoded string) (subjectUserID int32, scopes []string, err error) {
+			return 123, []string{authz.ScopeSearchRead, authz.ScopeCodeRead + "@^github\\.com/a/"}, nil
+		}
+		defer func() { database.Mocks = database.MockStores{} }()
+		checkHTTPResponse(t, req, http.StatusOK, `user 123 with scopes ["search:read" "code:read@^github\\.com/a/"]`)
+	})
+
+	// Test that a token that may only be used with sudo is rejected without sudo.
+	t.Run("valid non-sudo token without usable scopes", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "token abcdef")
+		database.Mocks.AccessTokens.LookupScopes = func(tokenHexEncoded string) (subjectUserID int32, scopes []string, err error) {
+			return 123, []string{authz.ScopeSiteAdminSudo}, nil
+		}
+		defer func() { database.Mocks = database.MockStores{} }()
+		checkHTTPResponse(t, req, http.StatusUnauthorized, "Invalid access token.\n")
+	})
+
 	t.Run("valid sudo token", func(t *testing.T) {
 		req, _ := http.NewRequest("GET", "/", nil)
 		req.Header.Set("Authorization", `token-sudo token="abcdef",user="alice"`)
@@ -0,0 +1,119 @@
+package httpapi
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// commitDates are the committer dates of the commits at HEAD of indexed
+// repositories, which rank recently active repositories higher in search.
+var commitDates = newCommitDateCache(gitCommitDate)
+
+// commitDateCache caches commit dates, which never change for a commit. The
+// search configuration is polled for every indexed repository, so dates which
+// are not cached yet are looked up in the background instead of making the
+// poll wait on gitserver. Until then the last known date of the repository is
+// used, so that its priority doesn't change twice on every new commit.
+type commitDateCache struct {
+	get func(ctx context.Context, repo api.RepoName, commit api.CommitID) (time.Time, error)
+
+	cache *lru.Cache // commitDateKey -> time.Time
+
+	startOnce sync.Once
+	queue     chan commitDateKey
+
+	mu     sync.Mutex
+	queued map[commitDateKey]struct{}
+	last   map[api.RepoName]time.Time
+}
+
+type commitDateKey struct {
+	repo   api.RepoName
+	commit api.CommitID
+}
+
+const (
+	commitDateCacheSize   = 100000
+	commitDateQueueSize   = 10000
+	commitDateConcurrency = 4
+)
+
+func newCommitDateCache(get func(context.Context, api.RepoName, api.CommitID) (time.Time, error)) *commitDateCache {
+	cache, err := lru.New(commitDateCacheSize)
+	if err != nil {
+		panic(err)
+	}
+	return &commitDateCache{
+		get:    get,
+		cache:  cache,
+		queue:  make(chan commitDateKey, commitDateQueueSize),
+		queued: map[commitDateKey]struct{}{},
+		last:   map[api.RepoName]time.Time{},
+	}
+}
+
+// Get returns the date of commit in repo. If it is not cached yet, it is
+// looked up in the background and the date of the commit last returned for
+// repo is returned instead, or the zero time if there is none.
+func (c *commitDateCache) Get(repo api.RepoName, commit api.CommitID) time.Time {
+	key := commitDateKey{repo: repo, commit: commit}
+	if date, ok := c.cache.Get(key); ok {
+		c.mu.Lock()
+		c.last[repo] = date.(time.Time)
+		c.mu.Unlock()
+		return date.(time.Time)
+	}
+
+	c.startOnce.Do(func() {
+		for i := 0; i < commitDateConcurrency; i++ {
+			go c.lookupQueued()
+		}
+	})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.queued[key]; !ok {
+		select {
+		case c.queue <- key:
+			c.queued[key] = struct{}{}
+		default:
+			// The queue is full. We try again on the next poll.
+		}
+	}
+	return c.last[repo]
+}
+
+func (c *commitDateCache) lookupQueued() {
+	for key := range c.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		date, err := c.get(ctx, key.repo, key.commit)
+		cancel()
+		if err != nil {
+			log15.Debug("failed to look up commit date for search ranking", "repo", key.repo, "commit", key.commit, "error", err)
+		} else {
+			c.cache.Add(key, date)
+		}
+
+		c.mu.Lock()
+		delete(c.queued, key)
+		c.mu.Unlock()
+	}
+}
+
+func gitCommitDate(ctx context.Context, repo api.RepoName, commit api.CommitID) (time.Time, error) {
+	c, err := git.GetCommit(ctx, repo, commit, git.ResolveRevisionOptions{NoEnsureRevision: true})
+	if err != nil {
+		return time.Time{}, err
+	}
+	if c.Committer != nil {
+		return c.Committer.Date, nil
+	}
+	return c.Author.Date, nil
+}
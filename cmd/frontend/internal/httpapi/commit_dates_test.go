@@ -0,0 +1,52 @@
+package httpapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestCommitDateCache(t *testing.T) {
+	date := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	lookups := make(chan api.CommitID, 10)
+	c := newCommitDateCache(func(_ context.Context, repo api.RepoName, commit api.CommitID) (time.Time, error) {
+		lookups <- commit
+		return date, nil
+	})
+
+	if got := c.Get("repo", "c1"); !got.IsZero() {
+		t.Fatalf("got date %v before lookup, want zero", got)
+	}
+	if got := <-lookups; got != "c1" {
+		t.Fatalf("looked up commit %q, want c1", got)
+	}
+
+	// The lookup happens in the background.
+	deadline := time.Now().Add(10 * time.Second)
+	for c.Get("repo", "c1").IsZero() {
+		if time.Now().After(deadline) {
+			t.Fatal("commit date was not cached")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got := c.Get("repo", "c1"); !got.Equal(date) {
+		t.Fatalf("got date %v, want %v", got, date)
+	}
+
+	select {
+	case commit := <-lookups:
+		t.Fatalf("unexpected lookup of cached commit %q", commit)
+	default:
+	}
+
+	// Until the date of a new commit is known, the last known date of the
+	// repository is used.
+	if got := c.Get("repo", "c2"); !got.Equal(date) {
+		t.Fatalf("got date %v before lookup of new commit, want %v", got, date)
+	}
+	if got := c.Get("other", "c2"); !got.IsZero() {
+		t.Fatalf("got date %v for other repository, want zero", got)
+	}
+}
@@ -73,6 +73,19 @@ func serveGraphQL(schema *graphql.Schema, rlw graphqlbackend.LimitWatcher, isInt
 		traceData.uid = uid
 		traceData.anonymous = anonymous
 
+		// 🚨 SECURITY: Access tokens with fine-grained scopes may only request the fields that
+		// their scopes permit.
+		if err := graphqlbackend.CheckAccessTokenScopes(r.Context(), params.Query); err != nil {
+			responseJSON, err := json.Marshal(&graphql.Response{Errors: []*gqlerrors.QueryError{gqlerrors.Errorf("%s", err)}})
+			if err != nil {
+				return err
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write(responseJSON)
+			return nil
+		}
+
 		validationErrs := schema.ValidateWithVariables(params.Query, params.Variables)
 
 		var cost *graphqlbackend.QueryCost
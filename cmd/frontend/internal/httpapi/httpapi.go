@@ -25,6 +25,7 @@ import (
 	frontendsearch "github.com/sourcegraph/sourcegraph/cmd/frontend/internal/search"
 	registry "github.com/sourcegraph/sourcegraph/cmd/frontend/registry/api"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/webhooks"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/env"
@@ -39,7 +40,7 @@ import (
 //
 // 🚨 SECURITY: The caller MUST wrap the returned handler in middleware that checks authentication
 // and sets the actor in the request context.
-func NewHandler(db dbutil.DB, m *mux.Router, schema *graphql.Schema, githubWebhook webhooks.Registerer, gitlabWebhook, bitbucketServerWebhook http.Handler, newCodeIntelUploadHandler enterprise.NewCodeIntelUploadHandler, rateLimiter graphqlbackend.LimitWatcher) http.Handler {
+func NewHandler(db dbutil.DB, m *mux.Router, schema *graphql.Schema, githubWebhook webhooks.Registerer, gitlabWebhook, bitbucketServerWebhook, azureDevOpsWebhook http.Handler, newCodeIntelUploadHandler enterprise.NewCodeIntelUploadHandler, rateLimiter graphqlbackend.LimitWatcher) http.Handler {
 	if m == nil {
 		m = apirouter.New(nil)
 	}
@@ -70,6 +71,7 @@ func NewHandler(db dbutil.DB, m *mux.Router, schema *graphql.Schema, githubWebho
 	m.Get(apirouter.GitHubWebhooks).Handler(trace.Route(&gh))
 	m.Get(apirouter.GitLabWebhooks).Handler(trace.Route(gitlabWebhook))
 	m.Get(apirouter.BitbucketServerWebhooks).Handler(trace.Route(bitbucketServerWebhook))
+	m.Get(apirouter.AzureDevOpsWebhooks).Handler(trace.Route(azureDevOpsWebhook))
 	m.Get(apirouter.LSIFUpload).Handler(trace.Route(newCodeIntelUploadHandler(false)))
 
 	if envvar.SourcegraphDotComMode() {
@@ -91,7 +93,36 @@ func NewHandler(db dbutil.DB, m *mux.Router, schema *graphql.Schema, githubWebho
 		http.Error(w, "no route", http.StatusNotFound)
 	})
 
-	return m
+	return accessTokenScopesMiddleware(m)
+}
+
+// accessTokenScopesRoutes are the API routes that may be requested with an access token that
+// only has fine-grained scopes. Their handlers enforce the scopes.
+var accessTokenScopesRoutes = map[string]struct{}{
+	apirouter.GraphQL:        {},
+	apirouter.SearchStream:   {},
+	apirouter.LSIFUpload:     {},
+	apirouter.SrcCliVersion:  {},
+	apirouter.SrcCliDownload: {},
+}
+
+// accessTokenScopesMiddleware rejects requests from actors that were authenticated with an
+// access token that only has fine-grained scopes, unless the requested route enforces them.
+func accessTokenScopesMiddleware(m *mux.Router) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if actor.FromContext(r.Context()).Scopes != nil {
+			var match mux.RouteMatch
+			if !m.Match(r, &match) || match.Route == nil {
+				http.Error(w, "no route", http.StatusNotFound)
+				return
+			}
+			if _, ok := accessTokenScopesRoutes[match.Route.GetName()]; !ok {
+				http.Error(w, "The scopes of the access token do not permit this request.", http.StatusForbidden)
+				return
+			}
+		}
+		m.ServeHTTP(w, r)
+	})
 }
 
 // NewInternalHandler returns a new API handler for internal endpoints that uses
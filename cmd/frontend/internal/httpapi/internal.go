@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"path"
 	"strconv"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/gorilla/mux"
@@ -212,15 +213,19 @@ func serveSearchConfiguration(w http.ResponseWriter, r *http.Request) error {
 			return string(commitID), err
 		}
 
-		priority := float64(repo.Stars) + repoRankFromConfig(siteConfig, repoName)
+		getCommitDate := func(commit string) (time.Time, error) {
+			return commitDates.Get(repo.Name, api.CommitID(commit)), nil
+		}
 
 		return &searchbackend.RepoIndexOptions{
-			RepoID:     int32(repo.ID),
-			Public:     !repo.Private,
-			Priority:   priority,
-			Fork:       repo.Fork,
-			Archived:   repo.Archived,
-			GetVersion: getVersion,
+			RepoID:        int32(repo.ID),
+			Public:        !repo.Private,
+			Stars:         repo.Stars,
+			Boost:         repoRankFromConfig(siteConfig, repoName),
+			Fork:          repo.Fork,
+			Archived:      repo.Archived,
+			GetVersion:    getVersion,
+			GetCommitDate: getCommitDate,
 		}, nil
 	}
 
@@ -24,14 +24,17 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	searchlogs "github.com/sourcegraph/sourcegraph/cmd/frontend/internal/search/logs"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/honey"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	searchshared "github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/run"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	streamapi "github.com/sourcegraph/sourcegraph/internal/search/streaming/api"
 	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/internal/types"
@@ -130,6 +133,19 @@ func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		Globbing: false, // TODO
 	}
 
+	// If the query aggregates results, we send the aggregation instead of
+	// the matches. The aggregation is sent whenever it changes, and a last
+	// time once the search is complete.
+	aggregation := streaming.NewSearchAggregation(inputs.Query)
+	if aggregation != nil {
+		display = 0
+	}
+	aggregationDirty := false
+	sendAggregations := func(complete bool) error {
+		aggregationDirty = false
+		return eventWriter.Event("aggregations", fromAggregation(aggregation, complete, progress.Stats))
+	}
+
 	// Store marshalled matches and flush periodically or when we go over
 	// 32kb.
 	matchesBuf := &jsonArrayBuf{
@@ -146,6 +162,13 @@ func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if aggregationDirty {
+			if err := sendAggregations(false); err != nil {
+				// EOF
+				return
+			}
+		}
+
 		if progress.Dirty {
 			sendProgress()
 		}
@@ -182,6 +205,10 @@ func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 		progress.Update(event)
 		filters.Update(event)
+		if aggregation != nil && len(event.Results) > 0 {
+			aggregation.Update(event)
+			aggregationDirty = true
+		}
 
 		// Truncate the event to the match limit before fetching repo metadata
 		for i, match := range event.Results {
@@ -212,6 +239,13 @@ func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	matchesFlush()
 
+	if aggregation != nil {
+		if err := sendAggregations(true); err != nil {
+			// EOF
+			return
+		}
+	}
+
 	// Send dynamic filters once.
 	if filters := filters.Compute(); len(filters) > 0 {
 		buf := make([]streamhttp.EventFilter, 0, len(filters))
@@ -234,6 +268,11 @@ func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	resultsResolver, err := results()
 	if err != nil {
 		_ = eventWriter.Event("error", streamhttp.EventError{Message: err.Error()})
+		h.recordQueryHistory(ctx, &inputs, &database.SearchQueryHistoryEntry{
+			Status:      graphqlbackend.DetermineStatusForLogs(resultsResolver, err),
+			ResultCount: int32(progress.MatchCount),
+			DurationMs:  int32(time.Since(start).Milliseconds()),
+		})
 		return
 	}
 
@@ -257,12 +296,21 @@ func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	_ = eventWriter.Event("progress", progress.Final())
 
-	var status, alertType string
+	var status, alertType, alertTitle string
 	status = graphqlbackend.DetermineStatusForLogs(resultsResolver, err)
 	if alert != nil {
 		alertType = alert.PrometheusType()
+		alertTitle = alert.Title()
 	}
 
+	h.recordQueryHistory(ctx, &inputs, &database.SearchQueryHistoryEntry{
+		Status:      status,
+		AlertType:   alertType,
+		AlertTitle:  alertTitle,
+		ResultCount: int32(progress.MatchCount),
+		DurationMs:  int32(time.Since(start).Milliseconds()),
+	})
+
 	isSlow := time.Since(start) > searchlogs.LogSlowSearchesThreshold()
 	if honey.Enabled() || isSlow {
 		ev := honey.SearchEvent(ctx, honey.SearchEventArgs{
@@ -285,6 +333,29 @@ func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// recordQueryHistory adds the search to the search query history of the
+// current user, unless they turned it off with the search.queryHistory
+// setting. The query and pattern type of entry are set from inputs.
+func (h *streamHandler) recordQueryHistory(ctx context.Context, inputs *run.SearchInputs, entry *database.SearchQueryHistoryEntry) {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() || inputs.UserSettings == nil {
+		return
+	}
+	if enabled := inputs.UserSettings.SearchQueryHistory; enabled != nil && !*enabled {
+		return
+	}
+
+	entry.UserID = a.UID
+	entry.Query = inputs.OriginalQuery
+	entry.PatternType = inputs.PatternType.String()
+
+	// The request context is canceled as soon as the client goes away, which
+	// must not prevent us from recording the search.
+	if err := database.SearchQueryHistory(h.db).Create(context.Background(), entry); err != nil {
+		log15.Warn("streaming: failed to record search query history", "error", err)
+	}
+}
+
 func (h *streamHandler) getEventRepoMetadata(ctx context.Context, event streaming.SearchEvent) map[api.RepoID]*types.Repo {
 	ids := repoIDs(event.Results)
 	if len(ids) == 0 {
@@ -406,6 +477,22 @@ func fromStrPtr(s *string) string {
 	return *s
 }
 
+// fromAggregation returns the aggregations event for a. The counts are a lower
+// bound if the search hit a limit or timed out in a repository.
+func fromAggregation(a *streaming.SearchAggregation, complete bool, stats streaming.Stats) streamhttp.EventAggregations {
+	groups := a.Compute()
+	buf := make([]streamhttp.EventAggregationGroup, 0, len(groups))
+	for _, g := range groups {
+		buf = append(buf, streamhttp.EventAggregationGroup{Label: g.Label, Count: g.Count})
+	}
+	return streamhttp.EventAggregations{
+		Mode:     string(a.Mode),
+		Groups:   buf,
+		Complete: complete,
+		LimitHit: stats.IsLimitHit || len(getNames(stats, searchshared.RepoStatusTimedout)) > 0,
+	}
+}
+
 func fromMatch(match result.Match, repoCache map[api.RepoID]*types.Repo) streamhttp.EventMatch {
 	switch v := match.(type) {
 	case *result.FileMatch:
@@ -423,6 +510,9 @@ func fromFileMatch(fm *result.FileMatch, repoCache map[api.RepoID]*types.Repo) s
 	if syms := fm.Symbols; len(syms) > 0 {
 		return fromSymbolMatch(fm, repoCache)
 	}
+	if fm.Diff != "" {
+		return fromFileDiffMatch(fm, repoCache)
+	}
 
 	lineMatches := make([]streamhttp.EventLineMatch, 0, len(fm.LineMatches))
 	for _, lm := range fm.LineMatches {
@@ -451,6 +541,7 @@ func fromFileMatch(fm *result.FileMatch, repoCache map[api.RepoID]*types.Repo) s
 		Branches:    branches,
 		Version:     string(fm.CommitID),
 		LineMatches: lineMatches,
+		Debug:       fromDebug(fm.Debug),
 	}
 }
 
@@ -489,6 +580,44 @@ func fromSymbolMatch(fm *result.FileMatch, repoCache map[api.RepoID]*types.Repo)
 		Branches:   branches,
 		Version:    string(fm.CommitID),
 		Symbols:    symbols,
+		Debug:      fromDebug(fm.Debug),
+	}
+}
+
+func fromFileDiffMatch(fm *result.FileMatch, repoCache map[api.RepoID]*types.Repo) *streamhttp.EventFileDiffMatch {
+	var branches []string
+	if fm.InputRev != nil {
+		branches = []string{*fm.InputRev}
+	}
+
+	var stars int
+	if r, ok := repoCache[fm.Repo.ID]; ok {
+		stars = r.Stars
+	}
+
+	return &streamhttp.EventFileDiffMatch{
+		Type:       streamhttp.FileDiffMatchType,
+		Path:       fm.Path,
+		Repository: string(fm.Repo.Name),
+		RepoStars:  stars,
+		Branches:   branches,
+		Version:    string(fm.CommitID),
+		Diff:       fm.Diff,
+		Debug:      fromDebug(fm.Debug),
+	}
+}
+
+func fromDebug(d *result.Debug) *streamapi.MatchDebug {
+	if d == nil {
+		return nil
+	}
+	return &streamapi.MatchDebug{
+		Backend:       d.Backend,
+		Endpoint:      d.Endpoint,
+		Score:         d.Score,
+		ShardPriority: d.ShardPriority,
+		Query:         d.Query,
+		DurationMs:    int(d.Duration.Milliseconds()),
 	}
 }
 
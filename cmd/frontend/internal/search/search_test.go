@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
@@ -212,6 +213,94 @@ func TestDisplayLimit(t *testing.T) {
 	}
 }
 
+func TestServeStream_aggregations(t *testing.T) {
+	mock := &mockSearchResolver{
+		done: make(chan struct{}),
+	}
+
+	database.Mocks.Repos.GetByIDs = func(ctx context.Context, ids ...api2.RepoID) (_ []*types.Repo, err error) {
+		res := make([]*types.Repo, 0, len(ids))
+		for _, id := range ids {
+			res = append(res, &types.Repo{
+				ID: id,
+			})
+		}
+		return res, nil
+	}
+	defer func() { database.Mocks.Repos.GetByIDs = nil }()
+
+	queryString := "foo aggregate:repo"
+	ts := httptest.NewServer(&streamHandler{
+		flushTickerInternal: 1 * time.Millisecond,
+		pingTickerInterval:  1 * time.Millisecond,
+		newSearchResolver: func(_ context.Context, _ dbutil.DB, args *graphqlbackend.SearchArgs) (searchResolver, error) {
+			mock.c = args.Stream
+			q, err := query.Parse(queryString, query.Literal)
+			if err != nil {
+				t.Fatal(err)
+			}
+			mock.inputs = &run.SearchInputs{
+				Query: q,
+			}
+			return mock, nil
+		}})
+	defer ts.Close()
+
+	req, _ := streamhttp.NewRequest(ts.URL, queryString)
+
+	var matches int
+	var last *streamhttp.EventAggregations
+	decoder := streamhttp.Decoder{
+		OnMatches: func(m []streamhttp.EventMatch) {
+			matches += len(m)
+		},
+		OnAggregations: func(a *streamhttp.EventAggregations) {
+			if last != nil && last.Complete {
+				t.Error("got aggregations after the complete aggregations")
+			}
+			last = a
+		},
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	// Consume events.
+	g := errgroup.Group{}
+	g.Go(func() error {
+		return decoder.ReadAll(resp.Body)
+	})
+
+	mock.c.Send(streaming.SearchEvent{
+		Results: []result.Match{mkRepoMatch(1), mkRepoMatch(2)},
+	})
+	mock.c.Send(streaming.SearchEvent{
+		Results: []result.Match{mkRepoMatch(2)},
+	})
+	mock.Close()
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	if matches != 0 {
+		t.Errorf("got %d matches, want none when aggregating", matches)
+	}
+	want := &streamhttp.EventAggregations{
+		Mode: "repo",
+		Groups: []streamhttp.EventAggregationGroup{
+			{Label: "repo2", Count: 2},
+			{Label: "repo1", Count: 1},
+		},
+		Complete: true,
+	}
+	if diff := cmp.Diff(want, last); diff != "" {
+		t.Fatalf("unexpected aggregations (-want +got):\n%s", diff)
+	}
+}
+
 func mkRepoMatch(id int) *result.RepoMatch {
 	return &result.RepoMatch{
 		ID:   api2.RepoID(id),
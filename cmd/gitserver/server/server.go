@@ -40,8 +40,10 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/honey"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/internal/logging"
 	"github.com/sourcegraph/sourcegraph/internal/mutablelimiter"
 	"github.com/sourcegraph/sourcegraph/internal/repotrackutil"
+	"github.com/sourcegraph/sourcegraph/internal/requestid"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 	"github.com/sourcegraph/sourcegraph/internal/types"
@@ -662,7 +664,7 @@ func (s *Server) handleRepoUpdate(w http.ResponseWriter, r *http.Request) {
 		resp.CloneInProgress = true
 		_, err := s.cloneRepo(ctx, req.Repo, &cloneOptions{Block: true})
 		if err != nil {
-			log15.Warn("error cloning repo", "repo", req.Repo, "err", err)
+			logging.FromContext(r.Context()).Warn("error cloning repo", "repo", req.Repo, "err", err)
 			resp.Error = err.Error()
 		}
 	} else {
@@ -688,7 +690,7 @@ func (s *Server) handleRepoUpdate(w http.ResponseWriter, r *http.Request) {
 			resp.LastChanged = &lastChanged
 		}
 		if statusErr != nil {
-			log15.Error("failed to get status of repo", "repo", req.Repo, "error", statusErr)
+			logging.FromContext(r.Context()).Error("failed to get status of repo", "repo", req.Repo, "error", statusErr)
 			// report this error in-band, but still produce a valid response with the
 			// other information.
 			resp.Error = statusErr.Error()
@@ -718,13 +720,13 @@ func (s *Server) handleArchive(w http.ResponseWriter, r *http.Request) {
 
 	if err := checkSpecArgSafety(treeish); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		log15.Error("gitserver.archive.CheckSpecArgSafety", "error", err)
+		logging.FromContext(r.Context()).Error("gitserver.archive.CheckSpecArgSafety", "error", err)
 		return
 	}
 
 	if repo == "" || format == "" {
 		w.WriteHeader(http.StatusBadRequest)
-		log15.Error("gitserver.archive", "error", "empty repo or format")
+		logging.FromContext(r.Context()).Error("gitserver.archive", "error", "empty repo or format")
 		return
 	}
 
@@ -834,6 +836,7 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 				ev.AddField("cmd", cmd)
 				ev.AddField("args", args)
 				ev.AddField("actor", r.Header.Get("X-Sourcegraph-Actor"))
+				ev.AddField("request_id", requestid.FromContext(ctx))
 				ev.AddField("ensure_revision", req.EnsureRevision)
 				ev.AddField("ensure_revision_status", ensureRevisionStatus)
 				ev.AddField("client", r.UserAgent())
@@ -877,7 +880,7 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 	dir := s.dir(req.Repo)
 	if !repoCloned(dir) {
 		if conf.Get().DisableAutoGitUpdates {
-			log15.Debug("not cloning on demand as DisableAutoGitUpdates is set")
+			logging.FromContext(ctx).Debug("not cloning on demand as DisableAutoGitUpdates is set")
 			status = "repo-not-found"
 			w.WriteHeader(http.StatusNotFound)
 			_ = json.NewEncoder(w).Encode(&protocol.NotFoundPayload{})
@@ -897,7 +900,7 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 
 		cloneProgress, err := s.cloneRepo(ctx, req.Repo, nil)
 		if err != nil {
-			log15.Debug("error starting repo clone", "repo", req.Repo, "err", err)
+			logging.FromContext(ctx).Debug("error starting repo clone", "repo", req.Repo, "err", err)
 			status = "repo-not-found"
 			w.WriteHeader(http.StatusNotFound)
 			_ = json.NewEncoder(w).Encode(&protocol.NotFoundPayload{CloneInProgress: false})
@@ -1073,6 +1076,7 @@ func (s *Server) p4exec(w http.ResponseWriter, r *http.Request, req *protocol.P4
 				ev.AddField("cmd", cmd)
 				ev.AddField("args", args)
 				ev.AddField("actor", r.Header.Get("X-Sourcegraph-Actor"))
+				ev.AddField("request_id", requestid.FromContext(ctx))
 				ev.AddField("client", r.UserAgent())
 				ev.AddField("duration_ms", duration.Milliseconds())
 				ev.AddField("stdout_size", stdoutN)
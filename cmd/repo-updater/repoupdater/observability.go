@@ -12,6 +12,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/requestid"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 )
 
@@ -88,6 +89,7 @@ func (h *observedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 		h.log.Debug(
 			"http.request",
+			"request_id", requestid.FromContext(r.Context()),
 			"method", r.Method,
 			"route", r.URL.Path,
 			"code", rr.code,
@@ -22,8 +22,10 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/logging"
 	"github.com/sourcegraph/sourcegraph/internal/repos"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/requestid"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
@@ -136,7 +138,7 @@ func (s *Server) handleRepoLookup(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "request canceled", http.StatusGatewayTimeout)
 			return
 		}
-		log15.Error("repoLookup failed", "args", &args, "error", err)
+		logging.FromContext(r.Context()).Error("repoLookup failed", "args", &args, "error", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -155,7 +157,7 @@ func (s *Server) handleEnqueueRepoUpdate(w http.ResponseWriter, r *http.Request)
 	}
 	result, status, err := s.enqueueRepoUpdate(r.Context(), &req)
 	if err != nil {
-		log15.Error("enqueueRepoUpdate failed", "req", req, "error", err)
+		logging.FromContext(r.Context()).Error("enqueueRepoUpdate failed", "req", req, "error", err)
 		respond(w, status, err)
 		return
 	}
@@ -165,7 +167,7 @@ func (s *Server) handleEnqueueRepoUpdate(w http.ResponseWriter, r *http.Request)
 func (s *Server) enqueueRepoUpdate(ctx context.Context, req *protocol.RepoUpdateRequest) (resp *protocol.RepoUpdateResponse, httpStatus int, err error) {
 	tr, ctx := trace.New(ctx, "enqueueRepoUpdate", req.String())
 	defer func() {
-		log15.Debug("enqueueRepoUpdate", "httpStatus", httpStatus, "resp", resp, "error", err)
+		logging.FromContext(ctx).Debug("enqueueRepoUpdate", "httpStatus", httpStatus, "resp", resp, "error", err)
 		if resp != nil {
 			tr.LogFields(
 				otlog.Int32("resp.id", int32(resp.ID)),
@@ -199,6 +201,7 @@ func (s *Server) enqueueRepoUpdate(ctx context.Context, req *protocol.RepoUpdate
 func (s *Server) handleExternalServiceSync(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithCancel(r.Context())
 	defer cancel()
+	logger := logging.FromContext(ctx)
 
 	var req protocol.ExternalServiceSyncRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -213,13 +216,13 @@ func (s *Server) handleExternalServiceSync(w http.ResponseWriter, r *http.Reques
 		Config:      req.ExternalService.Config,
 	}, httpcli.NewExternalHTTPClientFactory())
 	if err != nil {
-		log15.Error("server.external-service-sync", "kind", req.ExternalService.Kind, "error", err)
+		logger.Error("server.external-service-sync", "kind", req.ExternalService.Kind, "error", err)
 		return
 	}
 
 	err = externalServiceValidate(ctx, req, src)
 	if err == github.ErrIncompleteResults {
-		log15.Info("server.external-service-sync", "kind", req.ExternalService.Kind, "error", err)
+		logger.Info("server.external-service-sync", "kind", req.ExternalService.Kind, "error", err)
 		syncResult := &protocol.ExternalServiceSyncResult{
 			ExternalService: req.ExternalService,
 			Error:           err.Error(),
@@ -230,26 +233,26 @@ func (s *Server) handleExternalServiceSync(w http.ResponseWriter, r *http.Reques
 		// client is gone
 		return
 	} else if err != nil {
-		log15.Error("server.external-service-sync", "kind", req.ExternalService.Kind, "error", err)
+		logger.Error("server.external-service-sync", "kind", req.ExternalService.Kind, "error", err)
 		respond(w, http.StatusInternalServerError, err)
 		return
 	}
 
 	if err := s.Syncer.TriggerExternalServiceSync(ctx, req.ExternalService.ID); err != nil {
-		log15.Warn("Enqueueing external service sync job", "error", err, "id", req.ExternalService.ID)
+		logger.Warn("Enqueueing external service sync job", "error", err, "id", req.ExternalService.ID)
 	}
 
 	if s.RateLimitSyncer != nil {
 		err = s.RateLimitSyncer.SyncRateLimiters(ctx)
 		if err != nil {
-			log15.Warn("Handling rate limiter sync", "err", err)
+			logger.Warn("Handling rate limiter sync", "err", err)
 		}
 	}
 	if s.ChangesetSyncRegistry != nil {
 		s.ChangesetSyncRegistry.HandleExternalServiceSync(req.ExternalService)
 	}
 
-	log15.Info("server.external-service-sync", "synced", req.ExternalService.Kind)
+	logger.Info("server.external-service-sync", "synced", req.ExternalService.Kind)
 	respond(w, http.StatusOK, &protocol.ExternalServiceSyncResult{
 		ExternalService: req.ExternalService,
 	})
@@ -303,7 +306,7 @@ func (s *Server) repoLookup(ctx context.Context, args protocol.RepoLookupArgs) (
 
 	tr, ctx := trace.New(ctx, "repoLookup", args.String())
 	defer func() {
-		log15.Debug("repoLookup", "result", result, "error", err)
+		logging.FromContext(ctx).Debug("repoLookup", "result", result, "error", err)
 		if result != nil {
 			tr.LazyPrintf("result: %s", result)
 		}
@@ -352,11 +355,12 @@ func (s *Server) repoLookup(ctx context.Context, args protocol.RepoLookupArgs) (
 		// our background syncer.
 		if !repo.Private {
 			go func() {
-				ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+				// The sync outlives the request, but the work is still done on its behalf.
+				ctx, cancel := context.WithTimeout(requestid.WithRequestID(context.Background(), requestid.FromContext(ctx)), time.Minute)
 				defer cancel()
 				repoResult, err := s.remoteRepoSync(ctx, codehost, string(args.Repo))
 				if err != nil {
-					log15.Error("async remoteRepoSync failed", "repo", args.Repo, "error", err)
+					logging.FromContext(ctx).Error("async remoteRepoSync failed", "repo", args.Repo, "error", err)
 					return
 				}
 
@@ -368,7 +372,7 @@ func (s *Server) repoLookup(ctx context.Context, args protocol.RepoLookupArgs) (
 						r.DeletedAt = s.Now()
 					}))
 					if err != nil {
-						log15.Error("failed to delete inaccessible repo", "repo", args.Repo, "error", err)
+						logging.FromContext(ctx).Error("failed to delete inaccessible repo", "repo", args.Repo, "error", err)
 					}
 				}
 			}()
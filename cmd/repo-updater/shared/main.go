@@ -166,7 +166,8 @@ func Main(enterpriseInit EnterpriseInit) {
 		src = repos.NewSourcer(cf, repos.ObservedSource(log15.Root(), m))
 	}
 
-	scheduler := repos.NewUpdateScheduler()
+	commitIndexer := repos.NewCommitIndexer(db)
+	scheduler := repos.NewUpdateScheduler(commitIndexer)
 	server := &repoupdater.Server{
 		Store:           store,
 		Scheduler:       scheduler,
@@ -284,6 +285,9 @@ func Main(enterpriseInit EnterpriseInit) {
 		go repos.RunRepositoryPurgeWorker(ctx)
 	}
 
+	// Keeps the commit index used by commit and diff searches up to date.
+	go commitIndexer.Run(ctx)
+
 	// Git fetches scheduler
 	go repos.RunScheduler(ctx, scheduler)
 	log15.Debug("started scheduler")
@@ -26,6 +26,11 @@ type Request struct {
 	// because Zoekt only takes branch names
 	Branch string
 
+	// Paths, if non-empty, are the only files at Commit which are fetched and
+	// searched. It is used to search the files of a revision which differ
+	// from the commit Zoekt indexed.
+	Paths []string
+
 	PatternInfo
 
 	// The amount of time to wait for a repo archive to fetch.
@@ -123,6 +128,22 @@ type PatternInfo struct {
 	// file list in the frontend and passes it to searcher.
 	CombyRule string
 
+	// CombyRewrite is a comby rewrite template for structural search. If it is
+	// non-empty, matches are rewritten with it and every FileMatch contains the
+	// diff of the rewritten file instead of line matches. It only applies when
+	// IsStructuralPat is true.
+	CombyRewrite string
+
+	// Sizes and Lines are comparisons like ">1048576" or "<=5000" which the
+	// size in bytes and the number of lines of returned files must all
+	// satisfy. The number of lines of files too large to search or binary
+	// files is unknown, so they never satisfy Lines.
+	Sizes []string
+	Lines []string
+
+	// Binary, if "yes" or "no", only returns binary or non-binary files.
+	Binary string
+
 	// Select is the value of the the select field in the query. It is not necessary to
 	// use it since selection is done after the query completes, but exposing it can enable
 	// optimizations.
@@ -140,6 +161,9 @@ func (p *PatternInfo) String() string {
 		} else {
 			args = append(args, "comby")
 		}
+		if p.CombyRewrite != "" {
+			args = append(args, fmt.Sprintf("rewrite:%q", p.CombyRewrite))
+		}
 	}
 	if p.IsWordMatch {
 		args = append(args, "word")
@@ -162,6 +186,15 @@ func (p *PatternInfo) String() string {
 	if p.Select != "" {
 		args = append(args, fmt.Sprintf("select:%s", p.Select))
 	}
+	for _, size := range p.Sizes {
+		args = append(args, fmt.Sprintf("size:%s", size))
+	}
+	for _, lines := range p.Lines {
+		args = append(args, fmt.Sprintf("lines:%s", lines))
+	}
+	if p.Binary != "" {
+		args = append(args, fmt.Sprintf("binary:%s", p.Binary))
+	}
 
 	path := "glob"
 	if p.PathPatternsAreRegExps {
@@ -202,6 +235,15 @@ type FileMatch struct {
 
 	// LimitHit is true if LineMatches may not include all LineMatches.
 	LimitHit bool
+
+	// Diff is the unified diff of rewriting the file with the CombyRewrite
+	// template. It is empty unless the request has a CombyRewrite template.
+	Diff string `json:",omitempty"`
+
+	// Endpoint is the address of the searcher instance which found the
+	// match. Searcher doesn't send it, the client sets it for queries with
+	// debug:yes.
+	Endpoint string `json:"-"`
 }
 
 // LineMatch is the struct used by vscode to receive search results for a line.
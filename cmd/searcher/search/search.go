@@ -15,7 +15,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
 	"strconv"
 	"time"
@@ -32,6 +31,8 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/logging"
+	"github.com/sourcegraph/sourcegraph/internal/requestid"
 	"github.com/sourcegraph/sourcegraph/internal/store"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 )
@@ -113,7 +114,7 @@ func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		} else if errcode.IsTemporary(err) {
 			code = http.StatusServiceUnavailable
 		} else {
-			log.Printf("internal error serving %#+v: %s", p, err)
+			logging.FromContext(ctx).Error("internal error serving search request", "request", fmt.Sprintf("%#+v", p), "error", err)
 		}
 		http.Error(w, err.Error(), code)
 		return
@@ -141,6 +142,7 @@ func (s *Service) search(ctx context.Context, p *protocol.Request, sender *limit
 	span.SetTag("repo", p.Repo)
 	span.SetTag("url", p.URL)
 	span.SetTag("commit", p.Commit)
+	span.SetTag("paths", len(p.Paths))
 	span.SetTag("pattern", p.Pattern)
 	span.SetTag("isRegExp", strconv.FormatBool(p.IsRegExp))
 	span.SetTag("isStructuralPat", strconv.FormatBool(p.IsStructuralPat))
@@ -155,6 +157,9 @@ func (s *Service) search(ctx context.Context, p *protocol.Request, sender *limit
 	span.SetTag("deadline", p.Deadline)
 	span.SetTag("indexerEndpoints", p.IndexerEndpoints)
 	span.SetTag("select", p.Select)
+	span.SetTag("sizes", p.Sizes)
+	span.SetTag("lines", p.Lines)
+	span.SetTag("binary", p.Binary)
 	defer func(start time.Time) {
 		code := "200"
 		// We often have canceled and timed out requests. We do not want to
@@ -188,7 +193,7 @@ func (s *Service) search(ctx context.Context, p *protocol.Request, sender *limit
 		span.SetTag("deadlineHit", deadlineHit)
 		span.Finish()
 		if s.Log != nil {
-			s.Log.Debug("search request", "repo", p.Repo, "commit", p.Commit, "pattern", p.Pattern, "isRegExp", p.IsRegExp, "isStructuralPat", p.IsStructuralPat, "languages", p.Languages, "isWordMatch", p.IsWordMatch, "isCaseSensitive", p.IsCaseSensitive, "patternMatchesContent", p.PatternMatchesContent, "patternMatchesPath", p.PatternMatchesPath, "matches", sender.SentCount(), "code", code, "duration", time.Since(start), "indexerEndpoints", p.IndexerEndpoints, "err", err)
+			s.Log.Debug("search request", "request_id", requestid.FromContext(ctx), "repo", p.Repo, "commit", p.Commit, "pattern", p.Pattern, "isRegExp", p.IsRegExp, "isStructuralPat", p.IsStructuralPat, "languages", p.Languages, "isWordMatch", p.IsWordMatch, "isCaseSensitive", p.IsCaseSensitive, "patternMatchesContent", p.PatternMatchesContent, "patternMatchesPath", p.PatternMatchesPath, "matches", sender.SentCount(), "code", code, "duration", time.Since(start), "indexerEndpoints", p.IndexerEndpoints, "err", err)
 		}
 	}(time.Now())
 
@@ -218,7 +223,7 @@ func (s *Service) search(ctx context.Context, p *protocol.Request, sender *limit
 	defer cancel()
 
 	getZf := func() (string, *store.ZipFile, error) {
-		path, err := s.Store.PrepareZip(prepareCtx, p.Repo, p.Commit)
+		path, err := s.Store.PrepareZipPaths(prepareCtx, p.Repo, p.Commit, p.Paths)
 		if err != nil {
 			return "", nil, err
 		}
@@ -256,12 +261,15 @@ func validateParams(p *protocol.Request) error {
 	if len(p.Commit) != 40 {
 		return errors.Errorf("Commit must be resolved (Commit=%q)", p.Commit)
 	}
-	if p.Pattern == "" && p.ExcludePattern == "" && len(p.IncludePatterns) == 0 {
-		return errors.New("At least one of pattern and include/exclude pattners must be non-empty")
+	if p.Pattern == "" && p.ExcludePattern == "" && len(p.IncludePatterns) == 0 && len(p.Sizes) == 0 && len(p.Lines) == 0 && p.Binary == "" {
+		return errors.New("At least one of pattern, include/exclude pattners and file filters must be non-empty")
 	}
 	if p.IsNegated && p.IsStructuralPat {
 		return errors.New("Negated patterns are not supported for structural searches")
 	}
+	if p.CombyRewrite != "" && !p.IsStructuralPat {
+		return errors.New("Rewrite templates are only supported for structural searches")
+	}
 	return nil
 }
 
@@ -20,6 +20,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/pathmatch"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/store"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 )
@@ -57,6 +58,10 @@ type readerGrep struct {
 	// whether a file path matches (and should be searched).
 	matchPath pathmatch.PathMatcher
 
+	// fileFilters are the size, line count and binary filters which files
+	// must pass to be searched.
+	fileFilters query.FileFilters
+
 	// literalSubstring is used to test if a file is worth considering for
 	// matches. literalSubstring is guaranteed to appear in any match found by
 	// re. It is the output of the longestLiteral function. It is only set if
@@ -122,14 +127,51 @@ func compile(p *protocol.PatternInfo) (*readerGrep, error) {
 		return nil, err
 	}
 
+	fileFilters, err := compileFileFilters(p)
+	if err != nil {
+		return nil, err
+	}
+
 	return &readerGrep{
 		re:               re,
 		ignoreCase:       !p.IsCaseSensitive,
 		matchPath:        matchPath,
+		fileFilters:      fileFilters,
 		literalSubstring: literalSubstring,
 	}, nil
 }
 
+// compileFileFilters parses the size, line count and binary filters of p.
+func compileFileFilters(p *protocol.PatternInfo) (query.FileFilters, error) {
+	var f query.FileFilters
+	for _, v := range p.Sizes {
+		c, err := query.ParseSizeComparison(v)
+		if err != nil {
+			return f, err
+		}
+		f.Sizes = append(f.Sizes, c)
+	}
+	for _, v := range p.Lines {
+		c, err := query.ParseLinesComparison(v)
+		if err != nil {
+			return f, err
+		}
+		f.Lines = append(f.Lines, c)
+	}
+	switch p.Binary {
+	case "":
+	case "yes":
+		binary := true
+		f.Binary = &binary
+	case "no":
+		binary := false
+		f.Binary = &binary
+	default:
+		return f, errors.Errorf("invalid value %q for Binary", p.Binary)
+	}
+	return f, nil
+}
+
 // Copy returns a copied version of rg that is safe to use from another
 // goroutine.
 func (rg *readerGrep) Copy() *readerGrep {
@@ -137,10 +179,27 @@ func (rg *readerGrep) Copy() *readerGrep {
 		re:               rg.re,
 		ignoreCase:       rg.ignoreCase,
 		matchPath:        rg.matchPath,
+		fileFilters:      rg.fileFilters,
 		literalSubstring: rg.literalSubstring,
 	}
 }
 
+// matchFile reports whether the path of f matches the include/exclude path
+// patterns and f passes the file filters.
+func (rg *readerGrep) matchFile(zf *store.ZipFile, f *store.SrcFile) bool {
+	if !rg.matchPath.MatchPath(f.Name) {
+		return false
+	}
+	if rg.fileFilters.IsEmpty() {
+		return true
+	}
+	lines := -1
+	if f.Skipped == nil && len(rg.fileFilters.Lines) > 0 {
+		lines = query.LineCount(zf.DataFor(f))
+	}
+	return rg.fileFilters.Matches(f.Size(), lines, f.Skipped != nil && f.Skipped.Binary)
+}
+
 // matchString returns whether rg's regexp pattern matches s. It is intended to be
 // used to match file paths.
 func (rg *readerGrep) matchString(s string) bool {
@@ -337,8 +396,9 @@ func regexSearch(ctx context.Context, rg *readerGrep, zf *store.ZipFile, limit i
 	if rg.re == nil || (patternMatchesPaths && !patternMatchesContent) {
 		// Fast path for only matching file paths (or with a nil pattern, which matches all files,
 		// so is effectively matching only on file paths).
-		for _, f := range files {
-			if match := rg.matchPath.MatchPath(f.Name) && rg.matchString(f.Name); match == !isPatternNegated {
+		for i := range files {
+			f := &files[i]
+			if match := rg.matchFile(zf, f) && rg.matchString(f.Name); match == !isPatternNegated {
 				if ctx.Err() != nil {
 					return ctx.Err()
 				}
@@ -372,7 +432,7 @@ func regexSearch(ctx context.Context, rg *readerGrep, zf *store.ZipFile, limit i
 				filesmu.Unlock()
 
 				// decide whether to process, record that decision
-				if !rg.matchPath.MatchPath(f.Name) {
+				if !rg.matchFile(zf, f) {
 					filesSkipped.Inc()
 					continue
 				}
@@ -10,6 +10,7 @@ import (
 	"regexp/syntax"
 	"sort"
 	"strconv"
+	"strings"
 	"testing"
 	"testing/iotest"
 	"testing/quick"
@@ -522,6 +523,84 @@ func TestPathMatches(t *testing.T) {
 	}
 }
 
+func TestFileFilters(t *testing.T) {
+	zipData, err := testutil.CreateZip(map[string]string{
+		"small.go":   "package main\n",
+		"medium.go":  strings.Repeat("// medium\n", 100),
+		"large.js":   "",
+		"image.png":  "",
+		"no-newline": "a\nb",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	zf, err := store.MockZipFile(zipData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The store doesn't keep the contents of large and binary files.
+	for i := range zf.Files {
+		switch zf.Files[i].Name {
+		case "large.js":
+			zf.Files[i].Skipped = &store.SkippedFile{Size: 5 << 20}
+		case "image.png":
+			zf.Files[i].Skipped = &store.SkippedFile{Size: 2048, Binary: true}
+		}
+	}
+
+	cases := []struct {
+		name string
+		p    protocol.PatternInfo
+		want []string
+	}{{
+		name: "larger than",
+		p:    protocol.PatternInfo{Sizes: []string{">1KB"}},
+		want: []string{"image.png", "large.js"},
+	}, {
+		name: "size range",
+		p:    protocol.PatternInfo{Sizes: []string{">=100", "<1MB"}},
+		want: []string{"image.png", "medium.go"},
+	}, {
+		name: "lines",
+		p:    protocol.PatternInfo{Lines: []string{"<=2"}},
+		// The number of lines of large and binary files is unknown, so they
+		// may match.
+		want: []string{"image.png", "large.js", "no-newline", "small.go"},
+	}, {
+		name: "binary",
+		p:    protocol.PatternInfo{Binary: "yes"},
+		want: []string{"image.png"},
+	}, {
+		name: "not binary",
+		p:    protocol.PatternInfo{Binary: "no", Sizes: []string{">10"}},
+		want: []string{"large.js", "medium.go", "small.go"},
+	}, {
+		name: "content",
+		p:    protocol.PatternInfo{Pattern: "package|medium", IsRegExp: true, Lines: []string{">10"}},
+		want: []string{"medium.go"},
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rg, err := compile(&tc.p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fileMatches, _, err := regexSearchBatch(context.Background(), rg, zf, 10, true, true, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]string, len(fileMatches))
+			for i, fm := range fileMatches {
+				got[i] = fm.Path
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got file matches %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
 // githubStore fetches from github and caches across test runs.
 var githubStore = &store.Store{
 	FetchTar: testutil.FetchTarFromGithub,
@@ -2,6 +2,7 @@ package search
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -15,7 +16,9 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/comby"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/internal/logging"
 	"github.com/sourcegraph/sourcegraph/internal/search"
+	zoektutil "github.com/sourcegraph/sourcegraph/internal/search/zoekt"
 	"github.com/sourcegraph/sourcegraph/internal/store"
 )
 
@@ -175,6 +178,29 @@ func lookupMatcher(language string) string {
 	return ".generic"
 }
 
+// toFileDiffMatch converts the diff of a comby rewrite to a FileMatch. Comby
+// labels both sides of the diff with the bare path, so the file headers are
+// replaced with the "a/" and "b/" prefixed ones that git produces.
+func toFileDiffMatch(combyDiff comby.FileDiff) protocol.FileMatch {
+	hunks := combyDiff.Diff
+	if i := strings.Index(hunks, "@@"); i >= 0 {
+		hunks = hunks[i:]
+	}
+	if !strings.HasSuffix(hunks, "\n") {
+		hunks += "\n"
+	}
+
+	var diff strings.Builder
+	fmt.Fprintf(&diff, "--- a/%s\n+++ b/%s\n", combyDiff.URI, combyDiff.URI)
+	diff.WriteString(hunks)
+
+	return protocol.FileMatch{
+		Path:       combyDiff.URI,
+		MatchCount: 1,
+		Diff:       diff.String(),
+	}
+}
+
 // filteredStructuralSearch filters the list of files with a regex search before passing the zip to comby
 func filteredStructuralSearch(ctx context.Context, zipPath string, zipFile *store.ZipFile, p *protocol.PatternInfo, repo api.RepoName, sender *limitedStreamCollector) error {
 	// Make a copy of the pattern info to modify it to work for a regex search
@@ -202,7 +228,7 @@ func filteredStructuralSearch(ctx context.Context, zipPath string, zipFile *stor
 		extensionHint = filepath.Ext(matchedPaths[0])
 	}
 
-	return structuralSearch(ctx, zipPath, Subset(matchedPaths), extensionHint, p.Pattern, p.CombyRule, p.Languages, repo, sender)
+	return structuralSearch(ctx, zipPath, Subset(matchedPaths), extensionHint, p.Pattern, p.CombyRule, p.CombyRewrite, p.Languages, repo, sender)
 }
 
 // toMatcher returns the matcher that parameterizes structural search. It
@@ -240,8 +266,8 @@ type Subset []string
 
 var All UniversalSet = struct{}{}
 
-func structuralSearch(ctx context.Context, zipPath string, paths filePatterns, extensionHint, pattern, rule string, languages []string, repo api.RepoName, sender *limitedStreamCollector) error {
-	log15.Info("structural search", "repo", string(repo))
+func structuralSearch(ctx context.Context, zipPath string, paths filePatterns, extensionHint, pattern, rule, rewrite string, languages []string, repo api.RepoName, sender *limitedStreamCollector) error {
+	logging.FromContext(ctx).Info("structural search", "repo", string(repo))
 
 	// Cap the number of forked processes to limit the size of zip contents being mapped to memory. Resolving #7133 could help to lift this restriction.
 	numWorkers := 4
@@ -263,6 +289,22 @@ func structuralSearch(ctx context.Context, zipPath string, paths filePatterns, e
 		NumWorkers:    numWorkers,
 	}
 
+	if rewrite != "" {
+		args.RewriteTemplate = rewrite
+		combyDiffs, err := comby.Replacements(ctx, args)
+		if err != nil {
+			return err
+		}
+
+		for _, combyDiff := range combyDiffs {
+			if ctx.Err() != nil {
+				return nil
+			}
+			sender.Send(toFileDiffMatch(combyDiff))
+		}
+		return nil
+	}
+
 	combyMatches, err := comby.Matches(ctx, args)
 	if err != nil {
 		return err
@@ -294,6 +336,10 @@ func structuralSearchWithZoekt(ctx context.Context, p *protocol.Request, sender
 		PatternMatchesPath:           p.PatternMatchesPath,
 		Languages:                    p.Languages,
 	}
+	patternInfo.FileFilters, err = compileFileFilters(&p.PatternInfo)
+	if err != nil {
+		return false, badRequestError{err.Error()}
+	}
 
 	if p.Branch == "" {
 		p.Branch = "HEAD"
@@ -304,6 +350,7 @@ func structuralSearchWithZoekt(ctx context.Context, p *protocol.Request, sender
 	if err != nil {
 		return false, err
 	}
+	zoektMatches = zoektutil.FilterFileMatches(zoektMatches, patternInfo.FileFilters)
 
 	if len(zoektMatches) == 0 {
 		return false, nil
@@ -326,7 +373,7 @@ func structuralSearchWithZoekt(ctx context.Context, p *protocol.Request, sender
 		extensionHint = filepath.Ext(filename)
 	}
 
-	return false, structuralSearch(ctx, zipFile.Name(), All, extensionHint, p.Pattern, p.CombyRule, p.Languages, p.Repo, sender)
+	return false, structuralSearch(ctx, zipFile.Name(), All, extensionHint, p.Pattern, p.CombyRule, p.CombyRewrite, p.Languages, p.Repo, sender)
 }
 
 var requestTotalStructuralSearch = promauto.NewCounterVec(prometheus.CounterOpts{
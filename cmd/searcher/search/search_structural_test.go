@@ -78,7 +78,7 @@ func foo(go string) {}
 
 				ctx, cancel, sender := newLimitedStreamCollector(context.Background(), 100000000)
 				defer cancel()
-				err := structuralSearch(ctx, zf, Subset(p.IncludePatterns), "", p.Pattern, p.CombyRule, p.Languages, "repo_foo", sender)
+				err := structuralSearch(ctx, zf, Subset(p.IncludePatterns), "", p.Pattern, p.CombyRule, p.CombyRewrite, p.Languages, "repo_foo", sender)
 				if err != nil {
 					t.Fatal(err)
 				}
@@ -139,7 +139,7 @@ func foo(go.txt) {}
 		extensionHint := filepath.Ext(filename)
 		ctx, cancel, sender := newLimitedStreamCollector(context.Background(), 1000000000)
 		defer cancel()
-		err := structuralSearch(ctx, zf, All, extensionHint, "foo(:[args])", "", languages, "repo_foo", sender)
+		err := structuralSearch(ctx, zf, All, extensionHint, "foo(:[args])", "", "", languages, "repo_foo", sender)
 		if err != nil {
 			return "ERROR: " + err.Error()
 		}
@@ -340,7 +340,7 @@ func TestIncludePatterns(t *testing.T) {
 	}
 	ctx, cancel, sender := newLimitedStreamCollector(context.Background(), 1000000000)
 	defer cancel()
-	err = structuralSearch(ctx, zf, Subset(p.IncludePatterns), "", p.Pattern, p.CombyRule, p.Languages, "foo", sender)
+	err = structuralSearch(ctx, zf, Subset(p.IncludePatterns), "", p.Pattern, p.CombyRule, p.CombyRewrite, p.Languages, "foo", sender)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -384,7 +384,7 @@ func TestRule(t *testing.T) {
 
 	ctx, cancel, sender := newLimitedStreamCollector(context.Background(), 1000000000)
 	defer cancel()
-	err = structuralSearch(ctx, zf, Subset(p.IncludePatterns), "", p.Pattern, p.CombyRule, p.Languages, "repo", sender)
+	err = structuralSearch(ctx, zf, Subset(p.IncludePatterns), "", p.Pattern, p.CombyRule, p.CombyRewrite, p.Languages, "repo", sender)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -410,6 +410,70 @@ func TestRule(t *testing.T) {
 	}
 }
 
+func TestRewrite(t *testing.T) {
+	// If we are not on CI skip the test.
+	if os.Getenv("CI") == "" {
+		t.Skip("Not on CI, skipping comby-dependent test")
+	}
+
+	input := map[string]string{
+		"file.go":  "func foo(success) {}\n",
+		"other.go": "var x = 1\n",
+	}
+
+	zipData, err := testutil.CreateZip(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zf, cleanup, err := testutil.TempZipFileOnDisk(zipData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	p := &protocol.PatternInfo{
+		Pattern:         "func :[[fn]](:[args])",
+		IncludePatterns: []string{".go"},
+		CombyRewrite:    "func :[fn]_renamed(:[args])",
+	}
+
+	ctx, cancel, sender := newLimitedStreamCollector(context.Background(), 1000000000)
+	defer cancel()
+	err = structuralSearch(ctx, zf, Subset(p.IncludePatterns), "", p.Pattern, p.CombyRule, p.CombyRewrite, p.Languages, "repo", sender)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := sender.collected
+
+	want := []protocol.FileMatch{
+		{
+			Path:       "file.go",
+			MatchCount: 1,
+			Diff:       "--- a/file.go\n+++ b/file.go\n@@ -1,1 +1,1 @@\n-func foo(success) {}\n+func foo_renamed(success) {}\n",
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got file matches %v, want %v", got, want)
+	}
+}
+
+func TestToFileDiffMatch(t *testing.T) {
+	got := toFileDiffMatch(comby.FileDiff{
+		URI:  "dir/main.go",
+		Diff: "--- dir/main.go\n+++ dir/main.go\n@@ -1,2 +1,2 @@\n package main\n-func main() {}",
+	})
+
+	want := protocol.FileMatch{
+		Path:       "dir/main.go",
+		MatchCount: 1,
+		Diff:       "--- a/dir/main.go\n+++ b/dir/main.go\n@@ -1,2 +1,2 @@\n package main\n-func main() {}\n",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
 func TestStructuralLimits(t *testing.T) {
 	// If we are not on CI skip the test.
 	if os.Getenv("CI") == "" {
@@ -456,7 +520,7 @@ func bar() {
 		return func(t *testing.T) {
 			ctx, cancel, sender := newLimitedStreamCollector(context.Background(), limit)
 			defer cancel()
-			err := structuralSearch(ctx, zf, Subset(p.IncludePatterns), "", p.Pattern, p.CombyRule, p.Languages, "repo_foo", sender)
+			err := structuralSearch(ctx, zf, Subset(p.IncludePatterns), "", p.Pattern, p.CombyRule, p.CombyRewrite, p.Languages, "repo_foo", sender)
 			require.NoError(t, err)
 
 			require.Equal(t, wantCount, count(sender.collected))
@@ -599,7 +663,7 @@ func bar() {
 	t.Run("Strutural search match count", func(t *testing.T) {
 		ctx, cancel, sender := newLimitedStreamCollector(context.Background(), 1000000000)
 		defer cancel()
-		err := structuralSearch(ctx, zf, Subset(p.IncludePatterns), "", p.Pattern, p.CombyRule, p.Languages, "repo_foo", sender)
+		err := structuralSearch(ctx, zf, Subset(p.IncludePatterns), "", p.Pattern, p.CombyRule, p.CombyRewrite, p.Languages, "repo_foo", sender)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -11,7 +11,6 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 
-	"github.com/inconshreveable/log15"
 	"github.com/jmoiron/sqlx"
 	"github.com/keegancsmith/sqlf"
 	"github.com/opentracing/opentracing-go/ext"
@@ -19,6 +18,7 @@ import (
 	nettrace "golang.org/x/net/trace"
 
 	"github.com/sourcegraph/sourcegraph/cmd/symbols/internal/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/logging"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
@@ -39,7 +39,7 @@ func (s *Service) handleSearch(w http.ResponseWriter, r *http.Request) {
 		if err == context.Canceled && r.Context().Err() == context.Canceled {
 			return // client went away
 		}
-		log15.Error("Symbol search failed", "args", args, "error", err)
+		logging.FromContext(r.Context()).Error("Symbol search failed", "args", args, "error", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -55,7 +55,7 @@ func (s *Service) search(ctx context.Context, args protocol.SearchArgs) (*result
 	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
-	log15.Debug("Symbol search", "repo", args.Repo, "query", args.Query)
+	logging.FromContext(ctx).Debug("Symbol search", "repo", args.Repo, "query", args.Query)
 	span, ctx := ot.StartSpanFromContext(ctx, "search")
 	span.SetTag("repo", args.Repo)
 	span.SetTag("commitID", args.CommitID)
@@ -103,7 +103,7 @@ func (s *Service) getDBFile(ctx context.Context, args protocol.SearchArgs) (stri
 		err := s.writeAllSymbolsToNewDB(fetcherCtx, tempDBFile, args.Repo, args.CommitID)
 		if err != nil {
 			if err == context.Canceled {
-				log15.Error("Unable to parse repository symbols within the context", "repo", args.Repo, "commit", args.CommitID, "query", args.Query)
+				logging.FromContext(fetcherCtx).Error("Unable to parse repository symbols within the context", "repo", args.Repo, "commit", args.CommitID, "query", args.Query)
 			}
 			return err
 		}
@@ -144,6 +144,20 @@ mutation {
 				query: `
 mutation {
 	updateSiteConfiguration(input: "", lastID: 0)
+}`,
+			}, {
+				name: "revertSiteConfiguration",
+				query: `
+mutation {
+	revertSiteConfiguration(id: "U2l0ZUNvbmZpZ3VyYXRpb25DaGFuZ2U6MQ==")
+}`,
+			}, {
+				name: "validateSiteConfiguration",
+				query: `
+{
+	validateSiteConfiguration(input: "{}") {
+		message
+	}
 }`,
 			}, {
 				name: "deleteLSIFUpload",
@@ -168,6 +182,38 @@ mutation {
 	SetMigrationDirection(id: "TWlncmF0aW9uOjE=", applyReverse: false) {
 		alwaysNil
 	}
+}`,
+			}, {
+				name: "pauseOutOfBandMigration",
+				query: `
+mutation {
+	pauseOutOfBandMigration(id: "TWlncmF0aW9uOjE=") {
+		alwaysNil
+	}
+}`,
+			}, {
+				name: "resumeOutOfBandMigration",
+				query: `
+mutation {
+	resumeOutOfBandMigration(id: "TWlncmF0aW9uOjE=") {
+		alwaysNil
+	}
+}`,
+			}, {
+				name: "rollbackOutOfBandMigration",
+				query: `
+mutation {
+	rollbackOutOfBandMigration(id: "TWlncmF0aW9uOjE=") {
+		alwaysNil
+	}
+}`,
+			}, {
+				name: "setOutOfBandMigrationRateLimit",
+				query: `
+mutation {
+	setOutOfBandMigrationRateLimit(id: "TWlncmF0aW9uOjE=", batchSize: 10) {
+		alwaysNil
+	}
 }`,
 			}, {
 				name: "sendSavedSearchTestNotification",
@@ -46,15 +46,17 @@ func QueueOptions(db dbutil.DB, config *Config, observationContext *observation.
 func newWorkerStore(db dbutil.DB, observationContext *observation.Context) dbworkerstore.Store {
 	handle := basestore.NewHandleWithDB(db, sql.TxOptions{})
 	options := dbworkerstore.Options{
-		Name:              "precise_code_intel_index_worker_store",
-		TableName:         "lsif_indexes",
-		ViewName:          "lsif_indexes_with_repository_name u",
-		ColumnExpressions: store.IndexColumnsWithNullRank,
-		Scan:              store.ScanFirstIndexRecord,
-		OrderByExpression: sqlf.Sprintf("u.queued_at, u.id"),
-		HeartbeatInterval: HeartbeatInterval,
-		StalledMaxAge:     StalledJobMaximumAge,
-		MaxNumResets:      MaximumNumResets,
+		Name:                  "precise_code_intel_index_worker_store",
+		TableName:             "lsif_indexes",
+		ViewName:              "lsif_indexes_with_repository_name u",
+		ColumnExpressions:     store.IndexColumnsWithNullRank,
+		Scan:                  store.ScanFirstIndexRecord,
+		OrderByExpression:     sqlf.Sprintf("u.queued_at, u.id"),
+		PriorityExpression:    sqlf.Sprintf("u.state != 'errored'"),
+		FairnessKeyExpression: sqlf.Sprintf("u.repository_id"),
+		HeartbeatInterval:     HeartbeatInterval,
+		StalledMaxAge:         StalledJobMaximumAge,
+		MaxNumResets:          MaximumNumResets,
 	}
 
 	return dbworkerstore.NewWithMetrics(handle, options, observationContext)
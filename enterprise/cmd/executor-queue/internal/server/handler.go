@@ -146,24 +146,35 @@ func (m *handler) dequeue(ctx context.Context, queueName, executorName, executor
 	return job, true, nil
 }
 
-// addExecutionLogEntry calls AddExecutionLogEntry for the given job. If the job identifier
-// is not known, a false-valued flag is returned.
-func (m *handler) addExecutionLogEntry(ctx context.Context, queueName, executorName string, jobID int, entry workerutil.ExecutionLogEntry) error {
+// addExecutionLogEntry calls AddExecutionLogEntry for the given job and returns the identifier
+// of the new log entry.
+func (m *handler) addExecutionLogEntry(ctx context.Context, queueName, executorName string, jobID int, entry workerutil.ExecutionLogEntry) (int, error) {
 	queueOptions, ok := m.options.QueueOptions[queueName]
 	if !ok {
-		return ErrUnknownQueue
+		return 0, ErrUnknownQueue
 	}
 
 	_, err := m.findMeta(queueName, executorName, jobID, false)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	if err := queueOptions.Store.AddExecutionLogEntry(ctx, jobID, entry); err != nil {
+	return queueOptions.Store.AddExecutionLogEntry(ctx, jobID, entry)
+}
+
+// updateExecutionLogEntry calls UpdateExecutionLogEntry for the given job.
+func (m *handler) updateExecutionLogEntry(ctx context.Context, queueName, executorName string, jobID, entryID int, entry workerutil.ExecutionLogEntry) error {
+	queueOptions, ok := m.options.QueueOptions[queueName]
+	if !ok {
+		return ErrUnknownQueue
+	}
+
+	_, err := m.findMeta(queueName, executorName, jobID, false)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	return queueOptions.Store.UpdateExecutionLogEntry(ctx, jobID, entryID, entry)
 }
 
 // markComplete calls MarkComplete for the given job, then commits the job's transaction.
@@ -166,9 +166,14 @@ func TestAddExecutionLogEntry(t *testing.T) {
 		Command: []string{"ls", "-a"},
 		Out:     "<log payload>",
 	}
-	if err := handler.addExecutionLogEntry(context.Background(), "test_queue", "deadbeef", job.ID, entry); err != nil {
+	store.AddExecutionLogEntryFunc.SetDefaultReturn(1, nil)
+	entryID, err := handler.addExecutionLogEntry(context.Background(), "test_queue", "deadbeef", job.ID, entry)
+	if err != nil {
 		t.Fatalf("unexpected error updating log contents: %s", err)
 	}
+	if entryID != 1 {
+		t.Errorf("unexpected entry identifier. want=%d have=%d", 1, entryID)
+	}
 
 	if value := len(store.AddExecutionLogEntryFunc.History()); value != 1 {
 		t.Fatalf("unexpected number of calls to AddExecutionLogEntry. want=%d have=%d", 1, value)
@@ -190,7 +195,7 @@ func TestAddExecutionLogEntryUnknownQueue(t *testing.T) {
 		Command: []string{"ls", "-a"},
 		Out:     "<log payload>",
 	}
-	if err := handler.addExecutionLogEntry(context.Background(), "test_queue", "deadbjeef", 42, entry); err != ErrUnknownQueue {
+	if _, err := handler.addExecutionLogEntry(context.Background(), "test_queue", "deadbjeef", 42, entry); err != ErrUnknownQueue {
 		t.Fatalf("unexpected error. want=%q have=%q", ErrUnknownQueue, err)
 	}
 }
@@ -207,7 +212,70 @@ func TestAddExecutionLogEntryUnknownJob(t *testing.T) {
 		Command: []string{"ls", "-a"},
 		Out:     "<log payload>",
 	}
-	if err := handler.addExecutionLogEntry(context.Background(), "test_queue", "deadbeef", 42, entry); err != ErrUnknownJob {
+	if _, err := handler.addExecutionLogEntry(context.Background(), "test_queue", "deadbeef", 42, entry); err != ErrUnknownJob {
+		t.Fatalf("unexpected error. want=%q have=%q", ErrUnknownJob, err)
+	}
+}
+
+func TestUpdateExecutionLogEntry(t *testing.T) {
+	store := workerstoremocks.NewMockStore()
+	store.DequeueFunc.SetDefaultReturn(testRecord{ID: 42}, func() {}, true, nil)
+	recordTransformer := func(ctx context.Context, record workerutil.Record) (apiclient.Job, error) {
+		return apiclient.Job{ID: 42}, nil
+	}
+
+	options := Options{
+		QueueOptions: map[string]QueueOptions{
+			"test_queue": {Store: store, RecordTransformer: recordTransformer},
+		},
+		MaximumNumTransactions: 10,
+	}
+	handler := newHandler(options, glock.NewMockClock())
+
+	job, dequeued, err := handler.dequeue(context.Background(), "test_queue", "deadbeef", "test")
+	if err != nil {
+		t.Fatalf("unexpected error dequeueing job: %s", err)
+	}
+	if !dequeued {
+		t.Fatalf("expected a job to be dequeued")
+	}
+
+	entry := workerutil.ExecutionLogEntry{
+		Command: []string{"ls", "-a"},
+		Out:     "<log payload>",
+	}
+	if err := handler.updateExecutionLogEntry(context.Background(), "test_queue", "deadbeef", job.ID, 3, entry); err != nil {
+		t.Fatalf("unexpected error updating log contents: %s", err)
+	}
+
+	if value := len(store.UpdateExecutionLogEntryFunc.History()); value != 1 {
+		t.Fatalf("unexpected number of calls to UpdateExecutionLogEntry. want=%d have=%d", 1, value)
+	}
+	call := store.UpdateExecutionLogEntryFunc.History()[0]
+	if call.Arg1 != 42 {
+		t.Errorf("unexpected job identifier. want=%d have=%d", 42, call.Arg1)
+	}
+	if call.Arg2 != 3 {
+		t.Errorf("unexpected entry identifier. want=%d have=%d", 3, call.Arg2)
+	}
+	if diff := cmp.Diff(entry, call.Arg3); diff != "" {
+		t.Errorf("unexpected entry (-want +got):\n%s", diff)
+	}
+}
+
+func TestUpdateExecutionLogEntryUnknownJob(t *testing.T) {
+	options := Options{
+		QueueOptions: map[string]QueueOptions{
+			"test_queue": {Store: workerstoremocks.NewMockStore()},
+		},
+	}
+	handler := newHandler(options, glock.NewMockClock())
+
+	entry := workerutil.ExecutionLogEntry{
+		Command: []string{"ls", "-a"},
+		Out:     "<log payload>",
+	}
+	if err := handler.updateExecutionLogEntry(context.Background(), "test_queue", "deadbeef", 42, 3, entry); err != ErrUnknownJob {
 		t.Fatalf("unexpected error. want=%q have=%q", ErrUnknownJob, err)
 	}
 }
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"sort"
 
 	"github.com/hashicorp/go-multierror"
 )
@@ -9,12 +10,18 @@ import (
 // heartbeat will release the transaction for any job that is not confirmed to be in-progress
 // by the given executor. This method is called when the executor POSTs its in-progress job
 // identifiers to the /heartbeat route. This method returns the set of identifiers which the
-// executor erroneously claims to hold (and are sent back as a hint to stop processing).
-func (h *handler) heartbeat(ctx context.Context, executorName string, jobIDs []int) ([]int, error) {
-	unknownIDs := h.unknownJobs(executorName, jobIDs)
+// executor erroneously claims to hold and the set of identifiers of canceled jobs (which are
+// both sent back as a hint to stop processing).
+func (h *handler) heartbeat(ctx context.Context, executorName string, jobIDs []int) (unknownIDs, canceledIDs []int, err error) {
+	unknownIDs = h.unknownJobs(executorName, jobIDs)
 	deadJobs := h.pruneJobs(executorName, jobIDs)
-	err := h.requeueJobs(ctx, deadJobs)
-	return unknownIDs, err
+
+	canceledIDs, err = h.canceledJobs(ctx, executorName, jobIDs)
+	if requeueErr := h.requeueJobs(ctx, deadJobs); requeueErr != nil {
+		err = multierror.Append(err, requeueErr)
+	}
+
+	return unknownIDs, canceledIDs, err
 }
 
 // cleanup will release the transactions held by any executor that has not sent a heartbeat
@@ -64,6 +71,45 @@ func (h *handler) unknownJobs(executorName string, ids []int) []int {
 	return unknown
 }
 
+// canceledJobs returns the set of job identifiers reported by the executor which have been
+// canceled while being processed. We send these identifiers back to the executor so that it
+// stops processing them.
+func (h *handler) canceledJobs(ctx context.Context, executorName string, ids []int) ([]int, error) {
+	idMap := map[int]struct{}{}
+	for _, id := range ids {
+		idMap[id] = struct{}{}
+	}
+
+	h.m.Lock()
+	idsByQueue := map[string][]int{}
+	if executor, ok := h.executors[executorName]; ok {
+		for _, job := range executor.jobs {
+			if _, ok := idMap[job.record.RecordID()]; ok {
+				idsByQueue[job.queueName] = append(idsByQueue[job.queueName], job.record.RecordID())
+			}
+		}
+	}
+	h.m.Unlock()
+
+	canceled := make([]int, 0, len(ids))
+	for queueName, ids := range idsByQueue {
+		queueOptions, ok := h.options.QueueOptions[queueName]
+		if !ok {
+			return nil, ErrUnknownQueue
+		}
+
+		canceledIDs, err := queueOptions.Store.CanceledIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		canceled = append(canceled, canceledIDs...)
+	}
+	sort.Ints(canceled)
+
+	return canceled, nil
+}
+
 // pruneJobs updates the set of job identifiers assigned to the given executor and returns
 // any job that was known to us but not reported by the executor.
 func (h *handler) pruneJobs(executorName string, ids []int) (dead []jobMeta) {
@@ -56,58 +56,103 @@ func TestHeartbeat(t *testing.T) {
 
 	// missing all jobs, but they're less than UnreportedMaxAge
 	clock.Advance(time.Second / 2)
-	if _, err := handler.heartbeat(context.Background(), "deadbeef", []int{}); err != nil {
+	if _, _, err := handler.heartbeat(context.Background(), "deadbeef", []int{}); err != nil {
 		t.Fatalf("unexpected error performing heartbeat: %s", err)
 	}
-	if _, err := handler.heartbeat(context.Background(), "deadveal", []int{}); err != nil {
+	if _, _, err := handler.heartbeat(context.Background(), "deadveal", []int{}); err != nil {
 		t.Fatalf("unexpected error performing heartbeat: %s", err)
 	}
 	assertDoneCounts(0, 0)
 
 	// missing no jobs
 	clock.Advance(time.Minute * 2)
-	if _, err := handler.heartbeat(context.Background(), "deadbeef", []int{41, 43}); err != nil {
+	if _, _, err := handler.heartbeat(context.Background(), "deadbeef", []int{41, 43}); err != nil {
 		t.Fatalf("unexpected error performing heartbeat: %s", err)
 	}
-	if _, err := handler.heartbeat(context.Background(), "deadveal", []int{42, 44}); err != nil {
+	if _, _, err := handler.heartbeat(context.Background(), "deadveal", []int{42, 44}); err != nil {
 		t.Fatalf("unexpected error performing heartbeat: %s", err)
 	}
 	assertDoneCounts(0, 0)
 
 	// missing one deadbeef jobs
 	clock.Advance(time.Minute * 2)
-	if _, err := handler.heartbeat(context.Background(), "deadbeef", []int{41}); err != nil {
+	if _, _, err := handler.heartbeat(context.Background(), "deadbeef", []int{41}); err != nil {
 		t.Fatalf("unexpected error performing heartbeat: %s", err)
 	}
-	if _, err := handler.heartbeat(context.Background(), "deadveal", []int{42, 44}); err != nil {
+	if _, _, err := handler.heartbeat(context.Background(), "deadveal", []int{42, 44}); err != nil {
 		t.Fatalf("unexpected error performing heartbeat: %s", err)
 	}
 	assertDoneCounts(0, 1)
 
 	// missing two deadveal jobs
 	clock.Advance(time.Minute * 2)
-	if _, err := handler.heartbeat(context.Background(), "deadbeef", []int{41}); err != nil {
+	if _, _, err := handler.heartbeat(context.Background(), "deadbeef", []int{41}); err != nil {
 		t.Fatalf("unexpected error performing heartbeat: %s", err)
 	}
-	if _, err := handler.heartbeat(context.Background(), "deadveal", []int{}); err != nil {
+	if _, _, err := handler.heartbeat(context.Background(), "deadveal", []int{}); err != nil {
 		t.Fatalf("unexpected error performing heartbeat: %s", err)
 	}
 	assertDoneCounts(1, 2)
 
 	// unknown jobs
 	clock.Advance(time.Minute * 2)
-	if unknownIDs, err := handler.heartbeat(context.Background(), "deadbeef", []int{41, 43, 45}); err != nil {
+	if unknownIDs, _, err := handler.heartbeat(context.Background(), "deadbeef", []int{41, 43, 45}); err != nil {
 		t.Fatalf("unexpected error performing heartbeat: %s", err)
 	} else if diff := cmp.Diff([]int{43, 45}, unknownIDs); diff != "" {
 		t.Errorf("unexpected unknown ids (-want +got):\n%s", diff)
 	}
-	if unknownIDs, err := handler.heartbeat(context.Background(), "deadveal", []int{42, 44, 45}); err != nil {
+	if unknownIDs, _, err := handler.heartbeat(context.Background(), "deadveal", []int{42, 44, 45}); err != nil {
 		t.Fatalf("unexpected error performing heartbeat: %s", err)
 	} else if diff := cmp.Diff([]int{42, 44, 45}, unknownIDs); diff != "" {
 		t.Errorf("unexpected unknown ids (-want +got):\n%s", diff)
 	}
 }
 
+func TestHeartbeatCanceled(t *testing.T) {
+	store1 := workerstoremocks.NewMockStore()
+	store2 := workerstoremocks.NewMockStore()
+	recordTransformer := func(ctx context.Context, record workerutil.Record) (apiclient.Job, error) {
+		return apiclient.Job{ID: record.RecordID()}, nil
+	}
+
+	store1.DequeueFunc.PushReturn(testRecord{ID: 41}, func() {}, true, nil)
+	store1.DequeueFunc.PushReturn(testRecord{ID: 42}, func() {}, true, nil)
+	store2.DequeueFunc.PushReturn(testRecord{ID: 43}, func() {}, true, nil)
+	store1.CanceledIDsFunc.SetDefaultReturn([]int{42}, nil)
+	store2.CanceledIDsFunc.SetDefaultReturn([]int{43}, nil)
+
+	options := Options{
+		QueueOptions: map[string]QueueOptions{
+			"q1": {Store: store1, RecordTransformer: recordTransformer},
+			"q2": {Store: store2, RecordTransformer: recordTransformer},
+		},
+		MaximumNumTransactions: 10,
+		UnreportedMaxAge:       time.Second,
+	}
+	handler := newHandler(options, glock.NewMockClock())
+
+	_, dequeued1, _ := handler.dequeue(context.Background(), "q1", "deadbeef", "test")
+	_, dequeued2, _ := handler.dequeue(context.Background(), "q1", "deadbeef", "test")
+	_, dequeued3, _ := handler.dequeue(context.Background(), "q2", "deadbeef", "test")
+	if !dequeued1 || !dequeued2 || !dequeued3 {
+		t.Fatalf("failed to dequeue records")
+	}
+
+	_, canceledIDs, err := handler.heartbeat(context.Background(), "deadbeef", []int{41, 42, 43, 45})
+	if err != nil {
+		t.Fatalf("unexpected error performing heartbeat: %s", err)
+	}
+	if diff := cmp.Diff([]int{42, 43}, canceledIDs); diff != "" {
+		t.Errorf("unexpected canceled ids (-want +got):\n%s", diff)
+	}
+
+	if history := store1.CanceledIDsFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected number of calls to CanceledIDs (store 1). want=%d have=%d", 1, len(history))
+	} else if diff := cmp.Diff([]int{41, 42}, history[0].Arg1); diff != "" {
+		t.Errorf("unexpected ids (-want +got):\n%s", diff)
+	}
+}
+
 func TestCleanup(t *testing.T) {
 	store1 := workerstoremocks.NewMockStore()
 	store2 := workerstoremocks.NewMockStore()
@@ -143,7 +188,7 @@ func TestCleanup(t *testing.T) {
 	for i := 0; i < 6; i++ {
 		clock.Advance(time.Minute)
 
-		if _, err := handler.heartbeat(context.Background(), "deadbeef", []int{41, 43}); err != nil {
+		if _, _, err := handler.heartbeat(context.Background(), "deadbeef", []int{41, 43}); err != nil {
 			t.Fatalf("unexpected error performing heartbeat: %s", err)
 		}
 	}
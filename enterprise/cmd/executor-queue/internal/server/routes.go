@@ -22,11 +22,12 @@ func (h *handler) setupRoutes(router *mux.Router) {
 	}
 
 	routes := map[string]func(w http.ResponseWriter, r *http.Request){
-		"dequeue":              h.handleDequeue,
-		"addExecutionLogEntry": h.handleAddExecutionLogEntry,
-		"markComplete":         h.handleMarkComplete,
-		"markErrored":          h.handleMarkErrored,
-		"markFailed":           h.handleMarkFailed,
+		"dequeue":                 h.handleDequeue,
+		"addExecutionLogEntry":    h.handleAddExecutionLogEntry,
+		"updateExecutionLogEntry": h.handleUpdateExecutionLogEntry,
+		"markComplete":            h.handleMarkComplete,
+		"markErrored":             h.handleMarkErrored,
+		"markFailed":              h.handleMarkFailed,
 	}
 	for path, handler := range routes {
 		router.Path(fmt.Sprintf("/{queueName:(?:%s)}/%s", strings.Join(names, "|"), path)).Methods("POST").HandlerFunc(handler)
@@ -54,7 +55,17 @@ func (h *handler) handleAddExecutionLogEntry(w http.ResponseWriter, r *http.Requ
 	var payload apiclient.AddExecutionLogEntryRequest
 
 	h.wrapHandler(w, r, &payload, func() (int, interface{}, error) {
-		err := h.addExecutionLogEntry(r.Context(), mux.Vars(r)["queueName"], payload.ExecutorName, payload.JobID, payload.ExecutionLogEntry)
+		entryID, err := h.addExecutionLogEntry(r.Context(), mux.Vars(r)["queueName"], payload.ExecutorName, payload.JobID, payload.ExecutionLogEntry)
+		return http.StatusOK, entryID, err
+	})
+}
+
+// POST /{queueName}/updateExecutionLogEntry
+func (h *handler) handleUpdateExecutionLogEntry(w http.ResponseWriter, r *http.Request) {
+	var payload apiclient.UpdateExecutionLogEntryRequest
+
+	h.wrapHandler(w, r, &payload, func() (int, interface{}, error) {
+		err := h.updateExecutionLogEntry(r.Context(), mux.Vars(r)["queueName"], payload.ExecutorName, payload.JobID, payload.EntryID, payload.ExecutionLogEntry)
 		return http.StatusNoContent, nil, err
 	})
 }
@@ -106,8 +117,12 @@ func (h *handler) handleHeartbeat(w http.ResponseWriter, r *http.Request) {
 	var payload apiclient.HeartbeatRequest
 
 	h.wrapHandler(w, r, &payload, func() (int, interface{}, error) {
-		unknownIDs, err := h.heartbeat(r.Context(), payload.ExecutorName, payload.JobIDs)
-		return http.StatusOK, unknownIDs, err
+		unknownIDs, canceledIDs, err := h.heartbeat(r.Context(), payload.ExecutorName, payload.JobIDs)
+		if payload.Version != apiclient.ExecutorAPIVersion2 {
+			// Older executors only understand the list of unknown identifiers.
+			return http.StatusOK, unknownIDs, err
+		}
+		return http.StatusOK, apiclient.HeartbeatResponse{UnknownIDs: unknownIDs, CanceledIDs: canceledIDs}, err
 	})
 }
 
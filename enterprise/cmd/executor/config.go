@@ -1,10 +1,14 @@
 package main
 
 import (
+	"encoding/json"
 	"net/http"
+	"os"
 	"time"
 
+	"github.com/cockroachdb/errors"
 	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor/internal/apiclient"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor/internal/command"
@@ -32,6 +36,14 @@ type Config struct {
 	DisableHealthServer  bool
 	HealthServerPort     int
 	MaximumRuntimePerJob time.Duration
+
+	UseKubernetes             bool
+	KubernetesConfigPath      string
+	KubernetesNamespacePrefix string
+	KubernetesWorkspaceRoot   string
+	KubernetesWorkspaceVolume corev1.VolumeSource
+	KubernetesPollInterval    time.Duration
+	KubernetesPendingTimeout  time.Duration
 }
 
 func (c *Config) Load() {
@@ -51,6 +63,23 @@ func (c *Config) Load() {
 	c.DisableHealthServer = c.GetBool("EXECUTOR_DISABLE_HEALTHSERVER", "false", "Whether or not to disable the health server.")
 	c.HealthServerPort = c.GetInt("EXECUTOR_HEALTH_SERVER_PORT", "3192", "The port to listen on for the health server.")
 	c.MaximumRuntimePerJob = c.GetInterval("EXECUTOR_MAXIMUM_RUNTIME_PER_JOB", "30m", "The maximum wall time that can be spent on a single job.")
+	c.UseKubernetes = c.GetBool("EXECUTOR_USE_KUBERNETES", "false", "Whether to run docker steps in Kubernetes pods. Takes precedence over EXECUTOR_USE_FIRECRACKER.")
+	c.KubernetesConfigPath = c.GetOptional("EXECUTOR_KUBERNETES_CONFIG_PATH", "The path to the kubeconfig file used to connect to the cluster. The in-cluster configuration is used if not set.")
+	c.KubernetesNamespacePrefix = c.Get("EXECUTOR_KUBERNETES_NAMESPACE_PREFIX", "executor-", "The prefix of the namespaces created for each job.")
+	c.KubernetesWorkspaceRoot = c.GetOptional("EXECUTOR_KUBERNETES_WORKSPACE_ROOT", "The directory on which the workspace volume is mounted. Defaults to the temporary directory, in which job workspaces are created.")
+	c.KubernetesPollInterval = c.GetInterval("EXECUTOR_KUBERNETES_POLL_INTERVAL", "1s", "Interval between pod status requests.")
+	c.KubernetesPendingTimeout = c.GetInterval("EXECUTOR_KUBERNETES_POD_PENDING_TIMEOUT", "5m", "The maximum time a pod may wait to be scheduled and for its image to be pulled.")
+
+	workspaceVolume := c.GetOptional("EXECUTOR_KUBERNETES_WORKSPACE_VOLUME", "The JSON-encoded Kubernetes volume source holding the workspaces of jobs. Required if EXECUTOR_USE_KUBERNETES is set.")
+	if workspaceVolume != "" {
+		if err := json.Unmarshal([]byte(workspaceVolume), &c.KubernetesWorkspaceVolume); err != nil {
+			c.AddError(errors.Wrap(err, "invalid value for EXECUTOR_KUBERNETES_WORKSPACE_VOLUME"))
+		}
+	} else if c.UseKubernetes {
+		// A host path of the executor's workspace root would only be shared with pods
+		// scheduled on the node of the executor, so we don't default to one.
+		c.AddError(errors.New("EXECUTOR_KUBERNETES_WORKSPACE_VOLUME is required when EXECUTOR_USE_KUBERNETES is set"))
+	}
 }
 
 func (c *Config) APIWorkerOptions(transport http.RoundTripper) apiworker.Options {
@@ -59,6 +88,7 @@ func (c *Config) APIWorkerOptions(transport http.RoundTripper) apiworker.Options
 		HeartbeatInterval:    c.HeartbeatInterval,
 		WorkerOptions:        c.WorkerOptions(),
 		FirecrackerOptions:   c.FirecrackerOptions(),
+		KubernetesOptions:    c.KubernetesOptions(),
 		ResourceOptions:      c.ResourceOptions(),
 		MaximumRuntimePerJob: c.MaximumRuntimePerJob,
 		GitServicePath:       "/.executors/git",
@@ -88,6 +118,23 @@ func (c *Config) FirecrackerOptions() command.FirecrackerOptions {
 	}
 }
 
+func (c *Config) KubernetesOptions() command.KubernetesOptions {
+	workspaceRoot := c.KubernetesWorkspaceRoot
+	if workspaceRoot == "" {
+		workspaceRoot = os.TempDir()
+	}
+
+	return command.KubernetesOptions{
+		Enabled:         c.UseKubernetes,
+		ConfigPath:      c.KubernetesConfigPath,
+		NamespacePrefix: c.KubernetesNamespacePrefix,
+		WorkspaceRoot:   workspaceRoot,
+		WorkspaceVolume: c.KubernetesWorkspaceVolume,
+		PollInterval:    c.KubernetesPollInterval,
+		PendingTimeout:  c.KubernetesPendingTimeout,
+	}
+}
+
 func (c *Config) ResourceOptions() command.ResourceOptions {
 	return command.ResourceOptions{
 		NumCPUs:   c.FirecrackerNumCPUs,
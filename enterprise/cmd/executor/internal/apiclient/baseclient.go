@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
@@ -78,12 +79,27 @@ func (c *BaseClient) Do(ctx context.Context, req *http.Request) (hasContent bool
 			return false, nil, nil
 		}
 
-		return false, nil, errors.Errorf("unexpected status code %d", resp.StatusCode)
+		return false, nil, &unexpectedStatusCodeError{statusCode: resp.StatusCode}
 	}
 
 	return true, resp.Body, nil
 }
 
+type unexpectedStatusCodeError struct {
+	statusCode int
+}
+
+func (e *unexpectedStatusCodeError) Error() string {
+	return fmt.Sprintf("unexpected status code %d", e.statusCode)
+}
+
+// IsNotFound returns true if the given error was caused by a 404 response, e.g. because
+// the API does not know the requested route.
+func IsNotFound(err error) bool {
+	var e *unexpectedStatusCodeError
+	return errors.As(err, &e) && e.statusCode == http.StatusNotFound
+}
+
 // DoAndDecode performs the given HTTP request and unmarshals the response body into the
 // given interface pointer. If the response body was empty due to a 204 response, then a
 // false-valued flag is returned.
@@ -1,7 +1,9 @@
 package apiclient
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -76,7 +78,7 @@ func (c *Client) Dequeue(ctx context.Context, queueName string, job *executor.Jo
 	return c.client.DoAndDecode(ctx, req, &job)
 }
 
-func (c *Client) AddExecutionLogEntry(ctx context.Context, queueName string, jobID int, entry workerutil.ExecutionLogEntry) (err error) {
+func (c *Client) AddExecutionLogEntry(ctx context.Context, queueName string, jobID int, entry workerutil.ExecutionLogEntry) (entryID int, err error) {
 	ctx, endObservation := c.operations.addExecutionLogEntry.With(ctx, &err, observation.Args{LogFields: []log.Field{
 		log.String("queueName", queueName),
 		log.Int("jobID", jobID),
@@ -88,6 +90,28 @@ func (c *Client) AddExecutionLogEntry(ctx context.Context, queueName string, job
 		JobID:             jobID,
 		ExecutionLogEntry: entry,
 	})
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = c.client.DoAndDecode(ctx, req, &entryID)
+	return entryID, err
+}
+
+func (c *Client) UpdateExecutionLogEntry(ctx context.Context, queueName string, jobID, entryID int, entry workerutil.ExecutionLogEntry) (err error) {
+	ctx, endObservation := c.operations.updateExecutionLogEntry.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.String("queueName", queueName),
+		log.Int("jobID", jobID),
+		log.Int("entryID", entryID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	req, err := c.makeRequest("POST", fmt.Sprintf("%s/updateExecutionLogEntry", queueName), executor.UpdateExecutionLogEntryRequest{
+		ExecutorName:      c.options.ExecutorName,
+		JobID:             jobID,
+		EntryID:           entryID,
+		ExecutionLogEntry: entry,
+	})
 	if err != nil {
 		return err
 	}
@@ -162,25 +186,41 @@ func (c *Client) Ping(ctx context.Context, jobIDs []int) (err error) {
 	return c.client.DoAndDrop(ctx, req)
 }
 
-func (c *Client) Heartbeat(ctx context.Context, jobIDs []int) (unknownIDs []int, err error) {
+func (c *Client) Heartbeat(ctx context.Context, jobIDs []int) (unknownIDs, canceledIDs []int, err error) {
 	ctx, endObservation := c.operations.heartbeat.With(ctx, &err, observation.Args{LogFields: []log.Field{
 		log.String("jobIDs", intsToString(jobIDs)),
 	}})
 	defer endObservation(1, observation.Args{})
 
 	req, err := c.makeRequest("POST", "heartbeat", executor.HeartbeatRequest{
+		Version:      executor.ExecutorAPIVersion2,
 		ExecutorName: c.options.ExecutorName,
 		JobIDs:       jobIDs,
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	if _, err := c.client.DoAndDecode(ctx, req, &unknownIDs); err != nil {
-		return nil, err
+	var raw json.RawMessage
+	if decoded, err := c.client.DoAndDecode(ctx, req, &raw); err != nil || !decoded {
+		return nil, nil, err
+	}
+
+	// Executor queues which don't know about version 2 respond with the list of
+	// unknown identifiers only.
+	if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+		if err := json.Unmarshal(raw, &unknownIDs); err != nil {
+			return nil, nil, err
+		}
+		return unknownIDs, nil, nil
+	}
+
+	var response executor.HeartbeatResponse
+	if err := json.Unmarshal(raw, &response); err != nil {
+		return nil, nil, err
 	}
 
-	return unknownIDs, nil
+	return response.UnknownIDs, response.CanceledIDs, nil
 }
 
 func (c *Client) makeRequest(method, path string, payload interface{}) (*http.Request, error) {
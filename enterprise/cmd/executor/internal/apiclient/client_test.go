@@ -87,9 +87,9 @@ func TestAddExecutionLogEntry(t *testing.T) {
 		Key:        "foo",
 		Command:    []string{"ls", "-a"},
 		StartTime:  time.Unix(1587396557, 0).UTC(),
-		ExitCode:   123,
+		ExitCode:   intPtr(123),
 		Out:        "<log payload>",
-		DurationMs: 23123,
+		DurationMs: intPtr(23123),
 	}
 
 	spec := routeSpec{
@@ -107,14 +107,18 @@ func TestAddExecutionLogEntry(t *testing.T) {
 			"out": "<log payload>",
 			"durationMs": 23123
 		}`,
-		responseStatus:  http.StatusNoContent,
-		responsePayload: ``,
+		responseStatus:  http.StatusOK,
+		responsePayload: `99`,
 	}
 
 	testRoute(t, spec, func(client *Client) {
-		if err := client.AddExecutionLogEntry(context.Background(), "test_queue", 42, entry); err != nil {
+		entryID, err := client.AddExecutionLogEntry(context.Background(), "test_queue", 42, entry)
+		if err != nil {
 			t.Fatalf("unexpected error updating log contents: %s", err)
 		}
+		if entryID != 99 {
+			t.Errorf("unexpected entry id. want=%d have=%d", 99, entryID)
+		}
 	})
 }
 
@@ -123,9 +127,9 @@ func TestAddExecutionLogEntryBadResponse(t *testing.T) {
 		Key:        "foo",
 		Command:    []string{"ls", "-a"},
 		StartTime:  time.Unix(1587396557, 0).UTC(),
-		ExitCode:   123,
+		ExitCode:   intPtr(123),
 		Out:        "<log payload>",
-		DurationMs: 23123,
+		DurationMs: intPtr(23123),
 	}
 
 	spec := routeSpec{
@@ -148,12 +152,76 @@ func TestAddExecutionLogEntryBadResponse(t *testing.T) {
 	}
 
 	testRoute(t, spec, func(client *Client) {
-		if err := client.AddExecutionLogEntry(context.Background(), "test_queue", 42, entry); err == nil {
+		if _, err := client.AddExecutionLogEntry(context.Background(), "test_queue", 42, entry); err == nil {
 			t.Fatalf("expected an error")
 		}
 	})
 }
 
+func TestUpdateExecutionLogEntry(t *testing.T) {
+	entry := workerutil.ExecutionLogEntry{
+		Key:       "foo",
+		Command:   []string{"ls", "-a"},
+		StartTime: time.Unix(1587396557, 0).UTC(),
+		Out:       "<log payload>",
+	}
+
+	spec := routeSpec{
+		expectedMethod:   "POST",
+		expectedPath:     "/.executors/queue/test_queue/updateExecutionLogEntry",
+		expectedUsername: "test",
+		expectedPassword: "hunter2",
+		expectedPayload: `{
+			"executorName": "deadbeef",
+			"jobId": 42,
+			"entryId": 3,
+			"key": "foo",
+			"command": ["ls", "-a"],
+			"startTime": "2020-04-20T15:29:17Z",
+			"exitCode": null,
+			"out": "<log payload>",
+			"durationMs": null
+		}`,
+		responseStatus:  http.StatusNoContent,
+		responsePayload: ``,
+	}
+
+	testRoute(t, spec, func(client *Client) {
+		if err := client.UpdateExecutionLogEntry(context.Background(), "test_queue", 42, 3, entry); err != nil {
+			t.Fatalf("unexpected error updating log contents: %s", err)
+		}
+	})
+}
+
+func TestUpdateExecutionLogEntryNotFound(t *testing.T) {
+	spec := routeSpec{
+		expectedMethod:   "POST",
+		expectedPath:     "/.executors/queue/test_queue/updateExecutionLogEntry",
+		expectedUsername: "test",
+		expectedPassword: "hunter2",
+		expectedPayload: `{
+			"executorName": "deadbeef",
+			"jobId": 42,
+			"entryId": 3,
+			"key": "",
+			"command": null,
+			"startTime": "0001-01-01T00:00:00Z",
+			"exitCode": null,
+			"out": "",
+			"durationMs": null
+		}`,
+		responseStatus:  http.StatusNotFound,
+		responsePayload: ``,
+	}
+
+	testRoute(t, spec, func(client *Client) {
+		err := client.UpdateExecutionLogEntry(context.Background(), "test_queue", 42, 3, workerutil.ExecutionLogEntry{})
+		if !IsNotFound(err) {
+			t.Fatalf("expected not found error, got %v", err)
+		}
+	})
+}
+
 func TestMarkComplete(t *testing.T) {
 	spec := routeSpec{
 		expectedMethod:   "POST",
@@ -250,13 +318,39 @@ func TestHeartbeat(t *testing.T) {
 		expectedPath:     "/.executors/queue/heartbeat",
 		expectedUsername: "test",
 		expectedPassword: "hunter2",
-		expectedPayload:  `{"executorName": "deadbeef", "jobIds": [1, 2, 3]}`,
+		expectedPayload:  `{"version": "V2", "executorName": "deadbeef", "jobIds": [1, 2, 3]}`,
+		responseStatus:   http.StatusOK,
+		responsePayload:  `{"unknownIds": [1], "canceledIds": [2]}`,
+	}
+
+	testRoute(t, spec, func(client *Client) {
+		unknownIDs, canceledIDs, err := client.Heartbeat(context.Background(), []int{1, 2, 3})
+		if err != nil {
+			t.Fatalf("unexpected error performing heartbeat: %s", err)
+		}
+
+		if diff := cmp.Diff([]int{1}, unknownIDs); diff != "" {
+			t.Errorf("unexpected unknown ids (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]int{2}, canceledIDs); diff != "" {
+			t.Errorf("unexpected canceled ids (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestHeartbeatV1Response(t *testing.T) {
+	spec := routeSpec{
+		expectedMethod:   "POST",
+		expectedPath:     "/.executors/queue/heartbeat",
+		expectedUsername: "test",
+		expectedPassword: "hunter2",
+		expectedPayload:  `{"version": "V2", "executorName": "deadbeef", "jobIds": [1, 2, 3]}`,
 		responseStatus:   http.StatusOK,
 		responsePayload:  `[1]`,
 	}
 
 	testRoute(t, spec, func(client *Client) {
-		unknownIDs, err := client.Heartbeat(context.Background(), []int{1, 2, 3})
+		unknownIDs, canceledIDs, err := client.Heartbeat(context.Background(), []int{1, 2, 3})
 		if err != nil {
 			t.Fatalf("unexpected error performing heartbeat: %s", err)
 		}
@@ -264,6 +358,9 @@ func TestHeartbeat(t *testing.T) {
 		if diff := cmp.Diff([]int{1}, unknownIDs); diff != "" {
 			t.Errorf("unexpected unknown ids (-want +got):\n%s", diff)
 		}
+		if len(canceledIDs) != 0 {
+			t.Errorf("unexpected canceled ids: %v", canceledIDs)
+		}
 	})
 }
 
@@ -273,13 +370,13 @@ func TestHeartbeatBadResponse(t *testing.T) {
 		expectedPath:     "/.executors/queue/heartbeat",
 		expectedUsername: "test",
 		expectedPassword: "hunter2",
-		expectedPayload:  `{"executorName": "deadbeef", "jobIds": [1, 2, 3]}`,
+		expectedPayload:  `{"version": "V2", "executorName": "deadbeef", "jobIds": [1, 2, 3]}`,
 		responseStatus:   http.StatusInternalServerError,
 		responsePayload:  ``,
 	}
 
 	testRoute(t, spec, func(client *Client) {
-		if _, err := client.Heartbeat(context.Background(), []int{1, 2, 3}); err == nil {
+		if _, _, err := client.Heartbeat(context.Background(), []int{1, 2, 3}); err == nil {
 			t.Fatalf("expected an error")
 		}
 	})
@@ -349,3 +446,5 @@ func normalizeJSON(v []byte) string {
 	v, _ = json.Marshal(temp)
 	return string(v)
 }
+
+func intPtr(v int) *int { return &v }
@@ -8,12 +8,13 @@ import (
 )
 
 type operations struct {
-	dequeue              *observation.Operation
-	addExecutionLogEntry *observation.Operation
-	markComplete         *observation.Operation
-	markErrored          *observation.Operation
-	markFailed           *observation.Operation
-	heartbeat            *observation.Operation
+	dequeue                 *observation.Operation
+	addExecutionLogEntry    *observation.Operation
+	updateExecutionLogEntry *observation.Operation
+	markComplete            *observation.Operation
+	markErrored             *observation.Operation
+	markFailed              *observation.Operation
+	heartbeat               *observation.Operation
 }
 
 func newOperations(observationContext *observation.Context) *operations {
@@ -33,11 +34,12 @@ func newOperations(observationContext *observation.Context) *operations {
 	}
 
 	return &operations{
-		dequeue:              op("Dequeue"),
-		addExecutionLogEntry: op("AddExecutionLogEntry"),
-		markComplete:         op("MarkComplete"),
-		markErrored:          op("MarkErrored"),
-		markFailed:           op("MarkFailed"),
-		heartbeat:            op("Heartbeat"),
+		dequeue:                 op("Dequeue"),
+		addExecutionLogEntry:    op("AddExecutionLogEntry"),
+		updateExecutionLogEntry: op("UpdateExecutionLogEntry"),
+		markComplete:            op("MarkComplete"),
+		markErrored:             op("MarkErrored"),
+		markFailed:              op("MarkFailed"),
+		heartbeat:               op("Heartbeat"),
 	}
 }
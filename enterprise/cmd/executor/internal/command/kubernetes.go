@@ -0,0 +1,408 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+type KubernetesOptions struct {
+	// Enabled determines if docker steps will be run in Kubernetes pods.
+	Enabled bool
+
+	// ConfigPath is the path to a kubeconfig file used to connect to the cluster. If
+	// empty, the in-cluster configuration of the executor pod is used.
+	ConfigPath string
+
+	// NamespacePrefix is prepended to the executor name to build the name of the
+	// namespace created for each job.
+	NamespacePrefix string
+
+	// WorkspaceRoot is the directory of the executor under which job workspaces are
+	// created. It must be the mount point of WorkspaceVolume in the executor.
+	WorkspaceRoot string
+
+	// WorkspaceVolume is the volume holding the job workspaces. It is mounted into
+	// each pod, so it must be accessible from every node pods are scheduled on.
+	WorkspaceVolume corev1.VolumeSource
+
+	// PollInterval is the interval at which the state of running pods is checked.
+	PollInterval time.Duration
+
+	// PendingTimeout is the maximum time a pod may wait to be scheduled and for its
+	// image to be pulled before the command fails.
+	PendingTimeout time.Duration
+
+	// Client is the client used to talk to the Kubernetes API.
+	Client kubernetes.Interface
+}
+
+// NewKubernetesClient creates a client for the cluster configured in the given options.
+func NewKubernetesClient(options KubernetesOptions) (kubernetes.Interface, error) {
+	var (
+		config *rest.Config
+		err    error
+	)
+	if options.ConfigPath != "" {
+		config, err = clientcmd.BuildConfigFromFlags("", options.ConfigPath)
+	} else {
+		config, err = rest.InClusterConfig()
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load kubernetes configuration")
+	}
+
+	return kubernetes.NewForConfig(config)
+}
+
+const (
+	kubernetesWorkspaceVolumeName = "workspace"
+	kubernetesContainerName       = "step"
+	kubernetesManagedByLabel      = "app.kubernetes.io/managed-by"
+	kubernetesManagedByValue      = "sourcegraph-executor"
+)
+
+// kubernetesStartFailureReasons are the reasons for which a container waits to start
+// that won't resolve by themselves, and fail the command immediately.
+var kubernetesStartFailureReasons = map[string]struct{}{
+	"ErrImagePull":               {},
+	"ImagePullBackOff":           {},
+	"InvalidImageName":           {},
+	"CreateContainerConfigError": {},
+	"CreateContainerError":       {},
+}
+
+type kubernetesRunner struct {
+	name       string
+	dir        string
+	logger     *Logger
+	options    Options
+	operations *Operations
+}
+
+var _ Runner = &kubernetesRunner{}
+
+// Setup creates the namespace in which the pods of the job will run.
+func (r *kubernetesRunner) Setup(ctx context.Context, imageNames, scriptPaths []string) (err error) {
+	if _, err := r.workspaceSubPath(); err != nil {
+		return err
+	}
+
+	ctx, endObservation := r.operations.SetupKubernetesNamespace.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	handle := r.logger.Log("setup.kubernetes.namespace", []string{"create", "namespace", r.namespace()})
+	_, err = r.options.KubernetesOptions.Client.CoreV1().Namespaces().Create(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   r.namespace(),
+			Labels: map[string]string{kubernetesManagedByLabel: kubernetesManagedByValue},
+		},
+	})
+	finalizeKubernetesHandle(handle, err)
+	return errors.Wrap(err, "failed to create kubernetes namespace")
+}
+
+// Teardown deletes the namespace of the job, along with all of its pods.
+func (r *kubernetesRunner) Teardown(ctx context.Context) (err error) {
+	ctx, endObservation := r.operations.TeardownKubernetesNamespace.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	propagationPolicy := metav1.DeletePropagationBackground
+	handle := r.logger.Log("teardown.kubernetes.namespace", []string{"delete", "namespace", r.namespace()})
+	err = r.options.KubernetesOptions.Client.CoreV1().Namespaces().Delete(r.namespace(), &metav1.DeleteOptions{
+		PropagationPolicy: &propagationPolicy,
+	})
+	finalizeKubernetesHandle(handle, err)
+	if err != nil {
+		log15.Warn("Failed to delete kubernetes namespace", "namespace", r.namespace(), "err", err)
+	}
+
+	return nil
+}
+
+// Run invokes the given command. Commands with an image are run in a pod in the namespace
+// of the job, and all other commands are run directly on the host.
+func (r *kubernetesRunner) Run(ctx context.Context, spec CommandSpec) (err error) {
+	if spec.Image == "" {
+		return runCommand(ctx, formatRawOrDockerCommand(spec, r.dir, r.options), r.logger)
+	}
+
+	ctx, endObservation := spec.Operation.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	pod, err := r.makePod(spec)
+	if err != nil {
+		return err
+	}
+
+	container := pod.Spec.Containers[0]
+	handle := r.logger.Log(spec.Key, flatten("run", "pod", pod.Name, "--namespace", r.namespace(), "--image", container.Image, "--", container.Command))
+	exitCode, err := r.runPod(ctx, pod, handle)
+	handle.Finalize(exitCode)
+
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		return errors.New("command failed")
+	}
+	return nil
+}
+
+// runPod creates the given pod and writes its output to the given handle until its
+// container terminates. The exit code of the container is returned.
+func (r *kubernetesRunner) runPod(ctx context.Context, pod *corev1.Pod, handle *entryHandle) (int, error) {
+	pods := r.options.KubernetesOptions.Client.CoreV1().Pods(r.namespace())
+
+	if _, err := pods.Create(pod); err != nil {
+		return -1, errors.Wrap(err, "failed to create pod")
+	}
+
+	// Logs can only be streamed once the container started
+	if err := r.waitForPodStart(ctx, pod.Name); err != nil {
+		return -1, err
+	}
+
+	stream, err := pods.GetLogs(pod.Name, &corev1.PodLogOptions{Container: kubernetesContainerName, Follow: true}).Context(ctx).Stream()
+	if err != nil {
+		return -1, errors.Wrap(err, "failed to stream pod logs")
+	}
+	defer stream.Close()
+
+	// The logs of a pod interleave the standard output and error streams of its
+	// container, so we can't tell them apart.
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		fmt.Fprintf(handle, "stdout: %s\n", scanner.Text())
+	}
+
+	terminated, err := r.waitForPod(ctx, pod.Name, func(pod *corev1.Pod) (bool, error) {
+		return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed, nil
+	})
+	if err != nil {
+		return -1, err
+	}
+
+	for _, status := range terminated.Status.ContainerStatuses {
+		if status.Name == kubernetesContainerName && status.State.Terminated != nil {
+			return int(status.State.Terminated.ExitCode), nil
+		}
+	}
+	if terminated.Status.Phase == corev1.PodSucceeded {
+		return 0, nil
+	}
+	return -1, errors.Errorf("pod %s failed: %s", pod.Name, terminated.Status.Message)
+}
+
+// waitForPodStart waits until the pod with the given name is no longer pending. It fails
+// if the container of the pod can't be started, or if the pod is still pending after the
+// configured pending timeout, for example because it can't be scheduled.
+func (r *kubernetesRunner) waitForPodStart(ctx context.Context, name string) error {
+	pendingCtx, cancel := context.WithTimeout(ctx, r.options.KubernetesOptions.PendingTimeout)
+	defer cancel()
+
+	var pending *corev1.Pod
+	_, err := r.waitForPod(pendingCtx, name, func(pod *corev1.Pod) (bool, error) {
+		if pod.Status.Phase != corev1.PodPending {
+			return true, nil
+		}
+
+		pending = pod
+		return false, kubernetesStartError(pod)
+	})
+	if err != nil && ctx.Err() == nil && pendingCtx.Err() == context.DeadlineExceeded {
+		message := "unknown reason"
+		if pending != nil {
+			message = kubernetesPendingReason(pending)
+		}
+		return errors.Errorf("pod %s did not start within %s: %s", name, r.options.KubernetesOptions.PendingTimeout, message)
+	}
+	return err
+}
+
+// kubernetesStartError returns an error if the container of the given pending pod can't
+// be started, for example because its image can't be pulled.
+func kubernetesStartError(pod *corev1.Pod) error {
+	for _, status := range pod.Status.ContainerStatuses {
+		if waiting := status.State.Waiting; waiting != nil {
+			if _, ok := kubernetesStartFailureReasons[waiting.Reason]; ok {
+				return errors.Errorf("pod %s failed to start: %s: %s", pod.Name, waiting.Reason, waiting.Message)
+			}
+		}
+	}
+
+	return nil
+}
+
+// kubernetesPendingReason describes why the given pod is still pending.
+func kubernetesPendingReason(pod *corev1.Pod) string {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse {
+			return fmt.Sprintf("%s: %s", condition.Reason, condition.Message)
+		}
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if waiting := status.State.Waiting; waiting != nil {
+			return fmt.Sprintf("%s: %s", waiting.Reason, waiting.Message)
+		}
+	}
+
+	return "pod is pending"
+}
+
+// waitForPod polls the pod with the given name until the given condition holds, or
+// returns an error.
+func (r *kubernetesRunner) waitForPod(ctx context.Context, name string, condition func(pod *corev1.Pod) (bool, error)) (*corev1.Pod, error) {
+	for {
+		pod, err := r.options.KubernetesOptions.Client.CoreV1().Pods(r.namespace()).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get pod")
+		}
+		if ok, err := condition(pod); err != nil || ok {
+			return pod, err
+		}
+
+		select {
+		case <-time.After(r.options.KubernetesOptions.PollInterval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// makePod returns the pod running the given command. The workspace of the job is mounted
+// at /data, like it is in docker containers run by the other runners.
+func (r *kubernetesRunner) makePod(spec CommandSpec) (*corev1.Pod, error) {
+	subPath, err := r.workspaceSubPath()
+	if err != nil {
+		return nil, err
+	}
+
+	resources, err := kubernetesResources(r.options.ResourceOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	env := make([]corev1.EnvVar, 0, len(spec.Env))
+	for _, e := range spec.Env {
+		parts := strings.SplitN(e, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid environment variable %q", e)
+		}
+		env = append(env, corev1.EnvVar{Name: parts[0], Value: parts[1]})
+	}
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kubernetesPodName(spec.Key),
+			Namespace: r.namespace(),
+			Labels:    map[string]string{kubernetesManagedByLabel: kubernetesManagedByValue},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{
+				{
+					Name:       kubernetesContainerName,
+					Image:      spec.Image,
+					Command:    []string{"/bin/sh", filepath.Join("/data", ScriptsPath, spec.ScriptPath)},
+					WorkingDir: filepath.Join("/data", spec.Dir),
+					Env:        env,
+					Resources:  resources,
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: kubernetesWorkspaceVolumeName, MountPath: "/data", SubPath: subPath},
+					},
+				},
+			},
+			Volumes: []corev1.Volume{
+				{Name: kubernetesWorkspaceVolumeName, VolumeSource: r.options.KubernetesOptions.WorkspaceVolume},
+			},
+		},
+	}, nil
+}
+
+// namespace returns the name of the namespace created for the job.
+func (r *kubernetesRunner) namespace() string {
+	return r.options.KubernetesOptions.NamespacePrefix + r.name
+}
+
+// workspaceSubPath returns the path of the job workspace relative to the root of the
+// workspace volume.
+func (r *kubernetesRunner) workspaceSubPath() (string, error) {
+	subPath, err := filepath.Rel(r.options.KubernetesOptions.WorkspaceRoot, r.dir)
+	if err != nil || subPath == ".." || strings.HasPrefix(subPath, "../") {
+		return "", errors.Errorf("workspace %q is not in the kubernetes workspace root %q", r.dir, r.options.KubernetesOptions.WorkspaceRoot)
+	}
+
+	return subPath, nil
+}
+
+// kubernetesResources converts the given resource options into the requests and limits of
+// a container. The requests are equal to the limits so that pods of a job get the resources
+// they would get in a docker container or a virtual machine.
+func kubernetesResources(options ResourceOptions) (corev1.ResourceRequirements, error) {
+	resources := corev1.ResourceList{}
+	if options.NumCPUs != 0 {
+		resources[corev1.ResourceCPU] = *resource.NewQuantity(int64(options.NumCPUs), resource.DecimalSI)
+	}
+
+	for name, value := range map[corev1.ResourceName]string{
+		corev1.ResourceMemory:           options.Memory,
+		corev1.ResourceEphemeralStorage: options.DiskSpace,
+	} {
+		if value == "" {
+			continue
+		}
+
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return corev1.ResourceRequirements{}, errors.Wrapf(err, "invalid %s quantity %q", name, value)
+		}
+		resources[name] = quantity
+	}
+
+	return corev1.ResourceRequirements{Requests: resources, Limits: resources}, nil
+}
+
+// kubernetesPodName converts the given command key into a valid pod name.
+func kubernetesPodName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		if r >= 'A' && r <= 'Z' {
+			return r - 'A' + 'a'
+		}
+		return '-'
+	}, key)
+
+	if len(name) > 63 {
+		name = name[:63]
+	}
+	return strings.Trim(name, "-")
+}
+
+// finalizeKubernetesHandle finalizes the log entry of a Kubernetes API call, recording
+// the error of the call if there is one.
+func finalizeKubernetesHandle(handle *entryHandle, err error) {
+	exitCode := 0
+	if err != nil {
+		fmt.Fprintf(handle, "stderr: %s\n", err)
+		exitCode = 1
+	}
+
+	handle.Finalize(exitCode)
+}
@@ -0,0 +1,370 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+)
+
+func TestKubernetesRunner(t *testing.T) {
+	api := newFakeKubernetesAPI(t, 0, "hello\nworld\n")
+	store := newTestExecutionLogEntryStore()
+	runner := newTestKubernetesRunner(t, api, store)
+
+	if err := runner.Setup(context.Background(), []string{"alpine:latest"}, nil); err != nil {
+		t.Fatalf("unexpected error setting up runner: %s", err)
+	}
+
+	spec := CommandSpec{
+		Key:        "step.docker.0",
+		Image:      "alpine:latest",
+		ScriptPath: "step.0.sh",
+		Dir:        "sub",
+		Env:        []string{"FOO=bar=baz"},
+		Operation:  makeTestOperation(),
+	}
+	if err := runner.Run(context.Background(), spec); err != nil {
+		t.Fatalf("unexpected error running command: %s", err)
+	}
+
+	if err := runner.Teardown(context.Background()); err != nil {
+		t.Fatalf("unexpected error tearing down runner: %s", err)
+	}
+	runner.logger.Flush()
+
+	if diff := cmp.Diff([]string{"executor-deadbeef"}, api.createdNamespaces); diff != "" {
+		t.Errorf("unexpected created namespaces (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"executor-deadbeef"}, api.deletedNamespaces); diff != "" {
+		t.Errorf("unexpected deleted namespaces (-want +got):\n%s", diff)
+	}
+
+	if len(api.createdPods) != 1 {
+		t.Fatalf("unexpected number of created pods. want=%d have=%d", 1, len(api.createdPods))
+	}
+	pod := api.createdPods[0]
+	if pod.Name != "step-docker-0" || pod.Namespace != "executor-deadbeef" {
+		t.Errorf("unexpected pod. want=%s/%s have=%s/%s", "executor-deadbeef", "step-docker-0", pod.Namespace, pod.Name)
+	}
+	if pod.Spec.RestartPolicy != corev1.RestartPolicyNever {
+		t.Errorf("unexpected restart policy. want=%s have=%s", corev1.RestartPolicyNever, pod.Spec.RestartPolicy)
+	}
+
+	quantities := corev1.ResourceList{
+		corev1.ResourceCPU:              resource.MustParse("4"),
+		corev1.ResourceMemory:           resource.MustParse("12G"),
+		corev1.ResourceEphemeralStorage: resource.MustParse("20G"),
+	}
+	expectedContainer := corev1.Container{
+		Name:       "step",
+		Image:      "alpine:latest",
+		Command:    []string{"/bin/sh", "/data/.sourcegraph-executor/step.0.sh"},
+		WorkingDir: "/data/sub",
+		Env:        []corev1.EnvVar{{Name: "FOO", Value: "bar=baz"}},
+		Resources:  corev1.ResourceRequirements{Requests: quantities, Limits: quantities},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "workspace", MountPath: "/data", SubPath: "job-42"},
+		},
+	}
+	if len(pod.Spec.Containers) != 1 {
+		t.Fatalf("unexpected number of containers. want=%d have=%d", 1, len(pod.Spec.Containers))
+	}
+	if diff := cmp.Diff(expectedContainer, pod.Spec.Containers[0], cmp.Comparer(func(x, y resource.Quantity) bool { return x.Cmp(y) == 0 })); diff != "" {
+		t.Errorf("unexpected container (-want +got):\n%s", diff)
+	}
+
+	expectedVolumes := []corev1.Volume{
+		{Name: "workspace", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/workspaces"}}},
+	}
+	if diff := cmp.Diff(expectedVolumes, pod.Spec.Volumes); diff != "" {
+		t.Errorf("unexpected volumes (-want +got):\n%s", diff)
+	}
+
+	entry := logEntry(t, store, "step.docker.0")
+	if entry.ExitCode == nil || *entry.ExitCode != 0 {
+		t.Errorf("unexpected exit code. want=%d have=%v", 0, entry.ExitCode)
+	}
+	if expected := "stdout: hello\nstdout: world\n"; entry.Out != expected {
+		t.Errorf("unexpected output. want=%q have=%q", expected, entry.Out)
+	}
+}
+
+func TestKubernetesRunnerFailedPod(t *testing.T) {
+	api := newFakeKubernetesAPI(t, 3, "oops\n")
+	store := newTestExecutionLogEntryStore()
+	runner := newTestKubernetesRunner(t, api, store)
+
+	spec := CommandSpec{
+		Key:        "step.docker.0",
+		Image:      "alpine:latest",
+		ScriptPath: "step.0.sh",
+		Operation:  makeTestOperation(),
+	}
+	if err := runner.Run(context.Background(), spec); err == nil {
+		t.Fatalf("expected an error")
+	}
+	runner.logger.Flush()
+
+	entry := logEntry(t, store, "step.docker.0")
+	if entry.ExitCode == nil || *entry.ExitCode != 3 {
+		t.Errorf("unexpected exit code. want=%d have=%v", 3, entry.ExitCode)
+	}
+}
+
+func TestKubernetesRunnerImagePullError(t *testing.T) {
+	api := newFakeKubernetesAPI(t, 0, "")
+	api.pendingStatus = &corev1.PodStatus{
+		Phase: corev1.PodPending,
+		ContainerStatuses: []corev1.ContainerStatus{
+			{Name: "step", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image"}}},
+		},
+	}
+	runner := newTestKubernetesRunner(t, api, newTestExecutionLogEntryStore())
+
+	spec := CommandSpec{
+		Key:        "step.docker.0",
+		Image:      "alpine:doesnotexist",
+		ScriptPath: "step.0.sh",
+		Operation:  makeTestOperation(),
+	}
+	err := runner.Run(context.Background(), spec)
+	if err == nil || !strings.Contains(err.Error(), "ImagePullBackOff") {
+		t.Fatalf("unexpected error. want=%q have=%v", "ImagePullBackOff", err)
+	}
+}
+
+func TestKubernetesRunnerPendingTimeout(t *testing.T) {
+	api := newFakeKubernetesAPI(t, 0, "")
+	api.pendingStatus = &corev1.PodStatus{
+		Phase: corev1.PodPending,
+		Conditions: []corev1.PodCondition{
+			{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: "Unschedulable", Message: "0/3 nodes are available: 3 Insufficient cpu."},
+		},
+	}
+	runner := newTestKubernetesRunner(t, api, newTestExecutionLogEntryStore())
+	runner.options.KubernetesOptions.PendingTimeout = 10 * time.Millisecond
+
+	spec := CommandSpec{
+		Key:        "step.docker.0",
+		Image:      "alpine:latest",
+		ScriptPath: "step.0.sh",
+		Operation:  makeTestOperation(),
+	}
+	err := runner.Run(context.Background(), spec)
+	if err == nil || !strings.Contains(err.Error(), "Insufficient cpu") {
+		t.Fatalf("unexpected error. want=%q have=%v", "Insufficient cpu", err)
+	}
+}
+
+func TestKubernetesRunnerWorkspaceOutsideRoot(t *testing.T) {
+	api := newFakeKubernetesAPI(t, 0, "")
+	runner := newTestKubernetesRunner(t, api, newTestExecutionLogEntryStore())
+	runner.dir = "/elsewhere/job-42"
+
+	if err := runner.Setup(context.Background(), nil, nil); err == nil {
+		t.Fatalf("expected an error")
+	}
+	if len(api.createdNamespaces) != 0 {
+		t.Errorf("unexpected namespaces created: %v", api.createdNamespaces)
+	}
+}
+
+func TestKubernetesPodName(t *testing.T) {
+	testCases := map[string]string{
+		"step.docker.0":               "step-docker-0",
+		"Step_SRC.12":                 "step-src-12",
+		"." + strings.Repeat("a", 70): strings.Repeat("a", 62),
+	}
+
+	for key, expected := range testCases {
+		if name := kubernetesPodName(key); name != expected {
+			t.Errorf("unexpected pod name for %q. want=%q have=%q", key, expected, name)
+		}
+	}
+}
+
+func newTestKubernetesRunner(t *testing.T, api *fakeKubernetesAPI, store ExecutionLogEntryStore) *kubernetesRunner {
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: api.server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %s", err)
+	}
+
+	options := Options{
+		ExecutorName: "deadbeef",
+		KubernetesOptions: KubernetesOptions{
+			Enabled:         true,
+			NamespacePrefix: "executor-",
+			WorkspaceRoot:   "/workspaces",
+			WorkspaceVolume: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/workspaces"}},
+			PollInterval:    time.Millisecond,
+			PendingTimeout:  time.Minute,
+			Client:          client,
+		},
+		ResourceOptions: ResourceOptions{
+			NumCPUs:   4,
+			Memory:    "12G",
+			DiskSpace: "20G",
+		},
+	}
+
+	return NewRunner("/workspaces/job-42", NewLogger(store, 42, nil), options, NewOperations(&observation.TestContext)).(*kubernetesRunner)
+}
+
+// newTestExecutionLogEntryStore creates a mock store that assigns increasing identifiers
+// to new log entries.
+func newTestExecutionLogEntryStore() *MockExecutionLogEntryStore {
+	var m sync.Mutex
+	var lastID int
+
+	store := NewMockExecutionLogEntryStore()
+	store.AddExecutionLogEntryFunc.SetDefaultHook(func(context.Context, int, workerutil.ExecutionLogEntry) (int, error) {
+		m.Lock()
+		defer m.Unlock()
+		lastID++
+		return lastID, nil
+	})
+	return store
+}
+
+// logEntry reconstructs the log entry with the given key from the calls to the store.
+func logEntry(t *testing.T, store *MockExecutionLogEntryStore, key string) workerutil.ExecutionLogEntry {
+	for _, addCall := range store.AddExecutionLogEntryFunc.History() {
+		if addCall.Arg2.Key != key {
+			continue
+		}
+
+		entry := addCall.Arg2
+		for _, updateCall := range store.UpdateExecutionLogEntryFunc.History() {
+			if updateCall.Arg2 != addCall.Result0 {
+				continue
+			}
+			entry.Out += updateCall.Arg3.Out
+			if updateCall.Arg3.ExitCode != nil {
+				entry.ExitCode = updateCall.Arg3.ExitCode
+				entry.DurationMs = updateCall.Arg3.DurationMs
+			}
+		}
+		return entry
+	}
+
+	t.Fatalf("no log entry for key %q", key)
+	return workerutil.ExecutionLogEntry{}
+}
+
+// fakeKubernetesAPI is a minimal implementation of the Kubernetes API serving the requests
+// made by the Kubernetes runner. Pods are reported as pending on the first status request,
+// and as terminated with the configured exit code afterwards. If pendingStatus is set, pods
+// never leave the pending phase and report that status instead.
+type fakeKubernetesAPI struct {
+	server        *httptest.Server
+	exitCode      int32
+	logs          string
+	pendingStatus *corev1.PodStatus
+
+	m                 sync.Mutex
+	createdNamespaces []string
+	deletedNamespaces []string
+	createdPods       []corev1.Pod
+	podGets           map[string]int
+}
+
+func newFakeKubernetesAPI(t *testing.T, exitCode int32, logs string) *fakeKubernetesAPI {
+	api := &fakeKubernetesAPI{exitCode: exitCode, logs: logs, podGets: map[string]int{}}
+	api.server = httptest.NewServer(http.HandlerFunc(api.serveHTTP))
+	t.Cleanup(api.server.Close)
+	return api
+}
+
+func (api *fakeKubernetesAPI) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	api.m.Lock()
+	defer api.m.Unlock()
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 || parts[0] != "api" || parts[1] != "v1" || parts[2] != "namespaces" {
+		http.NotFound(w, r)
+		return
+	}
+	parts = parts[3:]
+
+	switch {
+	case r.Method == http.MethodPost && len(parts) == 0:
+		var namespace corev1.Namespace
+		if !decodeBody(w, r, &namespace) {
+			return
+		}
+		api.createdNamespaces = append(api.createdNamespaces, namespace.Name)
+		namespace.TypeMeta = metav1.TypeMeta{Kind: "Namespace", APIVersion: "v1"}
+		writeObject(w, http.StatusCreated, &namespace)
+
+	case r.Method == http.MethodDelete && len(parts) == 1:
+		api.deletedNamespaces = append(api.deletedNamespaces, parts[0])
+		writeObject(w, http.StatusOK, &metav1.Status{TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}, Status: metav1.StatusSuccess})
+
+	case r.Method == http.MethodPost && len(parts) == 2 && parts[1] == "pods":
+		var pod corev1.Pod
+		if !decodeBody(w, r, &pod) {
+			return
+		}
+		api.createdPods = append(api.createdPods, pod)
+		pod.TypeMeta = metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"}
+		writeObject(w, http.StatusCreated, &pod)
+
+	case r.Method == http.MethodGet && len(parts) == 3 && parts[1] == "pods":
+		pod := corev1.Pod{
+			TypeMeta:   metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: parts[2], Namespace: parts[0]},
+			Status:     corev1.PodStatus{Phase: corev1.PodPending},
+		}
+		if api.pendingStatus != nil {
+			pod.Status = *api.pendingStatus
+		} else if api.podGets[parts[2]] > 0 {
+			pod.Status.Phase = corev1.PodSucceeded
+			if api.exitCode != 0 {
+				pod.Status.Phase = corev1.PodFailed
+			}
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+				{Name: "step", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: api.exitCode}}},
+			}
+		}
+		api.podGets[parts[2]]++
+		writeObject(w, http.StatusOK, &pod)
+
+	case r.Method == http.MethodGet && len(parts) == 4 && parts[1] == "pods" && parts[3] == "log":
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, api.logs)
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+
+	return true
+}
+
+func writeObject(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
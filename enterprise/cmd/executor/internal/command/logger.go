@@ -1,47 +1,226 @@
 package command
 
 import (
+	"bytes"
+	"context"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
 
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 )
 
-// Logger tracks command invocations and stores the command's output and
-// error stream values.
+// ErrLogEntryUpdateUnsupported is returned by UpdateExecutionLogEntry if the store does
+// not support updating existing log entries.
+var ErrLogEntryUpdateUnsupported = errors.New("updating execution log entries is not supported")
+
+// ExecutionLogEntryStore handles interactions with the execution logs of a job record.
+type ExecutionLogEntryStore interface {
+	AddExecutionLogEntry(ctx context.Context, id int, entry workerutil.ExecutionLogEntry) (int, error)
+	UpdateExecutionLogEntry(ctx context.Context, id, entryID int, entry workerutil.ExecutionLogEntry) error
+}
+
+// Logger tracks command invocations and streams the command's output and error stream
+// values to the execution logs of a job record while the command is running.
 type Logger struct {
+	store    ExecutionLogEntryStore
+	recordID int
 	replacer *strings.Replacer
-	entries  []workerutil.ExecutionLogEntry
+	wg       sync.WaitGroup
 }
 
-// NewLogger creates a new logger instance with the given replacement map.
-// When the log messages are serialized, any occurrence of sensitive values
-// are replace with a non-sensitive value.
-func NewLogger(replacements map[string]string) *Logger {
+// logUpdateInterval is the interval at which the output of a running command is sent
+// to the store.
+var logUpdateInterval = time.Second
+
+// NewLogger creates a new logger instance writing to the execution logs of the record
+// with the given identifier. When the log messages are serialized, any occurrence of
+// sensitive values in the replacement map are replaced with a non-sensitive value.
+func NewLogger(store ExecutionLogEntryStore, recordID int, replacements map[string]string) *Logger {
 	oldnew := make([]string, 0, len(replacements)*2)
 	for k, v := range replacements {
 		oldnew = append(oldnew, k, v)
 	}
 
 	return &Logger{
+		store:    store,
+		recordID: recordID,
 		replacer: strings.NewReplacer(oldnew...),
 	}
 }
 
-// Log redacts secrets from the given log entry and stores it.
-func (l *Logger) Log(entry workerutil.ExecutionLogEntry) {
-	l.entries = append(l.entries, redact(entry, l.replacer))
+// Log adds a log entry for the given command to the record and returns a handle to
+// which the output of the command is written. New output is appended to the entry
+// periodically until the handle is finalized.
+func (l *Logger) Log(key string, command []string) *entryHandle {
+	redactedCommand := make([]string, 0, len(command))
+	for _, arg := range command {
+		redactedCommand = append(redactedCommand, l.replacer.Replace(arg))
+	}
+
+	handle := &entryHandle{
+		entry: workerutil.ExecutionLogEntry{
+			Key:       key,
+			Command:   redactedCommand,
+			StartTime: time.Now(),
+		},
+		replacer: l.replacer,
+		done:     make(chan struct{}),
+	}
+
+	// Perform these writes outside of the task execution context. If there is a timeout
+	// or cancellation error we don't want to skip uploading these logs as users will
+	// often want to see how far something progressed prior to a timeout.
+	entryID, err := l.store.AddExecutionLogEntry(context.Background(), l.recordID, handle.entry)
+	if err != nil {
+		log15.Warn("Failed to upload executor log entry for job", "id", l.recordID, "key", key, "error", err)
+		return handle
+	}
+
+	l.wg.Add(1)
+	go l.syncEntry(handle, entryID)
+	return handle
+}
+
+// Flush blocks until the final state of all log entries has been sent to the store.
+// All handles returned by Log must be finalized before calling this method.
+func (l *Logger) Flush() {
+	l.wg.Wait()
+}
+
+// syncEntry sends new output of the given handle to the store periodically until the
+// handle is finalized.
+//
+// Older versions of the store do not support updating log entries: they return no entry
+// identifier or reject the update. In that case the output is buffered and the complete
+// entry is added to the record once the handle is finalized.
+func (l *Logger) syncEntry(handle *entryHandle, entryID int) {
+	defer l.wg.Done()
+
+	if entryID != 0 && l.streamEntry(handle, entryID) {
+		return
+	}
+
+	<-handle.done
+	l.addFinalEntry(handle)
+}
+
+// streamEntry sends new output of the given handle to the store periodically until the
+// handle is finalized. The returned flag is false if the store does not support updates.
+func (l *Logger) streamEntry(handle *entryHandle, entryID int) bool {
+	ticker := time.NewTicker(logUpdateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if !l.updateEntry(handle, entryID) {
+				return false
+			}
+
+		case <-handle.done:
+			return l.updateEntry(handle, entryID)
+		}
+	}
+}
+
+// updateEntry sends the pending output of the given handle to the store. The returned flag
+// is false if the store does not support updates.
+func (l *Logger) updateEntry(handle *entryHandle, entryID int) bool {
+	update, ok := handle.pending()
+	if !ok {
+		return true
+	}
+
+	if err := l.store.UpdateExecutionLogEntry(context.Background(), l.recordID, entryID, update); err != nil {
+		if errors.Is(err, ErrLogEntryUpdateUnsupported) {
+			return false
+		}
+
+		// The pending output is kept and sent with the next update.
+		log15.Warn("Failed to update executor log entry for job", "id", l.recordID, "key", handle.entry.Key, "error", err)
+		return true
+	}
+
+	handle.sent(update)
+	return true
+}
+
+// addFinalEntry adds the complete log entry of the given finalized handle to the record.
+func (l *Logger) addFinalEntry(handle *entryHandle) {
+	update, _ := handle.pending()
+
+	entry := handle.entry
+	entry.Out = update.Out
+	entry.ExitCode = update.ExitCode
+	entry.DurationMs = update.DurationMs
+
+	if _, err := l.store.AddExecutionLogEntry(context.Background(), l.recordID, entry); err != nil {
+		log15.Warn("Failed to upload executor log entry for job", "id", l.recordID, "key", handle.entry.Key, "error", err)
+	}
+}
+
+// entryHandle accumulates the output of a single command.
+type entryHandle struct {
+	replacer *strings.Replacer
+	done     chan struct{}
+	entry    workerutil.ExecutionLogEntry
+
+	m          sync.Mutex
+	out        bytes.Buffer // output that has not been sent to the store yet
+	exitCode   *int
+	durationMs *int
+	finalSent  bool
+}
+
+// Write redacts the given output and appends it to the log entry. Callers should write
+// whole lines so that sensitive values are never split across two writes.
+func (h *entryHandle) Write(p []byte) (int, error) {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	h.out.WriteString(h.replacer.Replace(string(p)))
+	return len(p), nil
+}
+
+// Finalize records the exit code and duration of the command. The handle must not be
+// written to afterwards.
+func (h *entryHandle) Finalize(exitCode int) {
+	h.m.Lock()
+	durationMs := int(time.Since(h.entry.StartTime) / time.Millisecond)
+	h.exitCode = &exitCode
+	h.durationMs = &durationMs
+	h.m.Unlock()
+
+	close(h.done)
 }
 
-// Entries returns a copy of the stored log entries.
-func (l *Logger) Entries() (entries []workerutil.ExecutionLogEntry) {
-	entries = append(entries, l.entries...)
-	return entries
+// pending returns an update of the log entry with the output that has not been sent to
+// the store yet and, once the command has finished, its exit code and duration. The
+// returned flag is false if there is nothing to send.
+func (h *entryHandle) pending() (workerutil.ExecutionLogEntry, bool) {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	update := workerutil.ExecutionLogEntry{Out: h.out.String()}
+	if !h.finalSent {
+		update.ExitCode = h.exitCode
+		update.DurationMs = h.durationMs
+	}
+	return update, update.Out != "" || update.ExitCode != nil
 }
 
-func redact(entry workerutil.ExecutionLogEntry, replacer *strings.Replacer) workerutil.ExecutionLogEntry {
-	for i, arg := range entry.Command {
-		entry.Command[i] = replacer.Replace(arg)
+// sent marks the given update returned by pending as stored. Output written since the
+// call to pending remains pending.
+func (h *entryHandle) sent(update workerutil.ExecutionLogEntry) {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	h.out.Next(len(update.Out))
+	if update.ExitCode != nil {
+		h.finalSent = true
 	}
-	entry.Out = replacer.Replace(entry.Out)
-	return entry
 }
@@ -0,0 +1,145 @@
+package command
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+)
+
+func TestLogger(t *testing.T) {
+	store := NewMockExecutionLogEntryStore()
+	store.AddExecutionLogEntryFunc.SetDefaultReturn(7, nil)
+
+	logger := NewLogger(store, 42, map[string]string{"secret": "******"})
+	handle := logger.Log("step.0", []string{"docker", "run", "--env", "TOKEN=secret"})
+	fmt.Fprintf(handle, "stdout: the secret is secret\n")
+	fmt.Fprintf(handle, "stderr: oops\n")
+	handle.Finalize(1)
+	logger.Flush()
+
+	addCalls := store.AddExecutionLogEntryFunc.History()
+	if len(addCalls) != 1 {
+		t.Fatalf("unexpected number of AddExecutionLogEntry calls. want=%d have=%d", 1, len(addCalls))
+	}
+	if addCalls[0].Arg1 != 42 {
+		t.Errorf("unexpected record identifier. want=%d have=%d", 42, addCalls[0].Arg1)
+	}
+	expectedStart := workerutil.ExecutionLogEntry{
+		Key:     "step.0",
+		Command: []string{"docker", "run", "--env", "TOKEN=******"},
+	}
+	if diff := cmp.Diff(expectedStart, addCalls[0].Arg2, cmpopts.IgnoreFields(workerutil.ExecutionLogEntry{}, "StartTime")); diff != "" {
+		t.Errorf("unexpected initial entry (-want +got):\n%s", diff)
+	}
+
+	updateCalls := store.UpdateExecutionLogEntryFunc.History()
+	if len(updateCalls) == 0 {
+		t.Fatalf("expected UpdateExecutionLogEntry to be called")
+	}
+	var out string
+	for _, call := range updateCalls {
+		if call.Arg1 != 42 || call.Arg2 != 7 {
+			t.Errorf("unexpected identifiers. want=(%d, %d) have=(%d, %d)", 42, 7, call.Arg1, call.Arg2)
+		}
+		out += call.Arg3.Out
+	}
+	if expected := "stdout: the ****** is ******\nstderr: oops\n"; out != expected {
+		t.Errorf("unexpected output. want=%q have=%q", expected, out)
+	}
+	last := updateCalls[len(updateCalls)-1]
+	if last.Arg3.ExitCode == nil || *last.Arg3.ExitCode != 1 {
+		t.Errorf("unexpected exit code. want=%d have=%v", 1, last.Arg3.ExitCode)
+	}
+	if last.Arg3.DurationMs == nil {
+		t.Errorf("expected duration to be set")
+	}
+}
+
+func TestLoggerSendsOnlyNewOutput(t *testing.T) {
+	store := NewMockExecutionLogEntryStore()
+	store.AddExecutionLogEntryFunc.SetDefaultReturn(7, nil)
+	store.UpdateExecutionLogEntryFunc.PushReturn(fmt.Errorf("oops"))
+
+	logger := NewLogger(store, 42, nil)
+	handle := logger.Log("step.0", []string{"git", "fetch"})
+
+	fmt.Fprintf(handle, "stdout: a\n")
+	logger.updateEntry(handle, 7) // fails, so the output is sent again with the next update
+	fmt.Fprintf(handle, "stdout: b\n")
+	logger.updateEntry(handle, 7)
+	fmt.Fprintf(handle, "stdout: c\n")
+	logger.updateEntry(handle, 7)
+	logger.updateEntry(handle, 7) // nothing new to send
+	handle.Finalize(0)
+	logger.Flush()
+
+	var have []string
+	for _, call := range store.UpdateExecutionLogEntryFunc.History() {
+		have = append(have, call.Arg3.Out)
+	}
+	want := []string{"stdout: a\n", "stdout: a\nstdout: b\n", "stdout: c\n", ""}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Errorf("unexpected updates (-want +got):\n%s", diff)
+	}
+}
+
+func TestLoggerAddError(t *testing.T) {
+	store := NewMockExecutionLogEntryStore()
+	store.AddExecutionLogEntryFunc.SetDefaultReturn(0, fmt.Errorf("oops"))
+
+	logger := NewLogger(store, 42, nil)
+	handle := logger.Log("step.0", []string{"git", "fetch"})
+	fmt.Fprintf(handle, "stdout: hello\n")
+	handle.Finalize(0)
+	logger.Flush()
+
+	if value := len(store.UpdateExecutionLogEntryFunc.History()); value != 0 {
+		t.Errorf("unexpected number of UpdateExecutionLogEntry calls. want=%d have=%d", 0, value)
+	}
+}
+
+func TestLoggerUpdatesUnsupported(t *testing.T) {
+	for name, setup := range map[string]func(store *MockExecutionLogEntryStore){
+		"no entry identifier": func(store *MockExecutionLogEntryStore) {
+			store.AddExecutionLogEntryFunc.SetDefaultReturn(0, nil)
+		},
+		"update rejected": func(store *MockExecutionLogEntryStore) {
+			store.AddExecutionLogEntryFunc.SetDefaultReturn(7, nil)
+			store.UpdateExecutionLogEntryFunc.SetDefaultReturn(ErrLogEntryUpdateUnsupported)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := NewMockExecutionLogEntryStore()
+			setup(store)
+
+			logger := NewLogger(store, 42, map[string]string{"secret": "******"})
+			handle := logger.Log("step.0", []string{"git", "fetch"})
+			fmt.Fprintf(handle, "stdout: a secret\n")
+			fmt.Fprintf(handle, "stdout: b\n")
+			handle.Finalize(1)
+			logger.Flush()
+
+			addCalls := store.AddExecutionLogEntryFunc.History()
+			if len(addCalls) != 2 {
+				t.Fatalf("unexpected number of AddExecutionLogEntry calls. want=%d have=%d", 2, len(addCalls))
+			}
+			final := addCalls[1].Arg2
+			if expected := "stdout: a ******\nstdout: b\n"; final.Out != expected {
+				t.Errorf("unexpected output. want=%q have=%q", expected, final.Out)
+			}
+			if final.Key != "step.0" || final.StartTime.IsZero() {
+				t.Errorf("unexpected entry. have=%+v", final)
+			}
+			if final.ExitCode == nil || *final.ExitCode != 1 {
+				t.Errorf("unexpected exit code. want=%d have=%v", 1, final.ExitCode)
+			}
+			if final.DurationMs == nil {
+				t.Errorf("expected duration to be set")
+			}
+		})
+	}
+}
@@ -8,18 +8,20 @@ import (
 )
 
 type Operations struct {
-	SetupGitInit              *observation.Operation
-	SetupGitFetch             *observation.Operation
-	SetupAddRemote            *observation.Operation
-	SetupGitCheckout          *observation.Operation
-	SetupDockerPull           *observation.Operation
-	SetupDockerSave           *observation.Operation
-	SetupDockerLoad           *observation.Operation
-	SetupFirecrackerStart     *observation.Operation
-	SetupRm                   *observation.Operation
-	TeardownFirecrackerStop   *observation.Operation
-	TeardownFirecrackerRemove *observation.Operation
-	Exec                      *observation.Operation
+	SetupGitInit                *observation.Operation
+	SetupGitFetch               *observation.Operation
+	SetupAddRemote              *observation.Operation
+	SetupGitCheckout            *observation.Operation
+	SetupDockerPull             *observation.Operation
+	SetupDockerSave             *observation.Operation
+	SetupDockerLoad             *observation.Operation
+	SetupFirecrackerStart       *observation.Operation
+	SetupRm                     *observation.Operation
+	TeardownFirecrackerStop     *observation.Operation
+	TeardownFirecrackerRemove   *observation.Operation
+	SetupKubernetesNamespace    *observation.Operation
+	TeardownKubernetesNamespace *observation.Operation
+	Exec                        *observation.Operation
 }
 
 func NewOperations(observationContext *observation.Context) *Operations {
@@ -39,17 +41,19 @@ func NewOperations(observationContext *observation.Context) *Operations {
 	}
 
 	return &Operations{
-		SetupGitInit:              op("setup.git.init"),
-		SetupGitFetch:             op("setup.git.fetch"),
-		SetupAddRemote:            op("setup.git.add-remote"),
-		SetupGitCheckout:          op("setup.git.checkout"),
-		SetupDockerPull:           op("setup.docker.pull"),
-		SetupDockerSave:           op("setup.docker.save"),
-		SetupDockerLoad:           op("setup.docker.load"),
-		SetupRm:                   op("setup.rm"),
-		SetupFirecrackerStart:     op("setup.firecracker.start"),
-		TeardownFirecrackerStop:   op("teardown.firecracker.stop"),
-		TeardownFirecrackerRemove: op("teardown.firecracker.remove"),
-		Exec:                      op("exec"),
+		SetupGitInit:                op("setup.git.init"),
+		SetupGitFetch:               op("setup.git.fetch"),
+		SetupAddRemote:              op("setup.git.add-remote"),
+		SetupGitCheckout:            op("setup.git.checkout"),
+		SetupDockerPull:             op("setup.docker.pull"),
+		SetupDockerSave:             op("setup.docker.save"),
+		SetupDockerLoad:             op("setup.docker.load"),
+		SetupRm:                     op("setup.rm"),
+		SetupFirecrackerStart:       op("setup.firecracker.start"),
+		TeardownFirecrackerStop:     op("teardown.firecracker.stop"),
+		TeardownFirecrackerRemove:   op("teardown.firecracker.remove"),
+		SetupKubernetesNamespace:    op("setup.kubernetes.namespace"),
+		TeardownKubernetesNamespace: op("teardown.kubernetes.namespace"),
+		Exec:                        op("exec"),
 	}
 }
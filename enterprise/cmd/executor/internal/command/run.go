@@ -2,7 +2,6 @@ package command
 
 import (
 	"bufio"
-	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -10,13 +9,11 @@ import (
 	"os/exec"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/inconshreveable/log15"
 
 	"github.com/sourcegraph/sourcegraph/internal/observation"
-	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 )
 
 type command struct {
@@ -28,7 +25,7 @@ type command struct {
 }
 
 // runCommand invokes the given command on the host machine. The standard output and
-// standard error streams of the invoked command are written to the given logger.
+// standard error streams of the invoked command are streamed to the given logger.
 func runCommand(ctx context.Context, command command, logger *Logger) (err error) {
 	ctx, endObservation := command.Operation.With(ctx, &err, observation.Args{})
 	defer endObservation(1, observation.Args{})
@@ -69,19 +66,10 @@ func runCommand(ctx context.Context, command command, logger *Logger) (err error
 		stderr.Close()
 	}()
 
-	startTime := time.Now()
-	pipeContents, pipeReaderWaitGroup := readProcessPipes(stdout, stderr)
+	handle := logger.Log(command.Key, command.Command)
+	pipeReaderWaitGroup := readProcessPipes(handle, stdout, stderr)
 	exitCode, err := monitorCommand(ctx, cmd, pipeReaderWaitGroup)
-	duration := time.Since(startTime)
-
-	logger.Log(workerutil.ExecutionLogEntry{
-		Key:        command.Key,
-		Command:    command.Command,
-		StartTime:  startTime,
-		ExitCode:   exitCode,
-		Out:        pipeContents.String(),
-		DurationMs: int(duration / time.Millisecond),
-	})
+	handle.Finalize(exitCode)
 
 	if err != nil {
 		return err
@@ -148,9 +136,11 @@ func prepCommand(ctx context.Context, command command) (cmd *exec.Cmd, stdout, s
 // we shell out to, such a docker.
 var forwardedHostEnvVars = []string{"HOME", "PATH"}
 
-func readProcessPipes(stdout, stderr io.Reader) (*bytes.Buffer, *sync.WaitGroup) {
+// readProcessPipes writes the lines read from the given pipes to the given writer as they
+// are produced, each prefixed with the name of the pipe. Each line is written in a single
+// call to Write.
+func readProcessPipes(w io.Writer, stdout, stderr io.Reader) *sync.WaitGroup {
 	var m sync.Mutex
-	out := &bytes.Buffer{}
 	wg := &sync.WaitGroup{}
 
 	readIntoBuf := func(prefix string, r io.Reader) {
@@ -159,7 +149,7 @@ func readProcessPipes(stdout, stderr io.Reader) (*bytes.Buffer, *sync.WaitGroup)
 		scanner := bufio.NewScanner(r)
 		for scanner.Scan() {
 			m.Lock()
-			fmt.Fprintf(out, "%s: %s\n", prefix, scanner.Text())
+			fmt.Fprintf(w, "%s: %s\n", prefix, scanner.Text())
 			m.Unlock()
 		}
 	}
@@ -168,7 +158,7 @@ func readProcessPipes(stdout, stderr io.Reader) (*bytes.Buffer, *sync.WaitGroup)
 	go readIntoBuf("stdout", stdout)
 	go readIntoBuf("stderr", stderr)
 
-	return out, wg
+	return wg
 }
 
 // monitorCommand starts the given command and waits for the given wait group to complete.
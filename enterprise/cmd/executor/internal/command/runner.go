@@ -9,7 +9,7 @@ import (
 // Runner is the interface between an executor and the host on which commands
 // are invoked. Having this interface at this level allows us to use the same
 // code paths for local development (via shell + docker) as well as production
-// usage (via Firecracker).
+// usage (via Firecracker or Kubernetes).
 type Runner interface {
 	// Setup prepares the runner to invoke a series of commands.
 	Setup(ctx context.Context, imageNames, scriptPaths []string) error
@@ -41,8 +41,11 @@ type Options struct {
 	// FirecrackerOptions configures the behavior of Firecracker virtual machine creation.
 	FirecrackerOptions FirecrackerOptions
 
-	// ResourceOptions configures the resource limits of docker container and Firecracker
-	// virtual machines running on the executor.
+	// KubernetesOptions configures the behavior of Kubernetes pod creation.
+	KubernetesOptions KubernetesOptions
+
+	// ResourceOptions configures the resource limits of docker container, Firecracker
+	// virtual machines, and Kubernetes pods running on the executor.
 	ResourceOptions ResourceOptions
 }
 
@@ -70,6 +73,10 @@ type ResourceOptions struct {
 
 // NewRunner creates a new runner with the given options.
 func NewRunner(dir string, logger *Logger, options Options, operations *Operations) Runner {
+	if options.KubernetesOptions.Enabled {
+		return &kubernetesRunner{name: options.ExecutorName, dir: dir, logger: logger, options: options, operations: operations}
+	}
+
 	if !options.FirecrackerOptions.Enabled {
 		return &dockerRunner{dir: dir, logger: logger, options: options}
 	}
@@ -67,19 +67,14 @@ func (h *handler) Handle(ctx context.Context, record workerutil.Record) (err err
 	// interpolate into the command. No command that we run on the host leaks environment
 	// variables, and the user-specified commands (which could leak their environment) are
 	// run in a clean VM.
-	logger := command.NewLogger(union(h.options.RedactedValues, job.RedactedValues))
+	//
+	// Log entries are written to the store while the commands are running, so that users
+	// can follow the progress of the job.
+	logger := command.NewLogger(h.store, record.RecordID(), union(h.options.RedactedValues, job.RedactedValues))
 
 	defer func() {
-		log15.Info("Writing log entries", "jobID", job.ID, "repositoryName", job.RepositoryName, "commit", job.Commit)
-
-		for _, entry := range logger.Entries() {
-			// Perform this outside of the task execution context. If there is a timeout or
-			// cancellation error we don't want to skip uploading these logs as users will
-			// often want to see how far something progressed prior to a timeout.
-			if err := h.store.AddExecutionLogEntry(context.Background(), record.RecordID(), entry); err != nil {
-				log15.Warn("Failed to upload executor log entry for job", "id", record.RecordID(), "repositoryName", job.RepositoryName, "commit", job.Commit, "error", err)
-			}
-		}
+		log15.Info("Flushing log entries", "jobID", job.ID, "repositoryName", job.RepositoryName, "commit", job.Commit)
+		logger.Flush()
 	}()
 
 	// Create a working directory for this job which will be removed once the job completes.
@@ -121,6 +116,7 @@ func (h *handler) Handle(ctx context.Context, record workerutil.Record) (err err
 	options := command.Options{
 		ExecutorName:       name.String(),
 		FirecrackerOptions: h.options.FirecrackerOptions,
+		KubernetesOptions:  h.options.KubernetesOptions,
 		ResourceOptions:    h.options.ResourceOptions,
 	}
 	runner := h.runnerFactory(workingDirectory, logger, options, h.operations)
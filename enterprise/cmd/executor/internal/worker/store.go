@@ -5,6 +5,8 @@ import (
 
 	"github.com/cockroachdb/errors"
 
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor/internal/apiclient"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor/internal/command"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 )
@@ -16,7 +18,8 @@ type storeShim struct {
 
 type QueueStore interface {
 	Dequeue(ctx context.Context, queueName string, payload *executor.Job) (bool, error)
-	AddExecutionLogEntry(ctx context.Context, queueName string, jobID int, entry workerutil.ExecutionLogEntry) error
+	AddExecutionLogEntry(ctx context.Context, queueName string, jobID int, entry workerutil.ExecutionLogEntry) (int, error)
+	UpdateExecutionLogEntry(ctx context.Context, queueName string, jobID, entryID int, entry workerutil.ExecutionLogEntry) error
 	MarkComplete(ctx context.Context, queueName string, jobID int) error
 	MarkErrored(ctx context.Context, queueName string, jobID int, errorMessage string) error
 	MarkFailed(ctx context.Context, queueName string, jobID int, errorMessage string) error
@@ -38,10 +41,19 @@ func (s *storeShim) Dequeue(ctx context.Context, workerHostname string, extraArg
 	return job, func() {}, dequeued, nil
 }
 
-func (s *storeShim) AddExecutionLogEntry(ctx context.Context, id int, entry workerutil.ExecutionLogEntry) error {
+func (s *storeShim) AddExecutionLogEntry(ctx context.Context, id int, entry workerutil.ExecutionLogEntry) (int, error) {
 	return s.queueStore.AddExecutionLogEntry(ctx, s.queueName, id, entry)
 }
 
+func (s *storeShim) UpdateExecutionLogEntry(ctx context.Context, id, entryID int, entry workerutil.ExecutionLogEntry) error {
+	err := s.queueStore.UpdateExecutionLogEntry(ctx, s.queueName, id, entryID, entry)
+	if apiclient.IsNotFound(err) {
+		// Older versions of the executor queue do not serve this route
+		return command.ErrLogEntryUpdateUnsupported
+	}
+	return err
+}
+
 func (s *storeShim) MarkComplete(ctx context.Context, id int) (bool, error) {
 	return true, s.queueStore.MarkComplete(ctx, s.queueName, id)
 }
@@ -53,3 +65,9 @@ func (s *storeShim) MarkErrored(ctx context.Context, id int, errorMessage string
 func (s *storeShim) MarkFailed(ctx context.Context, id int, errorMessage string) (bool, error) {
 	return true, s.queueStore.MarkFailed(ctx, s.queueName, id, errorMessage)
 }
+
+// CanceledIDs returns no identifiers, as canceled jobs are reported by the queue API in the
+// response of heartbeat requests and their handlers are canceled directly.
+func (s *storeShim) CanceledIDs(ctx context.Context, ids []int) ([]int, error) {
+	return nil, nil
+}
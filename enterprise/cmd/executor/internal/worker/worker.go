@@ -45,8 +45,11 @@ type Options struct {
 	// FirecrackerOptions configures the behavior of Firecracker virtual machine creation.
 	FirecrackerOptions command.FirecrackerOptions
 
-	// ResourceOptions configures the resource limits of docker container and Firecracker
-	// virtual machines running on the executor.
+	// KubernetesOptions configures the behavior of Kubernetes pod creation.
+	KubernetesOptions command.KubernetesOptions
+
+	// ResourceOptions configures the resource limits of docker container, Firecracker
+	// virtual machines, and Kubernetes pods running on the executor.
 	ResourceOptions command.ResourceOptions
 
 	// MaximumRuntimePerJob is the maximum wall time that can be spent on a single job.
@@ -57,7 +60,8 @@ type Options struct {
 // routine contains both a worker that periodically polls for new work to perform, as well
 // as a heartbeat routine that will periodically hit the remote API with the work that is
 // currently being performed, which is necessary so the job queue API doesn't hand out jobs
-// it thinks may have been dropped.
+// it thinks may have been dropped. Jobs canceled by users are reported in the response of
+// the heartbeat requests and stop being processed.
 func NewWorker(options Options, observationContext *observation.Context) goroutine.BackgroundRoutine {
 	idSet := newIDSet()
 	queueStore := apiclient.New(options.ClientOptions, observationContext)
@@ -67,6 +71,15 @@ func NewWorker(options Options, observationContext *observation.Context) gorouti
 		os.Exit(1)
 	}
 
+	if options.KubernetesOptions.Enabled && options.KubernetesOptions.Client == nil {
+		client, err := command.NewKubernetesClient(options.KubernetesOptions)
+		if err != nil {
+			log15.Error("Failed to create Kubernetes client", "error", err)
+			os.Exit(1)
+		}
+		options.KubernetesOptions.Client = client
+	}
+
 	handler := &handler{
 		idSet:         idSet,
 		store:         store,
@@ -77,14 +90,19 @@ func NewWorker(options Options, observationContext *observation.Context) gorouti
 
 	indexer := workerutil.NewWorker(context.Background(), store, handler, options.WorkerOptions)
 	heartbeat := goroutine.NewHandlerWithErrorMessage("heartbeat", func(ctx context.Context) error {
-		unknownIDs, err := queueStore.Heartbeat(ctx, idSet.Slice())
+		unknownIDs, canceledIDs, err := queueStore.Heartbeat(ctx, idSet.Slice())
 		if err != nil {
 			return err
 		}
 
+		// Removing a job from the set cancels the context of its handler
 		for _, id := range unknownIDs {
 			idSet.Remove(id)
 		}
+		for _, id := range canceledIDs {
+			log15.Info("Canceling job", "jobID", id)
+			idSet.Remove(id)
+		}
 
 		return nil
 	})
@@ -18,6 +18,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/uploadstore"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
@@ -49,6 +50,14 @@ var revhashPattern = lazyregexp.New(`^[a-z0-9]{40}$`)
 func (h *UploadHandler) handleEnqueue(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	// 🚨 SECURITY: Access tokens with fine-grained scopes may only upload code intelligence
+	// data if they have the codeintel:upload scope. The repository pattern of the scope is
+	// checked when the upload is created below.
+	if err := authz.CheckScope(ctx, authz.ScopeCodeIntelUpload); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
 	var repositoryID int
 	if !hasQuery(r, "uploadId") {
 		repoName := getQuery(r, "repository")
@@ -66,6 +75,13 @@ func (h *UploadHandler) handleEnqueue(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		// 🚨 SECURITY: Access tokens whose codeintel:upload scope is restricted to some
+		// repositories can't upload code intelligence data for other repositories.
+		if err := authz.CheckRepoScope(ctx, authz.ScopeCodeIntelUpload, api.RepoName(repoName)); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+
 		// 🚨 SECURITY: It is critical to ensure if repository and commit exists after
 		// the above authz check. Otherwise, it is possible to use this endpoint to
 		// brute-force existence of repositories.
@@ -32,9 +32,9 @@ func (r *indexStepsResolver) PreIndex() []gql.PreIndexStepResolver {
 	var resolvers []gql.PreIndexStepResolver
 	for i, step := range r.index.DockerSteps {
 		if entry, ok := r.findExecutionLogEntry(fmt.Sprintf("step.docker.%d", i)); ok {
-			resolvers = append(resolvers, &preIndexStepResolver{step: step, entry: &entry})
+			resolvers = append(resolvers, &preIndexStepResolver{index: r.index, step: step, entry: &entry})
 		} else {
-			resolvers = append(resolvers, &preIndexStepResolver{step: step, entry: nil})
+			resolvers = append(resolvers, &preIndexStepResolver{index: r.index, step: step, entry: nil})
 		}
 	}
 
@@ -51,7 +51,7 @@ func (r *indexStepsResolver) Index() gql.IndexStepResolver {
 
 func (r *indexStepsResolver) Upload() gql.ExecutionLogEntryResolver {
 	if entry, ok := r.findExecutionLogEntry("step.src.0"); ok {
-		return gql.NewExecutionLogEntryResolver(dbconn.Global, entry)
+		return gql.NewExecutionLogEntryResolver(dbconn.Global, entry, executionLogEntryRecord(r.index))
 	}
 
 	return nil
@@ -61,6 +61,12 @@ func (r *indexStepsResolver) Teardown() []gql.ExecutionLogEntryResolver {
 	return r.executionLogEntryResolversWithPrefix("teardown.")
 }
 
+// executionLogEntryRecord returns the state of the given index which determines whether
+// its execution log entries are still running.
+func executionLogEntryRecord(index store.Index) gql.ExecutionLogEntryRecord {
+	return gql.ExecutionLogEntryRecord{State: index.State, FinishedAt: index.FinishedAt}
+}
+
 func (r *indexStepsResolver) findExecutionLogEntry(key string) (workerutil.ExecutionLogEntry, bool) {
 	for _, entry := range r.index.ExecutionLogs {
 		if entry.Key == key {
@@ -77,7 +83,7 @@ func (r *indexStepsResolver) executionLogEntryResolversWithPrefix(prefix string)
 		if !strings.HasPrefix(entry.Key, prefix) {
 			continue
 		}
-		r := gql.NewExecutionLogEntryResolver(dbconn.Global, entry)
+		r := gql.NewExecutionLogEntryResolver(dbconn.Global, entry, executionLogEntryRecord(r.index))
 		resolvers = append(resolvers, r)
 	}
 
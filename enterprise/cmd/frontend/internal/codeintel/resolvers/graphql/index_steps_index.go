@@ -19,7 +19,7 @@ func (r *indexStepResolver) Outfile() *string      { return strPtr(r.index.Outfi
 
 func (r *indexStepResolver) LogEntry() gql.ExecutionLogEntryResolver {
 	if r.entry != nil {
-		return gql.NewExecutionLogEntryResolver(dbconn.Global, *r.entry)
+		return gql.NewExecutionLogEntryResolver(dbconn.Global, *r.entry, executionLogEntryRecord(r.index))
 	}
 
 	return nil
@@ -8,6 +8,7 @@ import (
 )
 
 type preIndexStepResolver struct {
+	index store.Index
 	step  store.DockerStep
 	entry *workerutil.ExecutionLogEntry
 }
@@ -20,7 +21,7 @@ func (r *preIndexStepResolver) Commands() []string { return r.step.Commands }
 
 func (r *preIndexStepResolver) LogEntry() gql.ExecutionLogEntryResolver {
 	if r.entry != nil {
-		return gql.NewExecutionLogEntryResolver(dbconn.Global, *r.entry)
+		return gql.NewExecutionLogEntryResolver(dbconn.Global, *r.entry, executionLogEntryRecord(r.index))
 	}
 
 	return nil
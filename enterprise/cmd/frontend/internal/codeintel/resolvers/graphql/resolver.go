@@ -23,6 +23,8 @@ const (
 
 var errAutoIndexingNotEnabled = errors.New("precise code intelligence auto indexing is not enabled")
 
+var errIndexNotCancelable = errors.New("index is not queued or processing")
+
 // Resolver is the main interface to code intel-related operations exposted to the GraphQL API. This
 // resolver concerns itself with GraphQL/API-specific behaviors (auth, validation, marshaling, etc.).
 // All code intel-specific behavior is delegated to the underlying resolver instance, which is defined
@@ -197,6 +199,32 @@ func (r *Resolver) DeleteLSIFIndex(ctx context.Context, args *struct{ ID graphql
 	return &gql.EmptyResponse{}, nil
 }
 
+func (r *Resolver) CancelLSIFIndex(ctx context.Context, args *struct{ ID graphql.ID }) (*gql.EmptyResponse, error) {
+	if !autoIndexingEnabled() {
+		return nil, errAutoIndexingNotEnabled
+	}
+
+	// 🚨 SECURITY: Only site admins may cancel LSIF indexes for now
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, dbconn.Global); err != nil {
+		return nil, err
+	}
+
+	indexID, err := unmarshalLSIFIndexGQLID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	canceled, err := r.resolver.CancelIndexByID(ctx, int(indexID))
+	if err != nil {
+		return nil, err
+	}
+	if !canceled {
+		return nil, errIndexNotCancelable
+	}
+
+	return &gql.EmptyResponse{}, nil
+}
+
 func (r *Resolver) IndexConfiguration(ctx context.Context, id graphql.ID) (gql.IndexConfigurationResolver, error) {
 	if !autoIndexingEnabled() {
 		return nil, errAutoIndexingNotEnabled
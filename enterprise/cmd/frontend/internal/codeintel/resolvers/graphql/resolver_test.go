@@ -95,6 +95,62 @@ func TestDeleteLSIFIndexUnauthenticated(t *testing.T) {
 	}
 }
 
+func TestCancelLSIFIndex(t *testing.T) {
+	db := new(dbtesting.MockDB)
+
+	t.Cleanup(func() {
+		database.Mocks.Users.GetByCurrentAuthUser = nil
+	})
+	database.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{SiteAdmin: true}, nil
+	}
+
+	id := graphql.ID(base64.StdEncoding.EncodeToString([]byte("LSIFIndex:42")))
+	mockResolver := resolvermocks.NewMockResolver()
+	mockResolver.CancelIndexByIDFunc.SetDefaultReturn(true, nil)
+
+	if _, err := NewResolver(db, mockResolver).CancelLSIFIndex(context.Background(), &struct{ ID graphql.ID }{id}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(mockResolver.CancelIndexByIDFunc.History()) != 1 {
+		t.Fatalf("unexpected call count. want=%d have=%d", 1, len(mockResolver.CancelIndexByIDFunc.History()))
+	}
+	if val := mockResolver.CancelIndexByIDFunc.History()[0].Arg1; val != 42 {
+		t.Fatalf("unexpected index id. want=%d have=%d", 42, val)
+	}
+}
+
+func TestCancelLSIFIndexNotCancelable(t *testing.T) {
+	db := new(dbtesting.MockDB)
+
+	t.Cleanup(func() {
+		database.Mocks.Users.GetByCurrentAuthUser = nil
+	})
+	database.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{SiteAdmin: true}, nil
+	}
+
+	id := graphql.ID(base64.StdEncoding.EncodeToString([]byte("LSIFIndex:42")))
+	mockResolver := resolvermocks.NewMockResolver()
+	mockResolver.CancelIndexByIDFunc.SetDefaultReturn(false, nil)
+
+	if _, err := NewResolver(db, mockResolver).CancelLSIFIndex(context.Background(), &struct{ ID graphql.ID }{id}); err != errIndexNotCancelable {
+		t.Errorf("unexpected error. want=%q have=%q", errIndexNotCancelable, err)
+	}
+}
+
+func TestCancelLSIFIndexUnauthenticated(t *testing.T) {
+	db := new(dbtesting.MockDB)
+
+	id := graphql.ID(base64.StdEncoding.EncodeToString([]byte("LSIFIndex:42")))
+	mockResolver := resolvermocks.NewMockResolver()
+
+	if _, err := NewResolver(db, mockResolver).CancelLSIFIndex(context.Background(), &struct{ ID graphql.ID }{id}); err != backend.ErrNotAuthenticated {
+		t.Errorf("unexpected error. want=%q have=%q", backend.ErrNotAuthenticated, err)
+	}
+}
+
 func TestMakeGetUploadsOptions(t *testing.T) {
 	t.Cleanup(func() {
 		database.Mocks.Repos.Get = nil
@@ -24,6 +24,9 @@ import (
 // github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers)
 // used for unit testing.
 type MockDBStore struct {
+	// CancelIndexByIDFunc is an instance of a mock function object
+	// controlling the behavior of the method CancelIndexByID.
+	CancelIndexByIDFunc *DBStoreCancelIndexByIDFunc
 	// CommitGraphMetadataFunc is an instance of a mock function object
 	// controlling the behavior of the method CommitGraphMetadata.
 	CommitGraphMetadataFunc *DBStoreCommitGraphMetadataFunc
@@ -93,6 +96,11 @@ type MockDBStore struct {
 // return zero values for all results, unless overwritten.
 func NewMockDBStore() *MockDBStore {
 	return &MockDBStore{
+		CancelIndexByIDFunc: &DBStoreCancelIndexByIDFunc{
+			defaultHook: func(context.Context, int) (bool, error) {
+				return false, nil
+			},
+		},
 		CommitGraphMetadataFunc: &DBStoreCommitGraphMetadataFunc{
 			defaultHook: func(context.Context, int) (bool, *time.Time, error) {
 				return false, nil, nil
@@ -200,6 +208,9 @@ func NewMockDBStore() *MockDBStore {
 // methods delegate to the given implementation, unless overwritten.
 func NewMockDBStoreFrom(i DBStore) *MockDBStore {
 	return &MockDBStore{
+		CancelIndexByIDFunc: &DBStoreCancelIndexByIDFunc{
+			defaultHook: i.CancelIndexByID,
+		},
 		CommitGraphMetadataFunc: &DBStoreCommitGraphMetadataFunc{
 			defaultHook: i.CommitGraphMetadata,
 		},
@@ -263,6 +274,115 @@ func NewMockDBStoreFrom(i DBStore) *MockDBStore {
 	}
 }
 
+// DBStoreCancelIndexByIDFunc describes the behavior when the
+// CancelIndexByID method of the parent MockDBStore instance is invoked.
+type DBStoreCancelIndexByIDFunc struct {
+	defaultHook func(context.Context, int) (bool, error)
+	hooks       []func(context.Context, int) (bool, error)
+	history     []DBStoreCancelIndexByIDFuncCall
+	mutex       sync.Mutex
+}
+
+// CancelIndexByID delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockDBStore) CancelIndexByID(v0 context.Context, v1 int) (bool, error) {
+	r0, r1 := m.CancelIndexByIDFunc.nextHook()(v0, v1)
+	m.CancelIndexByIDFunc.appendCall(DBStoreCancelIndexByIDFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the CancelIndexByID
+// method of the parent MockDBStore instance is invoked and the hook queue
+// is empty.
+func (f *DBStoreCancelIndexByIDFunc) SetDefaultHook(hook func(context.Context, int) (bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CancelIndexByID method of the parent MockDBStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *DBStoreCancelIndexByIDFunc) PushHook(hook func(context.Context, int) (bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBStoreCancelIndexByIDFunc) SetDefaultReturn(r0 bool, r1 error) {
+	f.SetDefaultHook(func(context.Context, int) (bool, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBStoreCancelIndexByIDFunc) PushReturn(r0 bool, r1 error) {
+	f.PushHook(func(context.Context, int) (bool, error) {
+		return r0, r1
+	})
+}
+
+func (f *DBStoreCancelIndexByIDFunc) nextHook() func(context.Context, int) (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBStoreCancelIndexByIDFunc) appendCall(r0 DBStoreCancelIndexByIDFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBStoreCancelIndexByIDFuncCall objects
+// describing the invocations of this function.
+func (f *DBStoreCancelIndexByIDFunc) History() []DBStoreCancelIndexByIDFuncCall {
+	f.mutex.Lock()
+	history := make([]DBStoreCancelIndexByIDFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBStoreCancelIndexByIDFuncCall is an object that describes an invocation
+// of method CancelIndexByID on an instance of MockDBStore.
+type DBStoreCancelIndexByIDFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 bool
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBStoreCancelIndexByIDFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBStoreCancelIndexByIDFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // DBStoreCommitGraphMetadataFunc describes the behavior when the
 // CommitGraphMetadata method of the parent MockDBStore instance is invoked.
 type DBStoreCommitGraphMetadataFunc struct {
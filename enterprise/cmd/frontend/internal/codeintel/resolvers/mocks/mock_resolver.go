@@ -16,6 +16,9 @@ import (
 // github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers)
 // used for unit testing.
 type MockResolver struct {
+	// CancelIndexByIDFunc is an instance of a mock function object
+	// controlling the behavior of the method CancelIndexByID.
+	CancelIndexByIDFunc *ResolverCancelIndexByIDFunc
 	// CommitGraphFunc is an instance of a mock function object controlling
 	// the behavior of the method CommitGraph.
 	CommitGraphFunc *ResolverCommitGraphFunc
@@ -62,6 +65,11 @@ type MockResolver struct {
 // return zero values for all results, unless overwritten.
 func NewMockResolver() *MockResolver {
 	return &MockResolver{
+		CancelIndexByIDFunc: &ResolverCancelIndexByIDFunc{
+			defaultHook: func(context.Context, int) (bool, error) {
+				return false, nil
+			},
+		},
 		CommitGraphFunc: &ResolverCommitGraphFunc{
 			defaultHook: func(context.Context, int) (graphqlbackend.CodeIntelligenceCommitGraphResolver, error) {
 				return nil, nil
@@ -134,6 +142,9 @@ func NewMockResolver() *MockResolver {
 // methods delegate to the given implementation, unless overwritten.
 func NewMockResolverFrom(i resolvers.Resolver) *MockResolver {
 	return &MockResolver{
+		CancelIndexByIDFunc: &ResolverCancelIndexByIDFunc{
+			defaultHook: i.CancelIndexByID,
+		},
 		CommitGraphFunc: &ResolverCommitGraphFunc{
 			defaultHook: i.CommitGraph,
 		},
@@ -176,6 +187,115 @@ func NewMockResolverFrom(i resolvers.Resolver) *MockResolver {
 	}
 }
 
+// ResolverCancelIndexByIDFunc describes the behavior when the
+// CancelIndexByID method of the parent MockResolver instance is invoked.
+type ResolverCancelIndexByIDFunc struct {
+	defaultHook func(context.Context, int) (bool, error)
+	hooks       []func(context.Context, int) (bool, error)
+	history     []ResolverCancelIndexByIDFuncCall
+	mutex       sync.Mutex
+}
+
+// CancelIndexByID delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockResolver) CancelIndexByID(v0 context.Context, v1 int) (bool, error) {
+	r0, r1 := m.CancelIndexByIDFunc.nextHook()(v0, v1)
+	m.CancelIndexByIDFunc.appendCall(ResolverCancelIndexByIDFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the CancelIndexByID
+// method of the parent MockResolver instance is invoked and the hook queue
+// is empty.
+func (f *ResolverCancelIndexByIDFunc) SetDefaultHook(hook func(context.Context, int) (bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CancelIndexByID method of the parent MockResolver instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *ResolverCancelIndexByIDFunc) PushHook(hook func(context.Context, int) (bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *ResolverCancelIndexByIDFunc) SetDefaultReturn(r0 bool, r1 error) {
+	f.SetDefaultHook(func(context.Context, int) (bool, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *ResolverCancelIndexByIDFunc) PushReturn(r0 bool, r1 error) {
+	f.PushHook(func(context.Context, int) (bool, error) {
+		return r0, r1
+	})
+}
+
+func (f *ResolverCancelIndexByIDFunc) nextHook() func(context.Context, int) (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ResolverCancelIndexByIDFunc) appendCall(r0 ResolverCancelIndexByIDFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ResolverCancelIndexByIDFuncCall objects
+// describing the invocations of this function.
+func (f *ResolverCancelIndexByIDFunc) History() []ResolverCancelIndexByIDFuncCall {
+	f.mutex.Lock()
+	history := make([]ResolverCancelIndexByIDFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ResolverCancelIndexByIDFuncCall is an object that describes an invocation
+// of method CancelIndexByID on an instance of MockResolver.
+type ResolverCancelIndexByIDFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 bool
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ResolverCancelIndexByIDFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ResolverCancelIndexByIDFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // ResolverCommitGraphFunc describes the behavior when the CommitGraph
 // method of the parent MockResolver instance is invoked.
 type ResolverCommitGraphFunc struct {
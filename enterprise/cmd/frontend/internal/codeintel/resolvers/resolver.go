@@ -28,6 +28,7 @@ type Resolver interface {
 	IndexConnectionResolver(opts store.GetIndexesOptions) *IndexesResolver
 	DeleteUploadByID(ctx context.Context, uploadID int) error
 	DeleteIndexByID(ctx context.Context, id int) error
+	CancelIndexByID(ctx context.Context, id int) (bool, error)
 	IndexConfiguration(ctx context.Context, repositoryID int) ([]byte, error)
 	UpdateIndexConfigurationByRepositoryID(ctx context.Context, repositoryID int, configuration string) error
 	CommitGraph(ctx context.Context, repositoryID int) (gql.CodeIntelligenceCommitGraphResolver, error)
@@ -108,6 +109,10 @@ func (r *resolver) DeleteIndexByID(ctx context.Context, id int) error {
 	return err
 }
 
+func (r *resolver) CancelIndexByID(ctx context.Context, id int) (bool, error) {
+	return r.dbStore.CancelIndexByID(ctx, id)
+}
+
 func (r *resolver) IndexConfiguration(ctx context.Context, repositoryID int) ([]byte, error) {
 	configuration, exists, err := r.dbStore.GetIndexConfigurationByRepositoryID(ctx, repositoryID)
 	if err != nil {
@@ -10,10 +10,11 @@ import (
 type Config struct {
 	env.BaseConfig
 
-	UploadStoreConfig  *uploadstore.Config
-	WorkerPollInterval time.Duration
-	WorkerConcurrency  int
-	WorkerBudget       int64
+	UploadStoreConfig                 *uploadstore.Config
+	WorkerPollInterval                time.Duration
+	WorkerConcurrency                 int
+	WorkerMaxConcurrencyPerRepository int
+	WorkerBudget                      int64
 }
 
 func (c *Config) Load() {
@@ -23,5 +24,6 @@ func (c *Config) Load() {
 
 	c.WorkerPollInterval = c.GetInterval("PRECISE_CODE_INTEL_WORKER_POLL_INTERVAL", "1s", "Interval between queries to the upload queue.")
 	c.WorkerConcurrency = c.GetInt("PRECISE_CODE_INTEL_WORKER_CONCURRENCY", "1", "The maximum number of indexes that can be processed concurrently.")
+	c.WorkerMaxConcurrencyPerRepository = c.GetInt("PRECISE_CODE_INTEL_WORKER_MAX_CONCURRENCY_PER_REPOSITORY", "0", "The maximum number of uploads of a single repository that can be processed concurrently across all workers. Zero disables the limit.")
 	c.WorkerBudget = int64(c.GetInt("PRECISE_CODE_INTEL_WORKER_BUDGET", "0", "The amount of compressed input data (in bytes) a worker can process concurrently. Zero acts as an infinite budget."))
 }
@@ -78,7 +78,7 @@ func main() {
 
 	// Initialize stores
 	dbStore := dbstore.NewWithDB(db, observationContext)
-	workerStore := dbstore.WorkerutilUploadStore(dbStore, observationContext)
+	workerStore := dbstore.WorkerutilUploadStore(dbStore, config.WorkerMaxConcurrencyPerRepository, observationContext)
 	lsifStore := lsifstore.NewStore(codeIntelDB, observationContext)
 	gitserverClient := gitserver.New(dbStore, observationContext)
 
@@ -44,7 +44,7 @@ func (j *janitorJob) Routines(ctx context.Context) ([]goroutine.BackgroundRoutin
 	}
 
 	dbStoreShim := &janitor.DBStoreShim{Store: dbStore}
-	uploadWorkerStore := dbstore.WorkerutilUploadStore(dbStoreShim, observationContext)
+	uploadWorkerStore := dbstore.WorkerutilUploadStore(dbStoreShim, 0, observationContext)
 	indexWorkerStore := dbstore.WorkerutilIndexStore(dbStoreShim, observationContext)
 	metrics := janitor.NewMetrics(observationContext)
 
@@ -10,6 +10,7 @@ import (
 	"github.com/inconshreveable/log15"
 
 	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/authz/azuredevops"
 	"github.com/sourcegraph/sourcegraph/internal/authz/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/authz/github"
 	"github.com/sourcegraph/sourcegraph/internal/authz/gitlab"
@@ -53,6 +54,7 @@ func ProvidersFromConfig(
 			extsvc.KindGitLab,
 			extsvc.KindBitbucketServer,
 			extsvc.KindPerforce,
+			extsvc.KindAzureDevOps,
 		},
 		LimitOffset: &database.LimitOffset{
 			Limit: 500, // The number is randomly chosen
@@ -64,6 +66,7 @@ func ProvidersFromConfig(
 		gitLabConns          []*types.GitLabConnection
 		bitbucketServerConns []*types.BitbucketServerConnection
 		perforceConns        []*types.PerforceConnection
+		azureDevOpsConns     []*types.AzureDevOpsConnection
 	)
 	for {
 		svcs, err := store.List(ctx, opt)
@@ -104,6 +107,11 @@ func ProvidersFromConfig(
 					URN:                svc.URN(),
 					PerforceConnection: c,
 				})
+			case *schema.AzureDevOpsConnection:
+				azureDevOpsConns = append(azureDevOpsConns, &types.AzureDevOpsConnection{
+					URN:                   svc.URN(),
+					AzureDevOpsConnection: c,
+				})
 			default:
 				log15.Error("ProvidersFromConfig", "error", errors.Errorf("unexpected connection type: %T", cfg))
 				continue
@@ -144,6 +152,13 @@ func ProvidersFromConfig(
 		warnings = append(warnings, pfWarnings...)
 	}
 
+	if len(azureDevOpsConns) > 0 {
+		adoProviders, adoProblems, adoWarnings := azuredevops.NewAuthzProviders(cfg, azureDevOpsConns)
+		providers = append(providers, adoProviders...)
+		seriousProblems = append(seriousProblems, adoProblems...)
+		warnings = append(warnings, adoWarnings...)
+	}
+
 	// 🚨 SECURITY: Warn the admin when both code host authz provider and the permissions user mapping are configured.
 	if cfg.SiteConfiguration.PermissionsUserMapping != nil &&
 		cfg.SiteConfiguration.PermissionsUserMapping.Enabled {
@@ -506,6 +506,7 @@ type fakeStore struct {
 	githubs          []*schema.GitHubConnection
 	bitbucketServers []*schema.BitbucketServerConnection
 	perforces        []*schema.PerforceConnection
+	azureDevOpses    []*schema.AzureDevOpsConnection
 }
 
 func (s fakeStore) List(ctx context.Context, opt database.ExternalServicesListOptions) ([]*types.ExternalService, error) {
@@ -548,6 +549,13 @@ func (s fakeStore) List(ctx context.Context, opt database.ExternalServicesListOp
 					Config: mustMarshalJSONString(p),
 				})
 			}
+		case extsvc.KindAzureDevOps:
+			for _, ado := range s.azureDevOpses {
+				svcs = append(svcs, &types.ExternalService{
+					Kind:   kind,
+					Config: mustMarshalJSONString(ado),
+				})
+			}
 		default:
 			return nil, errors.Errorf("unexpected kind: %s", kind)
 		}
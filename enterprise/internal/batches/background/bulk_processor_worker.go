@@ -69,7 +69,11 @@ func createBulkOperationDBWorkerStore(s *store.Store) dbworkerstore.Store {
 		ColumnExpressions: store.ChangesetJobColumns.ToSqlf(),
 		Scan:              scanFirstChangesetJobRecord,
 
-		OrderByExpression: sqlf.Sprintf("changeset_jobs.state = 'errored', changeset_jobs.updated_at DESC"),
+		// Freshly enqueued jobs have a higher priority than retried ones, and the
+		// bulk operations of different users are processed in round-robin.
+		PriorityExpression:    sqlf.Sprintf("changeset_jobs.state != 'errored'"),
+		FairnessKeyExpression: sqlf.Sprintf("changeset_jobs.user_id"),
+		OrderByExpression:     sqlf.Sprintf("changeset_jobs.updated_at DESC"),
 
 		HeartbeatInterval: 15 * time.Second,
 		StalledMaxAge:     60 * time.Second,
@@ -37,8 +37,10 @@ var executorWorkerStoreOptions = dbworkerstore.Options{
 	ColumnExpressions: store.BatchSpecExecutionColumns,
 	Scan:              scanFirstExecutionRecord,
 	OrderByExpression: sqlf.Sprintf("batch_spec_executions.created_at, batch_spec_executions.id"),
-	StalledMaxAge:     executorStalledJobMaximumAge,
-	MaxNumResets:      executorMaximumNumResets,
+	// Run the executions of different users in round-robin.
+	FairnessKeyExpression: sqlf.Sprintf("batch_spec_executions.user_id"),
+	StalledMaxAge:         executorStalledJobMaximumAge,
+	MaxNumResets:          executorMaximumNumResets,
 	// Explicitly disable retries.
 	MaxNumRetries: 0,
 }
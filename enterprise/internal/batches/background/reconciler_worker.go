@@ -74,10 +74,13 @@ func createReconcilerDBWorkerStore(s *store.Store) dbworkerstore.Store {
 		ColumnExpressions:    store.ChangesetColumns,
 		Scan:                 scanFirstChangesetRecord,
 
-		// Order changesets by state, so that freshly enqueued changesets have
-		// higher priority.
-		// If state is equal, prefer the newer ones.
-		OrderByExpression: sqlf.Sprintf("changesets.reconciler_state = 'errored', changesets.updated_at DESC"),
+		// Freshly enqueued changesets have a higher priority than retried ones.
+		PriorityExpression: sqlf.Sprintf("changesets.reconciler_state != 'errored'"),
+		// Reconcile the changesets of different batch changes in round-robin,
+		// so that a batch change with many changesets does not starve the others.
+		FairnessKeyExpression: sqlf.Sprintf("changesets.owned_by_batch_change_id"),
+		// Within a batch change, prefer the newer changesets.
+		OrderByExpression: sqlf.Sprintf("changesets.updated_at DESC"),
 
 		HeartbeatInterval: 15 * time.Second,
 		StalledMaxAge:     60 * time.Second,
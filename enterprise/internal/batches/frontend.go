@@ -34,6 +34,7 @@ func InitFrontend(ctx context.Context, db dbutil.DB, outOfBandMigrationRunner *o
 	enterpriseServices.GitHubWebhook = webhooks.NewGitHubWebhook(cstore)
 	enterpriseServices.BitbucketServerWebhook = webhooks.NewBitbucketServerWebhook(cstore)
 	enterpriseServices.GitLabWebhook = webhooks.NewGitLabWebhook(cstore)
+	enterpriseServices.AzureDevOpsWebhook = webhooks.NewAzureDevOpsWebhook(cstore)
 
 	return background.RegisterMigrations(cstore, outOfBandMigrationRunner)
 }
@@ -127,7 +127,7 @@ func (r *batchSpecExecutionStepsResolver) Setup() []graphqlbackend.ExecutionLogE
 
 func (r *batchSpecExecutionStepsResolver) SrcPreview() graphqlbackend.ExecutionLogEntryResolver {
 	if entry, ok := r.findExecutionLogEntry("step.src.0"); ok {
-		return graphqlbackend.NewExecutionLogEntryResolver(r.store.DB(), entry)
+		return graphqlbackend.NewExecutionLogEntryResolver(r.store.DB(), entry, r.executionLogEntryRecord())
 	}
 
 	return nil
@@ -137,6 +137,12 @@ func (r *batchSpecExecutionStepsResolver) Teardown() []graphqlbackend.ExecutionL
 	return r.executionLogEntryResolversWithPrefix("teardown.")
 }
 
+// executionLogEntryRecord returns the state of the execution which determines whether
+// its execution log entries are still running.
+func (r *batchSpecExecutionStepsResolver) executionLogEntryRecord() graphqlbackend.ExecutionLogEntryRecord {
+	return graphqlbackend.ExecutionLogEntryRecord{State: string(r.exec.State), FinishedAt: r.exec.FinishedAt}
+}
+
 func (r *batchSpecExecutionStepsResolver) findExecutionLogEntry(key string) (workerutil.ExecutionLogEntry, bool) {
 	for _, entry := range r.exec.ExecutionLogs {
 		if entry.Key == key {
@@ -153,7 +159,7 @@ func (r *batchSpecExecutionStepsResolver) executionLogEntryResolversWithPrefix(p
 		if !strings.HasPrefix(entry.Key, prefix) {
 			continue
 		}
-		r := graphqlbackend.NewExecutionLogEntryResolver(r.store.DB(), entry)
+		r := graphqlbackend.NewExecutionLogEntryResolver(r.store.DB(), entry, r.executionLogEntryRecord())
 		resolvers = append(resolvers, r)
 	}
 
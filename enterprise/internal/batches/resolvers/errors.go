@@ -92,6 +92,16 @@ func (e ErrMatchingBatchChangeExists) Extensions() map[string]interface{} {
 	return map[string]interface{}{"code": "ErrMatchingBatchChangeExists"}
 }
 
+type ErrBatchSpecExecutionNotCancelable struct{}
+
+func (e ErrBatchSpecExecutionNotCancelable) Error() string {
+	return "the batch spec execution is not queued or processing"
+}
+
+func (e ErrBatchSpecExecutionNotCancelable) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": "ErrBatchSpecExecutionNotCancelable"}
+}
+
 type ErrDuplicateCredential struct{}
 
 func (e ErrDuplicateCredential) Error() string {
@@ -19,6 +19,7 @@ import (
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/licensing"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
@@ -564,6 +565,111 @@ func (r *Resolver) CreateBatchSpec(ctx context.Context, args *graphqlbackend.Cre
 	return specResolver, nil
 }
 
+func (r *Resolver) CreateBatchSpecFromStructuralReplace(ctx context.Context, args *graphqlbackend.CreateBatchSpecFromStructuralReplaceArgs) (graphqlbackend.BatchSpecResolver, error) {
+	var err error
+	tr, ctx := trace.New(ctx, "Resolver.CreateBatchSpecFromStructuralReplace", fmt.Sprintf("Namespace %s, Query %q", args.Namespace, args.Query))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	if err := batchChangesEnabled(ctx, r.store.DB()); err != nil {
+		return nil, err
+	}
+
+	if err := batchChangesCreateAccess(ctx); err != nil {
+		return nil, err
+	}
+
+	act := actor.FromContext(ctx)
+	if !act.IsAuthenticated() {
+		return nil, backend.ErrNotAuthenticated
+	}
+
+	opts := service.CreateBatchSpecOpts{}
+	err = graphqlbackend.UnmarshalNamespaceID(args.Namespace, &opts.NamespaceUserID, &opts.NamespaceOrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.RawSpec, err = structuralReplaceBatchSpec(args)
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := structuralReplaceQuery(args.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: The search only returns results from repositories that the user
+	// (and the scopes of their access token) can access.
+	impl, err := graphqlbackend.NewSearchImplementer(ctx, r.store.DB(), &graphqlbackend.SearchArgs{
+		Version: "V2",
+		Query:   q,
+	})
+	if err != nil {
+		return nil, err
+	}
+	results, err := impl.Results(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if alert := results.Alert(); alert != nil {
+		return nil, errors.New(alert.Title())
+	}
+	if err := structuralReplaceIncomplete(results); err != nil {
+		return nil, err
+	}
+
+	diffs, err := structuralReplaceDiffs(results.Matches)
+	if err != nil {
+		return nil, err
+	}
+	if len(diffs) == 0 {
+		return nil, errNoStructuralReplaceDiffs
+	}
+
+	if err := checkLicense(); err != nil {
+		if licensing.IsFeatureNotActivated(err) {
+			if len(diffs) > maxUnlicensedChangesets {
+				return nil, ErrBatchChangesUnlicensed{err}
+			}
+		} else {
+			return nil, err
+		}
+	}
+
+	svc := service.New(r.store)
+	for _, d := range diffs {
+		baseRef, err := structuralReplaceBaseRef(ctx, r.store.DB(), d)
+		if err != nil {
+			return nil, err
+		}
+		rawSpec, err := structuralReplaceChangesetSpec(args, d, baseRef)
+		if err != nil {
+			return nil, err
+		}
+		spec, err := svc.CreateChangesetSpec(ctx, rawSpec, act.UID)
+		if err != nil {
+			return nil, err
+		}
+		opts.ChangesetSpecRandIDs = append(opts.ChangesetSpecRandIDs, spec.RandID)
+	}
+
+	batchSpec, err := svc.CreateBatchSpec(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	eventArg := &batchSpecCreatedArg{ChangesetSpecsCount: len(opts.ChangesetSpecRandIDs)}
+	if err := logBackendEvent(ctx, r.store.DB(), "BatchSpecCreated", eventArg); err != nil {
+		return nil, err
+	}
+
+	return &batchSpecResolver{store: r.store, batchSpec: batchSpec}, nil
+}
+
 func (r *Resolver) CreateChangesetSpec(ctx context.Context, args *graphqlbackend.CreateChangesetSpecArgs) (graphqlbackend.ChangesetSpecResolver, error) {
 	var err error
 	tr, ctx := trace.New(ctx, "Resolver.CreateChangesetSpec", "")
@@ -1459,6 +1565,13 @@ func (r *Resolver) CreateBatchSpecExecution(ctx context.Context, args *graphqlba
 		return nil, err
 	}
 
+	// 🚨 SECURITY: The repositories a batch spec is executed in are only known
+	// once it runs, so access tokens whose batches:write scope is restricted to
+	// some repositories can't execute batch specs.
+	if _, restricted := authz.RepoPattern(ctx, authz.ScopeBatchesWrite); restricted {
+		return nil, &authz.InsufficientScopeError{Scope: authz.ScopeBatchesWrite}
+	}
+
 	actor := actor.FromContext(ctx)
 
 	exec := &btypes.BatchSpecExecution{
@@ -1474,6 +1587,46 @@ func (r *Resolver) CreateBatchSpecExecution(ctx context.Context, args *graphqlba
 	return r.batchSpecExecutionByID(ctx, marshalBatchSpecExecutionRandID(exec.RandID))
 }
 
+func (r *Resolver) CancelBatchSpecExecution(ctx context.Context, args *graphqlbackend.CancelBatchSpecExecutionArgs) (_ graphqlbackend.BatchSpecExecutionResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.CancelBatchSpecExecution", fmt.Sprintf("BatchSpecExecution: %q", args.BatchSpecExecution))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	if err := batchChangesEnabled(ctx, r.store.DB()); err != nil {
+		return nil, err
+	}
+
+	randID, err := unmarshalBatchSpecExecutionRandID(args.BatchSpecExecution)
+	if err != nil {
+		return nil, err
+	}
+
+	if randID == "" {
+		return nil, ErrIDIsZero{}
+	}
+
+	exec, err := r.store.GetBatchSpecExecution(ctx, store.GetBatchSpecExecutionOpts{RandID: randID})
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Only site admins or the creator of the execution can cancel it.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, r.store.DB(), exec.UserID); err != nil {
+		return nil, err
+	}
+
+	if _, err := r.store.CancelBatchSpecExecution(ctx, randID); err != nil {
+		if err == store.ErrNoResults {
+			return nil, ErrBatchSpecExecutionNotCancelable{}
+		}
+		return nil, err
+	}
+
+	return r.batchSpecExecutionByID(ctx, args.BatchSpecExecution)
+}
+
 func parseBatchChangeState(s *string) (btypes.BatchChangeState, error) {
 	if s == nil {
 		return btypes.BatchChangeStateAny, nil
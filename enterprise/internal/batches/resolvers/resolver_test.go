@@ -25,6 +25,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/licensing"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
@@ -1636,6 +1637,20 @@ func TestResolver_CreateBatchSpecExecution(t *testing.T) {
 	if diff := cmp.Diff(want, response.CreateBatchSpecExecution); diff != "" {
 		t.Fatalf("invalid execution returned, diff=%s", diff)
 	}
+
+	t.Run("repository-scoped access token", func(t *testing.T) {
+		a := actor.FromUser(userID)
+		a.Scopes = []string{authz.ScopeBatchesWrite + "@^github\\.com/sourcegraph/"}
+		scopedCtx := actor.WithActor(ctx, a)
+
+		errs := apitest.Exec(scopedCtx, t, s, input, &response, mutationCreateBatchSpecExecution)
+		if len(errs) != 1 {
+			t.Fatalf("expected single error, got %v", errs)
+		}
+		if have, want := errs[0].Message, `access token does not have scope "batches:write"`; have != want {
+			t.Fatalf("wrong error. want=%q, have=%q", want, have)
+		}
+	})
 }
 
 const mutationCreateBatchSpecExecution = `
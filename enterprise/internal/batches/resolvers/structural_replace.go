@@ -0,0 +1,232 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// The author of the commits of changeset specs created from a structural
+// search, which is the same as the default author that src-cli uses.
+const (
+	structuralReplaceAuthorName  = "Sourcegraph"
+	structuralReplaceAuthorEmail = "batch-changes@sourcegraph.com"
+)
+
+// errNoStructuralReplaceDiffs is returned by
+// CreateBatchSpecFromStructuralReplace if the search didn't rewrite any files.
+var errNoStructuralReplaceDiffs = errors.New("the search didn't rewrite any files: use a structural search with a replace: value that matches at least one file")
+
+// structuralReplaceQuery returns the query to run for a structural search and
+// replace. Unless the query sets a count: itself, all results are returned
+// rather than the default number, so that the batch change covers every match.
+func structuralReplaceQuery(q string) (string, error) {
+	plan, err := query.Pipeline(query.InitStructural(q))
+	if err != nil {
+		return "", err
+	}
+	for _, basic := range plan {
+		if basic.GetCount() != "" {
+			return q, nil
+		}
+	}
+	return q + " count:all", nil
+}
+
+// structuralReplaceIncomplete returns an error if the structural search may
+// have missed matches, in which case the batch change would only cover some
+// of them.
+func structuralReplaceIncomplete(results *graphqlbackend.SearchResultsResolver) error {
+	count := func(status search.RepoStatus) (n int) {
+		results.Stats.Status.Filter(status, func(api.RepoID) { n++ })
+		return n
+	}
+	if results.LimitHit() {
+		return errors.New("the search hit a result limit: raise the count: of the query or narrow it down so that all matches are rewritten")
+	}
+	if n := count(search.RepoStatusTimedout); n > 0 {
+		return errors.Errorf("the search timed out in %d repositories: raise the timeout: of the query or narrow it down so that all matches are rewritten", n)
+	}
+	if n := count(search.RepoStatusCloning); n > 0 {
+		return errors.Errorf("%d repositories are still being cloned: try again once they are cloned", n)
+	}
+	return nil
+}
+
+// repoDiff is the combined diff of all files of a repository that a structural
+// search rewrote.
+type repoDiff struct {
+	Repo     types.RepoName
+	Rev      string
+	CommitID api.CommitID
+	Diff     string
+}
+
+// structuralReplaceDiffs combines the diffs of the file matches in matches by
+// repository. Matches without a diff are ignored.
+func structuralReplaceDiffs(matches []result.Match) ([]*repoDiff, error) {
+	byRepo := map[api.RepoID]*repoDiff{}
+	var diffs []*repoDiff
+	files := map[api.RepoID][]*result.FileMatch{}
+	for _, m := range matches {
+		fm, ok := m.(*result.FileMatch)
+		if !ok || fm.Diff == "" {
+			continue
+		}
+
+		var rev string
+		if fm.InputRev != nil {
+			rev = *fm.InputRev
+		}
+
+		d, ok := byRepo[fm.Repo.ID]
+		if !ok {
+			d = &repoDiff{Repo: fm.Repo, Rev: rev, CommitID: fm.CommitID}
+			byRepo[fm.Repo.ID] = d
+			diffs = append(diffs, d)
+		} else if d.CommitID != fm.CommitID {
+			return nil, errors.Errorf("repository %q was searched at more than one revision, but a changeset can only be based on one", fm.Repo.Name)
+		}
+		files[fm.Repo.ID] = append(files[fm.Repo.ID], fm)
+	}
+
+	for _, d := range diffs {
+		fms := files[d.Repo.ID]
+		sort.Slice(fms, func(i, j int) bool { return fms[i].Path < fms[j].Path })
+
+		var diff strings.Builder
+		for _, fm := range fms {
+			diff.WriteString(fm.Diff)
+		}
+		d.Diff = diff.String()
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Repo.Name < diffs[j].Repo.Name })
+	return diffs, nil
+}
+
+// structuralReplaceBaseRef returns the ref that the changeset for d is
+// proposed to be merged into. It's the branch that was searched, or the
+// default branch if the search didn't specify one. Other revisions, like tags
+// or commits, are rejected since a changeset can't be merged into them.
+func structuralReplaceBaseRef(ctx context.Context, db dbutil.DB, d *repoDiff) (string, error) {
+	if d.Rev != "" && d.Rev != "HEAD" {
+		ref := "refs/heads/" + strings.TrimPrefix(d.Rev, "refs/heads/")
+		_, err := git.ResolveRevision(ctx, d.Repo.Name, ref, git.ResolveRevisionOptions{NoEnsureRevision: true})
+		if errors.HasType(err, &gitserver.RevisionNotFoundError{}) {
+			return "", errors.Errorf("revision %q of repository %q is not a branch: search a branch so that the changeset can be merged into it", d.Rev, d.Repo.Name)
+		}
+		if err != nil {
+			return "", err
+		}
+		return ref, nil
+	}
+
+	repo := graphqlbackend.NewRepositoryResolver(db, &types.Repo{ID: d.Repo.ID, Name: d.Repo.Name})
+	ref, err := repo.DefaultBranch(ctx)
+	if err != nil {
+		return "", err
+	}
+	if ref == nil {
+		return "", errors.Errorf("repository %q has no default branch", d.Repo.Name)
+	}
+	return ref.Name(), nil
+}
+
+// structuralReplaceChangesetSpec returns the raw changeset spec that proposes
+// to merge the diff d into baseRef.
+func structuralReplaceChangesetSpec(args *graphqlbackend.CreateBatchSpecFromStructuralReplaceArgs, d *repoDiff, baseRef string) (string, error) {
+	repoID := graphqlbackend.MarshalRepositoryID(d.Repo.ID)
+	spec := btypes.ChangesetSpecDescription{
+		BaseRepository: repoID,
+		BaseRef:        baseRef,
+		BaseRev:        string(d.CommitID),
+		HeadRepository: repoID,
+		HeadRef:        "refs/heads/" + args.Branch,
+		Title:          args.Title,
+		Body:           structuralReplaceBody(args),
+		Commits: []btypes.GitCommitDescription{{
+			Message:     structuralReplaceCommitMessage(args),
+			Diff:        d.Diff,
+			AuthorName:  structuralReplaceAuthorName,
+			AuthorEmail: structuralReplaceAuthorEmail,
+		}},
+	}
+
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+// structuralReplaceBatchSpec returns the raw batch spec for the changeset
+// specs created from a structural search.
+func structuralReplaceBatchSpec(args *graphqlbackend.CreateBatchSpecFromStructuralReplaceArgs) (string, error) {
+	// This mirrors the subset of the batch spec schema that is used here.
+	type commit struct {
+		Message string `json:"message"`
+	}
+	type changesetTemplate struct {
+		Title  string `json:"title"`
+		Body   string `json:"body,omitempty"`
+		Branch string `json:"branch"`
+		Commit commit `json:"commit"`
+	}
+	spec := struct {
+		Name              string            `json:"name"`
+		Description       string            `json:"description,omitempty"`
+		ChangesetTemplate changesetTemplate `json:"changesetTemplate"`
+	}{
+		Name:        args.Name,
+		Description: derefString(args.Description),
+		ChangesetTemplate: changesetTemplate{
+			Title:  args.Title,
+			Body:   structuralReplaceBody(args),
+			Branch: args.Branch,
+			Commit: commit{Message: structuralReplaceCommitMessage(args)},
+		},
+	}
+
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+func structuralReplaceCommitMessage(args *graphqlbackend.CreateBatchSpecFromStructuralReplaceArgs) string {
+	if args.CommitMessage != nil && *args.CommitMessage != "" {
+		return *args.CommitMessage
+	}
+	return args.Title
+}
+
+func structuralReplaceBody(args *graphqlbackend.CreateBatchSpecFromStructuralReplaceArgs) string {
+	if args.Body != nil && *args.Body != "" {
+		return *args.Body
+	}
+	return fmt.Sprintf("Rewritten by the structural search `%s`.", args.Query)
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,193 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestStructuralReplaceDiffs(t *testing.T) {
+	repoA := types.RepoName{ID: 1, Name: "github.com/sourcegraph/a"}
+	repoB := types.RepoName{ID: 2, Name: "github.com/sourcegraph/b"}
+	rev := "feature"
+
+	fileMatch := func(repo types.RepoName, commit api.CommitID, path, diff string) *result.FileMatch {
+		return &result.FileMatch{
+			File: result.File{Repo: repo, CommitID: commit, InputRev: &rev, Path: path},
+			Diff: diff,
+		}
+	}
+
+	t.Run("combines diffs by repository", func(t *testing.T) {
+		diffs, err := structuralReplaceDiffs([]result.Match{
+			fileMatch(repoB, "c2", "main.go", "b/main.go\n"),
+			fileMatch(repoA, "c1", "z.go", "a/z.go\n"),
+			&result.RepoMatch{Name: repoA.Name, ID: repoA.ID},
+			fileMatch(repoA, "c1", "no_diff.go", ""),
+			fileMatch(repoA, "c1", "b.go", "a/b.go\n"),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []*repoDiff{
+			{Repo: repoA, Rev: rev, CommitID: "c1", Diff: "a/b.go\na/z.go\n"},
+			{Repo: repoB, Rev: rev, CommitID: "c2", Diff: "b/main.go\n"},
+		}
+		if diff := cmp.Diff(want, diffs); diff != "" {
+			t.Fatalf("unexpected diffs (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("more than one revision", func(t *testing.T) {
+		_, err := structuralReplaceDiffs([]result.Match{
+			fileMatch(repoA, "c1", "a.go", "a/a.go\n"),
+			fileMatch(repoA, "c2", "a.go", "a/a.go\n"),
+		})
+		if err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestStructuralReplaceSpecs(t *testing.T) {
+	commitMessage := "Use fmt.Errorf"
+	args := &graphqlbackend.CreateBatchSpecFromStructuralReplaceArgs{
+		Query:         "errors.New(fmt.Sprintf(:[args])) replace:fmt.Errorf(:[args]) patterntype:structural",
+		Name:          "use-errorf",
+		Title:         "Use fmt.Errorf instead of errors.New(fmt.Sprintf(...))",
+		Branch:        "use-errorf",
+		CommitMessage: &commitMessage,
+	}
+
+	rawBatchSpec, err := structuralReplaceBatchSpec(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	batchSpec, err := btypes.NewBatchSpecFromRaw(rawBatchSpec)
+	if err != nil {
+		t.Fatalf("invalid batch spec %s: %s", rawBatchSpec, err)
+	}
+	if have, want := batchSpec.Spec.ChangesetTemplate.Commit.Message, commitMessage; have != want {
+		t.Errorf("wrong commit message: have %q, want %q", have, want)
+	}
+
+	d := &repoDiff{
+		Repo:     types.RepoName{ID: 1, Name: "github.com/sourcegraph/a"},
+		CommitID: "d34db33f",
+		Diff:     "--- a/main.go\n+++ b/main.go\n@@ -1,1 +1,1 @@\n-a\n+b\n",
+	}
+	rawChangesetSpec, err := structuralReplaceChangesetSpec(args, d, "refs/heads/main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	changesetSpec, err := btypes.NewChangesetSpecFromRaw(rawChangesetSpec)
+	if err != nil {
+		t.Fatalf("invalid changeset spec %s: %s", rawChangesetSpec, err)
+	}
+
+	want := &btypes.ChangesetSpecDescription{
+		BaseRepository: graphqlbackend.MarshalRepositoryID(1),
+		BaseRef:        "refs/heads/main",
+		BaseRev:        "d34db33f",
+		HeadRepository: graphqlbackend.MarshalRepositoryID(1),
+		HeadRef:        "refs/heads/use-errorf",
+		Title:          args.Title,
+		Body:           "Rewritten by the structural search `errors.New(fmt.Sprintf(:[args])) replace:fmt.Errorf(:[args]) patterntype:structural`.",
+		Commits: []btypes.GitCommitDescription{{
+			Message:     commitMessage,
+			Diff:        d.Diff,
+			AuthorName:  structuralReplaceAuthorName,
+			AuthorEmail: structuralReplaceAuthorEmail,
+		}},
+	}
+	if diff := cmp.Diff(want, changesetSpec.Spec); diff != "" {
+		t.Fatalf("unexpected changeset spec (-want +got):\n%s", diff)
+	}
+}
+
+func TestStructuralReplaceQuery(t *testing.T) {
+	for query, want := range map[string]string{
+		"fmt.Sprintf(:[args]) replace:fmt.Errorf(:[args])":          "fmt.Sprintf(:[args]) replace:fmt.Errorf(:[args]) count:all",
+		"fmt.Sprintf(:[args]) replace:fmt.Errorf(:[args]) count:10": "fmt.Sprintf(:[args]) replace:fmt.Errorf(:[args]) count:10",
+		`"count::[n]" replace:"limit::[n]"`:                         `"count::[n]" replace:"limit::[n]" count:all`,
+	} {
+		got, err := structuralReplaceQuery(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("structuralReplaceQuery(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
+
+func TestStructuralReplaceBaseRef(t *testing.T) {
+	git.Mocks.ResolveRevision = func(spec string, opt git.ResolveRevisionOptions) (api.CommitID, error) {
+		if spec == "refs/heads/feature" {
+			return "d34db33f", nil
+		}
+		return "", &gitserver.RevisionNotFoundError{Repo: "github.com/sourcegraph/a", Spec: spec}
+	}
+	defer git.ResetMocks()
+
+	repo := types.RepoName{ID: 1, Name: "github.com/sourcegraph/a"}
+	for _, rev := range []string{"feature", "refs/heads/feature"} {
+		ref, err := structuralReplaceBaseRef(context.Background(), nil, &repoDiff{Repo: repo, Rev: rev, CommitID: "d34db33f"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "refs/heads/feature"; ref != want {
+			t.Errorf("structuralReplaceBaseRef for rev %q = %q, want %q", rev, ref, want)
+		}
+	}
+
+	for _, rev := range []string{"v1.0.0", "d34db33f"} {
+		if _, err := structuralReplaceBaseRef(context.Background(), nil, &repoDiff{Repo: repo, Rev: rev, CommitID: "d34db33f"}); err == nil {
+			t.Errorf("structuralReplaceBaseRef for rev %q: expected error", rev)
+		}
+	}
+}
+
+func TestStructuralReplaceIncomplete(t *testing.T) {
+	status := func(s search.RepoStatus) search.RepoStatusMap {
+		var m search.RepoStatusMap
+		m.Update(1, s)
+		return m
+	}
+	resolver := func(stats streaming.Stats) *graphqlbackend.SearchResultsResolver {
+		return &graphqlbackend.SearchResultsResolver{
+			SearchResults: &graphqlbackend.SearchResults{Stats: stats},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		stats   streaming.Stats
+		wantErr bool
+	}{
+		{name: "complete", stats: streaming.Stats{Repos: map[api.RepoID]types.RepoName{1: {ID: 1}}}},
+		{name: "limit hit", stats: streaming.Stats{IsLimitHit: true}, wantErr: true},
+		{name: "timed out", stats: streaming.Stats{Status: status(search.RepoStatusTimedout)}, wantErr: true},
+		{name: "cloning", stats: streaming.Stats{Status: status(search.RepoStatusCloning)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := structuralReplaceIncomplete(resolver(tt.stats))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -14,6 +14,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
@@ -157,7 +159,14 @@ func (s *Service) CreateChangesetSpec(ctx context.Context, rawSpec string, userI
 
 	// 🚨 SECURITY: We use database.Repos.Get to check whether the user has access to
 	// the repository or not.
-	if _, err = s.store.Repos().Get(ctx, spec.RepoID); err != nil {
+	repo, err := s.store.Repos().Get(ctx, spec.RepoID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Access tokens whose batches:write scope is restricted to some
+	// repositories can't create changesets in other repositories.
+	if err := authz.CheckRepoScope(ctx, authz.ScopeBatchesWrite, repo.Name); err != nil {
 		return nil, err
 	}
 
@@ -310,6 +319,18 @@ func (s *Service) CloseBatchChange(ctx context.Context, id int64, closeChangeset
 		return nil, err
 	}
 
+	if closeChangesets {
+		// 🚨 SECURITY: Access tokens whose batches:write scope is restricted to
+		// some repositories can't close changesets in other repositories.
+		cs, _, err := s.store.ListChangesets(ctx, store.ListChangesetsOpts{BatchChangeID: batchChange.ID})
+		if err != nil {
+			return nil, err
+		}
+		if err := s.checkRepoScope(ctx, cs.RepoIDs()...); err != nil {
+			return nil, err
+		}
+	}
+
 	tx, err := s.store.Transact(ctx)
 	if err != nil {
 		return nil, err
@@ -378,7 +399,14 @@ func (s *Service) EnqueueChangesetSync(ctx context.Context, id int64) (err error
 
 	// 🚨 SECURITY: We use database.Repos.Get to check whether the user has access to
 	// the repository or not.
-	if _, err = s.store.Repos().Get(ctx, changeset.RepoID); err != nil {
+	repo, err := s.store.Repos().Get(ctx, changeset.RepoID)
+	if err != nil {
+		return err
+	}
+
+	// 🚨 SECURITY: Access tokens whose batches:write scope is restricted to some
+	// repositories can't sync changesets in other repositories.
+	if err := authz.CheckRepoScope(ctx, authz.ScopeBatchesWrite, repo.Name); err != nil {
 		return err
 	}
 
@@ -437,6 +465,12 @@ func (s *Service) ReenqueueChangeset(ctx context.Context, id int64) (changeset *
 		return nil, nil, err
 	}
 
+	// 🚨 SECURITY: Access tokens whose batches:write scope is restricted to some
+	// repositories can't reenqueue changesets in other repositories.
+	if err := authz.CheckRepoScope(ctx, authz.ScopeBatchesWrite, repo.Name); err != nil {
+		return nil, nil, err
+	}
+
 	attachedBatchChanges, _, err := s.store.ListBatchChanges(ctx, store.ListBatchChangesOpts{ChangesetID: id})
 	if err != nil {
 		return nil, nil, err
@@ -469,6 +503,31 @@ func (s *Service) ReenqueueChangeset(ctx context.Context, id int64) (changeset *
 	return changeset, repo, nil
 }
 
+// checkRepoScope returns an error if the actor in ctx was authenticated with an
+// access token whose batches:write scope is restricted to repositories that
+// don't include all of the given repositories.
+func (s *Service) checkRepoScope(ctx context.Context, repoIDs ...api.RepoID) error {
+	if _, restricted := authz.RepoPattern(ctx, authz.ScopeBatchesWrite); !restricted || len(repoIDs) == 0 {
+		return nil
+	}
+
+	repos, err := s.store.Repos().GetReposSetByIDs(ctx, repoIDs...)
+	if err != nil {
+		return err
+	}
+	for _, id := range repoIDs {
+		repo, ok := repos[id]
+		if !ok {
+			// The token can't be scoped to a repository the user can't access.
+			return &authz.InsufficientScopeError{Scope: authz.ScopeBatchesWrite}
+		}
+		if err := authz.CheckRepoScope(ctx, authz.ScopeBatchesWrite, repo.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // checkNamespaceAccess checks whether the current user in the ctx has access
 // to either the user ID or the org ID as a namespace.
 // If the userID is non-zero that will be checked. Otherwise the org ID will be
@@ -603,6 +662,12 @@ func (s *Service) CreateChangesetJobs(ctx context.Context, batchChangeID int64,
 		return bulkGroupID, ErrChangesetsForJobNotFound
 	}
 
+	// 🚨 SECURITY: Access tokens whose batches:write scope is restricted to some
+	// repositories can't act on changesets in other repositories.
+	if err := s.checkRepoScope(ctx, cs.RepoIDs()...); err != nil {
+		return bulkGroupID, err
+	}
+
 	bulkGroupID, err = store.RandomID()
 	if err != nil {
 		return bulkGroupID, errors.Wrap(err, "creating bulkGroupID failed")
@@ -146,6 +146,13 @@ func (s *Service) ApplyBatchChange(ctx context.Context, opts ApplyBatchChangeOpt
 		return nil, err
 	}
 
+	// 🚨 SECURITY: Access tokens whose batches:write scope is restricted to some
+	// repositories can't create, update, close or detach changesets in other
+	// repositories.
+	if err := s.checkRepoScope(ctx, btypes.Changesets(changesets).RepoIDs()...); err != nil {
+		return nil, err
+	}
+
 	// Prepare the UI publication states. We need to do this within the
 	// transaction to avoid conflicting writes to the changeset specs.
 	if err := opts.PublicationStates.prepareAndValidate(mappings); err != nil {
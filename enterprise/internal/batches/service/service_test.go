@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"testing"
 	"time"
 
@@ -18,6 +19,7 @@ import (
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
@@ -26,6 +28,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
 	"github.com/sourcegraph/sourcegraph/internal/timeutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
 func TestServicePermissionLevels(t *testing.T) {
@@ -170,6 +173,120 @@ func TestServicePermissionLevels(t *testing.T) {
 	}
 }
 
+func TestServiceRepoScopes(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := backend.WithAuthzBypass(context.Background())
+	db := dbtest.NewDB(t, "")
+
+	s := store.New(db, nil)
+	svc := New(s)
+
+	user := ct.CreateTestUser(t, db, false)
+	rs, _ := ct.CreateTestRepos(t, ctx, db, 2)
+	inScope, outOfScope := rs[0], rs[1]
+
+	// The access token may only act on changesets in the first repository.
+	a := actor.FromUser(user.ID)
+	a.Scopes = []string{authz.ScopeBatchesWrite + "@^" + regexp.QuoteMeta(string(inScope.Name)) + "$"}
+	scopedCtx := actor.WithActor(context.Background(), a)
+
+	createTestData := func(t *testing.T, repo *types.Repo) (*btypes.BatchChange, *btypes.Changeset) {
+		spec := testBatchSpec(user.ID)
+		if err := s.CreateBatchSpec(ctx, spec); err != nil {
+			t.Fatal(err)
+		}
+
+		batchChange := testBatchChange(user.ID, spec)
+		if err := s.CreateBatchChange(ctx, batchChange); err != nil {
+			t.Fatal(err)
+		}
+
+		changeset := testChangeset(repo.ID, batchChange.ID, btypes.ChangesetExternalStateOpen)
+		if err := s.CreateChangeset(ctx, changeset); err != nil {
+			t.Fatal(err)
+		}
+
+		return batchChange, changeset
+	}
+
+	assertScopeError := func(t *testing.T, err error) {
+		t.Helper()
+
+		if !errors.HasType(err, &authz.InsufficientScopeError{}) {
+			t.Fatalf("want insufficient scope error, got %v (%T)", err, err)
+		}
+	}
+
+	repoupdater.MockEnqueueChangesetSync = func(ctx context.Context, ids []int64) error {
+		return nil
+	}
+	t.Cleanup(func() { repoupdater.MockEnqueueChangesetSync = nil })
+
+	t.Run("syncChangeset", func(t *testing.T) {
+		_, changeset := createTestData(t, outOfScope)
+		assertScopeError(t, svc.EnqueueChangesetSync(scopedCtx, changeset.ID))
+
+		_, changeset = createTestData(t, inScope)
+		if err := svc.EnqueueChangesetSync(scopedCtx, changeset.ID); err != nil {
+			t.Fatalf("unexpected error for changeset in scope: %s", err)
+		}
+	})
+
+	t.Run("reenqueueChangeset", func(t *testing.T) {
+		_, changeset := createTestData(t, outOfScope)
+		_, _, err := svc.ReenqueueChangeset(scopedCtx, changeset.ID)
+		assertScopeError(t, err)
+	})
+
+	t.Run("closeBatchChange", func(t *testing.T) {
+		batchChange, _ := createTestData(t, outOfScope)
+		_, err := svc.CloseBatchChange(scopedCtx, batchChange.ID, true)
+		assertScopeError(t, err)
+
+		have, err := s.GetBatchChange(ctx, store.GetBatchChangeOpts{ID: batchChange.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have.Closed() {
+			t.Fatal("batch change was closed")
+		}
+	})
+
+	t.Run("applyBatchChange", func(t *testing.T) {
+		batchSpec := ct.CreateBatchSpec(t, ctx, s, "repo-scopes", user.ID)
+		ct.CreateChangesetSpec(t, ctx, s, ct.TestSpecOpts{
+			User:      user.ID,
+			Repo:      outOfScope.ID,
+			BatchSpec: batchSpec.ID,
+			HeadRef:   "refs/heads/my-branch",
+		})
+
+		_, err := svc.ApplyBatchChange(scopedCtx, ApplyBatchChangeOpts{BatchSpecRandID: batchSpec.RandID})
+		assertScopeError(t, err)
+	})
+
+	for name, job := range map[string]struct {
+		jobType btypes.ChangesetJobType
+		payload interface{}
+	}{
+		"createChangesetComments": {btypes.ChangesetJobTypeComment, btypes.ChangesetJobCommentPayload{Message: "test"}},
+		"detachChangesets":        {btypes.ChangesetJobTypeDetach, btypes.ChangesetJobDetachPayload{}},
+		"reenqueueChangesets":     {btypes.ChangesetJobTypeReenqueue, btypes.ChangesetJobReenqueuePayload{}},
+		"mergeChangesets":         {btypes.ChangesetJobTypeMerge, btypes.ChangesetJobMergePayload{}},
+		"closeChangesets":         {btypes.ChangesetJobTypeClose, btypes.ChangesetJobClosePayload{}},
+		"publishChangesets":       {btypes.ChangesetJobTypePublish, btypes.ChangesetJobPublishPayload{}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			batchChange, changeset := createTestData(t, outOfScope)
+			_, err := svc.CreateChangesetJobs(scopedCtx, batchChange.ID, []int64{changeset.ID}, job.jobType, job.payload, store.ListChangesetsOpts{})
+			assertScopeError(t, err)
+		})
+	}
+}
+
 func TestService(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -0,0 +1,272 @@
+package sources
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type AzureDevOpsSource struct {
+	client *azuredevops.Client
+	au     auth.Authenticator
+	// org is the organization used to validate authenticators. Tokens are
+	// scoped to organizations, so any synced organization will do.
+	org string
+}
+
+// NewAzureDevOpsSource returns a new AzureDevOpsSource from the given external
+// service.
+func NewAzureDevOpsSource(svc *types.ExternalService, cf *httpcli.Factory) (*AzureDevOpsSource, error) {
+	var c schema.AzureDevOpsConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	return newAzureDevOpsSource(&c, cf, nil)
+}
+
+func newAzureDevOpsSource(c *schema.AzureDevOpsConnection, cf *httpcli.Factory, au auth.Authenticator) (*AzureDevOpsSource, error) {
+	baseURL, err := url.Parse(c.Url)
+	if err != nil {
+		return nil, err
+	}
+	baseURL = extsvc.NormalizeBaseURL(baseURL)
+
+	if cf == nil {
+		cf = httpcli.NewExternalHTTPClientFactory()
+	}
+
+	cli, err := cf.Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	authr := au
+	if authr == nil && c.Token != "" {
+		authr = &auth.BasicAuth{Password: c.Token}
+	}
+
+	var org string
+	if len(c.Orgs) > 0 {
+		org = c.Orgs[0]
+	} else if len(c.Projects) > 0 {
+		org = strings.SplitN(c.Projects[0], "/", 2)[0]
+	}
+
+	return &AzureDevOpsSource{
+		au:     authr,
+		client: azuredevops.NewClient(baseURL, cli).WithAuthenticator(authr),
+		org:    org,
+	}, nil
+}
+
+func (s AzureDevOpsSource) GitserverPushConfig(ctx context.Context, store *database.ExternalServiceStore, repo *types.Repo) (*protocol.PushConfig, error) {
+	return gitserverPushConfig(ctx, store, repo, s.au)
+}
+
+func (s AzureDevOpsSource) WithAuthenticator(a auth.Authenticator) (ChangesetSource, error) {
+	switch a.(type) {
+	case *auth.OAuthBearerToken,
+		*auth.OAuthBearerTokenWithSSH,
+		*auth.BasicAuth,
+		*auth.BasicAuthWithSSH:
+		break
+
+	default:
+		return nil, newUnsupportedAuthenticatorError("AzureDevOpsSource", a)
+	}
+
+	sc := s
+	sc.au = a
+	sc.client = sc.client.WithAuthenticator(a)
+
+	return &sc, nil
+}
+
+func (s AzureDevOpsSource) ValidateAuthenticator(ctx context.Context) error {
+	if s.org == "" {
+		return errors.New("no organization configured to validate the token against")
+	}
+	_, err := s.client.AuthenticatedUser(ctx, s.org)
+	return err
+}
+
+// CreateChangeset creates an Azure DevOps pull request. If it already exists,
+// *Changeset will be populated and the return value will be true.
+func (s *AzureDevOpsSource) CreateChangeset(ctx context.Context, c *Changeset) (bool, error) {
+	return s.createChangeset(ctx, c, false)
+}
+
+// CreateDraftChangeset creates an Azure DevOps draft pull request. If it
+// already exists, *Changeset will be populated and the return value will be
+// true.
+func (s *AzureDevOpsSource) CreateDraftChangeset(ctx context.Context, c *Changeset) (bool, error) {
+	return s.createChangeset(ctx, c, true)
+}
+
+func (s *AzureDevOpsSource) createChangeset(ctx context.Context, c *Changeset, draft bool) (bool, error) {
+	repo := c.Repo.Metadata.(*azuredevops.Repository)
+	exists := false
+
+	pr, err := s.client.CreatePullRequest(ctx, repo, &azuredevops.CreatePullRequestInput{
+		SourceRefName: git.EnsureRefPrefix(c.HeadRef),
+		TargetRefName: git.EnsureRefPrefix(c.BaseRef),
+		Title:         c.Title,
+		Description:   c.Body,
+		IsDraft:       draft,
+	})
+	if err != nil {
+		var e azuredevops.ErrPullRequestAlreadyExists
+		if !errors.As(err, &e) {
+			return exists, errors.Wrap(err, "creating the pull request")
+		}
+		exists = true
+		pr = e.Existing
+	}
+
+	if err := s.loadStatuses(ctx, repo, pr); err != nil {
+		return exists, err
+	}
+
+	if err := c.SetMetadata(pr); err != nil {
+		return exists, errors.Wrap(err, "setting changeset metadata")
+	}
+	return exists, nil
+}
+
+// UndraftChangeset marks the pull request as ready for review.
+func (s *AzureDevOpsSource) UndraftChangeset(ctx context.Context, c *Changeset) error {
+	isDraft := false
+	return s.updatePullRequest(ctx, c, &azuredevops.UpdatePullRequestInput{IsDraft: &isDraft})
+}
+
+// CloseChangeset abandons the pull request on Azure DevOps.
+func (s *AzureDevOpsSource) CloseChangeset(ctx context.Context, c *Changeset) error {
+	return s.updatePullRequest(ctx, c, &azuredevops.UpdatePullRequestInput{
+		Status: azuredevops.PullRequestStatusAbandoned,
+	})
+}
+
+// ReopenChangeset reactivates the abandoned pull request on Azure DevOps.
+func (s *AzureDevOpsSource) ReopenChangeset(ctx context.Context, c *Changeset) error {
+	return s.updatePullRequest(ctx, c, &azuredevops.UpdatePullRequestInput{
+		Status: azuredevops.PullRequestStatusActive,
+	})
+}
+
+// UpdateChangeset updates the pull request on Azure DevOps to reflect the
+// local state of the Changeset.
+func (s *AzureDevOpsSource) UpdateChangeset(ctx context.Context, c *Changeset) error {
+	return s.updatePullRequest(ctx, c, &azuredevops.UpdatePullRequestInput{
+		Title:         c.Title,
+		Description:   &c.Body,
+		TargetRefName: git.EnsureRefPrefix(c.BaseRef),
+	})
+}
+
+func (s *AzureDevOpsSource) updatePullRequest(ctx context.Context, c *Changeset, input *azuredevops.UpdatePullRequestInput) error {
+	pr, ok := c.Changeset.Metadata.(*azuredevops.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not an Azure DevOps pull request")
+	}
+	repo := c.Repo.Metadata.(*azuredevops.Repository)
+
+	updated, err := s.client.UpdatePullRequest(ctx, repo, pr.ID, input)
+	if err != nil {
+		return errors.Wrap(err, "updating Azure DevOps pull request")
+	}
+
+	if err := s.loadStatuses(ctx, repo, updated); err != nil {
+		return err
+	}
+
+	return c.Changeset.SetMetadata(updated)
+}
+
+// LoadChangeset loads the given pull request from Azure DevOps and updates
+// it.
+func (s *AzureDevOpsSource) LoadChangeset(ctx context.Context, cs *Changeset) error {
+	repo := cs.Repo.Metadata.(*azuredevops.Repository)
+
+	id, err := strconv.Atoi(cs.ExternalID)
+	if err != nil {
+		return errors.Wrapf(err, "parsing changeset external ID %s", cs.ExternalID)
+	}
+
+	pr, err := s.client.GetPullRequest(ctx, repo, id)
+	if err != nil {
+		if azuredevops.IsNotFound(err) {
+			return ChangesetNotFoundError{Changeset: cs}
+		}
+		return errors.Wrapf(err, "retrieving pull request %d", id)
+	}
+
+	if err := s.loadStatuses(ctx, repo, pr); err != nil {
+		return err
+	}
+
+	if err := cs.SetMetadata(pr); err != nil {
+		return errors.Wrapf(err, "setting changeset metadata for pull request %d", id)
+	}
+	return nil
+}
+
+// loadStatuses loads the statuses of the pull request, which the pull request
+// API doesn't return, to compute its check state.
+func (s *AzureDevOpsSource) loadStatuses(ctx context.Context, repo *azuredevops.Repository, pr *azuredevops.PullRequest) error {
+	statuses, err := s.client.ListPullRequestStatuses(ctx, repo, pr.ID)
+	if err != nil {
+		return errors.Wrapf(err, "retrieving statuses of pull request %d", pr.ID)
+	}
+	pr.Statuses = statuses
+	return nil
+}
+
+// CreateComment posts a comment on the Changeset.
+func (s *AzureDevOpsSource) CreateComment(ctx context.Context, c *Changeset, text string) error {
+	pr, ok := c.Changeset.Metadata.(*azuredevops.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not an Azure DevOps pull request")
+	}
+	repo := c.Repo.Metadata.(*azuredevops.Repository)
+
+	return s.client.CreatePullRequestComment(ctx, repo, pr.ID, text)
+}
+
+// MergeChangeset completes the pull request on Azure DevOps, if in a
+// mergeable state. If squash is true, the changes are squashed into a single
+// commit.
+func (s *AzureDevOpsSource) MergeChangeset(ctx context.Context, c *Changeset, squash bool) error {
+	pr, ok := c.Changeset.Metadata.(*azuredevops.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not an Azure DevOps pull request")
+	}
+	repo := c.Repo.Metadata.(*azuredevops.Repository)
+
+	updated, err := s.client.CompletePullRequest(ctx, repo, pr, squash)
+	if err != nil {
+		if errors.Is(err, azuredevops.ErrNotMergeable) {
+			return ChangesetNotMergeableError{ErrorMsg: err.Error()}
+		}
+		return errors.Wrap(err, "completing Azure DevOps pull request")
+	}
+
+	if err := s.loadStatuses(ctx, repo, updated); err != nil {
+		return err
+	}
+
+	return c.Changeset.SetMetadata(updated)
+}
@@ -265,6 +265,10 @@ func loadExternalService(ctx context.Context, s *database.ExternalServiceStore,
 			if cfg.Token != "" {
 				return e, nil
 			}
+		case *schema.AzureDevOpsConnection:
+			if cfg.Token != "" {
+				return e, nil
+			}
 		}
 	}
 
@@ -282,6 +286,8 @@ func buildChangesetSource(store SourcerStore, cf *httpcli.Factory, externalServi
 		return NewGitLabSource(externalService, cf)
 	case extsvc.KindBitbucketServer:
 		return NewBitbucketServerSource(externalService, cf)
+	case extsvc.KindAzureDevOps:
+		return NewAzureDevOpsSource(externalService, cf)
 	default:
 		return nil, errors.Errorf("unsupported external service type %q", extsvc.KindToType(externalService.Kind))
 	}
@@ -334,6 +340,11 @@ func setOAuthTokenAuth(u *vcs.URL, extSvcType, token string) error {
 	case extsvc.TypeBitbucketServer:
 		return errors.New("require username/token to push commits to BitbucketServer")
 
+	case extsvc.TypeAzureDevOps:
+		// Azure DevOps ignores the username when authenticating with a
+		// personal access token.
+		u.User = url.UserPassword("sourcegraph", token)
+
 	default:
 		panic(fmt.Sprintf("setOAuthTokenAuth: invalid external service type %q", extSvcType))
 	}
@@ -347,7 +358,7 @@ func setBasicAuth(u *vcs.URL, extSvcType, username, password string) error {
 	case extsvc.TypeGitHub, extsvc.TypeGitLab:
 		return errors.New("need token to push commits to " + extSvcType)
 
-	case extsvc.TypeBitbucketServer:
+	case extsvc.TypeBitbucketServer, extsvc.TypeAzureDevOps:
 		u.User = url.UserPassword(username, password)
 
 	default:
@@ -54,9 +54,11 @@ var RequiredEventTypesForHistory = []btypes.ChangesetEventKind{
 	btypes.ChangesetEventKindGitHubClosed,
 	btypes.ChangesetEventKindBitbucketServerDeclined,
 	btypes.ChangesetEventKindGitLabClosed,
+	btypes.ChangesetEventKindAzureDevOpsAbandoned,
 	btypes.ChangesetEventKindGitHubMerged,
 	btypes.ChangesetEventKindBitbucketServerMerged,
 	btypes.ChangesetEventKindGitLabMerged,
+	btypes.ChangesetEventKindAzureDevOpsMerged,
 	btypes.ChangesetEventKindGitHubReopened,
 	btypes.ChangesetEventKindBitbucketServerReopened,
 	btypes.ChangesetEventKindGitLabReopened,
@@ -121,7 +123,8 @@ func computeHistory(ch *btypes.Changeset, ce ChangesetEvents) (changesetHistory,
 		switch e.Kind {
 		case btypes.ChangesetEventKindGitHubClosed,
 			btypes.ChangesetEventKindBitbucketServerDeclined,
-			btypes.ChangesetEventKindGitLabClosed:
+			btypes.ChangesetEventKindGitLabClosed,
+			btypes.ChangesetEventKindAzureDevOpsAbandoned:
 			// Merged is a final state. We can ignore everything after.
 			if currentExtState != btypes.ChangesetExternalStateMerged {
 				currentExtState = btypes.ChangesetExternalStateClosed
@@ -130,7 +133,8 @@ func computeHistory(ch *btypes.Changeset, ce ChangesetEvents) (changesetHistory,
 
 		case btypes.ChangesetEventKindGitHubMerged,
 			btypes.ChangesetEventKindBitbucketServerMerged,
-			btypes.ChangesetEventKindGitLabMerged:
+			btypes.ChangesetEventKindGitLabMerged,
+			btypes.ChangesetEventKindAzureDevOpsMerged:
 			currentExtState = btypes.ChangesetExternalStateMerged
 			pushStates(et)
 
@@ -16,6 +16,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
@@ -105,6 +106,9 @@ func computeCheckState(c *btypes.Changeset, events ChangesetEvents) btypes.Chang
 
 	case *gitlab.MergeRequest:
 		return computeGitLabCheckState(c.UpdatedAt, m, events)
+
+	case *azuredevops.PullRequest:
+		return computeAzureDevOpsCheckState(m)
 	}
 
 	return btypes.ChangesetCheckStateUnknown
@@ -415,6 +419,41 @@ func parseGitLabPipelineStatus(status gitlab.PipelineStatus) btypes.ChangesetChe
 	}
 }
 
+// computeAzureDevOpsCheckState computes the check state from the statuses
+// posted to the pull request. Statuses posted again for the same context, such
+// as a build validation policy running again after a push, supersede earlier
+// ones.
+func computeAzureDevOpsCheckState(pr *azuredevops.PullRequest) btypes.ChangesetCheckState {
+	latest := make(map[string]*azuredevops.GitStatus)
+	for _, s := range pr.Statuses {
+		if l, ok := latest[s.Key()]; !ok || l.IterationID < s.IterationID || (l.IterationID == s.IterationID && l.CreationDate.Before(s.CreationDate)) {
+			latest[s.Key()] = s
+		}
+	}
+
+	states := make([]btypes.ChangesetCheckState, 0, len(latest))
+	for _, s := range latest {
+		if s.State == azuredevops.GitStatusStateNotApplicable {
+			continue
+		}
+		states = append(states, parseAzureDevOpsStatusState(s.State))
+	}
+	return combineCheckStates(states)
+}
+
+func parseAzureDevOpsStatusState(s azuredevops.GitStatusState) btypes.ChangesetCheckState {
+	switch s {
+	case azuredevops.GitStatusStateSucceeded:
+		return btypes.ChangesetCheckStatePassed
+	case azuredevops.GitStatusStateFailed, azuredevops.GitStatusStateError:
+		return btypes.ChangesetCheckStateFailed
+	case azuredevops.GitStatusStatePending:
+		return btypes.ChangesetCheckStatePending
+	default:
+		return btypes.ChangesetCheckStateUnknown
+	}
+}
+
 // computeSingleChangesetExternalState of a Changeset based on the metadata.
 // It does NOT reflect the final calculated state, use `ExternalState` instead.
 func computeSingleChangesetExternalState(c *btypes.Changeset) (s btypes.ChangesetExternalState, err error) {
@@ -450,6 +489,21 @@ func computeSingleChangesetExternalState(c *btypes.Changeset) (s btypes.Changese
 		default:
 			return "", errors.Errorf("unknown GitLab merge request state: %s", m.State)
 		}
+	case *azuredevops.PullRequest:
+		switch m.Status {
+		case azuredevops.PullRequestStatusAbandoned:
+			s = btypes.ChangesetExternalStateClosed
+		case azuredevops.PullRequestStatusCompleted:
+			s = btypes.ChangesetExternalStateMerged
+		case azuredevops.PullRequestStatusActive:
+			if m.IsDraft {
+				s = btypes.ChangesetExternalStateDraft
+			} else {
+				s = btypes.ChangesetExternalStateOpen
+			}
+		default:
+			return "", errors.Errorf("unknown Azure DevOps pull request status: %s", m.Status)
+		}
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -507,6 +561,18 @@ func computeSingleChangesetReviewState(c *btypes.Changeset) (s btypes.ChangesetR
 		}
 		return btypes.ChangesetReviewStatePending, nil
 
+	case *azuredevops.PullRequest:
+		for _, r := range m.Reviewers {
+			switch {
+			case r.Vote >= azuredevops.VoteApprovedWithSuggestions:
+				states[btypes.ChangesetReviewStateApproved] = true
+			case r.Vote <= azuredevops.VoteWaitingForAuthor:
+				states[btypes.ChangesetReviewStateChangesRequested] = true
+			default:
+				states[btypes.ChangesetReviewStatePending] = true
+			}
+		}
+
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -9,6 +9,7 @@ import (
 
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
@@ -406,6 +407,60 @@ func TestComputeGitLabCheckState(t *testing.T) {
 	})
 }
 
+func TestComputeAzureDevOpsCheckState(t *testing.T) {
+	t.Parallel()
+
+	status := func(name string, iteration int, state azuredevops.GitStatusState) *azuredevops.GitStatus {
+		return &azuredevops.GitStatus{
+			State:        state,
+			Context:      azuredevops.GitStatusContext{Name: name, Genre: "ci"},
+			CreationDate: time.Unix(int64(iteration), 0),
+			IterationID:  iteration,
+		}
+	}
+
+	for name, tc := range map[string]struct {
+		statuses []*azuredevops.GitStatus
+		want     btypes.ChangesetCheckState
+	}{
+		"no statuses": {
+			want: btypes.ChangesetCheckStateUnknown,
+		},
+		"single pending status": {
+			statuses: []*azuredevops.GitStatus{status("build", 1, azuredevops.GitStatusStatePending)},
+			want:     btypes.ChangesetCheckStatePending,
+		},
+		"not applicable statuses are ignored": {
+			statuses: []*azuredevops.GitStatus{
+				status("build", 1, azuredevops.GitStatusStateSucceeded),
+				status("lint", 1, azuredevops.GitStatusStateNotApplicable),
+			},
+			want: btypes.ChangesetCheckStatePassed,
+		},
+		"later iteration supersedes earlier": {
+			statuses: []*azuredevops.GitStatus{
+				status("build", 2, azuredevops.GitStatusStateSucceeded),
+				status("build", 1, azuredevops.GitStatusStateFailed),
+			},
+			want: btypes.ChangesetCheckStatePassed,
+		},
+		"one failing context": {
+			statuses: []*azuredevops.GitStatus{
+				status("build", 1, azuredevops.GitStatusStateSucceeded),
+				status("test", 1, azuredevops.GitStatusStateError),
+			},
+			want: btypes.ChangesetCheckStateFailed,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			have := computeAzureDevOpsCheckState(&azuredevops.PullRequest{Statuses: tc.statuses})
+			if have != tc.want {
+				t.Errorf("unexpected check state: have %s; want %s", have, tc.want)
+			}
+		})
+	}
+}
+
 func TestComputeReviewState(t *testing.T) {
 	t.Parallel()
 
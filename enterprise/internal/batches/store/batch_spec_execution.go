@@ -148,6 +148,37 @@ func getBatchSpecExecutionQuery(opts *GetBatchSpecExecutionOpts) (*sqlf.Query, e
 	), nil
 }
 
+// CancelBatchSpecExecution cancels the BatchSpecExecution with the given rand
+// ID if it is queued, processing or errored. If no such execution exists,
+// ErrNoResults is returned.
+func (s *Store) CancelBatchSpecExecution(ctx context.Context, randID string) (*btypes.BatchSpecExecution, error) {
+	q := sqlf.Sprintf(
+		cancelBatchSpecExecutionQueryFmtstr,
+		randID,
+		sqlf.Join(BatchSpecExecutionColumns, ", "),
+	)
+
+	var b btypes.BatchSpecExecution
+	err := s.query(ctx, q, func(sc scanner) error { return scanBatchSpecExecution(&b, sc) })
+	if err != nil {
+		return nil, err
+	}
+
+	if b.ID == 0 {
+		return nil, ErrNoResults
+	}
+
+	return &b, nil
+}
+
+var cancelBatchSpecExecutionQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_executions.go:CancelBatchSpecExecution
+UPDATE batch_spec_executions
+SET state = 'canceled', finished_at = clock_timestamp()
+WHERE rand_id = %s AND state IN ('queued', 'processing', 'errored')
+RETURNING %s
+`
+
 func scanBatchSpecExecution(b *btypes.BatchSpecExecution, sc scanner) error {
 	var executionLogs []dbworkerstore.ExecutionLogEntry
 
@@ -100,4 +100,28 @@ func testStoreChangesetSpecExecutions(t *testing.T, ctx context.Context, s *Stor
 			}
 		})
 	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		have, err := s.CancelBatchSpecExecution(ctx, execs[0].RandID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if have.State != btypes.BatchSpecExecutionStateCanceled {
+			t.Fatalf("unexpected state. want=%q have=%q", btypes.BatchSpecExecutionStateCanceled, have.State)
+		}
+
+		if have.FinishedAt == nil {
+			t.Fatal("FinishedAt should be set")
+		}
+
+		t.Run("AlreadyCanceled", func(t *testing.T) {
+			_, have := s.CancelBatchSpecExecution(ctx, execs[0].RandID)
+			want := ErrNoResults
+
+			if have != want {
+				t.Fatalf("have err %v, want %v", have, want)
+			}
+		})
+	})
 }
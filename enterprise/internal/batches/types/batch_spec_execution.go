@@ -14,6 +14,7 @@ const (
 	BatchSpecExecutionStateFailed     BatchSpecExecutionState = "failed"
 	BatchSpecExecutionStateCompleted  BatchSpecExecutionState = "completed"
 	BatchSpecExecutionStateProcessing BatchSpecExecutionState = "processing"
+	BatchSpecExecutionStateCanceled   BatchSpecExecutionState = "canceled"
 )
 
 type BatchSpecExecution struct {
@@ -11,6 +11,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
@@ -379,6 +380,16 @@ func (c *Changeset) SetMetadata(meta interface{}) error {
 		c.ExternalServiceType = extsvc.TypeGitLab
 		c.ExternalBranch = git.EnsureRefPrefix(pr.SourceBranch)
 		c.ExternalUpdatedAt = pr.UpdatedAt.Time
+	case *azuredevops.PullRequest:
+		c.Metadata = pr
+		c.ExternalID = strconv.Itoa(pr.ID)
+		c.ExternalServiceType = extsvc.TypeAzureDevOps
+		c.ExternalBranch = git.EnsureRefPrefix(pr.SourceRefName)
+		// Azure DevOps doesn't report when a pull request was last updated.
+		c.ExternalUpdatedAt = pr.CreationDate
+		if !pr.ClosedDate.IsZero() {
+			c.ExternalUpdatedAt = pr.ClosedDate
+		}
 	default:
 		return errors.New("unknown changeset type")
 	}
@@ -404,6 +415,8 @@ func (c *Changeset) Title() (string, error) {
 		return m.Title, nil
 	case *gitlab.MergeRequest:
 		return m.Title, nil
+	case *azuredevops.PullRequest:
+		return m.Title, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -421,6 +434,8 @@ func (c *Changeset) AuthorName() (string, error) {
 		return m.Author.User.Name, nil
 	case *gitlab.MergeRequest:
 		return m.Author.Username, nil
+	case *azuredevops.PullRequest:
+		return m.CreatedBy.UniqueName, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -444,6 +459,11 @@ func (c *Changeset) AuthorEmail() (string, error) {
 		return m.Author.User.EmailAddress, nil
 	case *gitlab.MergeRequest:
 		return m.Author.Email, nil
+	case *azuredevops.PullRequest:
+		// The unique name of Azure Active Directory users is their user
+		// principal name, which is usually but not necessarily their email
+		// address, so we don't return it here.
+		return "", nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -460,6 +480,8 @@ func (c *Changeset) ExternalCreatedAt() time.Time {
 		return unixMilliToTime(int64(m.CreatedDate))
 	case *gitlab.MergeRequest:
 		return m.CreatedAt.Time
+	case *azuredevops.PullRequest:
+		return m.CreationDate
 	default:
 		return time.Time{}
 	}
@@ -474,6 +496,8 @@ func (c *Changeset) Body() (string, error) {
 		return m.Description, nil
 	case *gitlab.MergeRequest:
 		return m.Description, nil
+	case *azuredevops.PullRequest:
+		return m.Description, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -511,6 +535,8 @@ func (c *Changeset) URL() (s string, err error) {
 		return selfLink.Href, nil
 	case *gitlab.MergeRequest:
 		return m.WebURL, nil
+	case *azuredevops.PullRequest:
+		return m.WebURL(), nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -643,6 +669,22 @@ func (c *Changeset) Events() (events []*ChangesetEvent, err error) {
 				Metadata:    pipeline,
 			})
 		}
+
+	case *azuredevops.PullRequest:
+		// Azure DevOps has no pull request timeline, so the only event we
+		// know of is the pull request being completed or abandoned.
+		if e := m.StatusEvent(); e != nil {
+			var kind ChangesetEventKind
+			if kind, err = ChangesetEventKindFor(e); err != nil {
+				return
+			}
+			appendEvent(&ChangesetEvent{
+				ChangesetID: c.ID,
+				Key:         e.Key(),
+				Kind:        kind,
+				Metadata:    e,
+			})
+		}
 	}
 	return events, nil
 }
@@ -658,6 +700,11 @@ func (c *Changeset) HeadRefOid() (string, error) {
 		return "", nil
 	case *gitlab.MergeRequest:
 		return m.DiffRefs.HeadSHA, nil
+	case *azuredevops.PullRequest:
+		if m.LastMergeSourceCommit == nil {
+			return "", nil
+		}
+		return m.LastMergeSourceCommit.CommitID, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -673,6 +720,8 @@ func (c *Changeset) HeadRef() (string, error) {
 		return m.FromRef.ID, nil
 	case *gitlab.MergeRequest:
 		return "refs/heads/" + m.SourceBranch, nil
+	case *azuredevops.PullRequest:
+		return m.SourceRefName, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -689,6 +738,11 @@ func (c *Changeset) BaseRefOid() (string, error) {
 		return "", nil
 	case *gitlab.MergeRequest:
 		return m.DiffRefs.BaseSHA, nil
+	case *azuredevops.PullRequest:
+		if m.LastMergeTargetCommit == nil {
+			return "", nil
+		}
+		return m.LastMergeTargetCommit.CommitID, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -704,6 +758,8 @@ func (c *Changeset) BaseRef() (string, error) {
 		return m.ToRef.ID, nil
 	case *gitlab.MergeRequest:
 		return "refs/heads/" + m.TargetBranch, nil
+	case *azuredevops.PullRequest:
+		return m.TargetRefName, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -981,6 +1037,14 @@ func ChangesetEventKindFor(e interface{}) (ChangesetEventKind, error) {
 		return ChangesetEventKindGitLabReopened, nil
 	case *gitlab.MergeRequestMergedEvent:
 		return ChangesetEventKindGitLabMerged, nil
+
+	case *azuredevops.PullRequestStatusEvent:
+		switch e.Status {
+		case azuredevops.PullRequestStatusCompleted:
+			return ChangesetEventKindAzureDevOpsMerged, nil
+		case azuredevops.PullRequestStatusAbandoned:
+			return ChangesetEventKindAzureDevOpsAbandoned, nil
+		}
 	}
 
 	return ChangesetEventKindInvalid, errors.Errorf("unknown changeset event kind for %T", e)
@@ -1061,6 +1125,11 @@ func NewChangesetEventMetadata(k ChangesetEventKind) (interface{}, error) {
 		case ChangesetEventKindGitLabReopened:
 			return new(gitlab.MergeRequestReopenedEvent), nil
 		}
+	case strings.HasPrefix(string(k), "azuredevops"):
+		switch k {
+		case ChangesetEventKindAzureDevOpsMerged, ChangesetEventKindAzureDevOpsAbandoned:
+			return new(azuredevops.PullRequestStatusEvent), nil
+		}
 	}
 	return nil, errors.Errorf("unknown changeset event kind %q", k)
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/cockroachdb/errors"
 	"github.com/inconshreveable/log15"
 
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
@@ -78,6 +79,9 @@ const (
 	ChangesetEventKindGitLabMarkWorkInProgress   ChangesetEventKind = "gitlab:mark_wip"
 	ChangesetEventKindGitLabUnmarkWorkInProgress ChangesetEventKind = "gitlab:unmark_wip"
 
+	ChangesetEventKindAzureDevOpsMerged    ChangesetEventKind = "azuredevops:merged"
+	ChangesetEventKindAzureDevOpsAbandoned ChangesetEventKind = "azuredevops:abandoned"
+
 	ChangesetEventKindInvalid ChangesetEventKind = "invalid"
 )
 
@@ -240,6 +244,8 @@ func (e *ChangesetEvent) Timestamp() time.Time {
 		// fall back to the event record we created when we received the
 		// webhook.
 		t = e.CreatedAt
+	case *azuredevops.PullRequestStatusEvent:
+		t = ev.ClosedDate
 	}
 
 	return t
@@ -722,6 +728,11 @@ func (e *ChangesetEvent) Update(o *ChangesetEvent) error {
 		// We always get the full event, so safe to replace it
 		*e = *o
 
+	case *azuredevops.PullRequestStatusEvent:
+		o := o.Metadata.(*azuredevops.PullRequestStatusEvent)
+		// The event is derived from the full pull request, so safe to replace it
+		*e = *o
+
 	default:
 		return errors.Errorf("unknown changeset event metadata %T", e)
 	}
@@ -0,0 +1,198 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type AzureDevOpsWebhook struct {
+	*Webhook
+}
+
+func NewAzureDevOpsWebhook(store *store.Store) *AzureDevOpsWebhook {
+	return &AzureDevOpsWebhook{&Webhook{store, extsvc.TypeAzureDevOps}}
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *AzureDevOpsWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	extSvc, err := h.getExternalServiceFromRawID(r.Context(), r.FormValue(extsvc.IDParam))
+	if err == errExternalServiceNotFound {
+		respond(w, http.StatusUnauthorized, err)
+		return
+	} else if err != nil {
+		respond(w, http.StatusInternalServerError, errors.Wrap(err, "getting external service"))
+		return
+	}
+
+	// 🚨 SECURITY: Service hooks send the shared secret as the password of the
+	// basic authentication credentials configured on the subscription. If there
+	// isn't a webhook defined in the service with this secret, or no
+	// credentials were sent, then we return a 401 to the client.
+	_, secret, _ := r.BasicAuth()
+	if ok, err := validateAzureDevOpsSecret(extSvc, secret); err != nil {
+		respond(w, http.StatusInternalServerError, errors.Wrap(err, "validating the shared secret"))
+		return
+	} else if !ok {
+		respond(w, http.StatusUnauthorized, "shared secret is incorrect")
+		return
+	}
+
+	if r.Body == nil {
+		respond(w, http.StatusBadRequest, "missing request body")
+		return
+	}
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		respond(w, http.StatusInternalServerError, errors.Wrap(err, "reading payload"))
+		return
+	}
+
+	event, err := azuredevops.ParseWebhookEvent(payload)
+	if err != nil {
+		var unknown azuredevops.ErrUnknownEventType
+		if errors.As(err, &unknown) {
+			// We don't want to return a non-2XX status code and have Azure
+			// DevOps retry the service hook, so we'll log that we don't know
+			// what to do and return 204.
+			log15.Debug("unknown Azure DevOps event type", "err", err)
+
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.WriteHeader(http.StatusNoContent)
+			fmt.Fprintf(w, "%v", err)
+		} else {
+			respond(w, http.StatusBadRequest, errors.Wrap(err, "parsing payload"))
+		}
+		return
+	}
+
+	if err := h.handleEvent(r.Context(), extSvc, event); err != nil {
+		respond(w, err.code, err)
+	} else {
+		respond(w, http.StatusNoContent, nil)
+	}
+}
+
+// getExternalServiceFromRawID retrieves the Azure DevOps external service
+// matching the given raw ID, which is usually going to be the string in the
+// externalServiceID URL parameter.
+func (h *AzureDevOpsWebhook) getExternalServiceFromRawID(ctx context.Context, raw string) (*types.ExternalService, error) {
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing the raw external service ID")
+	}
+
+	es, err := h.Store.ExternalServices().List(ctx, database.ExternalServicesListOptions{
+		IDs:   []int64{id},
+		Kinds: []string{extsvc.KindAzureDevOps},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing external services")
+	}
+
+	if len(es) == 0 {
+		return nil, errExternalServiceNotFound
+	} else if len(es) > 1 {
+		return nil, errors.New("too many external services found")
+	}
+
+	return es[0], nil
+}
+
+// handleEvent records the completion or abandonment of the pull request, if
+// the event carries one, and enqueues a sync of the changeset.
+//
+// Service hook payloads contain the pull request, but neither its statuses nor
+// up to date reviewer votes, so a full sync is required to pick up everything
+// else that may have changed.
+func (h *AzureDevOpsWebhook) handleEvent(ctx context.Context, extSvc *types.ExternalService, event *azuredevops.PullRequestEvent) *httpError {
+	log15.Debug("Azure DevOps webhook received", "type", event.EventType)
+
+	esID, err := extractExternalServiceID(extSvc)
+	if err != nil {
+		return &httpError{code: http.StatusInternalServerError, err: err}
+	}
+
+	pr := PR{
+		ID:             int64(event.PullRequest.ID),
+		RepoExternalID: event.PullRequest.Repository.ID,
+	}
+
+	if ev := event.PullRequest.StatusEvent(); ev != nil {
+		if err := h.upsertChangesetEvent(ctx, esID, pr, ev); err != nil {
+			return &httpError{
+				code: http.StatusInternalServerError,
+				err:  errors.Wrap(err, "upserting changeset event"),
+			}
+		}
+	}
+
+	if err := h.enqueueChangesetSync(ctx, esID, pr); err != nil {
+		return &httpError{code: http.StatusInternalServerError, err: err}
+	}
+	return nil
+}
+
+func (h *AzureDevOpsWebhook) enqueueChangesetSync(ctx context.Context, esID string, pr PR) error {
+	repo, err := h.getRepoForPR(ctx, h.Store, pr, esID)
+	if err != nil {
+		return errors.Wrap(err, "getting repo")
+	}
+
+	c, err := h.Store.GetChangeset(ctx, store.GetChangesetOpts{
+		RepoID:              repo.ID,
+		ExternalID:          strconv.FormatInt(pr.ID, 10),
+		ExternalServiceType: h.ServiceType,
+	})
+	if err != nil {
+		if err == store.ErrNoResults {
+			// Not a changeset tracked by Sourcegraph.
+			return nil
+		}
+		return errors.Wrap(err, "getting changeset")
+	}
+
+	if err := repoupdater.DefaultClient.EnqueueChangesetSync(ctx, []int64{c.ID}); err != nil {
+		return errors.Wrap(err, "enqueuing changeset sync")
+	}
+	return nil
+}
+
+// validateAzureDevOpsSecret validates that the given secret matches one of
+// the webhooks in the external service.
+func validateAzureDevOpsSecret(extSvc *types.ExternalService, secret string) (bool, error) {
+	// An empty secret never succeeds.
+	if secret == "" {
+		return false, nil
+	}
+
+	c, err := extSvc.Configuration()
+	if err != nil {
+		return false, errors.Wrap(err, "getting external service configuration")
+	}
+
+	config, ok := c.(*schema.AzureDevOpsConnection)
+	if !ok {
+		return false, errExternalServiceWrongKind
+	}
+
+	for _, webhook := range config.Webhooks {
+		if webhook.Secret == secret {
+			return true, nil
+		}
+	}
+	return false, nil
+}
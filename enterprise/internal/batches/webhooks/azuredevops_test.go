@@ -0,0 +1,63 @@
+package webhooks
+
+import (
+	"testing"
+
+	ct "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/testing"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestValidateAzureDevOpsSecret(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty secret", func(t *testing.T) {
+		ok, err := validateAzureDevOpsSecret(nil, "")
+		if ok {
+			t.Errorf("unexpected ok: %v", ok)
+		}
+		if err != nil {
+			t.Errorf("unexpected non-nil error: %+v", err)
+		}
+	})
+
+	t.Run("not an Azure DevOps connection", func(t *testing.T) {
+		es := &types.ExternalService{Kind: extsvc.KindGitHub}
+		ok, err := validateAzureDevOpsSecret(es, "secret")
+		if ok {
+			t.Errorf("unexpected ok: %v", ok)
+		}
+		if err != errExternalServiceWrongKind {
+			t.Errorf("unexpected error: have %+v; want %+v", err, errExternalServiceWrongKind)
+		}
+	})
+
+	t.Run("valid webhooks", func(t *testing.T) {
+		for secret, want := range map[string]bool{
+			"not secret": false,
+			"secret":     true,
+			"super":      true,
+		} {
+			t.Run(secret, func(t *testing.T) {
+				es := &types.ExternalService{
+					Kind: extsvc.KindAzureDevOps,
+					Config: ct.MarshalJSON(t, &schema.AzureDevOpsConnection{
+						Webhooks: []*schema.AzureDevOpsWebhook{
+							{Secret: "super"},
+							{Secret: "secret"},
+						},
+					}),
+				}
+
+				ok, err := validateAzureDevOpsSecret(es, secret)
+				if ok != want {
+					t.Errorf("unexpected ok: have %v; want %v", ok, want)
+				}
+				if err != nil {
+					t.Errorf("unexpected non-nil error: %+v", err)
+				}
+			})
+		}
+	})
+}
@@ -470,6 +470,25 @@ const deleteIndexByIDQuery = `
 DELETE FROM lsif_indexes WHERE id = %s RETURNING repository_id
 `
 
+// CancelIndexByID cancels a queued, processing, or errored index by its identifier. If the index
+// is being processed by an executor, the executor is notified on its next heartbeat.
+func (s *Store) CancelIndexByID(ctx context.Context, id int) (_ bool, err error) {
+	ctx, endObservation := s.operations.cancelIndexByID.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("id", id),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	_, exists, err := basestore.ScanFirstInt(s.Store.Query(ctx, sqlf.Sprintf(cancelIndexByIDQuery, id)))
+	return exists, err
+}
+
+const cancelIndexByIDQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/indexes.go:CancelIndexByID
+UPDATE lsif_indexes SET state = 'canceled', finished_at = clock_timestamp()
+WHERE id = %s AND state IN ('queued', 'processing', 'errored')
+RETURNING id
+`
+
 // DeleteIndexesWithoutRepository deletes indexes associated with repositories that were deleted at least
 // DeletedRepositoryGracePeriod ago. This returns the repository identifier mapped to the number of indexes
 // that were removed for that repository.
@@ -485,6 +485,43 @@ func TestDeleteIndexByIDMissingRow(t *testing.T) {
 	}
 }
 
+func TestCancelIndexByID(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := testStore(db)
+
+	insertIndexes(t, db,
+		Index{ID: 1, State: "queued"},
+		Index{ID: 2, State: "processing"},
+		Index{ID: 3, State: "completed"},
+	)
+
+	for _, id := range []int{1, 2} {
+		if canceled, err := store.CancelIndexByID(context.Background(), id); err != nil {
+			t.Fatalf("unexpected error canceling index: %s", err)
+		} else if !canceled {
+			t.Fatalf("expected index %d to be canceled", id)
+		}
+
+		if index, exists, err := store.GetIndexByID(context.Background(), id); err != nil {
+			t.Fatalf("unexpected error getting index: %s", err)
+		} else if !exists {
+			t.Fatal("expected record to exist")
+		} else if index.State != "canceled" {
+			t.Errorf("unexpected state. want=%q have=%q", "canceled", index.State)
+		}
+	}
+
+	// Completed indexes are not canceled
+	if canceled, err := store.CancelIndexByID(context.Background(), 3); err != nil {
+		t.Fatalf("unexpected error canceling index: %s", err)
+	} else if canceled {
+		t.Fatalf("unexpected cancellation of completed index")
+	}
+}
+
 func TestDeleteIndexesWithoutRepository(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
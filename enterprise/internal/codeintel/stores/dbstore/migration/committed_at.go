@@ -18,6 +18,7 @@ type committedAtMigrator struct {
 	store           *dbstore.Store
 	gitserverClient GitserverClient
 	batchSize       int
+	defaultSize     int
 }
 
 // NewCommittedAtMigrator creates a new Migrator instance that reads records from
@@ -28,9 +29,20 @@ func NewCommittedAtMigrator(store *dbstore.Store, gitserverClient GitserverClien
 		store:           store,
 		gitserverClient: gitserverClient,
 		batchSize:       batchSize,
+		defaultSize:     batchSize,
 	}
 }
 
+// SetBatchSize changes the number of upload records processed on each call to Up/Down. A
+// non-positive value restores the batch size supplied at construction.
+func (m *committedAtMigrator) SetBatchSize(batchSize int) {
+	if batchSize <= 0 {
+		batchSize = m.defaultSize
+	}
+
+	m.batchSize = batchSize
+}
+
 // Progress returns the ratio between the number of upload records that have been
 // completely migrated over the total number of upload records. This simply counts
 // the number of completed upload records with and without a value for committed_at.
@@ -10,6 +10,7 @@ import (
 type operations struct {
 	addUploadPart                          *observation.Operation
 	calculateVisibleUploads                *observation.Operation
+	cancelIndexByID                        *observation.Operation
 	commitGraphMetadata                    *observation.Operation
 	definitionDumps                        *observation.Operation
 	deleteIndexByID                        *observation.Operation
@@ -98,6 +99,7 @@ func newOperations(observationContext *observation.Context) *operations {
 	return &operations{
 		addUploadPart:                          op("AddUploadPart"),
 		calculateVisibleUploads:                op("CalculateVisibleUploads"),
+		cancelIndexByID:                        op("CancelIndexByID"),
 		commitGraphMetadata:                    op("CommitGraphMetadata"),
 		definitionDumps:                        op("DefinitionDumps"),
 		deleteIndexByID:                        op("DeleteIndexByID"),
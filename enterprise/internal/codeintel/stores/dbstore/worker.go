@@ -31,13 +31,23 @@ var uploadWorkerStoreOptions = dbworkerstore.Options{
 	ColumnExpressions: uploadColumnsWithNullRank,
 	Scan:              scanFirstUploadRecord,
 	OrderByExpression: sqlf.Sprintf("u.uploaded_at, u.id"),
-	HeartbeatInterval: UploadHeartbeatInterval,
-	StalledMaxAge:     StalledUploadMaxAge,
-	MaxNumResets:      UploadMaxNumResets,
+	// Freshly enqueued uploads have a higher priority than retried ones.
+	PriorityExpression: sqlf.Sprintf("u.state != 'errored'"),
+	// Process the uploads of different repositories in round-robin so that a repository
+	// with a large number of uploads does not starve the others.
+	FairnessKeyExpression: sqlf.Sprintf("u.repository_id"),
+	HeartbeatInterval:     UploadHeartbeatInterval,
+	StalledMaxAge:         StalledUploadMaxAge,
+	MaxNumResets:          UploadMaxNumResets,
 }
 
-func WorkerutilUploadStore(s basestore.ShareableStore, observationContext *observation.Context) dbworkerstore.Store {
-	return dbworkerstore.NewWithMetrics(s.Handle(), uploadWorkerStoreOptions, observationContext)
+// WorkerutilUploadStore creates a dbworker store that wraps the lsif_uploads table. If
+// maxConcurrencyPerRepository is non-zero, no more than that many uploads of the same
+// repository are processed at once.
+func WorkerutilUploadStore(s basestore.ShareableStore, maxConcurrencyPerRepository int, observationContext *observation.Context) dbworkerstore.Store {
+	options := uploadWorkerStoreOptions
+	options.MaxConcurrencyPerKey = maxConcurrencyPerRepository
+	return dbworkerstore.NewWithMetrics(s.Handle(), options, observationContext)
 }
 
 // IndexHeartbeatInterval is the duration between heartbeat updates to the index job records.
@@ -61,9 +71,14 @@ var indexWorkerStoreOptions = dbworkerstore.Options{
 	ColumnExpressions: indexColumnsWithNullRank,
 	Scan:              scanFirstIndexRecord,
 	OrderByExpression: sqlf.Sprintf("u.queued_at, u.id"),
-	HeartbeatInterval: IndexHeartbeatInterval,
-	StalledMaxAge:     StalledIndexMaxAge,
-	MaxNumResets:      IndexMaxNumResets,
+	// Freshly enqueued indexes have a higher priority than retried ones.
+	PriorityExpression: sqlf.Sprintf("u.state != 'errored'"),
+	// Process the indexes of different repositories in round-robin so that a repository
+	// with a large number of indexes does not starve the others.
+	FairnessKeyExpression: sqlf.Sprintf("u.repository_id"),
+	HeartbeatInterval:     IndexHeartbeatInterval,
+	StalledMaxAge:         StalledIndexMaxAge,
+	MaxNumResets:          IndexMaxNumResets,
 }
 
 func WorkerutilIndexStore(s basestore.ShareableStore, observationContext *observation.Context) dbworkerstore.Store {
@@ -49,6 +49,7 @@ type Migrator struct {
 	temporaryTableFieldSpecs []*sqlf.Query // names of fields inserted into temporary table
 	updateConditions         []*sqlf.Query // expressions used for the update statement
 	updateAssignments        []*sqlf.Query // expressions used to assign to the target table
+	defaultBatchSize         int           // batch size supplied at construction
 }
 
 type migratorOptions struct {
@@ -137,9 +138,20 @@ func newMigrator(store *lsifstore.Store, driver migrationDriver, options migrato
 		temporaryTableFieldSpecs: temporaryTableFieldSpecs,
 		updateConditions:         updateConditions,
 		updateAssignments:        updateAssignments,
+		defaultBatchSize:         options.batchSize,
 	}
 }
 
+// SetBatchSize changes the number of rows that will be scanned on each call to Up/Down. A
+// non-positive value restores the batch size supplied at construction.
+func (m *Migrator) SetBatchSize(batchSize int) {
+	if batchSize <= 0 {
+		batchSize = m.defaultBatchSize
+	}
+
+	m.options.batchSize = batchSize
+}
+
 // Progress returns the ratio between the number of upload records that have been completely
 // migrated over the total number of upload records. A record is migrated if its schema version
 // is no less than the target migration version.
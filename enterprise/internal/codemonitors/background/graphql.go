@@ -3,10 +3,13 @@ package background
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"log"
 	"net/url"
 	"runtime"
+	"sort"
 	"time"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
@@ -33,6 +36,12 @@ const gqlSearchQuery = `query Search(
 			results {
 				__typename
 				... on FileMatch {
+					repository {
+						name
+					}
+					file {
+						path
+					}
 					limitHit
 					lineMatches {
 						preview
@@ -111,6 +120,7 @@ type gqlSearchResponse struct {
 		Search struct {
 			Results struct {
 				ApproximateResultCount string
+				LimitHit               bool
 				Cloning                []*api.Repo
 				Timedout               []*api.Repo
 				Results                []interface{}
@@ -161,6 +171,67 @@ func gqlURL(queryName string) (string, error) {
 	return u.String(), nil
 }
 
+// incompleteReason returns why the given search results may be missing
+// matches, or the empty string if they are complete.
+func incompleteReason(v *gqlSearchResponse) string {
+	r := v.Data.Search.Results
+	switch {
+	case r.LimitHit:
+		return "result limit hit"
+	case len(r.Timedout) > 0:
+		return "repositories timed out"
+	case len(r.Cloning) > 0:
+		return "repositories are cloning"
+	}
+	return ""
+}
+
+// fingerprintResults returns a fingerprint of the set of file locations in the
+// given search results. The fingerprint changes whenever a match appears in or
+// disappears from the results. Lines are identified by their content rather
+// than by their line number, so edits which merely shift a match up or down
+// don't change the fingerprint.
+func fingerprintResults(v *gqlSearchResponse) (fingerprint string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			const size = 64 << 10
+			buf := make([]byte, size)
+			buf = buf[:runtime.Stack(buf, false)]
+			log.Printf("failed to fingerprint search results: %v\n%s", r, buf)
+			err = errors.Errorf("failed to fingerprint search results")
+		}
+	}()
+
+	var locations []string
+	for _, result := range v.Data.Search.Results.Results {
+		m := result.(map[string]interface{})
+		typeName := m["__typename"].(string)
+		if typeName != "FileMatch" {
+			return "", errors.Errorf("unexpected result __typename %q", typeName)
+		}
+		repo := m["repository"].(map[string]interface{})["name"].(string)
+		path := m["file"].(map[string]interface{})["path"].(string)
+		lineMatches, _ := m["lineMatches"].([]interface{})
+		if len(lineMatches) == 0 {
+			// Path matches don't have line matches.
+			locations = append(locations, repo+"\x00"+path)
+			continue
+		}
+		for _, lm := range lineMatches {
+			preview := lm.(map[string]interface{})["preview"].(string)
+			locations = append(locations, repo+"\x00"+path+"\x00"+preview)
+		}
+	}
+	sort.Strings(locations)
+
+	h := sha256.New()
+	for _, l := range locations {
+		h.Write([]byte(l))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
 // extractTime extracts the time from the given search result.
 func extractTime(result interface{}) (t *time.Time, err error) {
 	// Use recover because we assume the data structure here a lot, for less
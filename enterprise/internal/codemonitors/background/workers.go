@@ -13,6 +13,7 @@ import (
 	cm "github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors/email"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
 	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
@@ -152,6 +153,9 @@ func (r *queryRunner) Handle(ctx context.Context, record workerutil.Record) (err
 	if err != nil {
 		return err
 	}
+	if q.Kind == cm.MonitorQueryKindContent {
+		return handleContentQuery(ctx, s, q, record.RecordID())
+	}
 	newQuery := newQueryWithAfterFilter(q)
 
 	// Search.
@@ -184,6 +188,95 @@ func (r *queryRunner) Handle(ctx context.Context, record workerutil.Record) (err
 	return nil
 }
 
+// handleContentQuery runs a CONTENT trigger query and enqueues actions if the
+// set of matching file locations changed since the last run.
+func handleContentQuery(ctx context.Context, s *cm.Store, q *cm.MonitorQuery, recordID int) error {
+	queryString, err := withCountAll(q.QueryString)
+	if err != nil {
+		return err
+	}
+	results, err := search(ctx, queryString)
+	if err != nil {
+		return err
+	}
+	numResults := len(results.Data.Search.Results.Results)
+
+	now := s.Clock()()
+	// Matches missing from incomplete results would look like they
+	// disappeared, so we neither trigger nor update the fingerprint and try
+	// again on the next run.
+	if reason := incompleteReason(results); reason != "" {
+		log15.Warn("code monitor content query returned incomplete results", "queryID", q.Id, "reason", reason)
+		err = s.PostponeTriggerQuery(ctx, q.Id, now.Add(5*time.Minute))
+		if err != nil {
+			return err
+		}
+		err = s.LogContentSearch(ctx, queryString, numResults, false, recordID)
+		if err != nil {
+			return errors.Errorf("LogContentSearch: %w", err)
+		}
+		return nil
+	}
+
+	fingerprint, err := fingerprintResults(results)
+	if err != nil {
+		return err
+	}
+	fires := contentQueryFires(q, fingerprint, numResults)
+	if fires {
+		err = s.EnqueueActionEmailsForQueryIDInt64(ctx, q.Id, recordID)
+		if err != nil {
+			return errors.Errorf("store.EnqueueActionEmailsForQueryIDInt64: %w", err)
+		}
+	}
+	// For CONTENT queries, latest_result records the last time the results
+	// changed.
+	newLatestResult := now
+	if !fires && q.LatestResult != nil {
+		newLatestResult = *q.LatestResult
+	}
+	err = s.SetTriggerQueryFingerprint(ctx, q.Id, now.Add(5*time.Minute), newLatestResult.UTC(), fingerprint)
+	if err != nil {
+		return err
+	}
+	err = s.LogContentSearch(ctx, queryString, numResults, fires, recordID)
+	if err != nil {
+		return errors.Errorf("LogContentSearch: %w", err)
+	}
+	return nil
+}
+
+// withCountAll adds count:all to a CONTENT query unless it already has a
+// count: filter, so that the fingerprint covers all matches rather than the
+// default number of results.
+func withCountAll(q string) (string, error) {
+	plan, err := query.Pipeline(query.InitLiteral(q))
+	if err != nil {
+		return "", err
+	}
+	for _, basic := range plan {
+		if basic.GetCount() != "" {
+			return q, nil
+		}
+	}
+	return q + " count:all", nil
+}
+
+// contentQueryFires returns true if a CONTENT query whose latest results have
+// the given fingerprint should trigger its actions.
+func contentQueryFires(q *cm.MonitorQuery, fingerprint string, numResults int) bool {
+	// Just like for COMMIT queries, a missing latest result means the
+	// timestamps were reset and we trigger for any results.
+	if q.LatestResult == nil {
+		return numResults > 0
+	}
+	// The first run only establishes the baseline.
+	if q.LatestFingerprint == nil {
+		return false
+	}
+	return *q.LatestFingerprint != fingerprint
+}
+
 type actionRunner struct {
 	*cm.Store
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors/email"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors/resolvers"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors/storetest"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
 )
 
@@ -118,3 +119,162 @@ func TestActionRunner(t *testing.T) {
 		})
 	}
 }
+
+func TestFingerprintResults(t *testing.T) {
+	fileMatch := func(repo, path string, previews ...string) interface{} {
+		lineMatches := make([]interface{}, 0, len(previews))
+		for _, p := range previews {
+			lineMatches = append(lineMatches, map[string]interface{}{"preview": p})
+		}
+		return map[string]interface{}{
+			"__typename":  "FileMatch",
+			"repository":  map[string]interface{}{"name": repo},
+			"file":        map[string]interface{}{"path": path},
+			"lineMatches": lineMatches,
+		}
+	}
+	response := func(results ...interface{}) *gqlSearchResponse {
+		v := &gqlSearchResponse{}
+		v.Data.Search.Results.Results = results
+		return v
+	}
+	fingerprint := func(v *gqlSearchResponse) string {
+		t.Helper()
+		fp, err := fingerprintResults(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fp
+	}
+
+	base := fingerprint(response(
+		fileMatch("a", "main.go", "unsafe.Pointer(x)"),
+		fileMatch("b", "util.go", "unsafe.Pointer(y)", "unsafe.Pointer(z)"),
+	))
+
+	reordered := fingerprint(response(
+		fileMatch("b", "util.go", "unsafe.Pointer(z)", "unsafe.Pointer(y)"),
+		fileMatch("a", "main.go", "unsafe.Pointer(x)"),
+	))
+	if base != reordered {
+		t.Fatal("expected fingerprint to be independent of result order")
+	}
+
+	appeared := fingerprint(response(
+		fileMatch("a", "main.go", "unsafe.Pointer(x)"),
+		fileMatch("b", "util.go", "unsafe.Pointer(y)", "unsafe.Pointer(z)"),
+		fileMatch("c", "new.go", "unsafe.Pointer(w)"),
+	))
+	if base == appeared {
+		t.Fatal("expected fingerprint to change when a match appears")
+	}
+
+	disappeared := fingerprint(response(
+		fileMatch("b", "util.go", "unsafe.Pointer(y)", "unsafe.Pointer(z)"),
+	))
+	if base == disappeared {
+		t.Fatal("expected fingerprint to change when a match disappears")
+	}
+
+	if _, err := fingerprintResults(response(map[string]interface{}{"__typename": "CommitSearchResult"})); err == nil {
+		t.Fatal("expected error for commit results")
+	}
+}
+
+func TestContentQueryFires(t *testing.T) {
+	now := time.Now()
+	fingerprint := "a"
+	otherFingerprint := "b"
+
+	tests := []struct {
+		name       string
+		query      *codemonitors.MonitorQuery
+		numResults int
+		want       bool
+	}{
+		{
+			name:       "reset timestamps with results",
+			query:      &codemonitors.MonitorQuery{},
+			numResults: 1,
+			want:       true,
+		},
+		{
+			name:  "reset timestamps without results",
+			query: &codemonitors.MonitorQuery{},
+			want:  false,
+		},
+		{
+			name:       "first run",
+			query:      &codemonitors.MonitorQuery{LatestResult: &now},
+			numResults: 1,
+			want:       false,
+		},
+		{
+			name:       "unchanged",
+			query:      &codemonitors.MonitorQuery{LatestResult: &now, LatestFingerprint: &fingerprint},
+			numResults: 1,
+			want:       false,
+		},
+		{
+			name:       "changed",
+			query:      &codemonitors.MonitorQuery{LatestResult: &now, LatestFingerprint: &otherFingerprint},
+			numResults: 1,
+			want:       true,
+		},
+		{
+			name:  "all matches disappeared",
+			query: &codemonitors.MonitorQuery{LatestResult: &now, LatestFingerprint: &otherFingerprint},
+			want:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := contentQueryFires(tt.query, fingerprint, tt.numResults); got != tt.want {
+				t.Fatalf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithCountAll(t *testing.T) {
+	for query, want := range map[string]string{
+		"unsafe.Pointer":               "unsafe.Pointer count:all",
+		"unsafe.Pointer count:50":      "unsafe.Pointer count:50",
+		"unsafe.Pointer COUNT:all":     "unsafe.Pointer COUNT:all",
+		`"count:50" lang:go`:           `"count:50" lang:go count:all`,
+		"(a count:10) or (b count:10)": "(a count:10) or (b count:10)",
+	} {
+		got, err := withCountAll(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("withCountAll(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
+
+func TestIncompleteReason(t *testing.T) {
+	response := func(f func(v *gqlSearchResponse)) *gqlSearchResponse {
+		v := &gqlSearchResponse{}
+		f(v)
+		return v
+	}
+	tests := []struct {
+		name     string
+		response *gqlSearchResponse
+		want     string
+	}{
+		{"complete", response(func(v *gqlSearchResponse) {}), ""},
+		{"limit hit", response(func(v *gqlSearchResponse) { v.Data.Search.Results.LimitHit = true }), "result limit hit"},
+		{"timed out", response(func(v *gqlSearchResponse) { v.Data.Search.Results.Timedout = []*api.Repo{{Name: "a"}} }), "repositories timed out"},
+		{"cloning", response(func(v *gqlSearchResponse) { v.Data.Search.Results.Cloning = []*api.Repo{{Name: "a"}} }), "repositories are cloning"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := incompleteReason(tt.response); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
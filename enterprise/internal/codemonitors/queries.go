@@ -13,6 +13,17 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 )
 
+const (
+	// MonitorQueryKindCommit is the kind of trigger queries which search for
+	// commits or diffs created after the last run.
+	MonitorQueryKindCommit = "COMMIT"
+
+	// MonitorQueryKindContent is the kind of trigger queries which run a content
+	// or structural search and fire whenever the set of matching file locations
+	// changes.
+	MonitorQueryKindContent = "CONTENT"
+)
+
 type MonitorQuery struct {
 	Id           int64
 	Monitor      int64
@@ -23,6 +34,12 @@ type MonitorQuery struct {
 	CreatedAt    time.Time
 	ChangedBy    int32
 	ChangedAt    time.Time
+	Kind         string
+
+	// LatestFingerprint identifies the set of file locations returned by the
+	// last run of a CONTENT query. It is nil for COMMIT queries and for CONTENT
+	// queries which haven't run yet.
+	LatestFingerprint *string
 }
 
 var queryColumns = []*sqlf.Query{
@@ -34,6 +51,8 @@ var queryColumns = []*sqlf.Query{
 	sqlf.Sprintf("cm_queries.created_at"),
 	sqlf.Sprintf("cm_queries.changed_by"),
 	sqlf.Sprintf("cm_queries.changed_at"),
+	sqlf.Sprintf("cm_queries.kind"),
+	sqlf.Sprintf("cm_queries.latest_fingerprint"),
 }
 
 func (s *Store) CreateTriggerQuery(ctx context.Context, monitorID int64, args *graphqlbackend.CreateTriggerArgs) (err error) {
@@ -55,7 +74,7 @@ func (s *Store) UpdateTriggerQuery(ctx context.Context, args *graphqlbackend.Upd
 }
 
 const triggerQueryByMonitorFmtStr = `
-SELECT id, monitor, query, next_run, latest_result, created_by, created_at, changed_by, changed_at
+SELECT id, monitor, query, next_run, latest_result, created_by, created_at, changed_by, changed_at, kind, latest_fingerprint
 FROM cm_queries
 WHERE monitor = %s;
 `
@@ -65,7 +84,7 @@ func (s *Store) TriggerQueryByMonitorIDInt64(ctx context.Context, monitorID int6
 }
 
 const triggerQueryByIDFmtStr = `
-SELECT id, monitor, query, next_run, latest_result, created_by, created_at, changed_by, changed_at
+SELECT id, monitor, query, next_run, latest_result, created_by, created_at, changed_by, changed_at, kind, latest_fingerprint
 FROM cm_queries
 WHERE id = %s;
 `
@@ -77,6 +96,7 @@ func (s *Store) triggerQueryByIDInt64(ctx context.Context, queryID int64) (*Moni
 const resetTriggerQueryTimestamps = `
 UPDATE cm_queries
 SET latest_result = null,
+    latest_fingerprint = null,
     next_run = %s
 WHERE id = %s;
 `
@@ -87,8 +107,8 @@ func (s *Store) ResetTriggerQueryTimestamps(ctx context.Context, queryID int64)
 
 const createTriggerQueryFmtStr = `
 INSERT INTO cm_queries
-(monitor, query, created_by, created_at, changed_by, changed_at, next_run, latest_result)
-VALUES (%s,%s,%s,%s,%s,%s,%s,%s)
+(monitor, query, kind, created_by, created_at, changed_by, changed_at, next_run, latest_result)
+VALUES (%s,%s,%s,%s,%s,%s,%s,%s,%s)
 RETURNING %s;
 `
 
@@ -99,6 +119,7 @@ func (s *Store) createTriggerQueryQuery(ctx context.Context, monitorID int64, ar
 		createTriggerQueryFmtStr,
 		monitorID,
 		args.Query,
+		triggerQueryKind(args),
 		a.UID,
 		now,
 		a.UID,
@@ -112,9 +133,11 @@ func (s *Store) createTriggerQueryQuery(ctx context.Context, monitorID int64, ar
 const updateTriggerQueryFmtStr = `
 UPDATE cm_queries
 SET query = %s,
+	kind = %s,
 	changed_by = %s,
 	changed_at = %s,
-	latest_result = %s
+	latest_result = %s,
+	latest_fingerprint = null
 WHERE id = %s
 AND monitor = %s
 RETURNING %s;
@@ -139,6 +162,7 @@ func (s *Store) updateTriggerQueryQuery(ctx context.Context, args *graphqlbacken
 	return sqlf.Sprintf(
 		updateTriggerQueryFmtStr,
 		args.Trigger.Update.Query,
+		triggerQueryKind(args.Trigger.Update),
 		a.UID,
 		now,
 		now,
@@ -149,7 +173,7 @@ func (s *Store) updateTriggerQueryQuery(ctx context.Context, args *graphqlbacken
 }
 
 const getQueryByRecordIDFmtStr = `
-SELECT q.id, q.monitor, q.query, q.next_run, q.latest_result, q.created_by, q.created_at, q.changed_by, q.changed_at
+SELECT q.id, q.monitor, q.query, q.next_run, q.latest_result, q.created_by, q.created_at, q.changed_by, q.changed_at, q.kind, q.latest_fingerprint
 FROM cm_queries q INNER JOIN cm_trigger_jobs j ON q.id = j.query
 WHERE j.id = %s
 `
@@ -192,6 +216,47 @@ func (s *Store) SetTriggerQueryNextRun(ctx context.Context, triggerQueryID int64
 	return s.Exec(ctx, q)
 }
 
+const postponeTriggerQueryFmtStr = `
+UPDATE cm_queries
+SET next_run = %s
+WHERE id = %s
+`
+
+// PostponeTriggerQuery sets the next run of a trigger query without recording
+// its results. It is used when a run returned incomplete results.
+func (s *Store) PostponeTriggerQuery(ctx context.Context, triggerQueryID int64, next time.Time) error {
+	return s.Exec(ctx, sqlf.Sprintf(postponeTriggerQueryFmtStr, next, triggerQueryID))
+}
+
+const setTriggerQueryFingerprintFmtStr = `
+UPDATE cm_queries
+SET next_run = %s,
+latest_result = %s,
+latest_fingerprint = %s
+WHERE id = %s
+`
+
+// SetTriggerQueryFingerprint is like SetTriggerQueryNextRun, but additionally
+// records the fingerprint of the latest results of a CONTENT query.
+func (s *Store) SetTriggerQueryFingerprint(ctx context.Context, triggerQueryID int64, next time.Time, latestResults time.Time, fingerprint string) error {
+	q := sqlf.Sprintf(
+		setTriggerQueryFingerprintFmtStr,
+		next,
+		latestResults,
+		fingerprint,
+		triggerQueryID,
+	)
+	return s.Exec(ctx, q)
+}
+
+// triggerQueryKind returns the kind requested in args, defaulting to COMMIT.
+func triggerQueryKind(args *graphqlbackend.CreateTriggerArgs) string {
+	if args.Kind == "" {
+		return MonitorQueryKindCommit
+	}
+	return args.Kind
+}
+
 func scanTriggerQueries(rows *sql.Rows) (ms []*MonitorQuery, err error) {
 	for rows.Next() {
 		m := &MonitorQuery{}
@@ -205,6 +270,8 @@ func scanTriggerQueries(rows *sql.Rows) (ms []*MonitorQuery, err error) {
 			&m.CreatedAt,
 			&m.ChangedBy,
 			&m.ChangedAt,
+			&m.Kind,
+			&m.LatestFingerprint,
 		); err != nil {
 			return nil, err
 		}
@@ -37,6 +37,7 @@ func TestQueryByRecordID(t *testing.T) {
 		CreatedAt:    now,
 		ChangedBy:    id,
 		ChangedAt:    now,
+		Kind:         MonitorQueryKindCommit,
 	}
 	if diff := cmp.Diff(got, want); diff != "" {
 		t.Fatalf("diff: %s", diff)
@@ -80,6 +81,7 @@ func TestTriggerQueryNextRun(t *testing.T) {
 		CreatedAt:    s.Now(),
 		ChangedBy:    id,
 		ChangedAt:    s.Now(),
+		Kind:         MonitorQueryKindCommit,
 	}
 
 	if diff := cmp.Diff(got, want); diff != "" {
@@ -109,6 +111,7 @@ func TestResetTriggerQueryTimestamps(t *testing.T) {
 		CreatedAt:    now,
 		ChangedBy:    id,
 		ChangedAt:    now,
+		Kind:         MonitorQueryKindCommit,
 	}
 	got, err := s.triggerQueryByIDInt64(ctx, 1)
 	if err != nil {
@@ -137,9 +140,52 @@ func TestResetTriggerQueryTimestamps(t *testing.T) {
 		CreatedAt:    now,
 		ChangedBy:    id,
 		ChangedAt:    now,
+		Kind:         MonitorQueryKindCommit,
 	}
 
 	if diff := cmp.Diff(got, want); diff != "" {
 		t.Fatalf("diff: %s", diff)
 	}
 }
+
+func TestTriggerQueryFingerprint(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx, s := newTestStore(t)
+	_, _, _, userCTX := newTestUser(ctx, t)
+	_, err := s.insertTestMonitor(userCTX, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantLatestResult := s.Now().Add(time.Minute)
+	wantNextRun := s.Now().Add(time.Hour)
+	wantFingerprint := "fingerprint"
+
+	err = s.SetTriggerQueryFingerprint(ctx, 1, wantNextRun, wantLatestResult, wantFingerprint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.triggerQueryByIDInt64(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&wantFingerprint, got.LatestFingerprint); diff != "" {
+		t.Fatalf("diff: %s", diff)
+	}
+
+	// Resetting the timestamps also discards the fingerprint.
+	err = s.ResetTriggerQueryTimestamps(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = s.triggerQueryByIDInt64(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.LatestFingerprint != nil {
+		t.Fatalf("expected fingerprint to be reset, got %q", *got.LatestFingerprint)
+	}
+}
@@ -16,6 +16,7 @@ import (
 	cm "github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors/email"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
 )
 
 // NewResolver returns a new Resolver that uses the given database
@@ -115,6 +116,10 @@ func (r *Resolver) CreateCodeMonitor(ctx context.Context, args *graphqlbackend.C
 	if err != nil {
 		return nil, err
 	}
+	err = validateTrigger(args.Trigger)
+	if err != nil {
+		return nil, err
+	}
 	var mo *cm.Monitor
 	mo, err = r.store.CreateCodeMonitor(ctx, args)
 	if err != nil {
@@ -156,6 +161,10 @@ func (r *Resolver) UpdateCodeMonitor(ctx context.Context, args *graphqlbackend.U
 	if err != nil {
 		return nil, errors.Errorf("UpdateCodeMonitor: %w", err)
 	}
+	err = validateTrigger(args.Trigger.Update)
+	if err != nil {
+		return nil, err
+	}
 
 	var monitorID int64
 	err = relay.UnmarshalSpec(args.Monitor.Id, &monitorID)
@@ -338,6 +347,25 @@ func splitActionIDs(ctx context.Context, args *graphqlbackend.UpdateCodeMonitorA
 	return toCreate, toDelete, nil
 }
 
+// validateTrigger checks that the query of a trigger is compatible with its
+// kind. CONTENT triggers compare sets of file locations, which is meaningless
+// for commit and diff searches.
+func validateTrigger(args *graphqlbackend.CreateTriggerArgs) error {
+	if args.Kind != cm.MonitorQueryKindContent {
+		return nil
+	}
+	q, err := query.ParseLiteral(args.Query)
+	if err != nil {
+		return err
+	}
+	query.VisitField(q, query.FieldType, func(value string, _ bool, _ query.Annotation) {
+		if value == "diff" || value == "commit" {
+			err = errors.Errorf("content triggers do not support type:%s queries, use a commit trigger instead", value)
+		}
+	})
+	return err
+}
+
 func (r *Resolver) updateCodeMonitor(ctx context.Context, args *graphqlbackend.UpdateCodeMonitorArgs) (m graphqlbackend.MonitorResolver, err error) {
 	// Update monitor.
 	var mo *cm.Monitor
@@ -626,6 +654,10 @@ func (q *monitorQuery) Query() string {
 	return q.QueryString
 }
 
+func (q *monitorQuery) Kind() string {
+	return q.MonitorQuery.Kind
+}
+
 func (q *monitorQuery) Events(ctx context.Context, args *graphqlbackend.ListEventsArgs) (graphqlbackend.MonitorTriggerEventConnectionResolver, error) {
 	es, err := q.store.GetEventsForQueryIDInt64(ctx, q.Id, args)
 	if err != nil {
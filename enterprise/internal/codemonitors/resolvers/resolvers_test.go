@@ -1197,3 +1197,37 @@ func TestMonitorKindEqualsResolvers(t *testing.T) {
 		t.Fatal("email.MonitorKind should match resolvers.MonitorKind")
 	}
 }
+
+func TestValidateTrigger(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    *graphqlbackend.CreateTriggerArgs
+		wantErr bool
+	}{
+		{
+			name: "commit trigger",
+			args: &graphqlbackend.CreateTriggerArgs{Query: "repo:foo type:diff bar", Kind: cm.MonitorQueryKindCommit},
+		},
+		{
+			name: "default kind",
+			args: &graphqlbackend.CreateTriggerArgs{Query: "repo:foo type:commit bar"},
+		},
+		{
+			name: "content trigger",
+			args: &graphqlbackend.CreateTriggerArgs{Query: "unsafe.Pointer patternType:literal", Kind: cm.MonitorQueryKindContent},
+		},
+		{
+			name:    "content trigger with type:diff",
+			args:    &graphqlbackend.CreateTriggerArgs{Query: "repo:foo type:diff bar", Kind: cm.MonitorQueryKindContent},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTrigger(tt.args)
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Fatalf("got error %v, want error: %t", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -50,6 +50,13 @@ func (s *Store) LogSearch(ctx context.Context, queryString string, numResults in
 	return s.Store.Exec(ctx, sqlf.Sprintf(logSearchFmtStr, queryString, numResults > 0, numResults, recordID))
 }
 
+// LogContentSearch is like LogSearch for CONTENT queries. Their runs are kept
+// whenever they triggered actions, which includes runs where matches
+// disappeared and no results were returned.
+func (s *Store) LogContentSearch(ctx context.Context, queryString string, numResults int, triggered bool, recordID int) error {
+	return s.Store.Exec(ctx, sqlf.Sprintf(logSearchFmtStr, queryString, triggered, numResults, recordID))
+}
+
 const deleteObsoleteJobLogsFmtStr = `
 DELETE FROM cm_trigger_jobs
 WHERE results IS NOT TRUE
@@ -3,6 +3,7 @@ package database
 import (
 	"database/sql"
 
+	"github.com/sourcegraph/sourcegraph/internal/authz/azuredevops"
 	"github.com/sourcegraph/sourcegraph/internal/authz/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/authz/github"
 	"github.com/sourcegraph/sourcegraph/internal/authz/gitlab"
@@ -30,6 +31,9 @@ func NewExternalServicesStore(db dbutil.DB) *database.ExternalServiceStore {
 	es.PerforceValidators = []func(connection *schema.PerforceConnection) error{
 		perforce.ValidateAuthz,
 	}
+	es.AzureDevOpsValidators = []func(*schema.AzureDevOpsConnection, []schema.AuthProviders) error{
+		azuredevops.ValidateAuthz,
+	}
 
 	return es
 }
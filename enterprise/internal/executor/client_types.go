@@ -76,6 +76,13 @@ type AddExecutionLogEntryRequest struct {
 	workerutil.ExecutionLogEntry
 }
 
+type UpdateExecutionLogEntryRequest struct {
+	ExecutorName string `json:"executorName"`
+	JobID        int    `json:"jobId"`
+	EntryID      int    `json:"entryId"`
+	workerutil.ExecutionLogEntry
+}
+
 type MarkCompleteRequest struct {
 	ExecutorName string `json:"executorName"`
 	JobID        int    `json:"jobId"`
@@ -87,7 +94,23 @@ type MarkErroredRequest struct {
 	ErrorMessage string `json:"errorMessage"`
 }
 
+// ExecutorAPIVersion2 is the version of the heartbeat request for which the executor
+// queue responds with a HeartbeatResponse. Requests without a version get the list of
+// unknown job identifiers, which is what executors before version 2 expect.
+const ExecutorAPIVersion2 = "V2"
+
 type HeartbeatRequest struct {
+	// Version is the version of the response the executor expects.
+	Version string `json:"version,omitempty"`
+
 	ExecutorName string `json:"executorName"`
 	JobIDs       []int  `json:"jobIds"`
 }
+
+type HeartbeatResponse struct {
+	// UnknownIDs are the identifiers of the jobs not held by the executor queue.
+	UnknownIDs []int `json:"unknownIds"`
+
+	// CanceledIDs are the identifiers of the jobs that have been canceled.
+	CanceledIDs []int `json:"canceledIds"`
+}
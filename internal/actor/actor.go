@@ -24,6 +24,12 @@ type Actor struct {
 	// to selectively display a logout link. (If the actor wasn't authenticated with a session
 	// cookie, logout would be ineffective.)
 	FromSessionCookie bool `json:"-"`
+
+	// Scopes are the scopes of the access token that was used to authenticate the actor, if the
+	// token only grants access to a subset of the resources accessible to the user account. It is
+	// nil if the actor may access everything the user account can. Scopes are enforced by the
+	// frontend and are not propagated to other services.
+	Scopes []string `json:"-"`
 }
 
 // FromUser returns an actor corresponding to a user
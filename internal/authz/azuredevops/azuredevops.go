@@ -0,0 +1,84 @@
+package azuredevops
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth/providers"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// NewAuthzProviders returns the set of Azure DevOps authz providers derived from the connections.
+// It also returns any validation problems with the config, separating these into "serious problems" and
+// "warnings". "Serious problems" are those that should make Sourcegraph set authz.allowAccessByDefault
+// to false. "Warnings" are all other validation problems.
+func NewAuthzProviders(
+	cfg *conf.Unified,
+	conns []*types.AzureDevOpsConnection,
+) (ps []authz.Provider, problems []string, warnings []string) {
+	for _, c := range conns {
+		p, err := newAuthzProvider(c.URN, c.AzureDevOpsConnection, cfg.AuthProviders)
+		if err != nil {
+			problems = append(problems, err.Error())
+		} else if p != nil {
+			ps = append(ps, p)
+		}
+	}
+	for _, p := range ps {
+		for _, problem := range p.Validate() {
+			warnings = append(warnings, fmt.Sprintf("Azure DevOps config for %s was invalid: %s", p.ServiceID(), problem))
+		}
+	}
+
+	return ps, problems, warnings
+}
+
+func newAuthzProvider(urn string, c *schema.AzureDevOpsConnection, ps []schema.AuthProviders) (authz.Provider, error) {
+	if c.Authorization == nil {
+		return nil, nil
+	}
+
+	baseURL, err := url.Parse(c.Url)
+	if err != nil {
+		return nil, errors.Errorf("Could not parse URL for Azure DevOps instance %q: %s", c.Url, err)
+	}
+
+	idp := c.Authorization.IdentityProvider
+	for _, authProvider := range ps {
+		saml := authProvider.Saml
+		foundMatchingSAML := saml != nil && saml.ConfigID == idp.AuthProviderID && idp.AuthProviderType == saml.Type
+		oidc := authProvider.Openidconnect
+		foundMatchingOIDC := oidc != nil && oidc.ConfigID == idp.AuthProviderID && idp.AuthProviderType == oidc.Type
+		if foundMatchingSAML || foundMatchingOIDC {
+			return NewProvider(ProviderOp{
+				URN:     urn,
+				BaseURL: baseURL,
+				Token:   c.Token,
+				AuthnConfigID: providers.ConfigID{
+					Type: idp.AuthProviderType,
+					ID:   idp.AuthProviderID,
+				},
+				Orgs:     c.Orgs,
+				Projects: c.Projects,
+			}), nil
+		}
+	}
+	return nil, errors.Errorf("Did not find authentication provider matching type %s and configID %s. Check the [**site configuration**](/site-admin/configuration) to verify that an entry in [`auth.providers`](https://docs.sourcegraph.com/admin/auth) matches the type and configID.", idp.AuthProviderType, idp.AuthProviderID)
+}
+
+// NewProvider is a mockable constructor for new Provider instances.
+var NewProvider = func(op ProviderOp) authz.Provider {
+	return newProvider(op, nil)
+}
+
+// ValidateAuthz validates the authorization fields of the given Azure DevOps
+// external service config.
+func ValidateAuthz(cfg *schema.AzureDevOpsConnection, ps []schema.AuthProviders) error {
+	_, err := newAuthzProvider("", cfg, ps)
+	return err
+}
@@ -0,0 +1,359 @@
+package azuredevops
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth/providers"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// Provider is an implementation of authz.Provider that provides repository
+// permissions as determined by the security namespace of Git repositories on
+// Azure DevOps. Sourcegraph users are mapped to Azure DevOps identities through
+// their account of the configured SAML or OpenID Connect authentication
+// provider, which must be backed by the same Azure Active Directory as Azure
+// DevOps.
+type Provider struct {
+	urn           string
+	client        client
+	codeHost      *extsvc.CodeHost
+	authnConfigID providers.ConfigID
+	orgs          []string
+	projects      []string
+}
+
+var _ authz.Provider = (*Provider)(nil)
+
+type ProviderOp struct {
+	// The unique resource identifier of the external service where the provider is defined.
+	URN string
+
+	// BaseURL is the URL of Azure DevOps.
+	BaseURL *url.URL
+
+	// Token is a personal access token with the "Code (Read)", "Identity (Read)" and
+	// "Security (Manage)" scopes.
+	//
+	// 🚨 SECURITY: This value contains secret information that must not be shown to non-site-admins.
+	Token string
+
+	// AuthnConfigID identifies the authn provider to use to lookup users on Azure DevOps.
+	AuthnConfigID providers.ConfigID
+
+	// Orgs and Projects are the organizations and "org/project" projects whose
+	// repositories are synced by the external service.
+	Orgs     []string
+	Projects []string
+}
+
+// client is the subset of the Azure DevOps client used by the provider.
+type client interface {
+	ListRepositories(ctx context.Context, org, project string) ([]*azuredevops.Repository, error)
+	FindIdentity(ctx context.Context, org, principal string) (*azuredevops.Identity, error)
+	ListAccessControlLists(ctx context.Context, org, namespaceID, token, descriptor string) ([]*azuredevops.AccessControlList, error)
+}
+
+func newProvider(op ProviderOp, cli httpcli.Doer) *Provider {
+	baseURL := extsvc.NormalizeBaseURL(op.BaseURL)
+	return &Provider{
+		urn:           op.URN,
+		client:        azuredevops.NewClient(baseURL, cli).WithAuthenticator(&auth.BasicAuth{Password: op.Token}),
+		codeHost:      extsvc.NewCodeHost(baseURL, extsvc.TypeAzureDevOps),
+		authnConfigID: op.AuthnConfigID,
+		orgs:          op.Orgs,
+		projects:      op.Projects,
+	}
+}
+
+func (p *Provider) Validate() (problems []string) {
+	orgs := p.allOrgs()
+	if len(orgs) == 0 {
+		return []string{"at least one of orgs or projects must be set to enforce permissions"}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := p.client.ListAccessControlLists(ctx, orgs[0], azuredevops.GitRepositoriesNamespaceID, "repoV2", ""); err != nil {
+		if err == ctx.Err() {
+			problems = append(problems, fmt.Sprintf("Azure DevOps API did not respond within 5s (%s)", err.Error()))
+		} else {
+			problems = append(problems, "access token did not have sufficient privileges, requires scopes \"Code (Read)\", \"Identity (Read)\" and \"Security (Manage)\"")
+		}
+	}
+	return problems
+}
+
+func (p *Provider) URN() string {
+	return p.urn
+}
+
+func (p *Provider) ServiceID() string {
+	return p.codeHost.ServiceID
+}
+
+func (p *Provider) ServiceType() string {
+	return p.codeHost.ServiceType
+}
+
+// FetchAccount satisfies the authz.Provider interface. It finds the user's
+// account of the authn provider specified in the Provider struct and looks up
+// the Azure DevOps identity with the principal name of that account.
+func (p *Provider) FetchAccount(ctx context.Context, user *types.User, current []*extsvc.Account, _ []string) (mine *extsvc.Account, err error) {
+	if user == nil {
+		return nil, nil
+	}
+
+	authnProvider := providers.GetProviderByConfigID(p.authnConfigID)
+	if authnProvider == nil {
+		return nil, nil
+	}
+	var authnAcct *extsvc.Account
+	for _, acct := range current {
+		if acct.ServiceID == authnProvider.CachedInfo().ServiceID && acct.ServiceType == authnProvider.ConfigID().Type {
+			authnAcct = acct
+			break
+		}
+	}
+	if authnAcct == nil {
+		return nil, nil
+	}
+
+	principal, err := principalName(authnAcct)
+	if err != nil {
+		return nil, err
+	}
+	if principal == "" {
+		return nil, nil
+	}
+
+	// Identities of the same Azure Active Directory are shared by all
+	// organizations connected to it, so the first match is sufficient.
+	var identity *azuredevops.Identity
+	for _, org := range p.allOrgs() {
+		if identity, err = p.client.FindIdentity(ctx, org, principal); err != nil {
+			return nil, err
+		}
+		if identity != nil {
+			break
+		}
+	}
+	if identity == nil {
+		return nil, nil
+	}
+
+	var accountData extsvc.AccountData
+	azuredevops.SetExternalAccountData(&accountData, identity)
+
+	return &extsvc.Account{
+		UserID: user.ID,
+		AccountSpec: extsvc.AccountSpec{
+			ServiceType: p.codeHost.ServiceType,
+			ServiceID:   p.codeHost.ServiceID,
+			AccountID:   identity.ID,
+		},
+		AccountData: accountData,
+	}, nil
+}
+
+// principalName returns the user principal name of the given SAML or OpenID
+// Connect account. For OpenID Connect, Azure Active Directory puts it in the
+// preferred_username claim. For SAML, it is the NameID.
+func principalName(acct *extsvc.Account) (string, error) {
+	if acct.ServiceType != "openidconnect" {
+		return acct.AccountID, nil
+	}
+
+	var data struct {
+		UserInfo struct {
+			Email string `json:"email"`
+		} `json:"userInfo"`
+		UserClaims struct {
+			PreferredUsername string `json:"preferred_username"`
+		} `json:"userClaims"`
+	}
+	if acct.Data == nil {
+		return "", nil
+	}
+	if err := acct.GetAccountData(&data); err != nil {
+		return "", errors.Wrap(err, "get OpenID Connect account data")
+	}
+	if data.UserClaims.PreferredUsername != "" {
+		return data.UserClaims.PreferredUsername, nil
+	}
+	return data.UserInfo.Email, nil
+}
+
+// FetchUserPerms returns the IDs of the private repositories the given
+// account can read. A repository is readable if the "Read" permission is
+// effectively allowed for the account's identity on the repository, or, if the
+// repository has no entry for the identity and inherits permissions, on its
+// project.
+//
+// This method may return partial but valid results in case of error, and it is up to
+// callers to decide whether to discard.
+func (p *Provider) FetchUserPerms(ctx context.Context, account *extsvc.Account) (*authz.ExternalUserPermissions, error) {
+	if account == nil {
+		return nil, errors.New("no account provided")
+	} else if !extsvc.IsHostOfAccount(p.codeHost, account) {
+		return nil, errors.Errorf("not a code host of the account: want %q but have %q",
+			account.AccountSpec.ServiceID, p.codeHost.ServiceID)
+	}
+
+	identity, err := azuredevops.GetExternalAccountData(&account.AccountData)
+	if err != nil {
+		return nil, errors.Wrap(err, "get external account data")
+	} else if identity == nil || identity.Descriptor == "" {
+		return nil, errors.New("no identity descriptor in external account data")
+	}
+
+	perms := &authz.ExternalUserPermissions{}
+	for _, src := range p.repoSources() {
+		repos, err := p.client.ListRepositories(ctx, src.org, src.project)
+		if err != nil {
+			return perms, errors.Wrapf(err, "list repositories of %q", src)
+		}
+
+		ids, err := p.readableRepos(ctx, src.org, repos, identity.Descriptor)
+		perms.Exacts = append(perms.Exacts, ids...)
+		if err != nil {
+			return perms, err
+		}
+	}
+	return perms, nil
+}
+
+// readableRepos returns the IDs of the given private repositories of org the
+// identity with the given descriptor can read.
+func (p *Provider) readableRepos(ctx context.Context, org string, repos []*azuredevops.Repository, descriptor string) ([]extsvc.RepoID, error) {
+	byProject := make(map[string][]*azuredevops.Repository)
+	for _, r := range repos {
+		if r.Project.Visibility == "public" {
+			continue
+		}
+		byProject[r.Project.ID] = append(byProject[r.Project.ID], r)
+	}
+
+	var ids []extsvc.RepoID
+	for projectID, repos := range byProject {
+		projectToken := "repoV2/" + projectID
+		acls, err := p.client.ListAccessControlLists(ctx, org, azuredevops.GitRepositoriesNamespaceID, projectToken, descriptor)
+		if err != nil {
+			return ids, errors.Wrapf(err, "list access control lists of project %q", projectID)
+		}
+
+		byToken := make(map[string]*azuredevops.AccessControlList, len(acls))
+		for _, acl := range acls {
+			byToken[strings.ToLower(acl.Token)] = acl
+		}
+
+		projectAllowed, _ := canRead(byToken[strings.ToLower(projectToken)], descriptor)
+		for _, r := range repos {
+			allowed, found := canRead(byToken[strings.ToLower(projectToken+"/"+r.ID)], descriptor)
+			if !found {
+				allowed = projectAllowed
+			}
+			if allowed {
+				ids = append(ids, extsvc.RepoID(r.ID))
+			}
+		}
+	}
+	return ids, nil
+}
+
+// canRead reports whether the access control list allows the identity with
+// the given descriptor to read, and whether the list decides that at all. A
+// list which doesn't inherit the permissions of its parent token decides even
+// if it has no entry for the identity.
+func canRead(acl *azuredevops.AccessControlList, descriptor string) (allowed, found bool) {
+	if acl == nil {
+		return false, false
+	}
+
+	for key, ace := range acl.AcesDictionary {
+		if !strings.EqualFold(key, descriptor) && !strings.EqualFold(ace.Descriptor, descriptor) {
+			continue
+		}
+
+		allow, deny := ace.Allow, ace.Deny
+		if ace.ExtendedInfo != nil {
+			allow, deny = ace.ExtendedInfo.EffectiveAllow, ace.ExtendedInfo.EffectiveDeny
+		}
+		if deny&azuredevops.GitPermissionGenericRead != 0 {
+			return false, true
+		}
+		if allow&azuredevops.GitPermissionGenericRead != 0 {
+			return true, true
+		}
+		// An entry that neither allows nor denies reading defers to the
+		// parent token.
+		return false, !acl.InheritPermissions
+	}
+	return false, !acl.InheritPermissions
+}
+
+// FetchUserPermsByToken is not implemented, Azure DevOps permissions are
+// only synced through the identities of the configured authentication
+// provider.
+func (p *Provider) FetchUserPermsByToken(ctx context.Context, token string) (*authz.ExternalUserPermissions, error) {
+	return nil, errors.New("not implemented")
+}
+
+// FetchRepoPerms is not implemented, Azure DevOps doesn't provide an API to
+// list all identities with access to a repository.
+func (p *Provider) FetchRepoPerms(ctx context.Context, repo *extsvc.Repository) ([]extsvc.AccountID, error) {
+	return nil, errors.New("not implemented")
+}
+
+type repoSource struct {
+	org, project string
+}
+
+func (s repoSource) String() string {
+	if s.project == "" {
+		return s.org
+	}
+	return s.org + "/" + s.project
+}
+
+// repoSources returns the organizations and projects whose repositories are
+// synced. Projects of organizations that are synced as a whole are skipped.
+func (p *Provider) repoSources() []repoSource {
+	srcs := make([]repoSource, 0, len(p.orgs)+len(p.projects))
+	orgs := make(map[string]bool, len(p.orgs))
+	for _, org := range p.orgs {
+		orgs[strings.ToLower(org)] = true
+		srcs = append(srcs, repoSource{org: org})
+	}
+	for _, orgProject := range p.projects {
+		parts := strings.SplitN(orgProject, "/", 2)
+		if len(parts) != 2 || orgs[strings.ToLower(parts[0])] {
+			continue
+		}
+		srcs = append(srcs, repoSource{org: parts[0], project: parts[1]})
+	}
+	return srcs
+}
+
+// allOrgs returns the distinct organizations of the synced repositories.
+func (p *Provider) allOrgs() []string {
+	var orgs []string
+	seen := make(map[string]bool)
+	for _, src := range p.repoSources() {
+		if !seen[strings.ToLower(src.org)] {
+			seen[strings.ToLower(src.org)] = true
+			orgs = append(orgs, src.org)
+		}
+	}
+	return orgs
+}
@@ -0,0 +1,163 @@
+package azuredevops
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const descriptor = "Microsoft.IdentityModel.Claims.ClaimsIdentity;tenant\\alice@example.com"
+
+type fakeClient struct {
+	repos map[string][]*azuredevops.Repository
+	acls  map[string][]*azuredevops.AccessControlList
+}
+
+func (c *fakeClient) ListRepositories(_ context.Context, org, project string) ([]*azuredevops.Repository, error) {
+	return c.repos[org+"/"+project], nil
+}
+
+func (c *fakeClient) FindIdentity(_ context.Context, org, principal string) (*azuredevops.Identity, error) {
+	return nil, nil
+}
+
+func (c *fakeClient) ListAccessControlLists(_ context.Context, org, namespaceID, token, descriptor string) ([]*azuredevops.AccessControlList, error) {
+	return c.acls[token], nil
+}
+
+func ace(allow, deny int) map[string]*azuredevops.AccessControlEntry {
+	return map[string]*azuredevops.AccessControlEntry{
+		descriptor: {
+			Descriptor:   descriptor,
+			ExtendedInfo: &azuredevops.AccessControlEntryExtra{EffectiveAllow: allow, EffectiveDeny: deny},
+		},
+	}
+}
+
+func TestProvider_FetchUserPerms(t *testing.T) {
+	repo := func(id, projectID, visibility string) *azuredevops.Repository {
+		return &azuredevops.Repository{ID: id, Project: azuredevops.Project{ID: projectID, Visibility: visibility}}
+	}
+
+	cli := &fakeClient{
+		repos: map[string][]*azuredevops.Repository{
+			"sg/": {
+				repo("inherited", "p1", "private"),
+				repo("denied", "p1", "private"),
+				repo("not-inheriting", "p1", "private"),
+				repo("not-inheriting-no-entry", "p1", "private"),
+				repo("allowed", "p2", "private"),
+				repo("no-entry", "p2", "private"),
+				repo("public", "p3", "public"),
+			},
+			"other/proj": {
+				repo("other", "p4", ""),
+			},
+		},
+		acls: map[string][]*azuredevops.AccessControlList{
+			"repoV2/p1": {
+				{Token: "repoV2/p1", InheritPermissions: true, AcesDictionary: ace(azuredevops.GitPermissionGenericRead, 0)},
+				{Token: "repoV2/p1/denied", InheritPermissions: true, AcesDictionary: ace(0, azuredevops.GitPermissionGenericRead)},
+				{Token: "repoV2/p1/not-inheriting", InheritPermissions: false, AcesDictionary: ace(0, 0)},
+				{Token: "repoV2/p1/not-inheriting-no-entry", InheritPermissions: false},
+			},
+			"repoV2/p2": {
+				{Token: "repoV2/p2/allowed", InheritPermissions: true, AcesDictionary: ace(azuredevops.GitPermissionGenericRead|4, 0)},
+			},
+			"repoV2/p4": {
+				{Token: "repoV2/p4", InheritPermissions: true, AcesDictionary: ace(azuredevops.GitPermissionGenericRead, 0)},
+			},
+		},
+	}
+
+	baseURL, _ := url.Parse("https://dev.azure.com")
+	p := newProvider(ProviderOp{
+		URN:      "extsvc:azuredevops:1",
+		BaseURL:  baseURL,
+		Orgs:     []string{"sg"},
+		Projects: []string{"sg/ignored", "other/proj"},
+	}, nil)
+	p.client = cli
+
+	var data extsvc.AccountData
+	azuredevops.SetExternalAccountData(&data, &azuredevops.Identity{ID: "alice", Descriptor: descriptor})
+	account := &extsvc.Account{
+		AccountSpec: extsvc.AccountSpec{
+			ServiceType: extsvc.TypeAzureDevOps,
+			ServiceID:   "https://dev.azure.com/",
+			AccountID:   "alice",
+		},
+		AccountData: data,
+	}
+
+	perms, err := p.FetchUserPerms(context.Background(), account)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &authz.ExternalUserPermissions{
+		Exacts: []extsvc.RepoID{"inherited", "allowed", "other"},
+	}
+	if diff := cmp.Diff(want, perms); diff != "" {
+		t.Fatalf("mismatch (-want +got):\n%s", diff)
+	}
+
+	t.Run("other code host", func(t *testing.T) {
+		other := *account
+		other.ServiceID = "https://devops.example.com/"
+		if _, err := p.FetchUserPerms(context.Background(), &other); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestNewAuthzProviders(t *testing.T) {
+	conn := func(authProviderType string) *types.AzureDevOpsConnection {
+		return &types.AzureDevOpsConnection{
+			URN: "extsvc:azuredevops:1",
+			AzureDevOpsConnection: &schema.AzureDevOpsConnection{
+				Url:   "https://dev.azure.com",
+				Token: "secret",
+				Orgs:  []string{"sg"},
+				Authorization: &schema.AzureDevOpsAuthorization{
+					IdentityProvider: schema.AzureDevOpsIdentityProvider{
+						AuthProviderID:   "aad",
+						AuthProviderType: authProviderType,
+					},
+				},
+			},
+		}
+	}
+
+	cfg := &conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		AuthProviders: []schema.AuthProviders{{
+			Openidconnect: &schema.OpenIDConnectAuthProvider{ConfigID: "aad", Type: "openidconnect"},
+		}},
+	}}
+
+	NewProvider = func(op ProviderOp) authz.Provider {
+		p := newProvider(op, nil)
+		p.client = &fakeClient{}
+		return p
+	}
+	defer func() { NewProvider = func(op ProviderOp) authz.Provider { return newProvider(op, nil) } }()
+
+	ps, problems, _ := NewAuthzProviders(cfg, []*types.AzureDevOpsConnection{conn("openidconnect")})
+	if len(problems) != 0 || len(ps) != 1 {
+		t.Fatalf("expected one provider and no problems, got %d providers and problems %v", len(ps), problems)
+	}
+
+	ps, problems, _ = NewAuthzProviders(cfg, []*types.AzureDevOpsConnection{conn("saml")})
+	if len(problems) != 1 || len(ps) != 0 {
+		t.Fatalf("expected no provider and one problem, got %d providers and problems %v", len(ps), problems)
+	}
+}
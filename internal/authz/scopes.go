@@ -1,13 +1,186 @@
 package authz
 
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
 const (
 	// Access token scopes.
 	ScopeUserAll       = "user:all"        // Full control of all resources accessible to the user account.
 	ScopeSiteAdminSudo = "site-admin:sudo" // Ability to perform any action as any other user.
+
+	// Fine-grained access token scopes. Tokens that have one or more of these scopes but not
+	// ScopeUserAll can only be used for the operations granted by these scopes.
+	ScopeSearchRead      = "search:read"      // Ability to run searches.
+	ScopeCodeRead        = "code:read"        // Ability to read repositories and their contents.
+	ScopeCodeIntelUpload = "codeintel:upload" // Ability to upload precise code intelligence data.
+	ScopeBatchesWrite    = "batches:write"    // Ability to create, apply, and manage batch changes.
 )
 
+// scopeRepoPatternDelim separates the name of a fine-grained scope from the pattern of
+// repository names it is restricted to.
+const scopeRepoPatternDelim = "@"
+
 // AllScopes is a list of all known access token scopes.
 var AllScopes = []string{
 	ScopeUserAll,
 	ScopeSiteAdminSudo,
+	ScopeSearchRead,
+	ScopeCodeRead,
+	ScopeCodeIntelUpload,
+	ScopeBatchesWrite,
+}
+
+// fineGrainedScopes are the scopes that may be restricted to the repositories whose names match
+// a pattern, by appending "@" and a regular expression to the scope (e.g.,
+// "code:read@^github\.com/acme/").
+var fineGrainedScopes = map[string]struct{}{
+	ScopeSearchRead:      {},
+	ScopeCodeRead:        {},
+	ScopeCodeIntelUpload: {},
+	ScopeBatchesWrite:    {},
+}
+
+// ParseScope splits an access token scope into its name and the pattern of repository names it
+// is restricted to. The pattern is empty if the scope is not restricted to any repositories.
+func ParseScope(scope string) (name, repoPattern string) {
+	if i := strings.Index(scope, scopeRepoPatternDelim); i >= 0 {
+		return scope[:i], scope[i+len(scopeRepoPatternDelim):]
+	}
+	return scope, ""
+}
+
+// ValidateScope returns an error if scope is not a known access token scope, or if it is
+// restricted to an invalid repository name pattern.
+func ValidateScope(scope string) error {
+	name, repoPattern := ParseScope(scope)
+	known := false
+	for _, s := range AllScopes {
+		if s == name {
+			known = true
+		}
+	}
+	if !known {
+		return errors.Errorf("unknown access token scope %q (valid scopes: %q)", name, AllScopes)
+	}
+	if name == scope {
+		return nil
+	}
+
+	if _, ok := fineGrainedScopes[name]; !ok {
+		return errors.Errorf("access token scope %q can't be restricted to repositories", name)
+	}
+	if repoPattern == "" {
+		return errors.Errorf("access token scope %q has an empty repository pattern", scope)
+	}
+	if strings.Contains(repoPattern, scopeRepoPatternDelim) {
+		return errors.Errorf("repository pattern of access token scope %q must not contain %q", scope, scopeRepoPatternDelim)
+	}
+	if _, err := regexp.Compile(repoPattern); err != nil {
+		return errors.Wrapf(err, "invalid repository pattern in access token scope %q", scope)
+	}
+	return nil
+}
+
+// IsFineGrainedScope reports whether scope grants access to only a subset of the resources
+// that are accessible to the user account.
+func IsFineGrainedScope(scope string) bool {
+	name, _ := ParseScope(scope)
+	_, ok := fineGrainedScopes[name]
+	return ok
+}
+
+// InsufficientScopeError is returned when the actor was authenticated with an access token that
+// doesn't have the scope required to perform an operation.
+type InsufficientScopeError struct {
+	Scope string
+	Repo  api.RepoName // empty if the operation doesn't involve a repository
+}
+
+func (e *InsufficientScopeError) Error() string {
+	if e.Repo != "" {
+		return fmt.Sprintf("access token does not have scope %q for repository %q", e.Scope, e.Repo)
+	}
+	return fmt.Sprintf("access token does not have scope %q", e.Scope)
+}
+
+func (e *InsufficientScopeError) Forbidden() bool     { return true }
+func (e *InsufficientScopeError) HTTPStatusCode() int { return http.StatusForbidden }
+
+// CheckScope returns an InsufficientScopeError if the actor in ctx was authenticated with an
+// access token that has fine-grained scopes, but none of them is the given scope. The repository
+// patterns of the scopes are not checked; use CheckRepoScope or RepoPattern for that.
+//
+// Actors that weren't authenticated with such an access token may perform any operation.
+func CheckScope(ctx context.Context, scope string) error {
+	a := actor.FromContext(ctx)
+	if a.Scopes == nil {
+		return nil
+	}
+	for _, s := range a.Scopes {
+		if name, _ := ParseScope(s); name == scope {
+			return nil
+		}
+	}
+	return &InsufficientScopeError{Scope: scope}
+}
+
+// CheckRepoScope is like CheckScope, but also requires the repository pattern of the scope to
+// match repo.
+func CheckRepoScope(ctx context.Context, scope string, repo api.RepoName) error {
+	a := actor.FromContext(ctx)
+	if a.Scopes == nil {
+		return nil
+	}
+	for _, s := range a.Scopes {
+		name, repoPattern := ParseScope(s)
+		if name != scope {
+			continue
+		}
+		if repoPattern == "" {
+			return nil
+		}
+		// Repository patterns are validated when the token is created.
+		if re, err := regexp.Compile("(?i:" + repoPattern + ")"); err == nil && re.MatchString(string(repo)) {
+			return nil
+		}
+	}
+	return &InsufficientScopeError{Scope: scope, Repo: repo}
+}
+
+// RepoPattern returns a regular expression that matches the names of the repositories that the
+// actor in ctx may access with the given scope. It returns false if the actor may access all
+// repositories with the scope. The caller must check that the actor has the scope at all with
+// CheckScope.
+func RepoPattern(ctx context.Context, scope string) (string, bool) {
+	a := actor.FromContext(ctx)
+	if a.Scopes == nil {
+		return "", false
+	}
+
+	var patterns []string
+	for _, s := range a.Scopes {
+		name, repoPattern := ParseScope(s)
+		if name != scope {
+			continue
+		}
+		if repoPattern == "" {
+			return "", false
+		}
+		patterns = append(patterns, "(?:"+repoPattern+")")
+	}
+	if len(patterns) == 0 {
+		// Match nothing, so that callers that didn't check the scope fail closed.
+		return "$^", true
+	}
+	return strings.Join(patterns, "|"), true
 }
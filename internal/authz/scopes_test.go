@@ -0,0 +1,110 @@
+package authz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestValidateScope(t *testing.T) {
+	for _, scope := range []string{
+		ScopeUserAll,
+		ScopeSiteAdminSudo,
+		ScopeSearchRead,
+		ScopeCodeRead,
+		ScopeCodeIntelUpload,
+		ScopeBatchesWrite,
+		`code:read@^github\.com/acme/`,
+		`batches:write@^github\.com/acme/(a|b)$`,
+	} {
+		if err := ValidateScope(scope); err != nil {
+			t.Errorf("ValidateScope(%q): unexpected error: %s", scope, err)
+		}
+	}
+
+	for _, scope := range []string{
+		"",
+		"code:write",
+		"code:write@^a",
+		"user:all@^a",
+		"site-admin:sudo@^a",
+		"code:read@",
+		"code:read@(",
+		"code:read@a@b",
+	} {
+		if err := ValidateScope(scope); err == nil {
+			t.Errorf("ValidateScope(%q): expected error", scope)
+		}
+	}
+}
+
+func TestCheckScope(t *testing.T) {
+	unrestricted := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	restricted := actor.WithActor(context.Background(), &actor.Actor{UID: 1, Scopes: []string{
+		ScopeSearchRead,
+		`code:read@^github\.com/acme/`,
+		`code:read@^gitlab\.com/acme/`,
+	}})
+
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		scope string
+		repo  api.RepoName
+		want  bool
+	}{
+		{name: "unrestricted", ctx: unrestricted, scope: ScopeBatchesWrite, want: true},
+		{name: "unrestricted repo", ctx: unrestricted, scope: ScopeCodeRead, repo: "github.com/other/r", want: true},
+		{name: "scope", ctx: restricted, scope: ScopeSearchRead, want: true},
+		{name: "scope with any repo", ctx: restricted, scope: ScopeSearchRead, repo: "github.com/other/r", want: true},
+		{name: "missing scope", ctx: restricted, scope: ScopeBatchesWrite, want: false},
+		{name: "missing scope for repo", ctx: restricted, scope: ScopeBatchesWrite, repo: "github.com/acme/r", want: false},
+		{name: "repo matches first pattern", ctx: restricted, scope: ScopeCodeRead, repo: "github.com/acme/r", want: true},
+		{name: "repo matches second pattern", ctx: restricted, scope: ScopeCodeRead, repo: "gitlab.com/acme/r", want: true},
+		{name: "repo matches case-insensitively", ctx: restricted, scope: ScopeCodeRead, repo: "github.com/ACME/r", want: true},
+		{name: "repo doesn't match", ctx: restricted, scope: ScopeCodeRead, repo: "github.com/other/r", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			if tt.repo == "" {
+				err = CheckScope(tt.ctx, tt.scope)
+			} else {
+				err = CheckRepoScope(tt.ctx, tt.scope, tt.repo)
+			}
+			if got := err == nil; got != tt.want {
+				t.Errorf("got allowed %t, want %t (error: %v)", got, tt.want, err)
+			}
+			if err != nil {
+				if _, ok := err.(*InsufficientScopeError); !ok {
+					t.Errorf("got error of type %T, want *InsufficientScopeError", err)
+				}
+			}
+		})
+	}
+}
+
+func TestRepoPattern(t *testing.T) {
+	tests := []struct {
+		name        string
+		scopes      []string
+		wantPattern string
+		wantOK      bool
+	}{
+		{name: "unrestricted", scopes: nil},
+		{name: "scope without pattern", scopes: []string{ScopeSearchRead, `search:read@^a`}},
+		{name: "scope with patterns", scopes: []string{`search:read@^a`, `search:read@^b`, `code:read@^c`}, wantPattern: "(?:^a)|(?:^b)", wantOK: true},
+		{name: "missing scope", scopes: []string{ScopeCodeRead}, wantPattern: "$^", wantOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1, Scopes: tt.scopes})
+			pattern, ok := RepoPattern(ctx, ScopeSearchRead)
+			if pattern != tt.wantPattern || ok != tt.wantOK {
+				t.Errorf("got (%q, %t), want (%q, %t)", pattern, ok, tt.wantPattern, tt.wantOK)
+			}
+		})
+	}
+}
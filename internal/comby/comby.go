@@ -200,3 +200,48 @@ func Matches(ctx context.Context, args Args) (matches []FileMatch, err error) {
 	}
 	return matches, nil
 }
+
+// Replacements returns the diffs of rewriting all files for which comby finds
+// matches with args.RewriteTemplate.
+func Replacements(ctx context.Context, args Args) (diffs []FileDiff, err error) {
+	span, ctx := ot.StartSpanFromContext(ctx, "Comby.Replacements")
+	defer span.Finish()
+
+	b := new(bytes.Buffer)
+	w := bufio.NewWriter(b)
+
+	args.MatchOnly = false
+
+	err = PipeTo(ctx, args, w)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(b)
+	// increase the scanner buffer size for potentially long lines
+	scanner.Buffer(make([]byte, 100), 10*bufio.MaxScanTokenSize)
+	for scanner.Scan() {
+		b := scanner.Bytes()
+		var d *FileDiff
+		if err := json.Unmarshal(b, &d); err != nil {
+			// warn on decode errors and skip
+			log15.Warn("comby error: skipping unmarshaling error", "err", err.Error())
+			continue
+		}
+		if d == nil || d.Diff == "" {
+			continue
+		}
+		diffs = append(diffs, *d)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(diffs) > 0 {
+		log15.Info("comby invocation", "num_diffs", strconv.Itoa(len(diffs)))
+	}
+	return diffs, nil
+}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"os"
+	"reflect"
 	"testing"
 
 	"github.com/sourcegraph/sourcegraph/internal/testutil"
@@ -124,3 +125,49 @@ func main() {
 		}
 	}
 }
+
+func TestReplacements(t *testing.T) {
+	// If we are not on CI skip the test if comby is not installed.
+	if os.Getenv("CI") == "" && !exists() {
+		t.Skip("comby is not installed on the PATH. Try running 'bash <(curl -sL get.comby.dev)'.")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	files := map[string]string{
+		"README.md": `# Hello World`,
+		"main.go": `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("Hello foo")
+}
+`,
+	}
+
+	zipPath, cleanup, err := testutil.TempZipFromFiles(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	diffs, err := Replacements(ctx, Args{
+		Input:           ZipPath(zipPath),
+		MatchTemplate:   "func",
+		RewriteTemplate: "derp",
+		Matcher:         ".go",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []FileDiff{{
+		URI:  "main.go",
+		Diff: "--- main.go\n+++ main.go\n@@ -2,6 +2,6 @@\n \n import \"fmt\"\n \n-func main() {\n+derp main() {\n \tfmt.Println(\"Hello foo\")\n }",
+	}}
+	if !reflect.DeepEqual(diffs, want) {
+		t.Errorf("got %+v, want %+v", diffs, want)
+	}
+}
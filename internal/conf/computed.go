@@ -321,6 +321,24 @@ func AndOrQueryEnabled() bool {
 	return val == "enabled"
 }
 
+// CommitIndexEnabled reports whether the experimental commit index is
+// enabled.
+func CommitIndexEnabled() bool {
+	c := ExperimentalFeatures().CommitIndex
+	return c != nil && c.Enabled
+}
+
+// CommitIndexMaxCommitsPerRepo returns the maximum number of commits the
+// commit index holds per repository. If not set, it returns the default value
+// 10000.
+func CommitIndexMaxCommitsPerRepo() int {
+	c := ExperimentalFeatures().CommitIndex
+	if c == nil || c.MaxCommitsPerRepo <= 0 {
+		return 10000
+	}
+	return c.MaxCommitsPerRepo
+}
+
 func ExperimentalFeatures() schema.ExperimentalFeatures {
 	val := Get().ExperimentalFeatures
 	if val == nil {
@@ -0,0 +1,204 @@
+package conf
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+)
+
+// RedactedSecret replaces the values of secret properties in redacted site configuration.
+const RedactedSecret = "REDACTED"
+
+// secretPropertyPattern matches the names of site configuration properties that hold secrets,
+// such as "clientSecret", "password", "accessToken", "licenseKey", or "webhookURL". Every string
+// value nested below a matching property is redacted.
+var secretPropertyPattern = lazyregexp.New(`(?i)(secret|password|passwd|token|key$|webhook|credentials)`)
+
+// secretPropertyPaths are the paths of site configuration properties that hold secrets even though
+// their names don't say so, like URLs with an embedded key. Path elements are separated by "." and
+// array elements are denoted by "[]" (top-level property names may contain "." themselves).
+var secretPropertyPaths = map[string]struct{}{
+	// Slack and webhook notifier URLs embed the secret that authorizes posting to them, and the
+	// API URLs of the other notifiers may embed credentials.
+	"observability.alerts[].notifier.url":    {},
+	"observability.alerts[].notifier.apiUrl": {},
+
+	// Sentry DSNs embed the key of the project.
+	"log.sentry.dsn":        {},
+	"log.sentry.backendDSN": {},
+
+	"dotcom.slackLicenseExpirationWebhook": {},
+}
+
+// RedactSecrets returns the given site configuration as indented JSON in which the values of all
+// properties that may hold secrets (see secretPropertyPattern and secretPropertyPaths) are
+// replaced with RedactedSecret. Comments and trailing commas
+// are dropped and object properties are sorted, so that the output of two revisions of the site
+// configuration can be compared line by line.
+func RedactSecrets(raw string) (string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return "", nil
+	}
+
+	var v interface{}
+	if err := jsonc.Unmarshal(raw, &v); err != nil {
+		return "", err
+	}
+
+	redacted, err := json.MarshalIndent(redactValue(v, "", false), "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(redacted) + "\n", nil
+}
+
+// redactValue redacts the secrets in the value v of the property at path, which is secret if an
+// enclosing property is.
+func redactValue(v interface{}, path string, secret bool) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			keyPath := key
+			if path != "" {
+				keyPath = path + "." + key
+			}
+			_, secretPath := secretPropertyPaths[keyPath]
+			v[key] = redactValue(value, keyPath, secret || secretPath || secretPropertyPattern.MatchString(key))
+		}
+		return v
+
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactValue(value, path+"[]", secret)
+		}
+		return v
+
+	case string:
+		if secret && v != "" {
+			return RedactedSecret
+		}
+	}
+
+	return v
+}
+
+// diffContextLines is the number of unchanged lines shown around each change in a diff.
+const diffContextLines = 3
+
+// RedactedDiff returns a unified diff between two revisions of the site configuration with
+// secrets redacted from both sides (see RedactSecrets). An empty before revision yields a diff
+// that adds every line of the after revision.
+func RedactedDiff(before, after string) (string, error) {
+	redactedBefore, err := RedactSecrets(before)
+	if err != nil {
+		return "", err
+	}
+	redactedAfter, err := RedactSecrets(after)
+	if err != nil {
+		return "", err
+	}
+
+	return unifiedDiff(diffLines(redactedBefore, redactedAfter), diffContextLines), nil
+}
+
+type diffLine struct {
+	op   byte // one of ' ', '-', or '+'
+	text string
+}
+
+// diffLines returns the line-wise difference between before and after.
+func diffLines(before, after string) []diffLine {
+	dmp := diffmatchpatch.New()
+	a, b, lineArray := dmp.DiffLinesToChars(before, after)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(a, b, false), lineArray)
+
+	var lines []diffLine
+	for _, d := range diffs {
+		op := byte(' ')
+		switch d.Type {
+		case diffmatchpatch.DiffDelete:
+			op = '-'
+		case diffmatchpatch.DiffInsert:
+			op = '+'
+		}
+
+		for _, text := range strings.SplitAfter(d.Text, "\n") {
+			if text != "" {
+				lines = append(lines, diffLine{op: op, text: strings.TrimSuffix(text, "\n")})
+			}
+		}
+	}
+
+	return lines
+}
+
+// unifiedDiff formats the given lines as the hunks of a unified diff, keeping at most
+// context unchanged lines around each change.
+func unifiedDiff(lines []diffLine, context int) string {
+	// Count the lines of each side that precede each line of the diff so that
+	// we can label each hunk with its position in both revisions.
+	oldBefore := make([]int, len(lines)+1)
+	newBefore := make([]int, len(lines)+1)
+	for i, line := range lines {
+		oldBefore[i+1], newBefore[i+1] = oldBefore[i], newBefore[i]
+		if line.op != '+' {
+			oldBefore[i+1]++
+		}
+		if line.op != '-' {
+			newBefore[i+1]++
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(lines); {
+		if lines[i].op == ' ' {
+			i++
+			continue
+		}
+
+		// Extend the hunk until the next change is too far away to share context.
+		lastChange := i
+		for j := i + 1; j < len(lines) && j-lastChange <= 2*context; j++ {
+			if lines[j].op != ' ' {
+				lastChange = j
+			}
+		}
+
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		end := lastChange + context + 1
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n",
+			hunkRange(oldBefore[start], oldBefore[end]-oldBefore[start]),
+			hunkRange(newBefore[start], newBefore[end]-newBefore[start]),
+		)
+		for _, line := range lines[start:end] {
+			b.WriteByte(line.op)
+			b.WriteString(line.text)
+			b.WriteByte('\n')
+		}
+
+		i = end
+	}
+
+	return b.String()
+}
+
+// hunkRange formats the position of a hunk within one side of a unified diff, given the
+// number of lines preceding the hunk and the number of lines in the hunk.
+func hunkRange(preceding, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", preceding)
+	}
+	return fmt.Sprintf("%d,%d", preceding+1, count)
+}
@@ -0,0 +1,60 @@
+package reposource
+
+import (
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type AzureDevOps struct {
+	*schema.AzureDevOpsConnection
+}
+
+var _ RepoSource = AzureDevOps{}
+
+func (c AzureDevOps) CloneURLToRepoName(cloneURL string) (repoName api.RepoName, err error) {
+	parsedCloneURL, baseURL, match, err := parseURLs(cloneURL, c.Url)
+	if err != nil {
+		return "", err
+	}
+
+	path := strings.TrimPrefix(parsedCloneURL.Path, "/")
+	if !match {
+		// Azure DevOps Services serves SSH clones from a separate host and with
+		// paths of the form v3/{org}/{project}/{repo}.
+		if baseURL == nil || hostname(parsedCloneURL) != "ssh."+baseURL.Hostname() || !strings.HasPrefix(path, "v3/") {
+			return "", nil
+		}
+		parts := strings.Split(strings.TrimPrefix(path, "v3/"), "/")
+		if len(parts) != 3 {
+			return "", nil
+		}
+		return AzureDevOpsRepoName(c.RepositoryPathPattern, baseURL.Hostname(), parts[0], parts[1], parts[2]), nil
+	}
+
+	// HTTP(S) and Azure DevOps Server SSH clone URLs have the form
+	// {basePath}/{org}/{project}/_git/{repo}.
+	basePath := strings.TrimPrefix(baseURL.Path, "/")
+	if !strings.HasPrefix(path, basePath) {
+		return "", nil
+	}
+	parts := strings.Split(strings.TrimPrefix(path, basePath), "/")
+	if len(parts) != 4 || parts[2] != "_git" {
+		return "", nil
+	}
+	return AzureDevOpsRepoName(c.RepositoryPathPattern, baseURL.Hostname(), parts[0], parts[1], parts[3]), nil
+}
+
+func AzureDevOpsRepoName(repositoryPathPattern, host, org, project, repo string) api.RepoName {
+	if repositoryPathPattern == "" {
+		repositoryPathPattern = "{host}/{org}/{project}/{repo}"
+	}
+
+	return api.RepoName(strings.NewReplacer(
+		"{host}", host,
+		"{org}", org,
+		"{project}", project,
+		"{repo}", repo,
+	).Replace(repositoryPathPattern))
+}
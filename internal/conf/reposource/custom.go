@@ -9,19 +9,36 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 )
 
 func init() {
 	conf.ContributeValidator(func(c conf.Unified) (problems conf.Problems) {
-		for _, c := range c.GitCloneURLToRepositoryName {
-			if _, err := regexp.Compile(c.From); err != nil {
-				problems = append(problems, conf.NewSiteProblem(fmt.Sprintf("Not a valid regexp: %s. See the valid syntax: https://golang.org/pkg/regexp/", c.From)))
+		for i, c := range c.GitCloneURLToRepositoryName {
+			from, err := regexp.Compile(c.From)
+			if err != nil {
+				problems = append(problems, conf.NewSiteProblemAt(conf.JSONPointer("git.cloneURLToRepositoryName", i, "from"), fmt.Sprintf("Not a valid regexp: %s. See the valid syntax: https://golang.org/pkg/regexp/", c.From)))
+				continue
+			}
+
+			// Every placeholder of the template must refer to a named capturing group.
+			groups := map[string]struct{}{}
+			for _, name := range from.SubexpNames() {
+				groups[name] = struct{}{}
+			}
+			for _, match := range templatePlaceholderPattern.FindAllStringSubmatch(c.To, -1) {
+				if _, ok := groups[match[1]]; !ok {
+					problems = append(problems, conf.NewSiteProblemAt(conf.JSONPointer("git.cloneURLToRepositoryName", i, "to"), fmt.Sprintf("Template %s references {%s}, which is not a named capturing group of %s", c.To, match[1], c.From)))
+				}
 			}
 		}
 		return
 	})
 }
 
+// templatePlaceholderPattern matches the placeholders of a clone URL mapping template, such as {name}.
+var templatePlaceholderPattern = lazyregexp.New(`\{(\w+)\}`)
+
 type cloneURLResolver struct {
 	from *regexp.Regexp
 	to   string
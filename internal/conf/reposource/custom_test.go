@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
 )
 
 func TestCustomCloneURLToRepoName(t *testing.T) {
@@ -54,3 +56,33 @@ func TestCustomCloneURLToRepoName(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateCloneURLToRepositoryName(t *testing.T) {
+	problems, err := conf.Validate(conftypes.RawUnified{Site: `{
+		"git.cloneURLToRepositoryName": [
+			{"from": "^(", "to": "{name}"},
+			{"from": "^git@(?P<host>[^:]+):(?P<path>.+)$", "to": "{host}/{repo}"},
+			{"from": "^https://(?P<host>[^/]+)/(?P<path>.+)$", "to": "{host}/{path}"}
+		]
+	}`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	have := map[string]bool{}
+	for _, p := range problems.Site() {
+		have[p.Location()] = true
+	}
+	want := map[string]bool{
+		"/git.cloneURLToRepositoryName/0/from": true,
+		"/git.cloneURLToRepositoryName/1/to":   true,
+	}
+	if len(have) != len(want) {
+		t.Fatalf("unexpected problems %v", problems)
+	}
+	for location := range want {
+		if !have[location] {
+			t.Errorf("expected a problem at %q, have %v", location, problems)
+		}
+	}
+}
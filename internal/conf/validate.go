@@ -59,6 +59,7 @@ const (
 type Problem struct {
 	kind        problemKind
 	description string
+	location    string
 }
 
 // NewSiteProblem creates a new site config problem with given message.
@@ -69,6 +70,16 @@ func NewSiteProblem(msg string) *Problem {
 	}
 }
 
+// NewSiteProblemAt creates a new site config problem with given message about the value at the
+// given location. The location is a JSON pointer (see JSONPointer) into the site configuration.
+func NewSiteProblemAt(location, msg string) *Problem {
+	return &Problem{
+		kind:        problemSite,
+		description: msg,
+		location:    location,
+	}
+}
+
 // NewExternalServiceProblem creates a new external service config problem with given message.
 func NewExternalServiceProblem(msg string) *Problem {
 	return &Problem{
@@ -91,11 +102,21 @@ func (p Problem) String() string {
 	return p.description
 }
 
+// Location returns the JSON pointer (RFC 6901) to the configuration value the problem is about,
+// or an empty string if the problem is not about a specific value.
+func (p Problem) Location() string {
+	return p.location
+}
+
 func (p *Problem) MarshalJSON() ([]byte, error) {
-	return json.Marshal(map[string]string{
+	m := map[string]string{
 		"kind":        string(p.kind),
 		"description": p.description,
-	})
+	}
+	if p.location != "" {
+		m["location"] = p.location
+	}
+	return json.Marshal(m)
 }
 
 func (p *Problem) UnmarshalJSON(b []byte) error {
@@ -105,9 +126,24 @@ func (p *Problem) UnmarshalJSON(b []byte) error {
 	}
 	p.kind = problemKind(m["kind"])
 	p.description = m["description"]
+	p.location = m["location"]
 	return nil
 }
 
+// JSONPointer returns a JSON pointer (RFC 6901) referencing the value reached by following the
+// given object property names and array indexes from the root of a JSON document. For example,
+// JSONPointer("auth.providers", 0, "clientID") returns "/auth.providers/0/clientID".
+func JSONPointer(path ...interface{}) string {
+	var b strings.Builder
+	for _, segment := range path {
+		b.WriteByte('/')
+		b.WriteString(jsonPointerEscaper.Replace(fmt.Sprint(segment)))
+	}
+	return b.String()
+}
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
 // Problems is a list of problems.
 type Problems []*Problem
 
@@ -169,11 +205,13 @@ func (ps Problems) ExternalService() (problems Problems) {
 // Validate validates the configuration against the JSON Schema and other
 // custom validation checks.
 func Validate(input conftypes.RawUnified) (problems Problems, err error) {
-	siteProblems, err := doValidate(input.Site, schema.SiteSchemaJSON)
+	violations, err := validateSchema(input.Site, schema.SiteSchemaJSON)
 	if err != nil {
 		return nil, err
 	}
-	problems = append(problems, NewSiteProblems(siteProblems...)...)
+	for _, v := range violations {
+		problems = append(problems, NewSiteProblemAt(v.location, v.message))
+	}
 
 	customProblems, err := validateCustomRaw(conftypes.RawUnified{
 		Site: string(jsonc.Normalize(input.Site)),
@@ -202,28 +240,64 @@ func ValidateSetting(input string) (problems []string, err error) {
 }
 
 func doValidate(inputStr, schema string) (messages []string, err error) {
+	violations, err := validateSchema(inputStr, schema)
+	if err != nil {
+		return nil, err
+	}
+	messages = make([]string, 0, len(violations))
+	for _, v := range violations {
+		messages = append(messages, v.message)
+	}
+	return messages, nil
+}
+
+// schemaViolation describes a JSON Schema validation error and the location of the offending value.
+type schemaViolation struct {
+	location string // a JSON pointer
+	message  string
+}
+
+func validateSchema(inputStr, schema string) ([]schemaViolation, error) {
 	input := jsonc.Normalize(inputStr)
 
 	res, err := validate([]byte(schema), input)
 	if err != nil {
 		return nil, err
 	}
-	messages = make([]string, 0, len(res.Errors()))
+	violations := make([]schemaViolation, 0, len(res.Errors()))
 	for _, e := range res.Errors() {
 		if _, ok := ignoreLegacyKubernetesFields[e.Field()]; ok {
 			continue
 		}
 
 		var keyPath string
+		var path []interface{}
 		if c := e.Context(); c != nil {
 			keyPath = strings.TrimPrefix(e.Context().String("."), "(root).")
+
+			// Property names may contain dots (e.g. "auth.providers"), so we split the
+			// context on a delimiter that cannot occur in the normalized input instead.
+			for _, segment := range strings.Split(c.String("\x00"), "\x00") {
+				if segment != "(root)" {
+					path = append(path, segment)
+				}
+			}
 		} else {
 			keyPath = e.Field()
+			path = append(path, e.Field())
+		}
+		if e.Type() == "additional_property_not_allowed" {
+			if property, ok := e.Details()["property"]; ok {
+				path = append(path, property)
+			}
 		}
 
-		messages = append(messages, fmt.Sprintf("%s: %s", keyPath, e.Description()))
+		violations = append(violations, schemaViolation{
+			location: JSONPointer(path...),
+			message:  fmt.Sprintf("%s: %s", keyPath, e.Description()),
+		})
 	}
-	return messages, nil
+	return violations, nil
 }
 
 func validate(schema, input []byte) (*gojsonschema.Result, error) {
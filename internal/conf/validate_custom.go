@@ -46,10 +46,10 @@ func validateCustom(cfg Unified) (problems Problems) {
 		hasSMTP := cfg.EmailSmtp != nil
 		hasSMTPAuth := cfg.EmailSmtp != nil && cfg.EmailSmtp.Authentication != "none"
 		if hasSMTP && cfg.EmailAddress == "" {
-			invalid(NewSiteProblem(`should set email.address because email.smtp is set`))
+			invalid(NewSiteProblemAt(JSONPointer("email.address"), `should set email.address because email.smtp is set`))
 		}
 		if hasSMTPAuth && (cfg.EmailSmtp.Username == "" && cfg.EmailSmtp.Password == "") {
-			invalid(NewSiteProblem(`must set email.smtp username and password for email.smtp authentication`))
+			invalid(NewSiteProblemAt(JSONPointer("email.smtp"), `must set email.smtp username and password for email.smtp authentication`))
 		}
 	}
 
@@ -58,18 +58,20 @@ func validateCustom(cfg Unified) (problems Problems) {
 	if cfg.ExternalURL != "" {
 		eURL, err := url.Parse(cfg.ExternalURL)
 		if err != nil {
-			invalid(NewSiteProblem(`externalURL must be a valid URL`))
+			invalid(NewSiteProblemAt(JSONPointer("externalURL"), `externalURL must be a valid URL`))
 		} else if eURL.Path != "/" && eURL.Path != "" {
-			invalid(NewSiteProblem(`externalURL must not be a non-root URL`))
+			invalid(NewSiteProblemAt(JSONPointer("externalURL"), `externalURL must not be a non-root URL`))
 		}
 	}
 
-	for _, rule := range cfg.GitUpdateInterval {
+	for i, rule := range cfg.GitUpdateInterval {
 		if _, err := regexp.Compile(rule.Pattern); err != nil {
-			invalid(NewSiteProblem(fmt.Sprintf("GitUpdateIntervalRule pattern is not valid regex: %q", rule.Pattern)))
+			invalid(NewSiteProblemAt(JSONPointer("gitUpdateInterval", i, "pattern"), fmt.Sprintf("GitUpdateIntervalRule pattern is not valid regex: %q", rule.Pattern)))
 		}
 	}
 
+	problems = append(problems, validateSemantic(cfg)...)
+
 	for _, f := range contributedValidators {
 		problems = append(problems, f(cfg)...)
 	}
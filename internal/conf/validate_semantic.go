@@ -0,0 +1,182 @@
+package conf
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// validateSemantic validates the shape of site configuration values that the JSON Schema only
+// describes as strings, and relationships between values that the JSON Schema cannot express.
+// None of these checks perform network requests, so they're cheap enough to run on every
+// validation.
+func validateSemantic(cfg Unified) (problems Problems) {
+	problems = append(problems, validateURLs(cfg)...)
+	problems = append(problems, validateAuthProviderUniqueness(cfg)...)
+	problems = append(problems, validateEncryptionKeys(cfg)...)
+	return problems
+}
+
+// validateURLs checks that all URLs Sourcegraph sends requests to are absolute HTTP(S) URLs.
+func validateURLs(cfg Unified) (problems Problems) {
+	check := func(location, name, value string) {
+		if value == "" {
+			return
+		}
+
+		// 🚨 SECURITY: Some of these URLs embed secrets (e.g. Slack webhook URLs), so the
+		// value must not be included in the problem description.
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			problems = append(problems, NewSiteProblemAt(location, fmt.Sprintf("%s must be an absolute URL with an http or https scheme and a host", name)))
+		}
+	}
+
+	if cfg.ParentSourcegraph != nil {
+		check(JSONPointer("parentSourcegraph", "url"), "parentSourcegraph.url", cfg.ParentSourcegraph.Url)
+	}
+
+	for i, p := range cfg.AuthProviders {
+		switch {
+		case p.Github != nil:
+			check(JSONPointer("auth.providers", i, "url"), "GitHub auth provider url", p.Github.Url)
+		case p.Gitlab != nil:
+			check(JSONPointer("auth.providers", i, "url"), "GitLab auth provider url", p.Gitlab.Url)
+		case p.Openidconnect != nil:
+			check(JSONPointer("auth.providers", i, "issuer"), "OpenID Connect auth provider issuer", p.Openidconnect.Issuer)
+		case p.Saml != nil:
+			check(JSONPointer("auth.providers", i, "identityProviderMetadataURL"), "SAML auth provider identityProviderMetadataURL", p.Saml.IdentityProviderMetadataURL)
+		}
+	}
+
+	for i, alert := range cfg.ObservabilityAlerts {
+		if alert == nil {
+			continue
+		}
+
+		n := alert.Notifier
+		switch {
+		case n.Slack != nil:
+			check(JSONPointer("observability.alerts", i, "notifier", "url"), "Slack notifier url", n.Slack.Url)
+		case n.Webhook != nil:
+			check(JSONPointer("observability.alerts", i, "notifier", "url"), "webhook notifier url", n.Webhook.Url)
+		case n.Pagerduty != nil:
+			check(JSONPointer("observability.alerts", i, "notifier", "apiUrl"), "PagerDuty notifier apiUrl", n.Pagerduty.ApiUrl)
+		case n.Opsgenie != nil:
+			check(JSONPointer("observability.alerts", i, "notifier", "apiUrl"), "OpsGenie notifier apiUrl", n.Opsgenie.ApiUrl)
+		}
+	}
+
+	return problems
+}
+
+// validateAuthProviderUniqueness checks that no two auth providers claim the same identity, as
+// only one of them would be reachable when users sign in.
+func validateAuthProviderUniqueness(cfg Unified) (problems Problems) {
+	seenTypes := map[string]int{}
+	seenConfigIDs := map[string]int{}
+	seenOAuthApps := map[string]int{}
+
+	for i, p := range cfg.AuthProviders {
+		switch {
+		case p.Builtin != nil, p.HttpHeader != nil:
+			// These providers cannot be distinguished from one another.
+			typ := "builtin"
+			if p.HttpHeader != nil {
+				typ = "http-header"
+			}
+			if j, ok := seenTypes[typ]; ok {
+				problems = append(problems, NewSiteProblemAt(JSONPointer("auth.providers", i), fmt.Sprintf("only one %s auth provider may be set, but the auth provider at index %d is also of that type", typ, j)))
+			} else {
+				seenTypes[typ] = i
+			}
+
+		case p.Saml != nil, p.Openidconnect != nil:
+			configID := ""
+			if p.Saml != nil {
+				configID = p.Saml.ConfigID
+			} else {
+				configID = p.Openidconnect.ConfigID
+			}
+			if configID == "" {
+				continue
+			}
+			if j, ok := seenConfigIDs[configID]; ok {
+				problems = append(problems, NewSiteProblemAt(JSONPointer("auth.providers", i, "configID"), fmt.Sprintf("auth provider configID %q is already used by the auth provider at index %d", configID, j)))
+			} else {
+				seenConfigIDs[configID] = i
+			}
+
+		case p.Github != nil, p.Gitlab != nil:
+			var key string
+			if p.Github != nil {
+				key = "github " + normalizeProviderURL(p.Github.Url, "https://github.com/") + " " + p.Github.ClientID
+			} else {
+				key = "gitlab " + normalizeProviderURL(p.Gitlab.Url, "https://gitlab.com/") + " " + p.Gitlab.ClientID
+			}
+			if j, ok := seenOAuthApps[key]; ok {
+				problems = append(problems, NewSiteProblemAt(JSONPointer("auth.providers", i), fmt.Sprintf("auth provider uses the same code host URL and clientID as the auth provider at index %d", j)))
+			} else {
+				seenOAuthApps[key] = i
+			}
+		}
+	}
+
+	return problems
+}
+
+func normalizeProviderURL(u, defaultURL string) string {
+	if u == "" {
+		u = defaultURL
+	}
+	return strings.TrimSuffix(strings.ToLower(u), "/")
+}
+
+// cloudKMSKeyNamePattern matches the resource name of a Cloud KMS crypto key.
+var cloudKMSKeyNamePattern = lazyregexp.New(`^projects/[^/]+/locations/[^/]+/keyRings/[^/]+/cryptoKeys/[^/]+$`)
+
+// validateEncryptionKeys checks the encryption key configuration for mistakes that would
+// otherwise only surface when the keyring is created at startup.
+func validateEncryptionKeys(cfg Unified) (problems Problems) {
+	keys := cfg.EncryptionKeys
+	if keys == nil {
+		return nil
+	}
+
+	if keys.EnableCache && keys.CacheSize <= 0 {
+		problems = append(problems, NewSiteProblemAt(JSONPointer("encryption.keys", "cacheSize"), "encryption.keys.cacheSize must be positive when encryption.keys.enableCache is true"))
+	}
+
+	for _, k := range []struct {
+		name string
+		key  *schema.EncryptionKey
+	}{
+		{"batchChangesCredentialKey", keys.BatchChangesCredentialKey},
+		{"externalServiceKey", keys.ExternalServiceKey},
+		{"userExternalAccountKey", keys.UserExternalAccountKey},
+	} {
+		if k.key == nil {
+			continue
+		}
+
+		switch {
+		case k.key.Cloudkms != nil:
+			if !cloudKMSKeyNamePattern.MatchString(k.key.Cloudkms.Keyname) {
+				problems = append(problems, NewSiteProblemAt(JSONPointer("encryption.keys", k.name, "keyname"), fmt.Sprintf("encryption.keys.%s.keyname must be a Cloud KMS crypto key resource name (projects/<project>/locations/<location>/keyRings/<key ring>/cryptoKeys/<key>)", k.name)))
+			}
+		case k.key.Awskms != nil:
+			if k.key.Awskms.KeyId == "" {
+				problems = append(problems, NewSiteProblemAt(JSONPointer("encryption.keys", k.name, "keyId"), fmt.Sprintf("encryption.keys.%s.keyId must not be empty", k.name)))
+			}
+		case k.key.Mounted != nil:
+			if (k.key.Mounted.Filepath == "") == (k.key.Mounted.EnvVarName == "") {
+				problems = append(problems, NewSiteProblemAt(JSONPointer("encryption.keys", k.name), fmt.Sprintf("encryption.keys.%s must set exactly one of filepath and envVarName", k.name)))
+			}
+		}
+	}
+
+	return problems
+}
@@ -32,9 +32,10 @@ func TestValidate(t *testing.T) {
 
 func TestValidateCustom(t *testing.T) {
 	tests := map[string]struct {
-		raw         string
-		wantProblem string
-		wantErr     string
+		raw          string
+		wantProblem  string
+		wantLocation string
+		wantErr      string
 	}{
 		"unrecognized auth.providers": {
 			raw:     `{"auth.providers":[{"type":"asdf"}]}`,
@@ -47,8 +48,72 @@ func TestValidateCustom(t *testing.T) {
 			raw: `{"externalURL":"http://example.com/"}`,
 		},
 		"non-root externalURL": {
-			raw:         `{"externalURL":"http://example.com/sourcegraph"}`,
-			wantProblem: "externalURL must not be a non-root URL",
+			raw:          `{"externalURL":"http://example.com/sourcegraph"}`,
+			wantProblem:  "externalURL must not be a non-root URL",
+			wantLocation: "/externalURL",
+		},
+		"invalid gitUpdateInterval pattern": {
+			raw:          `{"gitUpdateInterval":[{"pattern":"^github.com/","interval":1},{"pattern":"(","interval":1}]}`,
+			wantProblem:  "GitUpdateIntervalRule pattern is not valid regex",
+			wantLocation: "/gitUpdateInterval/1/pattern",
+		},
+		"relative parentSourcegraph url": {
+			raw:          `{"parentSourcegraph":{"url":"sourcegraph.com"}}`,
+			wantProblem:  "parentSourcegraph.url must be an absolute URL",
+			wantLocation: "/parentSourcegraph/url",
+		},
+		"non-http notifier url": {
+			raw:          `{"observability.alerts":[{"level":"critical","notifier":{"type":"slack","url":"ftp://hooks.slack.com/secret"}}]}`,
+			wantProblem:  "Slack notifier url must be an absolute URL",
+			wantLocation: "/observability.alerts/0/notifier/url",
+		},
+		"valid auth providers": {
+			raw: `{"auth.providers":[
+				{"type":"builtin"},
+				{"type":"github","clientID":"a","clientSecret":"s"},
+				{"type":"github","url":"https://github.example.com","clientID":"a","clientSecret":"s"},
+				{"type":"openidconnect","configID":"a","issuer":"https://a","clientID":"a","clientSecret":"s"},
+				{"type":"saml","configID":"b","identityProviderMetadataURL":"https://b"}
+			]}`,
+		},
+		"duplicate builtin auth provider": {
+			raw:          `{"auth.providers":[{"type":"builtin"},{"type":"builtin","allowSignup":true}]}`,
+			wantProblem:  "only one builtin auth provider may be set",
+			wantLocation: "/auth.providers/1",
+		},
+		"duplicate auth provider configID": {
+			raw: `{"auth.providers":[
+				{"type":"openidconnect","configID":"a","issuer":"https://a","clientID":"a","clientSecret":"s"},
+				{"type":"saml","configID":"a","identityProviderMetadataURL":"https://b"}
+			]}`,
+			wantProblem:  `auth provider configID "a" is already used by the auth provider at index 0`,
+			wantLocation: "/auth.providers/1/configID",
+		},
+		"duplicate GitHub OAuth app": {
+			raw: `{"auth.providers":[
+				{"type":"github","clientID":"a","clientSecret":"s"},
+				{"type":"github","url":"https://GitHub.com/","clientID":"a","clientSecret":"t"}
+			]}`,
+			wantProblem:  "same code host URL and clientID as the auth provider at index 0",
+			wantLocation: "/auth.providers/1",
+		},
+		"invalid Cloud KMS key name": {
+			raw:          `{"encryption.keys":{"externalServiceKey":{"type":"cloudkms","keyname":"my-key"}}}`,
+			wantProblem:  "encryption.keys.externalServiceKey.keyname must be a Cloud KMS crypto key resource name",
+			wantLocation: "/encryption.keys/externalServiceKey/keyname",
+		},
+		"valid Cloud KMS key name": {
+			raw: `{"encryption.keys":{"externalServiceKey":{"type":"cloudkms","keyname":"projects/p/locations/global/keyRings/r/cryptoKeys/k"}}}`,
+		},
+		"ambiguous mounted key": {
+			raw:          `{"encryption.keys":{"userExternalAccountKey":{"type":"mounted","keyname":"k","filepath":"/k","envVarName":"K"}}}`,
+			wantProblem:  "encryption.keys.userExternalAccountKey must set exactly one of filepath and envVarName",
+			wantLocation: "/encryption.keys/userExternalAccountKey",
+		},
+		"cache without size": {
+			raw:          `{"encryption.keys":{"enableCache":true}}`,
+			wantProblem:  "encryption.keys.cacheSize must be positive",
+			wantLocation: "/encryption.keys/cacheSize",
 		},
 	}
 	for name, test := range tests {
@@ -71,6 +136,9 @@ func TestValidateCustom(t *testing.T) {
 			}
 			for _, p := range problems {
 				if strings.Contains(p.String(), test.wantProblem) {
+					if p.Location() != test.wantLocation {
+						t.Fatalf("unexpected location for problem %q. want=%q have=%q", p, test.wantLocation, p.Location())
+					}
 					return
 				}
 			}
@@ -79,6 +147,42 @@ func TestValidateCustom(t *testing.T) {
 	}
 }
 
+func TestValidateSchemaLocations(t *testing.T) {
+	problems, err := Validate(conftypes.RawUnified{Site: `{
+		"auth.providers": [{"type": "builtin"}],
+		"gitUpdateInterval": [{"pattern": "^github.com/", "interval": 1, "bogus": 1}],
+		"unknownProperty": true,
+	}`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	locations := map[string]bool{}
+	for _, p := range problems {
+		locations[p.Location()] = true
+	}
+	for _, want := range []string{"/gitUpdateInterval/0/bogus", "/unknownProperty"} {
+		if !locations[want] {
+			t.Errorf("expected a problem at %q, have %v", want, problems)
+		}
+	}
+}
+
+func TestJSONPointer(t *testing.T) {
+	for _, test := range []struct {
+		path []interface{}
+		want string
+	}{
+		{path: nil, want: ""},
+		{path: []interface{}{"auth.providers", 0, "clientID"}, want: "/auth.providers/0/clientID"},
+		{path: []interface{}{"a/b", "c~d"}, want: "/a~1b/c~0d"},
+	} {
+		if have := JSONPointer(test.path...); have != test.want {
+			t.Errorf("unexpected pointer for %v. want=%q have=%q", test.path, test.want, have)
+		}
+	}
+}
+
 func TestProblems(t *testing.T) {
 	siteProblems := NewSiteProblems(
 		"siteProblem1",
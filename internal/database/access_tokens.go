@@ -155,6 +155,47 @@ RETURNING t.subject_user_id
 	return subjectUserID, nil
 }
 
+// LookupScopes looks up the access token. If it's valid, it returns the subject's user ID and the
+// scopes of the access token. Otherwise ErrAccessTokenNotFound is returned. It is used to
+// authenticate requests with access tokens that may only have fine-grained scopes; the caller is
+// responsible for enforcing the returned scopes.
+//
+// Calling LookupScopes also updates the access token's last-used-at date.
+//
+// 🚨 SECURITY: This returns a user ID if and only if the tokenHexEncoded corresponds to a valid,
+// non-deleted access token.
+func (s *AccessTokenStore) LookupScopes(ctx context.Context, tokenHexEncoded string) (subjectUserID int32, scopes []string, err error) {
+	if Mocks.AccessTokens.LookupScopes != nil {
+		return Mocks.AccessTokens.LookupScopes(tokenHexEncoded)
+	}
+
+	token, err := hex.DecodeString(tokenHexEncoded)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "AccessTokens.LookupScopes")
+	}
+
+	if err := s.Handle().DB().QueryRowContext(ctx,
+		// Ensure that subject and creator users still exist.
+		`
+UPDATE access_tokens t SET last_used_at=now()
+WHERE t.id IN (
+	SELECT t2.id FROM access_tokens t2
+	JOIN users subject_user ON t2.subject_user_id=subject_user.id AND subject_user.deleted_at IS NULL
+	JOIN users creator_user ON t2.creator_user_id=creator_user.id AND creator_user.deleted_at IS NULL
+	WHERE t2.value_sha256=$1 AND t2.deleted_at IS NULL
+)
+RETURNING t.subject_user_id, t.scopes
+`,
+		toSHA256Bytes(token),
+	).Scan(&subjectUserID, pq.Array(&scopes)); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil, ErrAccessTokenNotFound
+		}
+		return 0, nil, err
+	}
+	return subjectUserID, scopes, nil
+}
+
 // GetByID retrieves the access token (if any) given its ID.
 //
 // 🚨 SECURITY: The caller must ensure that the actor is permitted to view this access token.
@@ -309,8 +350,9 @@ func toSHA256Bytes(input []byte) []byte {
 }
 
 type MockAccessTokens struct {
-	Create     func(subjectUserID int32, scopes []string, note string, creatorUserID int32) (id int64, token string, err error)
-	DeleteByID func(id int64, subjectUserID int32) error
-	Lookup     func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error)
-	GetByID    func(id int64) (*AccessToken, error)
+	Create       func(subjectUserID int32, scopes []string, note string, creatorUserID int32) (id int64, token string, err error)
+	DeleteByID   func(id int64, subjectUserID int32) error
+	Lookup       func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error)
+	LookupScopes func(tokenHexEncoded string) (subjectUserID int32, scopes []string, err error)
+	GetByID      func(id int64) (*AccessToken, error)
 }
@@ -229,6 +229,50 @@ func TestAccessTokens_Lookup(t *testing.T) {
 	}
 }
 
+// 🚨 SECURITY: This tests the routine that verifies access tokens with fine-grained scopes.
+func TestAccessTokens_LookupScopes(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx := context.Background()
+
+	subject, err := Users(db).Create(ctx, NewUser{
+		Email:                 "a@example.com",
+		Username:              "u1",
+		Password:              "p1",
+		EmailVerificationCode: "c1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tid0, tv0, err := AccessTokens(db).Create(ctx, subject.ID, []string{"code:read@^github\\.com/a/", "search:read"}, "n0", subject.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gotSubjectUserID, gotScopes, err := AccessTokens(db).LookupScopes(ctx, tv0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := subject.ID; gotSubjectUserID != want {
+		t.Errorf("got %v, want %v", gotSubjectUserID, want)
+	}
+	if want := []string{"code:read@^github\\.com/a/", "search:read"}; !reflect.DeepEqual(gotScopes, want) {
+		t.Errorf("got scopes %q, want %q", gotScopes, want)
+	}
+
+	// Delete a token and ensure LookupScopes fails on it.
+	if err := AccessTokens(db).DeleteByID(ctx, tid0, subject.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := AccessTokens(db).LookupScopes(ctx, tv0); err != ErrAccessTokenNotFound {
+		t.Fatalf("got error %v, want %v", err, ErrAccessTokenNotFound)
+	}
+}
+
 // 🚨 SECURITY: This tests that deleting the subject or creator user of an access token invalidates
 // the token, and that no new access tokens may be created for deleted users.
 func TestAccessTokens_Lookup_deletedUser(t *testing.T) {
@@ -0,0 +1,386 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/batch"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// CommitIndexState describes how much of a repository's history is in the
+// commit index.
+type CommitIndexState struct {
+	RepoID api.RepoID
+	// HeadRef is the full name of the ref HEAD pointed to when the repository
+	// was indexed, such as "refs/heads/main".
+	HeadRef string
+	// IndexedCommit is the commit HeadRef pointed to when the repository was
+	// indexed.
+	IndexedCommit api.CommitID
+	// OldestCommitDate is the oldest committer date of the indexed commits. It
+	// is zero if no commits are indexed.
+	OldestCommitDate time.Time
+	// HistoryComplete is whether every non-merge commit reachable from
+	// IndexedCommit is indexed.
+	HistoryComplete bool
+	// NextSeq is the smallest sequence number which newly appended commits
+	// may use. Commits with larger sequence numbers come earlier in `git log`
+	// order.
+	NextSeq   int64
+	IndexedAt time.Time
+}
+
+// CommitIndexEntry is a commit stored in the commit index.
+type CommitIndexEntry struct {
+	CommitID       api.CommitID
+	Seq            int64
+	AuthorName     string
+	AuthorEmail    string
+	AuthorDate     time.Time
+	CommitterName  string
+	CommitterEmail string
+	CommitterDate  time.Time
+	Message        string
+	Parents        []string
+	// Diff is the patch of the commit as printed by `git log --patch
+	// --no-prefix`. It is empty if the commit changes no files, if it was
+	// not requested or if it is too large to be stored.
+	Diff string
+	// DiffTooLarge is whether the diff was larger than MaxCommitIndexDiffSize
+	// and therefore not stored. Searches of such diffs must use git instead.
+	DiffTooLarge bool
+}
+
+// MaxCommitIndexDiffSize is the size in bytes of the largest diff stored in
+// the commit index. It matches the default size of the largest files indexed
+// search indexes.
+const MaxCommitIndexDiffSize = 1 << 20
+
+// CommitIndexSearchOptions specifies the options for searching the commit
+// index of a repository. The substring conditions only narrow down the
+// candidates; callers are expected to match the returned entries exactly.
+type CommitIndexSearchOptions struct {
+	RepoID api.RepoID
+	// MessageSubstrings and DiffSubstrings must all occur in the message or
+	// the diff, ignoring case. Commits whose diffs are too large to be stored
+	// always match the DiffSubstrings.
+	MessageSubstrings []string
+	DiffSubstrings    []string
+	// After and Before bound the committer date of returned commits
+	// (inclusive). They are ignored if zero.
+	After  time.Time
+	Before time.Time
+	// BeforeSeq, if non-zero, only returns commits with a smaller sequence
+	// number. It is used to page through the results.
+	BeforeSeq int64
+	// WithDiffs loads the diffs of the returned commits.
+	WithDiffs bool
+	Limit     int
+}
+
+func (o CommitIndexSearchOptions) sqlConditions() []*sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("c.repo_id = %d", o.RepoID)}
+	for _, s := range o.MessageSubstrings {
+		conds = append(conds, sqlf.Sprintf("c.message ILIKE %s", "%"+escapeLikePattern(s)+"%"))
+	}
+	if len(o.DiffSubstrings) > 0 {
+		diffConds := make([]*sqlf.Query, 0, len(o.DiffSubstrings))
+		for _, s := range o.DiffSubstrings {
+			diffConds = append(diffConds, sqlf.Sprintf("d.diff ILIKE %s", "%"+escapeLikePattern(s)+"%"))
+		}
+		conds = append(conds, sqlf.Sprintf("(c.diff_too_large OR (%s))", sqlf.Join(diffConds, "AND")))
+	}
+	if !o.After.IsZero() {
+		conds = append(conds, sqlf.Sprintf("c.committer_date >= %s", o.After))
+	}
+	if !o.Before.IsZero() {
+		conds = append(conds, sqlf.Sprintf("c.committer_date <= %s", o.Before))
+	}
+	if o.BeforeSeq != 0 {
+		conds = append(conds, sqlf.Sprintf("c.seq < %d", o.BeforeSeq))
+	}
+	return conds
+}
+
+// CommitIndexStore provides access to the commit_index_* tables.
+type CommitIndexStore struct {
+	*basestore.Store
+}
+
+// CommitIndex instantiates and returns a new CommitIndexStore.
+func CommitIndex(db dbutil.DB) *CommitIndexStore {
+	return &CommitIndexStore{Store: basestore.NewWithDB(db, sql.TxOptions{})}
+}
+
+// CommitIndexWith instantiates and returns a new CommitIndexStore using the
+// other store handle.
+func CommitIndexWith(other basestore.ShareableStore) *CommitIndexStore {
+	return &CommitIndexStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+func (s *CommitIndexStore) With(other basestore.ShareableStore) *CommitIndexStore {
+	return &CommitIndexStore{Store: s.Store.With(other)}
+}
+
+func (s *CommitIndexStore) Transact(ctx context.Context) (*CommitIndexStore, error) {
+	txBase, err := s.Store.Transact(ctx)
+	return &CommitIndexStore{Store: txBase}, err
+}
+
+// GetState returns the index state of the repository, or nil if it has not
+// been indexed.
+func (s *CommitIndexStore) GetState(ctx context.Context, repoID api.RepoID) (*CommitIndexState, error) {
+	q := sqlf.Sprintf(`
+SELECT repo_id, head_ref, indexed_commit, oldest_commit_date, history_complete, next_seq, indexed_at
+FROM commit_index_repos
+WHERE repo_id = %d
+`, repoID)
+
+	var st CommitIndexState
+	err := s.QueryRow(ctx, q).Scan(
+		&st.RepoID,
+		&st.HeadRef,
+		&st.IndexedCommit,
+		&dbutil.NullTime{Time: &st.OldestCommitDate},
+		&st.HistoryComplete,
+		&st.NextSeq,
+		&st.IndexedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "getting commit index state")
+	}
+	return &st, nil
+}
+
+// UpdateState creates or replaces the index state of the repository. The
+// OldestCommitDate and IndexedAt fields are computed and set on st.
+func (s *CommitIndexStore) UpdateState(ctx context.Context, st *CommitIndexState) error {
+	q := sqlf.Sprintf(`
+INSERT INTO commit_index_repos (repo_id, head_ref, indexed_commit, oldest_commit_date, history_complete, next_seq, indexed_at)
+VALUES (%d, %s, %s, (SELECT MIN(committer_date) FROM commit_index_commits WHERE repo_id = %d), %s, %d, NOW())
+ON CONFLICT (repo_id) DO UPDATE SET
+	head_ref = EXCLUDED.head_ref,
+	indexed_commit = EXCLUDED.indexed_commit,
+	oldest_commit_date = EXCLUDED.oldest_commit_date,
+	history_complete = EXCLUDED.history_complete,
+	next_seq = EXCLUDED.next_seq,
+	indexed_at = EXCLUDED.indexed_at
+RETURNING oldest_commit_date, indexed_at
+`,
+		st.RepoID,
+		st.HeadRef,
+		st.IndexedCommit,
+		st.RepoID,
+		st.HistoryComplete,
+		st.NextSeq,
+	)
+	if err := s.QueryRow(ctx, q).Scan(&dbutil.NullTime{Time: &st.OldestCommitDate}, &st.IndexedAt); err != nil {
+		return errors.Wrap(err, "updating commit index state")
+	}
+	return nil
+}
+
+// Append adds the entries to the index of the repository. The diffs of the
+// entries are stored if non-empty and at most MaxCommitIndexDiffSize bytes
+// large. Larger diffs are skipped and their commits marked as DiffTooLarge.
+func (s *CommitIndexStore) Append(ctx context.Context, repoID api.RepoID, entries []*CommitIndexEntry) (err error) {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	err = batch.WithInserter(
+		ctx,
+		tx.Handle().DB(),
+		"commit_index_commits",
+		[]string{"repo_id", "commit_id", "seq", "author_name", "author_email", "author_date", "committer_name", "committer_email", "committer_date", "message", "parents", "diff_too_large"},
+		func(inserter *batch.Inserter) error {
+			for _, e := range entries {
+				parents := e.Parents
+				if parents == nil {
+					parents = []string{}
+				}
+				if err := inserter.Insert(ctx,
+					repoID,
+					e.CommitID,
+					e.Seq,
+					e.AuthorName,
+					e.AuthorEmail,
+					e.AuthorDate,
+					e.CommitterName,
+					e.CommitterEmail,
+					e.CommitterDate,
+					e.Message,
+					pq.Array(parents),
+					len(e.Diff) > MaxCommitIndexDiffSize,
+				); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return errors.Wrap(err, "inserting indexed commits")
+	}
+
+	err = batch.WithInserter(
+		ctx,
+		tx.Handle().DB(),
+		"commit_index_diffs",
+		[]string{"repo_id", "commit_id", "diff"},
+		func(inserter *batch.Inserter) error {
+			for _, e := range entries {
+				if e.Diff == "" || len(e.Diff) > MaxCommitIndexDiffSize {
+					continue
+				}
+				if err := inserter.Insert(ctx, repoID, e.CommitID, e.Diff); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+	return errors.Wrap(err, "inserting indexed diffs")
+}
+
+// Trim deletes all but the keep newest indexed commits of the repository. It
+// returns the number of deleted commits.
+func (s *CommitIndexStore) Trim(ctx context.Context, repoID api.RepoID, keep int) (int, error) {
+	q := sqlf.Sprintf(`
+DELETE FROM commit_index_commits
+WHERE repo_id = %d AND seq < (
+	SELECT seq FROM commit_index_commits WHERE repo_id = %d ORDER BY seq DESC OFFSET %d LIMIT 1
+)
+`, repoID, repoID, keep-1)
+	res, err := s.ExecResult(ctx, q)
+	if err != nil {
+		return 0, errors.Wrap(err, "trimming commit index")
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// DeleteFromSeq deletes the indexed commits of the repository with a sequence
+// number of at least seq. These are left behind by an update of the index
+// which failed before its state was updated.
+func (s *CommitIndexStore) DeleteFromSeq(ctx context.Context, repoID api.RepoID, seq int64) error {
+	q := sqlf.Sprintf("DELETE FROM commit_index_commits WHERE repo_id = %d AND seq >= %d", repoID, seq)
+	return errors.Wrap(s.Exec(ctx, q), "deleting commits of incomplete index update")
+}
+
+// ListUnindexedRepos returns up to limit cloned repositories which have not
+// been indexed yet.
+func (s *CommitIndexStore) ListUnindexedRepos(ctx context.Context, limit int) (_ []types.RepoName, err error) {
+	q := sqlf.Sprintf(`
+SELECT repo.id, repo.name
+FROM repo
+LEFT JOIN gitserver_repos gr ON gr.repo_id = repo.id
+WHERE
+	repo.deleted_at IS NULL AND
+	(gr.clone_status = 'cloned' OR (gr.clone_status IS NULL AND repo.cloned)) AND
+	NOT EXISTS (SELECT 1 FROM commit_index_repos cir WHERE cir.repo_id = repo.id)
+ORDER BY repo.id
+LIMIT %d
+`, limit)
+
+	rows, err := s.Query(ctx, q)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing unindexed repos")
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var repos []types.RepoName
+	for rows.Next() {
+		var r types.RepoName
+		if err := rows.Scan(&r.ID, &r.Name); err != nil {
+			return nil, err
+		}
+		repos = append(repos, r)
+	}
+	return repos, nil
+}
+
+// Delete deletes the index of the repository along with its state.
+func (s *CommitIndexStore) Delete(ctx context.Context, repoID api.RepoID) (err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	if err := tx.Exec(ctx, sqlf.Sprintf("DELETE FROM commit_index_commits WHERE repo_id = %d", repoID)); err != nil {
+		return err
+	}
+	return tx.Exec(ctx, sqlf.Sprintf("DELETE FROM commit_index_repos WHERE repo_id = %d", repoID))
+}
+
+// Search returns the indexed commits of a repository which satisfy the
+// options, in `git log` order.
+func (s *CommitIndexStore) Search(ctx context.Context, opt CommitIndexSearchOptions) (_ []*CommitIndexEntry, err error) {
+	diffColumn := sqlf.Sprintf("''")
+	join := sqlf.Sprintf("")
+	if opt.WithDiffs || len(opt.DiffSubstrings) > 0 {
+		diffColumn = sqlf.Sprintf("COALESCE(d.diff, '')")
+		join = sqlf.Sprintf("LEFT JOIN commit_index_diffs d ON d.repo_id = c.repo_id AND d.commit_id = c.commit_id")
+	}
+	limit := sqlf.Sprintf("")
+	if opt.Limit > 0 {
+		limit = sqlf.Sprintf("LIMIT %d", opt.Limit)
+	}
+
+	q := sqlf.Sprintf(`
+SELECT c.commit_id, c.seq, c.author_name, c.author_email, c.author_date, c.committer_name, c.committer_email, c.committer_date, c.message, c.parents, c.diff_too_large, %s
+FROM commit_index_commits c
+%s
+WHERE %s
+ORDER BY c.seq DESC
+%s
+`, diffColumn, join, sqlf.Join(opt.sqlConditions(), "AND"), limit)
+
+	rows, err := s.Query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var entries []*CommitIndexEntry
+	for rows.Next() {
+		var e CommitIndexEntry
+		if err := rows.Scan(
+			&e.CommitID,
+			&e.Seq,
+			&e.AuthorName,
+			&e.AuthorEmail,
+			&e.AuthorDate,
+			&e.CommitterName,
+			&e.CommitterEmail,
+			&e.CommitterDate,
+			&e.Message,
+			pq.Array(&e.Parents),
+			&e.DiffTooLarge,
+			&e.Diff,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &e)
+	}
+	return entries, nil
+}
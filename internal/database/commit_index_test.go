@@ -0,0 +1,143 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestCommitIndex(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx := context.Background()
+
+	repo := &types.Repo{Name: "github.com/sourcegraph/repo", URI: "github.com/sourcegraph/repo"}
+	if err := Repos(db).Create(ctx, repo); err != nil {
+		t.Fatal(err)
+	}
+
+	store := CommitIndex(db)
+	if st, err := store.GetState(ctx, repo.ID); err != nil || st != nil {
+		t.Fatalf("got state %+v and error %v for unindexed repo, want none", st, err)
+	}
+
+	date := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	entries := []*CommitIndexEntry{
+		{CommitID: "c3", Seq: 3, AuthorName: "a", AuthorEmail: "a@a.com", AuthorDate: date.Add(2 * time.Hour), CommitterName: "a", CommitterEmail: "a@a.com", CommitterDate: date.Add(2 * time.Hour), Message: "fix 100% of bugs", Parents: []string{"c2"}, Diff: "diff --git f f\n+bugfix\n"},
+		{CommitID: "c2", Seq: 2, AuthorName: "b", AuthorEmail: "b@b.com", AuthorDate: date.Add(time.Hour), CommitterName: "b", CommitterEmail: "b@b.com", CommitterDate: date.Add(time.Hour), Message: "empty", Parents: []string{"c1"}},
+		{CommitID: "c1", Seq: 1, AuthorName: "a", AuthorEmail: "a@a.com", AuthorDate: date, CommitterName: "a", CommitterEmail: "a@a.com", CommitterDate: date, Message: "Initial commit", Parents: []string{}, Diff: "diff --git f f\n+hello\n"},
+	}
+	if err := store.Append(ctx, repo.ID, entries); err != nil {
+		t.Fatal(err)
+	}
+
+	state := &CommitIndexState{RepoID: repo.ID, HeadRef: "refs/heads/main", IndexedCommit: "c3", HistoryComplete: true, NextSeq: 4}
+	if err := store.UpdateState(ctx, state); err != nil {
+		t.Fatal(err)
+	}
+	if !state.OldestCommitDate.Equal(date) {
+		t.Errorf("got oldest commit date %s, want %s", state.OldestCommitDate, date)
+	}
+	have, err := store.GetState(ctx, repo.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(state, have, cmp.Comparer(func(a, b time.Time) bool { return a.Equal(b) })); diff != "" {
+		t.Errorf("unexpected state (-want +have):\n%s", diff)
+	}
+
+	t.Run("Search", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			opt  CommitIndexSearchOptions
+			want []api.CommitID
+		}{
+			"all":                   {opt: CommitIndexSearchOptions{}, want: []api.CommitID{"c3", "c2", "c1"}},
+			"message ignoring case": {opt: CommitIndexSearchOptions{MessageSubstrings: []string{"INITIAL"}}, want: []api.CommitID{"c1"}},
+			"escaped like pattern":  {opt: CommitIndexSearchOptions{MessageSubstrings: []string{"100%"}}, want: []api.CommitID{"c3"}},
+			"diff":                  {opt: CommitIndexSearchOptions{DiffSubstrings: []string{"hello"}}, want: []api.CommitID{"c1"}},
+			"dates":                 {opt: CommitIndexSearchOptions{After: date.Add(time.Hour), Before: date.Add(time.Hour)}, want: []api.CommitID{"c2"}},
+			"page":                  {opt: CommitIndexSearchOptions{BeforeSeq: 3, Limit: 1}, want: []api.CommitID{"c2"}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				tc.opt.RepoID = repo.ID
+				entries, err := store.Search(ctx, tc.opt)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var have []api.CommitID
+				for _, e := range entries {
+					have = append(have, e.CommitID)
+				}
+				if diff := cmp.Diff(tc.want, have); diff != "" {
+					t.Errorf("unexpected commits (-want +have):\n%s", diff)
+				}
+			})
+		}
+	})
+
+	t.Run("Search with diffs", func(t *testing.T) {
+		entries, err := store.Search(ctx, CommitIndexSearchOptions{RepoID: repo.ID, WithDiffs: true, Limit: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 {
+			t.Fatalf("got %d commits, want 1", len(entries))
+		}
+		if want := "diff --git f f\n+bugfix\n"; entries[0].Diff != want {
+			t.Errorf("got diff %q, want %q", entries[0].Diff, want)
+		}
+		if diff := cmp.Diff([]string{"c2"}, entries[0].Parents); diff != "" {
+			t.Errorf("unexpected parents (-want +have):\n%s", diff)
+		}
+	})
+
+	t.Run("Trim and Delete", func(t *testing.T) {
+		trimmed, err := store.Trim(ctx, repo.ID, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if trimmed != 1 {
+			t.Errorf("got %d trimmed commits, want 1", trimmed)
+		}
+
+		if err := store.Delete(ctx, repo.ID); err != nil {
+			t.Fatal(err)
+		}
+		if st, err := store.GetState(ctx, repo.ID); err != nil || st != nil {
+			t.Errorf("got state %+v and error %v after delete, want none", st, err)
+		}
+		entries, err := store.Search(ctx, CommitIndexSearchOptions{RepoID: repo.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 0 {
+			t.Errorf("got %d commits after delete, want none", len(entries))
+		}
+	})
+
+	t.Run("Oversized diff", func(t *testing.T) {
+		large := &CommitIndexEntry{CommitID: "c5", Seq: 5, CommitterDate: date, Message: "vendor", Diff: "diff --git f f\n+" + strings.Repeat("x", MaxCommitIndexDiffSize)}
+		if err := store.Append(ctx, repo.ID, []*CommitIndexEntry{large}); err != nil {
+			t.Fatal(err)
+		}
+
+		// The diff isn't stored, so the commit is returned for any diff search.
+		entries, err := store.Search(ctx, CommitIndexSearchOptions{RepoID: repo.ID, DiffSubstrings: []string{"no such text"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 || !entries[0].DiffTooLarge || entries[0].Diff != "" {
+			t.Fatalf("got entries %+v, want c5 without its diff", entries)
+		}
+	})
+}
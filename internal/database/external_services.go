@@ -47,6 +47,7 @@ type ExternalServiceStore struct {
 	GitLabValidators          []func(*schema.GitLabConnection, []schema.AuthProviders) error
 	BitbucketServerValidators []func(*schema.BitbucketServerConnection) error
 	PerforceValidators        []func(*schema.PerforceConnection) error
+	AzureDevOpsValidators     []func(*schema.AzureDevOpsConnection, []schema.AuthProviders) error
 
 	key encryption.Key
 
@@ -61,6 +62,7 @@ func (e *ExternalServiceStore) copy() *ExternalServiceStore {
 		GitLabValidators:          e.GitLabValidators,
 		BitbucketServerValidators: e.BitbucketServerValidators,
 		PerforceValidators:        e.PerforceValidators,
+		AzureDevOpsValidators:     e.AzureDevOpsValidators,
 	}
 }
 
@@ -119,10 +121,13 @@ func (e *ExternalServiceStore) ensureStore() {
 // external services.
 var ExternalServiceKinds = map[string]ExternalServiceKind{
 	extsvc.KindAWSCodeCommit:   {CodeHost: true, JSONSchema: schema.AWSCodeCommitSchemaJSON},
+	extsvc.KindAzureDevOps:     {CodeHost: true, JSONSchema: schema.AzureDevOpsSchemaJSON},
 	extsvc.KindBitbucketCloud:  {CodeHost: true, JSONSchema: schema.BitbucketCloudSchemaJSON},
 	extsvc.KindBitbucketServer: {CodeHost: true, JSONSchema: schema.BitbucketServerSchemaJSON},
+	extsvc.KindGerrit:          {CodeHost: true, JSONSchema: schema.GerritSchemaJSON},
 	extsvc.KindGitHub:          {CodeHost: true, JSONSchema: schema.GitHubSchemaJSON},
 	extsvc.KindGitLab:          {CodeHost: true, JSONSchema: schema.GitLabSchemaJSON},
+	extsvc.KindGitea:           {CodeHost: true, JSONSchema: schema.GiteaSchemaJSON},
 	extsvc.KindGitolite:        {CodeHost: true, JSONSchema: schema.GitoliteSchemaJSON},
 	extsvc.KindJVMPackages:     {CodeHost: true, JSONSchema: schema.JVMPackagesSchemaJSON},
 	extsvc.KindPerforce:        {CodeHost: true, JSONSchema: schema.PerforceSchemaJSON},
@@ -312,6 +317,27 @@ func (e *ExternalServiceStore) ValidateConfig(ctx context.Context, opt ValidateE
 		}
 		err = e.validateBitbucketCloudConnection(ctx, opt.ExternalServiceID, &c)
 
+	case extsvc.KindGitea:
+		var c schema.GiteaConnection
+		if err = jsoniter.Unmarshal(normalized, &c); err != nil {
+			return nil, err
+		}
+		err = e.validateDuplicateRateLimits(ctx, opt.ExternalServiceID, extsvc.KindGitea, &c)
+
+	case extsvc.KindGerrit:
+		var c schema.GerritConnection
+		if err = jsoniter.Unmarshal(normalized, &c); err != nil {
+			return nil, err
+		}
+		err = e.validateDuplicateRateLimits(ctx, opt.ExternalServiceID, extsvc.KindGerrit, &c)
+
+	case extsvc.KindAzureDevOps:
+		var c schema.AzureDevOpsConnection
+		if err = jsoniter.Unmarshal(normalized, &c); err != nil {
+			return nil, err
+		}
+		err = e.validateAzureDevOpsConnection(ctx, opt.ExternalServiceID, &c, opt.AuthProviders)
+
 	case extsvc.KindPerforce:
 		var c schema.PerforceConnection
 		if err = jsoniter.Unmarshal(normalized, &c); err != nil {
@@ -385,6 +411,21 @@ func (e *ExternalServiceStore) validateGitLabConnection(ctx context.Context, id
 	return err.ErrorOrNil()
 }
 
+func (e *ExternalServiceStore) validateAzureDevOpsConnection(ctx context.Context, id int64, c *schema.AzureDevOpsConnection, ps []schema.AuthProviders) error {
+	err := new(multierror.Error)
+	for _, validate := range e.AzureDevOpsValidators {
+		err = multierror.Append(err, validate(c, ps))
+	}
+
+	if c.Orgs == nil && c.Projects == nil {
+		err = multierror.Append(err, errors.New("at least one of orgs or projects must be set"))
+	}
+
+	err = multierror.Append(err, e.validateDuplicateRateLimits(ctx, id, extsvc.KindAzureDevOps, c))
+
+	return err.ErrorOrNil()
+}
+
 func (e *ExternalServiceStore) validateBitbucketServerConnection(ctx context.Context, id int64, c *schema.BitbucketServerConnection) error {
 	err := new(multierror.Error)
 	for _, validate := range e.BitbucketServerValidators {
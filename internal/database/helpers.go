@@ -1,6 +1,10 @@
 package database
 
-import "github.com/keegancsmith/sqlf"
+import (
+	"strings"
+
+	"github.com/keegancsmith/sqlf"
+)
 
 // LimitOffset specifies SQL LIMIT and OFFSET counts. A pointer to it is typically embedded in other options
 // structs that need to perform SQL queries with LIMIT and OFFSET.
@@ -16,3 +20,9 @@ func (o *LimitOffset) SQL() *sqlf.Query {
 	}
 	return sqlf.Sprintf("LIMIT %d OFFSET %d", o.Limit, o.Offset)
 }
+
+// escapeLikePattern escapes the characters of s which have a special meaning
+// in LIKE patterns.
+func escapeLikePattern(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -13,6 +13,10 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
+// defaultEncryptionMigrationBatchSize is the number of records locked by the encryption
+// migrators on each run. It's kept small to prevent congestion.
+const defaultEncryptionMigrationBatchSize = 50
+
 // ExternalServiceConfigMigrator is a background job that encrypts
 // external services config on startup.
 // It periodically waits until a keyring is configured to determine
@@ -28,7 +32,7 @@ type ExternalServiceConfigMigrator struct {
 
 func NewExternalServiceConfigMigrator(store *basestore.Store) *ExternalServiceConfigMigrator {
 	// not locking too many external services at a time to prevent congestion
-	return &ExternalServiceConfigMigrator{store: store, BatchSize: 50}
+	return &ExternalServiceConfigMigrator{store: store, BatchSize: defaultEncryptionMigrationBatchSize}
 }
 
 func NewExternalServiceConfigMigratorWithDB(db dbutil.DB) *ExternalServiceConfigMigrator {
@@ -41,6 +45,16 @@ func (m *ExternalServiceConfigMigrator) ID() int {
 	return 3
 }
 
+// SetBatchSize changes the number of external services migrated on each call to Up/Down.
+// A non-positive value restores the default batch size.
+func (m *ExternalServiceConfigMigrator) SetBatchSize(batchSize int) {
+	if batchSize <= 0 {
+		batchSize = defaultEncryptionMigrationBatchSize
+	}
+
+	m.BatchSize = batchSize
+}
+
 // Progress returns a value from 0 to 1 representing the percentage of configuration already migrated.
 func (m *ExternalServiceConfigMigrator) Progress(ctx context.Context) (float64, error) {
 	progress, _, err := basestore.ScanFirstFloat(m.store.Query(ctx, sqlf.Sprintf(`
@@ -200,7 +214,7 @@ type ExternalAccountsMigrator struct {
 
 func NewExternalAccountsMigrator(store *basestore.Store) *ExternalAccountsMigrator {
 	// not locking too many external accounts at a time to prevent congestion
-	return &ExternalAccountsMigrator{store: store, BatchSize: 50}
+	return &ExternalAccountsMigrator{store: store, BatchSize: defaultEncryptionMigrationBatchSize}
 }
 
 func NewExternalAccountsMigratorWithDB(db dbutil.DB) *ExternalAccountsMigrator {
@@ -213,6 +227,16 @@ func (m *ExternalAccountsMigrator) ID() int {
 	return 6
 }
 
+// SetBatchSize changes the number of external accounts migrated on each call to Up/Down.
+// A non-positive value restores the default batch size.
+func (m *ExternalAccountsMigrator) SetBatchSize(batchSize int) {
+	if batchSize <= 0 {
+		batchSize = defaultEncryptionMigrationBatchSize
+	}
+
+	m.BatchSize = batchSize
+}
+
 // Progress returns a value from 0 to 1 representing the percentage of configuration already migrated.
 func (m *ExternalAccountsMigrator) Progress(ctx context.Context) (float64, error) {
 	progress, _, err := basestore.ScanFirstFloat(m.store.Query(ctx, sqlf.Sprintf(`
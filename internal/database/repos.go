@@ -23,8 +23,11 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database/query"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
@@ -356,6 +359,12 @@ func scanRepo(rows *sql.Rows, r *types.Repo) (err error) {
 		r.Metadata = new(bitbucketserver.Repo)
 	case extsvc.TypeBitbucketCloud:
 		r.Metadata = new(bitbucketcloud.Repo)
+	case extsvc.TypeGitea:
+		r.Metadata = new(gitea.Repo)
+	case extsvc.TypeGerrit:
+		r.Metadata = new(gerrit.Project)
+	case extsvc.TypeAzureDevOps:
+		r.Metadata = new(azuredevops.Repository)
 	case extsvc.TypeAWSCodeCommit:
 		r.Metadata = new(awscodecommit.Repository)
 	case extsvc.TypeGitolite:
@@ -391,6 +400,11 @@ type ReposListOptions struct {
 	// Direction options are ignored
 	Query string
 
+	// SimilarTo, if non-empty, limits the results to repositories whose names
+	// contain a part which is similar to it, by trigram similarity. The
+	// results are ordered by similarity before OrderBy.
+	SimilarTo string
+
 	// IncludePatterns is a list of regular expressions, all of which must match all
 	// repositories returned in the list.
 	IncludePatterns []string
@@ -665,6 +679,11 @@ func (s *RepoStore) listSQL(ctx context.Context, opt ReposListOptions) (*sqlf.Qu
 		where = append(where, sqlf.Sprintf("lower(name) LIKE %s", "%"+strings.ToLower(opt.Query)+"%"))
 	}
 
+	if opt.SimilarTo != "" {
+		// Uses the trigram index on lower(name).
+		where = append(where, sqlf.Sprintf("%s <%%%% lower(name)", strings.ToLower(opt.SimilarTo)))
+	}
+
 	for _, includePattern := range opt.IncludePatterns {
 		extraConds, err := parsePattern(includePattern)
 		if err != nil {
@@ -835,6 +854,13 @@ func (s *RepoStore) listSQL(ctx context.Context, opt ReposListOptions) (*sqlf.Qu
 	}
 
 	querySuffix := sqlf.Sprintf("%s %s", opt.OrderBy.SQL(), opt.LimitOffset.SQL())
+	if opt.SimilarTo != "" {
+		orderBy := []*sqlf.Query{sqlf.Sprintf("word_similarity(%s, lower(name)) DESC", strings.ToLower(opt.SimilarTo))}
+		for _, s := range opt.OrderBy {
+			orderBy = append(orderBy, s.SQL())
+		}
+		querySuffix = sqlf.Sprintf("ORDER BY %s %s", sqlf.Join(orderBy, ", "), opt.LimitOffset.SQL())
+	}
 
 	columns := repoColumns
 	if len(opt.Select) > 0 {
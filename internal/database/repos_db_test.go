@@ -1475,6 +1475,42 @@ func TestRepos_ListRepoNames_query2(t *testing.T) {
 	}
 }
 
+func TestRepos_ListRepoNames_similarTo(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx := actor.WithInternalActor(context.Background())
+
+	createdRepos := []*types.Repo{
+		{Name: "github.com/sourcegraph/sourcegraph"},
+		{Name: "github.com/golang/go"},
+		{Name: "github.com/sourcegraph/zoekt"},
+	}
+	for _, repo := range createdRepos {
+		createRepo(ctx, t, db, repo)
+	}
+	tests := []struct {
+		similarTo string
+		want      []api.RepoName
+	}{
+		{"sourcegraf", []api.RepoName{"github.com/sourcegraph/sourcegraph", "github.com/sourcegraph/zoekt"}},
+		{"Golan", []api.RepoName{"github.com/golang/go"}},
+		{"kubernetes", nil},
+	}
+	for _, test := range tests {
+		repos, err := Repos(db).ListRepoNames(ctx, ReposListOptions{SimilarTo: test.similarTo})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := repoNames(reposFromRepoNames(repos)); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Unexpected repo result for %q:\ngot:  %q\nwant: %q", test.similarTo, got, test.want)
+		}
+	}
+}
+
 // Test sort
 func TestRepos_ListRepoNames_sort(t *testing.T) {
 	if testing.Short() {
@@ -0,0 +1,290 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// SearchQueryHistoryEntry is a search query run by a user, along with the
+// outcome of the search.
+type SearchQueryHistoryEntry struct {
+	ID          int64
+	UserID      int32
+	Query       string
+	PatternType string
+	// Status is the status of the search as reported in logs, one of
+	// "success", "alert", "partial_timeout", "timeout" or "error".
+	Status string
+	// AlertType and AlertTitle describe the alert shown for the search, if
+	// any.
+	AlertType   string
+	AlertTitle  string
+	ResultCount int32
+	DurationMs  int32
+	CreatedAt   time.Time
+}
+
+// SearchQueryHistoryListOptions specifies the options for listing a user's
+// search query history.
+type SearchQueryHistoryListOptions struct {
+	// UserID is the user whose history is listed. It is required.
+	UserID int32
+	// Query, if non-empty, only lists entries whose query contains it,
+	// ignoring case.
+	Query string
+	*LimitOffset
+}
+
+func (o SearchQueryHistoryListOptions) sqlConditions() []*sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("user_id = %d", o.UserID)}
+	if o.Query != "" {
+		conds = append(conds, sqlf.Sprintf("query ILIKE %s", "%"+escapeLikePattern(o.Query)+"%"))
+	}
+	return conds
+}
+
+// SearchQueryStatistic aggregates all the runs of a search query by any user.
+type SearchQueryStatistic struct {
+	Query             string
+	PatternType       string
+	Count             int32
+	AverageDurationMs int32
+	MaxDurationMs     int32
+	// TimeoutCount is the number of runs which timed out in some or all
+	// repositories.
+	TimeoutCount int32
+	ErrorCount   int32
+	AlertCount   int32
+	// LatestAlertType and LatestAlertTitle describe the alert of the most
+	// recent run which had one.
+	LatestAlertType  string
+	LatestAlertTitle string
+	LastRunAt        time.Time
+}
+
+// SearchQueryStatisticsOrder is the order in which search query statistics
+// are listed.
+type SearchQueryStatisticsOrder string
+
+const (
+	// SearchQueryStatisticsOrderSlowest lists the queries with the highest
+	// average duration first.
+	SearchQueryStatisticsOrderSlowest SearchQueryStatisticsOrder = "SLOWEST"
+	// SearchQueryStatisticsOrderMostFailing lists the queries which timed out
+	// or failed most often first.
+	SearchQueryStatisticsOrderMostFailing SearchQueryStatisticsOrder = "MOST_FAILING"
+)
+
+// SearchQueryStatisticsOptions specifies the options for listing search query
+// statistics.
+type SearchQueryStatisticsOptions struct {
+	// Since only takes into account queries run after it, if non-zero.
+	Since   time.Time
+	OrderBy SearchQueryStatisticsOrder
+	*LimitOffset
+}
+
+func (o SearchQueryStatisticsOptions) sqlConditions() []*sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if !o.Since.IsZero() {
+		conds = append(conds, sqlf.Sprintf("created_at >= %s", o.Since))
+	}
+	return conds
+}
+
+// SearchQueryHistoryStore provides access to the search_query_history table.
+type SearchQueryHistoryStore struct {
+	*basestore.Store
+}
+
+// SearchQueryHistory instantiates and returns a new SearchQueryHistoryStore.
+func SearchQueryHistory(db dbutil.DB) *SearchQueryHistoryStore {
+	return &SearchQueryHistoryStore{Store: basestore.NewWithDB(db, sql.TxOptions{})}
+}
+
+// SearchQueryHistoryWith instantiates and returns a new SearchQueryHistoryStore
+// using the other store handle.
+func SearchQueryHistoryWith(other basestore.ShareableStore) *SearchQueryHistoryStore {
+	return &SearchQueryHistoryStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+func (s *SearchQueryHistoryStore) With(other basestore.ShareableStore) *SearchQueryHistoryStore {
+	return &SearchQueryHistoryStore{Store: s.Store.With(other)}
+}
+
+func (s *SearchQueryHistoryStore) Transact(ctx context.Context) (*SearchQueryHistoryStore, error) {
+	txBase, err := s.Store.Transact(ctx)
+	return &SearchQueryHistoryStore{Store: txBase}, err
+}
+
+// Create records the given entry and sets its ID and CreatedAt fields.
+func (s *SearchQueryHistoryStore) Create(ctx context.Context, e *SearchQueryHistoryEntry) error {
+	q := sqlf.Sprintf(`
+INSERT INTO search_query_history (user_id, query, pattern_type, status, alert_type, alert_title, result_count, duration_ms)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s)
+RETURNING id, created_at
+`,
+		e.UserID,
+		e.Query,
+		e.PatternType,
+		e.Status,
+		dbutil.NewNullString(e.AlertType),
+		dbutil.NewNullString(e.AlertTitle),
+		e.ResultCount,
+		e.DurationMs,
+	)
+	if err := s.QueryRow(ctx, q).Scan(&e.ID, &e.CreatedAt); err != nil {
+		return errors.Wrap(err, "inserting search query history entry")
+	}
+	return nil
+}
+
+// List returns the entries of a user's search query history, most recent
+// first.
+func (s *SearchQueryHistoryStore) List(ctx context.Context, opt SearchQueryHistoryListOptions) (_ []*SearchQueryHistoryEntry, err error) {
+	q := sqlf.Sprintf(`
+SELECT id, user_id, query, pattern_type, status, alert_type, alert_title, result_count, duration_ms, created_at
+FROM search_query_history
+WHERE %s
+ORDER BY created_at DESC, id DESC
+%s
+`, sqlf.Join(opt.sqlConditions(), "AND"), opt.LimitOffset.SQL())
+
+	rows, err := s.Query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var entries []*SearchQueryHistoryEntry
+	for rows.Next() {
+		var e SearchQueryHistoryEntry
+		if err := rows.Scan(
+			&e.ID,
+			&e.UserID,
+			&e.Query,
+			&e.PatternType,
+			&e.Status,
+			&dbutil.NullString{S: &e.AlertType},
+			&dbutil.NullString{S: &e.AlertTitle},
+			&e.ResultCount,
+			&e.DurationMs,
+			&e.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &e)
+	}
+	return entries, nil
+}
+
+// Count counts the entries of a user's search query history.
+func (s *SearchQueryHistoryStore) Count(ctx context.Context, opt SearchQueryHistoryListOptions) (int, error) {
+	q := sqlf.Sprintf("SELECT COUNT(*) FROM search_query_history WHERE %s", sqlf.Join(opt.sqlConditions(), "AND"))
+	count, _, err := basestore.ScanFirstInt(s.Query(ctx, q))
+	return count, err
+}
+
+// Delete deletes the given entries of a user's search query history. Entries
+// belonging to other users are left untouched.
+func (s *SearchQueryHistoryStore) Delete(ctx context.Context, userID int32, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	preds := make([]*sqlf.Query, 0, len(ids))
+	for _, id := range ids {
+		preds = append(preds, sqlf.Sprintf("%d", id))
+	}
+	q := sqlf.Sprintf(
+		"DELETE FROM search_query_history WHERE user_id = %d AND id IN (%s)",
+		userID,
+		sqlf.Join(preds, ","),
+	)
+	return s.Exec(ctx, q)
+}
+
+// DeleteAll deletes the whole search query history of a user.
+func (s *SearchQueryHistoryStore) DeleteAll(ctx context.Context, userID int32) error {
+	return s.Exec(ctx, sqlf.Sprintf("DELETE FROM search_query_history WHERE user_id = %d", userID))
+}
+
+const searchQueryStatisticsFmtStr = `
+SELECT
+	query,
+	pattern_type,
+	COUNT(*),
+	AVG(duration_ms)::integer,
+	MAX(duration_ms),
+	COUNT(*) FILTER (WHERE status IN ('timeout', 'partial_timeout')),
+	COUNT(*) FILTER (WHERE status = 'error'),
+	COUNT(*) FILTER (WHERE alert_title IS NOT NULL),
+	(ARRAY_AGG(alert_type ORDER BY created_at DESC) FILTER (WHERE alert_title IS NOT NULL))[1],
+	(ARRAY_AGG(alert_title ORDER BY created_at DESC) FILTER (WHERE alert_title IS NOT NULL))[1],
+	MAX(created_at)
+FROM search_query_history
+WHERE %s
+GROUP BY query, pattern_type
+ORDER BY %s
+%s
+`
+
+// Statistics returns the search queries run by all users, aggregated per
+// query and pattern type.
+func (s *SearchQueryHistoryStore) Statistics(ctx context.Context, opt SearchQueryStatisticsOptions) (_ []*SearchQueryStatistic, err error) {
+	var orderBy *sqlf.Query
+	switch opt.OrderBy {
+	case SearchQueryStatisticsOrderMostFailing:
+		orderBy = sqlf.Sprintf("COUNT(*) FILTER (WHERE status IN ('timeout', 'partial_timeout', 'error')) DESC, COUNT(*) DESC, query ASC")
+	case SearchQueryStatisticsOrderSlowest, "":
+		orderBy = sqlf.Sprintf("AVG(duration_ms) DESC, query ASC")
+	default:
+		return nil, errors.Errorf("invalid search query statistics order %q", opt.OrderBy)
+	}
+
+	q := sqlf.Sprintf(searchQueryStatisticsFmtStr, sqlf.Join(opt.sqlConditions(), "AND"), orderBy, opt.LimitOffset.SQL())
+
+	rows, err := s.Query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var stats []*SearchQueryStatistic
+	for rows.Next() {
+		var st SearchQueryStatistic
+		if err := rows.Scan(
+			&st.Query,
+			&st.PatternType,
+			&st.Count,
+			&st.AverageDurationMs,
+			&st.MaxDurationMs,
+			&st.TimeoutCount,
+			&st.ErrorCount,
+			&st.AlertCount,
+			&dbutil.NullString{S: &st.LatestAlertType},
+			&dbutil.NullString{S: &st.LatestAlertTitle},
+			&st.LastRunAt,
+		); err != nil {
+			return nil, err
+		}
+		stats = append(stats, &st)
+	}
+	return stats, nil
+}
+
+// CountStatistics counts the distinct search queries returned by Statistics.
+func (s *SearchQueryHistoryStore) CountStatistics(ctx context.Context, opt SearchQueryStatisticsOptions) (int, error) {
+	q := sqlf.Sprintf(
+		"SELECT COUNT(*) FROM (SELECT 1 FROM search_query_history WHERE %s GROUP BY query, pattern_type) AS q",
+		sqlf.Join(opt.sqlConditions(), "AND"),
+	)
+	count, _, err := basestore.ScanFirstInt(s.Query(ctx, q))
+	return count, err
+}
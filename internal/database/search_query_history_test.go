@@ -0,0 +1,132 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+)
+
+func TestSearchQueryHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx := context.Background()
+
+	var userIDs []int32
+	for _, username := range []string{"u1", "u2"} {
+		u, err := Users(db).Create(ctx, NewUser{Username: username})
+		if err != nil {
+			t.Fatal(err)
+		}
+		userIDs = append(userIDs, u.ID)
+	}
+	u1, u2 := userIDs[0], userIDs[1]
+
+	store := SearchQueryHistory(db)
+	entries := []*SearchQueryHistoryEntry{
+		{UserID: u1, Query: "foo", PatternType: "literal", Status: "success", ResultCount: 3, DurationMs: 100},
+		{UserID: u1, Query: "bar", PatternType: "literal", Status: "timeout", AlertType: "timed_out", AlertTitle: "Timed out", DurationMs: 1000},
+		{UserID: u2, Query: "bar", PatternType: "literal", Status: "partial_timeout", ResultCount: 1, DurationMs: 800},
+		{UserID: u2, Query: "baz", PatternType: "regexp", Status: "error", DurationMs: 10},
+	}
+	for _, e := range entries {
+		if err := store.Create(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+		if e.ID == 0 || e.CreatedAt.IsZero() {
+			t.Fatalf("entry not populated after create: %+v", e)
+		}
+	}
+
+	t.Run("List", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			opt  SearchQueryHistoryListOptions
+			want []*SearchQueryHistoryEntry
+		}{
+			"all of a user":   {opt: SearchQueryHistoryListOptions{UserID: u1}, want: []*SearchQueryHistoryEntry{entries[1], entries[0]}},
+			"query filter":    {opt: SearchQueryHistoryListOptions{UserID: u1, Query: "FO"}, want: []*SearchQueryHistoryEntry{entries[0]}},
+			"limit":           {opt: SearchQueryHistoryListOptions{UserID: u2, LimitOffset: &LimitOffset{Limit: 1}}, want: []*SearchQueryHistoryEntry{entries[3]}},
+			"no such entries": {opt: SearchQueryHistoryListOptions{UserID: u1, Query: "baz"}},
+			"wildcards":       {opt: SearchQueryHistoryListOptions{UserID: u1, Query: "f_o"}},
+			"percent sign":    {opt: SearchQueryHistoryListOptions{UserID: u1, Query: "%"}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				have, err := store.List(ctx, tc.opt)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(tc.want, have); diff != "" {
+					t.Errorf("unexpected entries (-want +have):\n%s", diff)
+				}
+			})
+		}
+	})
+
+	t.Run("Statistics", func(t *testing.T) {
+		have, err := store.Statistics(ctx, SearchQueryStatisticsOptions{OrderBy: SearchQueryStatisticsOrderMostFailing})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(have) != 3 {
+			t.Fatalf("unexpected number of statistics: %d", len(have))
+		}
+		bar := have[0]
+		if bar.Query != "bar" || bar.Count != 2 || bar.TimeoutCount != 2 || bar.MaxDurationMs != 1000 || bar.AverageDurationMs != 900 {
+			t.Errorf("unexpected statistic for bar: %+v", bar)
+		}
+		if bar.AlertCount != 1 || bar.LatestAlertType != "timed_out" || bar.LatestAlertTitle != "Timed out" {
+			t.Errorf("unexpected alert for bar: %+v", bar)
+		}
+
+		have, err = store.Statistics(ctx, SearchQueryStatisticsOptions{OrderBy: SearchQueryStatisticsOrderSlowest})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var queries []string
+		for _, st := range have {
+			queries = append(queries, st.Query)
+		}
+		if diff := cmp.Diff([]string{"bar", "foo", "baz"}, queries); diff != "" {
+			t.Errorf("unexpected order (-want +have):\n%s", diff)
+		}
+
+		count, err := store.CountStatistics(ctx, SearchQueryStatisticsOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 3 {
+			t.Errorf("unexpected count: %d", count)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		// Deleting another user's entry is a noop.
+		if err := store.Delete(ctx, u1, []int64{entries[0].ID, entries[2].ID}); err != nil {
+			t.Fatal(err)
+		}
+		if count, err := store.Count(ctx, SearchQueryHistoryListOptions{UserID: u1}); err != nil {
+			t.Fatal(err)
+		} else if count != 1 {
+			t.Errorf("unexpected count for u1: %d", count)
+		}
+		if count, err := store.Count(ctx, SearchQueryHistoryListOptions{UserID: u2}); err != nil {
+			t.Fatal(err)
+		} else if count != 2 {
+			t.Errorf("unexpected count for u2: %d", count)
+		}
+
+		if err := store.DeleteAll(ctx, u2); err != nil {
+			t.Fatal(err)
+		}
+		if count, err := store.Count(ctx, SearchQueryHistoryListOptions{UserID: u2}); err != nil {
+			t.Fatal(err)
+		} else if count != 0 {
+			t.Errorf("unexpected count for u2: %d", count)
+		}
+	})
+}
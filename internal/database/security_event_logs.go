@@ -38,6 +38,8 @@ const (
 
 	SecurityEventNameRoleChangeDenied  SecurityEventName = "RoleChangeDenied"
 	SecurityEventNameRoleChangeGranted SecurityEventName = "RoleChangeGranted"
+
+	SecurityEventNameAccessTokenCreated SecurityEventName = "AccessTokenCreated"
 )
 
 // SecurityEvent contains information needed for logging a security-relevant event.
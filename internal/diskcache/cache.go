@@ -16,6 +16,7 @@ import (
 	"github.com/cockroachdb/errors"
 	"github.com/opentracing/opentracing-go/ext"
 
+	"github.com/sourcegraph/sourcegraph/internal/requestid"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 )
 
@@ -121,7 +122,9 @@ func (s *Store) OpenWithPath(ctx context.Context, key string, fetcher FetcherWit
 	go func(ctx context.Context) {
 		if s.BackgroundTimeout != 0 {
 			var cancel context.CancelFunc
-			ctx, cancel = context.WithTimeout(context.Background(), s.BackgroundTimeout)
+			// The fetch outlives the request, but the work is still done on its behalf.
+			bgCtx := requestid.WithRequestID(context.Background(), requestid.FromContext(ctx))
+			ctx, cancel = context.WithTimeout(bgCtx, s.BackgroundTimeout)
 			defer cancel()
 		}
 		f, err := doFetch(ctx, path, fetcher)
@@ -0,0 +1,497 @@
+package azuredevops
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+	"github.com/opentracing-contrib/go-stdlib/nethttp"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+)
+
+var requestCounter = metrics.NewRequestMeter("azuredevops_requests_count", "Total number of requests sent to the Azure DevOps API.")
+
+// These fields define the self-imposed Azure DevOps rate limit. Azure DevOps
+// only reports rate limits once a client is already being throttled, so we use
+// the same defaults as for Bitbucket Cloud.
+//
+// See https://godoc.org/golang.org/x/time/rate#Limiter for an explanation of these fields.
+const (
+	rateLimitRequestsPerSecond = 2 // 120/min or 7200/hr
+	RateLimitMaxBurstRequests  = 500
+)
+
+// apiVersion is the version of the REST API requested by the client. 6.0 is
+// supported by Azure DevOps Services and Azure DevOps Server 2020.
+const apiVersion = "6.0"
+
+// Client access Azure DevOps Services or Azure DevOps Server via the REST API.
+type Client struct {
+	// HTTP Client used to communicate with the API
+	httpClient httpcli.Doer
+
+	// URL is the base URL of Azure DevOps, without an organization or
+	// collection, such as https://dev.azure.com/.
+	URL *url.URL
+
+	// Auth is the authenticator used to authenticate requests. Personal access
+	// tokens are usually passed as *auth.BasicAuth with an empty username.
+	Auth auth.Authenticator
+
+	// RateLimit is the self-imposed rate limiter.
+	RateLimit *rate.Limiter
+}
+
+// NewClient creates a new Azure DevOps API client for the instance at baseURL.
+// If a nil httpClient is provided, http.DefaultClient will be used. The Auth
+// field should be set before calling any APIs.
+func NewClient(baseURL *url.URL, httpClient httpcli.Doer) *Client {
+	if httpClient == nil {
+		httpClient = httpcli.ExternalDoer()
+	}
+
+	httpClient = requestCounter.Doer(httpClient, func(u *url.URL) string {
+		// The component following _apis (/<org>/[<project>/]_apis/<category>/...)
+		// maps to the type of API request we are making.
+		parts := strings.Split(u.Path, "/")
+		for i, p := range parts {
+			if p == "_apis" && i+1 < len(parts) {
+				return parts[i+1]
+			}
+		}
+		return ""
+	})
+
+	// Normally our registry will return a default infinite limiter when nothing has been
+	// synced from config. However, we always want to ensure there is at least some form of rate
+	// limiting for Azure DevOps.
+	defaultLimiter := rate.NewLimiter(rateLimitRequestsPerSecond, RateLimitMaxBurstRequests)
+	l := ratelimit.DefaultRegistry.GetOrSet(baseURL.String(), defaultLimiter)
+
+	// Azure DevOps Server is commonly served from a sub-path (e.g.
+	// https://example.com/tfs/), so API paths are resolved relative to a base
+	// URL ending in a slash.
+	if !strings.HasSuffix(baseURL.Path, "/") {
+		u := *baseURL
+		u.Path += "/"
+		baseURL = &u
+	}
+
+	return &Client{
+		httpClient: httpClient,
+		URL:        baseURL,
+		RateLimit:  l,
+	}
+}
+
+// WithAuthenticator returns a new Client that uses the same configuration,
+// HTTPClient, and RateLimiter as the current Client, except authenticated with
+// the given authenticator instance.
+func (c *Client) WithAuthenticator(a auth.Authenticator) *Client {
+	return &Client{
+		httpClient: c.httpClient,
+		URL:        c.URL,
+		RateLimit:  c.RateLimit,
+		Auth:       a,
+	}
+}
+
+// ListRepositories returns the Git repositories of the given organization. If
+// project is not empty, only the repositories of that project are returned.
+func (c *Client) ListRepositories(ctx context.Context, org, project string) ([]*Repository, error) {
+	path := org + "/_apis/git/repositories"
+	if project != "" {
+		path = org + "/" + project + "/_apis/git/repositories"
+	}
+
+	var resp struct {
+		Value []*Repository `json:"value"`
+	}
+	if err := c.send(ctx, "GET", c.URL, path, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	for _, r := range resp.Value {
+		r.Organization = org
+	}
+	return resp.Value, nil
+}
+
+// AuthenticatedUser returns the identity the client is authenticated as in the
+// given organization.
+func (c *Client) AuthenticatedUser(ctx context.Context, org string) (*Identity, error) {
+	var resp struct {
+		AuthenticatedUser *Identity `json:"authenticatedUser"`
+	}
+	// connectionData is only available as a preview API.
+	qry := url.Values{"api-version": []string{apiVersion + "-preview"}}
+	if err := c.send(ctx, "GET", c.URL, org+"/_apis/connectionData", qry, nil, &resp); err != nil {
+		return nil, err
+	}
+	if resp.AuthenticatedUser == nil {
+		return nil, errors.New("no authenticated user returned")
+	}
+	return resp.AuthenticatedUser, nil
+}
+
+// FindIdentity returns the active identity in the given organization whose
+// account name or email address matches principal, which is usually the user
+// principal name of an Azure Active Directory user. If no identity matches,
+// nil is returned.
+func (c *Client) FindIdentity(ctx context.Context, org, principal string) (*Identity, error) {
+	qry := url.Values{}
+	qry.Set("searchFilter", "General")
+	qry.Set("filterValue", principal)
+	qry.Set("queryMembership", "None")
+
+	var resp struct {
+		Value []*Identity `json:"value"`
+	}
+	if err := c.send(ctx, "GET", c.identityURL(), org+"/_apis/identities", qry, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	for _, id := range resp.Value {
+		if !id.IsActive {
+			continue
+		}
+		if strings.EqualFold(id.Properties.Account.Value, principal) || strings.EqualFold(id.Properties.Mail.Value, principal) {
+			return id, nil
+		}
+	}
+	return nil, nil
+}
+
+// identityURL returns the base URL of the identity service. Azure DevOps
+// Services hosts it on a separate domain, Azure DevOps Server serves it
+// alongside all other APIs.
+func (c *Client) identityURL() *url.URL {
+	if strings.EqualFold(c.URL.Hostname(), "dev.azure.com") {
+		u := *c.URL
+		u.Host = "vssps.dev.azure.com"
+		return &u
+	}
+	return c.URL
+}
+
+// ListAccessControlLists returns the access control lists of the given
+// security namespace for the given token and all tokens below it, restricted
+// to the access control entries of the given identity descriptor. The
+// entries include the effective permissions of the identity, taking group
+// memberships and inheritance into account.
+func (c *Client) ListAccessControlLists(ctx context.Context, org, namespaceID, token, descriptor string) ([]*AccessControlList, error) {
+	qry := url.Values{}
+	qry.Set("token", token)
+	qry.Set("descriptors", descriptor)
+	qry.Set("includeExtendedInfo", "true")
+	qry.Set("recurse", "true")
+
+	var resp struct {
+		Value []*AccessControlList `json:"value"`
+	}
+	if err := c.send(ctx, "GET", c.URL, org+"/_apis/accesscontrollists/"+namespaceID, qry, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Value, nil
+}
+
+// ErrPullRequestAlreadyExists is returned by CreatePullRequest when an active
+// pull request for the same source and target branches already exists.
+type ErrPullRequestAlreadyExists struct {
+	Existing *PullRequest
+}
+
+func (e ErrPullRequestAlreadyExists) Error() string {
+	return "An active pull request with the given source and target branches already exists"
+}
+
+// ErrNotMergeable is returned by CompletePullRequest when the pull request
+// cannot be completed, for example because of conflicts or branch policies.
+var ErrNotMergeable = errors.New("pull request cannot be merged")
+
+// CreatePullRequest creates a pull request in the given repository.
+func (c *Client) CreatePullRequest(ctx context.Context, repo *Repository, input *CreatePullRequestInput) (*PullRequest, error) {
+	var pr PullRequest
+	err := c.send(ctx, "POST", c.URL, repositoryPath(repo)+"/pullrequests", nil, input, &pr)
+	if err != nil {
+		var e *httpError
+		if !errors.As(err, &e) || e.StatusCode != http.StatusConflict {
+			return nil, err
+		}
+
+		existing, err := c.findActivePullRequest(ctx, repo, input.SourceRefName, input.TargetRefName)
+		if err != nil {
+			return nil, errors.Wrap(err, "loading existing pull request")
+		}
+		if existing == nil {
+			return nil, e
+		}
+		return nil, ErrPullRequestAlreadyExists{Existing: existing}
+	}
+
+	setRepository(&pr, repo)
+	return &pr, nil
+}
+
+func (c *Client) findActivePullRequest(ctx context.Context, repo *Repository, sourceRef, targetRef string) (*PullRequest, error) {
+	qry := url.Values{}
+	qry.Set("searchCriteria.sourceRefName", sourceRef)
+	qry.Set("searchCriteria.targetRefName", targetRef)
+	qry.Set("searchCriteria.status", string(PullRequestStatusActive))
+
+	var resp struct {
+		Value []*PullRequest `json:"value"`
+	}
+	if err := c.send(ctx, "GET", c.URL, repositoryPath(repo)+"/pullrequests", qry, nil, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Value) == 0 {
+		return nil, nil
+	}
+
+	pr := resp.Value[0]
+	setRepository(pr, repo)
+	return pr, nil
+}
+
+// GetPullRequest returns the pull request with the given ID in the given
+// repository.
+func (c *Client) GetPullRequest(ctx context.Context, repo *Repository, id int) (*PullRequest, error) {
+	var pr PullRequest
+	if err := c.send(ctx, "GET", c.URL, pullRequestPath(repo, id), nil, nil, &pr); err != nil {
+		return nil, err
+	}
+
+	setRepository(&pr, repo)
+	return &pr, nil
+}
+
+// UpdatePullRequest updates the pull request with the given ID in the given
+// repository. Only the non-empty fields of input are changed.
+func (c *Client) UpdatePullRequest(ctx context.Context, repo *Repository, id int, input *UpdatePullRequestInput) (*PullRequest, error) {
+	var pr PullRequest
+	if err := c.send(ctx, "PATCH", c.URL, pullRequestPath(repo, id), nil, input, &pr); err != nil {
+		return nil, err
+	}
+
+	setRepository(&pr, repo)
+	return &pr, nil
+}
+
+// CompletePullRequest completes (merges) the given pull request. If squash is
+// true, the changes are squashed into a single commit. Completion happens
+// asynchronously on Azure DevOps, so the returned pull request may still be
+// active.
+func (c *Client) CompletePullRequest(ctx context.Context, repo *Repository, pr *PullRequest, squash bool) (*PullRequest, error) {
+	switch pr.MergeStatus {
+	case MergeStatusConflicts, MergeStatusRejectedByPolicy, MergeStatusFailure:
+		return nil, errors.Wrapf(ErrNotMergeable, "merge status is %q", pr.MergeStatus)
+	}
+
+	strategy := MergeStrategyNoFastForward
+	if squash {
+		strategy = MergeStrategySquash
+	}
+
+	updated, err := c.UpdatePullRequest(ctx, repo, pr.ID, &UpdatePullRequestInput{
+		Status:                PullRequestStatusCompleted,
+		LastMergeSourceCommit: pr.LastMergeSourceCommit,
+		CompletionOptions:     &CompletionOptions{MergeStrategy: strategy},
+	})
+	if err != nil {
+		var e *httpError
+		if errors.As(err, &e) && (e.StatusCode == http.StatusConflict || e.StatusCode == http.StatusBadRequest) {
+			return nil, errors.Wrap(ErrNotMergeable, err.Error())
+		}
+		return nil, err
+	}
+	return updated, nil
+}
+
+// ListPullRequestStatuses returns the statuses posted to the given pull
+// request, such as the results of build validation policies.
+func (c *Client) ListPullRequestStatuses(ctx context.Context, repo *Repository, id int) ([]*GitStatus, error) {
+	var resp struct {
+		Value []*GitStatus `json:"value"`
+	}
+	// Pull request statuses are only available as a preview API.
+	qry := url.Values{"api-version": []string{apiVersion + "-preview.1"}}
+	if err := c.send(ctx, "GET", c.URL, pullRequestPath(repo, id)+"/statuses", qry, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Value, nil
+}
+
+// CreatePullRequestComment starts a new comment thread on the given pull
+// request.
+func (c *Client) CreatePullRequestComment(ctx context.Context, repo *Repository, id int, text string) error {
+	thread := map[string]interface{}{
+		"comments": []map[string]interface{}{
+			{"parentCommentId": 0, "content": text, "commentType": "text"},
+		},
+		"status": "active",
+	}
+	return c.send(ctx, "POST", c.URL, pullRequestPath(repo, id)+"/threads", nil, thread, nil)
+}
+
+func repositoryPath(repo *Repository) string {
+	return repo.Organization + "/" + repo.Project.ID + "/_apis/git/repositories/" + repo.ID
+}
+
+func pullRequestPath(repo *Repository, id int) string {
+	return repositoryPath(repo) + "/pullrequests/" + strconv.Itoa(id)
+}
+
+// setRepository fills in the fields of the pull request's repository that
+// the pull request APIs don't return.
+func setRepository(pr *PullRequest, repo *Repository) {
+	if pr.Repository.Organization == "" {
+		pr.Repository.Organization = repo.Organization
+	}
+	if pr.Repository.WebURL == "" {
+		pr.Repository.WebURL = repo.WebURL
+	}
+}
+
+func (c *Client) send(ctx context.Context, method string, base *url.URL, path string, qry url.Values, payload, result interface{}) error {
+	if qry == nil {
+		qry = make(url.Values)
+	}
+	if qry.Get("api-version") == "" {
+		qry.Set("api-version", apiVersion)
+	}
+
+	var body io.Reader
+	if payload != nil {
+		bs, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(bs)
+	}
+
+	u := url.URL{Path: path, RawQuery: qry.Encode()}
+	req, err := http.NewRequest(method, base.ResolveReference(&u).String(), body)
+	if err != nil {
+		return err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return c.do(ctx, req, result)
+}
+
+func (c *Client) do(ctx context.Context, req *http.Request, result interface{}) error {
+	req.Header.Set("Accept", "application/json")
+
+	req, ht := nethttp.TraceRequest(ot.GetTracer(ctx),
+		req.WithContext(ctx),
+		nethttp.OperationName("Azure DevOps"),
+		nethttp.ClientTrace(false))
+	defer ht.Finish()
+
+	if err := c.authenticate(req); err != nil {
+		return err
+	}
+
+	startWait := time.Now()
+	if err := c.RateLimit.Wait(ctx); err != nil {
+		return err
+	}
+
+	if d := time.Since(startWait); d > 200*time.Millisecond {
+		log15.Warn("Azure DevOps self-enforced API rate limit: request delayed longer than expected due to rate limit", "delay", d)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	// Azure DevOps answers requests with invalid credentials with its sign-in
+	// page and a 203 rather than with a 401.
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 || resp.StatusCode == http.StatusNonAuthoritativeInfo {
+		return errors.WithStack(&httpError{
+			URL:        req.URL,
+			StatusCode: resp.StatusCode,
+			Body:       bs,
+		})
+	}
+
+	if result != nil {
+		return json.Unmarshal(bs, result)
+	}
+
+	return nil
+}
+
+// authenticate adds the client's credentials to the request. Azure DevOps only
+// accepts personal access tokens as the password of basic authentication, so
+// tokens are sent that way.
+func (c *Client) authenticate(req *http.Request) error {
+	switch a := c.Auth.(type) {
+	case nil:
+		return nil
+	case *auth.OAuthBearerToken:
+		req.SetBasicAuth("", a.Token)
+		return nil
+	case *auth.OAuthBearerTokenWithSSH:
+		req.SetBasicAuth("", a.Token)
+		return nil
+	default:
+		return a.Authenticate(req)
+	}
+}
+
+type httpError struct {
+	StatusCode int
+	URL        *url.URL
+	Body       []byte
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("Azure DevOps API HTTP error: code=%d url=%q body=%q", e.StatusCode, e.URL, e.Body)
+}
+
+func (e *httpError) Unauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusNonAuthoritativeInfo
+}
+
+func (e *httpError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
+// IsNotFound reports whether err is an Azure DevOps API not found error.
+func IsNotFound(err error) bool {
+	return errcode.IsNotFound(err)
+}
+
+// IsUnauthorized reports whether err is an Azure DevOps API unauthorized
+// error.
+func IsUnauthorized(err error) bool {
+	return errcode.IsUnauthorized(err)
+}
@@ -0,0 +1,95 @@
+package azuredevops
+
+import (
+	"context"
+	"flag"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var update = flag.Bool("update", false, "update testdata")
+
+func TestClient_ListRepositories(t *testing.T) {
+	cli, save := NewTestClient(t, "ListRepositories", *update, &url.URL{Scheme: "https", Host: "dev.azure.com"})
+	defer save()
+
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name    string
+		org     string
+		project string
+	}{
+		{name: "organization", org: "sgtest"},
+		{name: "project", org: "sgtest", project: "sourcegraph"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			repos, err := cli.ListRepositories(ctx, tc.org, tc.project)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var have []string
+			for _, r := range repos {
+				have = append(have, r.NameWithProject())
+			}
+			want := []string{
+				"sgtest/sourcegraph/sourcegraph",
+				"sgtest/sourcegraph/src-cli",
+				"sgtest/sourcegraph/legacy",
+			}
+			if diff := cmp.Diff(want, have); diff != "" {
+				t.Fatalf("mismatch (-want +have):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := cli.ListRepositories(ctx, "nope", "")
+		if !IsNotFound(err) {
+			t.Errorf("expected not found error, got %v", err)
+		}
+	})
+}
+
+func TestClient_GetPullRequest(t *testing.T) {
+	cli, save := NewTestClient(t, "GetPullRequest", *update, &url.URL{Scheme: "https", Host: "dev.azure.com"})
+	defer save()
+
+	ctx := context.Background()
+	repo := &Repository{
+		ID:           "0f4c1a3e-7d0b-4b7e-8a34-2b8c0d4e5f61",
+		Name:         "sourcegraph",
+		Project:      Project{ID: "7c6a6e3c-2c5b-4a3e-9d3f-6f6b1b0e2c11", Name: "sourcegraph"},
+		WebURL:       "https://dev.azure.com/sgtest/sourcegraph/_git/sourcegraph",
+		Organization: "sgtest",
+	}
+
+	pr, err := cli.GetPullRequest(ctx, repo, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if have, want := pr.Title, "Add README"; have != want {
+		t.Errorf("Title:\nhave: %q\nwant: %q", have, want)
+	}
+	if have, want := pr.WebURL(), "https://dev.azure.com/sgtest/sourcegraph/_git/sourcegraph/pullrequest/1"; have != want {
+		t.Errorf("WebURL:\nhave: %q\nwant: %q", have, want)
+	}
+	if have, want := len(pr.Reviewers), 1; have != want {
+		t.Fatalf("Reviewers:\nhave: %d\nwant: %d", have, want)
+	}
+	if have, want := pr.Reviewers[0].Vote, VoteApproved; have != want {
+		t.Errorf("Vote:\nhave: %d\nwant: %d", have, want)
+	}
+	if pr.StatusEvent() != nil {
+		t.Error("expected no status event for active pull request")
+	}
+
+	_, err = cli.GetPullRequest(ctx, repo, 404)
+	if !IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package azuredevops
+
+import (
+	"encoding/json"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Service hook event types handled by Sourcegraph.
+const (
+	EventTypePullRequestCreated   = "git.pullrequest.created"
+	EventTypePullRequestUpdated   = "git.pullrequest.updated"
+	EventTypePullRequestMerged    = "git.pullrequest.merged"
+	EventTypePullRequestCommented = "ms.vss-code.git-pullrequest-comment-event"
+)
+
+// ErrUnknownEventType is returned by ParseWebhookEvent for service hook events
+// Sourcegraph doesn't handle.
+type ErrUnknownEventType struct {
+	EventType string
+}
+
+func (e ErrUnknownEventType) Error() string {
+	return "unknown service hook event type: " + e.EventType
+}
+
+// PullRequestEvent is a service hook event concerning a pull request.
+type PullRequestEvent struct {
+	EventType   string
+	PullRequest *PullRequest
+}
+
+// ParseWebhookEvent parses the payload of a service hook request.
+func ParseWebhookEvent(payload []byte) (*PullRequestEvent, error) {
+	var envelope struct {
+		EventType string          `json:"eventType"`
+		Resource  json.RawMessage `json:"resource"`
+	}
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return nil, err
+	}
+
+	e := &PullRequestEvent{EventType: envelope.EventType}
+	switch envelope.EventType {
+	case EventTypePullRequestCreated, EventTypePullRequestUpdated, EventTypePullRequestMerged:
+		if err := json.Unmarshal(envelope.Resource, &e.PullRequest); err != nil {
+			return nil, err
+		}
+	case EventTypePullRequestCommented:
+		var resource struct {
+			PullRequest *PullRequest `json:"pullRequest"`
+		}
+		if err := json.Unmarshal(envelope.Resource, &resource); err != nil {
+			return nil, err
+		}
+		e.PullRequest = resource.PullRequest
+	default:
+		return nil, ErrUnknownEventType{EventType: envelope.EventType}
+	}
+
+	if e.PullRequest == nil {
+		return nil, errors.Errorf("%s event without pull request", envelope.EventType)
+	}
+	return e, nil
+}
@@ -0,0 +1,284 @@
+package azuredevops
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Repository is an Azure DevOps Git repository as returned by the REST API.
+type Repository struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	URL           string  `json:"url"`
+	Project       Project `json:"project"`
+	DefaultBranch string  `json:"defaultBranch,omitempty"`
+	Size          int64   `json:"size,omitempty"`
+	RemoteURL     string  `json:"remoteUrl,omitempty"`
+	SSHURL        string  `json:"sshUrl,omitempty"`
+	WebURL        string  `json:"webUrl,omitempty"`
+	IsDisabled    bool    `json:"isDisabled,omitempty"`
+	IsFork        bool    `json:"isFork,omitempty"`
+
+	// Organization is the organization (or project collection on Azure DevOps
+	// Server) the repository belongs to. The API doesn't return it, it is
+	// filled in by the Client.
+	Organization string `json:"organization,omitempty"`
+}
+
+// NameWithProject returns the "org/project/repo" name of the repository.
+func (r *Repository) NameWithProject() string {
+	return r.Organization + "/" + r.Project.Name + "/" + r.Name
+}
+
+// Project is an Azure DevOps project.
+type Project struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	State      string `json:"state,omitempty"`
+	Visibility string `json:"visibility,omitempty"`
+}
+
+// ProjectVisibilityPrivate is the visibility of projects that are only
+// accessible to their members.
+const ProjectVisibilityPrivate = "private"
+
+// IdentityRef is a reference to an identity as embedded in other API objects.
+type IdentityRef struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName,omitempty"`
+	UniqueName  string `json:"uniqueName,omitempty"`
+	Descriptor  string `json:"descriptor,omitempty"`
+	ImageURL    string `json:"imageUrl,omitempty"`
+}
+
+// Identity is an Azure DevOps identity as returned by the identities API.
+type Identity struct {
+	ID                  string             `json:"id"`
+	Descriptor          string             `json:"descriptor"`
+	SubjectDescriptor   string             `json:"subjectDescriptor,omitempty"`
+	ProviderDisplayName string             `json:"providerDisplayName,omitempty"`
+	IsActive            bool               `json:"isActive"`
+	Properties          IdentityProperties `json:"properties"`
+}
+
+// IdentityProperties are the properties of an identity relevant to
+// Sourcegraph.
+type IdentityProperties struct {
+	Account IdentityProperty `json:"Account"`
+	Mail    IdentityProperty `json:"Mail"`
+}
+
+// IdentityProperty is a single property of an identity.
+type IdentityProperty struct {
+	Value string `json:"$value"`
+}
+
+// GitRepositoriesNamespaceID is the ID of the security namespace holding the
+// permissions of Git repositories. Its tokens are "repoV2/<project ID>" for
+// projects and "repoV2/<project ID>/<repository ID>" for repositories.
+const GitRepositoriesNamespaceID = "2e9eb7ed-3c0a-47d4-87c1-0ffdcd83602c"
+
+// GitPermissionGenericRead is the bit of the "Read" permission in the Git
+// repositories security namespace.
+const GitPermissionGenericRead = 2
+
+// AccessControlList is the list of access control entries of a security
+// token.
+type AccessControlList struct {
+	Token              string                         `json:"token"`
+	InheritPermissions bool                           `json:"inheritPermissions"`
+	AcesDictionary     map[string]*AccessControlEntry `json:"acesDictionary"`
+}
+
+// AccessControlEntry holds the permissions of a single identity.
+type AccessControlEntry struct {
+	Descriptor   string                   `json:"descriptor"`
+	Allow        int                      `json:"allow"`
+	Deny         int                      `json:"deny"`
+	ExtendedInfo *AccessControlEntryExtra `json:"extendedInfo,omitempty"`
+}
+
+// AccessControlEntryExtra holds the effective permissions of an access control
+// entry.
+type AccessControlEntryExtra struct {
+	EffectiveAllow int `json:"effectiveAllow"`
+	EffectiveDeny  int `json:"effectiveDeny"`
+	InheritedAllow int `json:"inheritedAllow"`
+	InheritedDeny  int `json:"inheritedDeny"`
+}
+
+// PullRequestStatus is the lifecycle status of a pull request.
+type PullRequestStatus string
+
+// Pull request statuses.
+const (
+	PullRequestStatusActive    PullRequestStatus = "active"
+	PullRequestStatusAbandoned PullRequestStatus = "abandoned"
+	PullRequestStatusCompleted PullRequestStatus = "completed"
+)
+
+// Merge statuses of a pull request.
+const (
+	MergeStatusNotSet           = "notSet"
+	MergeStatusQueued           = "queued"
+	MergeStatusConflicts        = "conflicts"
+	MergeStatusSucceeded        = "succeeded"
+	MergeStatusRejectedByPolicy = "rejectedByPolicy"
+	MergeStatusFailure          = "failure"
+)
+
+// Merge strategies used to complete a pull request.
+const (
+	MergeStrategyNoFastForward = "noFastForward"
+	MergeStrategySquash        = "squash"
+)
+
+// Reviewer votes.
+const (
+	VoteApproved                = 10
+	VoteApprovedWithSuggestions = 5
+	VoteNone                    = 0
+	VoteWaitingForAuthor        = -5
+	VoteRejected                = -10
+)
+
+// PullRequest is an Azure DevOps pull request as returned by the REST API.
+type PullRequest struct {
+	ID                    int               `json:"pullRequestId"`
+	Repository            Repository        `json:"repository"`
+	Status                PullRequestStatus `json:"status"`
+	CreatedBy             IdentityRef       `json:"createdBy"`
+	CreationDate          time.Time         `json:"creationDate"`
+	ClosedBy              *IdentityRef      `json:"closedBy,omitempty"`
+	ClosedDate            time.Time         `json:"closedDate,omitempty"`
+	Title                 string            `json:"title"`
+	Description           string            `json:"description"`
+	SourceRefName         string            `json:"sourceRefName"`
+	TargetRefName         string            `json:"targetRefName"`
+	MergeStatus           string            `json:"mergeStatus,omitempty"`
+	IsDraft               bool              `json:"isDraft"`
+	LastMergeSourceCommit *GitCommitRef     `json:"lastMergeSourceCommit,omitempty"`
+	LastMergeTargetCommit *GitCommitRef     `json:"lastMergeTargetCommit,omitempty"`
+	Reviewers             []*Reviewer       `json:"reviewers,omitempty"`
+	URL                   string            `json:"url"`
+
+	// Statuses are the statuses posted to the pull request. They are not part
+	// of the pull request API response and need to be loaded separately.
+	Statuses []*GitStatus `json:"statuses,omitempty"`
+}
+
+// WebURL returns the URL of the pull request in the Azure DevOps web UI.
+func (pr *PullRequest) WebURL() string {
+	if pr.Repository.WebURL == "" {
+		return ""
+	}
+	return pr.Repository.WebURL + "/pullrequest/" + strconv.Itoa(pr.ID)
+}
+
+// StatusEvent returns the event recording that the pull request was completed
+// or abandoned. It returns nil for active pull requests.
+func (pr *PullRequest) StatusEvent() *PullRequestStatusEvent {
+	if pr.Status == PullRequestStatusActive || pr.ClosedDate.IsZero() {
+		return nil
+	}
+	return &PullRequestStatusEvent{
+		PullRequestID: pr.ID,
+		Status:        pr.Status,
+		ClosedBy:      pr.ClosedBy,
+		ClosedDate:    pr.ClosedDate,
+	}
+}
+
+// GitCommitRef is a reference to a commit.
+type GitCommitRef struct {
+	CommitID string `json:"commitId"`
+}
+
+// Reviewer is a reviewer of a pull request and their vote.
+type Reviewer struct {
+	IdentityRef
+	Vote        int  `json:"vote"`
+	IsRequired  bool `json:"isRequired,omitempty"`
+	HasDeclined bool `json:"hasDeclined,omitempty"`
+}
+
+// GitStatusState is the state of a status posted to a pull request.
+type GitStatusState string
+
+// Status states.
+const (
+	GitStatusStateNotSet        GitStatusState = "notSet"
+	GitStatusStatePending       GitStatusState = "pending"
+	GitStatusStateSucceeded     GitStatusState = "succeeded"
+	GitStatusStateFailed        GitStatusState = "failed"
+	GitStatusStateError         GitStatusState = "error"
+	GitStatusStateNotApplicable GitStatusState = "notApplicable"
+)
+
+// GitStatus is a status posted to a pull request, such as the result of a
+// build validation policy.
+type GitStatus struct {
+	ID           int              `json:"id"`
+	State        GitStatusState   `json:"state"`
+	Description  string           `json:"description,omitempty"`
+	Context      GitStatusContext `json:"context"`
+	CreationDate time.Time        `json:"creationDate"`
+	UpdatedDate  time.Time        `json:"updatedDate"`
+	IterationID  int              `json:"iterationId,omitempty"`
+}
+
+// GitStatusContext identifies the service that posted a status.
+type GitStatusContext struct {
+	Name  string `json:"name"`
+	Genre string `json:"genre,omitempty"`
+}
+
+// Key identifies the context of a status. Statuses posted again with the same
+// context supersede earlier ones.
+func (s *GitStatus) Key() string {
+	return s.Context.Genre + "/" + s.Context.Name
+}
+
+// CreatePullRequestInput is the payload used to create a pull request.
+type CreatePullRequestInput struct {
+	SourceRefName string `json:"sourceRefName"`
+	TargetRefName string `json:"targetRefName"`
+	Title         string `json:"title"`
+	Description   string `json:"description"`
+	IsDraft       bool   `json:"isDraft"`
+}
+
+// UpdatePullRequestInput is the payload used to update a pull request. Empty
+// fields are left unchanged.
+type UpdatePullRequestInput struct {
+	Title                 string             `json:"title,omitempty"`
+	Description           *string            `json:"description,omitempty"`
+	TargetRefName         string             `json:"targetRefName,omitempty"`
+	Status                PullRequestStatus  `json:"status,omitempty"`
+	IsDraft               *bool              `json:"isDraft,omitempty"`
+	LastMergeSourceCommit *GitCommitRef      `json:"lastMergeSourceCommit,omitempty"`
+	CompletionOptions     *CompletionOptions `json:"completionOptions,omitempty"`
+}
+
+// CompletionOptions are the options used when completing a pull request.
+type CompletionOptions struct {
+	MergeStrategy      string `json:"mergeStrategy,omitempty"`
+	DeleteSourceBranch bool   `json:"deleteSourceBranch,omitempty"`
+}
+
+// PullRequestStatusEvent records that a pull request was completed or
+// abandoned. Azure DevOps doesn't expose a timeline of pull request events, so
+// these events are derived from the pull request itself.
+type PullRequestStatusEvent struct {
+	PullRequestID int               `json:"pullRequestId"`
+	Status        PullRequestStatus `json:"status"`
+	ClosedBy      *IdentityRef      `json:"closedBy,omitempty"`
+	ClosedDate    time.Time         `json:"closedDate"`
+}
+
+// Key is a unique key identifying this event in the context of its pull
+// request.
+func (e *PullRequestStatusEvent) Key() string {
+	return fmt.Sprintf("%d:%s:%d", e.PullRequestID, e.Status, e.ClosedDate.UnixNano())
+}
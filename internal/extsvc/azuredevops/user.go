@@ -0,0 +1,22 @@
+package azuredevops
+
+import "github.com/sourcegraph/sourcegraph/internal/extsvc"
+
+// GetExternalAccountData returns the Azure DevOps identity stored in the given
+// external account data.
+func GetExternalAccountData(data *extsvc.AccountData) (*Identity, error) {
+	if data.Data == nil {
+		return nil, nil
+	}
+
+	var id Identity
+	if err := data.GetAccountData(&id); err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// SetExternalAccountData sets the identity in the given external account data.
+func SetExternalAccountData(data *extsvc.AccountData, id *Identity) {
+	data.SetAccountData(id)
+}
@@ -0,0 +1,253 @@
+package gerrit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+	"github.com/opentracing-contrib/go-stdlib/nethttp"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+)
+
+var requestCounter = metrics.NewRequestMeter("gerrit_requests_count", "Total number of requests sent to the Gerrit API.")
+
+// These fields define the self-imposed Gerrit rate limit. Gerrit does not
+// report rate limits in HTTP response headers, so we use the same defaults as
+// for Bitbucket Cloud.
+//
+// See https://godoc.org/golang.org/x/time/rate#Limiter for an explanation of these fields.
+const (
+	rateLimitRequestsPerSecond = 2 // 120/min or 7200/hr
+	RateLimitMaxBurstRequests  = 500
+)
+
+// magicPrefix is prepended by Gerrit to every JSON response body to prevent
+// cross-site script inclusion.
+//
+// See https://gerrit-review.googlesource.com/Documentation/rest-api.html#output
+var magicPrefix = []byte(")]}'")
+
+// Client access a Gerrit instance via the REST API.
+type Client struct {
+	// HTTP Client used to communicate with the API
+	httpClient httpcli.Doer
+
+	// URL is the base URL of the Gerrit instance.
+	URL *url.URL
+
+	// Username and Password are the HTTP credentials used to authenticate
+	// requests.
+	Username string
+	Password string
+
+	// RateLimit is the self-imposed rate limiter (since Gerrit does not have a concept
+	// of rate limiting in HTTP response headers).
+	RateLimit *rate.Limiter
+}
+
+// NewClient creates a new Gerrit API client for the instance at baseURL. If a
+// nil httpClient is provided, http.DefaultClient will be used. The Username and
+// Password fields should be set before calling any APIs.
+func NewClient(baseURL *url.URL, httpClient httpcli.Doer) *Client {
+	if httpClient == nil {
+		httpClient = httpcli.ExternalDoer()
+	}
+
+	httpClient = requestCounter.Doer(httpClient, func(u *url.URL) string {
+		// The third component of the Path (/a/<category>/...) maps to the
+		// type of API request we are making.
+		var category string
+		if parts := strings.SplitN(u.Path, "/", 4); len(parts) > 2 {
+			category = parts[2]
+		}
+		return category
+	})
+
+	// Normally our registry will return a default infinite limiter when nothing has been
+	// synced from config. However, we always want to ensure there is at least some form of rate
+	// limiting for Gerrit.
+	defaultLimiter := rate.NewLimiter(rateLimitRequestsPerSecond, RateLimitMaxBurstRequests)
+	l := ratelimit.DefaultRegistry.GetOrSet(baseURL.String(), defaultLimiter)
+
+	// Gerrit is commonly served from a sub-path (e.g. https://example.com/r/),
+	// so API paths are resolved relative to a base URL ending in a slash.
+	if !strings.HasSuffix(baseURL.Path, "/") {
+		u := *baseURL
+		u.Path += "/"
+		baseURL = &u
+	}
+
+	return &Client{
+		httpClient: httpClient,
+		URL:        baseURL,
+		RateLimit:  l,
+	}
+}
+
+// ListProjects returns a page of the code projects visible to the
+// authenticated user, ordered by name. skip is the number of projects to skip
+// and limit the maximum number of projects to return.
+func (c *Client) ListProjects(ctx context.Context, skip, limit int) (projects []*Project, hasNextPage bool, err error) {
+	qry := url.Values{}
+	qry.Set("d", "")
+	qry.Set("type", "CODE")
+	qry.Set("n", strconv.Itoa(limit))
+	qry.Set("S", strconv.Itoa(skip))
+
+	u := url.URL{Path: "a/projects/", RawQuery: qry.Encode()}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var byName map[string]*Project
+	if err := c.do(ctx, req, &byName); err != nil {
+		return nil, false, err
+	}
+
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	projects = make([]*Project, 0, len(names))
+	for _, name := range names {
+		p := byName[name]
+		p.Name = name
+		projects = append(projects, p)
+		hasNextPage = hasNextPage || p.MoreProjects
+	}
+
+	return projects, hasNextPage, nil
+}
+
+// GetProject returns the project with the given name.
+func (c *Client) GetProject(ctx context.Context, name string) (*Project, error) {
+	req, err := http.NewRequest("GET", "a/projects/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var p Project
+	if err := c.do(ctx, req, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (c *Client) do(ctx context.Context, req *http.Request, result interface{}) error {
+	req.URL = c.URL.ResolveReference(req.URL)
+	req.Header.Set("Accept", "application/json")
+
+	req, ht := nethttp.TraceRequest(ot.GetTracer(ctx),
+		req.WithContext(ctx),
+		nethttp.OperationName("Gerrit"),
+		nethttp.ClientTrace(false))
+	defer ht.Finish()
+
+	if c.Username != "" || c.Password != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+
+	startWait := time.Now()
+	if err := c.RateLimit.Wait(ctx); err != nil {
+		return err
+	}
+
+	if d := time.Since(startWait); d > 200*time.Millisecond {
+		log15.Warn("Gerrit self-enforced API rate limit: request delayed longer than expected due to rate limit", "delay", d)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return errors.WithStack(&httpError{
+			URL:        req.URL,
+			StatusCode: resp.StatusCode,
+			Body:       bs,
+		})
+	}
+
+	if result != nil {
+		return json.Unmarshal(bytes.TrimPrefix(bs, magicPrefix), result)
+	}
+
+	return nil
+}
+
+// Project is a Gerrit project as returned by the REST API.
+type Project struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Parent      string     `json:"parent,omitempty"`
+	Description string     `json:"description,omitempty"`
+	State       string     `json:"state,omitempty"`
+	WebLinks    []*WebLink `json:"web_links,omitempty"`
+
+	// MoreProjects is set on the last project of a page when more projects
+	// are available.
+	MoreProjects bool `json:"_more_projects,omitempty"`
+}
+
+// WebLink is a link to an external web view of a project.
+type WebLink struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// Project states.
+const (
+	ProjectStateActive   = "ACTIVE"
+	ProjectStateReadOnly = "READ_ONLY"
+	ProjectStateHidden   = "HIDDEN"
+)
+
+type httpError struct {
+	StatusCode int
+	URL        *url.URL
+	Body       []byte
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("Gerrit API HTTP error: code=%d url=%q body=%q", e.StatusCode, e.URL, e.Body)
+}
+
+func (e *httpError) Unauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized
+}
+
+func (e *httpError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
+// IsNotFound reports whether err is a Gerrit API not found error.
+func IsNotFound(err error) bool {
+	return errcode.IsNotFound(err)
+}
@@ -0,0 +1,128 @@
+package gerrit
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var update = flag.Bool("update", false, "update testdata")
+
+func TestClient_ListProjects(t *testing.T) {
+	cli, save := NewTestClient(t, "ListProjects", *update, &url.URL{Scheme: "https", Host: "gerrit.sgdev.org"})
+	defer save()
+
+	timeout, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	for _, tc := range []struct {
+		name        string
+		ctx         context.Context
+		skip, limit int
+		projects    []*Project
+		hasNext     bool
+		err         string
+	}{
+		{
+			name: "timeout",
+			ctx:  timeout,
+			err:  "context deadline exceeded",
+		},
+		{
+			name:  "first page",
+			skip:  0,
+			limit: 2,
+			projects: []*Project{
+				{
+					ID:          "sourcegraph%2Fsourcegraph",
+					Name:        "sourcegraph/sourcegraph",
+					Parent:      "All-Projects",
+					Description: "Universal code search",
+					State:       ProjectStateActive,
+					WebLinks: []*WebLink{
+						{Name: "browse", URL: "/plugins/gitiles/sourcegraph/sourcegraph"},
+					},
+				},
+				{
+					ID:          "sourcegraph%2Fsrc-cli",
+					Name:        "sourcegraph/src-cli",
+					Parent:      "All-Projects",
+					Description: "Sourcegraph CLI",
+					State:       ProjectStateActive,
+					WebLinks: []*WebLink{
+						{Name: "browse", URL: "/plugins/gitiles/sourcegraph/src-cli"},
+					},
+					MoreProjects: true,
+				},
+			},
+			hasNext: true,
+		},
+		{
+			name:  "last page",
+			skip:  2,
+			limit: 2,
+			projects: []*Project{
+				{
+					ID:     "sourcegraph%2Fzoekt",
+					Name:   "sourcegraph/zoekt",
+					Parent: "All-Projects",
+					State:  ProjectStateReadOnly,
+					WebLinks: []*WebLink{
+						{Name: "browse", URL: "/plugins/gitiles/sourcegraph/zoekt"},
+					},
+				},
+			},
+			hasNext: false,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.ctx == nil {
+				tc.ctx = context.Background()
+			}
+
+			if tc.err == "" {
+				tc.err = "<nil>"
+			}
+
+			projects, hasNext, err := cli.ListProjects(tc.ctx, tc.skip, tc.limit)
+			if have, want := fmt.Sprint(err), tc.err; have != want {
+				t.Errorf("error:\nhave: %q\nwant: %q", have, want)
+			}
+
+			if have, want := hasNext, tc.hasNext; have != want {
+				t.Errorf("hasNext:\nhave: %t\nwant: %t", have, want)
+			}
+
+			if have, want := projects, tc.projects; !reflect.DeepEqual(have, want) {
+				t.Error(cmp.Diff(have, want))
+			}
+		})
+	}
+}
+
+func TestClient_GetProject(t *testing.T) {
+	cli, save := NewTestClient(t, "GetProject", *update, &url.URL{Scheme: "https", Host: "gerrit.sgdev.org"})
+	defer save()
+
+	ctx := context.Background()
+
+	p, err := cli.GetProject(ctx, "sourcegraph/src-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := p.ID, "sourcegraph%2Fsrc-cli"; have != want {
+		t.Errorf("ID:\nhave: %q\nwant: %q", have, want)
+	}
+
+	_, err = cli.GetProject(ctx, "sourcegraph/does-not-exist")
+	if !IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
@@ -0,0 +1,243 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+	"github.com/opentracing-contrib/go-stdlib/nethttp"
+	"github.com/peterhellberg/link"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+)
+
+var requestCounter = metrics.NewRequestMeter("gitea_requests_count", "Total number of requests sent to the Gitea API.")
+
+// These fields define the self-imposed Gitea rate limit. Gitea does not report
+// rate limits in HTTP response headers, so we use the same defaults as for
+// Bitbucket Cloud.
+//
+// See https://godoc.org/golang.org/x/time/rate#Limiter for an explanation of these fields.
+const (
+	rateLimitRequestsPerSecond = 2 // 120/min or 7200/hr
+	RateLimitMaxBurstRequests  = 500
+)
+
+// Client access a Gitea instance via the REST API v1.
+type Client struct {
+	// HTTP Client used to communicate with the API
+	httpClient httpcli.Doer
+
+	// URL is the base URL of the Gitea instance.
+	URL *url.URL
+
+	// Token is the access token used to authenticate requests.
+	Token string
+
+	// RateLimit is the self-imposed rate limiter (since Gitea does not have a concept
+	// of rate limiting in HTTP response headers).
+	RateLimit *rate.Limiter
+}
+
+// NewClient creates a new Gitea API client for the instance at baseURL. If a nil
+// httpClient is provided, http.DefaultClient will be used. The Token field
+// should be set before calling any APIs.
+func NewClient(baseURL *url.URL, httpClient httpcli.Doer) *Client {
+	if httpClient == nil {
+		httpClient = httpcli.ExternalDoer()
+	}
+
+	httpClient = requestCounter.Doer(httpClient, func(u *url.URL) string {
+		// The fourth component of the Path (/api/v1/<category>/...) mostly maps
+		// to the type of API request we are making.
+		var category string
+		if parts := strings.SplitN(u.Path, "/", 5); len(parts) > 3 {
+			category = parts[3]
+		}
+		return category
+	})
+
+	// Normally our registry will return a default infinite limiter when nothing has been
+	// synced from config. However, we always want to ensure there is at least some form of rate
+	// limiting for Gitea.
+	defaultLimiter := rate.NewLimiter(rateLimitRequestsPerSecond, RateLimitMaxBurstRequests)
+	l := ratelimit.DefaultRegistry.GetOrSet(baseURL.String(), defaultLimiter)
+
+	return &Client{
+		httpClient: httpClient,
+		URL:        baseURL,
+		RateLimit:  l,
+	}
+}
+
+// ListUserRepos returns a page of the repositories the authenticated user has
+// access to. Pages are 1-indexed.
+func (c *Client) ListUserRepos(ctx context.Context, page, perPage int) (repos []*Repo, hasNextPage bool, err error) {
+	return c.listRepos(ctx, "/api/v1/user/repos", page, perPage)
+}
+
+// ListOrgRepos returns a page of the repositories of the given organization.
+// Pages are 1-indexed.
+func (c *Client) ListOrgRepos(ctx context.Context, org string, page, perPage int) (repos []*Repo, hasNextPage bool, err error) {
+	return c.listRepos(ctx, "/api/v1/orgs/"+url.PathEscape(org)+"/repos", page, perPage)
+}
+
+// GetRepo returns the repository with the given owner and name.
+func (c *Client) GetRepo(ctx context.Context, owner, name string) (*Repo, error) {
+	req, err := http.NewRequest("GET", "/api/v1/repos/"+url.PathEscape(owner)+"/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var repo Repo
+	if _, err := c.do(ctx, req, &repo); err != nil {
+		return nil, err
+	}
+	return &repo, nil
+}
+
+func (c *Client) listRepos(ctx context.Context, path string, page, perPage int) ([]*Repo, bool, error) {
+	qry := url.Values{}
+	qry.Set("page", strconv.Itoa(page))
+	qry.Set("limit", strconv.Itoa(perPage))
+
+	u := url.URL{Path: path, RawQuery: qry.Encode()}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var repos []*Repo
+	respHeader, err := c.do(ctx, req, &repos)
+	if err != nil {
+		return nil, false, err
+	}
+	return repos, hasNextPage(respHeader, repos), nil
+}
+
+// hasNextPage reports whether there is a page after the one that returned
+// repos and the given response header. Gitea caps the page size at its
+// configured maximum, which may be lower than the requested one, so the size
+// of a page doesn't tell whether it is the last one.
+func hasNextPage(respHeader http.Header, repos []*Repo) bool {
+	// Gitea versions which send X-Total-Count also send a Link header, but
+	// only if there is more than one page.
+	if l := respHeader.Get("Link"); l != "" || respHeader.Get("X-Total-Count") != "" {
+		return link.Parse(l)["next"] != nil
+	}
+	// Older versions don't send pagination headers, so we list pages until
+	// we get an empty one.
+	return len(repos) > 0
+}
+
+func (c *Client) do(ctx context.Context, req *http.Request, result interface{}) (responseHeader http.Header, err error) {
+	req.URL = c.URL.ResolveReference(req.URL)
+	req.Header.Set("Accept", "application/json")
+
+	req, ht := nethttp.TraceRequest(ot.GetTracer(ctx),
+		req.WithContext(ctx),
+		nethttp.OperationName("Gitea"),
+		nethttp.ClientTrace(false))
+	defer ht.Finish()
+
+	if c.Token != "" {
+		req.Header.Set("Authorization", "token "+c.Token)
+	}
+
+	startWait := time.Now()
+	if err := c.RateLimit.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	if d := time.Since(startWait); d > 200*time.Millisecond {
+		log15.Warn("Gitea self-enforced API rate limit: request delayed longer than expected due to rate limit", "delay", d)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return nil, errors.WithStack(&httpError{
+			URL:        req.URL,
+			StatusCode: resp.StatusCode,
+			Body:       bs,
+		})
+	}
+
+	if result != nil {
+		return resp.Header, json.Unmarshal(bs, result)
+	}
+
+	return resp.Header, nil
+}
+
+// Repo is a Gitea repository as returned by the REST API.
+type Repo struct {
+	ID            int64  `json:"id"`
+	Owner         *User  `json:"owner"`
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	Description   string `json:"description"`
+	Empty         bool   `json:"empty"`
+	Private       bool   `json:"private"`
+	Fork          bool   `json:"fork"`
+	Mirror        bool   `json:"mirror"`
+	Archived      bool   `json:"archived"`
+	HTMLURL       string `json:"html_url"`
+	SSHURL        string `json:"ssh_url"`
+	CloneURL      string `json:"clone_url"`
+	DefaultBranch string `json:"default_branch"`
+	StarsCount    int    `json:"stars_count"`
+}
+
+// User is a Gitea user or organization.
+type User struct {
+	ID       int64  `json:"id"`
+	Login    string `json:"login"`
+	FullName string `json:"full_name"`
+}
+
+type httpError struct {
+	StatusCode int
+	URL        *url.URL
+	Body       []byte
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("Gitea API HTTP error: code=%d url=%q body=%q", e.StatusCode, e.URL, e.Body)
+}
+
+func (e *httpError) Unauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized
+}
+
+func (e *httpError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
+// IsNotFound reports whether err is a Gitea API not found error.
+func IsNotFound(err error) bool {
+	return errcode.IsNotFound(err)
+}
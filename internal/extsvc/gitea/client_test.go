@@ -0,0 +1,213 @@
+package gitea
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var update = flag.Bool("update", false, "update testdata")
+
+func TestClient_ListUserRepos(t *testing.T) {
+	cli, save := NewTestClient(t, "ListUserRepos", *update, &url.URL{Scheme: "https", Host: "gitea.sgdev.org"})
+	defer save()
+
+	timeout, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	owner := &User{ID: 2, Login: "sourcegraph", FullName: "Sourcegraph"}
+	repos := map[string]*Repo{
+		"sourcegraph": {
+			ID:            1,
+			Owner:         owner,
+			Name:          "sourcegraph",
+			FullName:      "sourcegraph/sourcegraph",
+			Description:   "Universal code search",
+			HTMLURL:       "https://gitea.sgdev.org/sourcegraph/sourcegraph",
+			SSHURL:        "git@gitea.sgdev.org:sourcegraph/sourcegraph.git",
+			CloneURL:      "https://gitea.sgdev.org/sourcegraph/sourcegraph.git",
+			DefaultBranch: "main",
+			StarsCount:    12,
+		},
+		"src-cli": {
+			ID:            2,
+			Owner:         owner,
+			Name:          "src-cli",
+			FullName:      "sourcegraph/src-cli",
+			Description:   "Sourcegraph CLI",
+			HTMLURL:       "https://gitea.sgdev.org/sourcegraph/src-cli",
+			SSHURL:        "git@gitea.sgdev.org:sourcegraph/src-cli.git",
+			CloneURL:      "https://gitea.sgdev.org/sourcegraph/src-cli.git",
+			DefaultBranch: "main",
+			StarsCount:    3,
+		},
+		"dotfiles": {
+			ID:            4,
+			Owner:         &User{ID: 1, Login: "sourcegraph-bot", FullName: "Sourcegraph Bot"},
+			Name:          "dotfiles",
+			FullName:      "sourcegraph-bot/dotfiles",
+			Private:       true,
+			HTMLURL:       "https://gitea.sgdev.org/sourcegraph-bot/dotfiles",
+			SSHURL:        "git@gitea.sgdev.org:sourcegraph-bot/dotfiles.git",
+			CloneURL:      "https://gitea.sgdev.org/sourcegraph-bot/dotfiles.git",
+			DefaultBranch: "master",
+		},
+	}
+
+	for _, tc := range []struct {
+		name    string
+		ctx     context.Context
+		page    int
+		perPage int
+		repos   []*Repo
+		hasNext bool
+		err     string
+	}{
+		{
+			name:    "timeout",
+			ctx:     timeout,
+			page:    1,
+			perPage: 2,
+			err:     "context deadline exceeded",
+		},
+		{
+			name:    "pagination: first page",
+			page:    1,
+			perPage: 2,
+			repos:   []*Repo{repos["sourcegraph"], repos["src-cli"]},
+			hasNext: true,
+		},
+		{
+			name:    "pagination: last page",
+			page:    3,
+			perPage: 2,
+			repos:   []*Repo{repos["dotfiles"]},
+			hasNext: false,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.ctx == nil {
+				tc.ctx = context.Background()
+			}
+
+			if tc.err == "" {
+				tc.err = "<nil>"
+			}
+
+			repos, hasNext, err := cli.ListUserRepos(tc.ctx, tc.page, tc.perPage)
+			if have, want := fmt.Sprint(err), tc.err; have != want {
+				t.Errorf("error:\nhave: %q\nwant: %q", have, want)
+			}
+
+			if have, want := hasNext, tc.hasNext; have != want {
+				t.Errorf("hasNext:\nhave: %t\nwant: %t", have, want)
+			}
+
+			if have, want := repos, tc.repos; !reflect.DeepEqual(have, want) {
+				t.Error(cmp.Diff(have, want))
+			}
+		})
+	}
+}
+
+func TestClient_ListOrgRepos(t *testing.T) {
+	cli, save := NewTestClient(t, "ListOrgRepos", *update, &url.URL{Scheme: "https", Host: "gitea.sgdev.org"})
+	defer save()
+
+	repos, hasNext, err := cli.ListOrgRepos(context.Background(), "sourcegraph", 1, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hasNext {
+		t.Error("expected no next page")
+	}
+
+	var have []string
+	for _, r := range repos {
+		have = append(have, r.FullName)
+	}
+	want := []string{
+		"sourcegraph/sourcegraph",
+		"sourcegraph/src-cli",
+		"sourcegraph/go-langserver-fork",
+		"sourcegraph/archived",
+	}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Fatalf("mismatch (-want +have):\n%s", diff)
+	}
+}
+
+func TestHasNextPage(t *testing.T) {
+	repos := []*Repo{{ID: 1}, {ID: 2}}
+	for _, tc := range []struct {
+		name   string
+		header http.Header
+		repos  []*Repo
+		want   bool
+	}{
+		{
+			name: "next link",
+			header: http.Header{
+				"Link":          {`<https://gitea.sgdev.org/api/v1/user/repos?limit=50&page=2>; rel="next",<https://gitea.sgdev.org/api/v1/user/repos?limit=50&page=3>; rel="last"`},
+				"X-Total-Count": {"5"},
+			},
+			repos: repos,
+			want:  true,
+		},
+		{
+			name: "last page",
+			header: http.Header{
+				"Link":          {`<https://gitea.sgdev.org/api/v1/user/repos?limit=50&page=1>; rel="first",<https://gitea.sgdev.org/api/v1/user/repos?limit=50&page=2>; rel="prev"`},
+				"X-Total-Count": {"5"},
+			},
+			repos: repos[:1],
+		},
+		{
+			name:   "single page",
+			header: http.Header{"X-Total-Count": {"2"}},
+			repos:  repos,
+		},
+		{
+			name:   "no pagination headers",
+			header: http.Header{},
+			repos:  repos,
+			want:   true,
+		},
+		{
+			name:   "no pagination headers, empty page",
+			header: http.Header{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if have := hasNextPage(tc.header, tc.repos); have != tc.want {
+				t.Errorf("have %t, want %t", have, tc.want)
+			}
+		})
+	}
+}
+
+func TestClient_GetRepo(t *testing.T) {
+	cli, save := NewTestClient(t, "GetRepo", *update, &url.URL{Scheme: "https", Host: "gitea.sgdev.org"})
+	defer save()
+
+	repo, err := cli.GetRepo(context.Background(), "sourcegraph", "src-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := repo.FullName, "sourcegraph/src-cli"; have != want {
+		t.Errorf("FullName:\nhave: %q\nwant: %q", have, want)
+	}
+
+	_, err = cli.GetRepo(context.Background(), "sourcegraph", "does-not-exist")
+	if !IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
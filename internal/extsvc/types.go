@@ -75,8 +75,11 @@ const (
 	// in preference to the Type values below.
 
 	KindAWSCodeCommit   = "AWSCODECOMMIT"
+	KindAzureDevOps     = "AZUREDEVOPS"
 	KindBitbucketServer = "BITBUCKETSERVER"
 	KindBitbucketCloud  = "BITBUCKETCLOUD"
+	KindGerrit          = "GERRIT"
+	KindGitea           = "GITEA"
 	KindGitHub          = "GITHUB"
 	KindGitLab          = "GITLAB"
 	KindGitolite        = "GITOLITE"
@@ -98,10 +101,22 @@ const (
 	// ServiceID value is the base URL to the Bitbucket Server instance.
 	TypeBitbucketServer = "bitbucketServer"
 
+	// TypeAzureDevOps is the (api.ExternalRepoSpec).ServiceType value for Azure DevOps repositories. The
+	// ServiceID value is the base URL to Azure DevOps (https://dev.azure.com or the Azure DevOps Server URL).
+	TypeAzureDevOps = "azuredevops"
+
 	// TypeBitbucketCloud is the (api.ExternalRepoSpec).ServiceType value for Bitbucket Cloud projects. The
 	// ServiceID value is the base URL to the Bitbucket Cloud.
 	TypeBitbucketCloud = "bitbucketCloud"
 
+	// TypeGerrit is the (api.ExternalRepoSpec).ServiceType value for Gerrit projects. The ServiceID
+	// value is the base URL to the Gerrit instance.
+	TypeGerrit = "gerrit"
+
+	// TypeGitea is the (api.ExternalRepoSpec).ServiceType value for Gitea repositories. The ServiceID
+	// value is the base URL to the Gitea instance.
+	TypeGitea = "gitea"
+
 	// TypeGitHub is the (api.ExternalRepoSpec).ServiceType value for GitHub repositories. The ServiceID value
 	// is the base URL to the GitHub instance (https://github.com or the GitHub Enterprise URL).
 	TypeGitHub = "github"
@@ -138,6 +153,12 @@ func KindToType(kind string) string {
 		return TypeBitbucketServer
 	case KindBitbucketCloud:
 		return TypeBitbucketCloud
+	case KindAzureDevOps:
+		return TypeAzureDevOps
+	case KindGerrit:
+		return TypeGerrit
+	case KindGitea:
+		return TypeGitea
 	case KindGitHub:
 		return TypeGitHub
 	case KindGitLab:
@@ -167,6 +188,12 @@ func TypeToKind(t string) string {
 		return KindBitbucketServer
 	case TypeBitbucketCloud:
 		return KindBitbucketCloud
+	case TypeAzureDevOps:
+		return KindAzureDevOps
+	case TypeGerrit:
+		return KindGerrit
+	case TypeGitea:
+		return KindGitea
 	case TypeGitHub:
 		return KindGitHub
 	case TypeGitLab:
@@ -203,6 +230,12 @@ func ParseServiceType(s string) (string, bool) {
 		return TypeBitbucketServer, true
 	case bbcLower:
 		return TypeBitbucketCloud, true
+	case TypeAzureDevOps:
+		return TypeAzureDevOps, true
+	case TypeGerrit:
+		return TypeGerrit, true
+	case TypeGitea:
+		return TypeGitea, true
 	case TypeGitHub:
 		return TypeGitHub, true
 	case TypeGitLab:
@@ -232,6 +265,12 @@ func ParseServiceKind(s string) (string, bool) {
 		return KindBitbucketServer, true
 	case KindBitbucketCloud:
 		return KindBitbucketCloud, true
+	case KindAzureDevOps:
+		return KindAzureDevOps, true
+	case KindGerrit:
+		return KindGerrit, true
+	case KindGitea:
+		return KindGitea, true
 	case KindGitHub:
 		return KindGitHub, true
 	case KindGitLab:
@@ -273,6 +312,12 @@ func ParseConfig(kind, config string) (cfg interface{}, _ error) {
 		cfg = &schema.BitbucketServerConnection{}
 	case KindBitbucketCloud:
 		cfg = &schema.BitbucketCloudConnection{}
+	case KindAzureDevOps:
+		cfg = &schema.AzureDevOpsConnection{}
+	case KindGerrit:
+		cfg = &schema.GerritConnection{}
+	case KindGitea:
+		cfg = &schema.GiteaConnection{}
 	case KindGitHub:
 		cfg = &schema.GitHubConnection{}
 	case KindGitLab:
@@ -304,6 +349,8 @@ func WebhookURL(kind string, externalServiceID int64, externalURL string) string
 		path = "bitbucket-server-webhooks"
 	case KindGitLab:
 		path = "gitlab-webhooks"
+	case KindAzureDevOps:
+		path = "azuredevops-webhooks"
 	default:
 		return ""
 	}
@@ -376,6 +423,27 @@ func GetLimitFromConfig(kind string, config interface{}) (rlc RateLimitConfig, e
 			rlc.IsDefault = false
 		}
 		rlc.BaseURL = c.Url
+	case *schema.GiteaConnection:
+		rlc.Limit = defaultRateLimit
+		if c != nil && c.RateLimit != nil {
+			rlc.Limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
+			rlc.IsDefault = false
+		}
+		rlc.BaseURL = c.Url
+	case *schema.GerritConnection:
+		rlc.Limit = defaultRateLimit
+		if c != nil && c.RateLimit != nil {
+			rlc.Limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
+			rlc.IsDefault = false
+		}
+		rlc.BaseURL = c.Url
+	case *schema.AzureDevOpsConnection:
+		rlc.Limit = defaultRateLimit
+		if c != nil && c.RateLimit != nil {
+			rlc.Limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
+			rlc.IsDefault = false
+		}
+		rlc.BaseURL = c.Url
 	case *schema.PerforceConnection:
 		rlc.Limit = rate.Limit(5000.0 / 3600.0)
 		if c != nil && c.RateLimit != nil {
@@ -472,6 +540,12 @@ func UniqueCodeHostIdentifier(kind, config string) (string, error) {
 		rawURL = c.Url
 	case *schema.BitbucketCloudConnection:
 		rawURL = c.Url
+	case *schema.AzureDevOpsConnection:
+		rawURL = c.Url
+	case *schema.GerritConnection:
+		rawURL = c.Url
+	case *schema.GiteaConnection:
+		rawURL = c.Url
 	case *schema.PhabricatorConnection:
 		rawURL = c.Url
 	case *schema.OtherExternalServiceConnection:
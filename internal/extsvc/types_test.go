@@ -62,6 +62,42 @@ func TestExtractRateLimitConfig(t *testing.T) {
 				IsDefault:   true,
 			},
 		},
+		{
+			name:        "Gitea default",
+			config:      `{"url": "https://example.com/"}`,
+			kind:        KindGitea,
+			displayName: "Gitea 1",
+			want: RateLimitConfig{
+				BaseURL:     "https://example.com/",
+				DisplayName: "Gitea 1",
+				Limit:       2.0,
+				IsDefault:   true,
+			},
+		},
+		{
+			name:        "Azure DevOps default",
+			config:      `{"url": "https://dev.azure.com/"}`,
+			kind:        KindAzureDevOps,
+			displayName: "Azure DevOps 1",
+			want: RateLimitConfig{
+				BaseURL:     "https://dev.azure.com/",
+				DisplayName: "Azure DevOps 1",
+				Limit:       2.0,
+				IsDefault:   true,
+			},
+		},
+		{
+			name:        "Gerrit default",
+			config:      `{"url": "https://example.com/"}`,
+			kind:        KindGerrit,
+			displayName: "Gerrit 1",
+			want: RateLimitConfig{
+				BaseURL:     "https://example.com/",
+				DisplayName: "Gerrit 1",
+				Limit:       2.0,
+				IsDefault:   true,
+			},
+		},
 		{
 			name:        "GitLab non-default",
 			config:      `{"url": "https://example.com/", "rateLimit": {"enabled": true, "requestsPerHour": 3600}}`,
@@ -110,6 +146,30 @@ func TestExtractRateLimitConfig(t *testing.T) {
 				IsDefault:   false,
 			},
 		},
+		{
+			name:        "Gitea non-default",
+			config:      `{"url": "https://example.com/", "rateLimit": {"enabled": true, "requestsPerHour": 3600}}`,
+			kind:        KindGitea,
+			displayName: "Gitea 1",
+			want: RateLimitConfig{
+				BaseURL:     "https://example.com/",
+				DisplayName: "Gitea 1",
+				Limit:       1.0,
+				IsDefault:   false,
+			},
+		},
+		{
+			name:        "Gerrit non-default",
+			config:      `{"url": "https://example.com/", "rateLimit": {"enabled": true, "requestsPerHour": 3600}}`,
+			kind:        KindGerrit,
+			displayName: "Gerrit 1",
+			want: RateLimitConfig{
+				BaseURL:     "https://example.com/",
+				DisplayName: "Gerrit 1",
+				Limit:       1.0,
+				IsDefault:   false,
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			rlc, err := ExtractRateLimitConfig(tc.config, tc.kind, tc.displayName)
@@ -258,6 +318,21 @@ func TestUniqueCodeHostIdentifier(t *testing.T) {
 			want:   "https://bitbucket.org/",
 		},
 
+		{
+			kind:   KindGitea,
+			config: `{"url": "https://gitea.sgdev.org"}`,
+			want:   "https://gitea.sgdev.org/",
+		},
+		{
+			kind:   KindAzureDevOps,
+			config: `{"url": "https://dev.azure.com"}`,
+			want:   "https://dev.azure.com/",
+		},
+		{
+			kind:   KindGerrit,
+			config: `{"url": "https://gerrit.sgdev.org/"}`,
+			want:   "https://gerrit.sgdev.org/",
+		},
 		{
 			kind:   KindGitolite,
 			config: `{"host": "ssh://git@gitolite.example.com:2222/"}`,
@@ -34,6 +34,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/requestid"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 	"github.com/sourcegraph/sourcegraph/internal/vcs"
 )
@@ -41,15 +42,18 @@ import (
 var requestMeter = metrics.NewRequestMeter("gitserver", "Total number of requests sent to gitserver.")
 
 // defaultTransport is the default transport used in the default client and the
-// default reverse proxy. ot.Transport will propagate opentracing spans.
+// default reverse proxy. ot.Transport will propagate opentracing spans, and
+// requestid.Transport the request ID.
 var defaultTransport = &ot.Transport{
-	RoundTripper: requestMeter.Transport(&http.Transport{
-		// Default is 2, but we can send many concurrent requests
-		MaxIdleConnsPerHost: 500,
-	}, func(u *url.URL) string {
-		// break it down by API function call (ie "/archive", "/exec", "/is-repo-cloneable", etc)
-		return u.Path
-	}),
+	RoundTripper: &requestid.Transport{
+		RoundTripper: requestMeter.Transport(&http.Transport{
+			// Default is 2, but we can send many concurrent requests
+			MaxIdleConnsPerHost: 500,
+		}, func(u *url.URL) string {
+			// break it down by API function call (ie "/archive", "/exec", "/is-repo-cloneable", etc)
+			return u.Path
+		}),
+	},
 }
 
 // DefaultClient is the default Client. Unless overwritten it is connected to servers specified by SRC_GIT_SERVERS.
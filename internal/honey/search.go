@@ -6,6 +6,7 @@ import (
 	"github.com/honeycombio/libhoney-go"
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/requestid"
 )
 
 type SearchEventArgs struct {
@@ -34,5 +35,6 @@ func SearchEvent(ctx context.Context, args SearchEventArgs) *libhoney.Event {
 	ev.AddField("alert_type", args.AlertType)
 	ev.AddField("duration_ms", args.DurationMs)
 	ev.AddField("result_size", args.ResultSize)
+	ev.AddField("request_id", requestid.FromContext(ctx))
 	return ev
 }
@@ -1,5 +1,13 @@
 package logging
 
+import (
+	"context"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/requestid"
+)
+
 // ErrorLogger captures the method required for logging an error.
 type ErrorLogger interface {
 	Error(msg string, ctx ...interface{})
@@ -13,3 +21,13 @@ func Log(lg ErrorLogger, msg string, err *error, ctx ...interface{}) {
 
 	lg.Error(msg, append(append(make([]interface{}, 0, 2+len(ctx)), "error", *err), ctx...)...)
 }
+
+// FromContext returns a logger that adds the request ID of the given context, if any, to all
+// of its log lines as "request_id". Log lines of all services handling the same request share
+// the request ID, so they can be found by searching for it.
+func FromContext(ctx context.Context) log15.Logger {
+	if id := requestid.FromContext(ctx); id != "" {
+		return log15.Root().New("request_id", id)
+	}
+	return log15.Root()
+}
@@ -111,7 +111,7 @@ func Init(options ...Option) {
 		// for these uses: https://cloud.google.com/run/docs/logging#log-resource
 		jsonFormatHandler := log15.StreamHandler(os.Stderr, log15.JsonFormat())
 		handler = log15.FuncHandler(func(r *log15.Record) error {
-			r.Ctx = append(r.Ctx, "severity", LogEntryLevelString(r.Lvl))
+			r.Ctx = append(r.Ctx, "severity", LogEntryLevelString(r.Lvl), "service", opts.serviceName)
 			return jsonFormatHandler.Log(r)
 		})
 	case "logfmt":
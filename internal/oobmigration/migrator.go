@@ -24,3 +24,12 @@ type Migrator interface {
 	// therefore do not need to be undone prior to a downgrade.
 	Down(ctx context.Context) error
 }
+
+// BatchSizer is implemented by migrators that process a configurable number of records on each
+// invocation of Up or Down. The batch size of these migrators can be overridden per migration
+// by site admins to slow down or speed up a running migration.
+type BatchSizer interface {
+	// SetBatchSize changes the number of records processed on each subsequent invocation. A
+	// non-positive value restores the batch size the migrator was constructed with.
+	SetBatchSize(batchSize int)
+}
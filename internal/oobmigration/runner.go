@@ -62,11 +62,15 @@ var ErrMigratorConflict = errors.New("migrator already registered")
 
 // MigratorOptions configures the behavior of a registered migrator.
 type MigratorOptions struct {
-	// Interval specifies the time between invocations of an active migration.
+	// Interval specifies the time between invocations of an active migration. This value
+	// can be overridden per migration by site admins.
 	Interval time.Duration
 
 	// ticker mocks periodic behavior for tests.
 	ticker glock.Ticker
+
+	// newTicker mocks the creation of a ticker when the interval is overridden for tests.
+	newTicker func(interval time.Duration) glock.Ticker
 }
 
 // Register correlates the given migrator with the given migration identifier. An error is
@@ -79,12 +83,17 @@ func (r *Runner) Register(id int, migrator Migrator, options MigratorOptions) er
 	if options.Interval == 0 {
 		options.Interval = time.Second
 	}
+	if options.newTicker == nil {
+		options.newTicker = glock.NewRealTicker
+	}
 	if options.ticker == nil {
-		options.ticker = glock.NewRealTicker(options.Interval)
+		options.ticker = options.newTicker(options.Interval)
 	}
 
 	r.migrators[id] = migratorAndOption{migrator, migratorOptions{
-		ticker: options.ticker,
+		interval:  options.Interval,
+		ticker:    options.ticker,
+		newTicker: options.newTicker,
 	}}
 	return nil
 }
@@ -290,12 +299,15 @@ func (r *Runner) Stop() {
 }
 
 type migratorOptions struct {
-	ticker glock.Ticker
+	interval  time.Duration
+	ticker    glock.Ticker
+	newTicker func(interval time.Duration) glock.Ticker
 }
 
 // runMigrator runs the given migrator function periodically (on each read from ticker)
-// while the migration is not complete. We will periodically (on each read from migrations)
-// update our current view of the migration progress and (more importantly) its direction.
+// while the migration is not complete or paused. We will periodically (on each read from
+// migrations) update our current view of the migration progress, its direction, and the
+// rate limits configured for it.
 func runMigrator(ctx context.Context, store storeIface, migrator Migrator, migrations <-chan Migration, options migratorOptions, operations *operations) {
 	// Get initial migration. This channel will close when the context
 	// is canceled, so we don't need to do any more complex select here.
@@ -304,6 +316,10 @@ func runMigrator(ctx context.Context, store storeIface, migrator Migrator, migra
 		return
 	}
 
+	limiter := newRateLimiter(migrator, options)
+	defer limiter.stop()
+	limiter.apply(migration)
+
 	// We're just starting up - refresh our progress before migrating
 	if err := updateProgress(ctx, store, &migration, migrator); err != nil {
 		log15.Error("Failed to determine migration progress", "migrationID", migration.ID, "error", err)
@@ -319,9 +335,11 @@ func runMigrator(ctx context.Context, store storeIface, migrator Migrator, migra
 				log15.Error("Failed to determine migration progress", "migrationID", migration.ID, "error", err)
 			}
 
-		case <-options.ticker.Chan():
-			if !migration.Complete() {
-				// Run the migration only if there's something left to do
+			limiter.apply(migration)
+
+		case <-limiter.ticker.Chan():
+			if !migration.Paused && !migration.Complete() {
+				// Run the migration only if it's not paused and there's something left to do
 				if err := runMigrationFunction(ctx, store, &migration, migrator, operations); err != nil {
 					log15.Error("Failed migration action", "migrationID", migration.ID, "error", err)
 				}
@@ -333,6 +351,50 @@ func runMigrator(ctx context.Context, store storeIface, migrator Migrator, migra
 	}
 }
 
+// rateLimiter applies the batch size and interval overrides of a migration record to a
+// running migrator, falling back to the values the migrator was registered with.
+type rateLimiter struct {
+	migrator Migrator
+	options  migratorOptions
+	interval time.Duration
+	ticker   glock.Ticker
+}
+
+func newRateLimiter(migrator Migrator, options migratorOptions) *rateLimiter {
+	return &rateLimiter{
+		migrator: migrator,
+		options:  options,
+		interval: options.interval,
+		ticker:   options.ticker,
+	}
+}
+
+// apply updates the batch size of the migrator and the ticker controlling the time between
+// invocations of the migrator to match the given migration record.
+func (l *rateLimiter) apply(migration Migration) {
+	if sizer, ok := l.migrator.(BatchSizer); ok {
+		batchSize := 0
+		if migration.BatchSize != nil {
+			batchSize = *migration.BatchSize
+		}
+		sizer.SetBatchSize(batchSize)
+	}
+
+	interval := l.options.interval
+	if migration.BatchInterval != nil {
+		interval = *migration.BatchInterval
+	}
+	if interval != l.interval {
+		l.ticker.Stop()
+		l.ticker = l.options.newTicker(interval)
+		l.interval = interval
+	}
+}
+
+func (l *rateLimiter) stop() {
+	l.ticker.Stop()
+}
+
 // runMigrationFunction invokes the Up or Down method on the given migrator depending on the migration
 // direction. If an error occurs, it will be associated in the database with the migration record.
 // Regardless of the success of the migration function, the progress function on the migrator will be
@@ -286,6 +286,90 @@ func TestRunMigratorMigrationDesyncedFromData(t *testing.T) {
 	}
 }
 
+func TestRunMigratorPaused(t *testing.T) {
+	store := NewMockStoreIface()
+	ticker := glock.NewMockTicker(time.Second)
+
+	migrator := NewMockMigrator()
+	migrator.ProgressFunc.SetDefaultReturn(0.5, nil)
+
+	runMigratorWrapped(store, migrator, ticker, func(migrations chan<- Migration) {
+		migrations <- Migration{ID: 1, Progress: 0.5, Paused: true}
+		tickN(ticker, 5)
+		migrations <- Migration{ID: 1, Progress: 0.5, Paused: false}
+		tickN(ticker, 3)
+	})
+
+	if callCount := len(migrator.UpFunc.History()); callCount != 3 {
+		t.Errorf("unexpected number of calls to Up. want=%d have=%d", 3, callCount)
+	}
+	if callCount := len(migrator.DownFunc.History()); callCount != 0 {
+		t.Errorf("unexpected number of calls to Down. want=%d have=%d", 0, callCount)
+	}
+}
+
+func TestRunMigratorBatchSize(t *testing.T) {
+	store := NewMockStoreIface()
+	ticker := glock.NewMockTicker(time.Second)
+
+	migrator := &batchSizerMigrator{MockMigrator: NewMockMigrator()}
+	migrator.ProgressFunc.SetDefaultReturn(0.5, nil)
+
+	batchSize := 10
+	runMigratorWrapped(store, migrator, ticker, func(migrations chan<- Migration) {
+		migrations <- Migration{ID: 1, Progress: 0.5, BatchSize: &batchSize}
+		tickN(ticker, 1)
+		migrations <- Migration{ID: 1, Progress: 0.5}
+		tickN(ticker, 1)
+	})
+
+	if diff := cmp.Diff([]int{10, 0}, migrator.batchSizes); diff != "" {
+		t.Errorf("unexpected batch sizes (-want +got):\n%s", diff)
+	}
+}
+
+func TestRunMigratorBatchInterval(t *testing.T) {
+	store := NewMockStoreIface()
+	ticker := glock.NewMockTicker(time.Second)
+	overrideTicker := glock.NewMockTicker(time.Second)
+
+	migrator := NewMockMigrator()
+	migrator.ProgressFunc.SetDefaultReturn(0.5, nil)
+
+	var intervals []time.Duration
+	options := migratorOptions{
+		interval: time.Second,
+		ticker:   ticker,
+		newTicker: func(interval time.Duration) glock.Ticker {
+			intervals = append(intervals, interval)
+			return overrideTicker
+		},
+	}
+
+	batchInterval := time.Minute
+	runMigratorWrappedWithOptions(store, migrator, options, func(migrations chan<- Migration) {
+		migrations <- Migration{ID: 1, Progress: 0.5, BatchInterval: &batchInterval}
+		tickN(overrideTicker, 3)
+	})
+
+	if diff := cmp.Diff([]time.Duration{time.Minute}, intervals); diff != "" {
+		t.Errorf("unexpected ticker intervals (-want +got):\n%s", diff)
+	}
+	if callCount := len(migrator.UpFunc.History()); callCount != 3 {
+		t.Errorf("unexpected number of calls to Up. want=%d have=%d", 3, callCount)
+	}
+}
+
+// batchSizerMigrator is a mock migrator that records its batch size overrides.
+type batchSizerMigrator struct {
+	*MockMigrator
+	batchSizes []int
+}
+
+func (m *batchSizerMigrator) SetBatchSize(batchSize int) {
+	m.batchSizes = append(m.batchSizes, batchSize)
+}
+
 // runMigratorWrapped creates a migrations channel, then passes it to both the runMigrator
 // function and the given interact function, which execute concurrently. This channel can
 // control the behavior of the migration controller from within the interact function.
@@ -293,6 +377,12 @@ func TestRunMigratorMigrationDesyncedFromData(t *testing.T) {
 // This method blocks until both functions return. The return of the interact function
 // cancels a context controlling the runMigrator main loop.
 func runMigratorWrapped(store storeIface, migrator Migrator, ticker glock.Ticker, interact func(migrations chan<- Migration)) {
+	runMigratorWrappedWithOptions(store, migrator, migratorOptions{ticker: ticker}, interact)
+}
+
+// runMigratorWrappedWithOptions behaves like runMigratorWrapped but runs the migrator with
+// the given options.
+func runMigratorWrappedWithOptions(store storeIface, migrator Migrator, options migratorOptions, interact func(migrations chan<- Migration)) {
 	ctx, cancel := context.WithCancel(context.Background())
 	migrations := make(chan Migration)
 
@@ -307,7 +397,7 @@ func runMigratorWrapped(store storeIface, migrator Migrator, ticker glock.Ticker
 			store,
 			migrator,
 			migrations,
-			migratorOptions{ticker: ticker},
+			options,
 			newOperations(&observation.TestContext),
 		)
 	}()
@@ -26,6 +26,9 @@ type Migration struct {
 	LastUpdated    *time.Time
 	NonDestructive bool
 	ApplyReverse   bool
+	Paused         bool
+	BatchSize      *int
+	BatchInterval  *time.Duration
 	Errors         []MigrationError
 }
 
@@ -61,6 +64,7 @@ func scanMigrations(rows *sql.Rows, queryErr error) (_ []Migration, err error) {
 		var message string
 		var created *time.Time
 		var deprecatedMajor, deprecatedMinor *int
+		var batchIntervalMs *int
 		value := Migration{Errors: []MigrationError{}}
 
 		if err := rows.Scan(
@@ -77,6 +81,9 @@ func scanMigrations(rows *sql.Rows, queryErr error) (_ []Migration, err error) {
 			&value.LastUpdated,
 			&value.NonDestructive,
 			&value.ApplyReverse,
+			&value.Paused,
+			&value.BatchSize,
+			&batchIntervalMs,
 			&dbutil.NullString{S: &message},
 			&created,
 		); err != nil {
@@ -97,6 +104,11 @@ func scanMigrations(rows *sql.Rows, queryErr error) (_ []Migration, err error) {
 			}
 		}
 
+		if batchIntervalMs != nil {
+			batchInterval := time.Duration(*batchIntervalMs) * time.Millisecond
+			value.BatchInterval = &batchInterval
+		}
+
 		if n := len(values); n > 0 && values[n-1].ID == value.ID {
 			values[n-1].Errors = append(values[n-1].Errors, value.Errors...)
 		} else {
@@ -169,6 +181,9 @@ SELECT
 	m.last_updated,
 	m.non_destructive,
 	m.apply_reverse,
+	m.paused,
+	m.batch_size,
+	m.batch_interval_ms,
 	e.message,
 	e.created
 FROM out_of_band_migrations m
@@ -211,6 +226,9 @@ SELECT
 	m.last_updated,
 	m.non_destructive,
 	m.apply_reverse,
+	m.paused,
+	m.batch_size,
+	m.batch_interval_ms,
 	e.message,
 	e.created
 FROM out_of_band_migrations m
@@ -229,6 +247,45 @@ const updateDirectionQuery = `
 UPDATE out_of_band_migrations SET apply_reverse = %s WHERE id = %s
 `
 
+// UpdatePaused pauses or resumes the given migration. A paused migration makes no progress in
+// either direction until it is resumed.
+func (s *Store) UpdatePaused(ctx context.Context, id int, paused bool) error {
+	return s.Store.Exec(ctx, sqlf.Sprintf(updatePausedQuery, paused, id))
+}
+
+const updatePausedQuery = `
+-- source: internal/oobmigration/store.go:UpdatePaused
+UPDATE out_of_band_migrations SET paused = %s WHERE id = %s
+`
+
+// UpdateRateLimit updates the batch size and the interval between batches of the given migration.
+// A nil value resets the setting to the default of the migrator.
+func (s *Store) UpdateRateLimit(ctx context.Context, id int, batchSize *int, batchInterval *time.Duration) error {
+	var batchIntervalMs *int
+	if batchInterval != nil {
+		ms := int(*batchInterval / time.Millisecond)
+		batchIntervalMs = &ms
+	}
+
+	return s.Store.Exec(ctx, sqlf.Sprintf(updateRateLimitQuery, batchSize, batchIntervalMs, id))
+}
+
+const updateRateLimitQuery = `
+-- source: internal/oobmigration/store.go:UpdateRateLimit
+UPDATE out_of_band_migrations SET batch_size = %s, batch_interval_ms = %s WHERE id = %s
+`
+
+// Rollback sets the given migration to run in reverse until no migrated records remain, and
+// resumes it if it was paused.
+func (s *Store) Rollback(ctx context.Context, id int) error {
+	return s.Store.Exec(ctx, sqlf.Sprintf(rollbackQuery, id))
+}
+
+const rollbackQuery = `
+-- source: internal/oobmigration/store.go:Rollback
+UPDATE out_of_band_migrations SET apply_reverse = true, paused = false WHERE id = %s
+`
+
 // UpdateProgress updates the progress for the given migration.
 func (s *Store) UpdateProgress(ctx context.Context, id int, progress float64) error {
 	return s.updateProgress(ctx, id, progress, time.Now())
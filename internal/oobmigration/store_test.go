@@ -95,6 +95,96 @@ func TestUpdateDirection(t *testing.T) {
 	}
 }
 
+func TestUpdatePaused(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := testStore(t, db)
+
+	if err := store.UpdatePaused(context.Background(), 3, true); err != nil {
+		t.Fatalf("unexpected error pausing migration: %s", err)
+	}
+
+	migration, exists, err := store.GetByID(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("unexpected error getting migrations: %s", err)
+	}
+	if !exists {
+		t.Fatalf("expected record to exist")
+	}
+
+	expectedMigration := testMigrations[2] // ID = 3
+	expectedMigration.Paused = true
+
+	if diff := cmp.Diff(expectedMigration, migration); diff != "" {
+		t.Errorf("unexpected migration (-want +got):\n%s", diff)
+	}
+}
+
+func TestUpdateRateLimit(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := testStore(t, db)
+
+	batchSize := 25
+	batchInterval := 5 * time.Second
+
+	if err := store.UpdateRateLimit(context.Background(), 3, &batchSize, &batchInterval); err != nil {
+		t.Fatalf("unexpected error updating rate limit: %s", err)
+	}
+
+	migration, exists, err := store.GetByID(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("unexpected error getting migrations: %s", err)
+	}
+	if !exists {
+		t.Fatalf("expected record to exist")
+	}
+
+	expectedMigration := testMigrations[2] // ID = 3
+	expectedMigration.BatchSize = &batchSize
+	expectedMigration.BatchInterval = &batchInterval
+
+	if diff := cmp.Diff(expectedMigration, migration); diff != "" {
+		t.Errorf("unexpected migration (-want +got):\n%s", diff)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := testStore(t, db)
+
+	if err := store.UpdatePaused(context.Background(), 3, true); err != nil {
+		t.Fatalf("unexpected error pausing migration: %s", err)
+	}
+
+	if err := store.Rollback(context.Background(), 3); err != nil {
+		t.Fatalf("unexpected error rolling back migration: %s", err)
+	}
+
+	migration, exists, err := store.GetByID(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("unexpected error getting migrations: %s", err)
+	}
+	if !exists {
+		t.Fatalf("expected record to exist")
+	}
+
+	expectedMigration := testMigrations[2] // ID = 3
+	expectedMigration.ApplyReverse = true
+	expectedMigration.Paused = false
+
+	if diff := cmp.Diff(expectedMigration, migration); diff != "" {
+		t.Errorf("unexpected migration (-want +got):\n%s", diff)
+	}
+}
+
 func TestUpdateProgress(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
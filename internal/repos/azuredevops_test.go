@@ -0,0 +1,228 @@
+package repos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+	"github.com/sourcegraph/sourcegraph/internal/testutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestAzureDevOpsSource_ListRepos(t *testing.T) {
+	assertAllReposListed := func(want []string) types.ReposAssertion {
+		return func(t testing.TB, rs types.Repos) {
+			t.Helper()
+
+			have := rs.Names()
+			sort.Strings(have)
+			sort.Strings(want)
+
+			if diff := cmp.Diff(want, have); diff != "" {
+				t.Errorf("Mismatch (-want +got):\n%s", diff)
+			}
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		assert types.ReposAssertion
+		conf   *schema.AzureDevOpsConnection
+		err    string
+	}{
+		{
+			name: "orgs",
+			assert: assertAllReposListed([]string{
+				"dev.azure.com/sgtest/sourcegraph/sourcegraph",
+				"dev.azure.com/sgtest/sourcegraph/src-cli",
+			}),
+			conf: &schema.AzureDevOpsConnection{
+				Url:   "https://dev.azure.com",
+				Token: os.Getenv("AZURE_DEVOPS_TOKEN"),
+				Orgs:  []string{"sgtest"},
+			},
+			err: "<nil>",
+		},
+		{
+			name: "projects",
+			assert: assertAllReposListed([]string{
+				"dev.azure.com/sgtest/sourcegraph/sourcegraph",
+			}),
+			conf: &schema.AzureDevOpsConnection{
+				Url:   "https://dev.azure.com",
+				Token: os.Getenv("AZURE_DEVOPS_TOKEN"),
+				Projects: []string{
+					"sgtest/sourcegraph",
+					"nope/does-not-exist",
+				},
+				Exclude: []*schema.ExcludedAzureDevOpsRepo{
+					{Name: "sgtest/sourcegraph/src-cli"},
+				},
+			},
+			err: "<nil>",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		tc.name = "AZUREDEVOPS-LIST-REPOS/" + tc.name
+		t.Run(tc.name, func(t *testing.T) {
+			cf, save := newClientFactory(t, tc.name)
+			defer save(t)
+
+			svc := &types.ExternalService{
+				Kind:   extsvc.KindAzureDevOps,
+				Config: marshalJSON(t, tc.conf),
+			}
+
+			src, err := newAzureDevOpsSource(svc, tc.conf, cf)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			repos, err := listAll(context.Background(), src)
+
+			if have, want := fmt.Sprint(err), tc.err; have != want {
+				t.Errorf("error:\nhave: %q\nwant: %q", have, want)
+			}
+
+			if tc.assert != nil {
+				tc.assert(t, repos)
+			}
+		})
+	}
+}
+
+func TestAzureDevOpsSource_makeRepo(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("testdata", "azuredevops-repos.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var repos []*azuredevops.Repository
+	if err := json.Unmarshal(b, &repos); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := types.ExternalService{ID: 1, Kind: extsvc.KindAzureDevOps}
+
+	tests := []struct {
+		name   string
+		schema *schema.AzureDevOpsConnection
+	}{
+		{
+			name: "simple",
+			schema: &schema.AzureDevOpsConnection{
+				Url:   "https://dev.azure.com",
+				Token: "secret",
+				Orgs:  []string{"sg"},
+			},
+		}, {
+			name: "ssh",
+			schema: &schema.AzureDevOpsConnection{
+				Url:        "https://dev.azure.com",
+				Token:      "secret",
+				Orgs:       []string{"sg"},
+				GitURLType: "ssh",
+			},
+		}, {
+			name: "path-pattern",
+			schema: &schema.AzureDevOpsConnection{
+				Url:                   "https://dev.azure.com",
+				Token:                 "secret",
+				Orgs:                  []string{"sg"},
+				RepositoryPathPattern: "ado/{project}/{repo}",
+			},
+		},
+	}
+	for _, test := range tests {
+		test.name = "AzureDevOpsSource_makeRepo_" + test.name
+		t.Run(test.name, func(t *testing.T) {
+			s, err := newAzureDevOpsSource(&svc, test.schema, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []*types.Repo
+			for _, r := range repos {
+				got = append(got, s.makeRepo(r))
+			}
+
+			testutil.AssertGolden(t, "testdata/golden/"+test.name, update(test.name), got)
+		})
+	}
+}
+
+func TestAzureDevOpsSource_Exclude(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("testdata", "azuredevops-repos.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var repos []*azuredevops.Repository
+	if err := json.Unmarshal(b, &repos); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]*schema.AzureDevOpsConnection{
+		"none": {
+			Url:   "https://dev.azure.com",
+			Token: "secret",
+		},
+		"name": {
+			Url:   "https://dev.azure.com",
+			Token: "secret",
+			Exclude: []*schema.ExcludedAzureDevOpsRepo{
+				{Name: "SG/Sourcegraph/Sourcegraph"},
+			},
+		},
+		"id": {
+			Url:   "https://dev.azure.com",
+			Token: "secret",
+			Exclude: []*schema.ExcludedAzureDevOpsRepo{
+				{Id: "3a9d2c7b-1e5f-4c8a-9b6d-7e0f1a2b3c4d"},
+			},
+		},
+		"pattern": {
+			Url:   "https://dev.azure.com",
+			Token: "secret",
+			Exclude: []*schema.ExcludedAzureDevOpsRepo{
+				{Pattern: "^sg/Open Source/"},
+			},
+		},
+	}
+
+	svc := types.ExternalService{ID: 1, Kind: extsvc.KindAzureDevOps}
+
+	for name, config := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, err := newAzureDevOpsSource(&svc, config, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			type output struct {
+				Include []string
+				Exclude []string
+			}
+			var got output
+			for _, r := range repos {
+				if s.excludes(r) {
+					got.Exclude = append(got.Exclude, r.NameWithProject())
+				} else {
+					got.Include = append(got.Include, r.NameWithProject())
+				}
+			}
+
+			path := filepath.Join("testdata", "azuredevops-repos-exclude-"+name+".golden")
+			testutil.AssertGolden(t, path, update(name), got)
+		})
+	}
+}
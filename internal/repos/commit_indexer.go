@@ -0,0 +1,289 @@
+package repos
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// commitIndexBatchSize is the number of commits fetched from gitserver and
+// written to the commit index at a time.
+const commitIndexBatchSize = 500
+
+// commitIndexUnindexedBatchSize is the number of repositories without an
+// index which are indexed per minute.
+const commitIndexUnindexedBatchSize = 100
+
+// CommitIndexer is a worker which keeps the commit index used by commit and
+// diff searches in sync with the default branch of every cloned repository.
+// It only does work while the commitIndex experimental feature is enabled.
+//
+// Repositories are indexed when the update scheduler fetched them, and once
+// they are cloned, so that the indexer doesn't need to poll every repository.
+// New commits are appended to the index of a repository. If its history was
+// rewritten or its default branch changed, the repository is indexed again
+// from scratch.
+type CommitIndexer struct {
+	store *database.CommitIndexStore
+	log   log15.Logger
+
+	mu     sync.Mutex
+	queued map[api.RepoID]types.RepoName
+	notify chan struct{}
+}
+
+// NewCommitIndexer returns a new commit indexer. Run must be called to start
+// indexing.
+func NewCommitIndexer(db dbutil.DB) *CommitIndexer {
+	return &CommitIndexer{
+		store:  database.CommitIndex(db),
+		log:    log15.Root().New("worker", "commit-index"),
+		queued: map[api.RepoID]types.RepoName{},
+		notify: make(chan struct{}, notifyChanBuffer),
+	}
+}
+
+// Enqueue schedules the index of repo to be brought up to date, because it
+// was fetched.
+func (c *CommitIndexer) Enqueue(repo types.RepoName) {
+	if !conf.CommitIndexEnabled() {
+		return
+	}
+
+	c.mu.Lock()
+	c.queued[repo.ID] = repo
+	c.mu.Unlock()
+
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Run indexes enqueued repositories, and looks for cloned repositories
+// without an index once a minute, until ctx is canceled.
+func (c *CommitIndexer) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.notify:
+		case <-ticker.C:
+			if conf.CommitIndexEnabled() {
+				c.enqueueUnindexed(ctx)
+			}
+		case <-ctx.Done():
+			return
+		}
+
+		if conf.CommitIndexEnabled() {
+			c.indexQueued(ctx)
+		}
+	}
+}
+
+func (c *CommitIndexer) enqueueUnindexed(ctx context.Context) {
+	repos, err := c.store.ListUnindexedRepos(ctx, commitIndexUnindexedBatchSize)
+	if err != nil {
+		c.log.Error("failed to list unindexed repos", "error", err)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, repo := range repos {
+		c.queued[repo.ID] = repo
+	}
+}
+
+func (c *CommitIndexer) indexQueued(ctx context.Context) {
+	c.mu.Lock()
+	queued := c.queued
+	c.queued = map[api.RepoID]types.RepoName{}
+	c.mu.Unlock()
+
+	maxCommits := conf.CommitIndexMaxCommitsPerRepo()
+	updated := 0
+	failed := 0
+	for _, repo := range queued {
+		if ctx.Err() != nil {
+			return
+		}
+
+		ok, err := indexRepoCommits(ctx, c.store, repo, maxCommits)
+		if err != nil {
+			c.log.Error("failed to index commits", "repo", repo.Name, "error", err)
+			commitIndexFailed.Inc()
+			failed++
+			continue
+		}
+		if ok {
+			commitIndexSuccess.Inc()
+			updated++
+		}
+	}
+
+	// If we did something we log with a higher level.
+	statusLogger := c.log.Debug
+	if updated > 0 || failed > 0 {
+		statusLogger = c.log.Info
+	}
+	statusLogger("commit index update finished", "repos", len(queued), "updated", updated, "failed", failed)
+}
+
+// indexRepoCommits brings the commit index of repo up to date with its HEAD,
+// keeping at most maxCommits commits. It reports whether the index changed.
+//
+// Commits are written in batches rather than in a single transaction, and the
+// state of the index is only updated once all of them are written. Searches
+// don't use the index until then, because HEAD is not the indexed commit.
+func indexRepoCommits(ctx context.Context, store *database.CommitIndexStore, repo types.RepoName, maxCommits int) (updated bool, err error) {
+	head, err := git.ResolveRevision(ctx, repo.Name, "HEAD", git.ResolveRevisionOptions{NoEnsureRevision: true})
+	if err != nil {
+		if errors.HasType(err, &gitserver.RevisionNotFoundError{}) {
+			// Empty repository.
+			return false, nil
+		}
+		return false, err
+	}
+	headRef, err := resolveHEADRef(ctx, repo)
+	if err != nil {
+		return false, err
+	}
+
+	state, err := store.GetState(ctx, repo.ID)
+	if err != nil {
+		return false, err
+	}
+	if state != nil && state.HeadRef == headRef && state.IndexedCommit == head {
+		return false, nil
+	}
+
+	if state != nil && state.HeadRef == headRef {
+		// If the indexed commit is an ancestor of HEAD we only need to add
+		// the new commits. Otherwise the history was rewritten, or the
+		// indexed commit no longer exists, and we start over.
+		if base, err := git.MergeBase(ctx, repo.Name, state.IndexedCommit, head); err == nil && base == state.IndexedCommit {
+			return true, appendRepoCommits(ctx, store, repo, state, head, maxCommits)
+		}
+	}
+
+	if err := store.Delete(ctx, repo.ID); err != nil {
+		return false, err
+	}
+	state = &database.CommitIndexState{RepoID: repo.ID, HeadRef: headRef}
+	return true, appendRepoCommits(ctx, store, repo, state, head, maxCommits)
+}
+
+// appendRepoCommits appends the commits reachable from head but not from
+// state.IndexedCommit to the index, trims the index to maxCommits commits and
+// updates state. If state.IndexedCommit is empty all commits reachable from
+// head are appended.
+func appendRepoCommits(ctx context.Context, store *database.CommitIndexStore, repo types.RepoName, state *database.CommitIndexState, head api.CommitID, maxCommits int) error {
+	rangeSpec := string(head)
+	if state.IndexedCommit != "" {
+		rangeSpec = string(state.IndexedCommit) + ".." + string(head)
+	}
+
+	// Remove the commits of a previous update which failed part way.
+	if err := store.DeleteFromSeq(ctx, repo.ID, state.NextSeq); err != nil {
+		return err
+	}
+
+	// git log lists the newest commits first, so the first commit gets the
+	// largest sequence number. We don't know how many commits there are up
+	// front, so we reserve maxCommits sequence numbers.
+	seq := state.NextSeq + int64(maxCommits) - 1
+	fetched := 0
+	for fetched < maxCommits {
+		n := commitIndexBatchSize
+		if rest := maxCommits - fetched; rest < n {
+			n = rest
+		}
+		commits, err := git.CommitsWithDiffs(ctx, repo.Name, git.CommitsOptions{
+			Range:            rangeSpec,
+			N:                uint(n),
+			Skip:             uint(fetched),
+			NoEnsureRevision: true,
+		})
+		if err != nil {
+			return err
+		}
+
+		entries := make([]*database.CommitIndexEntry, 0, len(commits))
+		for _, c := range commits {
+			entries = append(entries, commitIndexEntry(c, seq))
+			seq--
+		}
+		if err := store.Append(ctx, repo.ID, entries); err != nil {
+			return err
+		}
+
+		fetched += len(commits)
+		if len(commits) < n {
+			break
+		}
+	}
+
+	trimmed, err := store.Trim(ctx, repo.ID, maxCommits)
+	if err != nil {
+		return err
+	}
+
+	if state.IndexedCommit == "" {
+		state.HistoryComplete = fetched < maxCommits
+	} else {
+		state.HistoryComplete = state.HistoryComplete && fetched < maxCommits && trimmed == 0
+	}
+	state.IndexedCommit = head
+	state.NextSeq += int64(maxCommits)
+	return store.UpdateState(ctx, state)
+}
+
+func commitIndexEntry(c *git.CommitWithDiff, seq int64) *database.CommitIndexEntry {
+	e := &database.CommitIndexEntry{
+		CommitID:    c.ID,
+		Seq:         seq,
+		AuthorName:  c.Author.Name,
+		AuthorEmail: c.Author.Email,
+		AuthorDate:  c.Author.Date,
+		Message:     string(c.Message),
+	}
+	if c.Committer != nil {
+		e.CommitterName = c.Committer.Name
+		e.CommitterEmail = c.Committer.Email
+		e.CommitterDate = c.Committer.Date
+	}
+	for _, p := range c.Parents {
+		e.Parents = append(e.Parents, string(p))
+	}
+	if c.Diff != nil {
+		e.Diff = c.Diff.Raw
+	}
+	return e
+}
+
+// resolveHEADRef returns the full name of the ref HEAD points to.
+func resolveHEADRef(ctx context.Context, repo types.RepoName) (string, error) {
+	stdout, stderr, exitCode, err := git.ExecSafe(ctx, repo.Name, []string{"rev-parse", "--symbolic-full-name", "HEAD"})
+	if err != nil {
+		return "", err
+	}
+	if exitCode != 0 {
+		return "", errors.Errorf("resolving HEAD of %s failed (exit code %d): %s", repo.Name, exitCode, bytes.TrimSpace(stderr))
+	}
+	return string(bytes.TrimSpace(stdout)), nil
+}
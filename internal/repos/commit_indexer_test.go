@@ -0,0 +1,130 @@
+package repos
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestIndexRepoCommits(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := context.Background()
+	db := dbtest.NewDB(t, "")
+
+	repo := &types.Repo{Name: "github.com/sourcegraph/repo", URI: "github.com/sourcegraph/repo"}
+	if err := database.Repos(db).Create(ctx, repo); err != nil {
+		t.Fatal(err)
+	}
+	repoName := types.RepoName{ID: repo.ID, Name: repo.Name}
+
+	// history maps each commit to its parent.
+	history := map[api.CommitID]api.CommitID{"c2": "c1", "c3": "c2", "c4": "c3", "x3": "c2"}
+	commit := func(id api.CommitID) *git.CommitWithDiff {
+		date := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+		return &git.CommitWithDiff{
+			Commit: &git.Commit{
+				ID:        id,
+				Author:    git.Signature{Name: "a", Email: "a@a.com", Date: date},
+				Committer: &git.Signature{Name: "a", Email: "a@a.com", Date: date},
+				Message:   git.Message("commit " + id),
+			},
+			Diff: &git.RawDiff{Raw: "diff --git f f\n+" + string(id) + "\n"},
+		}
+	}
+
+	var head api.CommitID
+	git.Mocks.ResolveRevision = func(spec string, opt git.ResolveRevisionOptions) (api.CommitID, error) {
+		return head, nil
+	}
+	git.Mocks.ExecSafe = func(params []string) ([]byte, []byte, int, error) {
+		return []byte("refs/heads/main\n"), nil, 0, nil
+	}
+	git.Mocks.MergeBase = func(repo api.RepoName, a, b api.CommitID) (api.CommitID, error) {
+		for c := b; c != ""; c = history[c] {
+			if c == a {
+				return a, nil
+			}
+		}
+		return "c2", nil
+	}
+	git.Mocks.CommitsWithDiffs = func(repo api.RepoName, opt git.CommitsOptions) ([]*git.CommitWithDiff, error) {
+		var exclude, tip api.CommitID = "", api.CommitID(opt.Range)
+		for i := 0; i < len(opt.Range)-1; i++ {
+			if opt.Range[i:i+2] == ".." {
+				exclude, tip = api.CommitID(opt.Range[:i]), api.CommitID(opt.Range[i+2:])
+			}
+		}
+		var commits []*git.CommitWithDiff
+		for c := tip; c != "" && c != exclude; c = history[c] {
+			commits = append(commits, commit(c))
+		}
+		if int(opt.Skip) >= len(commits) {
+			return nil, nil
+		}
+		commits = commits[opt.Skip:]
+		if opt.N != 0 && int(opt.N) < len(commits) {
+			commits = commits[:opt.N]
+		}
+		return commits, nil
+	}
+	t.Cleanup(git.ResetMocks)
+
+	store := database.CommitIndex(db)
+	indexed := func() []api.CommitID {
+		entries, err := store.Search(ctx, database.CommitIndexSearchOptions{RepoID: repo.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []api.CommitID
+		for _, e := range entries {
+			ids = append(ids, e.CommitID)
+		}
+		return ids
+	}
+
+	for _, step := range []struct {
+		name         string
+		head         api.CommitID
+		wantUpdated  bool
+		wantCommits  []api.CommitID
+		wantComplete bool
+	}{
+		{name: "initial", head: "c2", wantUpdated: true, wantCommits: []api.CommitID{"c2", "c1"}, wantComplete: true},
+		{name: "up to date", head: "c2", wantCommits: []api.CommitID{"c2", "c1"}, wantComplete: true},
+		{name: "append", head: "c3", wantUpdated: true, wantCommits: []api.CommitID{"c3", "c2", "c1"}, wantComplete: true},
+		{name: "append and trim", head: "c4", wantUpdated: true, wantCommits: []api.CommitID{"c4", "c3", "c2"}},
+		{name: "history rewritten", head: "x3", wantUpdated: true, wantCommits: []api.CommitID{"x3", "c2", "c1"}},
+	} {
+		t.Run(step.name, func(t *testing.T) {
+			head = step.head
+			updated, err := indexRepoCommits(ctx, store, repoName, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if updated != step.wantUpdated {
+				t.Errorf("got updated %v, want %v", updated, step.wantUpdated)
+			}
+			if diff := cmp.Diff(step.wantCommits, indexed()); diff != "" {
+				t.Errorf("unexpected indexed commits (-want +have):\n%s", diff)
+			}
+
+			state, err := store.GetState(ctx, repo.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if state.IndexedCommit != step.head || state.HeadRef != "refs/heads/main" || state.HistoryComplete != step.wantComplete {
+				t.Errorf("unexpected state %+v", state)
+			}
+		})
+	}
+}
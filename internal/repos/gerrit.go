@@ -0,0 +1,168 @@
+package repos
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A GerritSource yields repositories from a single Gerrit connection configured
+// in Sourcegraph via the external services configuration.
+type GerritSource struct {
+	svc     *types.ExternalService
+	config  *schema.GerritConnection
+	exclude excludeFunc
+	client  *gerrit.Client
+	host    *url.URL
+
+	// perPage is the page size used when listing projects.
+	perPage int
+}
+
+// NewGerritSource returns a new GerritSource from the given external service.
+func NewGerritSource(svc *types.ExternalService, cf *httpcli.Factory) (*GerritSource, error) {
+	var c schema.GerritConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	return newGerritSource(svc, &c, cf)
+}
+
+func newGerritSource(svc *types.ExternalService, c *schema.GerritConnection, cf *httpcli.Factory) (*GerritSource, error) {
+	baseURL, err := url.Parse(c.Url)
+	if err != nil {
+		return nil, err
+	}
+	baseURL = extsvc.NormalizeBaseURL(baseURL)
+
+	if cf == nil {
+		cf = httpcli.NewExternalHTTPClientFactory()
+	}
+
+	cli, err := cf.Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	var eb excludeBuilder
+	for _, r := range c.Exclude {
+		eb.Exact(r.Name)
+		eb.Pattern(r.Pattern)
+	}
+	exclude, err := eb.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	client := gerrit.NewClient(baseURL, cli)
+	client.Username = c.Username
+	client.Password = c.Password
+
+	return &GerritSource{
+		svc:     svc,
+		config:  c,
+		exclude: exclude,
+		client:  client,
+		host:    baseURL,
+		perPage: 100,
+	}, nil
+}
+
+// ListRepos returns all Gerrit projects accessible to all connections configured
+// in Sourcegraph via the external services configuration.
+func (s GerritSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	yield := func(p *gerrit.Project) {
+		if !s.excludes(p) {
+			results <- SourceResult{Source: s, Repo: s.makeRepo(p)}
+		}
+	}
+
+	if len(s.config.Projects) > 0 {
+		for _, name := range s.config.Projects {
+			p, err := s.client.GetProject(ctx, name)
+			if err != nil {
+				if gerrit.IsNotFound(err) {
+					log15.Warn("skipping missing gerrit.projects entry:", "name", name, "err", err)
+					continue
+				}
+				results <- SourceResult{Source: s, Err: errors.Wrapf(err, "gerrit.projects: item=%q", name)}
+				continue
+			}
+			yield(p)
+		}
+		return
+	}
+
+	for skip, hasNext := 0, true; hasNext; {
+		var projects []*gerrit.Project
+		var err error
+		if projects, hasNext, err = s.client.ListProjects(ctx, skip, s.perPage); err != nil {
+			results <- SourceResult{Source: s, Err: errors.Wrapf(err, "gerrit.listProjects: skip=%d", skip)}
+			return
+		}
+		for _, p := range projects {
+			yield(p)
+		}
+		skip += len(projects)
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s GerritSource) ExternalServices() types.ExternalServices {
+	return types.ExternalServices{s.svc}
+}
+
+func (s GerritSource) makeRepo(p *gerrit.Project) *types.Repo {
+	urn := s.svc.URN()
+	return &types.Repo{
+		Name: reposource.GerritRepoName(
+			s.config.RepositoryPathPattern,
+			s.host.Hostname(),
+			p.Name,
+		),
+		URI: string(reposource.GerritRepoName(
+			"",
+			s.host.Hostname(),
+			p.Name,
+		)),
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          p.ID,
+			ServiceType: extsvc.TypeGerrit,
+			ServiceID:   s.host.String(),
+		},
+		Description: p.Description,
+		Archived:    p.State == gerrit.ProjectStateReadOnly,
+		Sources: map[string]*types.SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: s.remoteURL(p),
+			},
+		},
+		Metadata: p,
+	}
+}
+
+// remoteURL returns the project's Git remote URL
+//
+// note: this used to contain credentials but that is no longer the case
+// if you need to get an authenticated clone url use repos.CloneURL
+func (s *GerritSource) remoteURL(p *gerrit.Project) string {
+	return s.host.ResolveReference(&url.URL{Path: p.Name}).String()
+}
+
+func (s *GerritSource) excludes(p *gerrit.Project) bool {
+	// Hidden projects are only visible to project owners and can't be cloned
+	// by anyone else, so we never mirror them.
+	return p.State == gerrit.ProjectStateHidden || s.exclude(p.Name)
+}
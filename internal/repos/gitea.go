@@ -0,0 +1,210 @@
+package repos
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A GiteaSource yields repositories from a single Gitea connection configured
+// in Sourcegraph via the external services configuration.
+type GiteaSource struct {
+	svc     *types.ExternalService
+	config  *schema.GiteaConnection
+	exclude excludeFunc
+	client  *gitea.Client
+	host    *url.URL
+
+	// perPage is the page size used when listing repositories.
+	perPage int
+}
+
+// NewGiteaSource returns a new GiteaSource from the given external service.
+func NewGiteaSource(svc *types.ExternalService, cf *httpcli.Factory) (*GiteaSource, error) {
+	var c schema.GiteaConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	return newGiteaSource(svc, &c, cf)
+}
+
+func newGiteaSource(svc *types.ExternalService, c *schema.GiteaConnection, cf *httpcli.Factory) (*GiteaSource, error) {
+	baseURL, err := url.Parse(c.Url)
+	if err != nil {
+		return nil, err
+	}
+	baseURL = extsvc.NormalizeBaseURL(baseURL)
+
+	if cf == nil {
+		cf = httpcli.NewExternalHTTPClientFactory()
+	}
+
+	cli, err := cf.Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	var eb excludeBuilder
+	for _, r := range c.Exclude {
+		eb.Exact(r.Name)
+		if r.Id != 0 {
+			eb.Exact(strconv.Itoa(r.Id))
+		}
+		eb.Pattern(r.Pattern)
+	}
+	exclude, err := eb.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	client := gitea.NewClient(baseURL, cli)
+	client.Token = c.Token
+
+	return &GiteaSource{
+		svc:     svc,
+		config:  c,
+		exclude: exclude,
+		client:  client,
+		host:    baseURL,
+		perPage: 50,
+	}, nil
+}
+
+// ListRepos returns all Gitea repositories accessible to all connections configured
+// in Sourcegraph via the external services configuration.
+func (s GiteaSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	seen := make(map[int64]bool)
+	yield := func(repos []*gitea.Repo) {
+		for _, r := range repos {
+			if !seen[r.ID] && !s.excludes(r) {
+				results <- SourceResult{Source: s, Repo: s.makeRepo(r)}
+				seen[r.ID] = true
+			}
+		}
+	}
+
+	if len(s.config.Orgs) == 0 && len(s.config.Repos) == 0 {
+		s.listPaginated(ctx, results, "user", "self", s.client.ListUserRepos, yield)
+		return
+	}
+
+	for _, org := range s.config.Orgs {
+		org := org
+		list := func(ctx context.Context, page, perPage int) ([]*gitea.Repo, bool, error) {
+			return s.client.ListOrgRepos(ctx, org, page, perPage)
+		}
+		s.listPaginated(ctx, results, "orgs", org, list, yield)
+	}
+
+	for _, nameWithOwner := range s.config.Repos {
+		owner, name, err := splitGiteaNameWithOwner(nameWithOwner)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: err}
+			continue
+		}
+
+		r, err := s.client.GetRepo(ctx, owner, name)
+		if err != nil {
+			if gitea.IsNotFound(err) {
+				log15.Warn("skipping missing gitea.repos entry:", "name", nameWithOwner, "err", err)
+				continue
+			}
+			results <- SourceResult{Source: s, Err: errors.Wrapf(err, "gitea.repos: item=%q", nameWithOwner)}
+			continue
+		}
+		yield([]*gitea.Repo{r})
+	}
+}
+
+func (s GiteaSource) listPaginated(
+	ctx context.Context,
+	results chan SourceResult,
+	kind, item string,
+	list func(ctx context.Context, page, perPage int) ([]*gitea.Repo, bool, error),
+	yield func([]*gitea.Repo),
+) {
+	for page, hasNext := 1, true; hasNext; page++ {
+		var repos []*gitea.Repo
+		var err error
+		if repos, hasNext, err = list(ctx, page, s.perPage); err != nil {
+			results <- SourceResult{Source: s, Err: errors.Wrapf(err, "gitea.%s: item=%q, page=%d", kind, item, page)}
+			return
+		}
+		yield(repos)
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s GiteaSource) ExternalServices() types.ExternalServices {
+	return types.ExternalServices{s.svc}
+}
+
+func (s GiteaSource) makeRepo(r *gitea.Repo) *types.Repo {
+	urn := s.svc.URN()
+	return &types.Repo{
+		Name: reposource.GiteaRepoName(
+			s.config.RepositoryPathPattern,
+			s.host.Hostname(),
+			r.FullName,
+		),
+		URI: string(reposource.GiteaRepoName(
+			"",
+			s.host.Hostname(),
+			r.FullName,
+		)),
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          strconv.FormatInt(r.ID, 10),
+			ServiceType: extsvc.TypeGitea,
+			ServiceID:   s.host.String(),
+		},
+		Description: r.Description,
+		Fork:        r.Fork,
+		Archived:    r.Archived,
+		Private:     r.Private,
+		Sources: map[string]*types.SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: s.remoteURL(r),
+			},
+		},
+		Metadata: r,
+	}
+}
+
+// remoteURL returns the repository's Git remote URL
+//
+// note: this used to contain credentials but that is no longer the case
+// if you need to get an authenticated clone url use repos.CloneURL
+func (s *GiteaSource) remoteURL(repo *gitea.Repo) string {
+	if s.config.GitURLType == "ssh" {
+		return repo.SSHURL
+	}
+	return repo.CloneURL
+}
+
+func (s *GiteaSource) excludes(r *gitea.Repo) bool {
+	return s.exclude(r.FullName) ||
+		s.exclude(strconv.FormatInt(r.ID, 10)) ||
+		(s.config.ExcludeArchived && r.Archived)
+}
+
+func splitGiteaNameWithOwner(nameWithOwner string) (owner, name string, err error) {
+	parts := strings.SplitN(nameWithOwner, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.Errorf("gitea.repos: invalid repository %q, expected owner/name", nameWithOwner)
+	}
+	return parts[0], parts[1], nil
+}
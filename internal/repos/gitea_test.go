@@ -0,0 +1,249 @@
+package repos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
+	"github.com/sourcegraph/sourcegraph/internal/testutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestGiteaSource_ListRepos(t *testing.T) {
+	assertAllReposListed := func(want []string) types.ReposAssertion {
+		return func(t testing.TB, rs types.Repos) {
+			t.Helper()
+
+			have := rs.Names()
+			sort.Strings(have)
+			sort.Strings(want)
+
+			if diff := cmp.Diff(want, have); diff != "" {
+				t.Errorf("Mismatch (-want +got):\n%s", diff)
+			}
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		assert types.ReposAssertion
+		conf   *schema.GiteaConnection
+		err    string
+	}{
+		{
+			name: "user repos",
+			assert: assertAllReposListed([]string{
+				"gitea.sgdev.org/sourcegraph/sourcegraph",
+				"gitea.sgdev.org/sourcegraph/src-cli",
+				"gitea.sgdev.org/sourcegraph/go-langserver-fork",
+				"gitea.sgdev.org/sourcegraph/archived",
+				"gitea.sgdev.org/sourcegraph-bot/dotfiles",
+			}),
+			conf: &schema.GiteaConnection{
+				Url:   "https://gitea.sgdev.org",
+				Token: os.Getenv("GITEA_TOKEN"),
+			},
+			err: "<nil>",
+		},
+		{
+			name: "orgs and repos",
+			assert: assertAllReposListed([]string{
+				"gitea.sgdev.org/sourcegraph/sourcegraph",
+				"gitea.sgdev.org/sourcegraph/src-cli",
+				"gitea.sgdev.org/sourcegraph-bot/dotfiles",
+			}),
+			conf: &schema.GiteaConnection{
+				Url:   "https://gitea.sgdev.org",
+				Token: os.Getenv("GITEA_TOKEN"),
+				Orgs:  []string{"sourcegraph"},
+				Repos: []string{
+					"sourcegraph/src-cli", // also listed through the org
+					"sourcegraph-bot/dotfiles",
+					"sourcegraph-bot/does-not-exist",
+				},
+				Exclude: []*schema.ExcludedGiteaRepo{
+					{Pattern: "-fork$"},
+				},
+				ExcludeArchived: true,
+			},
+			err: "<nil>",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		tc.name = "GITEA-LIST-REPOS/" + tc.name
+		t.Run(tc.name, func(t *testing.T) {
+			cf, save := newClientFactory(t, tc.name)
+			defer save(t)
+
+			svc := &types.ExternalService{
+				Kind:   extsvc.KindGitea,
+				Config: marshalJSON(t, tc.conf),
+			}
+
+			src, err := newGiteaSource(svc, tc.conf, cf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Use a small page size to exercise pagination.
+			src.perPage = 2
+
+			repos, err := listAll(context.Background(), src)
+
+			if have, want := fmt.Sprint(err), tc.err; have != want {
+				t.Errorf("error:\nhave: %q\nwant: %q", have, want)
+			}
+
+			if tc.assert != nil {
+				tc.assert(t, repos)
+			}
+		})
+	}
+}
+
+func TestGiteaSource_makeRepo(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("testdata", "gitea-repos.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var repos []*gitea.Repo
+	if err := json.Unmarshal(b, &repos); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := types.ExternalService{ID: 1, Kind: extsvc.KindGitea}
+
+	tests := []struct {
+		name   string
+		schema *schema.GiteaConnection
+	}{
+		{
+			name: "simple",
+			schema: &schema.GiteaConnection{
+				Url:   "https://gitea.example.com",
+				Token: "secret",
+			},
+		}, {
+			name: "ssh",
+			schema: &schema.GiteaConnection{
+				Url:        "https://gitea.example.com",
+				Token:      "secret",
+				GitURLType: "ssh",
+			},
+		}, {
+			name: "path-pattern",
+			schema: &schema.GiteaConnection{
+				Url:                   "https://gitea.example.com",
+				Token:                 "secret",
+				RepositoryPathPattern: "gt/{nameWithOwner}",
+			},
+		},
+	}
+	for _, test := range tests {
+		test.name = "GiteaSource_makeRepo_" + test.name
+		t.Run(test.name, func(t *testing.T) {
+			s, err := newGiteaSource(&svc, test.schema, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []*types.Repo
+			for _, r := range repos {
+				got = append(got, s.makeRepo(r))
+			}
+
+			testutil.AssertGolden(t, "testdata/golden/"+test.name, update(test.name), got)
+		})
+	}
+}
+
+func TestGiteaSource_Exclude(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("testdata", "gitea-repos.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var repos []*gitea.Repo
+	if err := json.Unmarshal(b, &repos); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]*schema.GiteaConnection{
+		"none": {
+			Url:   "https://gitea.example.com",
+			Token: "secret",
+		},
+		"name": {
+			Url:   "https://gitea.example.com",
+			Token: "secret",
+			Exclude: []*schema.ExcludedGiteaRepo{
+				{Name: "SG/Sourcegraph"},
+			},
+		},
+		"id": {
+			Url:   "https://gitea.example.com",
+			Token: "secret",
+			Exclude: []*schema.ExcludedGiteaRepo{
+				{Id: 2},
+			},
+		},
+		"pattern": {
+			Url:   "https://gitea.example.com",
+			Token: "secret",
+			Exclude: []*schema.ExcludedGiteaRepo{
+				{Pattern: ".*-fork$"},
+			},
+		},
+		"archived": {
+			Url:             "https://gitea.example.com",
+			Token:           "secret",
+			ExcludeArchived: true,
+		},
+		"all": {
+			Url:   "https://gitea.example.com",
+			Token: "secret",
+			Exclude: []*schema.ExcludedGiteaRepo{
+				{Name: "SG/Sourcegraph"},
+				{Id: 2},
+				{Pattern: ".*-fork$"},
+			},
+			ExcludeArchived: true,
+		},
+	}
+
+	svc := types.ExternalService{ID: 1, Kind: extsvc.KindGitea}
+
+	for name, config := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, err := newGiteaSource(&svc, config, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			type output struct {
+				Include []string
+				Exclude []string
+			}
+			var got output
+			for _, r := range repos {
+				if s.excludes(r) {
+					got.Exclude = append(got.Exclude, r.FullName)
+				} else {
+					got.Include = append(got.Include, r.FullName)
+				}
+			}
+
+			path := filepath.Join("testdata", "gitea-repos-exclude-"+name+".golden")
+			testutil.AssertGolden(t, path, update(name), got)
+		})
+	}
+}
@@ -60,6 +60,16 @@ var (
 		Help: "Incremented each time we try and fail to remove a repository clone.",
 	})
 
+	commitIndexSuccess = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "src_repoupdater_commit_index_success",
+		Help: "Incremented each time we update the commit index of a repository.",
+	})
+
+	commitIndexFailed = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "src_repoupdater_commit_index_failed",
+		Help: "Incremented each time we try and fail to update the commit index of a repository.",
+	})
+
 	schedError = promauto.NewCounter(prometheus.CounterOpts{
 		Name: "src_repoupdater_sched_error",
 		Help: "Incremented each time we encounter an error updating a repository.",
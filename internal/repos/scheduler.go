@@ -106,6 +106,10 @@ const (
 type updateScheduler struct {
 	updateQueue *updateQueue
 	schedule    *schedule
+
+	// commitIndexer, if non-nil, is notified of every repo which was
+	// updated successfully.
+	commitIndexer *CommitIndexer
 }
 
 // A configuredRepo represents the configuration data for a given repo from
@@ -121,9 +125,10 @@ type configuredRepo struct {
 // non-blocking sends.
 const notifyChanBuffer = 1
 
-// NewUpdateScheduler returns a new scheduler.
-func NewUpdateScheduler() *updateScheduler {
+// NewUpdateScheduler returns a new scheduler. commitIndexer may be nil.
+func NewUpdateScheduler(commitIndexer *CommitIndexer) *updateScheduler {
 	return &updateScheduler{
+		commitIndexer: commitIndexer,
 		updateQueue: &updateQueue{
 			index:         make(map[api.RepoID]*repoUpdate),
 			notifyEnqueue: make(chan struct{}, notifyChanBuffer),
@@ -201,6 +206,8 @@ func (s *updateScheduler) runUpdateLoop(ctx context.Context) {
 				if err != nil {
 					schedError.Inc()
 					log15.Warn("error requesting repo update", "uri", repo.Name, "err", err)
+				} else if s.commitIndexer != nil && resp != nil && resp.Error == "" {
+					s.commitIndexer.Enqueue(types.RepoName{ID: repo.ID, Name: repo.Name})
 				}
 				if interval := getCustomInterval(conf.Get(), string(repo.Name)); interval > 0 {
 					s.schedule.updateInterval(repo, interval)
@@ -274,7 +274,7 @@ func TestUpdateQueue_enqueue(t *testing.T) {
 			r, stop := startRecording()
 			defer stop()
 
-			s := NewUpdateScheduler()
+			s := NewUpdateScheduler(nil)
 
 			for _, call := range test.calls {
 				s.updateQueue.enqueue(call.repo, call.priority)
@@ -440,7 +440,7 @@ func TestUpdateQueue_remove(t *testing.T) {
 			r, stop := startRecording()
 			defer stop()
 
-			s := NewUpdateScheduler()
+			s := NewUpdateScheduler(nil)
 			setupInitialQueue(s, test.initialQueue)
 
 			// Perform the removals.
@@ -512,7 +512,7 @@ func TestUpdateQueue_acquireNext(t *testing.T) {
 			r, stop := startRecording()
 			defer stop()
 
-			s := NewUpdateScheduler()
+			s := NewUpdateScheduler(nil)
 			setupInitialQueue(s, test.initialQueue)
 
 			// Test aquireNext.
@@ -642,7 +642,7 @@ func Test_updateScheduler_UpdateFromDiff(t *testing.T) {
 			_, stop := startRecording()
 			defer stop()
 
-			s := NewUpdateScheduler()
+			s := NewUpdateScheduler(nil)
 			setupInitialSchedule(s, test.initialSchedule)
 			setupInitialQueue(s, test.initialQueue)
 
@@ -788,7 +788,7 @@ func TestSchedule_upsert(t *testing.T) {
 			r, stop := startRecording()
 			defer stop()
 
-			s := NewUpdateScheduler()
+			s := NewUpdateScheduler(nil)
 			setupInitialSchedule(s, test.initialSchedule)
 
 			for _, call := range test.upsertCalls {
@@ -810,7 +810,7 @@ func TestUpdateQueue_PrioritiseUncloned(t *testing.T) {
 	_, stop := startRecording()
 	defer stop()
 
-	s := NewUpdateScheduler()
+	s := NewUpdateScheduler(nil)
 
 	assertFront := func(name api.RepoName) {
 		t.Helper()
@@ -843,7 +843,7 @@ func TestScheduleInsertNew(t *testing.T) {
 	_, stop := startRecording()
 	defer stop()
 
-	s := NewUpdateScheduler()
+	s := NewUpdateScheduler(nil)
 
 	assertFront := func(name api.RepoName) {
 		t.Helper()
@@ -1028,7 +1028,7 @@ func TestSchedule_updateInterval(t *testing.T) {
 			r, stop := startRecording()
 			defer stop()
 
-			s := NewUpdateScheduler()
+			s := NewUpdateScheduler(nil)
 			setupInitialSchedule(s, test.initialSchedule)
 
 			for _, call := range test.updateCalls {
@@ -1126,7 +1126,7 @@ func TestSchedule_remove(t *testing.T) {
 			r, stop := startRecording()
 			defer stop()
 
-			s := NewUpdateScheduler()
+			s := NewUpdateScheduler(nil)
 			setupInitialSchedule(s, test.initialSchedule)
 
 			for _, call := range test.removeCalls {
@@ -1288,7 +1288,7 @@ func TestUpdateScheduler_runSchedule(t *testing.T) {
 			r, stop := startRecording()
 			defer stop()
 
-			s := NewUpdateScheduler()
+			s := NewUpdateScheduler(nil)
 
 			setupInitialSchedule(s, test.initialSchedule)
 
@@ -1419,7 +1419,7 @@ func TestUpdateScheduler_runUpdateLoop(t *testing.T) {
 			}
 			defer func() { requestRepoUpdate = nil }()
 
-			s := NewUpdateScheduler()
+			s := NewUpdateScheduler(nil)
 
 			// unbuffer the channel
 			s.updateQueue.notifyEnqueue = make(chan struct{})
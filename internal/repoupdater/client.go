@@ -17,6 +17,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/metrics"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/requestid"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 )
 
@@ -41,15 +42,18 @@ func NewClient(serverURL string) *Client {
 	return &Client{
 		URL: serverURL,
 		HTTPClient: &http.Client{
-			// ot.Transport will propagate opentracing spans and whether or not to trace
+			// ot.Transport will propagate opentracing spans and whether or not to trace, and
+			// requestid.Transport the request ID
 			Transport: &ot.Transport{
-				RoundTripper: requestMeter.Transport(&http.Transport{
-					// Default is 2, but we can send many concurrent requests
-					MaxIdleConnsPerHost: 500,
-				}, func(u *url.URL) string {
-					// break it down by API function call (ie "/repo-update-scheduler-info", "/repo-lookup", etc)
-					return u.Path
-				}),
+				RoundTripper: &requestid.Transport{
+					RoundTripper: requestMeter.Transport(&http.Transport{
+						// Default is 2, but we can send many concurrent requests
+						MaxIdleConnsPerHost: 500,
+					}, func(u *url.URL) string {
+						// break it down by API function call (ie "/repo-update-scheduler-info", "/repo-lookup", etc)
+						return u.Path
+					}),
+				},
 			},
 		},
 	}
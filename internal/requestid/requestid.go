@@ -0,0 +1,89 @@
+// Package requestid correlates the work done by all Sourcegraph services on behalf of a single
+// request. The request ID is stored in the context.Context of the request, and propagated across
+// service boundaries through a HTTP header (X-Sourcegraph-Request-ID).
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+)
+
+// Header is the HTTP header carrying the request ID between services. It is also set on
+// responses so that users can refer to a request when reporting problems.
+const Header = "X-Sourcegraph-Request-ID"
+
+type key int
+
+const requestIDKey key = iota
+
+// FromContext returns the request ID of the context, or an empty string if it has none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// WithRequestID returns a copy of the context with the given request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// New returns a new random request ID.
+func New() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// validPattern matches the request IDs we accept from incoming requests. Request IDs end up in
+// log lines, so we only accept short IDs without characters that could forge log output.
+var validPattern = lazyregexp.New(`^[A-Za-z0-9_.-]{1,64}$`)
+
+// Middleware stores the request ID of the incoming request in the request context, or a new
+// request ID if the request has no (valid) request ID. The request ID is echoed in the response.
+//
+// 🚨 SECURITY: Request IDs sent by clients are untrusted. They are only used to correlate log
+// lines and must not be used for anything else.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if FromContext(r.Context()) != "" {
+			// The handler is wrapped more than once.
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		id := r.Header.Get(Header)
+		if !validPattern.MatchString(id) {
+			id = New()
+		}
+		w.Header().Set(Header, id)
+		next.ServeHTTP(w, r.WithContext(WithRequestID(r.Context(), id)))
+	})
+}
+
+// Transport wraps an underlying HTTP RoundTripper, setting the X-Sourcegraph-Request-ID header of
+// outgoing requests to the request ID of the request context.
+//
+// 🚨 SECURITY: Only use it for requests to other Sourcegraph services. Request IDs must not be sent
+// to code hosts or other external services.
+type Transport struct {
+	http.RoundTripper
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	SetHeader(req)
+	return t.RoundTripper.RoundTrip(req)
+}
+
+// SetHeader sets the request ID header of an outgoing request to the request ID of the request
+// context, if it has one.
+func SetHeader(req *http.Request) {
+	if id := FromContext(req.Context()); id != "" {
+		req.Header.Set(Header, id)
+	}
+}
@@ -0,0 +1,102 @@
+package requestid
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	var have string
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		have = FromContext(r.Context())
+	}))
+
+	serve := func(header string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/", nil)
+		if header != "" {
+			req.Header.Set(Header, header)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("incoming request ID", func(t *testing.T) {
+		rec := serve("abc-123")
+		if have != "abc-123" {
+			t.Errorf("unexpected request ID. want=%q have=%q", "abc-123", have)
+		}
+		if echoed := rec.Header().Get(Header); echoed != "abc-123" {
+			t.Errorf("unexpected response header. want=%q have=%q", "abc-123", echoed)
+		}
+	})
+
+	for name, header := range map[string]string{
+		"no request ID":      "",
+		"invalid request ID": "abc\ninjected=1",
+	} {
+		t.Run(name, func(t *testing.T) {
+			rec := serve(header)
+			if !validPattern.MatchString(have) {
+				t.Errorf("expected a new request ID, have %q", have)
+			}
+			if echoed := rec.Header().Get(Header); echoed != have {
+				t.Errorf("unexpected response header. want=%q have=%q", have, echoed)
+			}
+		})
+	}
+
+	t.Run("nested", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req = req.WithContext(WithRequestID(req.Context(), "outer"))
+		req.Header.Set(Header, "inner")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		if have != "outer" {
+			t.Errorf("unexpected request ID. want=%q have=%q", "outer", have)
+		}
+	})
+}
+
+func TestTransport(t *testing.T) {
+	var have string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		have = r.Header.Get(Header)
+	}))
+	defer srv.Close()
+
+	req, err := http.NewRequestWithContext(WithRequestID(context.Background(), "abc"), "GET", srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli := &http.Client{Transport: &Transport{RoundTripper: http.DefaultTransport}}
+	resp, err := cli.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if have != "abc" {
+		t.Errorf("unexpected header. want=%q have=%q", "abc", have)
+	}
+}
+
+func TestSetHeader(t *testing.T) {
+	req, err := http.NewRequestWithContext(WithRequestID(context.Background(), "abc"), "GET", "http://example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetHeader(req)
+	if have := req.Header.Get(Header); have != "abc" {
+		t.Errorf("unexpected header. want=%q have=%q", "abc", have)
+	}
+
+	req, err = http.NewRequest("GET", "http://example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetHeader(req)
+	if have := req.Header.Get(Header); have != "" {
+		t.Errorf("expected no header, have %q", have)
+	}
+}
@@ -26,6 +26,28 @@ var reorderQueueSize = promauto.NewHistogramVec(prometheus.HistogramOpts{
 	Buckets: prometheus.ExponentialBuckets(4, 2, 10),
 }, nil)
 
+type debugKey struct{}
+
+// WithDebug returns a context in which HorizontalSearcher records the endpoint
+// and the priority of the shard of each file match it finds in the Debug
+// field of the match, in the same "name:value, " form which Zoekt uses for
+// the components of the score.
+func WithDebug(ctx context.Context) context.Context {
+	return context.WithValue(ctx, debugKey{}, true)
+}
+
+func isDebug(ctx context.Context) bool {
+	debug, _ := ctx.Value(debugKey{}).(bool)
+	return debug
+}
+
+func addDebug(endpoint string, sr *zoekt.SearchResult) {
+	prefix := fmt.Sprintf("endpoint:%s, shard-priority:%g, ", endpoint, sr.Priority)
+	for i := range sr.Files {
+		sr.Files[i].Debug = prefix + sr.Files[i].Debug
+	}
+}
+
 // HorizontalSearcher is a Streamer which aggregates searches over
 // Map. It manages the connections to Map as the endpoints come and go.
 type HorizontalSearcher struct {
@@ -40,6 +62,11 @@ type HorizontalSearcher struct {
 	clients map[string]zoekt.Streamer // addr -> client
 }
 
+// defaultMaxReorderQueueSize is the number of search results buffered to
+// stream results in the order of their repository's priority, unless
+// configured otherwise in experimentalFeatures.ranking.maxReorderQueueSize.
+const defaultMaxReorderQueueSize = 200
+
 // StreamSearch does a search which merges the stream from every endpoint in Map, reordering results to produce a sorted stream.
 func (s *HorizontalSearcher) StreamSearch(ctx context.Context, q query.Q, opts *zoekt.SearchOptions, streamer zoekt.Sender) error {
 	clients, err := s.searchers()
@@ -48,9 +75,9 @@ func (s *HorizontalSearcher) StreamSearch(ctx context.Context, q query.Q, opts *
 	}
 
 	siteConfig := conf.Get().SiteConfiguration
-	maxQueueDepth := 0
-	if siteConfig.ExperimentalFeatures != nil && siteConfig.ExperimentalFeatures.Ranking != nil {
-		maxQueueDepth = siteConfig.ExperimentalFeatures.Ranking.MaxReorderQueueSize
+	maxQueueDepth := defaultMaxReorderQueueSize
+	if siteConfig.ExperimentalFeatures != nil && siteConfig.ExperimentalFeatures.Ranking != nil && siteConfig.ExperimentalFeatures.Ranking.MaxReorderQueueSize != nil {
+		maxQueueDepth = *siteConfig.ExperimentalFeatures.Ranking.MaxReorderQueueSize
 	}
 
 	// During rebalancing a repository can appear on more than one replica.
@@ -84,6 +111,10 @@ func (s *HorizontalSearcher) StreamSearch(ctx context.Context, q query.Q, opts *
 					return
 				}
 
+				if isDebug(ctx) {
+					addDebug(endpoint, sr)
+				}
+
 				mu.Lock()
 				sr.Files = dedupper.Dedup(endpoint, sr.Files)
 
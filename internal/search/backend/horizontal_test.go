@@ -149,6 +149,88 @@ func TestDoStreamSearch(t *testing.T) {
 	}
 }
 
+func TestHorizontalSearcher_priorityOrder(t *testing.T) {
+	var endpoints atomicMap
+	endpoints.Store(prefixMap{"1", "2", "3"})
+
+	priorities := map[string]float64{"1": 10, "2": 30, "3": 20}
+	searcher := &HorizontalSearcher{
+		Map: &endpoints,
+		Dial: func(endpoint string) zoekt.Streamer {
+			return &StreamSearchAdapter{&mockSearcher{
+				searchResult: &zoekt.SearchResult{
+					Files:    []zoekt.FileMatch{{Repository: endpoint}},
+					Progress: zoekt.Progress{Priority: priorities[endpoint], MaxPendingPriority: priorities[endpoint]},
+				},
+			}}
+		},
+	}
+	defer searcher.Close()
+
+	var got []string
+	err := searcher.StreamSearch(context.Background(), nil, nil, ZoektStreamFunc(func(event *zoekt.SearchResult) {
+		for _, fm := range event.Files {
+			got = append(got, fm.Repository)
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]string{"2", "3", "1"}, got); diff != "" {
+		t.Errorf("unexpected order (-want +got):\n%s", diff)
+	}
+}
+
+func TestHorizontalSearcher_debug(t *testing.T) {
+	var endpoints atomicMap
+	endpoints.Store(prefixMap{"1", "2"})
+
+	searcher := &HorizontalSearcher{
+		Map: &endpoints,
+		Dial: func(endpoint string) zoekt.Streamer {
+			return &StreamSearchAdapter{&mockSearcher{
+				searchResult: &zoekt.SearchResult{
+					Files: []zoekt.FileMatch{{
+						Repository: endpoint,
+						Debug:      "atom:1.000000, ",
+					}},
+				},
+			}}
+		},
+	}
+	defer searcher.Close()
+
+	search := func(ctx context.Context) map[string]string {
+		got := map[string]string{}
+		err := searcher.StreamSearch(ctx, nil, nil, ZoektStreamFunc(func(event *zoekt.SearchResult) {
+			for _, fm := range event.Files {
+				got[fm.Repository] = fm.Debug
+			}
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	want := map[string]string{
+		"1": "atom:1.000000, ",
+		"2": "atom:1.000000, ",
+	}
+	if diff := cmp.Diff(want, search(context.Background())); diff != "" {
+		t.Errorf("unexpected debug without WithDebug (-want +got):\n%s", diff)
+	}
+
+	want = map[string]string{
+		"1": "endpoint:1, shard-priority:0, atom:1.000000, ",
+		"2": "endpoint:2, shard-priority:0, atom:1.000000, ",
+	}
+	if diff := cmp.Diff(want, search(WithDebug(context.Background()))); diff != "" {
+		t.Errorf("unexpected debug with WithDebug (-want +got):\n%s", diff)
+	}
+}
+
 func TestSyncSearchers(t *testing.T) {
 	// This test exists to ensure we test the slow path for
 	// HorizontalSearcher.searchers. The slow-path is
@@ -3,7 +3,9 @@ package backend
 import (
 	"bytes"
 	"encoding/json"
+	"math"
 	"sort"
+	"time"
 
 	"github.com/google/zoekt"
 
@@ -57,8 +59,13 @@ type RepoIndexOptions struct {
 	// filtering.
 	Public bool
 
-	// Priority indicates ranking in results, higher first.
-	Priority float64
+	// Stars is the star count the repository has in the code host. It is
+	// one of the signals used to rank results of the repository.
+	Stars int
+
+	// Boost is the score set by site admins for the repository. It is one
+	// of the signals used to rank results of the repository.
+	Boost float64
 
 	// Fork is true if the repository is a fork.
 	Fork bool
@@ -70,6 +77,53 @@ type RepoIndexOptions struct {
 	// error is encoded in the body. If the revision is missing, an empty
 	// string should be returned rather than an error.
 	GetVersion func(branch string) (string, error)
+
+	// GetCommitDate returns the committer date of the given commit. It is
+	// used to rank recently active repositories higher. It returns the zero
+	// time if the date is not known (yet). If nil, commit activity is not
+	// taken into account.
+	GetCommitDate func(commit string) (time.Time, error)
+}
+
+// timeNow is the clock used to compute the age of a repository's latest
+// commit. It is replaced in tests.
+var timeNow = time.Now
+
+const (
+	defaultRecentActivityScore        = 100
+	defaultRecentActivityHalfLifeDays = 30
+)
+
+// repoPriority returns the priority zoekt uses to rank results of the
+// repository, higher first. It is the sum of the repository's stars, its
+// admin-set boost and a score for recent commit activity which halves every
+// recentActivityHalfLifeDays after the latest commit on HEAD.
+//
+// The activity score only changes once a whole half-life has passed rather
+// than continuously: the priority is part of the index options, and zoekt
+// re-indexes a repository whenever they change.
+func repoPriority(c *schema.SiteConfiguration, opts *RepoIndexOptions, lastCommit time.Time) float64 {
+	priority := float64(opts.Stars) + opts.Boost
+	if lastCommit.IsZero() {
+		return priority
+	}
+
+	score, halfLife := float64(defaultRecentActivityScore), float64(defaultRecentActivityHalfLifeDays)
+	if c.ExperimentalFeatures != nil && c.ExperimentalFeatures.Ranking != nil {
+		if r := c.ExperimentalFeatures.Ranking; r.RecentActivityScore != nil {
+			score = *r.RecentActivityScore
+		}
+		if r := c.ExperimentalFeatures.Ranking; r.RecentActivityHalfLifeDays > 0 {
+			halfLife = r.RecentActivityHalfLifeDays
+		}
+	}
+
+	age := timeNow().Sub(lastCommit).Hours() / 24
+	if age < 0 {
+		// Commits dated in the future are as recent as it gets.
+		age = 0
+	}
+	return priority + score*math.Exp2(-math.Floor(age/halfLife))
 }
 
 // GetIndexOptions returns a json blob for consumption by
@@ -116,7 +170,6 @@ func getIndexOptions(
 	o := &zoektIndexOptions{
 		RepoID:     opts.RepoID,
 		Public:     opts.Public,
-		Priority:   opts.Priority,
 		Fork:       opts.Fork,
 		Archived:   opts.Archived,
 		LargeFiles: c.SearchLargeFiles,
@@ -150,6 +203,7 @@ func getIndexOptions(
 		branches[rev] = struct{}{}
 	}
 
+	var lastCommit time.Time
 	for branch := range branches {
 		v, err := opts.GetVersion(branch)
 		if err != nil {
@@ -161,12 +215,22 @@ func getIndexOptions(
 			continue
 		}
 
+		if branch == "HEAD" && opts.GetCommitDate != nil {
+			// Ranking is best effort, so failing to look up the commit only
+			// means we don't consider the repository recently active.
+			if date, err := opts.GetCommitDate(v); err == nil {
+				lastCommit = date
+			}
+		}
+
 		o.Branches = append(o.Branches, zoekt.RepositoryBranch{
 			Name:    branch,
 			Version: v,
 		})
 	}
 
+	o.Priority = repoPriority(c, opts, lastCommit)
+
 	sort.Slice(o.Branches, func(i, j int) bool {
 		a, b := o.Branches[i].Name, o.Branches[j].Name
 		// Zoekt treats first branch as default branch, so put HEAD first
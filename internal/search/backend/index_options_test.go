@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/google/go-cmp/cmp"
@@ -216,6 +217,55 @@ func TestGetIndexOptions(t *testing.T) {
 			},
 			Priority: 10,
 		},
+	}, {
+		name: "with boost and recent activity",
+		conf: schema.SiteConfiguration{},
+		repo: "active",
+		want: zoektIndexOptions{
+			RepoID:  8,
+			Symbols: true,
+			Branches: []zoekt.RepositoryBranch{
+				{Name: "HEAD", Version: "!HEAD"},
+			},
+			Priority: 10 + 5 + 50,
+		},
+	}, {
+		name: "with configured recent activity score",
+		conf: schema.SiteConfiguration{
+			ExperimentalFeatures: &schema.ExperimentalFeatures{
+				Ranking: &schema.Ranking{
+					RecentActivityScore:        float64Ptr(40),
+					RecentActivityHalfLifeDays: 10,
+				},
+			},
+		},
+		repo: "active",
+		want: zoektIndexOptions{
+			RepoID:  8,
+			Symbols: true,
+			Branches: []zoekt.RepositoryBranch{
+				{Name: "HEAD", Version: "!HEAD"},
+			},
+			Priority: 10 + 5 + 5,
+		},
+	}, {
+		name: "with recent activity disabled",
+		conf: schema.SiteConfiguration{
+			ExperimentalFeatures: &schema.ExperimentalFeatures{
+				Ranking: &schema.Ranking{
+					RecentActivityScore: float64Ptr(0),
+				},
+			},
+		},
+		repo: "active",
+		want: zoektIndexOptions{
+			RepoID:  8,
+			Symbols: true,
+			Branches: []zoekt.RepositoryBranch{
+				{Name: "HEAD", Version: "!HEAD"},
+			},
+			Priority: 10 + 5,
+		},
 	}}
 
 	{
@@ -243,28 +293,42 @@ func TestGetIndexOptions(t *testing.T) {
 		})
 	}
 
+	now := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = time.Now })
+
 	getRepoIndexOptions := func(repo string) (*RepoIndexOptions, error) {
 		repoID := int32(1)
-		for _, r := range []string{"repo", "foo", "not_in_version_context", "priority", "public", "fork", "archived"} {
+		for _, r := range []string{"repo", "foo", "not_in_version_context", "priority", "public", "fork", "archived", "active"} {
 			if r == repo {
 				break
 			}
 			repoID++
 		}
-		var priority float64
-		if repo == "priority" {
-			priority = 10
-		}
-		return &RepoIndexOptions{
+		opts := &RepoIndexOptions{
 			RepoID:   repoID,
 			Public:   repo == "public",
 			Fork:     repo == "fork",
 			Archived: repo == "archived",
-			Priority: priority,
 			GetVersion: func(branch string) (string, error) {
 				return "!" + branch, nil
 			},
-		}, nil
+		}
+		switch repo {
+		case "priority":
+			opts.Stars = 10
+		case "active":
+			opts.Stars = 10
+			opts.Boost = 5
+			opts.GetCommitDate = func(commit string) (time.Time, error) {
+				if commit != "!HEAD" {
+					return time.Time{}, errors.Errorf("unexpected commit %q", commit)
+				}
+				// One default half-life ago.
+				return now.AddDate(0, 0, -30), nil
+			}
+		}
+		return opts, nil
 	}
 
 	for _, tc := range cases {
@@ -417,6 +481,33 @@ func TestGetIndexOptions_batch(t *testing.T) {
 	}
 }
 
+func TestRepoPriority_stable(t *testing.T) {
+	now := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	t.Cleanup(func() { timeNow = time.Now })
+
+	c := &schema.SiteConfiguration{}
+	opts := &RepoIndexOptions{Stars: 10}
+	lastCommit := now.AddDate(0, 0, -3)
+
+	cases := []struct {
+		age  time.Duration
+		want float64
+	}{
+		{0, 10 + 100},
+		{time.Minute, 10 + 100},
+		{26 * 24 * time.Hour, 10 + 100},
+		{27 * 24 * time.Hour, 10 + 50},
+		{56 * 24 * time.Hour, 10 + 50},
+		{57 * 24 * time.Hour, 10 + 25},
+	}
+	for _, tc := range cases {
+		timeNow = func() time.Time { return now.Add(tc.age) }
+		if got := repoPriority(c, opts, lastCommit); got != tc.want {
+			t.Errorf("%s later: got priority %v, want %v", tc.age, got, tc.want)
+		}
+	}
+}
+
 func parseVersionContext(name string, repoRevStrs ...string) *schema.VersionContext {
 	var repoRevs []*schema.VersionContextRevision
 	for _, repo := range repoRevStrs {
@@ -439,3 +530,7 @@ func parseVersionContext(name string, repoRevStrs ...string) *schema.VersionCont
 func boolPtr(b bool) *bool {
 	return &b
 }
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
@@ -9,12 +9,15 @@ import (
 	"github.com/google/zoekt/rpc"
 	zoektstream "github.com/google/zoekt/stream"
 
+	"github.com/sourcegraph/sourcegraph/internal/requestid"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 )
 
 var zoektHTTPClient = &http.Client{
 	Transport: &ot.Transport{
-		RoundTripper: http.DefaultTransport,
+		RoundTripper: &requestid.Transport{
+			RoundTripper: http.DefaultTransport,
+		},
 	},
 }
 
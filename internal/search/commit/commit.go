@@ -231,8 +231,22 @@ func searchCommitsInRepoStream(ctx context.Context, db dbutil.DB, op search.Comm
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	// Start the commit search stream.
-	events := git.RawLogDiffSearchStream(ctx, diffParameters.Repo, diffParameters.Options)
+	// Search the commit index if it is up to date, otherwise start the
+	// commit search stream.
+	var events <-chan git.LogCommitSearchEvent
+	indexResults, ok, err := searchCommitIndex(ctx, db, &op, diffParameters.Options)
+	if err != nil {
+		tr.LogFields(otlog.String("commitIndexErr", err.Error()))
+	}
+	if ok && err == nil {
+		tr.LazyPrintf("searched commit index")
+		c := make(chan git.LogCommitSearchEvent, 1)
+		c <- git.LogCommitSearchEvent{Results: indexResults, Complete: true}
+		close(c)
+		events = c
+	} else {
+		events = git.RawLogDiffSearchStream(ctx, diffParameters.Repo, diffParameters.Options)
+	}
 
 	// Ensure we drain events if we return early (limitHit or error).
 	defer func() {
@@ -0,0 +1,316 @@
+package commit
+
+import (
+	"context"
+	"regexp"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// commitIndexPageSize is the number of candidate commits read from the commit
+// index at a time.
+const commitIndexPageSize = 100
+
+// indexQuery is a commit search expressed in terms the commit index can
+// answer. It is derived from the `git log` arguments built by
+// commitParametersToDiffParameters so that both search paths agree on the
+// meaning of a query.
+type indexQuery struct {
+	// messages must all match the commit message.
+	messages []*regexp.Regexp
+	// If non-empty, one of authors (committers) must match the author
+	// (committer) of a commit, formatted as "Name <email>".
+	authors    []*regexp.Regexp
+	committers []*regexp.Regexp
+	after      time.Time
+	before     time.Time
+	limit      int
+
+	// messageSubstrings are substrings every matching commit message
+	// contains, used to narrow down the candidates in the database.
+	messageSubstrings []string
+}
+
+// newIndexQuery returns the indexQuery equivalent to the `git log` args, or
+// false if the commit index can't answer it. Only searches of HEAD with
+// extended regexps and RFC 3339 dates are supported.
+func newIndexQuery(args []string) (*indexQuery, bool) {
+	var (
+		q                                indexQuery
+		messages, authors, committers    []string
+		ignoreCase, extendedRegexp       bool
+		seenAfter, seenBefore, seenLimit bool
+	)
+	for _, arg := range args {
+		name, value := arg, ""
+		if i := strings.Index(arg, "="); i >= 0 {
+			name, value = arg[:i], arg[i+1:]
+		}
+		switch name {
+		case "--no-prefix", "--unified", "--all-match":
+		case "--extended-regexp":
+			extendedRegexp = true
+		case "--regexp-ignore-case":
+			ignoreCase = true
+		case "--grep":
+			messages = append(messages, value)
+		case "--author":
+			authors = append(authors, value)
+		case "--committer":
+			committers = append(committers, value)
+		case "--max-count":
+			n, err := strconv.Atoi(value)
+			if err != nil || seenLimit {
+				return nil, false
+			}
+			q.limit, seenLimit = n, true
+		case "--since", "--until":
+			// git lets the last of repeated dates win, which is
+			// surprising enough that we leave it to git.
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, false
+			}
+			if name == "--since" {
+				if seenAfter {
+					return nil, false
+				}
+				q.after, seenAfter = t, true
+			} else {
+				if seenBefore {
+					return nil, false
+				}
+				q.before, seenBefore = t, true
+			}
+		case "HEAD":
+		default:
+			// Other revisions, ref globs and --invert-grep.
+			return nil, false
+		}
+	}
+	if !seenLimit || !extendedRegexp {
+		// Basic regexps differ from Go regexps.
+		return nil, false
+	}
+
+	compile := func(patterns []string) ([]*regexp.Regexp, bool) {
+		res := make([]*regexp.Regexp, 0, len(patterns))
+		for _, p := range patterns {
+			// git matches patterns line by line.
+			flags := "(?m)"
+			if ignoreCase {
+				flags = "(?mi)"
+			}
+			re, err := regexp.Compile(flags + p)
+			if err != nil {
+				return nil, false
+			}
+			res = append(res, re)
+		}
+		return res, true
+	}
+	var ok bool
+	if q.messages, ok = compile(messages); !ok {
+		return nil, false
+	}
+	if q.authors, ok = compile(authors); !ok {
+		return nil, false
+	}
+	if q.committers, ok = compile(committers); !ok {
+		return nil, false
+	}
+	for _, p := range messages {
+		q.messageSubstrings = append(q.messageSubstrings, requiredSubstrings(p)...)
+	}
+	return &q, true
+}
+
+// matchesCommit reports whether the metadata of the indexed commit matches
+// the query. The dates are checked by the database.
+func (q *indexQuery) matchesCommit(e *database.CommitIndexEntry) bool {
+	for _, re := range q.messages {
+		if !re.MatchString(e.Message) {
+			return false
+		}
+	}
+	return matchesAny(q.authors, e.AuthorName+" <"+e.AuthorEmail+">") &&
+		matchesAny(q.committers, e.CommitterName+" <"+e.CommitterEmail+">")
+}
+
+func matchesAny(res []*regexp.Regexp, s string) bool {
+	if len(res) == 0 {
+		return true
+	}
+	for _, re := range res {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// requiredSubstrings returns literal substrings of at least three ASCII
+// characters which every match of the regexp pattern contains. The database
+// matches them ignoring case, so case-sensitivity flags are ignored.
+func requiredSubstrings(pattern string) []string {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil
+	}
+	re = re.Simplify()
+
+	var nodes []*syntax.Regexp
+	switch re.Op {
+	case syntax.OpLiteral:
+		nodes = []*syntax.Regexp{re}
+	case syntax.OpConcat:
+		nodes = re.Sub
+	}
+
+	var substrings []string
+	for _, n := range nodes {
+		if n.Op != syntax.OpLiteral {
+			continue
+		}
+		s := string(n.Rune)
+		if len(s) < 3 || !isASCII(s) {
+			continue
+		}
+		substrings = append(substrings, s)
+	}
+	return substrings
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// searchCommitIndex runs the commit search against the commit index. It
+// returns false if the index is disabled, not up to date with the
+// repository's HEAD, doesn't reach far enough back in history or can't
+// express the search, in which case the caller must search with git instead.
+//
+// The index does not store ref decorations, so the results have no Refs.
+func searchCommitIndex(ctx context.Context, db dbutil.DB, op *search.CommitParameters, opt git.RawLogDiffSearchOptions) ([]*git.LogCommitSearchResult, bool, error) {
+	if !conf.CommitIndexEnabled() || opt.MatchChangedOccurrenceCount {
+		return nil, false, nil
+	}
+	hasPathFilters := opt.Paths.ExcludePattern != "" || len(opt.Paths.IncludePatterns) > 0
+	if !op.Diff && hasPathFilters {
+		// git filters these by the diff hunks matching the pattern, which
+		// is not worth replicating for commit searches.
+		return nil, false, nil
+	}
+	q, ok := newIndexQuery(opt.Args)
+	if !ok {
+		return nil, false, nil
+	}
+
+	store := database.CommitIndex(db)
+	state, err := store.GetState(ctx, op.RepoRevs.Repo.ID)
+	if err != nil || state == nil {
+		return nil, false, err
+	}
+	head, err := git.ResolveRevision(ctx, op.RepoRevs.GitserverRepo(), "HEAD", git.ResolveRevisionOptions{NoEnsureRevision: true})
+	if err != nil || head != state.IndexedCommit {
+		return nil, false, nil
+	}
+
+	searchOpt := database.CommitIndexSearchOptions{
+		RepoID:            op.RepoRevs.Repo.ID,
+		MessageSubstrings: q.messageSubstrings,
+		After:             q.after,
+		Before:            q.before,
+		WithDiffs:         op.Diff,
+		Limit:             commitIndexPageSize,
+	}
+	if op.Diff && opt.Query.Pattern != "" && opt.Query.IsRegExp {
+		searchOpt.DiffSubstrings = requiredSubstrings(opt.Query.Pattern)
+	}
+
+	var results []*git.LogCommitSearchResult
+	for len(results) < q.limit {
+		entries, err := store.Search(ctx, searchOpt)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, e := range entries {
+			if len(results) == q.limit {
+				break
+			}
+			if !q.matchesCommit(e) {
+				continue
+			}
+
+			r := &git.LogCommitSearchResult{
+				Commit:     indexEntryToCommit(e),
+				SourceRefs: []string{state.HeadRef},
+			}
+			if op.Diff {
+				if e.DiffTooLarge {
+					// The diff isn't stored, so only git can search it.
+					return nil, false, nil
+				}
+				if e.Diff == "" {
+					// Commits without changes only match if git would not
+					// have filtered them by their diff.
+					if opt.Query.Pattern != "" || hasPathFilters {
+						continue
+					}
+				} else {
+					r.Diff, r.DiffHighlights, err = git.FilterRawDiff([]byte(e.Diff), opt)
+					if err != nil {
+						return nil, false, err
+					}
+					if r.Diff == nil {
+						continue
+					}
+				}
+			}
+			results = append(results, r)
+		}
+		if len(entries) < commitIndexPageSize {
+			break
+		}
+		searchOpt.BeforeSeq = entries[len(entries)-1].Seq
+	}
+
+	// Unless the whole history is indexed, only a search which stopped
+	// early or was limited to the indexed time range saw every commit git
+	// would have.
+	covered := state.HistoryComplete ||
+		len(results) == q.limit ||
+		(!q.after.IsZero() && !state.OldestCommitDate.IsZero() && !q.after.Before(state.OldestCommitDate))
+	if !covered {
+		return nil, false, nil
+	}
+	return results, true, nil
+}
+
+func indexEntryToCommit(e *database.CommitIndexEntry) git.Commit {
+	c := git.Commit{
+		ID:        e.CommitID,
+		Author:    git.Signature{Name: e.AuthorName, Email: e.AuthorEmail, Date: e.AuthorDate.UTC()},
+		Committer: &git.Signature{Name: e.CommitterName, Email: e.CommitterEmail, Date: e.CommitterDate.UTC()},
+		Message:   git.Message(e.Message),
+	}
+	for _, p := range e.Parents {
+		c.Parents = append(c.Parents, api.CommitID(p))
+	}
+	return c
+}
@@ -0,0 +1,122 @@
+package commit
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+)
+
+func TestNewIndexQuery(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		args []string
+		ok   bool
+	}{
+		{
+			name: "diff search",
+			args: []string{"--no-prefix", "--max-count=31", "--unified=0", "--extended-regexp", "--regexp-ignore-case"},
+			ok:   true,
+		},
+		{
+			name: "explicit HEAD and dates",
+			args: []string{"--no-prefix", "--max-count=31", "--extended-regexp", "HEAD", "--until=2021-01-02T00:00:00Z", "--since=2020-01-02T00:00:00Z"},
+			ok:   true,
+		},
+		{
+			name: "message and author",
+			args: []string{"--no-prefix", "--max-count=31", "--extended-regexp", "--all-match", "--grep=fix", "--all-match", "--author=alice"},
+			ok:   true,
+		},
+		{
+			name: "other revision",
+			args: []string{"--no-prefix", "--max-count=31", "--extended-regexp", "main"},
+		},
+		{
+			name: "ref glob",
+			args: []string{"--no-prefix", "--max-count=31", "--extended-regexp", "--glob=refs/tags/*"},
+		},
+		{
+			name: "relative date",
+			args: []string{"--no-prefix", "--max-count=31", "--extended-regexp", "--since=1 week ago"},
+		},
+		{
+			name: "repeated date",
+			args: []string{"--no-prefix", "--max-count=31", "--extended-regexp", "--since=2020-01-02T00:00:00Z", "--since=2020-01-03T00:00:00Z"},
+		},
+		{
+			name: "inverted message",
+			args: []string{"--no-prefix", "--max-count=31", "--extended-regexp", "--all-match", "--invert-grep", "--grep=fix"},
+		},
+		{
+			name: "basic regexp",
+			args: []string{"--no-prefix", "--max-count=31", "--grep=fix"},
+		},
+		{
+			name: "invalid regexp",
+			args: []string{"--no-prefix", "--max-count=31", "--extended-regexp", "--grep=fix("},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, ok := newIndexQuery(tc.args)
+			if ok != tc.ok {
+				t.Errorf("got ok %v, want %v", ok, tc.ok)
+			}
+		})
+	}
+}
+
+func TestIndexQuery_matchesCommit(t *testing.T) {
+	q, ok := newIndexQuery([]string{
+		"--max-count=31",
+		"--extended-regexp",
+		"--regexp-ignore-case",
+		"--grep=^fix",
+		"--grep=bug",
+		"--author=alice@example\\.com",
+		"--author=bob",
+	})
+	if !ok {
+		t.Fatal("could not create index query")
+	}
+
+	entry := func(message, authorName, authorEmail string) *database.CommitIndexEntry {
+		return &database.CommitIndexEntry{Message: message, AuthorName: authorName, AuthorEmail: authorEmail}
+	}
+	for _, tc := range []struct {
+		name  string
+		entry *database.CommitIndexEntry
+		want  bool
+	}{
+		{"all match", entry("Fix bug", "Alice", "alice@example.com"), true},
+		{"second line and other author", entry("Update docs\nfix BUG", "Bob", "bob@example.com"), true},
+		{"one message pattern missing", entry("Fix typo", "Alice", "alice@example.com"), false},
+		{"anchor not at line start", entry("Hotfix bug", "Alice", "alice@example.com"), false},
+		{"no author matches", entry("Fix bug", "Carol", "carol@example.com"), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := q.matchesCommit(tc.entry); got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRequiredSubstrings(t *testing.T) {
+	for pattern, want := range map[string][]string{
+		"foobar":           {"foobar"},
+		`foo\.bar`:         {"foo.bar"},
+		"foo.*barbaz":      {"foo", "barbaz"},
+		"(?i)foo":          {"FOO"},
+		"foo|bar":          nil,
+		"ab.*cd":           nil,
+		"fo+":              nil,
+		"héllo":            nil,
+		"(":                nil,
+		"^func [a-z]+Test": {"func ", "Test"},
+	} {
+		if got := requiredSubstrings(pattern); !reflect.DeepEqual(got, want) {
+			t.Errorf("requiredSubstrings(%q) = %q, want %q", pattern, got, want)
+		}
+	}
+}
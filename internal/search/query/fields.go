@@ -20,6 +20,9 @@ const (
 	FieldVisibility         = "visibility"
 	FieldRev                = "rev"
 	FieldContext            = "context"
+	FieldSize               = "size"
+	FieldLines              = "lines"
+	FieldBinary             = "binary"
 
 	// For diff and commit search only:
 	FieldBefore    = "before"
@@ -33,7 +36,10 @@ const (
 	FieldCount     = "count" // Searches that specify `count:` will fetch at least that number of results, or the full result set
 	FieldTimeout   = "timeout"
 	FieldCombyRule = "rule"
+	FieldReplace   = "replace"
 	FieldSelect    = "select"
+	FieldAggregate = "aggregate"
+	FieldDebug     = "debug"
 )
 
 var allFields = map[string]struct{}{
@@ -54,6 +60,9 @@ var allFields = map[string]struct{}{
 	FieldPatternType:        empty,
 	FieldContent:            empty,
 	FieldVisibility:         empty,
+	FieldSize:               empty,
+	FieldLines:              empty,
+	FieldBinary:             empty,
 	FieldRepoHasFile:        empty,
 	FieldRepoHasCommitAfter: empty,
 	FieldBefore:             empty,
@@ -69,9 +78,12 @@ var allFields = map[string]struct{}{
 	FieldCount:              empty,
 	FieldTimeout:            empty,
 	FieldCombyRule:          empty,
+	FieldReplace:            empty,
 	FieldRev:                empty,
 	"revision":              empty,
 	FieldSelect:             empty,
+	FieldAggregate:          empty,
+	FieldDebug:              empty,
 }
 
 var aliases = map[string]string{
@@ -0,0 +1,181 @@
+package query
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// A Comparison is the value of a size: or lines: filter, like >1MB or
+// <=5000. It compares a file size in bytes or a number of lines with Value.
+type Comparison struct {
+	// Op is one of <, <=, >, >= or =.
+	Op    string
+	Value int64
+}
+
+// sizeUnits are the units accepted by size: filters. They are powers of 1024,
+// like the limits on the sizes of searched files.
+var sizeUnits = []struct {
+	suffix string
+	factor int64
+}{
+	// Longer suffixes come first so that we don't match B in KB.
+	{"KB", 1 << 10},
+	{"MB", 1 << 20},
+	{"GB", 1 << 30},
+	{"K", 1 << 10},
+	{"M", 1 << 20},
+	{"G", 1 << 30},
+	{"B", 1},
+}
+
+// ParseSizeComparison parses the value of a size: filter, like >1MB. Sizes
+// without a unit are in bytes.
+func ParseSizeComparison(value string) (Comparison, error) {
+	op, number := splitComparisonOp(value)
+	factor := int64(1)
+	upper := strings.ToUpper(number)
+	for _, u := range sizeUnits {
+		if strings.HasSuffix(upper, u.suffix) {
+			number, factor = number[:len(number)-len(u.suffix)], u.factor
+			break
+		}
+	}
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil || f < 0 || math.IsInf(f, 0) || math.IsNaN(f) || f*float64(factor) > math.MaxInt64 {
+		return Comparison{}, errors.Errorf("invalid size %q. Use a comparison with a size in bytes, KB, MB or GB, like >1MB or <=512KB", value)
+	}
+	return Comparison{Op: op, Value: int64(math.Round(f * float64(factor)))}, nil
+}
+
+// ParseLinesComparison parses the value of a lines: filter, like >5000.
+func ParseLinesComparison(value string) (Comparison, error) {
+	op, number := splitComparisonOp(value)
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil || n < 0 {
+		return Comparison{}, errors.Errorf("invalid line count %q. Use a comparison with a number of lines, like >5000", value)
+	}
+	return Comparison{Op: op, Value: n}, nil
+}
+
+func splitComparisonOp(value string) (op, number string) {
+	for _, op := range []string{"<=", ">=", "<", ">", "="} {
+		if strings.HasPrefix(value, op) {
+			return op, strings.TrimSpace(value[len(op):])
+		}
+	}
+	return "=", value
+}
+
+// Matches reports whether n satisfies the comparison.
+func (c Comparison) Matches(n int64) bool {
+	switch c.Op {
+	case "<":
+		return n < c.Value
+	case "<=":
+		return n <= c.Value
+	case ">":
+		return n > c.Value
+	case ">=":
+		return n >= c.Value
+	default:
+		return n == c.Value
+	}
+}
+
+// String returns the comparison in a form which ParseSizeComparison and
+// ParseLinesComparison parse back to c.
+func (c Comparison) String() string {
+	return c.Op + strconv.FormatInt(c.Value, 10)
+}
+
+// FileFilters are the filters on the size, line count and contents of files
+// given by the size:, lines: and binary: parameters of a query.
+type FileFilters struct {
+	// Sizes and Lines must all match the size in bytes and the number of
+	// lines of a file.
+	Sizes []Comparison
+	Lines []Comparison
+	// Binary, if non-nil, is whether files must be binary.
+	Binary *bool
+}
+
+func (f FileFilters) IsEmpty() bool {
+	return len(f.Sizes) == 0 && len(f.Lines) == 0 && f.Binary == nil
+}
+
+// NeedContent reports whether applying the filters requires the contents of
+// files. Indexed search can't apply such filters before its match limits, so
+// queries with size: or lines: filters are run by unindexed search.
+func (f FileFilters) NeedContent() bool {
+	return len(f.Sizes) > 0 || len(f.Lines) > 0
+}
+
+// Matches reports whether a file with the size in bytes and number of lines
+// passes the filters. A negative size or number of lines means that it is
+// unknown, in which case the file may match and passes the size: or lines:
+// filters.
+func (f FileFilters) Matches(size int64, lines int, binary bool) bool {
+	if f.Binary != nil && *f.Binary != binary {
+		return false
+	}
+	for _, c := range f.Sizes {
+		if size >= 0 && !c.Matches(size) {
+			return false
+		}
+	}
+	for _, c := range f.Lines {
+		if lines >= 0 && !c.Matches(int64(lines)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f FileFilters) String() string {
+	var args []string
+	for _, c := range f.Sizes {
+		args = append(args, "size:"+c.String())
+	}
+	for _, c := range f.Lines {
+		args = append(args, "lines:"+c.String())
+	}
+	if f.Binary != nil {
+		args = append(args, fmt.Sprintf("binary:%t", *f.Binary))
+	}
+	return strings.Join(args, " ")
+}
+
+// LineCount returns the number of lines of the file contents. A final line
+// without a trailing newline counts as a line.
+func LineCount(content []byte) int {
+	n := bytes.Count(content, []byte{'\n'})
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		n++
+	}
+	return n
+}
+
+// FileFilters returns the file filters of the query. It expects a valid query.
+func (b Basic) FileFilters() FileFilters {
+	var f FileFilters
+	for _, p := range b.Parameters {
+		switch p.Field {
+		case FieldSize:
+			c, _ := ParseSizeComparison(p.Value) // Invariant: size is validated.
+			f.Sizes = append(f.Sizes, c)
+		case FieldLines:
+			c, _ := ParseLinesComparison(p.Value) // Invariant: lines is validated.
+			f.Lines = append(f.Lines, c)
+		case FieldBinary:
+			binary, _ := parseBool(p.Value) // Invariant: binary is validated.
+			f.Binary = &binary
+		}
+	}
+	return f
+}
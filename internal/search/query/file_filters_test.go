@@ -0,0 +1,106 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseSizeComparison(t *testing.T) {
+	cases := []struct {
+		value string
+		want  Comparison
+		err   bool
+	}{
+		{value: ">1MB", want: Comparison{Op: ">", Value: 1 << 20}},
+		{value: "<10KB", want: Comparison{Op: "<", Value: 10 << 10}},
+		{value: ">=1.5k", want: Comparison{Op: ">=", Value: 1536}},
+		{value: "<=2gb", want: Comparison{Op: "<=", Value: 2 << 30}},
+		{value: "100", want: Comparison{Op: "=", Value: 100}},
+		{value: "=100B", want: Comparison{Op: "=", Value: 100}},
+		{value: ">", err: true},
+		{value: ">1TB", err: true},
+		{value: "<-1", err: true},
+		{value: "big", err: true},
+	}
+	for _, c := range cases {
+		t.Run(c.value, func(t *testing.T) {
+			got, err := ParseSizeComparison(c.value)
+			if (err != nil) != c.err {
+				t.Fatalf("got error %v, want error: %t", err, c.err)
+			}
+			if diff := cmp.Diff(c.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+			if err == nil {
+				// The canonical form is what we send to searcher.
+				roundTrip, err := ParseSizeComparison(got.String())
+				if err != nil || roundTrip != got {
+					t.Fatalf("%q parsed as %v, %v", got.String(), roundTrip, err)
+				}
+			}
+		})
+	}
+}
+
+func TestParseLinesComparison(t *testing.T) {
+	if got, err := ParseLinesComparison(">5000"); err != nil || got != (Comparison{Op: ">", Value: 5000}) {
+		t.Fatalf("got %v, %v", got, err)
+	}
+	for _, value := range []string{">1k", "<1.5", "lots"} {
+		if _, err := ParseLinesComparison(value); err == nil {
+			t.Errorf("expected %q to be invalid", value)
+		}
+	}
+}
+
+func TestFileFilters(t *testing.T) {
+	plan, err := Pipeline(InitLiteral("size:>1MB size:<=2MB lines:>5000 binary:no foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	no := false
+	want := FileFilters{
+		Sizes:  []Comparison{{Op: ">", Value: 1 << 20}, {Op: "<=", Value: 2 << 20}},
+		Lines:  []Comparison{{Op: ">", Value: 5000}},
+		Binary: &no,
+	}
+	got := plan[0].FileFilters()
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatal(diff)
+	}
+
+	for _, tc := range []struct {
+		size   int64
+		lines  int
+		binary bool
+		want   bool
+	}{
+		{size: 1<<20 + 1, lines: 6000, want: true},
+		{size: 1 << 20, lines: 6000, want: false},
+		{size: 3 << 20, lines: 6000, want: false},
+		{size: 1<<20 + 1, lines: 100, want: false},
+		{size: 1<<20 + 1, lines: -1, want: true},
+		{size: -1, lines: 6000, want: true},
+		{size: 1<<20 + 1, lines: 6000, binary: true, want: false},
+	} {
+		if got := want.Matches(tc.size, tc.lines, tc.binary); got != tc.want {
+			t.Errorf("Matches(%d, %d, %t) = %t, want %t", tc.size, tc.lines, tc.binary, got, tc.want)
+		}
+	}
+}
+
+func TestLineCount(t *testing.T) {
+	for content, want := range map[string]int{
+		"":       0,
+		"a":      1,
+		"a\n":    1,
+		"a\nb":   2,
+		"a\n\n":  2,
+		"\n\n\n": 3,
+	} {
+		if got := LineCount([]byte(content)); got != want {
+			t.Errorf("LineCount(%q) = %d, want %d", content, got, want)
+		}
+	}
+}
@@ -15,6 +15,7 @@ const (
 	HeuristicHoisted
 	Structural
 	IsPredicate
+	Proximity // A pattern substituted for a NEAR operator.
 )
 
 var allLabels = map[labels]string{
@@ -27,6 +28,7 @@ var allLabels = map[labels]string{
 	HeuristicHoisted:          "HeuristicHoisted",
 	Structural:                "Structural",
 	IsPredicate:               "IsPredicate",
+	Proximity:                 "Proximity",
 }
 
 func (l *labels) IsSet(label labels) bool {
@@ -0,0 +1,273 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MacroError is returned when a query references a search macro which is
+// not defined, which expands to itself or whose expansion is too large. Range is the location of the
+// offending reference in the query the user typed. For macros referenced by
+// other macros, it is the location of the outermost reference.
+type MacroError struct {
+	Msg   string
+	Range Range
+}
+
+func (e *MacroError) Error() string {
+	return e.Msg
+}
+
+// ExpandMacros replaces references to search macros like $backend-code in
+// the query with their definitions in macros, which maps macro names
+// (without $) to query text. Definitions may reference other macros.
+//
+// A reference is a $ followed by a name at the start of a query term, so
+// patterns like foo$ or quoted strings like "$bar" are left alone. If no
+// macros are defined, the query is returned unchanged.
+//
+// Terms like $HOME or $scope.foo that name no macro are searched for
+// literally, since they are common in code. They are only reported as
+// undefined macros if the user evidently meant a macro: the name is used by
+// another macro, contains a dash, or is one edit away from a defined name.
+//
+// Expansions containing and or or are wrapped in parentheses, so that the
+// operators don't bind to the terms around the reference.
+//
+// Macros may be nested at most maxMacroDepth deep, and the expanded query
+// may be at most maxMacroExpansionFactor times as long as the input, but
+// never less than maxMacroExpansionLength bytes, so that definitions which
+// reference other macros several times can't blow up exponentially.
+//
+// ExpandMacros returns the expanded query and the names of the macros used,
+// in the order in which they were first expanded.
+func ExpandMacros(in string, macros map[string]string) (string, []string, error) {
+	if len(macros) == 0 || !strings.Contains(in, "$") {
+		return in, nil, nil
+	}
+	limit := maxMacroExpansionLength
+	if l := maxMacroExpansionFactor * len(in); l > limit {
+		limit = l
+	}
+	e := macroExpander{macros: macros, seen: map[string]struct{}{}, limit: limit}
+	out, err := e.expand(in, nil, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	return out, e.used, nil
+}
+
+const (
+	maxMacroDepth           = 10
+	maxMacroExpansionLength = 4096
+	maxMacroExpansionFactor = 4
+)
+
+type macroExpander struct {
+	macros map[string]string
+	seen   map[string]struct{}
+	used   []string
+	limit  int // maximum length of the expanded query
+}
+
+// expand expands the macro references in s. stack holds the names of the
+// macros being expanded, and outer is the range of the outermost reference
+// if s is the definition of a macro.
+func (e *macroExpander) expand(s string, stack []string, outer *Range) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+		if (c == '"' || c == '\'') && (i == 0 || isMacroBoundary(s[i-1]) || s[i-1] == ':') {
+			end := scanQuoted(s, i)
+			b.WriteString(s[i:end])
+			i = end
+			continue
+		}
+
+		if c != '$' || (i > 0 && !isMacroBoundary(s[i-1])) {
+			b.WriteByte(c)
+			i++
+			continue
+		}
+		n := scanMacroName(s[i+1:])
+		end := i + 1 + n
+		if n == 0 || (end < len(s) && !isMacroBoundary(s[end]) && s[end] != ')') {
+			b.WriteByte(c)
+			i++
+			continue
+		}
+
+		name := s[i+1 : end]
+		r := newRange(i, end)
+		if outer != nil {
+			r = *outer
+		}
+		definition, ok, err := e.lookup(name, stack, r)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			b.WriteString(s[i:end])
+			i = end
+			continue
+		}
+		if len(stack) >= maxMacroDepth {
+			return "", &MacroError{
+				Msg:   fmt.Sprintf("search macro $%s is nested more than %d levels deep", name, maxMacroDepth),
+				Range: r,
+			}
+		}
+		expanded, err := e.expand(definition, append(stack, name), &r)
+		if err != nil {
+			return "", err
+		}
+		if hasOperator(expanded) {
+			expanded = "(" + expanded + ")"
+		}
+		b.WriteString(expanded)
+		if b.Len() > e.limit {
+			outermost := name
+			if len(stack) > 0 {
+				outermost = stack[0]
+			}
+			return "", &MacroError{
+				Msg:   fmt.Sprintf("search macro $%s expands to a query longer than %d bytes", outermost, e.limit),
+				Range: r,
+			}
+		}
+		i = end
+	}
+	return b.String(), nil
+}
+
+// lookup returns the definition of the macro name. It returns false if name
+// is not a macro and the reference should be searched for literally.
+func (e *macroExpander) lookup(name string, stack []string, r Range) (string, bool, error) {
+	for i, s := range stack {
+		if s == name {
+			cycle := make([]string, 0, len(stack)-i+1)
+			for _, s := range append(stack[i:], name) {
+				cycle = append(cycle, "$"+s)
+			}
+			return "", false, &MacroError{
+				Msg:   fmt.Sprintf("search macro $%s references itself: %s", name, strings.Join(cycle, " -> ")),
+				Range: r,
+			}
+		}
+	}
+
+	definition, ok := e.macros[name]
+	if !ok {
+		if len(stack) > 0 {
+			return "", false, &MacroError{
+				Msg:   fmt.Sprintf("search macro $%s used by $%s is not defined", name, stack[len(stack)-1]),
+				Range: r,
+			}
+		}
+		if e.meantMacro(name) {
+			return "", false, &MacroError{Msg: fmt.Sprintf("search macro $%s is not defined", name), Range: r}
+		}
+		return "", false, nil
+	}
+	if _, ok := e.seen[name]; !ok {
+		e.seen[name] = struct{}{}
+		e.used = append(e.used, name)
+	}
+	return strings.TrimSpace(definition), true, nil
+}
+
+// meantMacro reports whether the undefined name was evidently meant to
+// reference a macro rather than be searched for, because it contains a
+// dash, which variable names in code don't, or is a typo of a defined name.
+func (e *macroExpander) meantMacro(name string) bool {
+	if strings.Contains(name, "-") {
+		return true
+	}
+	for defined := range e.macros {
+		if withinOneEdit(name, defined) {
+			return true
+		}
+	}
+	return false
+}
+
+// withinOneEdit reports whether a can be turned into b by inserting, deleting
+// or substituting at most one byte.
+func withinOneEdit(a, b string) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	if len(b)-len(a) > 1 {
+		return false
+	}
+	i := 0
+	for i < len(a) && a[i] == b[i] {
+		i++
+	}
+	if len(a) == len(b) {
+		return a[i:] == "" || a[i+1:] == b[i+1:]
+	}
+	return a[i:] == b[i+1:]
+}
+
+// hasOperator reports whether the query text s contains an and or or
+// operator outside of quoted strings.
+func hasOperator(s string) bool {
+	for i := 0; i < len(s); {
+		if (s[i] == '"' || s[i] == '\'') && (i == 0 || isMacroBoundary(s[i-1]) || s[i-1] == ':') {
+			i = scanQuoted(s, i)
+			continue
+		}
+		if i > 0 && !isMacroBoundary(s[i-1]) {
+			i++
+			continue
+		}
+		end := i
+		for end < len(s) && !isMacroBoundary(s[end]) && s[end] != ')' {
+			end++
+		}
+		if word := strings.ToLower(s[i:end]); word == "and" || word == "or" {
+			return true
+		}
+		if end == i {
+			end++
+		}
+		i = end
+	}
+	return false
+}
+
+func isMacroBoundary(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '('
+}
+
+// scanMacroName returns the length of the macro name at the start of s. Names
+// start with a letter or underscore, followed by letters, digits,
+// underscores, dashes and dots.
+func scanMacroName(s string) int {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_':
+		case i > 0 && (c >= '0' && c <= '9' || c == '-' || c == '.'):
+		default:
+			return i
+		}
+	}
+	return len(s)
+}
+
+// scanQuoted returns the position after the quoted string starting at
+// s[start], or len(s) if it is not terminated.
+func scanQuoted(s string, start int) int {
+	quote := s[start]
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		}
+	}
+	return len(s)
+}
@@ -0,0 +1,172 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExpandMacros(t *testing.T) {
+	macros := map[string]string{
+		"backend-code":  `-file:_test\.go$ -file:vendor/ $backend-repos`,
+		"backend-repos": ` repo:^github\.com/acme/(api|web)$ `,
+		"either":        "a or b",
+		"both":          `a and "b or c"`,
+		"nested":        "repo:y and $either",
+		"orange":        "repo:orange",
+	}
+
+	cases := []struct {
+		input    string
+		macros   map[string]string
+		want     string
+		wantUsed []string
+	}{
+		{
+			input:    "$backend-code fmt.Sprintf",
+			want:     `-file:_test\.go$ -file:vendor/ repo:^github\.com/acme/(api|web)$ fmt.Sprintf`,
+			wantUsed: []string{"backend-code", "backend-repos"},
+		},
+		{
+			input:    "(a or b) and ($backend-repos)",
+			want:     `(a or b) and (repo:^github\.com/acme/(api|web)$)`,
+			wantUsed: []string{"backend-repos"},
+		},
+		{
+			input: `file:foo$ foo$bar "$backend-code" content:'$backend-code' -$backend-code`,
+			want:  `file:foo$ foo$bar "$backend-code" content:'$backend-code' -$backend-code`,
+		},
+		{
+			// Expansions with operators are grouped.
+			input:    "repo:x $either",
+			want:     "repo:x (a or b)",
+			wantUsed: []string{"either"},
+		},
+		{
+			input:    "$both or c",
+			want:     `(a and "b or c") or c`,
+			wantUsed: []string{"both"},
+		},
+		{
+			input:    "$nested",
+			want:     `(repo:y and (a or b))`,
+			wantUsed: []string{"nested", "either"},
+		},
+		{
+			// Names of no macro are searched for literally.
+			input: "$HOME $http $scope.foo echo $1",
+			want:  "$HOME $http $scope.foo echo $1",
+		},
+		{
+			input: "$x $1",
+			want:  "$x $1",
+			// Without any macros, queries are not expanded.
+			macros: map[string]string{},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.input, func(t *testing.T) {
+			m := macros
+			if c.macros != nil {
+				m = c.macros
+			}
+			got, used, err := ExpandMacros(c.input, m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(c.want, got); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff(c.wantUsed, used); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestExpandMacros_errors(t *testing.T) {
+	macros := map[string]string{
+		"loop":   "$loop2",
+		"loop2":  "foo $loop",
+		"self":   "$self",
+		"broken": "$missing",
+		"a":      "$b $b",
+		"b":      "$c $c",
+		"c":      "$d $d",
+		"d":      "$e $e",
+		"e":      "$f $f",
+		"f":      "$g $g",
+		"g":      "$h $h",
+		"h":      "$i $i",
+		"i":      "$j $j",
+		"j":      "0123456789",
+		"n0":     "$n1",
+		"n1":     "$n2",
+		"n2":     "$n3",
+		"n3":     "$n4",
+		"n4":     "$n5",
+		"n5":     "$n6",
+		"n6":     "$n7",
+		"n7":     "$n8",
+		"n8":     "$n9",
+		"n9":     "$n10",
+		"n10":    "deep",
+	}
+
+	cases := []struct {
+		input string
+		want  *MacroError
+	}{
+		{
+			input: "foo $undefined-macro",
+			want:  &MacroError{Msg: "search macro $undefined-macro is not defined", Range: newRange(4, 20)},
+		},
+		{
+			input: "foo $lop",
+			want:  &MacroError{Msg: "search macro $lop is not defined", Range: newRange(4, 8)},
+		},
+		{
+			input: "$broken",
+			want:  &MacroError{Msg: "search macro $missing used by $broken is not defined", Range: newRange(0, 7)},
+		},
+		{
+			input: "a $loop",
+			want:  &MacroError{Msg: "search macro $loop references itself: $loop -> $loop2 -> $loop", Range: newRange(2, 7)},
+		},
+		{
+			input: "$self",
+			want:  &MacroError{Msg: "search macro $self references itself: $self -> $self", Range: newRange(0, 5)},
+		},
+		{
+			input: "x $a",
+			want:  &MacroError{Msg: "search macro $a expands to a query longer than 4096 bytes", Range: newRange(2, 4)},
+		},
+		{
+			input: "$n0",
+			want:  &MacroError{Msg: "search macro $n10 is nested more than 10 levels deep", Range: newRange(0, 3)},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.input, func(t *testing.T) {
+			_, _, err := ExpandMacros(c.input, macros)
+			if diff := cmp.Diff(c.want, err); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestExpandMacros_precedence(t *testing.T) {
+	macros := map[string]string{"m": "a or b"}
+	expanded, _, err := ExpandMacros("repo:x $m", macros)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := ParseRegexp(expanded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := toString(q), `(and "repo:x" (or "a" "b"))`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
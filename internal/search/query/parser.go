@@ -3,6 +3,8 @@ package query
 import (
 	"fmt"
 	"io"
+	"math"
+	"regexp"
 	"strconv"
 	"strings"
 	"unicode"
@@ -15,7 +17,8 @@ import (
 Parser implements a parser for the following grammar:
 
 OrTerm     → AndTerm { OR AndTerm }
-AndTerm    → Term { AND Term }
+AndTerm    → NearTerm { AND NearTerm }
+NearTerm   → Term { NEAR/N Term }
 Term       → (OrTerm) | Parameters
 Parameters → Parameter { " " Parameter }
 */
@@ -116,8 +119,13 @@ const (
 	DQUOTE keyword = "\""
 	SLASH  keyword = "/"
 	NOT    keyword = "not"
+	NEAR   keyword = "near/" // Followed by the distance, as in "NEAR/5".
 )
 
+// maxNearDistance is the maximum number of lines between the operands of a
+// NEAR operator.
+const maxNearDistance = 100
+
 func isSpace(buf []byte) bool {
 	r, _ := utf8.DecodeRune(buf)
 	return unicode.IsSpace(r)
@@ -233,6 +241,36 @@ func (p *parser) matchKeyword(keyword keyword) bool {
 	return strings.EqualFold(v, string(keyword))
 }
 
+// matchNear is like matchKeyword for the NEAR/N operator. It returns the
+// distance N and the length of the operator.
+func (p *parser) matchNear() (distance, advance int, ok bool) {
+	if p.pos == 0 || !isSpace(p.buf[p.pos-1:p.pos]) {
+		return 0, 0, false
+	}
+	return ScanNear(p.buf[p.pos:])
+}
+
+// ScanNear scans a NEAR/N operator that is followed by whitespace at the start
+// of buf. It returns the distance N and how much it consumed.
+func ScanNear(buf []byte) (distance, count int, ok bool) {
+	count = len(NEAR)
+	if len(buf) < count || !strings.EqualFold(string(buf[:count]), string(NEAR)) {
+		return 0, 0, false
+	}
+	for count < len(buf) && '0' <= buf[count] && buf[count] <= '9' {
+		count++
+	}
+	if count == len(NEAR) || count >= len(buf) || !isSpace(buf[count:count+1]) {
+		return 0, 0, false
+	}
+	distance, err := strconv.Atoi(string(buf[len(NEAR):count]))
+	if err != nil {
+		// The distance is out of range, which is rejected by the parser.
+		distance = math.MaxInt32
+	}
+	return distance, count, true
+}
+
 // matchUnaryKeyword is like match but expects the keyword to be followed by whitespace.
 func (p *parser) matchUnaryKeyword(keyword keyword) bool {
 	if p.pos != 0 && !(isSpace(p.buf[p.pos-1:p.pos]) || p.buf[p.pos-1] == '(') {
@@ -340,6 +378,10 @@ func ScanBalancedPattern(buf []byte) (scanned string, count int, ok bool) {
 			// This "pattern" contains a recognized keyword, reject it.
 			return false
 		}
+		if _, _, ok := ScanNear(buf); ok {
+			// This "pattern" contains a NEAR operator, reject it.
+			return false
+		}
 		return true
 	}
 
@@ -944,6 +986,9 @@ loop:
 		case p.matchKeyword(AND), p.matchKeyword(OR):
 			// Caller advances.
 			break loop
+		case isNear(p.matchNear()):
+			// Caller advances.
+			break loop
 		case p.matchUnaryKeyword(NOT):
 			start := p.pos
 			_ = p.expect(NOT)
@@ -1055,21 +1100,145 @@ func newOperator(nodes []Node, kind operatorKind) []Node {
 	return []Node{Operator{Kind: kind, Operands: reduced}}
 }
 
-// parseAnd parses and-expressions.
-func (p *parser) parseAnd() ([]Node, error) {
-	var left []Node
-	var err error
+// isNear returns the ok result of matchNear.
+func isNear(_, _ int, ok bool) bool {
+	return ok
+}
+
+var errNearOperand = errors.New("the NEAR operator requires a search pattern on each side, as in foo NEAR/3 bar. The patterns may not be negated or contain and/or expressions")
+
+// nearOperand partitions the nodes of an operand of a NEAR operator into its
+// parameters and its pattern, which it returns as a regular expression.
+func (p *parser) nearOperand(nodes []Node) (parameters []Node, pattern Pattern, err error) {
+	var patterns []Pattern
+	for len(nodes) > 0 {
+		node := nodes[0]
+		nodes = nodes[1:]
+		switch v := node.(type) {
+		case Parameter:
+			parameters = append(parameters, v)
+		case Pattern:
+			if v.Negated {
+				return nil, Pattern{}, errNearOperand
+			}
+			patterns = append(patterns, v)
+		case Operator:
+			switch {
+			case !containsPattern(v):
+				parameters = append(parameters, v)
+			case v.Kind == And:
+				// Parameters and patterns of a term are grouped by an
+				// and-expression, see partitionParameters.
+				nodes = append(nodes, v.Operands...)
+			case v.Kind == Concat:
+				var ps []Pattern
+				for _, operand := range v.Operands {
+					p, ok := operand.(Pattern)
+					if !ok || p.Negated {
+						return nil, Pattern{}, errNearOperand
+					}
+					ps = append(ps, p)
+				}
+				if p.leafParser == SearchTypeRegex {
+					patterns = append(patterns, fuzzyRegexp(ps))
+				} else {
+					patterns = append(patterns, space(ps))
+				}
+			default:
+				return nil, Pattern{}, errNearOperand
+			}
+		}
+	}
+	if len(patterns) != 1 {
+		return nil, Pattern{}, errNearOperand
+	}
+
+	pattern = patterns[0]
+	if pattern.Annotation.Labels.IsSet(Literal) {
+		pattern.Value = regexp.QuoteMeta(pattern.Value)
+	} else if !pattern.Annotation.Labels.IsSet(Quoted | Proximity) {
+		pattern.Value = escapeParens(pattern.Value)
+	}
+	return parameters, pattern, nil
+}
+
+// newNear returns a pattern that matches where the patterns of the left and
+// right operands occur at most distance lines apart, in any order, together
+// with the parameters of the operands. The match covers the region from the
+// first to the last pattern, so that both Zoekt and searcher evaluate it as a
+// regular expression.
+func (p *parser) newNear(left, right []Node, distance int) ([]Node, error) {
+	if p.leafParser == SearchTypeStructural {
+		return nil, errors.New("the NEAR operator is not supported for structural search")
+	}
+	if distance > maxNearDistance {
+		return nil, errors.Errorf("the NEAR operator supports a distance of at most %d lines", maxNearDistance)
+	}
+
+	leftParameters, leftPattern, err := p.nearOperand(left)
+	if err != nil {
+		return nil, err
+	}
+	rightParameters, rightPattern, err := p.nearOperand(right)
+	if err != nil {
+		return nil, err
+	}
+
+	// Up to distance line breaks between the patterns.
+	between := fmt.Sprintf(`(?:[^\n]*\n){0,%d}?[^\n]*?`, distance)
+	a, b := leftPattern.Value, rightPattern.Value
+	pattern := Pattern{
+		Value: fmt.Sprintf("(?:(?:%s)%s(?:%s)|(?:%s)%s(?:%s))", a, between, b, b, between, a),
+		Annotation: Annotation{
+			Labels: Regexp | Proximity,
+			Range:  Range{Start: leftPattern.Annotation.Range.Start, End: rightPattern.Annotation.Range.End},
+		},
+	}
+	parameters := append(leftParameters, rightParameters...)
+	return newOperator(append(parameters, pattern), And), nil
+}
+
+// parseNear parses proximity expressions. NEAR operators have higher
+// precedence than And operators, therefore parseAnd calls this function.
+func (p *parser) parseNear() ([]Node, error) {
+	var label labels = Literal
 	if p.leafParser == SearchTypeRegex {
-		left, err = p.parseLeaves(Regexp)
-	} else {
-		left, err = p.parseLeaves(Literal)
+		label = Regexp
 	}
+
+	left, err := p.parseLeaves(label)
 	if err != nil {
 		return nil, err
 	}
 	if left == nil {
 		return nil, &ExpectedOperand{Msg: fmt.Sprintf("expected operand at %d", p.pos)}
 	}
+	for {
+		distance, advance, ok := p.matchNear()
+		if !ok {
+			return left, nil
+		}
+		p.pos += advance
+		right, err := p.parseLeaves(label)
+		if err != nil {
+			return nil, err
+		}
+		if right == nil {
+			return nil, &ExpectedOperand{Msg: fmt.Sprintf("expected operand at %d", p.pos)}
+		}
+		left, err = p.newNear(left, right, distance)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseAnd parses and-expressions.
+func (p *parser) parseAnd() ([]Node, error) {
+	left, err := p.parseNear()
+	if err != nil {
+		return nil, err
+	}
 	if !p.expect(AND) {
 		return left, nil
 	}
@@ -594,6 +594,48 @@ func TestMatchUnaryKeyword(t *testing.T) {
 	autogold.Want("(not bar)", "true").Equal(t, test("(not bar)", 1))
 }
 
+func TestScanNear(t *testing.T) {
+	test := func(input string) string {
+		distance, count, ok := ScanNear([]byte(input))
+		if !ok {
+			return "ERROR"
+		}
+		return fmt.Sprintf("distance %d, count %d", distance, count)
+	}
+
+	autogold.Want("NEAR/5 bar", "distance 5, count 6").Equal(t, test("NEAR/5 bar"))
+	autogold.Want("near/10 bar", "distance 10, count 7").Equal(t, test("near/10 bar"))
+	autogold.Want("NEAR/ bar", "ERROR").Equal(t, test("NEAR/ bar"))
+	autogold.Want("NEAR/5", "ERROR").Equal(t, test("NEAR/5"))
+	autogold.Want("NEAR/5bar", "ERROR").Equal(t, test("NEAR/5bar"))
+	autogold.Want("NEAR bar", "ERROR").Equal(t, test("NEAR bar"))
+}
+
+func TestParseNear(t *testing.T) {
+	test := func(input string, searchType SearchType) string {
+		result, err := Parse(input, searchType)
+		if err != nil {
+			return fmt.Sprintf("ERROR: %s", err.Error())
+		}
+		return fmt.Sprintf("%s (%s)", toString(result), labelsToString(result))
+	}
+
+	autogold.Want("foo NEAR/2 bar", `"(?:(?:foo)(?:[^\\n]*\\n){0,2}?[^\\n]*?(?:bar)|(?:bar)(?:[^\\n]*\\n){0,2}?[^\\n]*?(?:foo))" (Proximity,Regexp)`).Equal(t, test("foo NEAR/2 bar", SearchTypeLiteral))
+	autogold.Want("foo.* near/0 /bar/", `"(?:(?:foo.*)(?:[^\\n]*\\n){0,0}?[^\\n]*?(?:bar)|(?:bar)(?:[^\\n]*\\n){0,0}?[^\\n]*?(?:foo.*))" (Proximity,Regexp)`).Equal(t, test("foo.* near/0 /bar/", SearchTypeRegex))
+	autogold.Want("repo:x foo( NEAR/1 bar file:y", `(and "repo:x" "file:y" "(?:(?:foo\\()(?:[^\\n]*\\n){0,1}?[^\\n]*?(?:bar)|(?:bar)(?:[^\\n]*\\n){0,1}?[^\\n]*?(?:foo\\())") (Proximity,Regexp)`).Equal(t, test("repo:x foo( NEAR/1 bar file:y", SearchTypeLiteral))
+	autogold.Want("foo bar NEAR/1 baz", `"(?:(?:foo bar)(?:[^\\n]*\\n){0,1}?[^\\n]*?(?:baz)|(?:baz)(?:[^\\n]*\\n){0,1}?[^\\n]*?(?:foo bar))" (Proximity,Regexp)`).Equal(t, test("foo bar NEAR/1 baz", SearchTypeLiteral))
+	autogold.Want("a NEAR/1 b NEAR/1 c", `"(?:(?:(?:(?:a)(?:[^\\n]*\\n){0,1}?[^\\n]*?(?:b)|(?:b)(?:[^\\n]*\\n){0,1}?[^\\n]*?(?:a)))(?:[^\\n]*\\n){0,1}?[^\\n]*?(?:c)|(?:c)(?:[^\\n]*\\n){0,1}?[^\\n]*?(?:(?:(?:a)(?:[^\\n]*\\n){0,1}?[^\\n]*?(?:b)|(?:b)(?:[^\\n]*\\n){0,1}?[^\\n]*?(?:a))))" (Proximity,Regexp)`).Equal(t, test("a NEAR/1 b NEAR/1 c", SearchTypeLiteral))
+	autogold.Want("a NEAR/1 b and c", `(and "(?:(?:a)(?:[^\\n]*\\n){0,1}?[^\\n]*?(?:b)|(?:b)(?:[^\\n]*\\n){0,1}?[^\\n]*?(?:a))" "c") (HeuristicHoisted,Literal,Proximity,Regexp)`).Equal(t, test("a NEAR/1 b and c", SearchTypeLiteral))
+	autogold.Want("x (a NEAR/1 b)", `(concat "x" "(?:(?:a)(?:[^\\n]*\\n){0,1}?[^\\n]*?(?:b)|(?:b)(?:[^\\n]*\\n){0,1}?[^\\n]*?(?:a))") (Literal,Proximity,Regexp)`).Equal(t, test("x (a NEAR/1 b)", SearchTypeLiteral))
+	autogold.Want("foo NEAR/1", `(concat "foo" "NEAR/1") (Literal)`).Equal(t, test("foo NEAR/1", SearchTypeLiteral))
+	autogold.Want("foo NEAR/1 ", "ERROR: expected operand at 11").Equal(t, test("foo NEAR/1 ", SearchTypeLiteral))
+	autogold.Want("foo NEAR/101 bar", "ERROR: the NEAR operator supports a distance of at most 100 lines").Equal(t, test("foo NEAR/101 bar", SearchTypeLiteral))
+	autogold.Want("(a or b) NEAR/1 c", "ERROR: the NEAR operator requires a search pattern on each side, as in foo NEAR/3 bar. The patterns may not be negated or contain and/or expressions").Equal(t, test("(a or b) NEAR/1 c", SearchTypeLiteral))
+	autogold.Want("not a NEAR/1 c", "ERROR: the NEAR operator requires a search pattern on each side, as in foo NEAR/3 bar. The patterns may not be negated or contain and/or expressions").Equal(t, test("not a NEAR/1 c", SearchTypeLiteral))
+	autogold.Want("a NEAR/1 repo:x", "ERROR: the NEAR operator requires a search pattern on each side, as in foo NEAR/3 bar. The patterns may not be negated or contain and/or expressions").Equal(t, test("a NEAR/1 repo:x", SearchTypeLiteral))
+	autogold.Want("a(:[x]) NEAR/1 b", "ERROR: the NEAR operator is not supported for structural search").Equal(t, test("a(:[x]) NEAR/1 b", SearchTypeStructural))
+}
+
 func TestParseAndOrLiteral(t *testing.T) {
 	test := func(input string) string {
 		result, err := Parse(input, SearchTypeLiteral)
@@ -2,7 +2,9 @@ package query
 
 import (
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/errors"
 )
@@ -36,6 +38,9 @@ var DefaultPredicateRegistry = predicateRegistry{
 		"contains.content": func() Predicate { return &FileContainsContentPredicate{} },
 		"contains":         func() Predicate { return &FileContainsContentPredicate{} },
 	},
+	FieldRev: {
+		"at.time": func() Predicate { return &RevAtTimePredicate{} },
+	},
 }
 
 type predicateRegistry map[string]map[string]func() Predicate
@@ -243,6 +248,69 @@ func (f *RepoContainsCommitAfterPredicate) Plan(parent Basic) (Plan, error) {
 	return ToPlan(Dnf(nodes))
 }
 
+/* rev:at.time(date[, branch]) */
+
+// RevAtTimePredicate represents the `rev:at.time()` predicate, which searches
+// each repository at the last commit made on or before a time. Unlike other
+// predicates, it does not expand to a query, but is resolved for each
+// repository when resolving the revisions to search.
+type RevAtTimePredicate struct {
+	Time time.Time
+
+	// Branch is the revision whose history is searched. If empty, the
+	// default branch is searched.
+	Branch string
+}
+
+// revAtTimeLayouts are the formats accepted for the date of rev:at.time().
+var revAtTimeLayouts = []string{"2006-01-02", time.RFC3339}
+
+func (f *RevAtTimePredicate) ParseParams(params string) error {
+	parts := strings.Split(params, ",")
+	if len(parts) > 2 {
+		return errors.New("at.time expects a date and an optional branch, as in at.time(2021-01-01, main)")
+	}
+
+	date := strings.TrimSpace(parts[0])
+	if date == "" {
+		return errors.New("at.time argument should not be empty")
+	}
+	var err error
+	for _, layout := range revAtTimeLayouts {
+		if f.Time, err = time.Parse(layout, date); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return errors.Errorf("at.time date %q must have the format YYYY-MM-DD or YYYY-MM-DDThh:mm:ssZ", date)
+	}
+
+	if len(parts) == 2 {
+		f.Branch = strings.TrimSpace(parts[1])
+		if f.Branch == "" {
+			return errors.New("at.time branch should not be empty")
+		}
+		if strings.ContainsAny(f.Branch, " *:^") {
+			return errors.Errorf("at.time branch %q must be a single branch name", f.Branch)
+		}
+	}
+	return nil
+}
+
+func (f *RevAtTimePredicate) Field() string { return FieldRev }
+func (f *RevAtTimePredicate) Name() string  { return "at.time" }
+func (f *RevAtTimePredicate) Plan(parent Basic) (Plan, error) {
+	return nil, errors.New("rev:at.time() is resolved for each repository and does not expand to a query")
+}
+
+// String returns the predicate in the syntax of the query language.
+func (f *RevAtTimePredicate) String() string {
+	if f.Branch == "" {
+		return "at.time(" + f.Time.Format(time.RFC3339) + ")"
+	}
+	return "at.time(" + f.Time.Format(time.RFC3339) + ", " + f.Branch + ")"
+}
+
 type FileContainsContentPredicate struct {
 	Pattern string
 }
@@ -307,3 +375,30 @@ func nonPredicateRepos(q Basic) []Node {
 	})
 	return res
 }
+
+// SymbolSearchPlan returns the plan of a case insensitive symbol search for
+// the regular expression pattern in the repositories searched by parent,
+// which finds at most count symbols.
+func SymbolSearchPlan(parent Basic, pattern string, count int) (Plan, error) {
+	nodes := []Node{
+		Parameter{
+			Field: FieldCount,
+			Value: strconv.Itoa(count),
+		},
+		Parameter{
+			Field: FieldType,
+			Value: "symbol",
+		},
+		Pattern{
+			Value:      pattern,
+			Annotation: Annotation{Labels: Regexp},
+		},
+	}
+	for _, node := range nonPredicateRepos(parent) {
+		if p, ok := node.(Parameter); ok && p.Field == FieldCase {
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	return ToPlan(Dnf(nodes))
+}
@@ -3,6 +3,7 @@ package query
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestRepoContainsPredicate(t *testing.T) {
@@ -55,6 +56,55 @@ func TestRepoContainsPredicate(t *testing.T) {
 	})
 }
 
+func TestRevAtTimePredicate(t *testing.T) {
+	t.Run("ParseParams", func(t *testing.T) {
+		type test struct {
+			name     string
+			params   string
+			expected *RevAtTimePredicate
+		}
+
+		valid := []test{
+			{`date`, `2021-01-01`, &RevAtTimePredicate{Time: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}},
+			{`time`, `2021-01-01T10:30:00Z`, &RevAtTimePredicate{Time: time.Date(2021, 1, 1, 10, 30, 0, 0, time.UTC)}},
+			{`date and branch`, `2021-01-01, release/3.0`, &RevAtTimePredicate{Time: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), Branch: "release/3.0"}},
+		}
+
+		for _, tc := range valid {
+			t.Run(tc.name, func(t *testing.T) {
+				p := &RevAtTimePredicate{}
+				err := p.ParseParams(tc.params)
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				if !reflect.DeepEqual(tc.expected, p) {
+					t.Fatalf("expected %#v, got %#v", tc.expected, p)
+				}
+			})
+		}
+
+		invalid := []test{
+			{`empty`, ``, nil},
+			{`relative date`, `1 year ago`, nil},
+			{`invalid date`, `2021-13-01`, nil},
+			{`empty branch`, `2021-01-01,`, nil},
+			{`ref glob`, `2021-01-01, *refs/heads/*`, nil},
+			{`multiple branches`, `2021-01-01, main, dev`, nil},
+		}
+
+		for _, tc := range invalid {
+			t.Run(tc.name, func(t *testing.T) {
+				p := &RevAtTimePredicate{}
+				err := p.ParseParams(tc.params)
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+			})
+		}
+	})
+}
+
 func TestParseAsPredicate(t *testing.T) {
 	tests := []struct {
 		input  string
@@ -79,3 +129,24 @@ func TestParseAsPredicate(t *testing.T) {
 	}
 
 }
+
+func TestSymbolSearchPlan(t *testing.T) {
+	parent, err := ParseLiteral("repo:foo -repo:bar case:yes file:baz NewRaeder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ToBasicQuery(parent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := SymbolSearchPlan(b, "^NewR.eder$", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `(and "count:10" "type:symbol" "repo:foo" "-repo:bar" "^NewR.eder$")`
+	if got := plan.ToParseTree().String(); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
@@ -3,6 +3,7 @@ package query
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/cockroachdb/errors"
@@ -37,11 +38,14 @@ func LowercaseFieldNames(nodes []Node) []Node {
 	})
 }
 
+// CountAllLimit is the result limit that count:all stands for.
+const CountAllLimit = 99999999
+
 // SubstituteCountAll replaces count:all with count:99999999.
 func SubstituteCountAll(nodes []Node) []Node {
 	return MapParameter(nodes, func(field, value string, negated bool, annotation Annotation) Node {
 		if field == FieldCount && strings.ToLower(value) == "all" {
-			return Parameter{Field: field, Value: "99999999", Negated: negated, Annotation: annotation}
+			return Parameter{Field: field, Value: strconv.Itoa(CountAllLimit), Negated: negated, Annotation: annotation}
 		}
 		return Parameter{Field: field, Value: value, Negated: negated, Annotation: annotation}
 	})
@@ -510,7 +514,9 @@ func space(patterns []Pattern) Pattern {
 // return value of callback is substituted in-place in the tree.
 func substituteConcat(callback func([]Pattern) Pattern) func(nodes []Node) []Node {
 	isPattern := func(node Node) bool {
-		if pattern, ok := node.(Pattern); ok && !pattern.Negated {
+		// Patterns substituted for NEAR operators are regular expressions
+		// that can't be concatenated with other patterns.
+		if pattern, ok := node.(Pattern); ok && !pattern.Negated && !pattern.Annotation.Labels.IsSet(Proximity) {
 			return true
 		}
 		return false
@@ -652,10 +658,14 @@ func FuzzifyRegexPatterns(nodes []Node) []Node {
 }
 
 // concatRevFilters removes rev: filters from parameters and attaches their value as @rev to the repo: filters.
+// The rev:at.time() predicate is kept as is, since it is resolved for each repository.
 // Invariant: Guaranteed to succeed on a validat Basic query.
 func ConcatRevFilters(b Basic) Basic {
 	var revision string
-	nodes := MapField(ToNodes(b.Parameters), FieldRev, func(value string, _ bool) Node {
+	nodes := MapParameter(ToNodes(b.Parameters), func(field, value string, negated bool, annotation Annotation) Node {
+		if field != FieldRev || annotation.Labels.IsSet(IsPredicate) {
+			return Parameter{Field: field, Value: value, Negated: negated, Annotation: annotation}
+		}
 		revision = value
 		return nil // remove this node
 	})
@@ -743,6 +753,40 @@ func AddRegexpField(q Q, field, pattern string) string {
 	return StringHuman(q)
 }
 
+// ReplaceWord replaces the whole-word occurrences of word in the non-negated
+// search patterns of a query with replacement, and returns the query as a
+// string.
+func ReplaceWord(q Q, word, replacement string) string {
+	wordRegexp := regexp.MustCompile(`\b` + regexp.QuoteMeta(word) + `\b`)
+	return StringHuman(MapPattern(q, func(value string, negated bool, annotation Annotation) Node {
+		if !negated {
+			value = wordRegexp.ReplaceAllLiteralString(value, replacement)
+		}
+		return Pattern{
+			Value:      value,
+			Negated:    negated,
+			Annotation: annotation,
+		}
+	}))
+}
+
+// ReplaceFieldValue replaces the value of the non-negated parameters of a
+// query with the field and value by replacement, and returns the query as a
+// string. The field should be the canonical name and not an alias.
+func ReplaceFieldValue(q Q, field, value, replacement string) string {
+	return StringHuman(MapParameter(q, func(gotField, gotValue string, negated bool, annotation Annotation) Node {
+		if gotField == field && gotValue == value && !negated {
+			gotValue = replacement
+		}
+		return Parameter{
+			Field:      gotField,
+			Value:      gotValue,
+			Negated:    negated,
+			Annotation: annotation,
+		}
+	}))
+}
+
 func identity(nodes []Node) ([]Node, error) {
 	return nodes, nil
 }
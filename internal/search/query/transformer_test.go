@@ -902,6 +902,10 @@ func TestConcatRevFilters(t *testing.T) {
 			input: "repo:foo file:bas qux AND (rev:a or rev:b)",
 			want:  `("repo:foo@a" "file:bas" "qux") OR ("repo:foo@b" "file:bas" "qux")`,
 		},
+		{
+			input: "repo:foo rev:at.time(2021-01-01)",
+			want:  `("repo:foo" "rev:at.time(2021-01-01)")`,
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.input, func(t *testing.T) {
@@ -961,6 +965,26 @@ func TestQueryField(t *testing.T) {
 	autogold.Want("omit repo alias", "alias-pattern").Equal(t, test("r:stuff alias-pattern", "repo"))
 }
 
+func TestReplaceWord(t *testing.T) {
+	test := func(input, word, replacement string) string {
+		q, _ := ParseLiteral(input)
+		return ReplaceWord(q, word, replacement)
+	}
+
+	autogold.Want("replace word", "repo:foo NewReader(").Equal(t, test("repo:foo NewRaeder(", "NewRaeder", "NewReader"))
+	autogold.Want("replace whole words only", "(NewReader or NewRaederSize)").Equal(t, test("NewRaeder or NewRaederSize", "NewRaeder", "NewReader"))
+	autogold.Want("don't replace negated patterns", "(not NewRaeder)").Equal(t, test("-content:NewRaeder", "NewRaeder", "NewReader"))
+}
+
+func TestReplaceFieldValue(t *testing.T) {
+	test := func(input, field, value, replacement string) string {
+		q, _ := ParseLiteral(input)
+		return ReplaceFieldValue(q, field, value, replacement)
+	}
+
+	autogold.Want("replace repo", "repo:^github\\.com/foo/bar$ -repo:foo baz").Equal(t, test("r:fo -repo:foo baz", "repo", "fo", `^github\.com/foo/bar$`))
+}
+
 func TestSubstituteCountAll(t *testing.T) {
 	test := func(input string) string {
 		query, _ := Parse(input, SearchTypeLiteral)
@@ -103,6 +103,15 @@ func (b Basic) AddCount(count int) Basic {
 	}))
 }
 
+// AddRepoFilter adds a repo parameter to a basic query, which restricts the
+// query to repositories that also match pattern.
+func (b Basic) AddRepoFilter(pattern string) Basic {
+	return b.MapParameters(append(b.Parameters, Parameter{
+		Field: FieldRepo,
+		Value: pattern,
+	}))
+}
+
 // GetCount returns the string value of the "count:" field. Returns empty string if none.
 func (b Basic) GetCount() string {
 	var countStr string
@@ -197,6 +206,10 @@ func (b Basic) IsCaseSensitive() bool {
 	return Q(ToNodes(b.Parameters)).IsCaseSensitive()
 }
 
+func (b Basic) IsDebug() bool {
+	return Q(ToNodes(b.Parameters)).IsDebug()
+}
+
 func (b Basic) Index() YesNoOnly {
 	v := Q(ToNodes(b.Parameters)).yesNoOnlyValue(FieldIndex)
 	if v == nil {
@@ -290,6 +303,32 @@ func (q Q) Count() *int {
 	return count
 }
 
+// Aggregation returns the mode of the aggregate: parameter, or the empty mode
+// if the query doesn't aggregate results.
+func (q Q) Aggregation() AggregationMode {
+	var mode AggregationMode
+	VisitField(q, FieldAggregate, func(value string, _ bool, _ Annotation) {
+		mode = ParseAggregationMode(value)
+	})
+	return mode
+}
+
+// RevAtTime returns the rev:at.time() predicate of the query, or nil if the
+// query doesn't contain one.
+func (q Q) RevAtTime() *RevAtTimePredicate {
+	var atTime *RevAtTimePredicate
+	VisitField(q, FieldRev, func(value string, _ bool, annotation Annotation) {
+		if !annotation.Labels.IsSet(IsPredicate) {
+			return
+		}
+		name, params := ParseAsPredicate(value)
+		if p, ok := DefaultPredicateRegistry.Get(FieldRev, name).(*RevAtTimePredicate); ok && p.ParseParams(params) == nil {
+			atTime = p
+		}
+	})
+	return atTime
+}
+
 func (q Q) Archived() *YesNoOnly {
 	return q.yesNoOnlyValue(FieldArchived)
 }
@@ -326,6 +365,10 @@ func (q Q) IsCaseSensitive() bool {
 	return q.BoolValue("case")
 }
 
+func (q Q) IsDebug() bool {
+	return q.BoolValue(FieldDebug)
+}
+
 func (q Q) Repositories() (repos []string, negatedRepos []string) {
 	VisitField(q, FieldRepo, func(value string, negated bool, _ Annotation) {
 		if negated {
@@ -367,7 +410,8 @@ func (q Q) valueToTypedValue(field, value string, label labels) []*Value {
 		return []*Value{{String: &value}}
 
 	case
-		FieldCase:
+		FieldCase,
+		FieldDebug:
 		b, _ := parseBool(value)
 		return []*Value{{Bool: &b}}
 
@@ -412,7 +456,8 @@ func (q Q) valueToTypedValue(field, value string, label labels) []*Value {
 		FieldIndex,
 		FieldCount,
 		FieldTimeout,
-		FieldCombyRule:
+		FieldCombyRule,
+		FieldReplace:
 		return []*Value{{String: &value}}
 	}
 	return []*Value{{String: &value}}
@@ -260,6 +260,23 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 		return errors.Errorf("unrecognized field %q", field)
 	}
 
+	isValidAggregate := func() error {
+		if ParseAggregationMode(value) == "" {
+			return errors.Errorf("invalid value %q for field %q. Valid values are: %s", value, field, strings.Join(aggregationModeNames, ", "))
+		}
+		return nil
+	}
+
+	isValidSize := func() error {
+		_, err := ParseSizeComparison(value)
+		return err
+	}
+
+	isValidLines := func() error {
+		_, err := ParseLinesComparison(value)
+		return err
+	}
+
 	isValidSelect := func() error {
 		_, err := filter.SelectPathFromString(value)
 		return err
@@ -279,7 +296,8 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 		FieldDefault:
 		// Search patterns are not validated here, as it depends on the search type.
 	case
-		FieldCase:
+		FieldCase,
+		FieldDebug:
 		return satisfies(isSingular, isBoolean, isNotNegated)
 	case
 		FieldRepo:
@@ -302,6 +320,15 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 		FieldContent,
 		FieldVisibility:
 		return satisfies(isSingular, isNotNegated)
+	case
+		FieldSize:
+		return satisfies(isNotNegated, isValidSize)
+	case
+		FieldLines:
+		return satisfies(isNotNegated, isValidLines)
+	case
+		FieldBinary:
+		return satisfies(isSingular, isBoolean, isNotNegated)
 	case
 		FieldRepoHasFile:
 		return satisfies(isValidRegexp)
@@ -326,7 +353,8 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 		FieldCount:
 		return satisfies(isSingular, isNumber, isNotNegated)
 	case
-		FieldCombyRule:
+		FieldCombyRule,
+		FieldReplace:
 		return satisfies(isSingular, isNotNegated)
 	case
 		FieldTimeout:
@@ -337,6 +365,9 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 	case
 		FieldSelect:
 		return satisfies(isSingular, isNotNegated, isValidSelect)
+	case
+		FieldAggregate:
+		return satisfies(isSingular, isNotNegated, isValidAggregate)
 	default:
 		return isUnrecognizedField()
 	}
@@ -347,6 +378,8 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 // (1) a repo is specified with @, OR
 // (2) no repo is specified, OR
 // (3) an empty repo value is specified (i.e., repo:"").
+// A rev:at.time() predicate applies to all repositories, so only (1) applies
+// to it.
 func validateRepoRevPair(nodes []Node) error {
 	var seenRepoWithCommit bool
 	var seenRepo bool
@@ -358,9 +391,11 @@ func validateRepoRevPair(nodes []Node) error {
 			seenRepoWithCommit = true
 		}
 	})
+	var revIsPredicate bool
 	revSpecified := exists(nodes, func(node Node) bool {
 		n, ok := node.(Parameter)
 		if ok && n.Field == FieldRev {
+			revIsPredicate = n.Annotation.Labels.IsSet(IsPredicate)
 			return true
 		}
 		return false
@@ -369,6 +404,9 @@ func validateRepoRevPair(nodes []Node) error {
 		return errors.New("invalid syntax. You specified both @ and rev: for a" +
 			" repo: filter and I don't know how to interpret this. Remove either @ or rev: and try again")
 	}
+	if revIsPredicate {
+		return nil
+	}
 	if !seenRepo && revSpecified {
 		return errors.New("invalid syntax. The query contains `rev:` without `repo:`. Add a `repo:` filter and try again")
 	}
@@ -421,6 +459,92 @@ func validateTypeStructural(nodes []Node) error {
 	return nil
 }
 
+// validateReplace validates that the replace: parameter is only used to
+// rewrite the matches of a structural search pattern.
+func validateReplace(nodes []Node) error {
+	seenReplace := exists(nodes, func(node Node) bool {
+		p, ok := node.(Parameter)
+		return ok && p.Field == FieldReplace
+	})
+	if !seenReplace {
+		return nil
+	}
+	seenStructural := exists(nodes, func(node Node) bool {
+		p, ok := node.(Pattern)
+		return ok && p.Annotation.Labels.IsSet(Structural)
+	})
+	if !seenStructural {
+		return errors.New("the query contains `replace:`, which requires a structural search pattern. Add patterntype:structural and try again")
+	}
+	return nil
+}
+
+// validateProximity validates that NEAR operators are only used to search file
+// contents.
+func validateProximity(nodes []Node) error {
+	seenProximity := exists(nodes, func(node Node) bool {
+		p, ok := node.(Pattern)
+		return ok && p.Annotation.Labels.IsSet(Proximity)
+	})
+	if !seenProximity {
+		return nil
+	}
+	var err error
+	VisitField(nodes, FieldType, func(value string, _ bool, _ Annotation) {
+		if value != "file" && err == nil {
+			err = errors.Errorf("the query contains a NEAR operator, which only applies to searching file contents and is not supported with type:%s", value)
+		}
+	})
+	return err
+}
+
+// validateFileFilters validates that the size:, lines: and binary: parameters
+// are only used to search files.
+func validateFileFilters(nodes []Node) error {
+	var seenFileFilter string
+	var otherType string
+	VisitParameter(nodes, func(field, value string, _ bool, _ Annotation) {
+		if field == FieldSize || field == FieldLines || field == FieldBinary {
+			seenFileFilter = field
+		}
+		if field == FieldType && value != "file" && value != "path" {
+			otherType = value
+		}
+	})
+	if seenFileFilter != "" && otherType != "" {
+		return errors.Errorf("the query contains `%s:`, which only applies to searching files and paths and is not supported with type:%s", seenFileFilter, otherType)
+	}
+	return nil
+}
+
+// validateAggregate validates that the aggregate: parameter groups by a value
+// that the results of the query have.
+func validateAggregate(nodes []Node) error {
+	var mode AggregationMode
+	var typeCommitExists bool
+	VisitParameter(nodes, func(field, value string, _ bool, _ Annotation) {
+		if field == FieldAggregate {
+			mode = ParseAggregationMode(value)
+		}
+		if field == FieldType && (value == "commit" || value == "diff") {
+			typeCommitExists = true
+		}
+	})
+	if mode == AggregateByAuthor && !typeCommitExists {
+		return errors.New("the query contains `aggregate:author`, which requires type:commit or type:diff in the query")
+	}
+	if mode == AggregateByCapture {
+		seenPattern := exists(nodes, func(node Node) bool {
+			p, ok := node.(Pattern)
+			return ok && p.Value != "" && !p.Annotation.Labels.IsSet(Structural)
+		})
+		if !seenPattern {
+			return errors.New("the query contains `aggregate:capture`, which requires a literal or regular expression search pattern")
+		}
+	}
+	return nil
+}
+
 // validatePredicates validates predicate parameters with respect to their validation logic.
 func validatePredicates(nodes []Node) error {
 	var err error
@@ -532,9 +656,45 @@ func validate(nodes []Node) error {
 		validateCommitParameters,
 		validatePredicates,
 		validateTypeStructural,
+		validateReplace,
+		validateProximity,
+		validateAggregate,
+		validateFileFilters,
 	)
 }
 
+// AggregationMode is the value of the aggregate: parameter. It determines by
+// which value search results are grouped and counted.
+type AggregationMode string
+
+const (
+	AggregateByRepo    AggregationMode = "repo"
+	AggregateByFile    AggregationMode = "file"
+	AggregateByLang    AggregationMode = "lang"
+	AggregateByAuthor  AggregationMode = "author"
+	AggregateByCapture AggregationMode = "capture"
+)
+
+var aggregationModeNames = []string{
+	string(AggregateByRepo),
+	string(AggregateByFile),
+	string(AggregateByLang),
+	string(AggregateByAuthor),
+	string(AggregateByCapture),
+}
+
+// ParseAggregationMode returns the aggregation mode for s, or the empty mode
+// if s is not a valid mode.
+func ParseAggregationMode(s string) AggregationMode {
+	s = strings.ToLower(s)
+	for _, name := range aggregationModeNames {
+		if s == name {
+			return AggregationMode(name)
+		}
+	}
+	return ""
+}
+
 type YesNoOnly string
 
 const (
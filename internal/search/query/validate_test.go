@@ -45,6 +45,26 @@ func TestAndOrQuery_Validation(t *testing.T) {
 			input: "count:-1",
 			want:  "field count requires a positive number",
 		},
+		{
+			input: "size:>1TB",
+			want:  `invalid size ">1TB". Use a comparison with a size in bytes, KB, MB or GB, like >1MB or <=512KB`,
+		},
+		{
+			input: "-lines:>10",
+			want:  `field "lines" does not support negation`,
+		},
+		{
+			input: "binary:maybe",
+			want:  `invalid boolean "maybe"`,
+		},
+		{
+			input: "-debug:yes",
+			want:  `field "debug" does not support negation`,
+		},
+		{
+			input: "size:>1MB type:commit",
+			want:  "the query contains `size:`, which only applies to searching files and paths and is not supported with type:commit",
+		},
 		{
 			input: "+",
 			want:  "error parsing regexp: missing argument to repetition operator: `+`",
@@ -109,6 +129,48 @@ func TestAndOrQuery_Validation(t *testing.T) {
 			want:       "this structural search query specifies `type:` and is not supported. Structural search syntax only applies to searching file contents and is not currently supported for diff searches",
 			searchType: SearchTypeStructural,
 		},
+		{
+			input: "foo replace:bar",
+			want:  "the query contains `replace:`, which requires a structural search pattern. Add patterntype:structural and try again",
+		},
+		{
+			input:      "foo(:[x]) replace:bar(:[x]) replace:baz(:[x])",
+			want:       `field "replace" may not be used more than once`,
+			searchType: SearchTypeStructural,
+		},
+		{
+			input: "foo aggregate:owner",
+			want:  `invalid value "owner" for field "aggregate". Valid values are: repo, file, lang, author, capture`,
+		},
+		{
+			input: "foo -aggregate:repo",
+			want:  `field "aggregate" does not support negation`,
+		},
+		{
+			input: "foo aggregate:author",
+			want:  "the query contains `aggregate:author`, which requires type:commit or type:diff in the query",
+		},
+		{
+			input: "repo:foo aggregate:capture",
+			want:  "the query contains `aggregate:capture`, which requires a literal or regular expression search pattern",
+		},
+		{
+			input: "repo:foo@bar rev:at.time(2021-01-01) foo",
+			want:  "invalid syntax. You specified both @ and rev: for a repo: filter and I don't know how to interpret this. Remove either @ or rev: and try again",
+		},
+		{
+			input: "rev:at.time(yesterday) foo",
+			want:  `invalid predicate value: at.time date "yesterday" must have the format YYYY-MM-DD or YYYY-MM-DDThh:mm:ssZ`,
+		},
+		{
+			input: "type:commit foo NEAR/2 bar",
+			want:  "the query contains a NEAR operator, which only applies to searching file contents and is not supported with type:commit",
+		},
+		{
+			input:      "foo NEAR/2 bar type:repo",
+			searchType: SearchTypeLiteral,
+			want:       "the query contains a NEAR operator, which only applies to searching file contents and is not supported with type:repo",
+		},
 	}
 	for _, c := range cases {
 		t.Run("validate and/or query", func(t *testing.T) {
@@ -125,6 +187,30 @@ func TestAndOrQuery_Validation(t *testing.T) {
 	}
 }
 
+func TestAndOrQuery_Aggregation(t *testing.T) {
+	cases := []struct {
+		input string
+		want  AggregationMode
+	}{
+		{input: "foo", want: ""},
+		{input: "foo aggregate:repo", want: AggregateByRepo},
+		{input: "foo aggregate:LANG", want: AggregateByLang},
+		{input: "type:commit foo aggregate:author", want: AggregateByAuthor},
+		{input: "foo(.*) aggregate:capture", want: AggregateByCapture},
+	}
+	for _, c := range cases {
+		t.Run(c.input, func(t *testing.T) {
+			q, err := ParseRegexp(c.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := q.Aggregation(); got != c.want {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
 func TestAndOrQuery_IsCaseSensitive(t *testing.T) {
 	cases := []struct {
 		name  string
@@ -148,8 +148,11 @@ func ToTextPatternInfo(q query.Basic, p Protocol, transform query.BasicPass) *Te
 		Languages:                    langInclude,
 		PathPatternsAreCaseSensitive: q.IsCaseSensitive(),
 		CombyRule:                    q.FindValue(query.FieldCombyRule),
+		CombyRewrite:                 q.FindValue(query.FieldReplace),
 		Index:                        q.Index(),
 		Select:                       selector,
+		FileFilters:                  q.FileFilters(),
+		Debug:                        q.IsDebug(),
 	}
 }
 
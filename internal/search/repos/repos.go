@@ -37,12 +37,22 @@ import (
 type Resolved struct {
 	RepoRevs        []*search.RepositoryRevisions
 	MissingRepoRevs []*search.RepositoryRevisions
-	ExcludedRepos   ExcludedRepos
-	OverLimit       bool
+
+	// ReposWithoutCommitAtTime are the repositories that had no commit at the
+	// time of a rev:at.time() predicate, and are therefore not searched.
+	ReposWithoutCommitAtTime []types.RepoName
+
+	// Unsearched are repositories which are not searched, with the reason as
+	// their status. For example, the revisions of a rev:at.time() predicate
+	// can't be resolved in repositories that are still being cloned.
+	Unsearched map[types.RepoName]search.RepoStatus
+
+	ExcludedRepos ExcludedRepos
+	OverLimit     bool
 }
 
 func (r *Resolved) String() string {
-	return fmt.Sprintf("Resolved{RepoRevs=%d, MissingRepoRevs=%d, OverLimit=%v, %#v}", len(r.RepoRevs), len(r.MissingRepoRevs), r.OverLimit, r.ExcludedRepos)
+	return fmt.Sprintf("Resolved{RepoRevs=%d, MissingRepoRevs=%d, ReposWithoutCommitAtTime=%d, Unsearched=%d, OverLimit=%v, %#v}", len(r.RepoRevs), len(r.MissingRepoRevs), len(r.ReposWithoutCommitAtTime), len(r.Unsearched), r.OverLimit, r.ExcludedRepos)
 }
 
 type Resolver struct {
@@ -307,11 +317,23 @@ func (r *Resolver) Resolve(ctx context.Context, op search.RepoOptions) (Resolved
 		tr.LazyPrintf("repohascommitafter removed %d repos in %s", before-len(repoRevs), time.Since(start))
 	}
 
+	var (
+		reposWithoutCommitAtTime []types.RepoName
+		unsearched               map[types.RepoName]search.RepoStatus
+	)
+	if err == nil && op.RevAtTime != nil {
+		start := time.Now()
+		repoRevs, reposWithoutCommitAtTime, unsearched, err = resolveRevsAtTime(ctx, repoRevs, op.RevAtTime, search.MaxUnindexedRepoRevSearchesPerQuery)
+		tr.LazyPrintf("rev:%s resolved %d repos (%d without a commit, %d unsearched) in %s", op.RevAtTime, len(repoRevs), len(reposWithoutCommitAtTime), len(unsearched), time.Since(start))
+	}
+
 	return Resolved{
-		RepoRevs:        repoRevs,
-		MissingRepoRevs: missingRepoRevs,
-		ExcludedRepos:   excluded,
-		OverLimit:       overLimit,
+		RepoRevs:                 repoRevs,
+		MissingRepoRevs:          missingRepoRevs,
+		ReposWithoutCommitAtTime: reposWithoutCommitAtTime,
+		Unsearched:               unsearched,
+		ExcludedRepos:            excluded,
+		OverLimit:                overLimit,
 	}, err
 }
 
@@ -617,6 +639,114 @@ func filterRepoHasCommitAfter(ctx context.Context, revisions []*search.Repositor
 	return pass, err
 }
 
+// revAtTimeConcurrency is the maximum number of concurrent requests to
+// gitserver when resolving the revisions of a rev:at.time() predicate.
+const revAtTimeConcurrency = 16
+
+// resolveRevsAtTime replaces each revision of revisions with the last commit
+// of the revision at or before the time of atTime. If atTime specifies a
+// branch, the branch is used instead of the revision. Repositories where no
+// revision has such a commit are returned in missing rather than resolved.
+//
+// Revisions at a time can only be searched by the unindexed searcher, which
+// searches at most limit revisions. Only those are resolved, and the
+// repositories after them are returned in unsearched with a limit hit status.
+// So are repositories which don't exist or are still being cloned, with a
+// missing or cloning status.
+func resolveRevsAtTime(ctx context.Context, revisions []*search.RepositoryRevisions, atTime *query.RevAtTimePredicate, limit int) (resolved []*search.RepositoryRevisions, missing []types.RepoName, unsearched map[types.RepoName]search.RepoStatus, err error) {
+	var (
+		mut sync.Mutex
+		run = parallel.NewRun(revAtTimeConcurrency)
+	)
+
+	setUnsearched := func(repo types.RepoName, status search.RepoStatus) {
+		if unsearched == nil {
+			unsearched = map[types.RepoName]search.RepoStatus{}
+		}
+		unsearched[repo] = status
+	}
+
+	for i, revs := range revisions {
+		if len(revs.Revs) == 0 {
+			limit-- // default branch
+		} else {
+			limit -= len(revs.Revs)
+		}
+		if limit < 0 {
+			for _, revs := range revisions[i:] {
+				setUnsearched(revs.Repo, search.RepoStatusLimitHit)
+			}
+			revisions = revisions[:i]
+			break
+		}
+	}
+
+	for _, revs := range revisions {
+		run.Acquire()
+
+		revs := revs
+		goroutine.Go(func() {
+			defer run.Release()
+
+			revSpecs := revs.Revs
+			if len(revSpecs) == 0 {
+				revSpecs = []search.RevisionSpecifier{{RevSpec: ""}} // default branch
+			}
+
+			var specifiers []search.RevisionSpecifier
+			for _, rev := range revSpecs {
+				revSpec := atTime.Branch
+				if revSpec == "" {
+					revSpec = rev.RevSpec
+				}
+				commit, err := git.FindCommitAtTime(ctx, revs.GitserverRepo(), revSpec, atTime.Time)
+				if err != nil {
+					if vcs.IsRepoNotExist(err) {
+						// We can't tell whether it had a commit.
+						status := search.RepoStatusMissing
+						if vcs.IsCloneInProgress(err) {
+							status = search.RepoStatusCloning
+						}
+						mut.Lock()
+						setUnsearched(revs.Repo, status)
+						mut.Unlock()
+						return
+					}
+					if errors.HasType(err, &gitserver.RevisionNotFoundError{}) {
+						continue
+					}
+
+					run.Error(err)
+					return
+				}
+				if commit != nil {
+					specifiers = append(specifiers, search.RevisionSpecifier{RevSpec: string(commit.ID)})
+				}
+			}
+
+			mut.Lock()
+			defer mut.Unlock()
+			if len(specifiers) == 0 {
+				missing = append(missing, revs.Repo)
+				return
+			}
+			resolved = append(resolved, &search.RepositoryRevisions{Repo: revs.Repo, Revs: specifiers})
+		})
+	}
+
+	err = run.Wait()
+
+	// Keep the order of revisions, which is lost by resolving concurrently.
+	order := make(map[api.RepoID]int, len(revisions))
+	for i, revs := range revisions {
+		order[revs.Repo.ID] = i
+	}
+	sort.Slice(resolved, func(i, j int) bool { return order[resolved[i].Repo.ID] < order[resolved[j].Repo.ID] })
+	sort.Slice(missing, func(i, j int) bool { return order[missing[i].ID] < order[missing[j].ID] })
+
+	return resolved, missing, unsearched, err
+}
+
 func optimizeRepoPatternWithHeuristics(repoPattern string) string {
 	if envvar.SourcegraphDotComMode() && (strings.HasPrefix(repoPattern, "github.com") || strings.HasPrefix(repoPattern, `github\.com`)) {
 		repoPattern = "^" + repoPattern
@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/google/go-cmp/cmp"
@@ -21,6 +22,7 @@ import (
 	searchbackend "github.com/sourcegraph/sourcegraph/internal/search/backend"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
 
@@ -181,6 +183,103 @@ func TestRevisionValidation(t *testing.T) {
 	}
 }
 
+func TestResolveRevsAtTime(t *testing.T) {
+	git.Mocks.ResolveRevision = func(spec string, opt git.ResolveRevisionOptions) (api.CommitID, error) {
+		if spec == "missing" {
+			return "", &gitserver.RevisionNotFoundError{Spec: spec}
+		}
+		return api.CommitID("tip-" + spec), nil
+	}
+	git.Mocks.Commits = func(repo api.RepoName, opt git.CommitsOptions) ([]*git.Commit, error) {
+		if opt.N != 1 || opt.Before != "2021-01-01T00:00:00Z" {
+			return nil, errors.Errorf("unexpected options %+v", opt)
+		}
+		if repo == "repoNew" {
+			return nil, nil
+		}
+		if repo == "repoCloning" {
+			return nil, &vcs.RepoNotExistError{Repo: repo, CloneInProgress: true}
+		}
+		return []*git.Commit{{ID: api.CommitID(string(repo) + "-" + opt.Range)}}, nil
+	}
+	defer git.ResetMocks()
+
+	revisions := []*search.RepositoryRevisions{
+		{Repo: types.RepoName{ID: 1, Name: "repoFoo"}, Revs: []search.RevisionSpecifier{{RevSpec: ""}}},
+		{Repo: types.RepoName{ID: 2, Name: "repoNew"}, Revs: []search.RevisionSpecifier{{RevSpec: ""}}},
+		{Repo: types.RepoName{ID: 3, Name: "repoBar"}, Revs: []search.RevisionSpecifier{{RevSpec: "dev"}, {RevSpec: "missing"}}},
+	}
+
+	t.Run("default branch", func(t *testing.T) {
+		atTime := &query.RevAtTimePredicate{Time: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+		resolved, missing, unsearched, err := resolveRevsAtTime(context.Background(), revisions, atTime, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(unsearched) != 0 {
+			t.Errorf("expected no unsearched repos, got %v", unsearched)
+		}
+		wantResolved := []*search.RepositoryRevisions{
+			{Repo: types.RepoName{ID: 1, Name: "repoFoo"}, Revs: []search.RevisionSpecifier{{RevSpec: "repoFoo-tip-HEAD"}}},
+			{Repo: types.RepoName{ID: 3, Name: "repoBar"}, Revs: []search.RevisionSpecifier{{RevSpec: "repoBar-tip-dev"}}},
+		}
+		if diff := cmp.Diff(wantResolved, resolved); diff != "" {
+			t.Error(diff)
+		}
+		if diff := cmp.Diff([]types.RepoName{{ID: 2, Name: "repoNew"}}, missing); diff != "" {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("branch", func(t *testing.T) {
+		atTime := &query.RevAtTimePredicate{Time: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), Branch: "missing"}
+		resolved, missing, _, err := resolveRevsAtTime(context.Background(), revisions, atTime, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resolved) != 0 {
+			t.Errorf("expected no resolved revisions, got %v", resolved)
+		}
+		if len(missing) != len(revisions) {
+			t.Errorf("expected all repos to be missing, got %v", missing)
+		}
+	})
+
+	t.Run("cloning", func(t *testing.T) {
+		atTime := &query.RevAtTimePredicate{Time: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+		cloning := types.RepoName{ID: 4, Name: "repoCloning"}
+		resolved, missing, unsearched, err := resolveRevsAtTime(context.Background(), []*search.RepositoryRevisions{
+			{Repo: cloning, Revs: []search.RevisionSpecifier{{RevSpec: ""}}},
+			revisions[0],
+		}, atTime, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resolved) != 1 || len(missing) != 0 {
+			t.Errorf("expected only repoFoo to be resolved, got resolved=%v missing=%v", resolved, missing)
+		}
+		if diff := cmp.Diff(map[types.RepoName]search.RepoStatus{cloning: search.RepoStatusCloning}, unsearched); diff != "" {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		atTime := &query.RevAtTimePredicate{Time: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+		resolved, missing, unsearched, err := resolveRevsAtTime(context.Background(), revisions, atTime, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// repoBar has two revisions, which exceed the limit.
+		if len(resolved) != 1 || len(missing) != 1 {
+			t.Errorf("expected repoFoo and repoNew to be resolved, got resolved=%v missing=%v", resolved, missing)
+		}
+		want := map[types.RepoName]search.RepoStatus{revisions[2].Repo: search.RepoStatusLimitHit}
+		if diff := cmp.Diff(want, unsearched); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
+
 // TestSearchRevspecs tests a repository name against a list of
 // repository specs with optional revspecs, and determines whether
 // we get the expected error, list of matching rev specs, or list
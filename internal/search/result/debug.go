@@ -0,0 +1,53 @@
+package result
+
+import "time"
+
+// Debug explains where a match was found and how it was ranked. It is only
+// recorded for queries with debug:yes.
+type Debug struct {
+	// Backend is the search backend which found the match, "zoekt" or
+	// "searcher".
+	Backend string
+
+	// Endpoint is the address of the Zoekt or searcher instance which found
+	// the match. It is empty if it is unknown.
+	Endpoint string
+
+	// Score is the score Zoekt gave the match. Zoekt sends the matches with
+	// the highest scores first.
+	Score float64
+
+	// ShardPriority is the priority of the Zoekt shard containing the file,
+	// which ranks the matches of repositories with a higher priority first.
+	// Zoekt only reports the other components of Score if it runs with score
+	// debugging enabled globally, so they are not part of the debug
+	// information.
+	ShardPriority float64
+
+	// Query is the query the match was found for. A query containing or is
+	// searched as several queries without or, and a match is reported for the
+	// first one it matches.
+	Query string
+
+	// Duration is the time from the start of the search by the backend until
+	// it found the match.
+	Duration time.Duration
+}
+
+// WithDebugQuery records in the debug information of the file matches in
+// matches that they were found for query q. It adds debug information to file
+// matches which have none.
+func WithDebugQuery(matches []Match, q string) {
+	for _, m := range matches {
+		fm, ok := m.(*FileMatch)
+		if !ok {
+			continue
+		}
+		if fm.Debug == nil {
+			fm.Debug = &Debug{}
+		}
+		if fm.Debug.Query == "" {
+			fm.Debug.Query = q
+		}
+	}
+}
@@ -40,12 +40,21 @@ func (f *File) URL() *url.URL {
 // - A collection of symbol results (len(Symbols) > 0)
 // - A collection of text content results (len(LineMatches) > 0)
 // - A result repsenting the whole file (len(Symbols) == 0 && len(LineMatches) == 0)
+// - A rewrite of the file by a structural search with a replace: value (Diff != "")
 type FileMatch struct {
 	File
 
 	LineMatches []*LineMatch
 	Symbols     []*SymbolMatch `json:"-"`
 
+	// Diff is the unified diff of rewriting the file with the replace: value of
+	// a structural search.
+	Diff string `json:"-"`
+
+	// Debug explains where the match was found and how it was ranked. It is
+	// nil unless the query has debug:yes.
+	Debug *Debug `json:"-"`
+
 	LimitHit bool
 }
 
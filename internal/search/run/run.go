@@ -11,6 +11,7 @@ type SearchInputs struct {
 	Plan           query.Plan // the comprehensive query plan
 	Query          query.Q    // the current basic query being evaluated, one part of query.Plan
 	OriginalQuery  string     // the raw string of the original search query
+	ExpandedQuery  string     // OriginalQuery with search macros expanded, if it references any
 	PatternType    query.SearchType
 	VersionContext *string
 	UserSettings   *schema.Settings
@@ -19,6 +20,15 @@ type SearchInputs struct {
 	DefaultLimit int
 }
 
+// ParsedQuery returns the query string that was parsed to Plan. The ranges of
+// the nodes in Query refer to it.
+func (inputs SearchInputs) ParsedQuery() string {
+	if inputs.ExpandedQuery != "" {
+		return inputs.ExpandedQuery
+	}
+	return inputs.OriginalQuery
+}
+
 // MaxResults computes the limit for the query.
 func (inputs SearchInputs) MaxResults() int {
 	if inputs.Query == nil {
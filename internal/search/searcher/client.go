@@ -20,6 +20,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/endpoint"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/requestid"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
@@ -29,24 +30,27 @@ var (
 	requestCounter = metrics.NewRequestMeter("textsearch", "Total number of requests sent to the textsearch API.")
 
 	searchHTTPClient = &http.Client{
-		// ot.Transport will propagate opentracing spans
+		// ot.Transport will propagate opentracing spans, and requestid.Transport the request ID
 		Transport: &ot.Transport{
-			RoundTripper: requestCounter.Transport(&http.Transport{
-				// Default is 2, but we can send many concurrent requests
-				MaxIdleConnsPerHost: 500,
-			}, func(u *url.URL) string {
-				return "search"
-			}),
+			RoundTripper: &requestid.Transport{
+				RoundTripper: requestCounter.Transport(&http.Transport{
+					// Default is 2, but we can send many concurrent requests
+					MaxIdleConnsPerHost: 500,
+				}, func(u *url.URL) string {
+					return "search"
+				}),
+			},
 		},
 	}
 )
 
-var MockSearch func(ctx context.Context, repo api.RepoName, commit api.CommitID, p *search.TextPatternInfo, fetchTimeout time.Duration) (matches []*protocol.FileMatch, limitHit bool, err error)
+var MockSearch func(ctx context.Context, repo api.RepoName, commit api.CommitID, paths []string, p *search.TextPatternInfo, fetchTimeout time.Duration) (matches []*protocol.FileMatch, limitHit bool, err error)
 
-// Search searches repo@commit with p.
-func Search(ctx context.Context, searcherURLs *endpoint.Map, repo api.RepoName, branch string, commit api.CommitID, indexed bool, p *search.TextPatternInfo, fetchTimeout time.Duration, indexerEndpoints []string) (matches []*protocol.FileMatch, limitHit bool, err error) {
+// Search searches repo@commit with p. If paths is non-empty, only the files
+// with those paths are searched.
+func Search(ctx context.Context, searcherURLs *endpoint.Map, repo api.RepoName, branch string, commit api.CommitID, paths []string, indexed bool, p *search.TextPatternInfo, fetchTimeout time.Duration, indexerEndpoints []string) (matches []*protocol.FileMatch, limitHit bool, err error) {
 	if MockSearch != nil {
-		return MockSearch(ctx, repo, commit, p, fetchTimeout)
+		return MockSearch(ctx, repo, commit, paths, p, fetchTimeout)
 	}
 
 	tr, ctx := trace.New(ctx, "searcher.client", fmt.Sprintf("%s@%s", repo, commit))
@@ -59,17 +63,32 @@ func Search(ctx context.Context, searcherURLs *endpoint.Map, repo api.RepoName,
 		"Repo":            []string{string(repo)},
 		"Commit":          []string{string(commit)},
 		"Branch":          []string{branch},
+		"Paths":           paths,
 		"Pattern":         []string{p.Pattern},
 		"ExcludePattern":  []string{p.ExcludePattern},
 		"IncludePatterns": p.IncludePatterns,
 		"FetchTimeout":    []string{fetchTimeout.String()},
 		"Languages":       p.Languages,
 		"CombyRule":       []string{p.CombyRule},
+		"CombyRewrite":    []string{p.CombyRewrite},
 
 		"PathPatternsAreRegExps": []string{"true"},
 		"IndexerEndpoints":       indexerEndpoints,
 		"Select":                 []string{p.Select.Root()},
 	}
+	for _, c := range p.FileFilters.Sizes {
+		q.Add("Sizes", c.String())
+	}
+	for _, c := range p.FileFilters.Lines {
+		q.Add("Lines", c.String())
+	}
+	if b := p.FileFilters.Binary; b != nil {
+		if *b {
+			q.Set("Binary", "yes")
+		} else {
+			q.Set("Binary", "no")
+		}
+	}
 	if deadline, ok := ctx.Deadline(); ok {
 		t, err := deadline.MarshalText()
 		if err != nil {
@@ -138,6 +157,11 @@ func Search(ctx context.Context, searcherURLs *endpoint.Map, repo api.RepoName,
 		tr.LazyPrintf("attempt %d: %s", attempt, url)
 		matches, limitHit, err = textSearchURL(ctx, url)
 		if err == nil || errcode.IsTimeout(err) {
+			if p.Debug {
+				for _, m := range matches {
+					m.Endpoint = searcherURL
+				}
+			}
 			return matches, limitHit, err
 		}
 
@@ -0,0 +1,149 @@
+package streaming
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/sourcegraph/sourcegraph/internal/inventory"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+)
+
+// maxAggregationGroups is the maximum number of groups Compute returns.
+const maxAggregationGroups = 1000
+
+// SearchAggregation counts the results of a search grouped by a value of the
+// results, such as their repository or the language of their file. It is
+// computed from the same events as SearchFilters, but unlike SearchFilters it
+// counts every result rather than proposing a few filters.
+type SearchAggregation struct {
+	// Mode is the value by which results are grouped.
+	Mode query.AggregationMode
+
+	// Capture is applied to the text of each match when Mode is
+	// query.AggregateByCapture. If it has a capture group, matches are grouped
+	// by the value of its first group. Otherwise, or if Capture is nil,
+	// matches are grouped by the matched text.
+	Capture *regexp.Regexp
+
+	counts map[string]int
+}
+
+// Aggregation is the number of results in a group of an aggregation.
+type Aggregation struct {
+	Label string
+	Count int
+}
+
+// NewSearchAggregation returns the aggregation requested by the aggregate:
+// parameter of q, or nil if q doesn't aggregate its results.
+func NewSearchAggregation(q query.Q) *SearchAggregation {
+	mode := q.Aggregation()
+	if mode == "" {
+		return nil
+	}
+	a := &SearchAggregation{Mode: mode}
+	if mode == query.AggregateByCapture {
+		a.Capture = captureRegexp(q)
+	}
+	return a
+}
+
+// captureRegexp returns the first pattern of q that has a capture group, or
+// nil if no pattern has one.
+func captureRegexp(q query.Q) *regexp.Regexp {
+	var capture *regexp.Regexp
+	query.VisitPattern(q, func(value string, negated bool, annotation query.Annotation) {
+		if capture != nil || negated || !annotation.Labels.IsSet(query.Regexp) {
+			return
+		}
+		if !q.IsCaseSensitive() {
+			value = "(?i:" + value + ")"
+		}
+		re, err := regexp.Compile(value)
+		if err != nil || re.NumSubexp() == 0 {
+			return
+		}
+		capture = re
+	})
+	return capture
+}
+
+// Update internal state for the results in event.
+func (a *SearchAggregation) Update(event SearchEvent) {
+	// Initialize state on first call.
+	if a.counts == nil {
+		a.counts = make(map[string]int)
+	}
+
+	for _, match := range event.Results {
+		switch v := match.(type) {
+		case *result.FileMatch:
+			switch a.Mode {
+			case query.AggregateByRepo:
+				a.counts[string(v.Repo.Name)] += v.ResultCount()
+			case query.AggregateByFile:
+				a.counts[string(v.Repo.Name)+"/"+v.Path] += v.ResultCount()
+			case query.AggregateByLang:
+				if language, _ := inventory.GetLanguageByFilename(v.Path); language != "" {
+					a.counts[language] += v.ResultCount()
+				}
+			case query.AggregateByCapture:
+				for _, lm := range v.LineMatches {
+					a.addCaptures(lm)
+				}
+			}
+		case *result.RepoMatch:
+			if a.Mode == query.AggregateByRepo {
+				a.counts[string(v.Name)]++
+			}
+		case *result.CommitMatch:
+			switch a.Mode {
+			case query.AggregateByRepo:
+				a.counts[string(v.Repo.Name)]++
+			case query.AggregateByAuthor:
+				a.counts[v.Commit.Author.Name]++
+			}
+		}
+	}
+}
+
+// addCaptures counts the value captured by each match in lm.
+func (a *SearchAggregation) addCaptures(lm *result.LineMatch) {
+	// Offsets and lengths are in runes.
+	preview := []rune(lm.Preview)
+	for _, ol := range lm.OffsetAndLengths {
+		start, end := int(ol[0]), int(ol[0]+ol[1])
+		if start < 0 || end > len(preview) || start > end {
+			continue
+		}
+		text := string(preview[start:end])
+		if a.Capture != nil {
+			submatch := a.Capture.FindStringSubmatch(text)
+			if len(submatch) < 2 {
+				continue
+			}
+			text = submatch[1]
+		}
+		a.counts[text]++
+	}
+}
+
+// Compute returns the groups of the aggregation ordered by descending count.
+// At most the maxAggregationGroups largest groups are returned.
+func (a *SearchAggregation) Compute() []Aggregation {
+	groups := make([]Aggregation, 0, len(a.counts))
+	for label, count := range a.counts {
+		groups = append(groups, Aggregation{Label: label, Count: count})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].Label < groups[j].Label
+	})
+	if len(groups) > maxAggregationGroups {
+		groups = groups[:maxAggregationGroups]
+	}
+	return groups
+}
@@ -0,0 +1,124 @@
+package streaming
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestSearchAggregation(t *testing.T) {
+	repoA := types.RepoName{ID: 1, Name: "github.com/sourcegraph/a"}
+	repoB := types.RepoName{ID: 2, Name: "github.com/sourcegraph/b"}
+
+	fileMatch := func(repo types.RepoName, path string, lines ...*result.LineMatch) *result.FileMatch {
+		return &result.FileMatch{
+			File:        result.File{Repo: repo, Path: path},
+			LineMatches: lines,
+		}
+	}
+	lineMatch := func(preview string, offsetAndLengths ...[2]int32) *result.LineMatch {
+		return &result.LineMatch{Preview: preview, OffsetAndLengths: offsetAndLengths}
+	}
+	commitMatch := func(repo types.RepoName, author string) *result.CommitMatch {
+		return &result.CommitMatch{
+			Repo:   repo,
+			Commit: git.Commit{Author: git.Signature{Name: author}},
+		}
+	}
+
+	events := []SearchEvent{{
+		Results: []result.Match{
+			fileMatch(repoA, "main.go", lineMatch("foo(1) foo(2)", [2]int32{0, 6}, [2]int32{7, 6})),
+			fileMatch(repoB, "README.md", lineMatch("föö foo(1)", [2]int32{4, 6})),
+		},
+	}, {
+		Results: []result.Match{
+			fileMatch(repoA, "lib.go", lineMatch("foo(3)", [2]int32{0, 6})),
+			&result.RepoMatch{Name: repoB.Name, ID: repoB.ID},
+		},
+	}}
+
+	cases := []struct {
+		query string
+		want  []Aggregation
+	}{{
+		query: `foo\(\d\) aggregate:repo`,
+		want: []Aggregation{
+			{Label: "github.com/sourcegraph/a", Count: 3},
+			{Label: "github.com/sourcegraph/b", Count: 2},
+		},
+	}, {
+		query: `foo\(\d\) aggregate:file`,
+		want: []Aggregation{
+			{Label: "github.com/sourcegraph/a/main.go", Count: 2},
+			{Label: "github.com/sourcegraph/a/lib.go", Count: 1},
+			{Label: "github.com/sourcegraph/b/README.md", Count: 1},
+		},
+	}, {
+		query: `foo\(\d\) aggregate:lang`,
+		want: []Aggregation{
+			{Label: "Go", Count: 3},
+			{Label: "Markdown", Count: 1},
+		},
+	}, {
+		query: `foo\(\d\) aggregate:capture`,
+		want: []Aggregation{
+			{Label: "foo(1)", Count: 2},
+			{Label: "foo(2)", Count: 1},
+			{Label: "foo(3)", Count: 1},
+		},
+	}, {
+		query: `FOO\((\d)\) aggregate:capture`,
+		want: []Aggregation{
+			{Label: "1", Count: 2},
+			{Label: "2", Count: 1},
+			{Label: "3", Count: 1},
+		},
+	}}
+	for _, tc := range cases {
+		t.Run(tc.query, func(t *testing.T) {
+			q, err := query.ParseRegexp(tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			a := NewSearchAggregation(q)
+			for _, event := range events {
+				a.Update(event)
+			}
+			if diff := cmp.Diff(tc.want, a.Compute()); diff != "" {
+				t.Fatalf("unexpected aggregation (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("author", func(t *testing.T) {
+		a := &SearchAggregation{Mode: query.AggregateByAuthor}
+		a.Update(SearchEvent{Results: []result.Match{
+			commitMatch(repoA, "alice"),
+			commitMatch(repoB, "bob"),
+			commitMatch(repoB, "alice"),
+		}})
+		want := []Aggregation{
+			{Label: "alice", Count: 2},
+			{Label: "bob", Count: 1},
+		}
+		if diff := cmp.Diff(want, a.Compute()); diff != "" {
+			t.Fatalf("unexpected aggregation (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("no aggregation", func(t *testing.T) {
+		q, err := query.ParseRegexp("foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a := NewSearchAggregation(q); a != nil {
+			t.Fatalf("expected no aggregation, got %+v", a)
+		}
+	})
+}
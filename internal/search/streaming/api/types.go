@@ -89,3 +89,31 @@ const (
 	SeverityInfo SkippedSeverity = "info"
 	SeverityWarn SkippedSeverity = "warn"
 )
+
+// MatchDebug explains where a match was found and how it was ranked. It is
+// only sent for queries with debug:yes.
+type MatchDebug struct {
+	// Backend is the search backend which found the match, "zoekt" or
+	// "searcher".
+	Backend string `json:"backend,omitempty"`
+
+	// Endpoint is the address of the Zoekt or searcher instance which found
+	// the match.
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Score is the score Zoekt gave the match. Zoekt sends the matches with
+	// the highest scores first.
+	Score float64 `json:"score,omitempty"`
+
+	// ShardPriority is the priority of the Zoekt shard containing the file,
+	// which ranks the matches of repositories with a higher priority first.
+	ShardPriority float64 `json:"shardPriority,omitempty"`
+
+	// Query is the query the match was found for. A query containing or is
+	// searched as several queries without or.
+	Query string `json:"query,omitempty"`
+
+	// DurationMs is the time in milliseconds from the start of the search by
+	// the backend until it found the match.
+	DurationMs int `json:"durationMs"`
+}
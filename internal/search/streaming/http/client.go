@@ -28,12 +28,13 @@ func NewRequest(baseURL string, query string) (*http.Request, error) {
 // support streams which are generated by Sourcegraph. IE this is not a fully
 // compliant Server Sent Events decoder.
 type Decoder struct {
-	OnProgress func(*api.Progress)
-	OnMatches  func([]EventMatch)
-	OnFilters  func([]*EventFilter)
-	OnAlert    func(*EventAlert)
-	OnError    func(*EventError)
-	OnUnknown  func(event, data []byte)
+	OnProgress     func(*api.Progress)
+	OnMatches      func([]EventMatch)
+	OnFilters      func([]*EventFilter)
+	OnAggregations func(*EventAggregations)
+	OnAlert        func(*EventAlert)
+	OnError        func(*EventError)
+	OnUnknown      func(event, data []byte)
 }
 
 func (rr Decoder) ReadAll(r io.Reader) error {
@@ -108,6 +109,15 @@ func (rr Decoder) ReadAll(r io.Reader) error {
 				return errors.Errorf("failed to decode filters payload: %w", err)
 			}
 			rr.OnFilters(d)
+		} else if bytes.Equal(event, []byte("aggregations")) {
+			if rr.OnAggregations == nil {
+				continue
+			}
+			var d EventAggregations
+			if err := json.Unmarshal(data, &d); err != nil {
+				return errors.Errorf("failed to decode aggregations payload: %w", err)
+			}
+			rr.OnAggregations(&d)
 		} else if bytes.Equal(event, []byte("alert")) {
 			if rr.OnAlert == nil {
 				continue
@@ -169,6 +179,8 @@ func (r *eventMatchUnmarshaller) UnmarshalJSON(b []byte) error {
 		r.EventMatch = &EventSymbolMatch{}
 	case CommitMatchType:
 		r.EventMatch = &EventCommitMatch{}
+	case FileDiffMatchType:
+		r.EventMatch = &EventFileDiffMatch{}
 	default:
 		return errors.Errorf("unknown MatchType %v", typeU.Type)
 	}
@@ -44,6 +44,11 @@ func TestDecoder(t *testing.T) {
 				Type:   CommitMatchType,
 				Detail: "test",
 			},
+			&EventFileDiffMatch{
+				Type: FileDiffMatchType,
+				Path: "test",
+				Diff: "--- a/test\n+++ b/test\n",
+			},
 		},
 	}, {
 		Name: "filters",
@@ -52,6 +57,13 @@ func TestDecoder(t *testing.T) {
 		}, {
 			Value: "filter-2",
 		}},
+	}, {
+		Name: "aggregations",
+		Value: &EventAggregations{
+			Mode:     "repo",
+			Groups:   []EventAggregationGroup{{Label: "test", Count: 3}},
+			Complete: true,
+		},
 	}, {
 		Name: "alert",
 		Value: &EventAlert{
@@ -98,6 +110,9 @@ func TestDecoder(t *testing.T) {
 		OnFilters: func(d []*EventFilter) {
 			got = append(got, Event{Name: "filters", Value: d})
 		},
+		OnAggregations: func(d *EventAggregations) {
+			got = append(got, Event{Name: "aggregations", Value: d})
+		},
 		OnAlert: func(d *EventAlert) {
 			got = append(got, Event{Name: "alert", Value: d})
 		},
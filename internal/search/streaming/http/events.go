@@ -4,6 +4,8 @@ import (
 	"bytes"
 
 	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming/api"
 )
 
 // EventMatch is an interface which only the top level match event types
@@ -25,6 +27,9 @@ type EventFileMatch struct {
 	Version    string   `json:"version,omitempty"`
 
 	LineMatches []EventLineMatch `json:"lineMatches"`
+
+	// Debug is only set for queries with debug:yes.
+	Debug *api.MatchDebug `json:"debug,omitempty"`
 }
 
 func (e *EventFileMatch) eventMatch() {}
@@ -63,6 +68,9 @@ type EventSymbolMatch struct {
 	Version    string   `json:"version,omitempty"`
 
 	Symbols []Symbol `json:"symbols"`
+
+	// Debug is only set for queries with debug:yes.
+	Debug *api.MatchDebug `json:"debug,omitempty"`
 }
 
 func (e *EventSymbolMatch) eventMatch() {}
@@ -94,6 +102,27 @@ type EventCommitMatch struct {
 
 func (e *EventCommitMatch) eventMatch() {}
 
+// EventFileDiffMatch is the rewrite of a file by a structural search with a
+// replace: value.
+type EventFileDiffMatch struct {
+	// Type is always FileDiffMatchType. Included here for marshalling.
+	Type MatchType `json:"type"`
+
+	Path       string   `json:"name"`
+	Repository string   `json:"repository"`
+	RepoStars  int      `json:"repoStars,omitempty"`
+	Branches   []string `json:"branches,omitempty"`
+	Version    string   `json:"version,omitempty"`
+
+	// Diff is the unified diff of the rewrite.
+	Diff string `json:"diff"`
+
+	// Debug is only set for queries with debug:yes.
+	Debug *api.MatchDebug `json:"debug,omitempty"`
+}
+
+func (e *EventFileDiffMatch) eventMatch() {}
+
 // EventFilter is a suggestion for a search filter. Currently has a 1-1
 // correspondance with the SearchFilter graphql type.
 type EventFilter struct {
@@ -104,6 +133,29 @@ type EventFilter struct {
 	Kind     string `json:"kind"`
 }
 
+// EventAggregations is the number of results of a search with an aggregate:
+// parameter, grouped by the aggregated value. Each event replaces the
+// previous one.
+type EventAggregations struct {
+	// Mode is the value of the aggregate: parameter.
+	Mode   string                  `json:"mode"`
+	Groups []EventAggregationGroup `json:"groups"`
+
+	// Complete is true once the search is done. Until then, the counts are
+	// partial and only grow.
+	Complete bool `json:"complete"`
+
+	// LimitHit is true if the search didn't search every result, so the
+	// counts are a lower bound even once the search is complete.
+	LimitHit bool `json:"limitHit"`
+}
+
+// EventAggregationGroup is the number of results that share the value Label.
+type EventAggregationGroup struct {
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
 // EventAlert is GQL.SearchAlert. It replaces when sent to match existing
 // behaviour.
 type EventAlert struct {
@@ -131,6 +183,7 @@ const (
 	RepoMatchType
 	SymbolMatchType
 	CommitMatchType
+	FileDiffMatchType
 )
 
 func (t MatchType) MarshalJSON() ([]byte, error) {
@@ -143,6 +196,8 @@ func (t MatchType) MarshalJSON() ([]byte, error) {
 		return []byte(`"symbol"`), nil
 	case CommitMatchType:
 		return []byte(`"commit"`), nil
+	case FileDiffMatchType:
+		return []byte(`"fileDiff"`), nil
 	default:
 		return nil, errors.Errorf("unknown MatchType: %d", t)
 	}
@@ -158,6 +213,8 @@ func (t *MatchType) UnmarshalJSON(b []byte) error {
 		*t = SymbolMatchType
 	} else if bytes.Equal(b, []byte(`"commit"`)) {
 		*t = CommitMatchType
+	} else if bytes.Equal(b, []byte(`"fileDiff"`)) {
+		*t = FileDiffMatchType
 	} else {
 		return errors.Errorf("unknown MatchType: %s", b)
 	}
@@ -102,6 +102,15 @@ func WithSelect(parent Sender, s filter.SelectPath) Sender {
 	})
 }
 
+// WithDebugQuery returns a child Stream of parent which records in the debug
+// information of each file match that it was found for the query q.
+func WithDebugQuery(parent Sender, q string) Sender {
+	return StreamFunc(func(e SearchEvent) {
+		result.WithDebugQuery(e.Results, q)
+		parent.Send(e)
+	})
+}
+
 type StreamFunc func(SearchEvent)
 
 func (f StreamFunc) Send(se SearchEvent) {
@@ -2,5 +2,5 @@
 package search
 
 func (p *TextPatternInfo) IsEmpty() bool {
-	return p.Pattern == "" && p.ExcludePattern == "" && len(p.IncludePatterns) == 0
+	return p.Pattern == "" && p.ExcludePattern == "" && len(p.IncludePatterns) == 0 && p.FileFilters.IsEmpty()
 }
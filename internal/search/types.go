@@ -176,6 +176,7 @@ type TextPatternInfo struct {
 	IsRegExp        bool
 	IsStructuralPat bool
 	CombyRule       string
+	CombyRewrite    string
 	IsWordMatch     bool
 	IsCaseSensitive bool
 	FileMatchLimit  int32
@@ -196,6 +197,13 @@ type TextPatternInfo struct {
 	PatternMatchesPath    bool
 
 	Languages []string
+
+	// FileFilters are the size:, lines: and binary: filters on the files.
+	FileFilters query.FileFilters
+
+	// Debug is true if the backends should record where they found each
+	// match and how they ranked it.
+	Debug bool
 }
 
 func (p *TextPatternInfo) String() string {
@@ -209,6 +217,9 @@ func (p *TextPatternInfo) String() string {
 		} else {
 			args = append(args, "comby")
 		}
+		if p.CombyRewrite != "" {
+			args = append(args, fmt.Sprintf("rewrite:%q", p.CombyRewrite))
+		}
 	}
 	if p.IsWordMatch {
 		args = append(args, "word")
@@ -228,6 +239,12 @@ func (p *TextPatternInfo) String() string {
 	for _, lang := range p.Languages {
 		args = append(args, fmt.Sprintf("lang:%s", lang))
 	}
+	if !p.FileFilters.IsEmpty() {
+		args = append(args, p.FileFilters.String())
+	}
+	if p.Debug {
+		args = append(args, "debug")
+	}
 
 	for _, inc := range p.FilePatternsReposMustInclude {
 		args = append(args, fmt.Sprintf("repositoryPathPattern:%s", inc))
@@ -250,6 +267,10 @@ func (p *TextPatternInfo) String() string {
 	return fmt.Sprintf("TextPatternInfo{%s}", strings.Join(args, ","))
 }
 
+// MaxUnindexedRepoRevSearchesPerQuery is the maximum number of repo@revs
+// searched by the unindexed searcher for a single query.
+const MaxUnindexedRepoRevSearchesPerQuery = 200
+
 type RepoOptions struct {
 	RepoFilters        []string
 	MinusRepoFilters   []string
@@ -262,6 +283,7 @@ type RepoOptions struct {
 	NoArchived         bool
 	OnlyArchived       bool
 	CommitAfter        string
+	RevAtTime          *query.RevAtTimePredicate // Search each repo at its last commit on or before a time
 	OnlyPrivate        bool
 	OnlyPublic         bool
 	Ranked             bool // Return results ordered by rank
@@ -294,6 +316,9 @@ func (op *RepoOptions) String() string {
 	if op.CommitAfter != "" {
 		_, _ = fmt.Fprintf(&b, " CommitAfter=%q", op.CommitAfter)
 	}
+	if op.RevAtTime != nil {
+		_, _ = fmt.Fprintf(&b, " RevAtTime=%q", op.RevAtTime.String())
+	}
 
 	if op.NoForks {
 		b.WriteString(" NoForks")
@@ -0,0 +1,203 @@
+package unindexed
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	otlog "github.com/opentracing/opentracing-go/log"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/search/repos"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	zoektutil "github.com/sourcegraph/sourcegraph/internal/search/zoekt"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// maxChangedFilesForIndexedBase is the largest number of files a revision
+// may change relative to the indexed default branch for it to be searched
+// with the index. Revisions with more changes are searched entirely with
+// searcher.
+const maxChangedFilesForIndexedBase = 200
+
+// indexedBaseConcurrency is the number of revisions searched with the index of
+// their default branch at a time. The searcher part of each search is also
+// limited by textSearchLimiter, which we can't use here since a revision holds
+// its slot while it waits for one.
+const indexedBaseConcurrency = 16
+
+// canSearchWithIndexedBase reports whether the unindexed revisions of
+// repositories with an indexed default branch can be searched with that
+// index. Structural search, repohasfile: filters and select:repo need all the
+// files of a revision to be searched the same way.
+func canSearchWithIndexedBase(args *search.TextParameters) bool {
+	p := args.PatternInfo
+	return !p.IsStructuralPat &&
+		len(p.FilePatternsReposMustInclude) == 0 &&
+		len(p.FilePatternsReposMustExclude) == 0 &&
+		p.Select.Root() != filter.Repository
+}
+
+// splitPartiallyIndexed splits the unindexed repository revisions of indexed
+// into those whose default branch is indexed and the others.
+func splitPartiallyIndexed(indexed *zoektutil.IndexedSearchRequest) (partiallyIndexed, unindexed []*search.RepositoryRevisions) {
+	for _, repoRevs := range indexed.Unindexed {
+		if _, ok := indexed.IndexedBase[repoRevs.Repo.Name]; ok {
+			partiallyIndexed = append(partiallyIndexed, repoRevs)
+		} else {
+			unindexed = append(unindexed, repoRevs)
+		}
+	}
+	return partiallyIndexed, unindexed
+}
+
+// searchWithIndexedBase searches the revisions of partiallyIndexed, whose
+// default branches are indexed. For each revision, Zoekt searches the files
+// which are the same as in the indexed commit and searcher searches the
+// files which differ. Revisions that differ too much from the indexed commit,
+// or whose differences can't be determined, are searched entirely with
+// searcher.
+func searchWithIndexedBase(ctx context.Context, args *search.TextParameters, indexed *zoektutil.IndexedSearchRequest, stream streaming.Sender, partiallyIndexed []*search.RepositoryRevisions) (err error) {
+	tr, ctx := trace.New(ctx, "searchWithIndexedBase", fmt.Sprintf("query: %s", args.PatternInfo.Pattern))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	var revs []*search.RepositoryRevisions
+	for _, repoAllRevs := range partiallyIndexed {
+		for _, rev := range repoAllRevs.RevSpecs() {
+			revs = append(revs, &search.RepositoryRevisions{Repo: repoAllRevs.Repo, Revs: []search.RevisionSpecifier{{RevSpec: rev}}})
+		}
+	}
+	fetchTimeout := searcherFetchTimeout(ctx, args, len(revs))
+
+	var (
+		mu       sync.Mutex
+		fallback []*search.RepositoryRevisions
+	)
+	g, gctx := errgroup.WithContext(ctx)
+	sem := make(chan struct{}, indexedBaseConcurrency)
+loop:
+	for _, repoRev := range revs {
+		select {
+		case sem <- struct{}{}:
+		case <-gctx.Done():
+			break loop
+		}
+
+		repoRev := repoRev
+		g.Go(func() error {
+			defer func() { <-sem }()
+
+			ok, err := searchRevWithIndexedBase(gctx, args, indexed, stream, repoRev, fetchTimeout)
+			if !ok {
+				mu.Lock()
+				fallback = append(fallback, repoRev)
+				mu.Unlock()
+			}
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	tr.LogFields(
+		otlog.Int("revs_count", len(revs)),
+		otlog.Int("fallback_count", len(fallback)),
+	)
+	return callSearcherOverRepos(ctx, args, stream, fallback, false)
+}
+
+// searchRevWithIndexedBase searches the single revision of repoRev with the
+// index of its default branch and searcher. It returns false if the revision
+// must be searched entirely with searcher instead.
+func searchRevWithIndexedBase(ctx context.Context, args *search.TextParameters, indexed *zoektutil.IndexedSearchRequest, stream streaming.Sender, repoRev *search.RepositoryRevisions, fetchTimeout time.Duration) (bool, error) {
+	gitserverRepo := repoRev.GitserverRepo()
+	rev := repoRev.RevSpecs()[0]
+	base := indexed.IndexedBase[repoRev.Repo.Name]
+
+	commit, err := git.ResolveRevision(ctx, gitserverRepo, rev, git.ResolveRevisionOptions{NoEnsureRevision: true})
+	if err != nil {
+		// Searcher reports the error.
+		return false, nil
+	}
+	changed, err := git.ChangedFiles(ctx, gitserverRepo, base, commit)
+	if err != nil {
+		log15.Warn("failed to list files changed relative to indexed commit", "error", err, "repo", repoRev.Repo.Name, "base", base, "rev", rev)
+		return false, nil
+	}
+	if len(changed) > maxChangedFilesForIndexedBase {
+		return false, nil
+	}
+
+	changedSet := make(map[string]struct{}, len(changed))
+	var paths []string
+	for _, f := range changed {
+		changedSet[f.Path] = struct{}{}
+		if !f.Deleted {
+			paths = append(paths, f.Path)
+		}
+	}
+
+	stream = dedupFileMatches(stream)
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		return indexed.SearchIndexedBase(ctx, repoRev.Repo, rev, commit, changedSet, stream)
+	})
+	if len(paths) > 0 {
+		g.Go(func() error {
+			limitCtx, limitDone, err := textSearchLimiter.Acquire(ctx)
+			if err != nil {
+				return err
+			}
+			defer limitDone()
+
+			matches, limitHit, err := searchFilesInRepoAtCommit(limitCtx, args.SearcherURLs, repoRev.Repo, gitserverRepo, rev, commit, paths, false, args.PatternInfo, fetchTimeout)
+			if err != nil {
+				log15.Warn("searchFilesInRepoAtCommit failed", "error", err, "repo", repoRev.Repo.Name, "temporary", errcode.IsTemporary(err))
+			}
+			stats, err := repos.HandleRepoSearchResult(repoRev, limitHit, false, err)
+			stream.Send(streaming.SearchEvent{
+				Results: matches,
+				Stats:   stats,
+			})
+			return err
+		})
+	}
+	return true, g.Wait()
+}
+
+// dedupFileMatches returns a sender which drops the file matches stream has
+// already been sent. Zoekt and searcher search disjoint sets of files of a
+// revision, so this only ensures a file is never reported twice if they
+// disagree about which files changed.
+func dedupFileMatches(stream streaming.Sender) streaming.Sender {
+	var mu sync.Mutex
+	dedup := result.NewDeduper()
+	return streaming.StreamFunc(func(e streaming.SearchEvent) {
+		mu.Lock()
+		results := make([]result.Match, 0, len(e.Results))
+		for _, m := range e.Results {
+			if _, ok := m.(*result.FileMatch); ok {
+				if dedup.Seen(m) {
+					continue
+				}
+				dedup.Add(m)
+			}
+			results = append(results, m)
+		}
+		mu.Unlock()
+
+		e.Results = results
+		stream.Send(e)
+	})
+}
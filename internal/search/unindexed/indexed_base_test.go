@@ -0,0 +1,120 @@
+package unindexed
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/zoekt"
+
+	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/endpoint"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	searchbackend "github.com/sourcegraph/sourcegraph/internal/search/backend"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/searcher"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestSearchFilesInRepos_indexedBase(t *testing.T) {
+	const (
+		baseCommit   = api.CommitID("1111111111111111111111111111111111111111")
+		branchCommit = api.CommitID("2222222222222222222222222222222222222222")
+	)
+
+	git.Mocks.ResolveRevision = func(spec string, opt git.ResolveRevisionOptions) (api.CommitID, error) {
+		if spec != "feature" {
+			t.Errorf("unexpected revision %q", spec)
+		}
+		return branchCommit, nil
+	}
+	git.Mocks.ChangedFiles = func(repo api.RepoName, base, head api.CommitID) ([]git.ChangedFile, error) {
+		if base != baseCommit || head != branchCommit {
+			t.Errorf("unexpected diff %s..%s", base, head)
+		}
+		return []git.ChangedFile{
+			{Path: "changed.go"},
+			{Path: "deleted.go", Deleted: true},
+			{Path: "added.go"},
+		}, nil
+	}
+	defer git.ResetMocks()
+
+	var searchedPaths []string
+	searcher.MockSearch = func(ctx context.Context, repo api.RepoName, commit api.CommitID, paths []string, p *search.TextPatternInfo, fetchTimeout time.Duration) ([]*protocol.FileMatch, bool, error) {
+		if commit != branchCommit {
+			t.Errorf("searcher searched commit %s, want %s", commit, branchCommit)
+		}
+		searchedPaths = paths
+		return []*protocol.FileMatch{{Path: "changed.go"}, {Path: "added.go"}}, false, nil
+	}
+	defer func() { searcher.MockSearch = nil }()
+
+	// The index of the default branch has matches in files which are
+	// unchanged, changed and deleted on the feature branch.
+	var files []zoekt.FileMatch
+	for _, name := range []string{"unchanged.go", "changed.go", "deleted.go"} {
+		files = append(files, zoekt.FileMatch{
+			FileName:   name,
+			Repository: "foo",
+			Branches:   []string{"HEAD"},
+			Version:    string(baseCommit),
+		})
+	}
+	zoektClient := &searchbackend.Zoekt{
+		Client: &searchbackend.FakeSearcher{
+			Result: &zoekt.SearchResult{Files: files},
+			Repos: []*zoekt.RepoListEntry{{
+				Repository: zoekt.Repository{
+					Name:     "foo",
+					Branches: []zoekt.RepositoryBranch{{Name: "HEAD", Version: string(baseCommit)}},
+				},
+			}},
+		},
+		DisableCache: true,
+	}
+
+	q, err := query.ParseLiteral("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := &search.TextParameters{
+		PatternInfo: &search.TextPatternInfo{
+			FileMatchLimit: search.DefaultMaxSearchResults,
+			Pattern:        "foo",
+		},
+		RepoPromise:  (&search.RepoPromise{}).Resolve(makeRepositoryRevisions("foo@feature")),
+		Query:        q,
+		Zoekt:        zoektClient,
+		SearcherURLs: endpoint.Static("test"),
+	}
+	matches, _, err := SearchFilesInReposBatch(context.Background(), args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []result.Key
+	for _, m := range matches {
+		got = append(got, m.Key())
+		if rev := *m.InputRev; rev != "feature" {
+			t.Errorf("%s: got input revision %q, want feature", m.Path, rev)
+		}
+	}
+	sort.Slice(got, func(i, j int) bool { return got[i].Less(got[j]) })
+	want := []result.Key{
+		{Repo: "foo", Commit: branchCommit, Path: "added.go"},
+		{Repo: "foo", Commit: branchCommit, Path: "changed.go"},
+		{Repo: "foo", Commit: branchCommit, Path: "unchanged.go"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected results (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff([]string{"changed.go", "added.go"}, searchedPaths); diff != "" {
+		t.Errorf("unexpected paths searched by searcher (-want +got):\n%s", diff)
+	}
+}
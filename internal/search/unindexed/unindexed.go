@@ -96,9 +96,20 @@ func SearchFilesInRepos(ctx context.Context, args *search.TextParameters, stream
 		}
 	}
 
+	// Revisions of repositories whose default branch is indexed are searched
+	// with the index and searcher for the files that differ from it.
+	searcherRepos := indexed.Unindexed
+	if canSearchWithIndexedBase(args) && len(indexed.IndexedBase) > 0 {
+		var partiallyIndexed []*search.RepositoryRevisions
+		partiallyIndexed, searcherRepos = splitPartiallyIndexed(indexed)
+		g.Go(func() error {
+			return searchWithIndexedBase(ctx, args, indexed, stream, partiallyIndexed)
+		})
+	}
+
 	// Concurrently run searcher for all unindexed repos regardless whether text, regexp, or structural search.
 	g.Go(func() error {
-		return callSearcherOverRepos(ctx, args, stream, indexed.Unindexed, false)
+		return callSearcherOverRepos(ctx, args, stream, searcherRepos, false)
 	})
 
 	return g.Wait()
@@ -134,6 +145,12 @@ func searchFilesInRepo(ctx context.Context, searcherURLs *endpoint.Map, repo typ
 		return nil, false, err
 	}
 
+	return searchFilesInRepoAtCommit(ctx, searcherURLs, repo, gitserverRepo, rev, commit, nil, index, info, fetchTimeout)
+}
+
+// searchFilesInRepoAtCommit searches rev, which resolves to commit. If paths
+// is non-empty, only the files with those paths are searched.
+func searchFilesInRepoAtCommit(ctx context.Context, searcherURLs *endpoint.Map, repo types.RepoName, gitserverRepo api.RepoName, rev string, commit api.CommitID, paths []string, index bool, info *search.TextPatternInfo, fetchTimeout time.Duration) ([]result.Match, bool, error) {
 	shouldBeSearched, err := repoShouldBeSearched(ctx, searcherURLs, info, gitserverRepo, commit, fetchTimeout)
 	if err != nil {
 		return nil, false, err
@@ -152,10 +169,12 @@ func searchFilesInRepo(ctx context.Context, searcherURLs *endpoint.Map, repo typ
 			return nil, false, err
 		}
 	}
-	searcherMatches, limitHit, err := searcher.Search(ctx, searcherURLs, gitserverRepo, rev, commit, index, info, fetchTimeout, indexerEndpoints)
+	start := time.Now()
+	searcherMatches, limitHit, err := searcher.Search(ctx, searcherURLs, gitserverRepo, rev, commit, paths, index, info, fetchTimeout, indexerEndpoints)
 	if err != nil {
 		return nil, false, err
 	}
+	elapsed := time.Since(start)
 
 	matches := make([]result.Match, 0, len(searcherMatches))
 	for _, fm := range searcherMatches {
@@ -172,7 +191,7 @@ func searchFilesInRepo(ctx context.Context, searcherURLs *endpoint.Map, repo typ
 			})
 		}
 
-		matches = append(matches, &result.FileMatch{
+		match := &result.FileMatch{
 			File: result.File{
 				Path:     fm.Path,
 				Repo:     repo,
@@ -181,7 +200,16 @@ func searchFilesInRepo(ctx context.Context, searcherURLs *endpoint.Map, repo typ
 			},
 			LineMatches: lineMatches,
 			LimitHit:    fm.LimitHit,
-		})
+			Diff:        fm.Diff,
+		}
+		if info.Debug {
+			match.Debug = &result.Debug{
+				Backend:  "searcher",
+				Endpoint: fm.Endpoint,
+				Duration: elapsed,
+			}
+		}
+		matches = append(matches, match)
 	}
 
 	return matches, limitHit, err
@@ -213,7 +241,7 @@ func repoShouldBeSearched(ctx context.Context, searcherURLs *endpoint.Map, searc
 func repoHasFilesWithNamesMatching(ctx context.Context, searcherURLs *endpoint.Map, include bool, repoHasFileFlag []string, gitserverRepo api.RepoName, commit api.CommitID, fetchTimeout time.Duration) (bool, error) {
 	for _, pattern := range repoHasFileFlag {
 		p := search.TextPatternInfo{IsRegExp: true, FileMatchLimit: 1, IncludePatterns: []string{pattern}, PathPatternsAreCaseSensitive: false, PatternMatchesContent: true, PatternMatchesPath: true}
-		matches, _, err := searcher.Search(ctx, searcherURLs, gitserverRepo, "", commit, false, &p, fetchTimeout, []string{})
+		matches, _, err := searcher.Search(ctx, searcherURLs, gitserverRepo, "", commit, nil, false, &p, fetchTimeout, []string{})
 		if err != nil {
 			return false, err
 		}
@@ -253,21 +281,7 @@ func callSearcherOverRepos(
 		tr.Finish()
 	}()
 
-	var fetchTimeout time.Duration
-	if len(searcherRepos) == 1 || args.UseFullDeadline {
-		// When searching a single repo or when an explicit timeout was specified, give it the remaining deadline to fetch the archive.
-		deadline, ok := ctx.Deadline()
-		if ok {
-			fetchTimeout = time.Until(deadline)
-		} else {
-			// In practice, this case should not happen because a deadline should always be set
-			// but if it does happen just set a long but finite timeout.
-			fetchTimeout = time.Minute
-		}
-	} else {
-		// When searching many repos, don't wait long for any single repo to fetch.
-		fetchTimeout = 500 * time.Millisecond
-	}
+	fetchTimeout := searcherFetchTimeout(ctx, args, len(searcherRepos))
 
 	tr.LogFields(
 		otlog.Int64("fetch_timeout_ms", fetchTimeout.Milliseconds()),
@@ -332,3 +346,20 @@ func callSearcherOverRepos(
 
 	return g.Wait()
 }
+
+// searcherFetchTimeout returns how long searcher waits to fetch the archive
+// of a repository when searching n repositories.
+func searcherFetchTimeout(ctx context.Context, args *search.TextParameters, n int) time.Duration {
+	if n == 1 || args.UseFullDeadline {
+		// When searching a single repo or when an explicit timeout was specified, give it the remaining deadline to fetch the archive.
+		deadline, ok := ctx.Deadline()
+		if ok {
+			return time.Until(deadline)
+		}
+		// In practice, this case should not happen because a deadline should always be set
+		// but if it does happen just set a long but finite timeout.
+		return time.Minute
+	}
+	// When searching many repos, don't wait long for any single repo to fetch.
+	return 500 * time.Millisecond
+}
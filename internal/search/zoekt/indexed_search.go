@@ -1,8 +1,10 @@
 package zoekt
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -160,6 +162,12 @@ type IndexedSearchRequest struct {
 	// repository revisions not indexed.
 	Unindexed []*search.RepositoryRevisions
 
+	// IndexedBase maps the names of repositories in Unindexed whose default
+	// branch is indexed to the commit Zoekt indexed. The unindexed revisions
+	// of these repositories can be searched with SearchIndexedBase and
+	// searcher for the files that differ from that commit.
+	IndexedBase map[api.RepoName]api.CommitID
+
 	// IndexUnavailable is true if zoekt is offline or disabled.
 	IndexUnavailable bool
 
@@ -205,7 +213,46 @@ func (s *IndexedSearchRequest) Search(ctx context.Context, c streaming.Sender) e
 	return zoektSearch(ctx, s.Args, s.RepoRevs, s.Typ, since, c)
 }
 
-const maxUnindexedRepoRevSearchesPerQuery = 200
+// SearchIndexedBase searches the revision rev of repo, which resolves to
+// commit, with the index of the repository's default branch at
+// IndexedBase[repo.Name]. The files in changed, which differ between the
+// indexed commit and commit, are not searched since their indexed contents
+// are stale. The results are reported at rev and commit.
+func (s *IndexedSearchRequest) SearchIndexedBase(ctx context.Context, repo types.RepoName, rev string, commit api.CommitID, changed map[string]struct{}, c streaming.Sender) error {
+	if s.Args == nil {
+		return nil
+	}
+	if _, ok := s.IndexedBase[repo.Name]; !ok {
+		return errors.Errorf("default branch of %s is not indexed", repo.Name)
+	}
+
+	repos := &IndexedRepoRevs{
+		repoRevs: map[string]*search.RepositoryRevisions{
+			string(repo.Name): {Repo: repo, Revs: []search.RevisionSpecifier{{RevSpec: rev}}},
+		},
+		repoBranches: map[string][]string{string(repo.Name): headBranch},
+	}
+
+	since := time.Since
+	if s.since != nil {
+		since = s.since
+	}
+
+	return zoektSearch(ctx, s.Args, repos, TextRequest, since, streaming.StreamFunc(func(e streaming.SearchEvent) {
+		results := make([]result.Match, 0, len(e.Results))
+		for _, m := range e.Results {
+			if fm, ok := m.(*result.FileMatch); ok {
+				if _, ok := changed[fm.Path]; ok {
+					continue
+				}
+				fm.CommitID = commit
+			}
+			results = append(results, m)
+		}
+		e.Results = results
+		c.Send(e)
+	}))
+}
 
 func NewIndexedSearchRequest(ctx context.Context, args *search.TextParameters, typ IndexedRequestType, stream streaming.Sender) (_ *IndexedSearchRequest, err error) {
 	tr, ctx := trace.New(ctx, "newIndexedSearchRequest", string(typ))
@@ -226,7 +273,7 @@ func NewIndexedSearchRequest(ctx context.Context, args *search.TextParameters, t
 		}
 
 		return &IndexedSearchRequest{
-			Unindexed:        limitUnindexedRepos(repos, maxUnindexedRepoRevSearchesPerQuery, stream),
+			Unindexed:        limitUnindexedRepos(repos, search.MaxUnindexedRepoRevSearchesPerQuery, stream),
 			IndexUnavailable: true,
 		}, nil
 	}
@@ -237,14 +284,37 @@ func NewIndexedSearchRequest(ctx context.Context, args *search.TextParameters, t
 			return nil, errors.Errorf("invalid index:%q (revsions with glob pattern cannot be resolved for indexed searches)", args.PatternInfo.Index)
 		}
 		return &IndexedSearchRequest{
-			Unindexed: limitUnindexedRepos(repos, maxUnindexedRepoRevSearchesPerQuery, stream),
+			Unindexed: limitUnindexedRepos(repos, search.MaxUnindexedRepoRevSearchesPerQuery, stream),
+		}, nil
+	}
+
+	// Fallback to Unindexed if the query searches revisions at a time, since
+	// Zoekt only indexes the latest revisions.
+	if args.Query.RevAtTime() != nil {
+		if args.PatternInfo.Index == query.Only {
+			return nil, errors.Errorf("invalid index:%q (revisions at a time cannot be resolved for indexed searches)", args.PatternInfo.Index)
+		}
+		return &IndexedSearchRequest{
+			Unindexed: limitUnindexedRepos(repos, search.MaxUnindexedRepoRevSearchesPerQuery, stream),
+		}, nil
+	}
+
+	// Fallback to Unindexed if the query filters on the size or line count of
+	// files, since Zoekt can only apply these filters to the files it returns
+	// after its match limits.
+	if args.PatternInfo.FileFilters.NeedContent() {
+		if args.PatternInfo.Index == query.Only {
+			return nil, errors.Errorf("invalid index:%q (size: and lines: are not supported for indexed search)", args.PatternInfo.Index)
+		}
+		return &IndexedSearchRequest{
+			Unindexed: limitUnindexedRepos(repos, search.MaxUnindexedRepoRevSearchesPerQuery, stream),
 		}, nil
 	}
 
 	// Fallback to Unindexed if index:no
 	if args.PatternInfo.Index == query.No {
 		return &IndexedSearchRequest{
-			Unindexed: limitUnindexedRepos(repos, maxUnindexedRepoRevSearchesPerQuery, stream),
+			Unindexed: limitUnindexedRepos(repos, search.MaxUnindexedRepoRevSearchesPerQuery, stream),
 		}, nil
 	}
 
@@ -271,7 +341,7 @@ func NewIndexedSearchRequest(ctx context.Context, args *search.TextParameters, t
 		}
 
 		return &IndexedSearchRequest{
-			Unindexed:        limitUnindexedRepos(repos, maxUnindexedRepoRevSearchesPerQuery, stream),
+			Unindexed:        limitUnindexedRepos(repos, search.MaxUnindexedRepoRevSearchesPerQuery, stream),
 			IndexUnavailable: true,
 		}, ctx.Err()
 	}
@@ -291,12 +361,18 @@ func NewIndexedSearchRequest(ctx context.Context, args *search.TextParameters, t
 		searcherRepos = limitUnindexedRepos(searcherRepos, 0, stream)
 	}
 
+	var indexedBase map[api.RepoName]api.CommitID
+	if typ == TextRequest {
+		indexedBase = zoektIndexedBase(indexedSet, searcherRepos)
+	}
+
 	return &IndexedSearchRequest{
 		Args: args,
 		Typ:  typ,
 
-		Unindexed: limitUnindexedRepos(searcherRepos, maxUnindexedRepoRevSearchesPerQuery, stream),
-		RepoRevs:  indexed,
+		Unindexed:   limitUnindexedRepos(searcherRepos, search.MaxUnindexedRepoRevSearchesPerQuery, stream),
+		IndexedBase: indexedBase,
+		RepoRevs:    indexed,
 
 		DisableUnindexedSearch: args.PatternInfo.Index == query.Only,
 	}, nil
@@ -325,6 +401,7 @@ func zoektSearchGlobal(ctx context.Context, args *search.TextParameters, typ Ind
 
 	// Start event stream.
 	t0 := time.Now()
+	debug := zoektDebugFunc(args.PatternInfo, since, t0)
 
 	// We use reposResolved to synchronize repo resolution and event processing.
 	reposResolved := make(chan struct{})
@@ -373,7 +450,8 @@ func zoektSearchGlobal(ctx context.Context, args *search.TextParameters, typ Ind
 		}
 
 		// PERF: if we are going to be selecting to repo results only anyways, we can just ask
-		// zoekt for only results of type repo.
+		// zoekt for only results of type repo. This doesn't work with the size: and lines:
+		// filters, since they are applied to the files zoekt returns.
 		if args.PatternInfo.Select.Root() == filter.Repository {
 			return zoektSearchReposOnly(ctx, args.Zoekt.Client, finalQuery, c, func() map[api.RepoID]*search.RepositoryRevisions {
 				<-reposResolved
@@ -390,8 +468,7 @@ func zoektSearchGlobal(ctx context.Context, args *search.TextParameters, typ Ind
 		bufSender, cleanup := bufferedSender(240, backend.ZoektStreamFunc(func(event *zoekt.SearchResult) {
 			foundResults.CAS(false, event.FileCount != 0 || event.MatchCount != 0)
 
-			files := event.Files
-			limitHit := event.FilesSkipped+event.ShardsSkipped > 0
+			files, limitHit := filterEvent(event, args.PatternInfo.FileFilters)
 
 			if len(files) == 0 {
 				c.Send(streaming.SearchEvent{
@@ -406,10 +483,13 @@ func zoektSearchGlobal(ctx context.Context, args *search.TextParameters, typ Ind
 				return
 			}
 
-			sendMatches(files, getRepoInputRev, typ, c, limitHit)
+			sendMatches(files, getRepoInputRev, typ, c, limitHit, debug)
 		}))
 		defer cleanup()
 
+		if args.PatternInfo.Debug {
+			ctx = backend.WithDebug(ctx)
+		}
 		return args.Zoekt.Client.StreamSearch(ctx, finalQuery, &searchOpts, bufSender)
 	})
 
@@ -460,6 +540,7 @@ func zoektSearch(ctx context.Context, args *search.TextParameters, repos *Indexe
 
 	// Start event stream.
 	t0 := time.Now()
+	debug := zoektDebugFunc(args.PatternInfo, since, t0)
 
 	if deadline, ok := ctx.Deadline(); ok {
 		// If the user manually specified a timeout, allow zoekt to use all of the remaining timeout.
@@ -479,7 +560,8 @@ func zoektSearch(ctx context.Context, args *search.TextParameters, repos *Indexe
 	}
 
 	// PERF: if we are going to be selecting to repo results only anyways, we can just ask
-	// zoekt for only results of type repo.
+	// zoekt for only results of type repo. This doesn't work with the size: and lines:
+	// filters, since they are applied to the files zoekt returns.
 	if args.PatternInfo.Select.Root() == filter.Repository {
 		return zoektSearchReposOnly(ctx, args.Zoekt.Client, finalQuery, c, func() map[api.RepoID]*search.RepositoryRevisions {
 			repoRevMap := make(map[api.RepoID]*search.RepositoryRevisions, len(repos.repoRevs))
@@ -490,12 +572,15 @@ func zoektSearch(ctx context.Context, args *search.TextParameters, repos *Indexe
 		})
 	}
 
+	if args.PatternInfo.Debug {
+		ctx = backend.WithDebug(ctx)
+	}
+
 	foundResults := atomic.Bool{}
 	err = args.Zoekt.Client.StreamSearch(ctx, finalQuery, &searchOpts, backend.ZoektStreamFunc(func(event *zoekt.SearchResult) {
 		foundResults.CAS(false, event.FileCount != 0 || event.MatchCount != 0)
 
-		files := event.Files
-		limitHit := event.FilesSkipped+event.ShardsSkipped > 0
+		files, limitHit := filterEvent(event, args.PatternInfo.FileFilters)
 
 		if len(files) == 0 {
 			c.Send(streaming.SearchEvent{
@@ -507,7 +592,7 @@ func zoektSearch(ctx context.Context, args *search.TextParameters, repos *Indexe
 		sendMatches(files, func(file *zoekt.FileMatch) (types.RepoName, []string, bool) {
 			repo, inputRevs := repos.getRepoInputRev(file)
 			return repo, inputRevs, true
-		}, typ, c, limitHit)
+		}, typ, c, limitHit, debug)
 	}))
 	if err != nil {
 		return err
@@ -527,7 +612,9 @@ func zoektSearch(ctx context.Context, args *search.TextParameters, repos *Indexe
 	return nil
 }
 
-func sendMatches(files []zoekt.FileMatch, getRepoInputRev repoRevFunc, typ IndexedRequestType, c streaming.Sender, limitHit bool) {
+// sendMatches sends the file matches found by Zoekt to c. If debug is
+// non-nil, it returns the debug information of each file match.
+func sendMatches(files []zoekt.FileMatch, getRepoInputRev repoRevFunc, typ IndexedRequestType, c streaming.Sender, limitHit bool, debug func(*zoekt.FileMatch) *result.Debug) {
 	matches := make([]result.Match, 0, len(files))
 	for _, file := range files {
 		repo, inputRevs, ok := getRepoInputRev(&file)
@@ -535,6 +622,11 @@ func sendMatches(files []zoekt.FileMatch, getRepoInputRev repoRevFunc, typ Index
 			continue
 		}
 
+		var fileDebug *result.Debug
+		if debug != nil {
+			fileDebug = debug(&file)
+		}
+
 		var lines []*result.LineMatch
 		if typ != SymbolRequest {
 			lines = zoektFileMatchToLineMatches(&file)
@@ -557,6 +649,10 @@ func sendMatches(files []zoekt.FileMatch, getRepoInputRev repoRevFunc, typ Index
 					Path:     file.FileName,
 				},
 			}
+			if fileDebug != nil {
+				d := *fileDebug
+				fm.Debug = &d
+			}
 			matches = append(matches, &fm)
 		}
 	}
@@ -569,6 +665,44 @@ func sendMatches(files []zoekt.FileMatch, getRepoInputRev repoRevFunc, typ Index
 	})
 }
 
+// zoektDebugFunc returns a function which returns the debug information of a
+// file match found by a Zoekt search started at t0, or nil if p doesn't ask
+// for debug information.
+func zoektDebugFunc(p *search.TextPatternInfo, since func(time.Time) time.Duration, t0 time.Time) func(*zoekt.FileMatch) *result.Debug {
+	if !p.Debug {
+		return nil
+	}
+	return func(file *zoekt.FileMatch) *result.Debug {
+		return zoektDebug(file, since(t0))
+	}
+}
+
+// zoektDebug returns the debug information of a file match which Zoekt found
+// after elapsed. It parses the "name:value, " pairs which HorizontalSearcher
+// adds to the Debug field of the match for the endpoint and the priority of
+// the shard.
+func zoektDebug(file *zoekt.FileMatch, elapsed time.Duration) *result.Debug {
+	d := &result.Debug{
+		Backend:  "zoekt",
+		Score:    file.Score,
+		Duration: elapsed,
+	}
+	for _, part := range strings.Split(file.Debug, ", ") {
+		i := strings.Index(part, ":")
+		if i < 0 {
+			continue
+		}
+		name, value := strings.TrimSpace(part[:i]), strings.TrimSpace(part[i+1:])
+		switch name {
+		case "endpoint":
+			d.Endpoint = value
+		case "shard-priority":
+			d.ShardPriority, _ = strconv.ParseFloat(value, 64)
+		}
+	}
+	return d
+}
+
 // bufferedSender returns a buffered Sender with capacity cap, and a cleanup
 // function which blocks until the buffer is drained. The cleanup function may
 // only be called once. For cap=0, bufferedSender returns the input sender.
@@ -635,6 +769,11 @@ func zoektFileMatchToLineMatches(file *zoekt.FileMatch) []*result.LineMatch {
 			continue
 		}
 
+		if bytes.IndexByte(l.Line, '\n') >= 0 {
+			lines = append(lines, splitZoektLineMatch(l)...)
+			continue
+		}
+
 		offsets := make([][2]int32, len(l.LineFragments))
 		for k, m := range l.LineFragments {
 			offset := utf8.RuneCount(l.Line[:m.LineOffset])
@@ -651,6 +790,44 @@ func zoektFileMatchToLineMatches(file *zoekt.FileMatch) []*result.LineMatch {
 	return lines
 }
 
+// splitZoektLineMatch splits a line match that spans several lines into one
+// line match per line, like searcher does. Zoekt returns such line matches for
+// fragments that match across lines, like those of NEAR operators.
+func splitZoektLineMatch(l zoekt.LineMatch) []*result.LineMatch {
+	var lines []*result.LineMatch
+	lineStart := 0
+	for i, line := range bytes.Split(l.Line, []byte{'\n'}) {
+		lineEnd := lineStart + len(line)
+		var offsets [][2]int32
+		for _, m := range l.LineFragments {
+			// Skip fragments that don't cover this line or its newline,
+			// and clip the others to this line.
+			start, end := m.LineOffset, m.LineOffset+m.MatchLength
+			if start > lineEnd || end < lineStart || (end == lineStart && m.MatchLength > 0) {
+				continue
+			}
+			if start < lineStart {
+				start = lineStart
+			}
+			if end > lineEnd {
+				end = lineEnd
+			}
+			offset := utf8.RuneCount(line[:start-lineStart])
+			length := utf8.RuneCount(line[start-lineStart : end-lineStart])
+			offsets = append(offsets, [2]int32{int32(offset), int32(length)})
+		}
+		if len(offsets) > 0 {
+			lines = append(lines, &result.LineMatch{
+				Preview:          string(line),
+				LineNumber:       int32(l.LineNumber - 1 + i),
+				OffsetAndLengths: offsets,
+			})
+		}
+		lineStart = lineEnd + 1 // Skip the newline.
+	}
+	return lines
+}
+
 func escape(s string) string {
 	isSpecial := func(c rune) bool {
 		switch c {
@@ -824,6 +1001,10 @@ func queryToZoektQuery(query *search.TextPatternInfo, typ IndexedRequestType) (z
 		and = append(and, &zoektquery.Not{Child: &zoektquery.Type{Type: zoektquery.TypeRepo, Child: q}})
 	}
 
+	if q := binaryFilterQuery(query.FileFilters); q != nil {
+		and = append(and, q)
+	}
+
 	return zoektquery.Simplify(zoektquery.NewAnd(and...)), nil
 }
 
@@ -857,6 +1038,23 @@ func zoektIndexedRepos(indexedSet map[string]*zoekt.Repository, revs []*search.R
 	return indexed, unindexed
 }
 
+// zoektIndexedBase returns the commits of the indexed default branches of
+// the unindexed repository revisions which only have explicit revisions.
+func zoektIndexedBase(indexedSet map[string]*zoekt.Repository, unindexed []*search.RepositoryRevisions) map[api.RepoName]api.CommitID {
+	var base map[api.RepoName]api.CommitID
+	for _, reporev := range unindexed {
+		repo, ok := indexedSet[string(reporev.Repo.Name)]
+		if !ok || len(repo.Branches) == 0 || repo.Branches[0].Name != "HEAD" || !reporev.OnlyExplicit() {
+			continue
+		}
+		if base == nil {
+			base = make(map[api.RepoName]api.CommitID)
+		}
+		base[reporev.Repo.Name] = api.CommitID(repo.Branches[0].Version)
+	}
+	return base
+}
+
 // limitUnindexedRepos limits the number of repo@revs searched by the
 // unindexed searcher codepath.  Sending many requests to searcher would
 // otherwise cause a flood of system and network requests that result in
@@ -249,6 +249,24 @@ func TestIndexedSearch(t *testing.T) {
 			wantMatchKeys:      nil,
 			wantMatchInputRevs: nil,
 		},
+		{
+			// Fallback to unindexed search if the query filters on the size of files.
+			name: "size filter",
+			args: args{
+				ctx:   context.Background(),
+				query: "size:>1MB",
+				patternInfo: &search.TextPatternInfo{
+					FileMatchLimit: 100,
+					FileFilters:    query.FileFilters{Sizes: []query.Comparison{{Op: ">", Value: 1 << 20}}},
+				},
+				repos:           makeRepositoryRevisions("foo/bar@HEAD"),
+				useFullDeadline: false,
+				results:         []zoekt.FileMatch{{Repository: "foo/bar", FileName: "large.json"}},
+			},
+			wantUnindexed:      makeRepositoryRevisions("foo/bar@HEAD"),
+			wantMatchKeys:      nil,
+			wantMatchInputRevs: nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -408,6 +426,39 @@ func TestZoektIndexedRepos(t *testing.T) {
 	}
 }
 
+func TestZoektIndexedBase(t *testing.T) {
+	zoektRepos := map[string]*zoekt.Repository{
+		"foo/indexed": {
+			Name:     "foo/indexed",
+			Branches: []zoekt.RepositoryBranch{{Name: "HEAD", Version: "deadbeef"}},
+		},
+		"foo/no-head": {
+			Name:     "foo/no-head",
+			Branches: []zoekt.RepositoryBranch{{Name: "main", Version: "deadcow"}},
+		},
+	}
+	unindexed := makeRepositoryRevisions(
+		"foo/indexed@feature",
+		"foo/no-head@feature",
+		"foo/unindexed@feature",
+	)
+	unindexed = append(unindexed, &search.RepositoryRevisions{
+		Repo: mkRepos("foo/indexed")[0],
+		Revs: []search.RevisionSpecifier{{RefGlob: "refs/heads/*"}},
+	})
+
+	got := zoektIndexedBase(zoektRepos, unindexed[:3])
+	want := map[api.RepoName]api.CommitID{"foo/indexed": "deadbeef"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected indexed base (-want +got):\n%s", diff)
+	}
+
+	// Ref globs can't be diffed against the indexed commit.
+	if got := zoektIndexedBase(zoektRepos, unindexed[3:]); got != nil {
+		t.Errorf("got %v, want no indexed base for ref globs", got)
+	}
+}
+
 func TestZoektResultCountFactor(t *testing.T) {
 	cases := []struct {
 		name         string
@@ -845,6 +896,72 @@ func TestZoektFileMatchToSymbolResults(t *testing.T) {
 	}
 }
 
+func TestZoektFileMatchToLineMatches(t *testing.T) {
+	file := &zoekt.FileMatch{
+		LineMatches: []zoekt.LineMatch{{
+			Line:       []byte("foo bar"),
+			LineNumber: 1,
+			LineFragments: []zoekt.LineFragmentMatch{{
+				LineOffset:  4,
+				MatchLength: 3,
+			}},
+		}, {
+			// A fragment that spans three lines, like a match of foo NEAR/2 bar.
+			Line:       []byte("x föo\n\nbar y"),
+			LineNumber: 5,
+			LineFragments: []zoekt.LineFragmentMatch{{
+				LineOffset:  2,
+				MatchLength: 9,
+			}},
+		}},
+	}
+
+	want := []*result.LineMatch{{
+		Preview:          "foo bar",
+		LineNumber:       0,
+		OffsetAndLengths: [][2]int32{{4, 3}},
+	}, {
+		Preview:          "x föo",
+		LineNumber:       4,
+		OffsetAndLengths: [][2]int32{{2, 3}},
+	}, {
+		Preview:          "",
+		LineNumber:       5,
+		OffsetAndLengths: [][2]int32{{0, 0}},
+	}, {
+		Preview:          "bar y",
+		LineNumber:       6,
+		OffsetAndLengths: [][2]int32{{0, 3}},
+	}}
+	if diff := cmp.Diff(want, zoektFileMatchToLineMatches(file)); diff != "" {
+		t.Fatalf("line match mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestZoektDebug(t *testing.T) {
+	file := &zoekt.FileMatch{
+		Score: 7010.5,
+		Debug: "endpoint:zoekt-0:6070, shard-priority:42, fragment:500.000000, atom:6500.000000, doc-order:10.000000, shard-order:0.500000, ",
+	}
+
+	want := &result.Debug{
+		Backend:       "zoekt",
+		Endpoint:      "zoekt-0:6070",
+		Score:         7010.5,
+		ShardPriority: 42,
+		Duration:      time.Second,
+	}
+	if diff := cmp.Diff(want, zoektDebug(file, time.Second)); diff != "" {
+		t.Fatalf("debug mismatch (-want +got):\n%s", diff)
+	}
+
+	// Without HorizontalSearcher, there is no endpoint or shard priority.
+	want = &result.Debug{Backend: "zoekt", Score: 1}
+	if diff := cmp.Diff(want, zoektDebug(&zoekt.FileMatch{Score: 1}, 0)); diff != "" {
+		t.Fatalf("debug mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func repoRevsSliceToMap(rs []*search.RepositoryRevisions) map[string]*search.RepositoryRevisions {
 	m := map[string]*search.RepositoryRevisions{}
 	for _, r := range rs {
@@ -1,8 +1,10 @@
 package zoekt
 
 import (
+	"bytes"
 	"context"
 	"regexp/syntax"
+	"strconv"
 	"time"
 
 	"github.com/google/zoekt"
@@ -10,7 +12,9 @@ import (
 	"github.com/inconshreveable/log15"
 	"github.com/opentracing/opentracing-go"
 
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
@@ -90,6 +94,98 @@ func SearchOpts(ctx context.Context, k int, query *search.TextPatternInfo) zoekt
 	return searchOpts
 }
 
+// notIndexedMarker is the prefix zoekt replaces the contents of files with
+// if it doesn't index them.
+const notIndexedMarker = "NOT-INDEXED: "
+
+var notIndexedTooLargeRe = lazyregexp.New(`^document size (\d+) larger than limit`)
+
+// binaryContentRe matches the contents zoekt stores for the binary files it
+// doesn't index.
+var binaryContentRe = func() *syntax.Regexp {
+	re, err := syntax.Parse(`\A`+notIndexedMarker+`binary content at byte offset \d+\z`, syntax.Perl)
+	if err != nil {
+		panic(err)
+	}
+	return re
+}()
+
+// binaryFilterQuery returns the zoekt query for the binary: filter, or nil if
+// there is none. Zoekt replaces the contents of binary files with a marker,
+// which lets zoekt apply the filter rather than us fetching the contents of
+// every file.
+func binaryFilterQuery(filters query.FileFilters) zoektquery.Q {
+	if filters.Binary == nil {
+		return nil
+	}
+	q := &zoektquery.Regexp{Regexp: binaryContentRe, Content: true, CaseSensitive: true}
+	if !*filters.Binary {
+		return &zoektquery.Not{Child: q}
+	}
+	return q
+}
+
+// filterEvent returns the files of a zoekt search event which pass the file
+// filters, and whether the event's results are limited because zoekt skipped
+// files.
+func filterEvent(event *zoekt.SearchResult, filters query.FileFilters) (files []zoekt.FileMatch, limitHit bool) {
+	return FilterFileMatches(event.Files, filters), event.FilesSkipped+event.ShardsSkipped > 0
+}
+
+// FilterFileMatches returns the files which pass the file filters. The
+// files must have been searched with SearchOptions.Whole if the filters need
+// their contents, and with the binaryFilterQuery otherwise.
+func FilterFileMatches(files []zoekt.FileMatch, filters query.FileFilters) []zoekt.FileMatch {
+	if filters.IsEmpty() {
+		return files
+	}
+	filtered := files[:0]
+	for _, file := range files {
+		if filters.NeedContent() {
+			size, lines, binary := fileAttributes(file.Content)
+			if !filters.Matches(size, lines, binary) {
+				continue
+			}
+		}
+		if filters.Binary != nil && *filters.Binary {
+			file.LineMatches = withoutNotIndexedMarker(file.LineMatches)
+		}
+		filtered = append(filtered, file)
+	}
+	return filtered
+}
+
+// withoutNotIndexedMarker removes the matches of the binaryFilterQuery from
+// the line matches of a binary file.
+func withoutNotIndexedMarker(lineMatches []zoekt.LineMatch) []zoekt.LineMatch {
+	kept := lineMatches[:0]
+	for _, lm := range lineMatches {
+		if !lm.FileName && bytes.HasPrefix(lm.Line, []byte(notIndexedMarker)) {
+			continue
+		}
+		kept = append(kept, lm)
+	}
+	return kept
+}
+
+// fileAttributes returns the size in bytes, the number of lines and whether
+// a file with the contents is binary. The size and number of lines are -1 if
+// they are unknown because zoekt didn't index the contents.
+func fileAttributes(content []byte) (size int64, lines int, binary bool) {
+	if !bytes.HasPrefix(content, []byte(notIndexedMarker)) {
+		return int64(len(content)), query.LineCount(content), false
+	}
+	reason := content[len(notIndexedMarker):]
+	if bytes.HasPrefix(reason, []byte("binary content")) {
+		return -1, -1, true
+	}
+	if m := notIndexedTooLargeRe.FindSubmatch(reason); m != nil {
+		size, _ := strconv.ParseInt(string(m[1]), 10, 64)
+		return size, -1, false
+	}
+	return -1, -1, false
+}
+
 func ResultCountFactor(numRepos int, fileMatchLimit int32, globalSearch bool) (k int) {
 	if globalSearch {
 		// for globalSearch, numRepos = 0, but effectively we are searching over all
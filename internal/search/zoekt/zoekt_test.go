@@ -0,0 +1,122 @@
+package zoekt
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/zoekt"
+	zoektquery "github.com/google/zoekt/query"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+)
+
+func TestFilterFileMatches(t *testing.T) {
+	files := []zoekt.FileMatch{
+		{FileName: "small.go", Content: []byte("package main\n")},
+		{FileName: "no-newline", Content: []byte("a\nb")},
+		{FileName: "bundle.min.js", Content: []byte("NOT-INDEXED: document size 2097152 larger than limit 1048576")},
+		{FileName: "image.png", Content: []byte("NOT-INDEXED: binary content at byte offset 4")},
+	}
+
+	yes, no := true, false
+	cases := []struct {
+		name    string
+		filters query.FileFilters
+		want    []string
+	}{{
+		name: "no filters",
+		want: []string{"small.go", "no-newline", "bundle.min.js", "image.png"},
+	}, {
+		name:    "larger than",
+		filters: query.FileFilters{Sizes: []query.Comparison{{Op: ">", Value: 1 << 20}}},
+		want:    []string{"bundle.min.js", "image.png"},
+	}, {
+		name:    "smaller than",
+		filters: query.FileFilters{Sizes: []query.Comparison{{Op: "<", Value: 10}}},
+		want:    []string{"no-newline", "image.png"},
+	}, {
+		name:    "lines",
+		filters: query.FileFilters{Lines: []query.Comparison{{Op: "=", Value: 2}}},
+		want:    []string{"no-newline", "bundle.min.js", "image.png"},
+	}, {
+		name:    "size and not binary",
+		filters: query.FileFilters{Sizes: []query.Comparison{{Op: "<", Value: 100}}, Binary: &no},
+		want:    []string{"small.go", "no-newline"},
+	}, {
+		// Without size: or lines: filters, zoekt applies the binary: filter.
+		name:    "binary",
+		filters: query.FileFilters{Binary: &yes},
+		want:    []string{"small.go", "no-newline", "bundle.min.js", "image.png"},
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, f := range FilterFileMatches(append([]zoekt.FileMatch(nil), files...), tc.filters) {
+				got = append(got, f.FileName)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFilterFileMatches_binaryLineMatches(t *testing.T) {
+	yes := true
+	files := []zoekt.FileMatch{{
+		FileName: "image.png",
+		LineMatches: []zoekt.LineMatch{
+			{Line: []byte("image.png"), FileName: true},
+			{Line: []byte("NOT-INDEXED: binary content at byte offset 4")},
+		},
+	}}
+
+	got := FilterFileMatches(files, query.FileFilters{Binary: &yes})
+	want := []zoekt.LineMatch{{Line: []byte("image.png"), FileName: true}}
+	if diff := cmp.Diff(want, got[0].LineMatches); diff != "" {
+		t.Fatalf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestBinaryFilterQuery(t *testing.T) {
+	yes, no := true, false
+	if q := binaryFilterQuery(query.FileFilters{}); q != nil {
+		t.Fatalf("got query %s without binary: filter, want nil", q)
+	}
+
+	binary := binaryFilterQuery(query.FileFilters{Binary: &yes})
+	notBinary := binaryFilterQuery(query.FileFilters{Binary: &no})
+	for content, wantBinary := range map[string]bool{
+		"NOT-INDEXED: binary content at byte offset 4":                  true,
+		"NOT-INDEXED: document size 2097152 larger than limit 1048576":  false,
+		"// NOT-INDEXED: binary content at byte offset 4 is the marker": false,
+		"package main\n": false,
+	} {
+		re := regexp.MustCompile(binary.(*zoektquery.Regexp).Regexp.String())
+		if got := re.MatchString(content); got != wantBinary {
+			t.Errorf("%q: got binary %t, want %t", content, got, wantBinary)
+		}
+	}
+	if _, ok := notBinary.(*zoektquery.Not); !ok {
+		t.Errorf("got query %s for binary:no, want negation", notBinary)
+	}
+}
+
+func TestFilterEvent(t *testing.T) {
+	event := &zoekt.SearchResult{
+		Files: []zoekt.FileMatch{{FileName: "small.go", Content: []byte("package main\n")}},
+	}
+	filters := query.FileFilters{Lines: []query.Comparison{{Op: ">", Value: 100}}}
+
+	if files, limitHit := filterEvent(event, filters); len(files) != 0 || limitHit {
+		t.Fatalf("got %d files and limitHit %t, want 0 and false", len(files), limitHit)
+	}
+
+	// Files dropped from a truncated page are reported as a limit hit.
+	event.Files = []zoekt.FileMatch{{FileName: "small.go", Content: []byte("package main\n")}}
+	event.Stats.FilesSkipped = 1
+	if files, limitHit := filterEvent(event, filters); len(files) != 0 || !limitHit {
+		t.Fatalf("got %d files and limitHit %t, want 0 and true", len(files), limitHit)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -36,6 +37,10 @@ import (
 // than this are searched.
 const maxFileSize = 1 << 20 // 1MB; match https://sourcegraph.com/search?q=repo:%5Egithub%5C.com/sourcegraph/zoekt%24+%22-file_limit%22
 
+// archiveFormatVersion is part of the key of cached archives. Increment it
+// when the contents of the archives change.
+const archiveFormatVersion = 1
+
 // Store manages the fetching and storing of git archives. Its main purpose is
 // keeping a local disk cache of the fetched archives to help speed up future
 // requests for the same archive. As a performance optimization, it is also
@@ -58,6 +63,11 @@ type Store struct {
 	// determine if the error is a bad request (eg invalid repo).
 	FetchTar func(ctx context.Context, repo api.RepoName, commit api.CommitID) (io.ReadCloser, error)
 
+	// FetchTarPaths is like FetchTar, but the archive only contains the files
+	// with the given paths. It is optional. If it is nil, PrepareZipPaths
+	// filters the archive returned by FetchTar instead.
+	FetchTarPaths func(ctx context.Context, repo api.RepoName, commit api.CommitID, paths []string) (io.ReadCloser, error)
+
 	// FilterTar returns a FilterFunc that filters out files we don't want to write to disk
 	FilterTar func(ctx context.Context, repo api.RepoName, commit api.CommitID) (FilterFunc, error)
 
@@ -110,6 +120,13 @@ func (s *Store) Start() {
 // PrepareZip returns the path to a local zip archive of repo at commit.
 // It will first consult the local cache, otherwise will fetch from the network.
 func (s *Store) PrepareZip(ctx context.Context, repo api.RepoName, commit api.CommitID) (path string, err error) {
+	return s.PrepareZipPaths(ctx, repo, commit, nil)
+}
+
+// PrepareZipPaths is like PrepareZip, but if paths is non-empty the archive
+// only contains the files with those paths. It is used to search the few
+// files of a revision that differ from an indexed commit.
+func (s *Store) PrepareZipPaths(ctx context.Context, repo api.RepoName, commit api.CommitID, paths []string) (path string, err error) {
 	span, ctx := ot.StartSpanFromContext(ctx, "Store.prepareZip")
 	ext.Component.Set(span, "store")
 	defer func() {
@@ -132,7 +149,13 @@ func (s *Store) PrepareZip(ctx context.Context, repo api.RepoName, commit api.Co
 	largeFilePatterns := conf.Get().SearchLargeFiles
 
 	// key is a sha256 hash since we want to use it for the disk name
-	h := sha256.Sum256([]byte(fmt.Sprintf("%q %q %q", repo, commit, largeFilePatterns)))
+	keyData := fmt.Sprintf("%q %q %q %d", repo, commit, largeFilePatterns, archiveFormatVersion)
+	if len(paths) > 0 {
+		paths = append([]string(nil), paths...)
+		sort.Strings(paths)
+		keyData += fmt.Sprintf(" %q", paths)
+	}
+	h := sha256.Sum256([]byte(keyData))
 	key := hex.EncodeToString(h[:])
 	span.LogKV("key", key)
 
@@ -149,7 +172,7 @@ func (s *Store) PrepareZip(ctx context.Context, repo api.RepoName, commit api.Co
 		// since we're just going to close it again immediately.
 		bgctx := opentracing.ContextWithSpan(context.Background(), opentracing.SpanFromContext(ctx))
 		f, err := s.cache.Open(bgctx, key, func(ctx context.Context) (io.ReadCloser, error) {
-			return s.fetch(ctx, repo, commit, largeFilePatterns)
+			return s.fetch(ctx, repo, commit, paths, largeFilePatterns)
 		})
 		var path string
 		if f != nil {
@@ -179,7 +202,7 @@ func (s *Store) PrepareZip(ctx context.Context, repo api.RepoName, commit api.Co
 // fetch fetches an archive from the network and stores it on disk. It does
 // not populate the in-memory cache. You should probably be calling
 // prepareZip.
-func (s *Store) fetch(ctx context.Context, repo api.RepoName, commit api.CommitID, largeFilePatterns []string) (rc io.ReadCloser, err error) {
+func (s *Store) fetch(ctx context.Context, repo api.RepoName, commit api.CommitID, paths, largeFilePatterns []string) (rc io.ReadCloser, err error) {
 	fetchQueueSize.Inc()
 	ctx, releaseFetchLimiter, err := s.fetchLimiter.Acquire(ctx) // Acquire concurrent fetches semaphore
 	if err != nil {
@@ -196,6 +219,7 @@ func (s *Store) fetch(ctx context.Context, repo api.RepoName, commit api.CommitI
 	ext.Component.Set(span, "store")
 	span.SetTag("repo", repo)
 	span.SetTag("commit", commit)
+	span.SetTag("paths", len(paths))
 
 	// Done is called when the returned reader is closed, or if this function
 	// returns an error. It should always be called once.
@@ -222,7 +246,12 @@ func (s *Store) fetch(ctx context.Context, repo api.RepoName, commit api.CommitI
 		}
 	}()
 
-	r, err := s.FetchTar(ctx, repo, commit)
+	var r io.ReadCloser
+	if len(paths) > 0 && s.FetchTarPaths != nil {
+		r, err = s.FetchTarPaths(ctx, repo, commit, paths)
+	} else {
+		r, err = s.FetchTar(ctx, repo, commit)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -231,9 +260,13 @@ func (s *Store) fetch(ctx context.Context, repo api.RepoName, commit api.CommitI
 	if s.FilterTar != nil {
 		filter, err = s.FilterTar(ctx, repo, commit)
 		if err != nil {
+			r.Close()
 			return nil, errors.Errorf("error while calling FilterTar: %w", err)
 		}
 	}
+	if len(paths) > 0 && s.FetchTarPaths == nil {
+		filter = filterPaths(filter, paths)
+	}
 
 	pr, pw := io.Pipe()
 
@@ -259,6 +292,21 @@ func (s *Store) fetch(ctx context.Context, repo api.RepoName, commit api.CommitI
 	return pr, nil
 }
 
+// filterPaths returns a FilterFunc which additionally filters out the files
+// whose paths are not in paths.
+func filterPaths(filter FilterFunc, paths []string) FilterFunc {
+	set := make(map[string]struct{}, len(paths))
+	for _, p := range paths {
+		set[p] = struct{}{}
+	}
+	return func(hdr *tar.Header) bool {
+		if _, ok := set[hdr.Name]; !ok {
+			return true
+		}
+		return filter(hdr)
+	}
+}
+
 // copySearchable copies searchable files from tr to zw. A searchable file is
 // any file that is under size limit, non-binary, and not matching the filter.
 func copySearchable(tr *tar.Reader, zw *zip.Writer, largeFilePatterns []string, filter FilterFunc) error {
@@ -290,21 +338,9 @@ func copySearchable(tr *tar.Reader, zw *zip.Writer, largeFilePatterns []string,
 			continue
 		}
 
-		// We are happy with the file, so we can write it to zw.
-		w, err := zw.CreateHeader(&zip.FileHeader{
-			Name:   hdr.Name,
-			Method: zip.Store,
-		})
-		if err != nil {
-			return err
-		}
-
 		n, err := tr.Read(buf)
 		switch err {
 		case io.EOF:
-			if n == 0 {
-				continue
-			}
 		case nil:
 		default:
 			return err
@@ -312,13 +348,31 @@ func copySearchable(tr *tar.Reader, zw *zip.Writer, largeFilePatterns []string,
 
 		// We do not search the content of large files unless they are
 		// allowed.
+		var skipped *SkippedFile
 		if hdr.Size > maxFileSize && !ignoreSizeMax(hdr.Name, largeFilePatterns) {
-			continue
+			skipped = &SkippedFile{Size: hdr.Size}
 		}
 
 		// Heuristic: Assume file is binary if first 256 bytes contain a
 		// 0x00. Best effort, so ignore err. We only search names of binary files.
 		if n > 0 && bytes.IndexByte(buf[:n], 0x00) >= 0 {
+			skipped = &SkippedFile{Size: hdr.Size, Binary: true}
+		}
+
+		// We are happy with the file, so we can write it to zw. We record
+		// why the contents of skipped files are missing in the comment.
+		fh := &zip.FileHeader{
+			Name:   hdr.Name,
+			Method: zip.Store,
+		}
+		if skipped != nil {
+			fh.Comment = skipped.comment()
+		}
+		w, err := zw.CreateHeader(fh)
+		if err != nil {
+			return err
+		}
+		if skipped != nil || n == 0 {
 			continue
 		}
 
@@ -2,6 +2,7 @@ package store
 
 import (
 	"archive/tar"
+	"archive/zip"
 	"bytes"
 	"context"
 	"io"
@@ -11,6 +12,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
@@ -117,6 +119,142 @@ func TestPrepareZip_errHeader(t *testing.T) {
 	}
 }
 
+func TestPrepareZipPaths(t *testing.T) {
+	tarOf := func(names ...string) io.ReadCloser {
+		buf := new(bytes.Buffer)
+		w := tar.NewWriter(buf)
+		for _, name := range names {
+			if err := w.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(name)), Typeflag: tar.TypeReg}); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := w.Write([]byte(name)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return io.NopCloser(bytes.NewReader(buf.Bytes()))
+	}
+	zipNames := func(path string) []string {
+		r, err := zip.OpenReader(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Close()
+		var names []string
+		for _, f := range r.File {
+			names = append(names, f.Name)
+		}
+		return names
+	}
+	commit := api.CommitID("deadbeefdeadbeefdeadbeefdeadbeefdeadbeef")
+
+	t.Run("filter FetchTar", func(t *testing.T) {
+		s, cleanup := tmpStore(t)
+		defer cleanup()
+		s.FetchTar = func(ctx context.Context, repo api.RepoName, commit api.CommitID) (io.ReadCloser, error) {
+			return tarOf("a", "b", "dir/c"), nil
+		}
+
+		path, err := s.PrepareZipPaths(context.Background(), "foo", commit, []string{"dir/c", "a"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]string{"a", "dir/c"}, zipNames(path)); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+
+		// The full archive is cached separately.
+		path, err = s.PrepareZip(context.Background(), "foo", commit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]string{"a", "b", "dir/c"}, zipNames(path)); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("FetchTarPaths", func(t *testing.T) {
+		s, cleanup := tmpStore(t)
+		defer cleanup()
+		var gotPaths []string
+		s.FetchTarPaths = func(ctx context.Context, repo api.RepoName, commit api.CommitID, paths []string) (io.ReadCloser, error) {
+			gotPaths = paths
+			return tarOf(paths...), nil
+		}
+
+		path, err := s.PrepareZipPaths(context.Background(), "foo", commit, []string{"b"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]string{"b"}, gotPaths); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]string{"b"}, zipNames(path)); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestCopySearchable_skippedFiles(t *testing.T) {
+	files := map[string][]byte{
+		"a.txt":     []byte("hello\nworld\n"),
+		"empty":     {},
+		"large.txt": bytes.Repeat([]byte("a\n"), maxFileSize),
+		"large.foo": bytes.Repeat([]byte("b\n"), maxFileSize),
+		"image.png": []byte("\x89PNG\x00\x00"),
+	}
+	tarBuf := new(bytes.Buffer)
+	tw := tar.NewWriter(tarBuf)
+	for _, name := range []string{"a.txt", "empty", "large.txt", "large.foo", "image.png"} {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(files[name])), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(files[name]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	zipBuf := new(bytes.Buffer)
+	zw := zip.NewWriter(zipBuf)
+	if err := copySearchable(tar.NewReader(tarBuf), zw, []string{"*.foo"}, func(*tar.Header) bool { return false }); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	zf, err := MockZipFile(zipBuf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type file struct {
+		Name    string
+		Len     int32
+		Size    int64
+		Skipped *SkippedFile
+	}
+	var got []file
+	for i := range zf.Files {
+		f := &zf.Files[i]
+		got = append(got, file{Name: f.Name, Len: f.Len, Size: f.Size(), Skipped: f.Skipped})
+	}
+	want := []file{
+		{Name: "a.txt", Len: 12, Size: 12},
+		{Name: "empty"},
+		{Name: "large.txt", Size: 2 * maxFileSize, Skipped: &SkippedFile{Size: 2 * maxFileSize}},
+		{Name: "large.foo", Len: 2 * maxFileSize, Size: 2 * maxFileSize},
+		{Name: "image.png", Size: 6, Skipped: &SkippedFile{Size: 6, Binary: true}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestIngoreSizeMax(t *testing.T) {
 	patterns := []string{
 		"foo",
@@ -153,6 +153,12 @@ func (f *ZipFile) PopulateFiles(r *zip.Reader) error {
 			return errors.Errorf("file %s has size > 2gb: %v", file.Name, size)
 		}
 		f.Files[i] = SrcFile{Name: file.Name, Off: off, Len: int32(size)}
+		if file.Comment != "" {
+			f.Files[i].Skipped, err = parseSkippedFile(file.Comment)
+			if err != nil {
+				return errors.Wrapf(err, "file %s", file.Name)
+			}
+		}
 		if size > f.MaxLen {
 			f.MaxLen = size
 		}
@@ -202,6 +208,40 @@ type SrcFile struct {
 	Name string
 	Off  int64
 	Len  int32
+	// Skipped is nil unless the contents of the file were not stored in the
+	// archive because the file is too large or binary.
+	Skipped *SkippedFile
+}
+
+// SkippedFile describes a file whose contents are not searched.
+type SkippedFile struct {
+	// Size is the size of the file in bytes.
+	Size int64
+	// Binary is true if the file was skipped because it is binary, and
+	// false if it is too large.
+	Binary bool
+}
+
+// comment encodes s as the comment of a file in a zip archive.
+func (s *SkippedFile) comment() string {
+	return fmt.Sprintf("size=%d binary=%t", s.Size, s.Binary)
+}
+
+func parseSkippedFile(comment string) (*SkippedFile, error) {
+	var s SkippedFile
+	if _, err := fmt.Sscanf(comment, "size=%d binary=%t", &s.Size, &s.Binary); err != nil {
+		return nil, errors.Wrapf(err, "invalid comment %q", comment)
+	}
+	return &s, nil
+}
+
+// Size returns the size of the file in bytes, including the size of contents
+// which were not stored in the archive.
+func (f *SrcFile) Size() int64 {
+	if f.Skipped != nil {
+		return f.Skipped.Size
+	}
+	return int64(f.Len)
 }
 
 // Data returns the contents of s, which is a SrcFile in f.
@@ -19,6 +19,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/endpoint"
 	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/requestid"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
@@ -31,11 +32,13 @@ var symbolsURL = env.Get("SYMBOLS_URL", "k8s+http://symbols:3184", "symbols serv
 var DefaultClient = &Client{
 	URL: symbolsURL,
 	HTTPClient: &http.Client{
-		// ot.Transport will propagate opentracing spans
+		// ot.Transport will propagate opentracing spans, and requestid.Transport the request ID
 		Transport: &ot.Transport{
-			RoundTripper: &http.Transport{
-				// Default is 2, but we can send many concurrent requests
-				MaxIdleConnsPerHost: 500,
+			RoundTripper: &requestid.Transport{
+				RoundTripper: &http.Transport{
+					// Default is 2, but we can send many concurrent requests
+					MaxIdleConnsPerHost: 500,
+				},
 			},
 		},
 	},
@@ -20,6 +20,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/repotrackutil"
+	"github.com/sourcegraph/sourcegraph/internal/requestid"
 	"github.com/sourcegraph/sourcegraph/internal/sentry"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 )
@@ -140,6 +141,8 @@ func HTTPTraceMiddleware(next http.Handler) http.Handler {
 		ext.HTTPUrl.Set(span, r.URL.String())
 		ext.HTTPMethod.Set(span, r.Method)
 		span.SetTag("http.referer", r.Header.Get("referer"))
+		requestID := requestid.FromContext(ctx)
+		span.SetTag("request_id", requestID)
 		defer span.Finish()
 		rw.Header().Set("X-Trace", SpanURL(span))
 		ctx = opentracing.ContextWithSpan(ctx, span)
@@ -199,6 +202,7 @@ func HTTPTraceMiddleware(next http.Handler) http.Handler {
 			"url", r.URL.String(),
 			"route_name", routeName,
 			"trace", SpanURL(span),
+			"request_id", requestID,
 			"user_agent", r.UserAgent(),
 			"user", userID,
 			"x_forwarded_for", r.Header.Get("X-Forwarded-For"),
@@ -219,6 +223,7 @@ func HTTPTraceMiddleware(next http.Handler) http.Handler {
 				"method":          r.Method,
 				"url":             r.URL.String(),
 				"route_name":      routeName,
+				"request_id":      requestID,
 				"user_agent":      r.UserAgent(),
 				"user":            strconv.FormatInt(int64(userID), 10),
 				"x_forwarded_for": r.Header.Get("X-Forwarded-For"),
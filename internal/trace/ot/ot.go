@@ -13,6 +13,8 @@ import (
 	"github.com/opentracing-contrib/go-stdlib/nethttp"
 	"github.com/opentracing/opentracing-go"
 	"go.uber.org/atomic"
+
+	"github.com/sourcegraph/sourcegraph/internal/requestid"
 )
 
 type tracePolicy string
@@ -43,6 +45,7 @@ func GetTracePolicy() tracePolicy {
 //
 // - If the HTTP header, X-Sourcegraph-Should-Trace, is set to a truthy value, set the
 //   shouldTraceKey context.Context value to true
+// - requestid.Middleware, which stores the request ID in the context.Context
 // - github.com/opentracing-contrib/go-stdlib/nethttp.Middleware, which creates a new span to track
 //   the request handler from the global tracer.
 func Middleware(h http.Handler, opts ...nethttp.MWOption) http.Handler {
@@ -57,7 +60,7 @@ func MiddlewareWithTracer(tr opentracing.Tracer, h http.Handler, opts ...nethttp
 			return ShouldTrace(r.Context())
 		}),
 	}, opts...)...)
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	return requestid.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var trace bool
 		switch GetTracePolicy() {
 		case TraceSelective:
@@ -68,7 +71,7 @@ func MiddlewareWithTracer(tr opentracing.Tracer, h http.Handler, opts ...nethttp
 			trace = false
 		}
 		nethttpMiddleware.ServeHTTP(w, r.WithContext(WithShouldTrace(r.Context(), trace)))
-	})
+	}))
 }
 
 const traceHeader = "X-Sourcegraph-Should-Trace"
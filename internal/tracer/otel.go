@@ -0,0 +1,114 @@
+package tracer
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+	"github.com/opentracing/opentracing-go"
+	otelbridge "go.opentelemetry.io/otel/bridge/opentracing"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// otelShutdownTimeout is the time we wait for buffered spans to be exported when the
+// OpenTelemetry tracer is replaced.
+const otelShutdownTimeout = 5 * time.Second
+
+// newOTelTracer returns an opentracing.Tracer that records spans with the OpenTelemetry SDK
+// and exports them with OTLP over HTTP. Trace context is propagated in W3C traceparent headers.
+func newOTelTracer(opts *tracerOpts) (opentracing.Tracer, func(span opentracing.Span) string, io.Closer, error) {
+	log15.Info("opentracing: OpenTelemetry enabled", "endpoint", opts.Endpoint)
+
+	var urlTemplate *template.Template
+	if opts.URLTemplate != "" {
+		var err error
+		urlTemplate, err = template.New("").Parse(opts.URLTemplate)
+		if err != nil {
+			return nil, nil, nil, errors.Wrap(err, "invalid observability.tracing.urlTemplate")
+		}
+	}
+
+	// Without an endpoint, the exporter reads the standard OTEL_EXPORTER_OTLP_* environment variables.
+	var exporterOpts []otlptracehttp.Option
+	if opts.Endpoint != "" {
+		u, err := url.Parse(opts.Endpoint)
+		if err != nil {
+			return nil, nil, nil, errors.Wrap(err, "invalid observability.tracing.endpoint")
+		}
+		exporterOpts = append(exporterOpts,
+			otlptracehttp.WithEndpoint(u.Host),
+			otlptracehttp.WithURLPath(path.Join("/", u.Path, "v1/traces")),
+		)
+		if u.Scheme == "http" {
+			exporterOpts = append(exporterOpts, otlptracehttp.WithInsecure())
+		}
+	}
+	exporter, err := otlptracehttp.New(context.Background(), exporterOpts...)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "otlptracehttp.New failed")
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceNameKey.String(opts.ServiceName),
+		)),
+	)
+
+	// The bridge lets all code instrumented with OpenTracing (including observation.Operation)
+	// record OpenTelemetry spans. Tags become span attributes and log fields become span events.
+	tracer, _ := otelbridge.NewTracerPair(provider.Tracer("github.com/sourcegraph/sourcegraph/internal/tracer"))
+	tracer.SetTextMapPropagator(propagation.TraceContext{})
+
+	spanURL := func(span opentracing.Span) string {
+		if span == nil || urlTemplate == nil {
+			return tracingNotEnabledURL
+		}
+		traceID, ok := otelTraceID(span)
+		if !ok {
+			return tracingNotEnabledURL
+		}
+
+		var b strings.Builder
+		if err := urlTemplate.Execute(&b, struct{ TraceID string }{TraceID: traceID.String()}); err != nil {
+			return tracingNotEnabledURL
+		}
+		return b.String()
+	}
+
+	return tracer, spanURL, closerFunc(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), otelShutdownTimeout)
+		defer cancel()
+		return provider.Shutdown(ctx)
+	}), nil
+}
+
+// otelTraceID returns the ID of the OpenTelemetry trace the given span belongs to. The bridge
+// does not expose the OpenTelemetry span context of its spans, so we read it back from the
+// traceparent header the span context propagates as.
+func otelTraceID(span opentracing.Span) (oteltrace.TraceID, bool) {
+	carrier := opentracing.HTTPHeadersCarrier{}
+	if err := span.Tracer().Inject(span.Context(), opentracing.HTTPHeaders, carrier); err != nil {
+		return oteltrace.TraceID{}, false
+	}
+
+	ctx := propagation.TraceContext{}.Extract(context.Background(), propagation.HeaderCarrier(carrier))
+	spanCtx := oteltrace.SpanContextFromContext(ctx)
+	return spanCtx.TraceID(), spanCtx.IsValid()
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
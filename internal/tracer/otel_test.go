@@ -0,0 +1,166 @@
+package tracer
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/opentracing/opentracing-go"
+	otlog "github.com/opentracing/opentracing-go/log"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+)
+
+func TestOTelTracer(t *testing.T) {
+	receiver := newOTLPReceiver(t)
+
+	tracer, spanURL, closer, err := newTracer(&tracerOpts{
+		ServiceName: "test-service",
+		Enabled:     true,
+		Type:        "opentelemetry",
+		Endpoint:    receiver.URL,
+		URLTemplate: "https://traces.example.com/{{ .TraceID }}",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Record an operation that fails, with log fields passed at the start and the end.
+	operation := (&observation.Context{Tracer: &trace.Tracer{Tracer: tracer}}).Operation(observation.Op{
+		Name: "Test.DoSomething",
+	})
+	ctx := ot.WithShouldTrace(context.Background(), true)
+	opErr := errors.New("oops")
+	ctx, endObservation := operation.With(ctx, &opErr, observation.Args{LogFields: []otlog.Field{
+		otlog.String("repo", "github.com/foo/bar"),
+	}})
+	span := opentracing.SpanFromContext(ctx)
+	url := spanURL(span)
+
+	// The span context must propagate as a W3C traceparent header.
+	header := http.Header{}
+	if err := tracer.Inject(span.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(header)); err != nil {
+		t.Fatal(err)
+	}
+	traceparent := header.Get("traceparent")
+	if traceparent == "" {
+		t.Fatalf("expected traceparent header, have %v", header)
+	}
+	if _, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(header)); err != nil {
+		t.Fatalf("unexpected error extracting span context: %s", err)
+	}
+
+	endObservation(3, observation.Args{})
+
+	// Closing the tracer flushes all spans to the receiver.
+	if err := closer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	serviceNames, spans := receiver.received()
+	if len(spans) != 1 {
+		t.Fatalf("unexpected number of spans. want=%d have=%d", 1, len(spans))
+	}
+	s := spans[0]
+
+	if len(serviceNames) != 1 || serviceNames[0] != "test-service" {
+		t.Errorf("unexpected service names. want=%q have=%q", []string{"test-service"}, serviceNames)
+	}
+
+	if s.Name != "test.do-something" {
+		t.Errorf("unexpected span name. want=%q have=%q", "test.do-something", s.Name)
+	}
+	if s.Status.GetCode() != tracepb.Status_STATUS_CODE_ERROR {
+		t.Errorf("unexpected span status. want=%s have=%s", tracepb.Status_STATUS_CODE_ERROR, s.Status.GetCode())
+	}
+
+	traceID := hex.EncodeToString(s.TraceId)
+	if want := "00-" + traceID + "-"; !strings.HasPrefix(traceparent, want) {
+		t.Errorf("unexpected traceparent header. want prefix=%q have=%q", want, traceparent)
+	}
+	if want := "https://traces.example.com/" + traceID; url != want {
+		t.Errorf("unexpected span URL. want=%q have=%q", want, url)
+	}
+
+	attributes := map[string]string{}
+	for _, event := range s.Events {
+		for _, attribute := range event.Attributes {
+			attributes[attribute.Key] = attribute.Value.GetStringValue()
+		}
+	}
+	for _, key := range []string{"repo", "count", "elapsed", "error.object"} {
+		if _, ok := attributes[key]; !ok {
+			t.Errorf("expected attribute %q in span events, have %v", key, attributes)
+		}
+	}
+	if attributes["repo"] != "github.com/foo/bar" {
+		t.Errorf("unexpected repo attribute. want=%q have=%q", "github.com/foo/bar", attributes["repo"])
+	}
+}
+
+// otlpReceiver is an in-process OTLP/HTTP trace receiver.
+type otlpReceiver struct {
+	*httptest.Server
+	requests chan *coltracepb.ExportTraceServiceRequest
+}
+
+func newOTLPReceiver(t *testing.T) *otlpReceiver {
+	r := &otlpReceiver{requests: make(chan *coltracepb.ExportTraceServiceRequest, 100)}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/v1/traces" {
+			t.Errorf("unexpected request path %q", req.URL.Path)
+			http.NotFound(w, req)
+			return
+		}
+
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var exportReq coltracepb.ExportTraceServiceRequest
+		if err := proto.Unmarshal(body, &exportReq); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		r.requests <- &exportReq
+
+		resp, _ := proto.Marshal(&coltracepb.ExportTraceServiceResponse{})
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		_, _ = w.Write(resp)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+// received returns the service names of the resources and the spans of all requests received
+// so far.
+func (r *otlpReceiver) received() (serviceNames []string, spans []*tracepb.Span) {
+	for {
+		select {
+		case req := <-r.requests:
+			for _, rs := range req.ResourceSpans {
+				for _, attribute := range rs.Resource.GetAttributes() {
+					if attribute.Key == "service.name" {
+						serviceNames = append(serviceNames, attribute.Value.GetStringValue())
+					}
+				}
+				for _, ils := range rs.InstrumentationLibrarySpans {
+					spans = append(spans, ils.Spans...)
+				}
+			}
+		default:
+			return serviceNames, spans
+		}
+	}
+}
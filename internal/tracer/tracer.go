@@ -1,4 +1,4 @@
-// Package tracer initializes distributed tracing and log15 behavior. It also updates distributed
+// Package tracer initializes distributed tracing (with Jaeger or OpenTelemetry) and log15 behavior. It also updates distributed
 // tracing behavior in response to changes in site configuration. When the Init function of this
 // package is invoked, opentracing.SetGlobalTracer is called (and subsequently called again after
 // every Sourcegraph site configuration change). Importing programs should not invoke
@@ -67,11 +67,17 @@ func Init(options ...Option) {
 	initTracer(opts.serviceName)
 }
 
-type jaegerOpts struct {
+type tracerOpts struct {
 	ServiceName string
 	ExternalURL string
 	Enabled     bool
 	Debug       bool
+
+	// Type is the value of observability.tracing.type, and Endpoint and URLTemplate are only
+	// used by the "opentelemetry" type.
+	Type        string
+	Endpoint    string
+	URLTemplate string
 }
 
 // initTracer is a helper that should be called exactly once (from Init).
@@ -84,7 +90,7 @@ func initTracer(serviceName string) {
 	initial := true
 
 	// Initially everything is disabled since we haven't read conf yet.
-	oldOpts := jaegerOpts{
+	oldOpts := tracerOpts{
 		ServiceName: serviceName,
 		Enabled:     false,
 		Debug:       false,
@@ -97,6 +103,7 @@ func initTracer(serviceName string) {
 		// Set sampling strategy
 		samplingStrategy := ot.TraceNone
 		shouldLog := false
+		var tracerType, endpoint, urlTemplate string
 		if tracingConfig := siteConfig.ObservabilityTracing; tracingConfig != nil {
 			switch tracingConfig.Sampling {
 			case "all":
@@ -105,6 +112,9 @@ func initTracer(serviceName string) {
 				samplingStrategy = ot.TraceSelective
 			}
 			shouldLog = tracingConfig.Debug
+			tracerType = tracingConfig.Type
+			endpoint = tracingConfig.Endpoint
+			urlTemplate = tracingConfig.UrlTemplate
 		} else if siteConfig.UseJaeger {
 			samplingStrategy = ot.TraceAll
 		}
@@ -114,11 +124,14 @@ func initTracer(serviceName string) {
 		initial = false
 		ot.SetTracePolicy(samplingStrategy)
 
-		opts := jaegerOpts{
+		opts := tracerOpts{
 			ServiceName: serviceName,
 			ExternalURL: siteConfig.ExternalURL,
 			Enabled:     samplingStrategy == ot.TraceAll || samplingStrategy == ot.TraceSelective,
 			Debug:       shouldLog,
+			Type:        tracerType,
+			Endpoint:    endpoint,
+			URLTemplate: urlTemplate,
 		}
 
 		if opts == oldOpts {
@@ -130,7 +143,7 @@ func initTracer(serviceName string) {
 
 		tracer, urlFunc, closer, err := newTracer(&opts)
 		if err != nil {
-			log15.Warn("Could not initialize jaeger tracer", "error", err.Error())
+			log15.Warn("Could not initialize tracer", "type", opts.Type, "error", err.Error())
 			return
 		}
 
@@ -139,12 +152,19 @@ func initTracer(serviceName string) {
 	})
 }
 
-func newTracer(opts *jaegerOpts) (opentracing.Tracer, func(span opentracing.Span) string, io.Closer, error) {
+func newTracer(opts *tracerOpts) (opentracing.Tracer, func(span opentracing.Span) string, io.Closer, error) {
 	if !opts.Enabled {
-		log15.Info("opentracing: Jaeger disabled")
+		log15.Info("opentracing: tracing disabled")
 		return opentracing.NoopTracer{}, nil, nil, nil
 	}
 
+	if opts.Type == "opentelemetry" {
+		return newOTelTracer(opts)
+	}
+	return newJaegerTracer(opts)
+}
+
+func newJaegerTracer(opts *tracerOpts) (opentracing.Tracer, func(span opentracing.Span) string, io.Closer, error) {
 	log15.Info("opentracing: Jaeger enabled")
 	cfg, err := jaegercfg.FromEnv()
 	cfg.ServiceName = opts.ServiceName
@@ -27,3 +27,9 @@ type PerforceConnection struct {
 	URN string
 	*schema.PerforceConnection
 }
+
+type AzureDevOpsConnection struct {
+	// The unique resource identifier of the external service.
+	URN string
+	*schema.AzureDevOpsConnection
+}
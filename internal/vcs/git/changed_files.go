@@ -0,0 +1,72 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+)
+
+// ChangedFile is a file which differs between two commits.
+type ChangedFile struct {
+	Path string
+
+	// Deleted is true if the file exists in the base commit but not in the
+	// head commit. Renamed files are reported as a deleted file and an added
+	// file.
+	Deleted bool
+}
+
+// ChangedFiles returns the files which differ between the base and head
+// commits.
+func ChangedFiles(ctx context.Context, repo api.RepoName, base, head api.CommitID) ([]ChangedFile, error) {
+	if Mocks.ChangedFiles != nil {
+		return Mocks.ChangedFiles(repo, base, head)
+	}
+	span, ctx := ot.StartSpanFromContext(ctx, "Git: ChangedFiles")
+	span.SetTag("Base", base)
+	span.SetTag("Head", head)
+	defer span.Finish()
+
+	if err := checkSpecArgSafety(string(base)); err != nil {
+		return nil, err
+	}
+	if err := checkSpecArgSafety(string(head)); err != nil {
+		return nil, err
+	}
+
+	cmd := gitserver.DefaultClient.Command("git", "diff", "--name-status", "--no-renames", "-z", string(base), string(head), "--")
+	cmd.Repo = repo
+	out, err := cmd.CombinedOutput(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("git command %v failed (output: %q)", cmd.Args, out))
+	}
+	return parseChangedFiles(out)
+}
+
+// parseChangedFiles parses the output of `git diff --name-status -z
+// --no-renames`, which is a sequence of NUL-terminated status and path
+// pairs.
+func parseChangedFiles(out []byte) ([]ChangedFile, error) {
+	if len(out) == 0 {
+		return nil, nil
+	}
+	fields := bytes.Split(bytes.TrimSuffix(out, []byte{0}), []byte{0})
+	if len(fields)%2 != 0 {
+		return nil, errors.Errorf("unexpected git diff --name-status output: %q", out)
+	}
+
+	files := make([]ChangedFile, 0, len(fields)/2)
+	for i := 0; i < len(fields); i += 2 {
+		files = append(files, ChangedFile{
+			Path:    string(fields[i+1]),
+			Deleted: string(fields[i]) == "D",
+		})
+	}
+	return files, nil
+}
@@ -0,0 +1,59 @@
+package git
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestChangedFiles(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	repo := MakeGitRepository(t,
+		"echo a > a",
+		"echo b > b",
+		"echo c > c",
+		"git add a b c",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m foo --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"git tag base",
+		"echo a2 >> a",
+		"git rm b",
+		"mkdir dir",
+		"git mv c 'dir/new c'",
+		"git add a",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m bar --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+	)
+
+	base, err := ResolveRevision(ctx, repo, "base", ResolveRevisionOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := ResolveRevision(ctx, repo, "HEAD", ResolveRevisionOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ChangedFiles(ctx, repo, base, head)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ChangedFile{
+		{Path: "a"},
+		{Path: "b", Deleted: true},
+		{Path: "c", Deleted: true},
+		{Path: "dir/new c"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected changed files (-want +got):\n%s", diff)
+	}
+
+	got, err = ChangedFiles(ctx, repo, head, head)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("got %v, want no changed files", got)
+	}
+}
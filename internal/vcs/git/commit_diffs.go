@@ -0,0 +1,87 @@
+package git
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+)
+
+// CommitWithDiff is a commit along with the patch it introduces.
+type CommitWithDiff struct {
+	*Commit
+
+	// Diff is the patch of the commit as printed by `git log --patch
+	// --no-prefix`. It is nil if the commit changes no files.
+	Diff *RawDiff
+}
+
+// CommitsWithDiffs returns the non-merge commits matching the options, along
+// with their patches. Only the Range, N, Skip and NoEnsureRevision options are
+// supported.
+func CommitsWithDiffs(ctx context.Context, repo api.RepoName, opt CommitsOptions) ([]*CommitWithDiff, error) {
+	if Mocks.CommitsWithDiffs != nil {
+		return Mocks.CommitsWithDiffs(repo, opt)
+	}
+
+	span, ctx := ot.StartSpanFromContext(ctx, "Git: CommitsWithDiffs")
+	span.SetTag("Opt", opt)
+	defer span.Finish()
+
+	if opt.MessageQuery != "" || opt.Author != "" || opt.After != "" || opt.Before != "" || opt.Reverse || opt.DateOrder || opt.Path != "" {
+		return nil, errors.New("CommitsWithDiffs only supports the Range, N, Skip and NoEnsureRevision options")
+	}
+
+	args, err := commitLogArgs([]string{"log", "--no-merges", "-z", "--patch", "--no-prefix", logFormatWithoutRefs}, CommitsOptions{
+		Range: opt.Range,
+		N:     opt.N,
+		Skip:  opt.Skip,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := gitserver.DefaultClient.Command("git", args...)
+	cmd.Repo = repo
+	if !opt.NoEnsureRevision {
+		cmd.EnsureRevision = opt.Range
+	}
+	data, stderr, err := cmd.DividedOutput(ctx)
+	if err != nil {
+		if isBadObjectErr(string(stderr), opt.Range) {
+			return nil, &gitserver.RevisionNotFoundError{Repo: repo, Spec: opt.Range}
+		}
+		return nil, errors.WithMessage(err, fmt.Sprintf("git command %v failed (stderr: %q)", cmd.Args, stderr))
+	}
+	return parseCommitsWithDiffs(data)
+}
+
+// parseCommitsWithDiffs parses the output of `git log -z --patch` formatted
+// with logFormatWithoutRefs.
+func parseCommitsWithDiffs(data []byte) ([]*CommitWithDiff, error) {
+	var commits []*CommitWithDiff
+	for len(data) > 0 {
+		commit, _, rest, err := parseCommitFromLog(data)
+		if err != nil {
+			return nil, err
+		}
+		data = rest
+
+		c := &CommitWithDiff{Commit: commit}
+		switch {
+		case len(data) > 0 && data[0] == '\x00':
+			// No diff patch.
+			data = data[1:]
+		case len(data) > 0 && data[0] == '\n':
+			var rawDiff []byte
+			rawDiff, data = splitPatchFromLog(data)
+			c.Diff = &RawDiff{Raw: string(rawDiff)}
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
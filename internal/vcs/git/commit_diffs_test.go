@@ -0,0 +1,73 @@
+package git
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRepository_CommitsWithDiffs(t *testing.T) {
+	t.Parallel()
+
+	repo := MakeGitRepository(t,
+		"echo root > f",
+		"git add f",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m root --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:06Z git commit --allow-empty -m empty --author='a <a@a.com>' --date 2006-01-02T15:04:06Z",
+		"echo second > f",
+		"git add f",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:07Z git commit -m 'second line' --author='a <a@a.com>' --date 2006-01-02T15:04:07Z",
+	)
+
+	rootCommit := &CommitWithDiff{
+		Commit: &Commit{
+			ID:        "ce72ece27fd5c8180cfbc1c412021d32fd1cda0d",
+			Author:    Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
+			Committer: &Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
+			Message:   "root",
+		},
+		Diff: &RawDiff{Raw: "diff --git f f\nnew file mode 100644\nindex 0000000..d8649da\n--- /dev/null\n+++ f\n@@ -0,0 +1 @@\n+root\n"},
+	}
+
+	all, err := CommitsWithDiffs(context.Background(), repo, CommitsOptions{Range: "HEAD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("got %d commits, want 3", len(all))
+	}
+	if got, want := string(all[0].Message), "second line"; got != want {
+		t.Errorf("got message %q, want %q", got, want)
+	}
+	if got, want := all[0].Diff.Raw, "diff --git f f\nindex d8649da..e019be0 100644\n--- f\n+++ f\n@@ -1 +1 @@\n-root\n+second\n"; got != want {
+		t.Errorf("got diff %q, want %q", got, want)
+	}
+	if all[1].Diff != nil {
+		t.Errorf("got diff %q for empty commit, want none", all[1].Diff.Raw)
+	}
+	if all[1].Parents[0] != rootCommit.ID {
+		t.Errorf("got parent %q, want %q", all[1].Parents[0], rootCommit.ID)
+	}
+	if diff := cmp.Diff(rootCommit, all[2]); diff != "" {
+		t.Errorf("unexpected root commit (-want +got):\n%s", diff)
+	}
+
+	paged, err := CommitsWithDiffs(context.Background(), repo, CommitsOptions{Range: "HEAD", N: 1, Skip: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]*CommitWithDiff{rootCommit}, paged); diff != "" {
+		t.Errorf("unexpected paged commits (-want +got):\n%s", diff)
+	}
+
+	if _, err := CommitsWithDiffs(context.Background(), repo, CommitsOptions{Range: "HEAD", Author: "a"}); err == nil {
+		t.Error("got no error for unsupported option")
+	}
+
+	_, err = CommitsWithDiffs(context.Background(), repo, CommitsOptions{Range: "doesnotexist", NoEnsureRevision: true})
+	if err == nil {
+		t.Error("got no error for missing revision")
+	}
+}
@@ -326,6 +326,34 @@ func FirstEverCommit(ctx context.Context, repo api.RepoName) (*Commit, error) {
 	return GetCommit(ctx, repo, id, ResolveRevisionOptions{NoEnsureRevision: true})
 }
 
+// FindCommitAtTime finds the most recent commit in the given repository revSpec
+// (e.g. `HEAD` or `mybranch`) that was committed on or before the target time.
+//
+// Returns nil, nil if revSpec has no commit on or before the target time.
+func FindCommitAtTime(ctx context.Context, repoName api.RepoName, revSpec string, target time.Time) (*Commit, error) {
+	if revSpec == "" {
+		revSpec = "HEAD"
+	}
+	branchCommit, err := ResolveRevision(ctx, repoName, revSpec, ResolveRevisionOptions{NoEnsureRevision: true})
+	if err != nil {
+		return nil, err
+	}
+
+	commits, err := Commits(ctx, repoName, CommitsOptions{
+		N:         1,
+		Before:    target.Format(time.RFC3339),
+		Range:     string(branchCommit),
+		DateOrder: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) == 0 {
+		return nil, nil
+	}
+	return commits[0], nil
+}
+
 // FindNearestCommit finds the commit in the given repository revSpec (e.g. `HEAD` or `mybranch`)
 // whose author date most closely matches the target time.
 //
@@ -138,6 +138,41 @@ func filterAndHighlightDiff(rawDiff []byte, query *regexp.Regexp, onlyMatchingHu
 	return rawDiff, highlights, nil
 }
 
+// diffHasChangedLineMatch reports whether query matches an added or removed
+// line of a file in rawDiff whose name matches pathMatcher. This mirrors the
+// commit selection of `git log -G`.
+func diffHasChangedLineMatch(rawDiff []byte, query *regexp.Regexp, pathMatcher pathmatch.PathMatcher) (_ bool, err error) {
+	defer func() {
+		if panicValue := recover(); panicValue != nil {
+			err = errors.Errorf("diffHasChangedLineMatch panic: %v", panicValue)
+		}
+	}()
+
+	dr := diff.NewMultiFileDiffReader(bytes.NewReader(rawDiff))
+	for {
+		fileDiff, err := dr.ReadFile()
+		if err == io.EOF {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+
+		origNameMatches := fileDiff.OrigName != "/dev/null" && pathMatcher.MatchPath(fileDiff.OrigName)
+		newNameMatches := fileDiff.NewName != "/dev/null" && pathMatcher.MatchPath(fileDiff.NewName)
+		if !origNameMatches && !newNameMatches {
+			continue
+		}
+
+		for _, hunk := range fileDiff.Hunks {
+			for _, line := range bytes.Split(hunk.Body, []byte("\n")) {
+				if len(line) > 0 && (line[0] == '+' || line[0] == '-') && query.Match(line[1:]) {
+					return true, nil
+				}
+			}
+		}
+	}
+}
+
 func truncateLongLines(data []byte, maxCharsPerLine int) []byte {
 	// We reuse data's storage to avoid allocation.
 
@@ -335,19 +335,9 @@ func rawShowSearch(ctx context.Context, repo api.RepoName, opt RawLogDiffSearchO
 	// Even though we've already searched using the query, we need to
 	// search the returned diff again to filter to only matching hunks
 	// and to highlight matches.
-	var query *regexp.Regexp
-	if pattern := opt.Query.Pattern; pattern != "" {
-		if !opt.Query.IsRegExp {
-			pattern = regexp.QuoteMeta(pattern)
-		}
-		if !opt.Query.IsCaseSensitive {
-			pattern = "(?i:" + pattern + ")"
-		}
-		var err error
-		query, err = regexp.Compile(pattern)
-		if err != nil {
-			return nil, false, err
-		}
+	query, err := compileTextSearchQuery(opt.Query)
+	if err != nil {
+		return nil, false, err
 	}
 
 	pathMatcher, err := compilePathMatcher(opt.Paths)
@@ -426,19 +416,8 @@ func rawShowSearch(ctx context.Context, repo api.RepoName, opt RawLogDiffSearchO
 				hasMatch = false // patch was empty for the filtered paths, don't add to results
 			}
 		} else if len(data) >= 1 && data[0] == '\n' {
-			data = data[1:]
-
-			// Next is the diff patch.
-			patchEnd := bytes.Index(data, []byte("\n\x00"))
 			var rawDiff []byte
-			if patchEnd != -1 {
-				rawDiff = data[:patchEnd+1]
-				data = data[patchEnd+2:]
-			} else {
-				// Rest of data is the diff patch.
-				rawDiff = data
-				data = nil
-			}
+			rawDiff, data = splitPatchFromLog(data)
 
 			var err error
 			rawDiff, result.DiffHighlights, err = filterAndHighlightDiff(rawDiff, query, opt.OnlyMatchingHunks, pathMatcher)
@@ -466,6 +445,68 @@ func rawShowSearch(ctx context.Context, repo api.RepoName, opt RawLogDiffSearchO
 	return results, complete, nil
 }
 
+// compileTextSearchQuery compiles the query to the regexp used to filter and
+// highlight diff hunks. It returns nil if the pattern is empty.
+func compileTextSearchQuery(opt TextSearchOptions) (*regexp.Regexp, error) {
+	pattern := opt.Pattern
+	if pattern == "" {
+		return nil, nil
+	}
+	if !opt.IsRegExp {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if !opt.IsCaseSensitive {
+		pattern = "(?i:" + pattern + ")"
+	}
+	return regexp.Compile(pattern)
+}
+
+// splitPatchFromLog splits the patch of a commit from the rest of the output
+// of `git log -z --patch` or `git show -z --patch`. data must start right
+// after the fields parsed by parseCommitFromLog and begin with the newline
+// preceding the patch.
+func splitPatchFromLog(data []byte) (rawDiff, rest []byte) {
+	data = data[1:]
+	patchEnd := bytes.Index(data, []byte("\n\x00"))
+	if patchEnd == -1 {
+		// Rest of data is the diff patch.
+		return data, nil
+	}
+	return data[:patchEnd+1], data[patchEnd+2:]
+}
+
+// FilterRawDiff applies the filtering that RawLogDiffSearch performs on the
+// patch of a commit matched by `git log` to rawDiff, a patch as printed by
+// `git log --patch --no-prefix`. It is used to search diffs which were fetched
+// earlier.
+//
+// Like `git log -G`, the diff only matches if the query matches an added or
+// removed line in a file matching opt.Paths. FilterRawDiff returns a nil diff
+// if it does not match.
+func FilterRawDiff(rawDiff []byte, opt RawLogDiffSearchOptions) (*RawDiff, []Highlight, error) {
+	query, err := compileTextSearchQuery(opt.Query)
+	if err != nil {
+		return nil, nil, err
+	}
+	pathMatcher, err := compilePathMatcher(opt.Paths)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if query != nil {
+		ok, err := diffHasChangedLineMatch(rawDiff, query, pathMatcher)
+		if err != nil || !ok {
+			return nil, nil, err
+		}
+	}
+
+	filtered, highlights, err := filterAndHighlightDiff(rawDiff, query, opt.OnlyMatchingHunks, pathMatcher)
+	if err != nil || filtered == nil {
+		return nil, nil, err
+	}
+	return &RawDiff{Raw: string(filtered)}, highlights, nil
+}
+
 func logDiffCommonArgs(opt RawLogDiffSearchOptions) []string {
 	var args []string
 	if opt.Query.Pattern != "" && opt.Diff {
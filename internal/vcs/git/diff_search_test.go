@@ -231,3 +231,59 @@ func TestRepository_RawLogDiffSearch_empty(t *testing.T) {
 		}
 	}
 }
+
+func TestFilterRawDiff(t *testing.T) {
+	const rawDiff = "diff --git a.go a.go\nindex d8649da..1193ff4 100644\n--- a.go\n+++ a.go\n@@ -1,2 +1,2 @@\n context\n-Foo\n+bar\n" +
+		"diff --git b.txt b.txt\nnew file mode 100644\nindex 0000000..d8649da\n--- /dev/null\n+++ b.txt\n@@ -0,0 +1,1 @@\n+foo\n"
+
+	tests := map[string]struct {
+		opt            RawLogDiffSearchOptions
+		want           string
+		wantHighlights []Highlight
+	}{
+		"case insensitive match in two files": {
+			opt: RawLogDiffSearchOptions{
+				Query:             TextSearchOptions{Pattern: "foo"},
+				OnlyMatchingHunks: true,
+			},
+			want:           rawDiff,
+			wantHighlights: []Highlight{{Line: 7, Character: 1, Length: 3}, {Line: 15, Character: 1, Length: 3}},
+		},
+		"case sensitive match restricted by path": {
+			opt: RawLogDiffSearchOptions{
+				Query:             TextSearchOptions{Pattern: "foo", IsCaseSensitive: true},
+				Paths:             PathOptions{IncludePatterns: []string{`\.go$`}, IsRegExp: true},
+				OnlyMatchingHunks: true,
+			},
+		},
+		"only context line matches": {
+			opt: RawLogDiffSearchOptions{
+				Query: TextSearchOptions{Pattern: "context"},
+			},
+		},
+		"no query": {
+			opt: RawLogDiffSearchOptions{
+				Paths: PathOptions{IncludePatterns: []string{`\.txt$`}, IsRegExp: true},
+			},
+			want: "diff --git b.txt b.txt\nnew file mode 100644\nindex 0000000..d8649da\n--- /dev/null\n+++ b.txt\n@@ -0,0 +1,1 @@\n+foo\n",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			diff, highlights, err := FilterRawDiff([]byte(rawDiff), test.opt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got string
+			if diff != nil {
+				got = diff.Raw
+			}
+			if got != test.want {
+				t.Errorf("got diff %q, want %q", got, test.want)
+			}
+			if d := cmp.Diff(test.wantHighlights, highlights); d != "" {
+				t.Errorf("unexpected highlights (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -24,7 +24,9 @@ var Mocks, emptyMocks struct {
 	Stat             func(commit api.CommitID, name string) (fs.FileInfo, error)
 	GetObject        func(objectName string) (OID, ObjectType, error)
 	Commits          func(repo api.RepoName, opt CommitsOptions) ([]*Commit, error)
+	CommitsWithDiffs func(repo api.RepoName, opt CommitsOptions) ([]*CommitWithDiff, error)
 	MergeBase        func(repo api.RepoName, a, b api.CommitID) (api.CommitID, error)
+	ChangedFiles     func(repo api.RepoName, base, head api.CommitID) ([]ChangedFile, error)
 }
 
 // ResetMocks clears the mock functions set on Mocks (so that subsequent tests don't inadvertently
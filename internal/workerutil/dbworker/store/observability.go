@@ -8,14 +8,17 @@ import (
 )
 
 type operations struct {
-	queuedCount          *observation.Operation
-	dequeue              *observation.Operation
-	requeue              *observation.Operation
-	addExecutionLogEntry *observation.Operation
-	markComplete         *observation.Operation
-	markErrored          *observation.Operation
-	markFailed           *observation.Operation
-	resetStalled         *observation.Operation
+	queuedCount             *observation.Operation
+	dequeue                 *observation.Operation
+	requeue                 *observation.Operation
+	addExecutionLogEntry    *observation.Operation
+	updateExecutionLogEntry *observation.Operation
+	markComplete            *observation.Operation
+	markErrored             *observation.Operation
+	markFailed              *observation.Operation
+	cancel                  *observation.Operation
+	canceledIDs             *observation.Operation
+	resetStalled            *observation.Operation
 }
 
 func newOperations(storeName string, observationContext *observation.Context) *operations {
@@ -35,13 +38,16 @@ func newOperations(storeName string, observationContext *observation.Context) *o
 	}
 
 	return &operations{
-		queuedCount:          op("QueuedCount"),
-		dequeue:              op("Dequeue"),
-		requeue:              op("Requeue"),
-		addExecutionLogEntry: op("AddExecutionLogEntry"),
-		markComplete:         op("MarkComplete"),
-		markErrored:          op("MarkErrored"),
-		markFailed:           op("MarkFailed"),
-		resetStalled:         op("ResetStalled"),
+		queuedCount:             op("QueuedCount"),
+		dequeue:                 op("Dequeue"),
+		requeue:                 op("Requeue"),
+		addExecutionLogEntry:    op("AddExecutionLogEntry"),
+		updateExecutionLogEntry: op("UpdateExecutionLogEntry"),
+		markComplete:            op("MarkComplete"),
+		markErrored:             op("MarkErrored"),
+		markFailed:              op("MarkFailed"),
+		cancel:                  op("Cancel"),
+		canceledIDs:             op("CanceledIDs"),
+		resetStalled:            op("ResetStalled"),
 	}
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/derision-test/glock"
 	"github.com/inconshreveable/log15"
 	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
 	"github.com/opentracing/opentracing-go/log"
 
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
@@ -43,8 +44,13 @@ type Store interface {
 	// the next dequeue of this record can be performed.
 	Requeue(ctx context.Context, id int, after time.Time) error
 
-	// AddExecutionLogEntry adds an executor log entry to the record.
-	AddExecutionLogEntry(ctx context.Context, id int, entry workerutil.ExecutionLogEntry) error
+	// AddExecutionLogEntry adds an executor log entry to the record and returns the identifier of the new entry.
+	AddExecutionLogEntry(ctx context.Context, id int, entry workerutil.ExecutionLogEntry) (int, error)
+
+	// UpdateExecutionLogEntry appends the output of the given entry to an existing executor log entry of the
+	// processing record, and sets its exit code and duration if they are given. This is used to stream the output
+	// of a command that is still running. The other fields of the given entry are ignored.
+	UpdateExecutionLogEntry(ctx context.Context, id, entryID int, entry workerutil.ExecutionLogEntry) error
 
 	// MarkComplete attempts to update the state of the record to complete. If this record has already been moved from
 	// the processing state to a terminal state, this method will have no effect. This method returns a boolean flag
@@ -61,6 +67,14 @@ type Store interface {
 	// with an error will not be updated. This method returns a boolean flag indicating if the record was updated.
 	MarkFailed(ctx context.Context, id int, failureMessage string) (bool, error)
 
+	// Cancel attempts to update the state of the record to canceled. This method will only have an effect if
+	// the record is queued, processing, or errored (and may be retried). The worker processing a canceled record
+	// is notified through CanceledIDs. This method returns a boolean flag indicating if the record was updated.
+	Cancel(ctx context.Context, id int) (bool, error)
+
+	// CanceledIDs returns the subset of the given record identifiers that are in the canceled state.
+	CanceledIDs(ctx context.Context, ids []int) ([]int, error)
+
 	// ResetStalled moves all processing records that have not received a heartbeat within `StalledMaxAge` back to the
 	// queued state. In order to prevent input that continually crashes worker instances, records that have been reset
 	// more than `MaxNumResets` times will be marked as errored. This method returns a list of record identifiers that
@@ -112,7 +126,7 @@ type Options struct {
 	// and types:
 	//
 	//   - id: integer primary key
-	//   - state: text (may be updated to `queued`, `processing`, `errored`, or `failed`)
+	//   - state: text (may be updated to `queued`, `processing`, `errored`, `failed`, or `canceled`)
 	//   - failure_message: text
 	//   - started_at: timestamp with time zone
 	//   - last_heartbeat_at: timestamp with time zone
@@ -157,6 +171,30 @@ type Options struct {
 	// supplied.
 	OrderByExpression *sqlf.Query
 
+	// PriorityExpression is an optional SQL expression giving the priority class of a candidate record.
+	// Candidate records are ordered by this expression in descending order before any other ordering is
+	// applied, so records of a higher priority class are always selected first. This expression may use
+	// the alias provided in `ViewName`, if one was supplied.
+	PriorityExpression *sqlf.Query
+
+	// FairnessKeyExpression is an optional SQL expression partitioning candidate records into groups that
+	// should be scheduled fairly (e.g. a repository or user identifier). When supplied, records of the
+	// group with the fewest records currently being processed are selected first. Ties are broken by the
+	// group whose most recent record was dequeued the longest ago, whether or not that record is still
+	// being processed, which round-robins between groups with the same load even when records are
+	// processed one at a time. Groups without a record dequeued within the last `fairnessWindow` come
+	// first. `OrderByExpression` then orders the records within a group. This prevents a single group
+	// with a large number of queued records from starving the others. This expression may use the alias
+	// provided in `ViewName`, if one was supplied.
+	FairnessKeyExpression *sqlf.Query
+
+	// MaxConcurrencyPerKey is the maximum number of records with the same fairness key that may be in
+	// the processing state at once. Records of a group at this limit are not selected until one of the
+	// group's records leaves the processing state. Concurrent dequeues may briefly exceed the limit.
+	// Setting this value to zero disables the limit. This value is ignored if `FairnessKeyExpression`
+	// is not supplied.
+	MaxConcurrencyPerKey int
+
 	// ColumnExpressions are the target columns provided to the query when selecting a job record. These
 	// expressions may use the alias provided in `ViewName`, if one was supplied.
 	ColumnExpressions []*sqlf.Query
@@ -317,17 +355,22 @@ func (s *store) Dequeue(ctx context.Context, workerHostname string, conditions [
 
 	now := s.now()
 
+	fairnessKeys, fairnessJoin, fairnessCondition, orderBy := s.makeCandidateOrdering(now)
+
 	// Select and "lock" candidate record
 	id, exists, err := basestore.ScanFirstInt(s.Query(ctx, s.formatQuery(
 		selectCandidateQuery,
+		fairnessKeys,
 		quote(s.options.ViewName),
+		fairnessJoin,
 		now,
 		int(s.options.RetryAfter/time.Second),
 		now,
 		int(s.options.RetryAfter/time.Second),
 		s.options.MaxNumRetries,
 		makeConditionSuffix(conditions),
-		s.options.OrderByExpression,
+		fairnessCondition,
+		orderBy,
 		quote(s.options.TableName),
 		now,
 		now,
@@ -381,8 +424,10 @@ func (s *store) Dequeue(ctx context.Context, workerHostname string, conditions [
 
 const selectCandidateQuery = `
 -- source: internal/workerutil/store.go:Dequeue
-WITH candidate AS (
+WITH %s
+candidate AS (
 	SELECT {id} FROM %s
+	%s
 	WHERE
 		(
 			(
@@ -396,6 +441,7 @@ WITH candidate AS (
 			)
 		)
 		%s
+		%s
 	ORDER BY %s
 	FOR UPDATE SKIP LOCKED
 	LIMIT 1
@@ -412,6 +458,63 @@ WHERE {id} IN (SELECT {id} FROM candidate)
 RETURNING {id}
 `
 
+// makeCandidateOrdering returns the common table expression holding the processing statistics of
+// each fairness key, the join of these statistics to the candidate records, the condition enforcing
+// the per-key concurrency limit, and the expression used to order candidate records in the dequeue
+// query. The first three queries are empty if no fairness key expression is configured.
+func (s *store) makeCandidateOrdering(now time.Time) (fairnessKeys, fairnessJoin, fairnessCondition, orderBy *sqlf.Query) {
+	fairnessKeys = sqlf.Sprintf("")
+	fairnessJoin = sqlf.Sprintf("")
+	fairnessCondition = sqlf.Sprintf("")
+
+	var orderByExpressions []*sqlf.Query
+	if s.options.PriorityExpression != nil {
+		orderByExpressions = append(orderByExpressions, sqlf.Sprintf("(%s) DESC", s.options.PriorityExpression))
+	}
+
+	if key := s.options.FairnessKeyExpression; key != nil {
+		fairnessKeys = s.formatQuery(fairnessKeysQuery, key, quote(s.options.ViewName), now.Add(-fairnessWindow))
+		fairnessJoin = sqlf.Sprintf(fairnessKeysJoin, key)
+
+		numProcessing := sqlf.Sprintf(fairnessKeyNumProcessingExpression)
+		if s.options.MaxConcurrencyPerKey > 0 {
+			fairnessCondition = sqlf.Sprintf("AND %s < %s", numProcessing, s.options.MaxConcurrencyPerKey)
+		}
+
+		orderByExpressions = append(orderByExpressions, numProcessing, sqlf.Sprintf(fairnessKeyLastStartedAtExpression))
+	}
+
+	orderByExpressions = append(orderByExpressions, s.options.OrderByExpression)
+	return fairnessKeys, fairnessJoin, fairnessCondition, sqlf.Join(orderByExpressions, ", ")
+}
+
+// fairnessWindow is how far back the dequeue query looks for the records last dequeued for each
+// fairness key. Keys without a record dequeued in this window are treated as never dequeued. The
+// tables of the stores configuring a fairness key should have partial indexes on {started_at} for
+// processing records and for records that have been started, so that this window can be read
+// without scanning the table.
+const fairnessWindow = time.Hour
+
+const fairnessKeysQuery = `
+fairness_keys AS (
+	SELECT
+		%s AS fairness_key,
+		COUNT(*) FILTER (WHERE {state} = 'processing') AS num_processing,
+		MAX({started_at}) AS last_started_at
+	FROM %s
+	WHERE {state} = 'processing' OR {started_at} >= %s
+	GROUP BY 1
+),
+`
+
+// fairnessKeysJoin joins the statistics of each candidate record's fairness key. Locking clauses do
+// not apply to common table expressions, so the candidate records can still be locked.
+const fairnessKeysJoin = `LEFT JOIN fairness_keys fk ON fk.fairness_key IS NOT DISTINCT FROM %s`
+
+const fairnessKeyNumProcessingExpression = `COALESCE(fk.num_processing, 0)`
+
+const fairnessKeyLastStartedAtExpression = `fk.last_started_at NULLS FIRST`
+
 const selectRecordQuery = `
 -- source: internal/workerutil/store.go:Dequeue
 SELECT %s FROM %s WHERE {id} = %s
@@ -445,22 +548,34 @@ const requeueQuery = `
 -- source: internal/workerutil/store.go:Requeue
 UPDATE %s
 SET {state} = 'queued', {process_after} = %s
-WHERE {id} = %s
+WHERE {id} = %s AND {state} != 'canceled'
 `
 
-// AddExecutionLogEntry adds an executor log entry to the record.
-func (s *store) AddExecutionLogEntry(ctx context.Context, id int, entry workerutil.ExecutionLogEntry) (err error) {
+// ErrExecutionLogEntryNotUpdated is returned by UpdateExecutionLogEntry when the record is not
+// processing or the log entry to update does not exist.
+var ErrExecutionLogEntryNotUpdated = errors.New("execution log entry not updated")
+
+// AddExecutionLogEntry adds an executor log entry to the record and returns the identifier of the new entry.
+func (s *store) AddExecutionLogEntry(ctx context.Context, id int, entry workerutil.ExecutionLogEntry) (entryID int, err error) {
 	ctx, endObservation := s.operations.addExecutionLogEntry.With(ctx, &err, observation.Args{LogFields: []log.Field{
 		log.Int("id", id),
 	}})
 	defer endObservation(1, observation.Args{})
 
-	return s.Exec(ctx, s.formatQuery(
+	entryID, ok, err := basestore.ScanFirstInt(s.Query(ctx, s.formatQuery(
 		addExecutionLogEntryQuery,
 		quote(s.options.TableName),
 		ExecutionLogEntry(entry),
 		id,
-	))
+	)))
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, errors.Errorf("unknown record %d", id)
+	}
+
+	return entryID, nil
 }
 
 const addExecutionLogEntryQuery = `
@@ -468,6 +583,67 @@ const addExecutionLogEntryQuery = `
 UPDATE %s
 SET {execution_logs} = {execution_logs} || %s::json
 WHERE {id} = %s
+RETURNING array_length({execution_logs}, 1)
+`
+
+// UpdateExecutionLogEntry appends the output of the given entry to an existing executor log entry of the
+// processing record, and sets its exit code and duration if they are given. This is used to stream the output
+// of a command that is still running. The other fields of the given entry are ignored.
+func (s *store) UpdateExecutionLogEntry(ctx context.Context, id, entryID int, entry workerutil.ExecutionLogEntry) (err error) {
+	ctx, endObservation := s.operations.updateExecutionLogEntry.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("id", id),
+		log.Int("entryID", entryID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	// The exit code and duration are only known once the command has finished, so they
+	// are only merged into the entry if they are given.
+	final := map[string]interface{}{}
+	if entry.ExitCode != nil {
+		final["exitCode"] = *entry.ExitCode
+	}
+	if entry.DurationMs != nil {
+		final["durationMs"] = *entry.DurationMs
+	}
+	finalJSON, err := json.Marshal(final)
+	if err != nil {
+		return err
+	}
+
+	_, ok, err := basestore.ScanFirstInt(s.Query(ctx, s.formatQuery(
+		updateExecutionLogEntryQuery,
+		quote(s.options.TableName),
+		entryID,
+		entryID,
+		entryID,
+		entry.Out,
+		string(finalJSON),
+		id,
+		entryID,
+	)))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrExecutionLogEntryNotUpdated
+	}
+
+	return nil
+}
+
+const updateExecutionLogEntryQuery = `
+-- source: internal/workerutil/store.go:UpdateExecutionLogEntry
+UPDATE %s
+SET {execution_logs}[%s] = (
+	{execution_logs}[%s]::jsonb ||
+	jsonb_build_object('out', COALESCE({execution_logs}[%s]->>'out', '') || %s) ||
+	%s::jsonb
+)::json
+WHERE
+	{id} = %s AND
+	{state} = 'processing' AND
+	%s BETWEEN 1 AND array_length({execution_logs}, 1)
+RETURNING {id}
 `
 
 // MarkComplete attempts to update the state of the record to complete. If this record has already been moved from
@@ -541,6 +717,48 @@ WHERE {id} = %s AND ({state} = 'processing' OR {state} = 'completed')
 RETURNING {id}
 `
 
+// Cancel attempts to update the state of the record to canceled. This method will only have an effect if
+// the record is queued, processing, or errored (and may be retried). The worker processing a canceled record
+// is notified through CanceledIDs. This method returns a boolean flag indicating if the record was updated.
+func (s *store) Cancel(ctx context.Context, id int) (_ bool, err error) {
+	ctx, endObservation := s.operations.cancel.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("id", id),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	_, ok, err := basestore.ScanFirstInt(s.Query(ctx, s.formatQuery(cancelQuery, quote(s.options.TableName), id)))
+	return ok, err
+}
+
+const cancelQuery = `
+-- source: internal/workerutil/store.go:Cancel
+UPDATE %s
+SET {state} = 'canceled', {finished_at} = clock_timestamp()
+WHERE {id} = %s AND {state} IN ('queued', 'processing', 'errored')
+RETURNING {id}
+`
+
+// CanceledIDs returns the subset of the given record identifiers that are in the canceled state.
+func (s *store) CanceledIDs(ctx context.Context, ids []int) (_ []int, err error) {
+	ctx, endObservation := s.operations.canceledIDs.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("numIDs", len(ids)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	return basestore.ScanInts(s.Query(ctx, s.formatQuery(canceledIDsQuery, quote(s.options.TableName), pq.Array(ids))))
+}
+
+const canceledIDsQuery = `
+-- source: internal/workerutil/store.go:CanceledIDs
+SELECT {id} FROM %s
+WHERE {id} = ANY(%s) AND {state} = 'canceled'
+ORDER BY {id}
+`
+
 // ResetStalled moves all processing records that have not received a heartbeat within `StalledMaxAge` back to the
 // queued state. In order to prevent input that continually crashes worker instances, records that have been reset
 // more than `MaxNumResets` times will be marked as errored. This method returns a list of record identifiers that
@@ -213,6 +213,123 @@ func TestStoreDequeueOrder(t *testing.T) {
 	assertDequeueRecordResult(t, 2, record, cancel, ok, err)
 }
 
+func TestStoreDequeueFairness(t *testing.T) {
+	db := setupStoreTest(t)
+
+	// Records are grouped by their tens digit: group 1 had a record processed a minute ago
+	// and has many older queued records, group 2 and 3 have not been processed recently.
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, uploaded_at, started_at, finished_at)
+		VALUES
+			(10, 'completed', NOW() - '10 minute'::interval, NOW() - '1 minute'::interval, NOW() - '30 second'::interval),
+			(11, 'queued', NOW() - '9 minute'::interval, NULL, NULL),
+			(12, 'queued', NOW() - '8 minute'::interval, NULL, NULL),
+			(13, 'queued', NOW() - '7 minute'::interval, NULL, NULL),
+			(20, 'queued', NOW() - '2 minute'::interval, NULL, NULL),
+			(21, 'queued', NOW() - '1 minute'::interval, NULL, NULL),
+			(30, 'queued', NOW() - '3 minute'::interval, NULL, NULL)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	options := defaultTestStoreOptions(nil)
+	options.FairnessKeyExpression = sqlf.Sprintf("w.id / 10")
+	store := testStore(db, options)
+
+	// Each record is completed before the next dequeue, as with a single worker. The idle
+	// groups are served first (oldest record first), then the groups round-robin by the
+	// time their last record was dequeued
+	for _, expectedID := range []int{30, 20, 11, 21, 12, 13} {
+		record, cancel, ok, err := store.Dequeue(context.Background(), "test", nil)
+		assertDequeueRecordResult(t, expectedID, record, cancel, ok, err)
+
+		if _, err := store.MarkComplete(context.Background(), expectedID); err != nil {
+			t.Fatalf("unexpected error marking record as complete: %s", err)
+		}
+	}
+}
+
+func TestStoreDequeueFairnessProcessing(t *testing.T) {
+	db := setupStoreTest(t)
+
+	// Group 1 has a record being processed, group 2 had a record processed more recently
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, uploaded_at, started_at, finished_at)
+		VALUES
+			(10, 'processing', NOW() - '10 minute'::interval, NOW() - '5 minute'::interval, NULL),
+			(11, 'queued', NOW() - '9 minute'::interval, NULL, NULL),
+			(20, 'completed', NOW() - '3 minute'::interval, NOW() - '1 minute'::interval, NOW()),
+			(21, 'queued', NOW() - '2 minute'::interval, NULL, NULL)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	options := defaultTestStoreOptions(nil)
+	options.FairnessKeyExpression = sqlf.Sprintf("w.id / 10")
+	store := testStore(db, options)
+
+	// The group with fewer records being processed comes first
+	for _, expectedID := range []int{21, 11} {
+		record, cancel, ok, err := store.Dequeue(context.Background(), "test", nil)
+		assertDequeueRecordResult(t, expectedID, record, cancel, ok, err)
+	}
+}
+
+func TestStoreDequeueMaxConcurrencyPerKey(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, uploaded_at, started_at)
+		VALUES
+			(10, 'processing', NOW() - '10 minute'::interval, NOW() - '1 minute'::interval),
+			(11, 'queued', NOW() - '9 minute'::interval, NULL),
+			(20, 'queued', NOW() - '2 minute'::interval, NULL),
+			(21, 'queued', NOW() - '1 minute'::interval, NULL)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	options := defaultTestStoreOptions(nil)
+	options.FairnessKeyExpression = sqlf.Sprintf("w.id / 10")
+	options.MaxConcurrencyPerKey = 1
+	store := testStore(db, options)
+
+	record, cancel, ok, err := store.Dequeue(context.Background(), "test", nil)
+	assertDequeueRecordResult(t, 20, record, cancel, ok, err)
+
+	// Both groups are at their limit
+	if _, _, ok, err := store.Dequeue(context.Background(), "test", nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if ok {
+		t.Fatalf("unexpected dequeueable record")
+	}
+}
+
+func TestStoreDequeuePriority(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, uploaded_at)
+		VALUES
+			(10, 'queued', NOW() - '5 minute'::interval),
+			(20, 'queued', NOW() - '1 minute'::interval),
+			(21, 'queued', NOW() - '2 minute'::interval)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	options := defaultTestStoreOptions(nil)
+	options.PriorityExpression = sqlf.Sprintf("w.id >= 20")
+	options.FairnessKeyExpression = sqlf.Sprintf("w.id / 10")
+	store := testStore(db, options)
+
+	// The second group has a higher priority even though it is already being processed
+	for _, expectedID := range []int{21, 20, 10} {
+		record, cancel, ok, err := store.Dequeue(context.Background(), "test", nil)
+		assertDequeueRecordResult(t, expectedID, record, cancel, ok, err)
+	}
+}
+
 func TestStoreDequeueConditions(t *testing.T) {
 	db := setupStoreTest(t)
 
@@ -470,9 +587,13 @@ func TestStoreAddExecutionLogEntry(t *testing.T) {
 			Command: command,
 			Out:     payload,
 		}
-		if err := testStore(db, defaultTestStoreOptions(nil)).AddExecutionLogEntry(context.Background(), 1, entry); err != nil {
+		entryID, err := testStore(db, defaultTestStoreOptions(nil)).AddExecutionLogEntry(context.Background(), 1, entry)
+		if err != nil {
 			t.Fatalf("unexpected error adding executor log entry: %s", err)
 		}
+		if entryID != i+1 {
+			t.Errorf("unexpected entry identifier. want=%d have=%d", i+1, entryID)
+		}
 	}
 
 	contents, err := basestore.ScanStrings(db.QueryContext(context.Background(), `SELECT unnest(execution_logs)::text FROM workerutil_test WHERE id = 1`))
@@ -499,6 +620,68 @@ func TestStoreAddExecutionLogEntry(t *testing.T) {
 	}
 }
 
+func TestStoreUpdateExecutionLogEntry(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state)
+		VALUES
+			(1, 'processing'),
+			(2, 'completed')
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	store := testStore(db, defaultTestStoreOptions(nil))
+
+	entry := workerutil.ExecutionLogEntry{
+		Command: []string{"ls", "-a"},
+		Out:     "stdout: a\n",
+	}
+	entryID, err := store.AddExecutionLogEntry(context.Background(), 1, entry)
+	if err != nil {
+		t.Fatalf("unexpected error adding executor log entry: %s", err)
+	}
+
+	if err := store.UpdateExecutionLogEntry(context.Background(), 1, entryID, workerutil.ExecutionLogEntry{Out: "stdout: b\n"}); err != nil {
+		t.Fatalf("unexpected error updating executor log entry: %s", err)
+	}
+	exitCode, durationMs := 0, 150
+	if err := store.UpdateExecutionLogEntry(context.Background(), 1, entryID, workerutil.ExecutionLogEntry{
+		Out:        "stdout: c\n",
+		ExitCode:   &exitCode,
+		DurationMs: &durationMs,
+	}); err != nil {
+		t.Fatalf("unexpected error updating executor log entry: %s", err)
+	}
+	entry.Out = "stdout: a\nstdout: b\nstdout: c\n"
+	entry.ExitCode = &exitCode
+	entry.DurationMs = &durationMs
+
+	contents, err := basestore.ScanStrings(db.QueryContext(context.Background(), `SELECT unnest(execution_logs)::text FROM workerutil_test WHERE id = 1`))
+	if err != nil {
+		t.Fatalf("unexpected error scanning record: %s", err)
+	}
+	if len(contents) != 1 {
+		t.Fatalf("unexpected number of payloads. want=%d have=%d", 1, len(contents))
+	}
+
+	var have workerutil.ExecutionLogEntry
+	if err := json.Unmarshal([]byte(contents[0]), &have); err != nil {
+		t.Fatalf("unexpected error decoding entry: %s", err)
+	}
+	if diff := cmp.Diff(entry, have); diff != "" {
+		t.Errorf("unexpected entry (-want +got):\n%s", diff)
+	}
+
+	if err := store.UpdateExecutionLogEntry(context.Background(), 1, entryID+1, entry); err != ErrExecutionLogEntryNotUpdated {
+		t.Errorf("unexpected error updating unknown entry. want=%q have=%q", ErrExecutionLogEntryNotUpdated, err)
+	}
+	if err := store.UpdateExecutionLogEntry(context.Background(), 2, 1, entry); err != ErrExecutionLogEntryNotUpdated {
+		t.Errorf("unexpected error updating entry of completed record. want=%q have=%q", ErrExecutionLogEntryNotUpdated, err)
+	}
+}
+
 func TestStoreMarkComplete(t *testing.T) {
 	db := setupStoreTest(t)
 
@@ -802,6 +985,60 @@ func TestStoreMarkErroredRetriesExhausted(t *testing.T) {
 	assertState(2, "failed")
 }
 
+func TestStoreCancel(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state)
+		VALUES
+			(1, 'queued'),
+			(2, 'processing'),
+			(3, 'errored'),
+			(4, 'completed'),
+			(5, 'failed')
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	store := testStore(db, defaultTestStoreOptions(nil))
+
+	for id, expected := range map[int]bool{1: true, 2: true, 3: true, 4: false, 5: false, 6: false} {
+		canceled, err := store.Cancel(context.Background(), id)
+		if err != nil {
+			t.Fatalf("unexpected error canceling record: %s", err)
+		}
+		if canceled != expected {
+			t.Errorf("unexpected canceled flag for record %d. want=%v have=%v", id, expected, canceled)
+		}
+	}
+
+	canceledIDs, err := store.CanceledIDs(context.Background(), []int{1, 2, 4, 6})
+	if err != nil {
+		t.Fatalf("unexpected error fetching canceled records: %s", err)
+	}
+	if diff := cmp.Diff([]int{1, 2}, canceledIDs); diff != "" {
+		t.Errorf("unexpected canceled ids (-want +got):\n%s", diff)
+	}
+
+	// Canceled records must not be processed again
+	if err := store.Requeue(context.Background(), 2, testNow()); err != nil {
+		t.Fatalf("unexpected error requeueing record: %s", err)
+	}
+	if marked, err := store.MarkComplete(context.Background(), 2); err != nil {
+		t.Fatalf("unexpected error marking record as completed: %s", err)
+	} else if marked {
+		t.Errorf("expected canceled record not to be marked")
+	}
+
+	state, _, err := basestore.ScanFirstString(db.QueryContext(context.Background(), `SELECT state FROM workerutil_test WHERE id = 2`))
+	if err != nil {
+		t.Fatalf("unexpected error querying record: %s", err)
+	}
+	if state != "canceled" {
+		t.Errorf("unexpected state. want=%q have=%q", "canceled", state)
+	}
+}
+
 func TestStoreResetStalled(t *testing.T) {
 	db := setupStoreTest(t)
 
@@ -24,8 +24,13 @@ type Store interface {
 	// by another concurrent worker process.
 	Dequeue(ctx context.Context, workerHostname string, extraArguments interface{}) (Record, context.CancelFunc, bool, error)
 
-	// AddExecutionLogEntry adds an executor log entry to the record.
-	AddExecutionLogEntry(ctx context.Context, id int, entry ExecutionLogEntry) error
+	// AddExecutionLogEntry adds an executor log entry to the record and returns the identifier of
+	// the new entry, which can be used to update the entry while its command is still running.
+	AddExecutionLogEntry(ctx context.Context, id int, entry ExecutionLogEntry) (int, error)
+
+	// UpdateExecutionLogEntry appends the output of the given entry to the executor log entry with
+	// the given identifier, and sets its exit code and duration if they are given.
+	UpdateExecutionLogEntry(ctx context.Context, id, entryID int, entry ExecutionLogEntry) error
 
 	// MarkComplete attempts to update the state of the record to complete. This method returns a boolean flag indicating
 	// if the record was updated.
@@ -38,14 +43,19 @@ type Store interface {
 	// MarkFailed attempts to update the state of the record to failed. This method returns a boolean flag indicating
 	// if the record was updated.
 	MarkFailed(ctx context.Context, id int, failureMessage string) (bool, error)
+
+	// CanceledIDs returns the subset of the given record identifiers that have been canceled while being processed.
+	// The handlers of these records are canceled by the worker.
+	CanceledIDs(ctx context.Context, ids []int) ([]int, error)
 }
 
-// ExecutionLogEntry represents a command run by the executor.
+// ExecutionLogEntry represents a command run by the executor. The exit code and duration
+// of an entry are nil while its command is still running.
 type ExecutionLogEntry struct {
 	Key        string    `json:"key"`
 	Command    []string  `json:"command"`
 	StartTime  time.Time `json:"startTime"`
-	ExitCode   int       `json:"exitCode"`
+	ExitCode   *int      `json:"exitCode"`
 	Out        string    `json:"out"`
-	DurationMs int       `json:"durationMs"`
+	DurationMs *int      `json:"durationMs"`
 }
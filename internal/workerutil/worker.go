@@ -2,6 +2,7 @@ package workerutil
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
@@ -25,6 +26,14 @@ type Worker struct {
 	cancel           func()          // cancels the root context
 	wg               sync.WaitGroup  // tracks active handler routines
 	finished         chan struct{}   // signals that Start has finished
+	runningM         sync.Mutex      // protects running
+	running          map[int]*runningRecord
+}
+
+// runningRecord tracks a record being processed by a handler routine.
+type runningRecord struct {
+	cancel   context.CancelFunc // cancels the context passed to the handler
+	canceled bool               // whether the record was canceled
 }
 
 type WorkerOptions struct {
@@ -46,6 +55,11 @@ type WorkerOptions struct {
 	// Interval is the frequency to poll the underlying store for new work.
 	Interval time.Duration
 
+	// CancelInterval is the frequency to poll the underlying store for records
+	// that were canceled while being processed by this worker. If not supplied,
+	// the value of Interval is used.
+	CancelInterval time.Duration
+
 	// Metrics configures logging, tracing, and metrics for the work loop.
 	Metrics WorkerMetrics
 }
@@ -63,6 +77,10 @@ func newWorker(ctx context.Context, store Store, handler Handler, options Worker
 		options.WorkerHostname = hostname.Get()
 	}
 
+	if options.CancelInterval == 0 {
+		options.CancelInterval = options.Interval
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 
 	handlerSemaphore := make(chan struct{}, options.NumHandlers)
@@ -79,6 +97,7 @@ func newWorker(ctx context.Context, store Store, handler Handler, options Worker
 		ctx:              ctx,
 		cancel:           cancel,
 		finished:         make(chan struct{}),
+		running:          map[int]*runningRecord{},
 	}
 }
 
@@ -86,6 +105,9 @@ func newWorker(ctx context.Context, store Store, handler Handler, options Worker
 func (w *Worker) Start() {
 	defer close(w.finished)
 
+	w.wg.Add(1)
+	go w.watchCanceledRecords()
+
 loop:
 	for {
 		ok, err := w.dequeueAndHandle()
@@ -199,7 +221,17 @@ func (w *Worker) handle(record Record) (err error) {
 	ctx, endOperation := w.options.Metrics.operations.handle.With(w.ctx, &err, observation.Args{})
 	defer endOperation(1, observation.Args{})
 
-	handleErr := w.handler.Handle(ctx, record)
+	// Run the handler in its own context so that it can be canceled individually
+	// when the record is canceled, without affecting the updates below.
+	handleCtx, cancel := context.WithCancel(ctx)
+	w.addRunningRecord(record.RecordID(), cancel)
+	handleErr := w.handler.Handle(handleCtx, record)
+
+	if canceled := w.removeRunningRecord(record.RecordID()); canceled {
+		// The record has already been moved into a terminal state
+		log15.Info("Record canceled", "name", w.options.Name, "id", record.RecordID(), "err", handleErr)
+		return nil
+	}
 
 	if errcode.IsNonRetryable(handleErr) {
 		if marked, markErr := w.store.MarkFailed(ctx, record.RecordID(), handleErr.Error()); markErr != nil {
@@ -225,6 +257,87 @@ func (w *Worker) handle(record Record) (err error) {
 	return nil
 }
 
+// watchCanceledRecords periodically cancels the handlers of the records that were canceled
+// while being processed. This method returns once the root context is canceled.
+func (w *Worker) watchCanceledRecords() {
+	defer w.wg.Done()
+
+	for {
+		select {
+		case <-w.clock.After(w.options.CancelInterval):
+		case <-w.ctx.Done():
+			return
+		}
+
+		ids := w.runningRecordIDs()
+		if len(ids) == 0 {
+			continue
+		}
+
+		canceledIDs, err := w.store.CanceledIDs(w.ctx, ids)
+		if err != nil {
+			if w.ctx.Err() == nil {
+				log15.Error("Failed to fetch canceled records", "name", w.options.Name, "err", err)
+			}
+
+			continue
+		}
+
+		for _, id := range canceledIDs {
+			w.cancelRunningRecord(id)
+		}
+	}
+}
+
+// addRunningRecord registers the cancel function of the handler processing the given record.
+func (w *Worker) addRunningRecord(id int, cancel context.CancelFunc) {
+	w.runningM.Lock()
+	defer w.runningM.Unlock()
+
+	w.running[id] = &runningRecord{cancel: cancel}
+}
+
+// removeRunningRecord releases the context of the handler processing the given record and
+// returns true if the record was canceled while being processed.
+func (w *Worker) removeRunningRecord(id int) bool {
+	w.runningM.Lock()
+	defer w.runningM.Unlock()
+
+	record, ok := w.running[id]
+	if !ok {
+		return false
+	}
+	delete(w.running, id)
+
+	record.cancel()
+	return record.canceled
+}
+
+// cancelRunningRecord cancels the context of the handler processing the given record.
+func (w *Worker) cancelRunningRecord(id int) {
+	w.runningM.Lock()
+	defer w.runningM.Unlock()
+
+	if record, ok := w.running[id]; ok {
+		record.canceled = true
+		record.cancel()
+	}
+}
+
+// runningRecordIDs returns the identifiers of the records currently being processed.
+func (w *Worker) runningRecordIDs() []int {
+	w.runningM.Lock()
+	defer w.runningM.Unlock()
+
+	ids := make([]int, 0, len(w.running))
+	for id := range w.running {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	return ids
+}
+
 // preDequeueHook invokes the handler's pre-dequeue hook if it exists.
 func (w *Worker) preDequeueHook() (dequeueable bool, extraDequeueArguments interface{}, err error) {
 	if o, ok := w.handler.(WithPreDequeue); ok {
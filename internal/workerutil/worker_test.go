@@ -152,6 +152,66 @@ func TestWorkerHandlerNonRetryableFailure(t *testing.T) {
 	}
 }
 
+func TestWorkerHandlerCanceled(t *testing.T) {
+	store := NewMockStore()
+	handler := NewMockHandler()
+	clock := glock.NewMockClock()
+	options := WorkerOptions{
+		Name:           "test",
+		WorkerHostname: "test",
+		NumHandlers:    1,
+		Interval:       time.Second,
+		Metrics:        NewMetrics(&observation.TestContext, "", nil),
+	}
+
+	var cancel int
+	store.DequeueFunc.PushReturn(TestRecord{ID: 42}, func() { cancel++ }, true, nil)
+	store.DequeueFunc.SetDefaultReturn(nil, nil, false, nil)
+	store.CanceledIDsFunc.SetDefaultReturn([]int{42}, nil)
+
+	done := make(chan struct{})
+	handler.HandleFunc.SetDefaultHook(func(ctx context.Context, record Record) error {
+		defer close(done)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	worker := newWorker(context.Background(), store, handler, options, clock)
+	go func() { worker.Start() }()
+
+	timeout := time.After(5 * time.Second)
+loop:
+	for {
+		clock.Advance(time.Second)
+
+		select {
+		case <-done:
+			break loop
+		case <-time.After(10 * time.Millisecond):
+		case <-timeout:
+			t.Fatal("handler was not canceled")
+		}
+	}
+	worker.Stop()
+
+	if callCount := len(store.CanceledIDsFunc.History()); callCount == 0 {
+		t.Errorf("expected canceled records to be fetched")
+	} else if ids := store.CanceledIDsFunc.History()[0].Arg1; len(ids) != 1 || ids[0] != 42 {
+		t.Errorf("unexpected ids argument to canceled ids. want=%v have=%v", []int{42}, ids)
+	}
+
+	if callCount := len(store.MarkErroredFunc.History()); callCount != 0 {
+		t.Errorf("unexpected mark errored call count. want=%d have=%d", 0, callCount)
+	}
+	if callCount := len(store.MarkCompleteFunc.History()); callCount != 0 {
+		t.Errorf("unexpected mark complete call count. want=%d have=%d", 0, callCount)
+	}
+
+	if cancel != 1 {
+		t.Errorf("unexpected cancel call count. want=%d have=%d", 1, cancel)
+	}
+}
+
 func TestWorkerConcurrent(t *testing.T) {
 	t.Skip("Disabled because it's flaky. See: https://github.com/sourcegraph/sourcegraph/issues/22595")
 
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE cm_queries DROP COLUMN IF EXISTS kind;
+ALTER TABLE cm_queries DROP COLUMN IF EXISTS latest_fingerprint;
+
+COMMIT;
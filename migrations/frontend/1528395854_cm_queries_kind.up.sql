@@ -0,0 +1,9 @@
+BEGIN;
+
+-- COMMIT triggers run type:diff/type:commit searches with an after: filter.
+-- CONTENT triggers run content or structural searches and fire whenever the
+-- fingerprint of the set of matching file locations changes.
+ALTER TABLE cm_queries ADD COLUMN IF NOT EXISTS kind text NOT NULL DEFAULT 'COMMIT';
+ALTER TABLE cm_queries ADD COLUMN IF NOT EXISTS latest_fingerprint text;
+
+COMMIT;
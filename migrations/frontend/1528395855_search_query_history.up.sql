@@ -0,0 +1,19 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS search_query_history (
+    id bigserial PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    query text NOT NULL,
+    pattern_type text NOT NULL,
+    status text NOT NULL,
+    alert_type text,
+    alert_title text,
+    result_count integer NOT NULL,
+    duration_ms integer NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS search_query_history_user_id_created_at ON search_query_history(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS search_query_history_created_at ON search_query_history(created_at);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE out_of_band_migrations DROP COLUMN IF EXISTS paused;
+ALTER TABLE out_of_band_migrations DROP COLUMN IF EXISTS batch_size;
+ALTER TABLE out_of_band_migrations DROP COLUMN IF EXISTS batch_interval_ms;
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+ALTER TABLE out_of_band_migrations ADD COLUMN IF NOT EXISTS paused boolean NOT NULL DEFAULT false;
+ALTER TABLE out_of_band_migrations ADD COLUMN IF NOT EXISTS batch_size integer;
+ALTER TABLE out_of_band_migrations ADD COLUMN IF NOT EXISTS batch_interval_ms integer;
+
+ALTER TABLE out_of_band_migrations ADD CONSTRAINT out_of_band_migrations_batch_size_positive CHECK (batch_size > 0);
+ALTER TABLE out_of_band_migrations ADD CONSTRAINT out_of_band_migrations_batch_interval_ms_positive CHECK (batch_interval_ms > 0);
+
+COMMENT ON COLUMN out_of_band_migrations.paused IS 'Whether the migration has been paused by a site admin. Paused migrations make no progress in either direction.';
+COMMENT ON COLUMN out_of_band_migrations.batch_size IS 'The number of records processed by each invocation of the migration, overriding the default of the migrator.';
+COMMENT ON COLUMN out_of_band_migrations.batch_interval_ms IS 'The time between two invocations of the migration in milliseconds, overriding the default of the migrator.';
+
+COMMIT;
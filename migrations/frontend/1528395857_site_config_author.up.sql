@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE critical_and_site_config ADD COLUMN IF NOT EXISTS author_user_id integer REFERENCES users(id) ON DELETE SET NULL DEFERRABLE;
+
+COMMENT ON COLUMN critical_and_site_config.author_user_id IS 'The user who saved this revision of the configuration. NULL for revisions written by the system or before authors were recorded.';
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS commit_index_diffs;
+DROP TABLE IF EXISTS commit_index_commits;
+DROP TABLE IF EXISTS commit_index_repos;
+
+COMMIT;
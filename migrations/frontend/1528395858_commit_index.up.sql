@@ -0,0 +1,46 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS commit_index_repos (
+    repo_id integer PRIMARY KEY REFERENCES repo(id) ON DELETE CASCADE,
+    head_ref text NOT NULL,
+    indexed_commit text NOT NULL,
+    oldest_commit_date timestamp with time zone,
+    history_complete boolean NOT NULL DEFAULT false,
+    next_seq bigint NOT NULL DEFAULT 0,
+    indexed_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+COMMENT ON TABLE commit_index_repos IS 'Tracks the state of the commit index for each repository.';
+COMMENT ON COLUMN commit_index_repos.indexed_commit IS 'The commit at the head of head_ref when the repository was last indexed.';
+COMMENT ON COLUMN commit_index_repos.history_complete IS 'Whether every commit reachable from indexed_commit is in the index.';
+COMMENT ON COLUMN commit_index_repos.next_seq IS 'The smallest seq which newly appended commits may use. Larger values come earlier in git log order.';
+
+CREATE TABLE IF NOT EXISTS commit_index_commits (
+    repo_id integer NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    commit_id text NOT NULL,
+    seq bigint NOT NULL,
+    author_name text NOT NULL,
+    author_email text NOT NULL,
+    author_date timestamp with time zone NOT NULL,
+    committer_name text NOT NULL,
+    committer_email text NOT NULL,
+    committer_date timestamp with time zone NOT NULL,
+    message text NOT NULL,
+    parents text[] NOT NULL DEFAULT '{}'::text[],
+    PRIMARY KEY (repo_id, commit_id)
+);
+
+CREATE INDEX IF NOT EXISTS commit_index_commits_repo_seq ON commit_index_commits USING btree (repo_id, seq DESC);
+CREATE INDEX IF NOT EXISTS commit_index_commits_message_trgm ON commit_index_commits USING gin (message gin_trgm_ops);
+
+CREATE TABLE IF NOT EXISTS commit_index_diffs (
+    repo_id integer NOT NULL,
+    commit_id text NOT NULL,
+    diff text NOT NULL,
+    PRIMARY KEY (repo_id, commit_id),
+    FOREIGN KEY (repo_id, commit_id) REFERENCES commit_index_commits(repo_id, commit_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS commit_index_diffs_diff_trgm ON commit_index_diffs USING gin (diff gin_trgm_ops);
+
+COMMIT;
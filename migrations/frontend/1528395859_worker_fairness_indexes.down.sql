@@ -0,0 +1,14 @@
+BEGIN;
+
+DROP INDEX IF EXISTS lsif_uploads_processing_started_at;
+DROP INDEX IF EXISTS lsif_uploads_started_at;
+DROP INDEX IF EXISTS lsif_indexes_processing_started_at;
+DROP INDEX IF EXISTS lsif_indexes_started_at;
+DROP INDEX IF EXISTS changesets_processing_started_at;
+DROP INDEX IF EXISTS changesets_started_at;
+DROP INDEX IF EXISTS changeset_jobs_processing_started_at;
+DROP INDEX IF EXISTS changeset_jobs_started_at;
+DROP INDEX IF EXISTS batch_spec_executions_processing_started_at;
+DROP INDEX IF EXISTS batch_spec_executions_started_at;
+
+COMMIT;
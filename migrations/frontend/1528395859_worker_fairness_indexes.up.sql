@@ -0,0 +1,21 @@
+BEGIN;
+
+-- The dequeue queries of the workers with a fairness key read the records that are processing
+-- or have been started in the last hour.
+
+CREATE INDEX IF NOT EXISTS lsif_uploads_processing_started_at ON lsif_uploads(started_at) WHERE state = 'processing';
+CREATE INDEX IF NOT EXISTS lsif_uploads_started_at ON lsif_uploads(started_at) WHERE started_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS lsif_indexes_processing_started_at ON lsif_indexes(started_at) WHERE state = 'processing';
+CREATE INDEX IF NOT EXISTS lsif_indexes_started_at ON lsif_indexes(started_at) WHERE started_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS changesets_processing_started_at ON changesets(started_at) WHERE reconciler_state = 'processing';
+CREATE INDEX IF NOT EXISTS changesets_started_at ON changesets(started_at) WHERE started_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS changeset_jobs_processing_started_at ON changeset_jobs(started_at) WHERE state = 'processing';
+CREATE INDEX IF NOT EXISTS changeset_jobs_started_at ON changeset_jobs(started_at) WHERE started_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS batch_spec_executions_processing_started_at ON batch_spec_executions(started_at) WHERE state = 'processing';
+CREATE INDEX IF NOT EXISTS batch_spec_executions_started_at ON batch_spec_executions(started_at) WHERE started_at IS NOT NULL;
+
+COMMIT;
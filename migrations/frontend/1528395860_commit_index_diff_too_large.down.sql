@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE commit_index_commits DROP COLUMN IF EXISTS diff_too_large;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE commit_index_commits ADD COLUMN IF NOT EXISTS diff_too_large boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN commit_index_commits.diff_too_large IS 'Whether the diff of the commit was too large to be stored in commit_index_diffs.';
+
+COMMIT;
@@ -137,6 +137,71 @@ func (v *AuthProviders) UnmarshalJSON(data []byte) error {
 	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"builtin", "saml", "openidconnect", "http-header", "github", "gitlab"})
 }
 
+// AzureDevOpsAuthorization description: If non-null, enforces Azure DevOps repository permissions. Sourcegraph users are mapped to Azure DevOps identities through their account in the given authentication provider, which must sign users in with their Azure Active Directory identity.
+type AzureDevOpsAuthorization struct {
+	// IdentityProvider description: The authentication provider whose accounts identify Sourcegraph users to Azure DevOps.
+	IdentityProvider AzureDevOpsIdentityProvider `json:"identityProvider"`
+}
+
+// AzureDevOpsConnection description: Configuration for a connection to Azure DevOps Services or Azure DevOps Server.
+type AzureDevOpsConnection struct {
+	// Authorization description: If non-null, enforces Azure DevOps repository permissions. Sourcegraph users are mapped to Azure DevOps identities through their account in the given authentication provider, which must sign users in with their Azure Active Directory identity.
+	Authorization *AzureDevOpsAuthorization `json:"authorization,omitempty"`
+	// Exclude description: A list of repositories to never mirror from Azure DevOps. Takes precedence over "orgs" and "projects" configuration.
+	//
+	// Supports excluding by name ({"name": "myorg/myproject/myrepo"}), by ID ({"id": "7a0a1b2c-..."}) or by regular expression ({"pattern": "^myorg/myproject/.*"}).
+	Exclude []*ExcludedAzureDevOpsRepo `json:"exclude,omitempty"`
+	// GitURLType description: The type of Git URLs to use for cloning and fetching Git repositories on Azure DevOps.
+	//
+	// If "http", Sourcegraph will access repositories using Git URLs of the form https://dev.azure.com/myorg/myproject/_git/myrepo.
+	//
+	// If "ssh", Sourcegraph will access repositories using Git URLs of the form git@ssh.dev.azure.com:v3/myorg/myproject/myrepo. See the documentation for how to provide SSH private keys and known_hosts: https://docs.sourcegraph.com/admin/repo/auth#repositories-that-need-http-s-or-ssh-authentication.
+	GitURLType string `json:"gitURLType,omitempty"`
+	// Orgs description: An array of organization names whose repositories should be mirrored on Sourcegraph. On Azure DevOps Server, these are the names of project collections.
+	Orgs []string `json:"orgs,omitempty"`
+	// Projects description: An array of "org/project" strings specifying Azure DevOps projects whose repositories should be mirrored on Sourcegraph.
+	Projects []string `json:"projects,omitempty"`
+	// RateLimit description: Rate limit applied when making background API requests to Azure DevOps.
+	RateLimit *AzureDevOpsRateLimit `json:"rateLimit,omitempty"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for an Azure DevOps repository.
+	//
+	//  - "{host}" is replaced with the Azure DevOps URL's host (such as dev.azure.com), "{org}" with the organization or collection, "{project}" with the project name and "{repo}" with the repository name.
+	//
+	// For example, a repositoryPathPattern of "{host}/{org}/{project}/{repo}" would mean that the Azure DevOps repository at https://dev.azure.com/myorg/myproject/_git/myrepo is available on Sourcegraph at https://src.example.com/dev.azure.com/myorg/myproject/myrepo.
+	//
+	// It is important that the Sourcegraph repository name generated with this pattern be unique to this code host. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+	// Token description: A personal access token for Azure DevOps. It is used to list repositories and to authenticate Git operations over HTTP(S).
+	//
+	// The token needs the "Code (Read)" scope to sync repositories. Enforcing permissions additionally requires the "Identity (Read)" and "Security (Manage)" scopes, and creating pull requests with batch changes requires "Code (Read & write)".
+	Token string `json:"token"`
+	// Url description: URL of Azure DevOps. For Azure DevOps Services this is https://dev.azure.com. For Azure DevOps Server this is the URL of the server without a collection, such as https://devops.example.com/tfs.
+	Url string `json:"url"`
+	// Username description: The username used when cloning repositories over HTTP(S). Azure DevOps accepts any non-empty username together with a personal access token.
+	Username string `json:"username,omitempty"`
+	// Webhooks description: An array of webhook configurations. Azure DevOps service hooks send the secret as the password of the basic authentication credentials configured on the subscription.
+	Webhooks []*AzureDevOpsWebhook `json:"webhooks,omitempty"`
+}
+
+// AzureDevOpsIdentityProvider description: The authentication provider whose accounts identify Sourcegraph users to Azure DevOps.
+type AzureDevOpsIdentityProvider struct {
+	// AuthProviderID description: The value of the `configID` field of the targeted authentication provider.
+	AuthProviderID string `json:"authProviderID"`
+	// AuthProviderType description: The `type` field of the targeted authentication provider.
+	AuthProviderType string `json:"authProviderType"`
+}
+
+// AzureDevOpsRateLimit description: Rate limit applied when making background API requests to Azure DevOps.
+type AzureDevOpsRateLimit struct {
+	// Enabled description: true if rate limiting is enabled.
+	Enabled bool `json:"enabled"`
+	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 500, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 500 requests immediately, provided that the complexity cost of each request is 1.
+	RequestsPerHour float64 `json:"requestsPerHour"`
+}
+type AzureDevOpsWebhook struct {
+	// Secret description: The secret used to authenticate incoming webhook requests
+	Secret string `json:"secret"`
+}
 type BatchChangeRolloutWindow struct {
 	// Days description: Day(s) the window applies to. If omitted, this rule applies to all days of the week.
 	Days []string `json:"days,omitempty"`
@@ -389,6 +454,14 @@ type CloudKMSEncryptionKey struct {
 	Type            string `json:"type"`
 }
 
+// CommitIndex description: Experimental persistent index of commit metadata and diffs, used to speed up type:commit and type:diff searches.
+type CommitIndex struct {
+	// Enabled description: Enables the background commit indexer in repo-updater and lets commit and diff searches read from the index when it is up to date.
+	Enabled bool `json:"enabled,omitempty"`
+	// MaxCommitsPerRepo description: The maximum number of commits indexed per repository, counted back from the default branch head. Searches that reach past the indexed history fall back to git.
+	MaxCommitsPerRepo int `json:"maxCommitsPerRepo,omitempty"`
+}
+
 // CustomGitFetchMapping description: Mapping from Git clone URl domain/path to git fetch command. The `domainPath` field contains the Git clone URL domain/path part. The `fetch` field contains the custom git fetch command.
 type CustomGitFetchMapping struct {
 	// DomainPath description: Git clone URL domain/path
@@ -468,6 +541,14 @@ type ExcludedAWSCodeCommitRepo struct {
 	// Name description: The name of an AWS CodeCommit repository ("repo-name") to exclude from mirroring.
 	Name string `json:"name,omitempty"`
 }
+type ExcludedAzureDevOpsRepo struct {
+	// Id description: The ID of an Azure DevOps repository (as returned by the Azure DevOps API) to exclude from mirroring.
+	Id string `json:"id,omitempty"`
+	// Name description: The name of an Azure DevOps repository ("org/project/repo") to exclude from mirroring.
+	Name string `json:"name,omitempty"`
+	// Pattern description: Regular expression which matches against the "org/project/repo" name of an Azure DevOps repository.
+	Pattern string `json:"pattern,omitempty"`
+}
 type ExcludedBitbucketCloudRepo struct {
 	// Name description: The name of a Bitbucket Cloud repo ("myorg/myrepo") to exclude from mirroring.
 	Name string `json:"name,omitempty"`
@@ -484,6 +565,12 @@ type ExcludedBitbucketServerRepo struct {
 	// Pattern description: Regular expression which matches against the name of a Bitbucket Server repo.
 	Pattern string `json:"pattern,omitempty"`
 }
+type ExcludedGerritProject struct {
+	// Name description: The name of a Gerrit project ("platform/build") to exclude from mirroring.
+	Name string `json:"name,omitempty"`
+	// Pattern description: Regular expression which matches against the name of a Gerrit project.
+	Pattern string `json:"pattern,omitempty"`
+}
 type ExcludedGitHubRepo struct {
 	// Archived description: If set to true, archived repositories will be excluded.
 	Archived bool `json:"archived,omitempty"`
@@ -502,6 +589,14 @@ type ExcludedGitLabProject struct {
 	// Name description: The name of a GitLab project ("group/name") to exclude from mirroring.
 	Name string `json:"name,omitempty"`
 }
+type ExcludedGiteaRepo struct {
+	// Id description: The ID of a Gitea repo (as returned by the Gitea API) to exclude from mirroring.
+	Id int `json:"id,omitempty"`
+	// Name description: The name of a Gitea repo ("myorg/myrepo") to exclude from mirroring.
+	Name string `json:"name,omitempty"`
+	// Pattern description: Regular expression which matches against the name of a Gitea repo.
+	Pattern string `json:"pattern,omitempty"`
+}
 type ExcludedGitoliteRepo struct {
 	// Name description: The name of a Gitolite repo ("my-repo") to exclude from mirroring.
 	Name string `json:"name,omitempty"`
@@ -523,6 +618,8 @@ type ExperimentalFeatures struct {
 	AndOrQuery string `json:"andOrQuery,omitempty"`
 	// BitbucketServerFastPerm description: DEPRECATED: Configure in Bitbucket Server config.
 	BitbucketServerFastPerm string `json:"bitbucketServerFastPerm,omitempty"`
+	// CommitIndex description: Experimental persistent index of commit metadata and diffs, used to speed up type:commit and type:diff searches.
+	CommitIndex *CommitIndex `json:"commitIndex,omitempty"`
 	// CustomGitFetch description: JSON array of configuration that maps from Git clone URL domain/path to custom git fetch command.
 	CustomGitFetch []*CustomGitFetchMapping `json:"customGitFetch,omitempty"`
 	// DebugLog description: Turns on debug logging for specific debugging scenarios.
@@ -574,6 +671,40 @@ type ExternalIdentity struct {
 	Type           string `json:"type"`
 }
 
+// GerritConnection description: Configuration for a connection to Gerrit.
+type GerritConnection struct {
+	// Exclude description: A list of projects to never mirror from Gerrit. Takes precedence over "projects" configuration.
+	//
+	// Supports excluding by name ({"name": "platform/build"}) or by regular expression ({"pattern": "^device/.*"}).
+	Exclude []*ExcludedGerritProject `json:"exclude,omitempty"`
+	// Password description: The HTTP password of the Gerrit user, as generated on the user's "HTTP Credentials" settings page. It is used for the REST API and for Git operations over HTTP(S).
+	Password string `json:"password"`
+	// Projects description: An array of project names specifying which Gerrit projects to mirror on Sourcegraph. If empty, all projects visible to the configured user are mirrored.
+	Projects []string `json:"projects,omitempty"`
+	// RateLimit description: Rate limit applied when making background API requests to Gerrit.
+	RateLimit *GerritRateLimit `json:"rateLimit,omitempty"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for a Gerrit project.
+	//
+	//  - "{host}" is replaced with the Gerrit URL's host (such as gerrit.example.com), and "{name}" is replaced with the Gerrit project's name (such as "platform/build").
+	//
+	// For example, if your Gerrit is https://gerrit.example.com and your Sourcegraph is https://src.example.com, then a repositoryPathPattern of "{host}/{name}" would mean that a Gerrit project named platform/build is available on Sourcegraph at https://src.example.com/gerrit.example.com/platform/build.
+	//
+	// It is important that the Sourcegraph repository name generated with this pattern be unique to this code host. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+	// Url description: URL of a Gerrit instance, such as https://gerrit.example.com.
+	Url string `json:"url"`
+	// Username description: The username to use when authenticating to Gerrit. Also set the corresponding "password" field.
+	Username string `json:"username"`
+}
+
+// GerritRateLimit description: Rate limit applied when making background API requests to Gerrit.
+type GerritRateLimit struct {
+	// Enabled description: true if rate limiting is enabled.
+	Enabled bool `json:"enabled"`
+	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 500, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 500 requests immediately, provided that the complexity cost of each request is 1.
+	RequestsPerHour float64 `json:"requestsPerHour"`
+}
+
 // GitCommitAuthor description: The author of the Git commit.
 type GitCommitAuthor struct {
 	// Email description: The Git commit author email.
@@ -777,6 +908,50 @@ type GitLabWebhook struct {
 	Secret string `json:"secret"`
 }
 
+// GiteaConnection description: Configuration for a connection to Gitea.
+type GiteaConnection struct {
+	// Exclude description: A list of repositories to never mirror from Gitea. Takes precedence over "orgs" and "repos" configuration.
+	//
+	// Supports excluding by name ({"name": "myorg/myrepo"}), by ID ({"id": 42}) or by regular expression ({"pattern": "^myorg/.*-fork$"}).
+	Exclude []*ExcludedGiteaRepo `json:"exclude,omitempty"`
+	// ExcludeArchived description: If true, archived repositories are not mirrored.
+	ExcludeArchived bool `json:"excludeArchived,omitempty"`
+	// GitURLType description: The type of Git URLs to use for cloning and fetching Git repositories on this Gitea instance.
+	//
+	// If "http", Sourcegraph will access Gitea repositories using Git URLs of the form https://gitea.example.com/myorg/myrepo.git.
+	//
+	// If "ssh", Sourcegraph will access Gitea repositories using Git URLs of the form git@gitea.example.com:myorg/myrepo.git. See the documentation for how to provide SSH private keys and known_hosts: https://docs.sourcegraph.com/admin/repo/auth#repositories-that-need-http-s-or-ssh-authentication.
+	GitURLType string `json:"gitURLType,omitempty"`
+	// Orgs description: An array of organization names identifying Gitea organizations whose repositories should be mirrored on Sourcegraph.
+	//
+	// If neither "orgs" nor "repos" is set, all repositories the token's user has access to are mirrored.
+	Orgs []string `json:"orgs,omitempty"`
+	// RateLimit description: Rate limit applied when making background API requests to Gitea.
+	RateLimit *GiteaRateLimit `json:"rateLimit,omitempty"`
+	// Repos description: An array of repository "owner/name" strings specifying which Gitea repositories to mirror on Sourcegraph.
+	Repos []string `json:"repos,omitempty"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for a Gitea repository.
+	//
+	//  - "{host}" is replaced with the Gitea URL's host (such as gitea.example.com), and "{nameWithOwner}" is replaced with the Gitea repository's "owner/name" (such as "myorg/myrepo").
+	//
+	// For example, if your Gitea is https://gitea.example.com and your Sourcegraph is https://src.example.com, then a repositoryPathPattern of "{host}/{nameWithOwner}" would mean that a Gitea repository at https://gitea.example.com/alice/my-repo is available on Sourcegraph at https://src.example.com/gitea.example.com/alice/my-repo.
+	//
+	// It is important that the Sourcegraph repository name generated with this pattern be unique to this code host. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+	// Token description: An access token for Gitea. It is used to list repositories and to authenticate Git operations over HTTP(S). The token must have access to all repositories that should be mirrored.
+	Token string `json:"token"`
+	// Url description: URL of a Gitea instance, such as https://gitea.example.com.
+	Url string `json:"url"`
+}
+
+// GiteaRateLimit description: Rate limit applied when making background API requests to Gitea.
+type GiteaRateLimit struct {
+	// Enabled description: true if rate limiting is enabled.
+	Enabled bool `json:"enabled"`
+	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 500, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 500 requests immediately, provided that the complexity cost of each request is 1.
+	RequestsPerHour float64 `json:"requestsPerHour"`
+}
+
 // GitoliteConnection description: Configuration for a connection to Gitolite.
 type GitoliteConnection struct {
 	// Exclude description: A list of repositories to never mirror from this Gitolite instance. Supports excluding by exact name ({"name": "foo"}).
@@ -1046,8 +1221,14 @@ type ObservabilityAlerts struct {
 type ObservabilityTracing struct {
 	// Debug description: Turns on debug logging of opentracing client requests. This can be useful for debugging connectivity issues between the tracing client and the Jaeger agent, the performance overhead of tracing, and other issues related to the use of distributed tracing.
 	Debug bool `json:"debug,omitempty"`
+	// Endpoint description: The OTLP/HTTP endpoint of the OpenTelemetry collector that traces are exported to when `type` is "opentelemetry", such as "http://otel-collector:4318". Traces are sent to the /v1/traces path of the endpoint. If not set, the OTEL_EXPORTER_OTLP_ENDPOINT environment variable is used, or "https://localhost:4318" if that is not set either. Endpoints with the http scheme are sent to without TLS.
+	Endpoint string `json:"endpoint,omitempty"`
 	// Sampling description: Determines the requests for which distributed traces are recorded. "none" (default) turns off tracing entirely. "selective" sends traces whenever `?trace=1` is present in the URL. "all" sends traces on every request. Note that this only affects the behavior of the distributed tracing client. The Jaeger instance must be running for traces to be collected (as described in the Sourcegraph installation instructions). Additional downsampling can be configured in Jaeger, itself (https://www.jaegertracing.io/docs/1.17/sampling)
 	Sampling string `json:"sampling,omitempty"`
+	// Type description: Determines where traces are sent. "jaeger" (default) sends traces to the Jaeger agent configured with the JAEGER_* environment variables. "opentelemetry" exports traces with the OpenTelemetry protocol (OTLP) over HTTP to the collector at `endpoint`, and propagates trace context between services with W3C `traceparent` headers.
+	Type string `json:"type,omitempty"`
+	// UrlTemplate description: The URL of a trace in the tracing UI when `type` is "opentelemetry", used for links to traces. "{{ .TraceID }}" is replaced with the hexadecimal ID of the trace.
+	UrlTemplate string `json:"urlTemplate,omitempty"`
 }
 
 // OnQuery description: A Sourcegraph search query that matches a set of repositories (and branches). Each matched repository branch is added to the list of repositories that the batch change will be run on.
@@ -1181,8 +1362,12 @@ type QuickLink struct {
 
 // Ranking description: Experimental search result ranking options.
 type Ranking struct {
-	// MaxReorderQueueSize description: The maximum number of search results that can be buffered to sort results. -1 is unbounded. The default is 0. Set this to small integers to limit latency increases from slow backends.
-	MaxReorderQueueSize int `json:"maxReorderQueueSize,omitempty"`
+	// MaxReorderQueueSize description: The maximum number of search results that can be buffered to stream results in the order of their repository's priority (stars, recent commit activity and repoScores). -1 is unbounded, and 0 streams results in the order they arrive in. The default is 200. Set this to small integers to limit latency increases from slow backends.
+	MaxReorderQueueSize *int `json:"maxReorderQueueSize,omitempty"`
+	// RecentActivityHalfLifeDays description: The number of days after which the score from recentActivityScore is halved. The score is halved once per elapsed half-life rather than decaying continuously.
+	RecentActivityHalfLifeDays float64 `json:"recentActivityHalfLifeDays,omitempty"`
+	// RecentActivityScore description: The score added to a repository whose default branch was committed to just now. The score decays as the latest commit ages, see recentActivityHalfLifeDays. It is added to the repository's star count and repoScores to rank search results. Set to 0 to ignore commit activity.
+	RecentActivityScore *float64 `json:"recentActivityScore,omitempty"`
 	// RepoScores description: a map of URI directories to numeric scores for specifying search result importance, like {"github.com": 500, "github.com/sourcegraph": 300, "github.com/sourcegraph/sourcegraph": 100}. Would rank "github.com/sourcegraph/sourcegraph" as 500+300+100=900, and "github.com/other/foo" as 500.
 	RepoScores map[string]float64 `json:"repoScores,omitempty"`
 }
@@ -1340,8 +1525,12 @@ type Settings struct {
 	SearchIncludeArchived *bool `json:"search.includeArchived,omitempty"`
 	// SearchIncludeForks description: Whether searches should include searching forked repositories.
 	SearchIncludeForks *bool `json:"search.includeForks,omitempty"`
+	// SearchMacros description: Named search macros that can be referenced in a search query as `$name`. A reference is replaced by the query text of the macro, which may reference other macros. Macros defined in user, organization and global settings are merged.
+	SearchMacros map[string]string `json:"search.macros,omitempty"`
 	// SearchMigrateParser description: REMOVED. Previously, a flag to enable and/or-expressions in queries as an aid transition to new language features in versions <= 3.24.0.
 	SearchMigrateParser *bool `json:"search.migrateParser,omitempty"`
+	// SearchQueryHistory description: Whether searches you run are recorded in your personal search query history. Turning this off stops recording new searches, it does not delete your existing history.
+	SearchQueryHistory *bool `json:"search.queryHistory,omitempty"`
 	// SearchRepositoryGroups description: Named groups of repositories that can be referenced in a search query using the `repogroup:` operator. The list can contain string literals (to include single repositories) and JSON objects with a "regex" field (to include all repositories matching the regular expression). Retrieving repogroups via the GQL interface will currently exclude repositories matched by regex patterns. #14208.
 	SearchRepositoryGroups map[string][]interface{} `json:"search.repositoryGroups,omitempty"`
 	// SearchSavedQueries description: DEPRECATED: Saved search queries